@@ -0,0 +1,116 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+
+	"github.com/sdcio/schema-server/pkg/backup"
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// runBackup runs the backup subcommand with args and returns the exit code.
+func runBackup(args []string) int {
+	fs := pflag.NewFlagSet("backup", pflag.ContinueOnError)
+	cfgFile := fs.StringP("config", "c", "schema-server.yaml", "config file path")
+	output := fs.StringP("output", "o", "", "archive file path")
+	noStore := fs.Bool("no-store", false, "leave the persistent store out, it can only be backed up with the server stopped")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	log.SetLevel(log.WarnLevel)
+	err := func() error {
+		if *output == "" {
+			return errors.New("--output is required")
+		}
+		cfg, err := config.New(*cfgFile)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		m, err := backup.Create(context.Background(), cfg, f, &backup.Options{ConfigFile: *cfgFile, NoStore: *noStore})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(*output)
+			return err
+		}
+		printManifest("archived", m)
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "backup: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runRestore runs the restore subcommand with args and returns the exit code.
+func runRestore(args []string) int {
+	fs := pflag.NewFlagSet("restore", pflag.ContinueOnError)
+	input := fs.StringP("input", "i", "", "archive file path")
+	root := fs.String("root", "", "directory the files are restored under, at their archived paths if not set")
+	force := fs.Bool("force", false, "overwrite the existing files and replace the content of the store")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	log.SetLevel(log.WarnLevel)
+	err := func() error {
+		if *input == "" {
+			return errors.New("--input is required")
+		}
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		m, err := backup.Restore(context.Background(), f, &backup.RestoreOptions{Root: *root, Force: *force})
+		if err != nil {
+			return err
+		}
+		printManifest("restored", m)
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "restore: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printManifest(verb string, m *backup.Manifest) {
+	blobs, size := m.Blobs()
+	store := "without the store"
+	if m.Store != nil {
+		store = "with the store " + m.Store.Path
+	}
+	fmt.Printf("%s %d files, %d distinct (%d bytes), %s\n", verb, len(m.Files), blobs, size, store)
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+
+	"github.com/sdcio/schema-server/pkg/bench"
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// runBench runs the bench subcommand with args and returns the exit code:
+// 1 on error or if a workload regressed from the baseline.
+func runBench(args []string) int {
+	fs := pflag.NewFlagSet("bench", pflag.ContinueOnError)
+	cfgFile := fs.StringP("config", "c", "schema-server.yaml", "config file path, the schema is parsed from its schema-store schemas")
+	ref := fs.String("schema", "", "schema as name@vendor@version, name@vendor or name, optional if the config has a single schema")
+	pathsFile := fs.String("paths", "", "file of xpaths the workloads run on, one per line, the top level data nodes if not set")
+	storeType := fs.String("store", config.StoreTypeMemory, "store type, memory or persistent (in a temporary directory)")
+	iterations := fs.IntP("iterations", "n", 5, "runs of each workload over the paths, after a warmup run")
+	format := fs.String("format", "text", "output format, text or json")
+	baselineFile := fs.String("baseline", "", "JSON report of a previous run to compare with")
+	threshold := fs.Float64("threshold", 0.1, "mean latency increase from the baseline reported as a regression, 0.1 for 10%")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	log.SetLevel(log.WarnLevel)
+	err := func() error {
+		cfg, err := config.New(*cfgFile)
+		if err != nil {
+			return err
+		}
+		o := &bench.Options{Store: *storeType, Iterations: *iterations}
+		o.Schema, err = benchSchema(cfg, *ref)
+		if err != nil {
+			return err
+		}
+		if *pathsFile != "" {
+			o.Paths, err = bench.ReadPaths(*pathsFile)
+			if err != nil {
+				return err
+			}
+		}
+		var baseline *bench.Report
+		if *baselineFile != "" {
+			baseline, err = bench.ReadReport(*baselineFile)
+			if err != nil {
+				return err
+			}
+		}
+		rpt, err := bench.Run(context.Background(), o)
+		if err != nil {
+			return err
+		}
+		switch *format {
+		case "json":
+			err = rpt.WriteJSON(os.Stdout)
+		default:
+			err = rpt.WriteText(os.Stdout, baseline)
+		}
+		if err != nil || baseline == nil {
+			return err
+		}
+		if baseline.Schema != rpt.Schema || baseline.Store != rpt.Store {
+			return fmt.Errorf("baseline of schema %s, %s store: not comparable", baseline.Schema, baseline.Store)
+		}
+		rs := rpt.Compare(baseline, *threshold)
+		for _, r := range rs {
+			fmt.Fprintf(os.Stderr, "regression: %s\n", r)
+		}
+		if len(rs) > 0 {
+			return fmt.Errorf("%d workload(s) regressed by more than %.0f%%", len(rs), *threshold*100)
+		}
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bench: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// benchSchema returns the schema of cfg matching ref.
+func benchSchema(cfg *config.Config, ref string) (*config.SchemaConfig, error) {
+	var found []*config.SchemaConfig
+	for _, sc := range cfg.SchemaStore.Schemas {
+		parts := []string{sc.Name, sc.Vendor, sc.Version}
+		rparts := strings.Split(ref, "@")
+		if ref != "" && (len(rparts) > len(parts) || strings.Join(parts[:len(rparts)], "@") != ref) {
+			continue
+		}
+		found = append(found, sc)
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("schema %q not found in the config", ref)
+	case 1:
+		return found[0], nil
+	}
+	return nil, fmt.Errorf("schema %q matches %d schemas of the config, set --schema name@vendor@version", ref, len(found))
+}
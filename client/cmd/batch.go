@@ -0,0 +1,244 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var batchInput string
+var batchConcurrency int
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "run the requests read from stdin or a file, one per line, and print their results as JSON lines",
+	Long: `run the requests read from stdin or a file, one per line, and print their results as JSON lines.
+The requests are sent over one connection, or spread over --connections, for the schema set by the name, vendor and version flags:
+
+  get <xpath>          GetSchema, a line starting with / is a get
+  elements <xpath>     GetSchemaElements
+  expand <xpath>       ExpandPath, as xpaths
+  to-path <elem,...>   ToPath
+  details              GetSchemaDetails
+  list                 ListSchema
+
+Empty lines and lines starting with # are skipped. The results are printed in the order of the requests.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		in := os.Stdin
+		if batchInput != "" && batchInput != "-" {
+			f, err := os.Open(batchInput)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		if batchConcurrency < 1 {
+			batchConcurrency = 1
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		schemaClient, err := createSchemaClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		b := &batch{
+			client: schemaClient,
+			schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+		}
+		return b.run(ctx, in, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.Flags().StringVarP(&batchInput, "input", "i", "-", "file the requests are read from, - for stdin")
+	batchCmd.Flags().IntVarP(&batchConcurrency, "concurrency", "", 8, "max number of requests in flight")
+}
+
+type batch struct {
+	client sdcpb.SchemaServerClient
+	schema *sdcpb.Schema
+}
+
+type batchResult struct {
+	Line     int             `json:"line"`
+	Command  string          `json:"command"`
+	Input    string          `json:"input,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *batchError     `json:"error,omitempty"`
+}
+
+type batchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// run executes the requests read from r, up to batchConcurrency at a time,
+// and writes their results to w in the order they were read.
+func (b *batch) run(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// results in read order, each one is received once done
+	pending := make(chan chan *batchResult, batchConcurrency)
+	var failed, total int
+	done := make(chan error, 1)
+	go func() {
+		enc := json.NewEncoder(w)
+		var werr error
+		for rc := range pending {
+			res := <-rc
+			if werr != nil {
+				continue
+			}
+			if res.Error != nil {
+				failed++
+			}
+			if werr = enc.Encode(res); werr != nil {
+				// stop reading the requests
+				cancel()
+			}
+		}
+		done <- werr
+	}()
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for ctx.Err() == nil && sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		total++
+		rc := make(chan *batchResult, 1)
+		pending <- rc
+		go func(line int, text string) {
+			rc <- b.exec(ctx, line, text)
+		}(line, text)
+	}
+	close(pending)
+	if err := <-done; err != nil {
+		return err
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d request(s) failed", failed, total)
+	}
+	return nil
+}
+
+func (b *batch) exec(ctx context.Context, line int, text string) *batchResult {
+	cmd, arg, _ := strings.Cut(text, " ")
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(cmd, "/") {
+		cmd, arg = "get", text
+	}
+	res := &batchResult{Line: line, Command: cmd, Input: arg}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var err error
+	res.Response, err = b.call(ctx, cmd, arg)
+	if err != nil {
+		st := status.Convert(err)
+		res.Error = &batchError{Code: st.Code().String(), Message: st.Message()}
+	}
+	return res
+}
+
+// call sends the request of command cmd and returns the JSON encoded response.
+func (b *batch) call(ctx context.Context, cmd, arg string) (json.RawMessage, error) {
+	switch cmd {
+	case "list":
+		return protoJSON(b.client.ListSchema(ctx, &sdcpb.ListSchemaRequest{}))
+	case "details":
+		return protoJSON(b.client.GetSchemaDetails(ctx, &sdcpb.GetSchemaDetailsRequest{Schema: b.schema}))
+	case "to-path":
+		return protoJSON(b.client.ToPath(ctx, &sdcpb.ToPathRequest{
+			PathElement: strings.Split(arg, ","),
+			Schema:      b.schema,
+		}))
+	case "get", "expand", "elements":
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown command %q", cmd)
+	}
+	p, err := utils.ParsePath(arg)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid path %q: %v", arg, err)
+	}
+	switch cmd {
+	case "get":
+		return protoJSON(b.client.GetSchema(ctx, &sdcpb.GetSchemaRequest{Path: p, Schema: b.schema}))
+	case "expand":
+		return protoJSON(b.client.ExpandPath(ctx, &sdcpb.ExpandPathRequest{
+			Path:     p,
+			Schema:   b.schema,
+			DataType: sdcpb.DataType_ALL,
+			Xpath:    true,
+		}))
+	}
+	stream, err := b.client.GetSchemaElements(ctx, &sdcpb.GetSchemaRequest{Path: p, Schema: b.schema})
+	if err != nil {
+		return nil, err
+	}
+	// the streamed responses are gathered in an array
+	elems := make([]json.RawMessage, 0)
+	for {
+		rsp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		b, err := protojson.Marshal(rsp)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, b)
+	}
+	return json.Marshal(elems)
+}
+
+func protoJSON(m proto.Message, err error) (json.RawMessage, error) {
+	if err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(m)
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// configReloadCmd represents the config reload command
+var configReloadCmd = &cobra.Command{
+	Use:          "reload",
+	Short:        "re-read the server config file and apply its schemas list",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ReloadConfig(ctx, &api.ReloadConfigRequest{})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0)
+			for _, r := range []struct {
+				result string
+				scs    []*sdcpb.Schema
+			}{
+				{"added", rsp.Added},
+				{"removed", rsp.Removed},
+				{"reloaded", rsp.Reloaded},
+				{"unchanged", rsp.Unchanged},
+			} {
+				for _, sc := range r.scs {
+					tableData = append(tableData, []string{sc.GetName(), sc.GetVendor(), sc.GetVersion(), r.result, ""})
+				}
+			}
+			for _, f := range rsp.Failed {
+				tableData = append(tableData, []string{f.Schema.GetName(), f.Schema.GetVendor(), f.Schema.GetVersion(), "failed", f.Error})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Vendor", "Version", "Result", "Error"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		if len(rsp.Failed) > 0 {
+			return fmt.Errorf("%d schema(s) failed to load", len(rsp.Failed))
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configReloadCmd)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var anonymizeInput string
+var anonymizePathRules []string
+var anonymizeTypeRules []string
+var anonymizeSeed string
+var anonymizeNoDefaults bool
+
+// documentAnonymizeCmd represents the document anonymize command
+var documentAnonymizeCmd = &cobra.Command{
+	Use:          "anonymize",
+	Short:        "mask and randomize the sensitive leaves of an RFC 7951 JSON config document",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		doc, err := readDocument(anonymizeInput)
+		if err != nil {
+			return err
+		}
+		req := &api.AnonymizeDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Document:       doc,
+			NoDefaultRules: anonymizeNoDefaults,
+			Seed:           anonymizeSeed,
+		}
+		for _, r := range anonymizePathRules {
+			action, re, ok := strings.Cut(r, "=")
+			if !ok {
+				return fmt.Errorf("invalid rule %q, expecting action=path-regexp", r)
+			}
+			req.Rules = append(req.Rules, &api.AnonymizeRule{Action: action, Path: re})
+		}
+		for _, r := range anonymizeTypeRules {
+			action, re, ok := strings.Cut(r, "=")
+			if !ok {
+				return fmt.Errorf("invalid type rule %q, expecting action=type-regexp", r)
+			}
+			req.Rules = append(req.Rules, &api.AnonymizeRule{Action: action, Type: re})
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.AnonymizeDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, p := range rsp.Paths {
+			fmt.Fprintf(os.Stderr, "anonymized: %s\n", p)
+		}
+		if format == "compact" {
+			fmt.Println(string(rsp.Document))
+			return nil
+		}
+		buf := new(bytes.Buffer)
+		err = json.Indent(buf, rsp.Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(buf.String())
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentAnonymizeCmd)
+	documentAnonymizeCmd.Flags().StringVarP(&anonymizeInput, "file", "f", "-", "path to the JSON document, - for stdin")
+	documentAnonymizeCmd.Flags().StringArrayVarP(&anonymizePathRules, "rule", "", nil,
+		"action=path-regexp rule applied to the leaves whose schema path matches, action is one of mask, randomize or keep")
+	documentAnonymizeCmd.Flags().StringArrayVarP(&anonymizeTypeRules, "type-rule", "", nil,
+		"action=type-regexp rule applied to the leaves whose type or typedef name matches, after the path rules")
+	documentAnonymizeCmd.Flags().StringVarP(&anonymizeSeed, "seed", "", "", "seed of the pseudonyms, random if not set")
+	documentAnonymizeCmd.Flags().BoolVarP(&anonymizeNoDefaults, "no-default-rules", "", false, "only apply the given rules")
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var buildSets []string
+var buildFile string
+var buildFormat string
+
+// documentBuildCmd represents the document build command
+var documentBuildCmd = &cobra.Command{
+	Use:          "build",
+	Short:        "build a nested config document from path/value pairs",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		req := &api.BuildDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Format: buildFormat,
+		}
+		if buildFile != "" {
+			b, err := os.ReadFile(buildFile)
+			if err != nil {
+				return err
+			}
+			err = json.Unmarshal(b, &req.Updates)
+			if err != nil {
+				return fmt.Errorf("%s: %w", buildFile, err)
+			}
+		}
+		for _, set := range buildSets {
+			xp, v, err := splitSet(set)
+			if err != nil {
+				return err
+			}
+			p, err := utils.ParsePath(xp)
+			if err != nil {
+				return err
+			}
+			upd := &sdcpb.Update{Path: p}
+			if v != "" {
+				upd.Value = &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: v}}
+			}
+			req.Updates = append(req.Updates, &api.Update{Update: upd})
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.BuildDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		if buildFormat == api.DocumentFormatXML {
+			fmt.Println(rsp.XML)
+			return nil
+		}
+		buf := new(bytes.Buffer)
+		err = json.Indent(buf, rsp.Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(buf.String())
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentBuildCmd)
+	documentBuildCmd.Flags().StringArrayVarP(&buildSets, "set", "", nil, "xpath=value update, the value is optional")
+	documentBuildCmd.Flags().StringVarP(&buildFile, "file", "f", "", "path to a JSON array of updates")
+	documentBuildCmd.Flags().StringVarP(&buildFormat, "output", "o", api.DocumentFormatJSON, "document format, json or xml")
+}
+
+// splitSet splits an xpath=value update on the first '=' outside of the path keys.
+func splitSet(s string) (string, string, error) {
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '=':
+			if depth == 0 {
+				return s[:i], s[i+1:], nil
+			}
+		}
+	}
+	if strings.TrimSpace(s) == "" {
+		return "", "", fmt.Errorf("invalid update %q", s)
+	}
+	return s, "", nil
+}
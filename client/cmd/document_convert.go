@@ -0,0 +1,100 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var convertInput string
+var convertFrom string
+var convertTo string
+
+// documentConvertCmd represents the document convert command
+var documentConvertCmd = &cobra.Command{
+	Use:          "convert",
+	Short:        "convert a config document between the JSON, XML and proto encodings",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		b, err := readInput(convertInput)
+		if err != nil {
+			return err
+		}
+		req := &api.ConvertDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			From: convertFrom,
+			To:   convertTo,
+		}
+		if convertFrom == api.DocumentFormatProto {
+			err = json.Unmarshal(b, &req.Updates)
+			if err != nil {
+				return fmt.Errorf("%s: %w", convertInput, err)
+			}
+		} else {
+			req.Document = b
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ConvertDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		switch convertTo {
+		case api.DocumentFormatXML:
+			fmt.Println(rsp.XML)
+		case api.DocumentFormatProto:
+			if rsp.Updates == nil {
+				rsp.Updates = []*api.Update{}
+			}
+			b, err := json.MarshalIndent(rsp.Updates, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			buf := new(bytes.Buffer)
+			err = json.Indent(buf, rsp.Document, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(buf.String())
+		}
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentConvertCmd)
+	documentConvertCmd.Flags().StringVarP(&convertInput, "file", "f", "-", "path to the document, a JSON array of updates for proto, - for stdin")
+	documentConvertCmd.Flags().StringVarP(&convertFrom, "from", "", api.DocumentFormatJSON, "input format, json, xml or proto")
+	documentConvertCmd.Flags().StringVarP(&convertTo, "to", "", api.DocumentFormatJSON, "output format, json, xml or proto")
+}
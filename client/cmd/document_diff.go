@@ -0,0 +1,119 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var diffFrom string
+var diffTo string
+
+// documentDiffCmd represents the document diff command
+var documentDiffCmd = &cobra.Command{
+	Use:          "diff",
+	Short:        "diff two RFC 7951 JSON config documents",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		req := &api.DiffDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+		}
+		var err error
+		req.From, err = readDocument(diffFrom)
+		if err != nil {
+			return err
+		}
+		req.To, err = readDocument(diffTo)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.DiffDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			if rsp.Changes == nil {
+				rsp.Changes = []*api.DocumentChange{}
+			}
+			b, err := json.MarshalIndent(rsp.Changes, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			for _, c := range rsp.Changes {
+				switch c.Op {
+				case "add":
+					fmt.Printf("+ %s: %s\n", c.XPath, c.New)
+				case "delete":
+					fmt.Printf("- %s: %s\n", c.XPath, c.Old)
+				default:
+					fmt.Printf("~ %s: %s -> %s\n", c.XPath, c.Old, c.New)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentDiffCmd)
+	documentDiffCmd.Flags().StringVarP(&diffFrom, "from", "", "", "path to the original JSON document")
+	documentDiffCmd.Flags().StringVarP(&diffTo, "to", "", "", "path to the modified JSON document")
+}
+
+// readDocument reads a JSON document from file name, stdin if name is "-".
+func readDocument(name string) (json.RawMessage, error) {
+	if name == "" {
+		return nil, nil
+	}
+	b, err := readInput(name)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("%s: invalid JSON document", name)
+	}
+	return b, nil
+}
+
+// readInput reads the file name, stdin if it is -.
+func readInput(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var flattenInput string
+
+// documentFlattenCmd represents the document flatten command
+var documentFlattenCmd = &cobra.Command{
+	Use:          "flatten",
+	Short:        "print the leaves of an RFC 7951 JSON config document as path/value pairs",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		doc, err := readDocument(flattenInput)
+		if err != nil {
+			return err
+		}
+		req := &api.FlattenDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Document: doc,
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.FlattenDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			if rsp.Updates == nil {
+				rsp.Updates = []*api.Update{}
+			}
+			b, err := json.MarshalIndent(rsp.Updates, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			for _, upd := range rsp.Updates {
+				fmt.Printf("%s: %s\n", utils.ToXPath(upd.GetPath(), false), prototext.MarshalOptions{}.Format(upd.GetValue()))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentFlattenCmd)
+	documentFlattenCmd.Flags().StringVarP(&flattenInput, "file", "f", "-", "path to the JSON document, - for stdin")
+}
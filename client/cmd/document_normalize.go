@@ -0,0 +1,78 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var normalizeInput string
+
+// documentNormalizeCmd represents the document normalize command
+var documentNormalizeCmd = &cobra.Command{
+	Use:          "normalize",
+	Short:        "print the canonical form of an RFC 7951 JSON config document",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		doc, err := readDocument(normalizeInput)
+		if err != nil {
+			return err
+		}
+		req := &api.NormalizeDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Document: doc,
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.NormalizeDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		// compact output is byte-wise comparable
+		if format == "compact" {
+			fmt.Println(string(rsp.Document))
+			return nil
+		}
+		buf := new(bytes.Buffer)
+		err = json.Indent(buf, rsp.Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(buf.String())
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentNormalizeCmd)
+	documentNormalizeCmd.Flags().StringVarP(&normalizeInput, "file", "f", "-", "path to the JSON document, - for stdin")
+}
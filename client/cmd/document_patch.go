@@ -0,0 +1,135 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var patchInput string
+var patchFile string
+var patchPath string
+
+// documentPatchCmd represents the document patch command
+var documentPatchCmd = &cobra.Command{
+	Use:          "patch",
+	Short:        "apply a JSON Patch (RFC 6902) to an RFC 7951 JSON config document, checked against a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		doc, err := readDocument(patchInput)
+		if err != nil {
+			return err
+		}
+		patch, err := readDocument(patchFile)
+		if err != nil {
+			return err
+		}
+		if patch == nil {
+			return fmt.Errorf("missing --patch")
+		}
+		req := &api.PatchDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Document: doc,
+			Patch:    patch,
+		}
+		if patchPath != "" {
+			req.Path, err = utils.ParsePath(patchPath)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.PatchDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			if rsp.Valid {
+				buf := new(bytes.Buffer)
+				err = json.Indent(buf, rsp.Document, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(buf.String())
+				return nil
+			}
+			tableData := make([][]string, 0, len(rsp.Errors))
+			for _, e := range rsp.Errors {
+				op := "document"
+				switch {
+				case e.Op != "":
+					op = strconv.Itoa(e.Index) + " " + e.Op + " " + e.Pointer
+				case e.Index >= 0:
+					op = strconv.Itoa(e.Index)
+				}
+				kind := e.Kind
+				if e.Constraint != "" && e.Constraint != e.Kind {
+					kind += " (" + e.Constraint + ")"
+				}
+				tableData = append(tableData, []string{op, e.Path, kind, e.Message})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Operation", "Path", "Kind", "Message"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+			if rsp.Truncated {
+				fmt.Println("more errors were found")
+			}
+		}
+		if !rsp.Valid {
+			return fmt.Errorf("patch not applied: %d error(s)", len(rsp.Errors))
+		}
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentPatchCmd)
+	documentPatchCmd.Flags().StringVarP(&patchInput, "file", "f", "-", "path to the JSON document, - for stdin")
+	documentPatchCmd.Flags().StringVarP(&patchFile, "patch", "", "", "path to the JSON Patch, - for stdin")
+	documentPatchCmd.Flags().StringVarP(&patchPath, "path", "p", "", "xpath of the container or list entry the document is the content of")
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var validateInput string
+var validatePath string
+var validateFormat string
+
+// documentValidateCmd represents the document validate command
+var documentValidateCmd = &cobra.Command{
+	Use:          "validate",
+	Short:        "validate a JSON or XML config document against a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		doc, err := readInput(validateInput)
+		if err != nil {
+			return err
+		}
+		req := &api.ValidateDocumentRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Format:   validateFormat,
+			Document: doc,
+		}
+		if validatePath != "" {
+			req.Path, err = utils.ParsePath(validatePath)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ValidateDocument(ctx, req)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			if rsp.Valid {
+				fmt.Println("valid")
+				return nil
+			}
+			tableData := make([][]string, 0, len(rsp.Errors))
+			for _, e := range rsp.Errors {
+				kind := e.Kind
+				if e.Constraint != "" && e.Constraint != e.Kind {
+					kind += " (" + e.Constraint + ")"
+				}
+				tableData = append(tableData, []string{e.Path, kind, e.Message})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Path", "Kind", "Message"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+			if rsp.Truncated {
+				fmt.Println("more errors were found")
+			}
+		}
+		if !rsp.Valid {
+			return fmt.Errorf("invalid document: %d error(s)", len(rsp.Errors))
+		}
+		return nil
+	},
+}
+
+func init() {
+	documentCmd.AddCommand(documentValidateCmd)
+	documentValidateCmd.Flags().StringVarP(&validateInput, "file", "f", "-", "path to the document, - for stdin")
+	documentValidateCmd.Flags().StringVarP(&validatePath, "path", "p", "", "xpath of the container or list entry the document is the content of")
+	documentValidateCmd.Flags().StringVarP(&validateFormat, "input-format", "", api.DocumentFormatJSON, "document format, json or xml")
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/schema"
+)
+
+// printError writes err to w along with the details the server attached to it
+// and, when one is known, a hint on how to fix the request.
+func printError(w io.Writer, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		fmt.Fprintf(w, "error: %v\n", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			fmt.Fprintf(w, "hint: no answer from %s within %s, check the server --address or raise the --timeout\n", addr, timeout)
+		}
+		return
+	}
+	msg := st.Message()
+	var violations []*errdetails.BadRequest_FieldViolation
+	var hints []string
+	var reqID string
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			violations = append(violations, d.GetFieldViolations()...)
+		case *errdetails.ErrorInfo:
+			if h := reasonHint(d); h != "" {
+				hints = append(hints, h)
+			}
+		case *errdetails.RequestInfo:
+			reqID = d.GetRequestId()
+		}
+	}
+	// the violations are listed in the message too, keep its summary only
+	if len(violations) > 0 {
+		msg, _, _ = strings.Cut(msg, "\n")
+		msg = strings.TrimSuffix(msg, ":")
+	}
+	fmt.Fprintf(w, "error: %s (%s)\n", msg, st.Code())
+	for _, fv := range violations {
+		if fv.GetField() == "" {
+			fmt.Fprintf(w, "  %s\n", fv.GetDescription())
+			continue
+		}
+		fmt.Fprintf(w, "  %s: %s\n", fv.GetField(), fv.GetDescription())
+	}
+	if len(hints) == 0 {
+		if h := codeHint(st); h != "" {
+			hints = append(hints, h)
+		}
+	}
+	for _, h := range hints {
+		fmt.Fprintf(w, "hint: %s\n", h)
+	}
+	if reqID != "" {
+		fmt.Fprintf(w, "request id: %s\n", reqID)
+	}
+}
+
+func reasonHint(ei *errdetails.ErrorInfo) string {
+	if ei.GetDomain() != schema.ErrorDomain {
+		return ""
+	}
+	md := ei.GetMetadata()
+	switch ei.GetReason() {
+	case schema.ReasonAmbiguousPath:
+		return fmt.Sprintf("prefix %q with its module name, one of: %s",
+			md["element"], strings.ReplaceAll(md["candidates"], ",", ", "))
+	case api.ReasonSchemaStaged:
+		return "add --include-staged to be served from a staged schema"
+	case api.ReasonSchemaArchived:
+		return fmt.Sprintf("restore the schema with: schemac schema state set active --name %s --vendor %s --version %s",
+			md["name"], md["vendor"], md["version"])
+	case api.ReasonUploadQuotaExceeded:
+		return fmt.Sprintf("the uploads of %q are limited to %s, delete a schema uploaded before or ask for a larger quota",
+			md["owner"], md["limit"])
+	case api.ReasonSubtreeTooLarge:
+		return fmt.Sprintf("the subtree is limited to %s, narrow the request to a path below %s", md["limit"], md["path"])
+	case api.ReasonResponseTooLarge:
+		return fmt.Sprintf("the response is limited to %s bytes, narrow the request path", md["limit"])
+	case api.ReasonSchemaNotAllowed:
+		return fmt.Sprintf("tenant %q is only served from its own schemas, list them with: schemac schema list", md["tenant"])
+	case api.ReasonTenantNotAllowed:
+		return fmt.Sprintf("%s is reserved to the admin tenants, ask for tenant %q to be made one in the authorization tenants of the server",
+			md["method"], md["tenant"])
+	case api.ReasonSchemaNotOwned:
+		return fmt.Sprintf("send the request to %s, the instance serving the %s schemas", md["address"], md["name"])
+	case api.ReasonClientNotAllowed:
+		return fmt.Sprintf("use a --tls-cert allowed to call %s or ask for %q to be added to the authorization clients of the server",
+			md["method"], md["principal"])
+	case api.ReasonInvalidValue:
+		return fmt.Sprintf("check the value at %s against the type of its leaf with: schemac schema get --path %s", md["path"], md["path"])
+	}
+	return ""
+}
+
+func codeHint(st *status.Status) string {
+	switch st.Code() {
+	case codes.Unavailable:
+		return fmt.Sprintf("is the schema server running at %s?", addr)
+	case codes.DeadlineExceeded:
+		return fmt.Sprintf("the request did not complete within %s, raise the --timeout", timeout)
+	case codes.Unimplemented:
+		return "the server does not support this request, check its version with: schemac info"
+	case codes.Unauthenticated:
+		return "check the credentials the request is sent with (--token, --tls-cert)"
+	case codes.PermissionDenied:
+		return "check the credentials (--token, --tls-cert) and the --tenant the request is sent with"
+	case codes.InvalidArgument:
+		if strings.HasPrefix(st.Message(), "unknown schema") {
+			return "list the available schemas with: schemac schema list"
+		}
+	}
+	return ""
+}
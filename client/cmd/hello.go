@@ -0,0 +1,85 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var helloCapabilities []string
+
+// helloCmd represents the hello command
+var helloCmd = &cobra.Command{
+	Use:          "hello",
+	Short:        "negotiate the API version and capabilities with the server",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := api.Negotiate(ctx, extClient, "schemac", helloCapabilities)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			if rsp.APIVersion == 0 {
+				fmt.Println("the server does not support Hello")
+				return nil
+			}
+			tableData := [][]string{
+				{"API Version", strconv.Itoa(rsp.APIVersion)},
+				{"Min API Version", strconv.Itoa(rsp.MinAPIVersion)},
+			}
+			for _, c := range rsp.Capabilities {
+				tableData = append(tableData, []string{c.Name, strings.Join(c.Values, ", ")})
+			}
+			if len(helloCapabilities) > 0 {
+				tableData = append(tableData, []string{"Negotiated", strings.Join(rsp.Negotiated, ", ")})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(helloCmd)
+	helloCmd.Flags().StringSliceVar(&helloCapabilities, "capability", nil, "capability the client can use, repeated")
+}
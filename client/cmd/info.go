@@ -0,0 +1,81 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:          "info",
+	Short:        "get server build info, features and limits",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetServerInfo(ctx, &api.GetServerInfoRequest{})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := [][]string{
+				{"Version", rsp.Version},
+				{"Commit", rsp.Commit},
+				{"Build Date", rsp.BuildDate},
+				{"Go Version", rsp.GoVersion},
+				{"Features", strings.Join(rsp.Features, ", ")},
+			}
+			if rsp.Limits != nil {
+				tableData = append(tableData,
+					[]string{"Max Recv Msg Size", fmt.Sprintf("%d", rsp.Limits.MaxRecvMsgSize)},
+					[]string{"RPC Timeout", (time.Duration(rsp.Limits.RPCTimeout) * time.Millisecond).String()},
+				)
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
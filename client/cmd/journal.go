@@ -0,0 +1,95 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var journalLast int
+var journalMethod string
+
+// journalCmd represents the journal command
+var journalCmd = &cobra.Command{
+	Use:          "journal",
+	Short:        "dump the last requests recorded in the server request journal",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetRequestJournal(ctx, &api.GetRequestJournalRequest{
+			Last:   journalLast,
+			Method: journalMethod,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Entries))
+			for _, e := range rsp.Entries {
+				var sc string
+				if e.Schema != nil {
+					sc = e.Schema.GetName() + "@" + e.Schema.GetVendor() + "@" + e.Schema.GetVersion()
+				}
+				tableData = append(tableData, []string{
+					e.Time.Format(time.RFC3339Nano),
+					e.Method,
+					e.Peer,
+					sc,
+					strings.Join(e.Paths, "\n"),
+					(time.Duration(e.Latency) * time.Microsecond).String(),
+					e.Code,
+					e.Error,
+					e.RequestID,
+				})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Time", "Method", "Peer", "Schema", "Paths", "Latency", "Code", "Error", "Request ID"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(journalCmd)
+	journalCmd.Flags().IntVarP(&journalLast, "last", "", 0, "number of most recent entries to dump, all if not set")
+	journalCmd.Flags().StringVarP(&journalMethod, "method", "", "", "only dump the requests of this method, e.g: GetSchema")
+}
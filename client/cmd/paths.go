@@ -0,0 +1,299 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// max number of recent paths kept per server
+const maxRecentPaths = 50
+
+// pathsFile holds the recent and bookmarked paths, by server address.
+type pathsFile struct {
+	Servers map[string]*serverPaths `json:"servers,omitempty"`
+}
+
+type serverPaths struct {
+	// most recent first
+	Recent    []string          `json:"recent,omitempty"`
+	Bookmarks map[string]string `json:"bookmarks,omitempty"`
+}
+
+func pathsFileName() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "schemac", "paths.json"), nil
+}
+
+func loadPaths() (*pathsFile, error) {
+	pf := &pathsFile{Servers: make(map[string]*serverPaths)}
+	name, err := pathsFileName()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, pf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if pf.Servers == nil {
+		pf.Servers = make(map[string]*serverPaths)
+	}
+	return pf, nil
+}
+
+func (pf *pathsFile) save() error {
+	name, err := pathsFileName()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(name), 0o700)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(pf, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	err = os.WriteFile(tmp, b, 0o600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// server returns the paths of the server the client talks to.
+func (pf *pathsFile) server() *serverPaths {
+	sp, ok := pf.Servers[addr]
+	if !ok {
+		sp = &serverPaths{}
+		pf.Servers[addr] = sp
+	}
+	if sp.Bookmarks == nil {
+		sp.Bookmarks = make(map[string]string)
+	}
+	return sp
+}
+
+func (sp *serverPaths) add(xp string) {
+	recent := make([]string, 0, len(sp.Recent)+1)
+	recent = append(recent, xp)
+	for _, r := range sp.Recent {
+		if r != xp && len(recent) < maxRecentPaths {
+			recent = append(recent, r)
+		}
+	}
+	sp.Recent = recent
+}
+
+// resolve returns the path ref points to: "@<n>" is the nth most recent path,
+// "@<name>" a bookmark. Other refs are returned as is.
+func (sp *serverPaths) resolve(ref string) (string, error) {
+	name, ok := strings.CutPrefix(ref, "@")
+	if !ok {
+		return ref, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 1 || n > len(sp.Recent) {
+			return "", fmt.Errorf("no recent path %s, %d path(s) recorded for %s", ref, len(sp.Recent), addr)
+		}
+		return sp.Recent[n-1], nil
+	}
+	xp, ok := sp.Bookmarks[name]
+	if !ok {
+		return "", fmt.Errorf("unknown bookmark %q for %s", name, addr)
+	}
+	return xp, nil
+}
+
+// parseUserPath parses the path given to a command, which may refer to
+// a recent or a bookmarked path, and records it as the most recent one.
+// Failing to record it is only a warning.
+func parseUserPath(ref string) (*sdcpb.Path, error) {
+	pf, err := loadPaths()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: path history: %v\n", err)
+		return utils.ParsePath(ref)
+	}
+	sp := pf.server()
+	xp, err := sp.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	p, err := utils.ParsePath(xp)
+	if err != nil {
+		return nil, err
+	}
+	if xp != "" {
+		sp.add(xp)
+		if err := pf.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: path history: %v\n", err)
+		}
+	}
+	return p, nil
+}
+
+// pathCmd represents the path command
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "list the recent paths and manage the bookmarked ones, per server address",
+	Long: `list the recent paths and manage the bookmarked ones, per server address.
+The path flag of the schema commands takes @<n> for the nth most recent path and @<name> for a bookmark.`,
+}
+
+// pathRecentCmd represents the path recent command
+var pathRecentCmd = &cobra.Command{
+	Use:          "recent",
+	Short:        "list the paths recently used with the server, most recent first",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		pf, err := loadPaths()
+		if err != nil {
+			return err
+		}
+		sp := pf.server()
+		rows := make([][]string, 0, len(sp.Recent))
+		for i, xp := range sp.Recent {
+			rows = append(rows, []string{"@" + strconv.Itoa(i+1), xp})
+		}
+		return printPaths(rows, sp.Recent)
+	},
+}
+
+// pathBookmarkCmd represents the path bookmark command
+var pathBookmarkCmd = &cobra.Command{
+	Use:          "bookmark <name> [path]",
+	Short:        "bookmark a path, the most recent one if not set",
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if name == "" || strings.ContainsAny(name, "@ ") {
+			return fmt.Errorf("invalid bookmark name %q", name)
+		}
+		if _, err := strconv.Atoi(name); err == nil {
+			return fmt.Errorf("invalid bookmark name %q: it refers to a recent path", name)
+		}
+		pf, err := loadPaths()
+		if err != nil {
+			return err
+		}
+		sp := pf.server()
+		ref := "@1"
+		if len(args) == 2 {
+			ref = args[1]
+		}
+		xp, err := sp.resolve(ref)
+		if err != nil {
+			return err
+		}
+		if _, err := utils.ParsePath(xp); err != nil {
+			return err
+		}
+		sp.Bookmarks[name] = xp
+		return pf.save()
+	},
+}
+
+// pathUnbookmarkCmd represents the path unbookmark command
+var pathUnbookmarkCmd = &cobra.Command{
+	Use:          "unbookmark <name>",
+	Short:        "remove a bookmark",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pf, err := loadPaths()
+		if err != nil {
+			return err
+		}
+		sp := pf.server()
+		if _, ok := sp.Bookmarks[args[0]]; !ok {
+			return fmt.Errorf("unknown bookmark %q for %s", args[0], addr)
+		}
+		delete(sp.Bookmarks, args[0])
+		return pf.save()
+	},
+}
+
+// pathBookmarksCmd represents the path bookmarks command
+var pathBookmarksCmd = &cobra.Command{
+	Use:          "bookmarks",
+	Short:        "list the bookmarked paths of the server",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		pf, err := loadPaths()
+		if err != nil {
+			return err
+		}
+		sp := pf.server()
+		names := make([]string, 0, len(sp.Bookmarks))
+		for name := range sp.Bookmarks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			rows = append(rows, []string{"@" + name, sp.Bookmarks[name]})
+		}
+		return printPaths(rows, sp.Bookmarks)
+	},
+}
+
+func printPaths(rows [][]string, v interface{}) error {
+	switch format {
+	case "table", "":
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Ref", "Path"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(rows)
+		table.Render()
+	case "json":
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(pathCmd)
+	pathCmd.AddCommand(pathRecentCmd, pathBookmarkCmd, pathUnbookmarkCmd, pathBookmarksCmd)
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// connPool spreads the RPCs over its connections round-robin,
+// each connection carries at most the concurrent streams allowed by the server.
+type connPool struct {
+	conns []*grpc.ClientConn
+	next  atomic.Uint32
+}
+
+func (p *connPool) pick() *grpc.ClientConn {
+	return p.conns[int(p.next.Add(1)-1)%len(p.conns)]
+}
+
+func (p *connPool) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	return p.pick().Invoke(ctx, method, args, reply, opts...)
+}
+
+func (p *connPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.pick().NewStream(ctx, desc, method, opts...)
+}
+
+func (p *connPool) Close() error {
+	var err error
+	for _, cc := range p.conns {
+		if cerr := cc.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
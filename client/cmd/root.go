@@ -15,18 +15,29 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sdcio/schema-server/pkg/api"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use: "schemac",
+	// printed by Execute along with their details
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -34,6 +45,7 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		printError(os.Stderr, err)
 		os.Exit(1)
 	}
 }
@@ -42,8 +54,25 @@ var addr string
 var format string
 var maxRcvMsg int
 var timeout time.Duration
+var requestID string
+var tenant string
+var includeStaged bool
+var canary bool
+var targetProfile string
+var tlsCA string
+var tlsCert string
+var tlsKey string
+var skipVerify bool
+var token string
+var connections int
+var keepaliveTime time.Duration
+var keepaliveTimeout time.Duration
+
+// follows the redirects of a sharded deployment to the instance owning the schema
+var redirector *api.Redirector
 
 func init() {
+	redirector = api.NewRedirector(dialConn)
 	rootCmd.PersistentFlags().StringVarP(&addr, "address", "a", "localhost:55000", "schema server address")
 	rootCmd.PersistentFlags().StringVar(&schemaName, "name", "", "schema name")
 	rootCmd.PersistentFlags().StringVar(&schemaVendor, "vendor", "", "schema vendor")
@@ -51,20 +80,138 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&format, "format", "", "output format")
 	rootCmd.PersistentFlags().IntVar(&maxRcvMsg, "max-rcv-msg", 25165824, "the maximum message size in bytes the client can receive")
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "", 60*time.Second, "gRPC rpc timeout")
+	rootCmd.PersistentFlags().StringVar(&requestID, "request-id", "", "request ID sent as x-request-id metadata, generated by the server if not set")
+	rootCmd.PersistentFlags().StringVar(&tenant, "tenant", "", "tenant sent as x-tenant metadata")
+	rootCmd.PersistentFlags().BoolVar(&includeStaged, "include-staged", false, "be served from staged schemas")
+	rootCmd.PersistentFlags().BoolVar(&canary, "canary", false, "be served from the canary version of the schema, if any")
+	rootCmd.PersistentFlags().StringVar(&targetProfile, "target-profile", "", "be served from the schema compiled for this target profile")
+	rootCmd.PersistentFlags().StringVar(&tlsCA, "tls-ca", "", "CA certificate verifying the server certificate, enables TLS")
+	rootCmd.PersistentFlags().StringVar(&tlsCert, "tls-cert", "", "client certificate, enables TLS")
+	rootCmd.PersistentFlags().StringVar(&tlsKey, "tls-key", "", "client certificate key")
+	rootCmd.PersistentFlags().BoolVar(&skipVerify, "skip-verify", false, "do not verify the server certificate, enables TLS")
+	rootCmd.PersistentFlags().IntVar(&connections, "connections", 1, "number of connections the requests are spread over, round-robin")
+	rootCmd.PersistentFlags().DurationVar(&keepaliveTime, "keepalive", 0, "interval of the keepalive pings sent on idle connections, at least 10s, disabled if 0")
+	rootCmd.PersistentFlags().DurationVar(&keepaliveTimeout, "keepalive-timeout", 20*time.Second, "time waited for a keepalive ping answer before closing the connection")
+	rootCmd.PersistentFlags().StringVar(&token, "token", os.Getenv("SCHEMAC_TOKEN"), "bearer token sent as authorization metadata, defaults to $SCHEMAC_TOKEN")
 }
 
 func createSchemaClient(ctx context.Context, addr string) (sdcpb.SchemaServerClient, error) {
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-	cc, err := grpc.DialContext(ctx, addr,
-		grpc.WithBlock(),
-		grpc.WithTransportCredentials(
-			insecure.NewCredentials(),
-		),
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxRcvMsg)),
-	)
+	cc, err := dial(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
 	return sdcpb.NewSchemaServerClient(cc), nil
 }
+
+func createSchemaExtClient(ctx context.Context, addr string) (api.SchemaServerExtClient, error) {
+	cc, err := dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewSchemaServerExtClient(cc), nil
+}
+
+// dial returns a connection to addr, a pool of --connections if more than one.
+func dial(ctx context.Context, addr string) (grpc.ClientConnInterface, error) {
+	if connections <= 1 {
+		return dialConn(ctx, addr)
+	}
+	p := &connPool{conns: make([]*grpc.ClientConn, 0, connections)}
+	for i := 0; i < connections; i++ {
+		cc, err := dialConn(ctx, addr)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.conns = append(p.conns, cc)
+	}
+	return p, nil
+}
+
+func dialConn(ctx context.Context, addr string) (*grpc.ClientConn, error) {
+	creds, err := transportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxRcvMsg)),
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			var md metadata.MD
+			err := invoker(attributionContext(ctx), method, req, reply, cc, append(opts, grpc.Header(&md))...)
+			if vs := md.Get("x-schema-deprecated"); len(vs) > 0 {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", vs[0])
+			}
+			return err
+		}, redirector.UnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(attributionContext(ctx), desc, cc, method, opts...)
+		}, redirector.StreamClientInterceptor),
+	}
+	if keepaliveTime > 0 {
+		// the server closes the connections pinging more often than its keepalive min-time
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                keepaliveTime,
+			Timeout:             keepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return grpc.DialContext(ctx, addr, opts...)
+}
+
+// transportCredentials returns TLS credentials if any of the TLS flags is set,
+// insecure ones otherwise.
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if tlsCA == "" && tlsCert == "" && tlsKey == "" && !skipVerify {
+		return insecure.NewCredentials(), nil
+	}
+	tlsCfg := &tls.Config{InsecureSkipVerify: skipVerify}
+	if tlsCA != "" {
+		b, err := os.ReadFile(tlsCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificate found in %s", tlsCA)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if (tlsCert == "") != (tlsKey == "") {
+		return nil, errors.New("--tls-cert and --tls-key must be set together")
+	}
+	if tlsCert != "" {
+		cert, err := tls.LoadX509KeyPair(tlsCert, tlsKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// attributionContext adds the request ID and tenant to the outgoing metadata.
+func attributionContext(ctx context.Context) context.Context {
+	if requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", requestID)
+	}
+	if tenant != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant", tenant)
+	}
+	if canary {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-schema-canary", "true")
+	}
+	if targetProfile != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-schema-target-profile", targetProfile)
+	}
+	if includeStaged {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-schema-include-staged", "true")
+	}
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	return ctx
+}
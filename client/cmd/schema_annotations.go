@@ -0,0 +1,87 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var annotationsModule string
+
+// schemaAnnotationsCmd represents the annotations command
+var schemaAnnotationsCmd = &cobra.Command{
+	Use:          "annotations",
+	Short:        "list the metadata annotations (RFC 7952) defined by a module, or by all the modules of the schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ListAnnotations(ctx, &api.ListAnnotationsRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Module: annotationsModule,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Annotations))
+			for _, a := range rsp.Annotations {
+				typ := a.Type.GetTypeName()
+				if typ == "" {
+					typ = a.Type.GetType()
+				}
+				tableData = append(tableData, []string{a.Qualified, typ, a.Units, strings.Join(a.IfFeature, ", "), a.Description})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Annotation", "Type", "Units", "If-Feature", "Description"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaAnnotationsCmd)
+	schemaAnnotationsCmd.Flags().StringVarP(&annotationsModule, "module", "m", "", "module defining the annotations")
+}
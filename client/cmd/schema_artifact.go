@@ -0,0 +1,92 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var artifactType string
+var artifactOutput string
+var artifactConfigOnly bool
+
+// schemaArtifactCmd represents the artifact command
+var schemaArtifactCmd = &cobra.Command{
+	Use:          "artifact",
+	Short:        "generate a JSON Schema, OpenAPI or protobuf artifact of a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		var p *sdcpb.Path
+		if xpath != "" {
+			var err error
+			p, err = parseUserPath(xpath)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GenerateArtifact(ctx, &api.GenerateArtifactRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Format:           artifactType,
+			Path:             p,
+			WithDescriptions: withDesc,
+			ConfigOnly:       artifactConfigOnly,
+		})
+		if err != nil {
+			return err
+		}
+		buf := bytes.NewBuffer(rsp.Content)
+		if rsp.Content == nil {
+			err = json.Indent(buf, rsp.Document, "", "  ")
+			if err != nil {
+				return err
+			}
+			buf.WriteByte('\n')
+		}
+		if artifactOutput != "" {
+			return os.WriteFile(artifactOutput, buf.Bytes(), 0644)
+		}
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaArtifactCmd)
+	schemaArtifactCmd.Flags().StringVarP(&artifactType, "type", "", "json-schema", "artifact type: json-schema, openapi, proto or descriptor-set")
+	schemaArtifactCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath of the node described, the whole schema if not set")
+	_ = schemaArtifactCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+	schemaArtifactCmd.Flags().BoolVarP(&withDesc, "with-desc", "", false, "include the descriptions")
+	schemaArtifactCmd.Flags().BoolVarP(&artifactConfigOnly, "config-only", "", false, "leave the state nodes out")
+	schemaArtifactCmd.Flags().StringVarP(&artifactOutput, "output", "o", "", "file the document is written to, stdout if not set")
+}
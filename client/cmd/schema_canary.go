@@ -0,0 +1,127 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaCanaryCmd represents the canary command
+var schemaCanaryCmd = &cobra.Command{
+	Use:   "canary",
+	Short: "manage the canary versions of the schemas",
+}
+
+// schemaCanarySetCmd represents the canary set command
+var schemaCanarySetCmd = &cobra.Command{
+	Use:          "set <canary version>",
+	Short:        "answer the canary clients requests for the schema from another version",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSchemaCanary(cmd, args[0])
+	},
+}
+
+// schemaCanaryClearCmd represents the canary clear command
+var schemaCanaryClearCmd = &cobra.Command{
+	Use:          "clear",
+	Short:        "remove the canary version of the schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return setSchemaCanary(cmd, "")
+	},
+}
+
+// schemaCanaryListCmd represents the canary list command
+var schemaCanaryListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "list the canary versions of the schemas",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ListSchemaCanaries(ctx, &api.ListSchemaCanariesRequest{})
+		if err != nil {
+			return err
+		}
+		return printSchemaCanaries(rsp.Canaries)
+	},
+}
+
+func setSchemaCanary(cmd *cobra.Command, version string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	extClient, err := createSchemaExtClient(ctx, addr)
+	if err != nil {
+		return err
+	}
+	ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel2()
+	rsp, err := extClient.SetSchemaCanary(ctx, &api.SetSchemaCanaryRequest{
+		Schema: &sdcpb.Schema{
+			Name:    schemaName,
+			Vendor:  schemaVendor,
+			Version: schemaVersion,
+		},
+		Version: version,
+	})
+	if err != nil {
+		return err
+	}
+	return printSchemaCanaries(rsp.Canaries)
+}
+
+func printSchemaCanaries(cs []*api.SchemaCanary) error {
+	switch format {
+	case "table", "":
+		tableData := make([][]string, 0, len(cs))
+		for _, c := range cs {
+			tableData = append(tableData, []string{c.Schema.GetName(), c.Schema.GetVendor(), c.Schema.GetVersion(), c.Version})
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Vendor", "Version", "Canary"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(tableData)
+		table.Render()
+	case "json":
+		b, err := json.MarshalIndent(cs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	}
+	return nil
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaCanaryCmd)
+	schemaCanaryCmd.AddCommand(schemaCanarySetCmd, schemaCanaryClearCmd, schemaCanaryListCmd)
+}
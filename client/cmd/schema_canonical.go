@@ -0,0 +1,72 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var canonicalPaths []string
+
+// schemaCanonicalCmd represents the canonical command
+var schemaCanonicalCmd = &cobra.Command{
+	Use:          "canonical",
+	Short:        "validate path keys and print the paths in canonical form",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		req := &api.CanonicalPathRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Paths: make([]*sdcpb.Path, 0, len(canonicalPaths)),
+		}
+		for _, xp := range canonicalPaths {
+			p, err := utils.ParsePath(xp)
+			if err != nil {
+				return err
+			}
+			req.Paths = append(req.Paths, p)
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.CanonicalPath(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, cp := range rsp.Paths {
+			fmt.Println(cp.XPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaCanonicalCmd)
+	schemaCanonicalCmd.Flags().StringArrayVarP(&canonicalPaths, "path", "p", nil, "xpath(s) to canonicalize")
+}
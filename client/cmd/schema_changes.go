@@ -0,0 +1,83 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var fromFingerprint string
+
+// schemaChangesCmd represents the changes command
+var schemaChangesCmd = &cobra.Command{
+	Use:          "changes",
+	Short:        "list the schema containers and lists changed since a fingerprint",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ExportSchemaChanges(ctx, &api.ExportSchemaChangesRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Fingerprint: fromFingerprint,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			fmt.Fprintf(os.Stderr, "%s -> %s\n", rsp.From, rsp.To)
+			tableData := make([][]string, 0, len(rsp.Changes))
+			for _, c := range rsp.Changes {
+				tableData = append(tableData, []string{c.Op, c.Path})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Op", "Path"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaChangesCmd)
+	schemaChangesCmd.Flags().StringVarP(&fromFingerprint, "fingerprint", "", "", "fingerprint to list the changes from")
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaCompleteCmd represents the complete command
+var schemaCompleteCmd = &cobra.Command{
+	Use:          "complete",
+	Short:        "list the possible next elements of a partial path",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if configOnly && stateOnly {
+			return errors.New("either --config-only or --state-only can be set")
+		}
+		rsp, err := completePath(cmd.Context(), xpath)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Candidates))
+			for _, c := range rsp.Candidates {
+				tableData = append(tableData, []string{c.Name, c.Kind, strings.Join(c.Keys, ", "), c.Type, c.Completion})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Kind", "Keys", "Type", "Completion"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaCompleteCmd)
+	schemaCompleteCmd.Flags().StringVarP(&xpath, "path", "", "", "partial xpath to complete")
+	schemaCompleteCmd.Flags().BoolVarP(&configOnly, "config-only", "", false, "complete the config nodes only")
+	schemaCompleteCmd.Flags().BoolVarP(&stateOnly, "state-only", "", false, "complete the state nodes only")
+}
+
+func completePath(ctx context.Context, partial string) (*api.CompletePathResponse, error) {
+	extClient, err := createSchemaExtClient(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	dt := sdcpb.DataType_ALL
+	if configOnly {
+		dt = sdcpb.DataType_CONFIG
+	}
+	if stateOnly {
+		dt = sdcpb.DataType_STATE
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return extClient.CompletePath(ctx, &api.CompletePathRequest{
+		Schema: &sdcpb.Schema{
+			Name:    schemaName,
+			Vendor:  schemaVendor,
+			Version: schemaVersion,
+		},
+		Path:     partial,
+		DataType: dt,
+	})
+}
+
+// pathFlagCompletion completes the --path flag of the schema commands
+// from the schema selected by the flags set before it.
+func pathFlagCompletion(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	rsp, err := completePath(cmd.Context(), toComplete)
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := make([]string, 0, len(rsp.Candidates))
+	for _, c := range rsp.Candidates {
+		completions = append(completions, c.Completion+"\t"+c.Kind)
+	}
+	return completions, cobra.ShellCompDirectiveNoSpace
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaCompositionCmd represents the composition command
+var schemaCompositionCmd = &cobra.Command{
+	Use:          "composition",
+	Short:        "report the unresolved imports, conflicting augments and duplicate nodes of a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetSchemaComposition(ctx, &api.GetSchemaCompositionRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.UnresolvedImports)+len(rsp.AugmentConflicts)+len(rsp.DuplicateNodes))
+			for _, ui := range rsp.UnresolvedImports {
+				tableData = append(tableData, []string{"unresolved-import", ui.Module, ui.Import, ""})
+			}
+			for _, ac := range rsp.AugmentConflicts {
+				tableData = append(tableData, []string{"augment-conflict", ac.Target, ac.Node, strings.Join(ac.Modules, ", ")})
+			}
+			for _, dn := range rsp.DuplicateNodes {
+				tableData = append(tableData, []string{"duplicate-node", dn.Path, "", strings.Join(dn.Modules, ", ")})
+			}
+			if len(tableData) == 0 {
+				fmt.Println("no composition issues")
+				return nil
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Issue", "Module/Target", "Import/Node", "Modules"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaCompositionCmd)
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// schemaDefaultsCmd represents the defaults command
+var schemaDefaultsCmd = &cobra.Command{
+	Use:          "defaults",
+	Short:        "list the leaves and leaf-lists with a default value below a container or a list",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetDefaults(ctx, &api.GetDefaultsRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path: p,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Defaults))
+			for _, d := range rsp.Defaults {
+				tableData = append(tableData, []string{utils.ToXPath(d.Update.GetPath(), false), d.Type, strings.Join(d.Default, ", "), defaultOrigin(d)})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Path", "Type", "Default", "Origin"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+// defaultOrigin returns the origin of default d followed by the typedef
+// or the deviation module stating it.
+func defaultOrigin(d *api.LeafDefault) string {
+	switch {
+	case d.Typedef != "":
+		return d.Origin + " " + d.Typedef
+	case d.Module != "":
+		return d.Origin + " " + d.Module
+	}
+	return d.Origin
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaDefaultsCmd)
+	schemaDefaultsCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath of the container or list, the schema root if not set")
+	_ = schemaDefaultsCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// schemaDetailsCmd represents the details command
+var schemaDetailsCmd = &cobra.Command{
+	Use:          "details",
+	Short:        "get the sources of a schema, its enabled features, deviation modules and subtrees",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		schemaClient, err := createSchemaClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		var header metadata.MD
+		rsp, err := schemaClient.GetSchemaDetails(ctx, &sdcpb.GetSchemaDetailsRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+		}, grpc.Header(&header))
+		if err != nil {
+			return err
+		}
+		features := header.Get("x-schema-features")
+		deviations := header.Get("x-schema-deviations")
+		subtrees := header.Get("x-schema-subtrees")
+		if format == "json" {
+			b, err := json.MarshalIndent(struct {
+				*sdcpb.GetSchemaDetailsResponse
+				Features   []string `json:"features,omitempty"`
+				Deviations []string `json:"deviations,omitempty"`
+				Subtrees   []string `json:"subtrees,omitempty"`
+			}{rsp, features, deviations, subtrees}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		fmt.Println(prototext.Format(rsp))
+		fmt.Printf("features (%d): %s\n", len(features), strings.Join(features, ", "))
+		fmt.Printf("deviations (%d): %s\n", len(deviations), strings.Join(deviations, ", "))
+		if len(subtrees) > 0 {
+			fmt.Printf("subtrees (%d): %s\n", len(subtrees), strings.Join(subtrees, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaDetailsCmd)
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var diffToName string
+var diffToVendor string
+var diffToVersion string
+var diffBreakingOnly bool
+
+// schemaDiffCmd represents the diff command
+var schemaDiffCmd = &cobra.Command{
+	Use:          "diff",
+	Short:        "list the differences between the schema and another one, usually another version",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		to := &sdcpb.Schema{Name: diffToName, Vendor: diffToVendor, Version: diffToVersion}
+		if to.Name == "" {
+			to.Name = schemaName
+		}
+		if to.Vendor == "" {
+			to.Vendor = schemaVendor
+		}
+		if to.Version == "" {
+			to.Version = schemaVersion
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		stream, err := extClient.DiffSchema(ctx, &api.DiffSchemaRequest{
+			From: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			To: to,
+		})
+		if err != nil {
+			return err
+		}
+		diffs := make([]*api.SchemaDiff, 0)
+		for {
+			rsp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			for _, sd := range rsp.Diffs {
+				if diffBreakingOnly && !sd.Breaking {
+					continue
+				}
+				diffs = append(diffs, sd)
+			}
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(diffs))
+			for _, sd := range diffs {
+				var change string
+				switch {
+				case sd.From != "" && sd.To != "":
+					change = sd.From + " -> " + sd.To
+				default:
+					change = sd.From + sd.To
+				}
+				if sd.Attribute != "" {
+					change = sd.Attribute + ": " + change
+				}
+				var breaking string
+				if sd.Breaking {
+					breaking = "yes"
+				}
+				tableData = append(tableData, []string{sd.Kind, sd.Path, sd.Node, change, breaking})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Kind", "Path", "Node", "Change", "Breaking"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(diffs, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaDiffCmd)
+	schemaDiffCmd.Flags().StringVarP(&diffToName, "to-name", "", "", "name of the schema to diff against, defaults to --name")
+	schemaDiffCmd.Flags().StringVarP(&diffToVendor, "to-vendor", "", "", "vendor of the schema to diff against, defaults to --vendor")
+	schemaDiffCmd.Flags().StringVarP(&diffToVersion, "to-version", "", "", "version of the schema to diff against")
+	schemaDiffCmd.Flags().BoolVarP(&diffBreakingOnly, "breaking", "", false, "list the breaking differences only")
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var encodeValues []string
+
+// schemaEncodeCmd represents the encode command
+var schemaEncodeCmd = &cobra.Command{
+	Use:          "encode",
+	Short:        "encode values found at a path as an RFC 7951 JSON document",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		req := &api.EncodeJSONRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path:   p,
+			Values: encodeValues,
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.EncodeJSON(ctx, req)
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		err = json.Indent(buf, rsp.Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(buf.String())
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaEncodeCmd)
+	schemaEncodeCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath")
+	schemaEncodeCmd.Flags().StringArrayVarP(&encodeValues, "value", "", nil, "raw value(s), repeat for leaf-lists")
+}
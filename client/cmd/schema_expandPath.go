@@ -17,12 +17,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
-	"github.com/sdcio/schema-server/pkg/utils"
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/sdcio/schema-server/pkg/api"
 )
 
 // schemaExpandPathCmd represents the expand-path command
@@ -35,13 +39,7 @@ var schemaExpandPathCmd = &cobra.Command{
 		if configOnly && stateOnly {
 			return errors.New("either --config-only or --state-only can be set")
 		}
-		p, err := utils.ParsePath(xpath)
-		if err != nil {
-			return err
-		}
-		ctx, cancel := context.WithCancel(cmd.Context())
-		defer cancel()
-		schemaClient, err := createSchemaClient(ctx, addr)
+		p, err := parseUserPath(xpath)
 		if err != nil {
 			return err
 		}
@@ -62,16 +60,33 @@ var schemaExpandPathCmd = &cobra.Command{
 			},
 			DataType: dt,
 		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		if expandPageSize > 0 || expandPageToken != "" || hasWildcardElems(p) {
+			if expandStream {
+				return errors.New("--stream does not apply to wildcard paths or pages")
+			}
+			return handleExpandWildcardPath(ctx, req)
+		}
 		fmt.Println("request:")
 		fmt.Println(prototext.Format(req))
+		if expandStream {
+			return handleExpandPathStream(ctx, req)
+		}
+		schemaClient, err := createSchemaClient(ctx, addr)
+		if err != nil {
+			return err
+		}
 		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
 		defer cancel2()
-		rsp, err := schemaClient.ExpandPath(ctx, req)
+		var header metadata.MD
+		rsp, err := schemaClient.ExpandPath(ctx, req, grpc.Header(&header))
 		if err != nil {
 			return err
 		}
 		fmt.Println("response:")
 		fmt.Println(prototext.Format(rsp))
+		printPlaceholders(header)
 		fmt.Fprintf(os.Stderr, "path count: %d | %d\n", len(rsp.GetPath()), len(rsp.GetXpath()))
 		return nil
 	},
@@ -80,11 +95,113 @@ var schemaExpandPathCmd = &cobra.Command{
 func init() {
 	schemaCmd.AddCommand(schemaExpandPathCmd)
 	schemaExpandPathCmd.Flags().StringVarP(&xpath, "path", "", "", "xpath to expand")
+	_ = schemaExpandPathCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
 	schemaExpandPathCmd.Flags().BoolVarP(&asXpath, "xpath", "", false, "return paths in xpath format")
 	schemaExpandPathCmd.Flags().BoolVarP(&configOnly, "config-only", "", false, "return paths from the config tree only")
 	schemaExpandPathCmd.Flags().BoolVarP(&stateOnly, "state-only", "", false, "return paths from the config tree only")
+	schemaExpandPathCmd.Flags().BoolVarP(&expandStream, "stream", "", false, "stream the paths in batches, for large subtrees")
+	schemaExpandPathCmd.Flags().IntVarP(&expandPageSize, "page-size", "", 0, "max number of paths per page, the server default if zero")
+	schemaExpandPathCmd.Flags().StringVarP(&expandPageToken, "page-token", "", "", "page token returned by a previous request")
+	schemaExpandPathCmd.Flags().BoolVarP(&expandAllPages, "all-pages", "", false, "request the following pages until the last one")
+}
+
+// hasWildcardElems reports whether a path element name of p is a wildcard,
+// list key wildcards are handled by the ExpandPath RPC.
+func hasWildcardElems(p *sdcpb.Path) bool {
+	for _, pe := range p.GetElem() {
+		if pe.GetName() == "*" || pe.GetName() == "..." {
+			return true
+		}
+	}
+	return false
+}
+
+// printPlaceholders prints the types of the placeholders of the request path,
+// name=type, as returned in the response header.
+func printPlaceholders(header metadata.MD) {
+	for _, ph := range header.Get("x-schema-placeholder") {
+		fmt.Fprintf(os.Stderr, "placeholder: %s\n", ph)
+	}
+}
+
+func handleExpandWildcardPath(ctx context.Context, sreq *sdcpb.ExpandPathRequest) error {
+	extClient, err := createSchemaExtClient(ctx, addr)
+	if err != nil {
+		return err
+	}
+	req := &api.ExpandWildcardPathRequest{
+		Schema:    sreq.GetSchema(),
+		Path:      sreq.GetPath(),
+		Xpath:     sreq.GetXpath(),
+		DataType:  sreq.GetDataType(),
+		PageSize:  expandPageSize,
+		PageToken: expandPageToken,
+	}
+	var count int
+	for {
+		rctx, cancel := context.WithTimeout(ctx, timeout)
+		rsp, err := extClient.ExpandWildcardPath(rctx, req)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, p := range rsp.Path {
+			fmt.Println(prototext.Format(p))
+		}
+		for _, xp := range rsp.Xpath {
+			fmt.Println(xp)
+		}
+		count += len(rsp.Path) + len(rsp.Xpath)
+		if rsp.NextPageToken == "" {
+			break
+		}
+		if !expandAllPages {
+			fmt.Fprintf(os.Stderr, "next page token: %s\n", rsp.NextPageToken)
+			break
+		}
+		req.PageToken = rsp.NextPageToken
+	}
+	fmt.Fprintf(os.Stderr, "path count: %d\n", count)
+	return nil
+}
+
+func handleExpandPathStream(ctx context.Context, req *sdcpb.ExpandPathRequest) error {
+	extClient, err := createSchemaExtClient(ctx, addr)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	stream, err := extClient.ExpandPathStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Println("response:")
+	var count int
+	for {
+		rsp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		for _, p := range rsp.GetPath() {
+			fmt.Println(prototext.Format(p))
+		}
+		for _, xp := range rsp.GetXpath() {
+			fmt.Println(xp)
+		}
+		count += len(rsp.GetPath()) + len(rsp.GetXpath())
+	}
+	fmt.Fprintf(os.Stderr, "path count: %d\n", count)
+	return nil
 }
 
 var asXpath bool
 var configOnly bool
 var stateOnly bool
+var expandStream bool
+var expandPageSize int
+var expandPageToken string
+var expandAllPages bool
@@ -0,0 +1,124 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var exportOutput string
+var exportCheck string
+
+// schemaExportCmd represents the export command
+var schemaExportCmd = &cobra.Command{
+	Use:          "export",
+	Short:        "write a canonical text representation of a schema, for golden files",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if exportOutput != "" && exportCheck != "" {
+			return errors.New("either --output or --check can be set")
+		}
+		var p *sdcpb.Path
+		if xpath != "" {
+			var err error
+			p, err = parseUserPath(xpath)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		stream, err := extClient.ExportSchema(ctx, &api.ExportSchemaRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path:             p,
+			WithDescriptions: withDesc,
+		})
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		for {
+			rsp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			buf.WriteString(rsp.Text)
+		}
+		switch {
+		case exportCheck != "":
+			golden, err := os.ReadFile(exportCheck)
+			if err != nil {
+				return err
+			}
+			return compareExport(exportCheck, golden, buf.Bytes())
+		case exportOutput != "":
+			return os.WriteFile(exportOutput, buf.Bytes(), 0644)
+		}
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+// compareExport returns an error pointing at the first line
+// of the golden file that differs from the export.
+func compareExport(name string, golden, export []byte) error {
+	if bytes.Equal(golden, export) {
+		return nil
+	}
+	gls := strings.Split(string(golden), "\n")
+	els := strings.Split(string(export), "\n")
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(gls):
+			return fmt.Errorf("%s:%d: schema has more lines: %s", name, i+1, els[i])
+		case i >= len(els):
+			return fmt.Errorf("%s:%d: schema has fewer lines: %s", name, i+1, gls[i])
+		case gls[i] != els[i]:
+			return fmt.Errorf("%s:%d: golden %q, schema %q", name, i+1, gls[i], els[i])
+		}
+	}
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaExportCmd)
+	schemaExportCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath of the exported subtree, the whole schema if not set")
+	_ = schemaExportCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+	schemaExportCmd.Flags().BoolVarP(&withDesc, "with-desc", "", false, "export the descriptions")
+	schemaExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file the export is written to, stdout if not set")
+	schemaExportCmd.Flags().StringVarP(&exportCheck, "check", "", "", "golden file the export is compared to, fails on the first different line")
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var expression string
+
+// schemaValidateExpressionCmd represents the validate-expression command
+var schemaValidateExpressionCmd = &cobra.Command{
+	Use:          "validate-expression",
+	Short:        "check an XPath must or when expression against a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ValidateExpression(ctx, &api.ValidateExpressionRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path:       p,
+			Expression: expression,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			if len(rsp.Issues) > 0 {
+				tableData := make([][]string, 0, len(rsp.Issues))
+				for _, is := range rsp.Issues {
+					tableData = append(tableData, []string{strconv.Itoa(is.Offset), is.Kind, is.Message})
+				}
+				table := tablewriter.NewWriter(os.Stdout)
+				table.SetHeader([]string{"Offset", "Issue", "Message"})
+				table.SetAlignment(tablewriter.ALIGN_LEFT)
+				table.SetAutoWrapText(false)
+				table.AppendBulk(tableData)
+				table.Render()
+			}
+			if len(rsp.Paths) > 0 {
+				fmt.Printf("paths:\n  %s\n", strings.Join(rsp.Paths, "\n  "))
+			}
+			if len(rsp.Functions) > 0 {
+				fmt.Printf("functions: %s\n", strings.Join(rsp.Functions, ", "))
+			}
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		if !rsp.Valid {
+			return fmt.Errorf("expression has %d issue(s)", len(rsp.Issues))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaValidateExpressionCmd)
+	schemaValidateExpressionCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath of the context node, the root if not set")
+	_ = schemaValidateExpressionCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+	schemaValidateExpressionCmd.Flags().StringVarP(&expression, "expression", "e", "", "must or when expression")
+}
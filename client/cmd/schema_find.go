@@ -0,0 +1,114 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var findRegex bool
+var findIgnoreCase bool
+var findFields []string
+var findKinds []string
+var findLimit int
+
+// schemaFindCmd represents the find command
+var schemaFindCmd = &cobra.Command{
+	Use:   "find <pattern>",
+	Short: "search the schema nodes names, descriptions and types",
+	Long: `search the schema nodes names, descriptions and types, e.g.:
+  find mtu --kind leaf
+  find '^(ipv4|ipv6)-' --regex --in name --path /network-instance`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var p *sdcpb.Path
+		if xpath != "" {
+			var err error
+			p, err = parseUserPath(xpath)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.FindSchema(ctx, &api.FindSchemaRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Pattern:    args[0],
+			Regex:      findRegex,
+			IgnoreCase: findIgnoreCase,
+			Fields:     findFields,
+			Kinds:      findKinds,
+			Path:       p,
+			Limit:      findLimit,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Matches))
+			for _, m := range rsp.Matches {
+				tableData = append(tableData, []string{m.Path, m.Kind, m.Type, m.Module, strings.Join(m.Fields, ",")})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Path", "Kind", "Type", "Module", "Matched"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+			if rsp.Truncated {
+				fmt.Fprintln(os.Stderr, "more nodes match, the results are truncated to the limit")
+			}
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaFindCmd)
+	schemaFindCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath of the searched subtree, the whole schema if not set")
+	_ = schemaFindCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+	schemaFindCmd.Flags().BoolVarP(&findRegex, "regex", "", false, "the pattern is a regular expression")
+	schemaFindCmd.Flags().BoolVarP(&findIgnoreCase, "ignore-case", "i", false, "ignore case")
+	schemaFindCmd.Flags().StringSliceVarP(&findFields, "in", "", nil, "fields searched: name, description, type. all of them if not set")
+	schemaFindCmd.Flags().StringSliceVarP(&findKinds, "kind", "", nil, "kinds of the nodes returned: container, list, leaf, leaf-list. all of them if not set")
+	schemaFindCmd.Flags().IntVarP(&findLimit, "limit", "", 0, "max number of nodes returned, no limit if zero")
+}
@@ -16,18 +16,30 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
-	"github.com/sdcio/schema-server/pkg/utils"
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/sdcio/schema-server/pkg/api"
 )
 
 var xpath string
 var withDesc bool
 var all bool
+var versionFallback bool
+var elemFields []string
+var elemPageSize int
+var elemPageToken string
+var elemAllPages bool
+var subtree bool
+var subtreeMaxDepth int
 
 // schemaGetCmd represents the get command
 var schemaGetCmd = &cobra.Command{
@@ -36,7 +48,7 @@ var schemaGetCmd = &cobra.Command{
 	// SilenceErrors: true,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		p, err := utils.ParsePath(xpath)
+		p, err := parseUserPath(xpath)
 		if err != nil {
 			return err
 		}
@@ -59,13 +71,33 @@ var schemaGetCmd = &cobra.Command{
 		fmt.Println(prototext.Format(req))
 		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
 		defer cancel2()
+		if subtree {
+			// no deadline, a large subtree takes a while to stream
+			return handleGetSchemaSubtree(cmd.Context(), req)
+		}
 		if all {
+			if len(elemFields) > 0 || elemPageSize > 0 || elemPageToken != "" {
+				return handleListSchemaElems(cmd.Context(), req)
+			}
 			return handleGetSchemaElems(ctx, schemaClient, req)
 		}
-		rsp, err := schemaClient.GetSchema(ctx, req)
+		if versionFallback {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-schema-version-fallback", "nearest")
+		}
+		var header metadata.MD
+		rsp, err := schemaClient.GetSchema(ctx, req, grpc.Header(&header))
 		if err != nil {
 			return err
 		}
+		if vs := header.Get("x-schema-served-version"); len(vs) > 0 {
+			fmt.Fprintf(os.Stderr, "warning: version %s is not loaded, answered from version %s\n", schemaVersion, vs[0])
+		}
+		for _, w := range header.Get("x-schema-when") {
+			fmt.Fprintf(os.Stderr, "when: %s\n", w)
+		}
+		for _, w := range header.Get("x-schema-parent-when") {
+			fmt.Fprintf(os.Stderr, "parent when: %s\n", w)
+		}
 		fmt.Fprintln(os.Stderr, "response:")
 		if format == "json" {
 			b, err := json.MarshalIndent(rsp, "", "  ")
@@ -84,8 +116,16 @@ func init() {
 	schemaCmd.AddCommand(schemaGetCmd)
 
 	schemaGetCmd.PersistentFlags().StringVarP(&xpath, "path", "p", "", "xpath")
+	_ = schemaGetCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
 	schemaGetCmd.PersistentFlags().BoolVarP(&all, "all", "", false, "return all path elems schemas")
 	schemaGetCmd.PersistentFlags().BoolVarP(&withDesc, "with-desc", "", false, "include YANG entries descriptions")
+	schemaGetCmd.PersistentFlags().StringSliceVarP(&elemFields, "fields", "", nil, "with --all, schema fields returned, e.g. name,children,fields.name,leaflists.name")
+	schemaGetCmd.PersistentFlags().IntVarP(&elemPageSize, "page-size", "", 0, "with --all, max number of elements per page, the server default if zero")
+	schemaGetCmd.PersistentFlags().StringVarP(&elemPageToken, "page-token", "", "", "with --all, page token returned by a previous request")
+	schemaGetCmd.PersistentFlags().BoolVarP(&elemAllPages, "all-pages", "", false, "with --all, request the following pages until the last one")
+	schemaGetCmd.PersistentFlags().BoolVarP(&subtree, "subtree", "", false, "stream the schema elements of the whole subtree found at path")
+	schemaGetCmd.PersistentFlags().IntVarP(&subtreeMaxDepth, "max-depth", "", 0, "with --subtree, levels walked below path, unlimited if zero")
+	schemaGetCmd.PersistentFlags().BoolVarP(&versionFallback, "version-fallback", "", false, "answer from the nearest loaded version if the requested one is not loaded")
 }
 
 func handleGetSchemaElems(ctx context.Context, scc sdcpb.SchemaServerClient, req *sdcpb.GetSchemaRequest) error {
@@ -105,3 +145,75 @@ func handleGetSchemaElems(ctx context.Context, scc sdcpb.SchemaServerClient, req
 		fmt.Println(prototext.Format(rsp))
 	}
 }
+
+func handleListSchemaElems(ctx context.Context, sreq *sdcpb.GetSchemaRequest) error {
+	extClient, err := createSchemaExtClient(ctx, addr)
+	if err != nil {
+		return err
+	}
+	req := &api.ListSchemaElementsRequest{
+		Schema:          sreq.GetSchema(),
+		Path:            sreq.GetPath(),
+		WithDescription: sreq.GetWithDescription(),
+		FieldMask:       elemFields,
+		PageSize:        elemPageSize,
+		PageToken:       elemPageToken,
+	}
+	for {
+		rctx, cancel := context.WithTimeout(ctx, timeout)
+		rsp, err := extClient.ListSchemaElements(rctx, req)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, se := range rsp.Elements {
+			fmt.Println("response:")
+			fmt.Println(prototext.Format(se.SchemaElem))
+		}
+		if rsp.NextPageToken == "" {
+			return nil
+		}
+		if !elemAllPages {
+			fmt.Fprintf(os.Stderr, "next page token: %s\n", rsp.NextPageToken)
+			return nil
+		}
+		req.PageToken = rsp.NextPageToken
+	}
+}
+
+func handleGetSchemaSubtree(ctx context.Context, sreq *sdcpb.GetSchemaRequest) error {
+	extClient, err := createSchemaExtClient(ctx, addr)
+	if err != nil {
+		return err
+	}
+	stream, err := extClient.GetSchemaSubtree(ctx, &api.GetSchemaSubtreeRequest{
+		Schema:          sreq.GetSchema(),
+		Path:            sreq.GetPath(),
+		WithDescription: sreq.GetWithDescription(),
+		MaxDepth:        subtreeMaxDepth,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		rsp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, e := range rsp.Elements {
+			fmt.Printf("%s:\n", e.Path)
+			if format == "json" {
+				b, err := json.MarshalIndent(e.Schema, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+				continue
+			}
+			fmt.Println(prototext.Format(e.Schema.SchemaElem))
+		}
+	}
+}
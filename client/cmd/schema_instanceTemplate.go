@@ -0,0 +1,74 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaInstanceTemplateCmd represents the instance-template command
+var schemaInstanceTemplateCmd = &cobra.Command{
+	Use:          "instance-template",
+	Short:        "print the instance path template of a list path with the type of each key, e.g: /interface/subinterface",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		req := &api.GetInstanceTemplateRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path: p,
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetInstanceTemplate(ctx, req)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		fmt.Println(rsp.Template)
+		fmt.Println(rsp.PathTemplate)
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaInstanceTemplateCmd)
+	schemaInstanceTemplateCmd.Flags().StringVarP(&xpath, "path", "p", "", "list path")
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var invalidationsFingerprint string
+
+// schemaInvalidationsCmd represents the invalidations command
+var schemaInvalidationsCmd = &cobra.Command{
+	Use:          "invalidations",
+	Short:        "print the nodes of a schema changed since a fingerprint, then at each reload",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		stream, err := extClient.WatchInvalidations(ctx, &api.WatchInvalidationsRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Fingerprint: invalidationsFingerprint,
+		})
+		if err != nil {
+			return err
+		}
+		for {
+			inv, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			switch format {
+			case "table", "":
+				scope := fmt.Sprintf("%d node(s)", len(inv.Stale))
+				if inv.Full {
+					scope = "full"
+				}
+				fmt.Printf("%s\t%s -> %s\t%s\n", inv.Time.Format(time.RFC3339), inv.From, inv.To, scope)
+				for _, sn := range inv.Stale {
+					kind := "node"
+					if sn.Subtree {
+						kind = "subtree"
+					}
+					fmt.Printf("  %-7s\t%s\t%s\n", kind, sn.Path, sn.Checksum)
+				}
+			case "json":
+				b, err := json.Marshal(inv)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+			}
+		}
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaInvalidationsCmd)
+	schemaInvalidationsCmd.Flags().StringVarP(&invalidationsFingerprint, "fingerprint", "", "", "fingerprint of the cached content, as printed by schema fingerprint")
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaKeysCmd represents the keys command
+var schemaKeysCmd = &cobra.Command{
+	Use:          "keys",
+	Short:        "show the constraints of the wildcarded keys of a path",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetKeyConstraints(ctx, &api.GetKeyConstraintsRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path: p,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Keys))
+			for _, k := range rsp.Keys {
+				members := make([]string, 0, len(k.Members))
+				for _, m := range k.Members {
+					members = append(members, typeConstraintsString(m))
+				}
+				tableData = append(tableData, []string{k.List, k.Key, strings.Join(members, "\n")})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"List", "Key", "Constraints"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.SetRowLine(true)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+// typeConstraintsString formats a key type, e.g: interface-name(string) length=3..20 pattern='...'
+func typeConstraintsString(c *api.TypeConstraints) string {
+	sb := new(strings.Builder)
+	if c.TypeName != "" && c.TypeName != c.Type {
+		fmt.Fprintf(sb, "%s(%s)", c.TypeName, c.Type)
+	} else {
+		sb.WriteString(c.Type)
+	}
+	if c.Range != "" {
+		fmt.Fprintf(sb, " range=%s", c.Range)
+	}
+	if c.Length != "" {
+		fmt.Fprintf(sb, " length=%s", c.Length)
+	}
+	for _, pt := range c.Patterns {
+		if pt.GetInverted() {
+			fmt.Fprintf(sb, " !pattern='%s'", pt.GetPattern())
+			continue
+		}
+		fmt.Fprintf(sb, " pattern='%s'", pt.GetPattern())
+	}
+	if len(c.Values) > 0 {
+		fmt.Fprintf(sb, " values=%s", strings.Join(c.Values, "|"))
+	}
+	if c.Target != "" {
+		fmt.Fprintf(sb, " leafref=%s", c.Target)
+	}
+	return sb.String()
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaKeysCmd)
+	schemaKeysCmd.Flags().StringVarP(&xpath, "path", "p", "", "path with wildcarded keys, e.g. /interface[name=*]/subinterface[index=*]")
+	_ = schemaKeysCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+}
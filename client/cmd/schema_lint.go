@@ -0,0 +1,121 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var lintRules []string
+var lintSeverity string
+var lintListRules bool
+
+// schemaLintCmd represents the lint command
+var schemaLintCmd = &cobra.Command{
+	Use:          "lint",
+	Short:        "report the lint diagnostics of a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		if lintListRules {
+			rsp, err := extClient.ListLintRules(ctx, &api.ListLintRulesRequest{})
+			if err != nil {
+				return err
+			}
+			switch format {
+			case "table", "":
+				tableData := make([][]string, 0, len(rsp.Rules))
+				for _, r := range rsp.Rules {
+					tableData = append(tableData, []string{r.Name, r.Severity, r.Description, r.Plugin})
+				}
+				table := tablewriter.NewWriter(os.Stdout)
+				table.SetHeader([]string{"Rule", "Severity", "Description", "Plugin"})
+				table.SetAlignment(tablewriter.ALIGN_LEFT)
+				table.SetAutoWrapText(false)
+				table.AppendBulk(tableData)
+				table.Render()
+			case "json":
+				b, err := json.MarshalIndent(rsp, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+			}
+			return nil
+		}
+		rsp, err := extClient.GetSchemaLint(ctx, &api.GetSchemaLintRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Rules:    lintRules,
+			Severity: lintSeverity,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			if rsp.NotLinted {
+				fmt.Println("the schema is not linted, set its lint config")
+				return nil
+			}
+			if len(rsp.Diagnostics) == 0 {
+				fmt.Println("no lint diagnostics")
+				return nil
+			}
+			tableData := make([][]string, 0, len(rsp.Diagnostics))
+			for _, d := range rsp.Diagnostics {
+				tableData = append(tableData, []string{d.Severity, d.Rule, d.Location, d.Path, d.Message})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Severity", "Rule", "Location", "Path", "Message"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaLintCmd)
+	schemaLintCmd.Flags().StringSliceVarP(&lintRules, "rule", "", nil, "report the diagnostics of these rules only")
+	schemaLintCmd.Flags().StringVarP(&lintSeverity, "severity", "", "", "report the diagnostics of this severity or a higher one: error, warning or info")
+	schemaLintCmd.Flags().BoolVarP(&lintListRules, "list-rules", "", false, "list the available lint rules")
+}
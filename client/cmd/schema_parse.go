@@ -0,0 +1,152 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// schemaParseCmd represents the parse command
+var schemaParseCmd = &cobra.Command{
+	Use:   "parse",
+	Short: "parse YANG modules on the server and report the errors found, nothing is stored",
+	Long: `parse YANG modules on the server and report the errors found, nothing is stored.
+The imports are resolved from the --dir files and, if --vendor and --version are set,
+from the files of that schema, whose features then apply.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if len(schemaFiles) == 0 {
+			return errors.New("missing --file")
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		stream, err := extClient.ParseModules(ctx)
+		if err != nil {
+			return err
+		}
+		first := new(api.ParseModulesRequest)
+		if schemaVendor != "" || schemaVersion != "" {
+			first.Schema = &sdcpb.Schema{Name: schemaName, Vendor: schemaVendor, Version: schemaVersion}
+		}
+		err = stream.Send(first)
+		for _, f := range schemaFiles {
+			if err != nil {
+				break
+			}
+			err = utils.WalkFiles(f, parseFileFn(stream, false))
+		}
+		for _, d := range schemaDirs {
+			if err != nil {
+				break
+			}
+			err = utils.WalkFiles(d, parseFileFn(stream, true))
+		}
+		// io.EOF: the server ended the stream, its status is returned by CloseAndRecv
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		rsp, err := stream.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			for _, d := range rsp.Errors {
+				fmt.Println(parseDiagnostic(d))
+			}
+			for _, w := range rsp.Warnings {
+				fmt.Printf("warning: %s\n", w)
+			}
+			if len(rsp.Errors) == 0 {
+				fmt.Printf("parsed %d module(s): %s\n", len(rsp.Modules), strings.Join(rsp.Modules, ", "))
+			}
+		}
+		if len(rsp.Errors) > 0 {
+			return fmt.Errorf("%d error(s) found in the modules", len(rsp.Errors))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaParseCmd)
+	schemaParseCmd.Flags().StringArrayVarP(&schemaFiles, "file", "", []string{}, "path to file(s) containing a YANG module")
+	schemaParseCmd.Flags().StringArrayVarP(&schemaDirs, "dir", "", []string{}, "path to file(s) containing a YANG module dependency")
+	schemaParseCmd.Flags().IntVarP(&uploadSize, "size", "", 1024*100, "upload chunk size")
+}
+
+func parseFileFn(stream api.SchemaServerExt_ParseModulesClient, dependency bool) func(path string) error {
+	return func(path string) error {
+		if !utils.IsYANGFile(path) {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		// an empty file is still created
+		for start := 0; start == 0 || start < len(b); start += uploadSize {
+			end := start + uploadSize
+			if end > len(b) {
+				end = len(b)
+			}
+			err = stream.Send(&api.ParseModulesRequest{
+				FileName:   utils.SlashPath(path),
+				Contents:   b[start:end],
+				Dependency: dependency,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// parseDiagnostic formats d as "file:line:col: message".
+func parseDiagnostic(d *api.ParseDiagnostic) string {
+	var pos string
+	switch {
+	case d.File == "":
+	case d.Line == 0:
+		pos = d.File + ": "
+	default:
+		pos = fmt.Sprintf("%s:%d:%d: ", d.File, d.Line, d.Column)
+	}
+	return pos + d.Message
+}
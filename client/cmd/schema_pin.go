@@ -0,0 +1,120 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var pinTTL time.Duration
+var pinFingerprint string
+
+// schemaPinCmd represents the pin command
+var schemaPinCmd = &cobra.Command{
+	Use:   "pin",
+	Short: "manage the pinned schemas, kept unchanged across reloads for a TTL",
+}
+
+// schemaPinCreateCmd represents the pin create command
+var schemaPinCreateCmd = &cobra.Command{
+	Use:          "create",
+	Short:        "pin the current tree of the schema, served at version <version>+<fingerprint>",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.PinSchema(ctx, &api.PinSchemaRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Fingerprint: pinFingerprint,
+			TTL:         pinTTL,
+		})
+		if err != nil {
+			return err
+		}
+		return printSchemaPins([]*api.SchemaPin{rsp.Pin})
+	},
+}
+
+// schemaPinListCmd represents the pin list command
+var schemaPinListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "list the pinned schemas",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ListSchemaPins(ctx, &api.ListSchemaPinsRequest{})
+		if err != nil {
+			return err
+		}
+		return printSchemaPins(rsp.Pins)
+	},
+}
+
+func printSchemaPins(pins []*api.SchemaPin) error {
+	switch format {
+	case "table", "":
+		tableData := make([][]string, 0, len(pins))
+		for _, p := range pins {
+			tableData = append(tableData, []string{p.Schema.GetName(), p.Schema.GetVendor(), p.Schema.GetVersion(),
+				p.Expires.Format(time.RFC3339)})
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Vendor", "Version", "Expires"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(tableData)
+		table.Render()
+	case "json":
+		b, err := json.MarshalIndent(pins, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	}
+	return nil
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaPinCmd)
+	schemaPinCmd.AddCommand(schemaPinCreateCmd, schemaPinListCmd)
+	schemaPinCreateCmd.Flags().DurationVarP(&pinTTL, "ttl", "", 0, "how long the schema stays pinned, the server default if not set")
+	schemaPinCreateCmd.Flags().StringVarP(&pinFingerprint, "fingerprint", "", "", "fail if the schema does not have this fingerprint")
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/sdcio/schema-server/pkg/store/shmstore"
+)
+
+var shmDir string
+
+// schemaShmCmd represents the shm command
+var schemaShmCmd = &cobra.Command{
+	Use:          "shm",
+	Short:        "get a schema element from the shared memory snapshot of a co-located server, without gRPC",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		s, err := shmstore.Open(filepath.Join(shmDir, shmstore.FileName(schemaName, schemaVendor, schemaVersion)))
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		if xpath == "" {
+			fmt.Printf("generation: %s\nelements: %d\n", time.Unix(0, int64(s.Generation())).Format(time.RFC3339Nano), s.Len())
+			return nil
+		}
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		se, err := s.Get(p)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			b, err := json.MarshalIndent(se, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		fmt.Println(prototext.Format(se))
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaShmCmd)
+	schemaShmCmd.Flags().StringVarP(&shmDir, "dir", "", "/dev/shm/schema-server", "shared memory directory of the server")
+	schemaShmCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath, the snapshot generation and size are printed if not set")
+}
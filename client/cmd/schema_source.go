@@ -0,0 +1,95 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var sourceModule string
+var sourceRevision string
+var sourceOutput string
+
+// schemaSourceCmd represents the source command
+var schemaSourceCmd = &cobra.Command{
+	Use:          "source",
+	Short:        "get the YANG source file of a module or a submodule of a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		stream, err := extClient.GetModuleSource(ctx, &api.GetModuleSourceRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Module:   sourceModule,
+			Revision: sourceRevision,
+		})
+		if err != nil {
+			return err
+		}
+		buf := new(bytes.Buffer)
+		var file, digest string
+		for {
+			rsp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if rsp.File != "" {
+				file = rsp.File
+			}
+			buf.Write(rsp.Contents)
+			digest = rsp.Digest
+		}
+		sum := sha256.Sum256(buf.Bytes())
+		if digest != hex.EncodeToString(sum[:]) {
+			return fmt.Errorf("%s: digest mismatch, got %x, expected %s", file, sum, digest)
+		}
+		if sourceOutput != "" {
+			return os.WriteFile(sourceOutput, buf.Bytes(), 0644)
+		}
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaSourceCmd)
+	schemaSourceCmd.Flags().StringVarP(&sourceModule, "module", "m", "", "name of the module or submodule")
+	schemaSourceCmd.Flags().StringVarP(&sourceRevision, "revision", "", "", "revision of the module, the latest one if not set")
+	schemaSourceCmd.Flags().StringVarP(&sourceOutput, "output", "o", "", "file to write the source to, stdout if not set")
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var stateMessage string
+
+// schemaStateCmd represents the state command
+var schemaStateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "manage the schemas lifecycle states",
+}
+
+// schemaStateSetCmd represents the state set command
+var schemaStateSetCmd = &cobra.Command{
+	Use:          "set <staged|active|deprecated|archived>",
+	Short:        "move a schema to a lifecycle state",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.SetSchemaState(ctx, &api.SetSchemaStateRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			State:   args[0],
+			Message: stateMessage,
+		})
+		if err != nil {
+			return err
+		}
+		return printSchemaStates([]*api.SchemaState{rsp.State})
+	},
+}
+
+// schemaStateListCmd represents the state list command
+var schemaStateListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "list the schemas that are not active",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ListSchemaStates(ctx, &api.ListSchemaStatesRequest{})
+		if err != nil {
+			return err
+		}
+		return printSchemaStates(rsp.States)
+	},
+}
+
+func printSchemaStates(sts []*api.SchemaState) error {
+	switch format {
+	case "table", "":
+		tableData := make([][]string, 0, len(sts))
+		for _, st := range sts {
+			tableData = append(tableData, []string{
+				st.Schema.GetName(), st.Schema.GetVendor(), st.Schema.GetVersion(),
+				st.State, st.Since.Format(time.RFC3339), st.Message,
+			})
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Vendor", "Version", "State", "Since", "Message"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(tableData)
+		table.Render()
+	case "json":
+		b, err := json.MarshalIndent(sts, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	}
+	return nil
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaStateCmd)
+	schemaStateCmd.AddCommand(schemaStateSetCmd, schemaStateListCmd)
+	schemaStateSetCmd.Flags().StringVar(&stateMessage, "message", "", "deprecation notice returned to the clients of a deprecated schema")
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaStatsCmd represents the stats command
+var schemaStatsCmd = &cobra.Command{
+	Use:          "stats",
+	Short:        "print the node counts and the parse duration of a schema, all the loaded ones if none is given",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		req := &api.GetSchemaStatsRequest{}
+		if schemaName != "" || schemaVendor != "" || schemaVersion != "" {
+			req.Schema = &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetSchemaStats(ctx, req)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			tableData := make([][]string, 0, len(rsp.Schemas)+1)
+			for _, st := range rsp.Schemas {
+				if st.Unknown {
+					tableData = append(tableData, []string{st.Schema.GetName(), st.Schema.GetVendor(), st.Schema.GetVersion(), "unknown"})
+					continue
+				}
+				tableData = append(tableData, statsRow(st.Schema.GetName(), st.Schema.GetVendor(), st.Schema.GetVersion(), st))
+			}
+			if rsp.Total != nil && len(rsp.Schemas) > 1 {
+				tableData = append(tableData, statsRow("total", "", "", rsp.Total))
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Name", "Vendor", "Version", "Modules", "Containers", "Lists", "Leaves", "Leaf-lists",
+				"Config", "State", "Max Depth", "Parse Duration", "Memory (KiB)"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoFormatHeaders(false)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		return nil
+	},
+}
+
+func statsRow(name, vendor, version string, st *api.SchemaStats) []string {
+	return []string{
+		name, vendor, version,
+		strconv.Itoa(st.Modules),
+		strconv.Itoa(st.Containers),
+		strconv.Itoa(st.Lists),
+		strconv.Itoa(st.Leaves),
+		strconv.Itoa(st.LeafLists),
+		strconv.Itoa(st.ConfigNodes),
+		strconv.Itoa(st.StateNodes),
+		strconv.Itoa(st.MaxDepth),
+		st.ParseDuration.String(),
+		strconv.FormatInt(st.MemoryEstimate/1024, 10),
+	}
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaStatsCmd)
+}
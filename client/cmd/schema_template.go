@@ -0,0 +1,85 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var templateValues []string
+
+// schemaTemplateCmd represents the template command
+var schemaTemplateCmd = &cobra.Command{
+	Use:          "template",
+	Short:        "print the schema node and the placeholder types of a path template, e.g: /interface[name={iface}]",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		req := &api.GetPathTemplateRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path:   p,
+			Values: make(map[string]string, len(templateValues)),
+		}
+		for _, v := range templateValues {
+			name, val, ok := strings.Cut(v, "=")
+			if !ok {
+				return fmt.Errorf("invalid placeholder value %q, expecting name=value", v)
+			}
+			req.Values[name] = val
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetPathTemplate(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Println(prototext.Format(rsp.Schema.SchemaElem))
+		for _, ph := range rsp.Placeholders {
+			fmt.Printf("{%s}: %s[%s]: %s\n", ph.Name, p.GetElem()[ph.Elem].GetName(), ph.Key,
+				prototext.MarshalOptions{}.Format(ph.Type))
+		}
+		if rsp.Rendered != nil {
+			fmt.Println(utils.ToXPath(rsp.Rendered, false))
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaTemplateCmd)
+	schemaTemplateCmd.Flags().StringVarP(&xpath, "path", "p", "", "path template")
+	schemaTemplateCmd.Flags().StringArrayVarP(&templateValues, "value", "", nil, "placeholder value(s) to render the template with, as name=value")
+}
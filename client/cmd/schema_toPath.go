@@ -19,6 +19,8 @@ import (
 
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/encoding/prototext"
 )
 
@@ -46,12 +48,14 @@ var schemaToPathCmd = &cobra.Command{
 		fmt.Println(prototext.Format(req))
 		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
 		defer cancel2()
-		rsp, err := schemaClient.ToPath(ctx, req)
+		var header metadata.MD
+		rsp, err := schemaClient.ToPath(ctx, req, grpc.Header(&header))
 		if err != nil {
 			return err
 		}
 		fmt.Println("response:")
 		fmt.Println(prototext.Format(rsp))
+		printPlaceholders(header)
 		return nil
 	},
 }
@@ -0,0 +1,110 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+var translateGNMIPath string
+var translatePrefix string
+var translatePath string
+var translateQualification string
+
+// schemaTranslatePathCmd represents the translate-path command
+var schemaTranslatePathCmd = &cobra.Command{
+	Use:          "translate-path",
+	Short:        "translate a gNMI path to a schema path or a schema path to a gNMI path",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		req := &api.TranslatePathRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Qualification: translateQualification,
+		}
+		var err error
+		if translateGNMIPath != "" {
+			req.GNMIPath, err = parseGNMIPath(translateGNMIPath)
+			if err != nil {
+				return err
+			}
+		}
+		if translatePrefix != "" {
+			req.Prefix, err = parseGNMIPath(translatePrefix)
+			if err != nil {
+				return err
+			}
+		}
+		if translatePath != "" {
+			req.Path, err = utils.ParsePath(translatePath)
+			if err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.TranslatePath(ctx, req)
+		if err != nil {
+			return err
+		}
+		if rsp.GNMIPath == nil {
+			fmt.Println(rsp.XPath)
+			return nil
+		}
+		fmt.Println(protojson.Format(rsp.GNMIPath.Path))
+		return nil
+	},
+}
+
+// parseGNMIPath parses the string form of a gNMI path, origin:/elem[key=value]/...
+func parseGNMIPath(s string) (*api.GNMIPath, error) {
+	p, err := utils.ParsePath(s)
+	if err != nil {
+		return nil, err
+	}
+	gp := &gnmipb.Path{
+		Origin: p.GetOrigin(),
+		Elem:   make([]*gnmipb.PathElem, 0, len(p.GetElem())),
+	}
+	for _, pe := range p.GetElem() {
+		gp.Elem = append(gp.Elem, &gnmipb.PathElem{Name: pe.GetName(), Key: pe.GetKey()})
+	}
+	return &api.GNMIPath{Path: gp}, nil
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaTranslatePathCmd)
+	schemaTranslatePathCmd.Flags().StringVarP(&translateGNMIPath, "gnmi-path", "g", "", "gNMI path to translate to a schema path")
+	schemaTranslatePathCmd.Flags().StringVarP(&translatePrefix, "prefix", "", "", "gNMI prefix of the gNMI path")
+	schemaTranslatePathCmd.Flags().StringVarP(&translatePath, "path", "p", "", "schema path to translate to a gNMI path")
+	schemaTranslatePathCmd.Flags().StringVarP(&translateQualification, "qualification", "", "", "module qualification of the gNMI path element names: module or rfc7951, none if empty")
+}
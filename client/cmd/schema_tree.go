@@ -0,0 +1,78 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var treeDepth int
+
+// schemaTreeCmd represents the tree command
+var schemaTreeCmd = &cobra.Command{
+	Use:          "tree",
+	Short:        "render a schema or subtree as a pyang style tree",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.RenderTree(ctx, &api.RenderTreeRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path:     p,
+			MaxDepth: treeDepth,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			fmt.Print(rsp.Tree)
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaTreeCmd)
+	schemaTreeCmd.Flags().StringVarP(&xpath, "path", "p", "", "path of the subtree to render, the whole schema if not set")
+	schemaTreeCmd.Flags().IntVarP(&treeDepth, "depth", "", 0, "levels rendered below the path, unlimited if not set")
+	_ = schemaTreeCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+}
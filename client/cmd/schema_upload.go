@@ -20,13 +20,14 @@ import (
 	"crypto/sha512"
 	"fmt"
 	"hash"
-	"io/fs"
 	"os"
-	"path/filepath"
 
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sdcio/schema-server/pkg/utils"
 )
 
 var uploadSize int
@@ -82,14 +83,14 @@ var schemaUploadCmd = &cobra.Command{
 			}
 			// walk files and upload
 			for _, schemaFile := range schemaFiles {
-				err = filepath.Walk(schemaFile, uploadFileFn(uploadClient, sdcpb.UploadSchemaFile_MODULE))
+				err = utils.WalkFiles(schemaFile, uploadFileFn(uploadClient, sdcpb.UploadSchemaFile_MODULE))
 				if err != nil {
 					senderErrCh <- err
 				}
 			}
 			// walk dir and upload
 			for _, schemaFile := range schemaDirs {
-				err = filepath.Walk(schemaFile, uploadFileFn(uploadClient, sdcpb.UploadSchemaFile_DEPENDENCY))
+				err = utils.WalkFiles(schemaFile, uploadFileFn(uploadClient, sdcpb.UploadSchemaFile_DEPENDENCY))
 				if err != nil {
 					senderErrCh <- err
 				}
@@ -119,10 +120,24 @@ var schemaUploadCmd = &cobra.Command{
 			return err
 		}
 		log.Infof("schema parsed.")
+		md, err := uploadClient.Header()
+		if err == nil && len(md.Get("x-schema-registered-vendor")) > 0 {
+			fmt.Printf("registered schema: name=%s vendor=%s version=%s\n",
+				firstMD(md, "x-schema-registered-name"),
+				firstMD(md, "x-schema-registered-vendor"),
+				firstMD(md, "x-schema-registered-version"))
+		}
 		return nil
 	},
 }
 
+func firstMD(md metadata.MD, key string) string {
+	if vs := md.Get(key); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
 func init() {
 	schemaCmd.AddCommand(schemaUploadCmd)
 	schemaUploadCmd.Flags().StringArrayVarP(&schemaFiles, "file", "", []string{}, "path to file(s) containing a YANG module")
@@ -133,14 +148,11 @@ func init() {
 	schemaUploadCmd.Flags().StringVarP(&hashMethod, "hash", "", "md5", "hash method: md5, sha256 or sha512")
 }
 
-func uploadFileFn(uploadClient sdcpb.SchemaServer_UploadSchemaClient, ft sdcpb.UploadSchemaFile_FileType) func(path string, info fs.FileInfo, err error) error {
-	return func(path string, info fs.FileInfo, err error) error {
+func uploadFileFn(uploadClient sdcpb.SchemaServer_UploadSchemaClient, ft sdcpb.UploadSchemaFile_FileType) func(path string) error {
+	return func(path string) error {
 		log.Debugf("found file %s", path)
-		if err != nil {
-			return err
-		}
-		// skip dirs and non .yang files
-		if info.IsDir() || filepath.Ext(path) != ".yang" {
+		// skip non .yang files
+		if !utils.IsYANGFile(path) {
 			return nil
 		}
 		log.Infof("reading file %s", path)
@@ -162,7 +174,7 @@ func uploadFileFn(uploadClient sdcpb.SchemaServer_UploadSchemaClient, ft sdcpb.U
 			err = uploadClient.Send(&sdcpb.UploadSchemaRequest{
 				Upload: &sdcpb.UploadSchemaRequest_SchemaFile{
 					SchemaFile: &sdcpb.UploadSchemaFile{
-						FileName: path,
+						FileName: utils.SlashPath(path),
 						FileType: ft,
 						Contents: chunk,
 					},
@@ -176,7 +188,7 @@ func uploadFileFn(uploadClient sdcpb.SchemaServer_UploadSchemaClient, ft sdcpb.U
 		err = uploadClient.Send(&sdcpb.UploadSchemaRequest{
 			Upload: &sdcpb.UploadSchemaRequest_SchemaFile{
 				SchemaFile: &sdcpb.UploadSchemaFile{
-					FileName: path,
+					FileName: utils.SlashPath(path),
 					FileType: ft,
 					Hash: &sdcpb.Hash{
 						Method: sdcpb.Hash_MD5,
@@ -0,0 +1,96 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var leafValue string
+
+// schemaValidateValueCmd represents the validate-value command
+var schemaValidateValueCmd = &cobra.Command{
+	Use:          "validate-value",
+	Short:        "check a value against the type of a leaf or leaf-list",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		p, err := parseUserPath(xpath)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.ValidateValue(ctx, &api.ValidateValueRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Path:  p,
+			Value: leafValue,
+		})
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "table", "":
+			if rsp.Valid {
+				fmt.Printf("valid %s value\n", rsp.Type)
+				return nil
+			}
+			tableData := make([][]string, 0, len(rsp.Violations))
+			for _, vl := range rsp.Violations {
+				tableData = append(tableData, []string{vl.Type, vl.Constraint, vl.Statement, vl.Message})
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Type", "Constraint", "Statement", "Message"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			table.AppendBulk(tableData)
+			table.Render()
+		case "json":
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		}
+		if !rsp.Valid {
+			return fmt.Errorf("invalid value %q", leafValue)
+		}
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaValidateValueCmd)
+	schemaValidateValueCmd.Flags().StringVarP(&xpath, "path", "p", "", "xpath of the leaf or leaf-list")
+	_ = schemaValidateValueCmd.RegisterFlagCompletionFunc("path", pathFlagCompletion)
+	schemaValidateValueCmd.Flags().StringVarP(&leafValue, "value", "v", "", "raw value")
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// schemaWatchCmd represents the watch command
+var schemaWatchCmd = &cobra.Command{
+	Use:          "watch",
+	Short:        "print the schemas added, removed or reloaded",
+	Long:         "print the schemas added, removed or reloaded, the schema name, vendor and version flags select the schemas to watch",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		req := &api.WatchSchemaChangesRequest{}
+		if schemaName != "" || schemaVendor != "" || schemaVersion != "" {
+			req.Schemas = []*sdcpb.Schema{{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			}}
+		}
+		stream, err := extClient.WatchSchemaChanges(ctx, req)
+		if err != nil {
+			return err
+		}
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			switch format {
+			case "table", "":
+				fmt.Printf("%s\t%-8s\t%s\t%s\t%s\n", ev.Time.Format(time.RFC3339), ev.Type,
+					ev.Schema.GetName(), ev.Schema.GetVendor(), ev.Schema.GetVersion())
+			case "json":
+				b, err := json.Marshal(ev)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+			}
+		}
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaWatchCmd)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var yangLibraryOutput string
+
+// schemaYangLibraryCmd represents the yang-library command
+var schemaYangLibraryCmd = &cobra.Command{
+	Use:          "yang-library",
+	Short:        "print the RFC 8525 YANG library (modules-state) document of a schema",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetYangLibrary(ctx, &api.GetYangLibraryRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		buf := new(bytes.Buffer)
+		err = json.Indent(buf, rsp.Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if yangLibraryOutput != "" {
+			return os.WriteFile(yangLibraryOutput, buf.Bytes(), 0644)
+		}
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaYangLibraryCmd)
+	schemaYangLibraryCmd.Flags().StringVarP(&yangLibraryOutput, "output", "o", "", "file to write the document to, stdout if not set")
+}
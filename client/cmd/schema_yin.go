@@ -0,0 +1,79 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+var yinModule string
+var yinRevision string
+var yinOutput string
+
+// schemaYINCmd represents the yin command
+var schemaYINCmd = &cobra.Command{
+	Use:          "yin",
+	Short:        "export a module or a submodule of a schema as YIN",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		extClient, err := createSchemaExtClient(ctx, addr)
+		if err != nil {
+			return err
+		}
+		ctx, cancel2 := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel2()
+		rsp, err := extClient.GetModuleYIN(ctx, &api.GetModuleYINRequest{
+			Schema: &sdcpb.Schema{
+				Name:    schemaName,
+				Vendor:  schemaVendor,
+				Version: schemaVersion,
+			},
+			Module:   yinModule,
+			Revision: yinRevision,
+		})
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			b, err := json.MarshalIndent(rsp, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		if yinOutput != "" {
+			return os.WriteFile(yinOutput, []byte(rsp.YIN), 0644)
+		}
+		_, err = os.Stdout.WriteString(rsp.YIN)
+		return err
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaYINCmd)
+	schemaYINCmd.Flags().StringVarP(&yinModule, "module", "m", "", "name of the module or submodule")
+	schemaYINCmd.Flags().StringVarP(&yinRevision, "revision", "", "", "revision of the module, the latest one if not set")
+	schemaYINCmd.Flags().StringVarP(&yinOutput, "output", "o", "", "file to write the YIN document to, stdout if not set")
+}
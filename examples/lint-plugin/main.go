@@ -0,0 +1,56 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command lint-plugin is an example lint plugin, build it with
+//
+//	go build -buildmode=plugin -o contact.so ./examples/lint-plugin
+//
+// and list the file under schema-store.lint-plugins.
+package main
+
+import (
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
+)
+
+// Rules is the symbol looked up by the server, see lint.PluginSymbol.
+func Rules() []lint.Rule {
+	return []lint.Rule{contactRule{}}
+}
+
+// contactRule requires the modules to tell who maintains them.
+type contactRule struct{}
+
+func (contactRule) Name() string { return "module-contact" }
+
+func (contactRule) Description() string {
+	return "modules have an organization and a contact statement"
+}
+
+func (contactRule) DefaultSeverity() string { return config.LintSeverityWarning }
+
+func (contactRule) CheckModule(m *yang.Module, report lint.ReportFunc) {
+	if m.Organization == nil {
+		report(m, "module %s has no organization statement", m.Name)
+	}
+	if m.Contact == nil {
+		report(m, "module %s has no contact statement", m.Name)
+	}
+}
+
+func (contactRule) CheckEntry(*yang.Entry, lint.ReportFunc) {}
+
+func main() {}
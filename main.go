@@ -20,18 +20,22 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"runtime"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 
+	"github.com/sdcio/schema-server/pkg/api"
 	"github.com/sdcio/schema-server/pkg/config"
 	"github.com/sdcio/schema-server/pkg/server"
 )
 
 var version = "dev"
 var commit = ""
+var date = ""
 
 var configFile string
 var debug bool
@@ -40,6 +44,18 @@ var stop bool
 var versionFlag bool
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		os.Exit(runBench(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		os.Exit(runBackup(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		os.Exit(runRestore(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		os.Exit(runQuery(os.Args[2:]))
+	}
 	pflag.StringVarP(&configFile, "config", "c", "schema-server.yaml", "config file path")
 	pflag.BoolVarP(&debug, "debug", "d", false, "set log level to DEBUG")
 	pflag.BoolVarP(&trace, "trace", "t", false, "set log level to TRACE")
@@ -59,7 +75,12 @@ func main() {
 	if trace {
 		log.SetLevel(log.TraceLevel)
 	}
+	log.Infof("schema-server version=%s commit=%s build-date=%s go=%s", version, commit, date, runtime.Version())
 	var s *server.Server
+	// server reloaded on SIGHUP
+	current := new(atomic.Pointer[server.Server])
+	setupReloadHandler(current)
+	setupTakeoverHandler(current)
 START:
 	if s != nil {
 		s.Stop()
@@ -81,30 +102,59 @@ START:
 		log.Errorf("failed to create server: %v", err)
 		os.Exit(1)
 	}
+	s.SetBuildInfo(server.BuildInfo{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+	})
+	s.SetConfigFile(configFile)
+	current.Store(s)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	setupCloseHandler(cancel)
+	setupCloseHandler(cancel, current)
 
 	err = s.Serve(ctx)
+	if stop {
+		// the process exits once the server is drained
+		select {}
+	}
 	if err != nil {
-		if stop {
-			return
-		}
 		log.Errorf("failed to run server: %v", err)
 		time.Sleep(time.Second)
 		goto START
 	}
 }
 
-func setupCloseHandler(cancelFn context.CancelFunc) {
+func setupCloseHandler(cancelFn context.CancelFunc, current *atomic.Pointer[server.Server]) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	go func() {
 		sig := <-c
 		fmt.Fprintf(os.Stderr, "\nreceived signal '%s'. terminating...\n", sig.String())
 		stop = true
+		if s := current.Load(); s != nil {
+			s.Drain()
+		}
 		cancelFn()
-		time.Sleep(500 * time.Millisecond)
 		os.Exit(0)
 	}()
 }
+
+// setupReloadHandler reloads the configured schemas of the current server on SIGHUP.
+func setupReloadHandler(current *atomic.Pointer[server.Server]) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		for range c {
+			s := current.Load()
+			if s == nil {
+				continue
+			}
+			log.Infof("received signal 'hangup'. reloading config %s...", configFile)
+			_, err := s.ReloadConfig(context.Background(), &api.ReloadConfigRequest{})
+			if err != nil {
+				log.Errorf("failed to reload config: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ListAnnotationsRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// name of the module defining the annotations,
+	// all the annotations of the schema are listed if empty.
+	Module string `json:"module,omitempty"`
+}
+
+type ListAnnotationsResponse struct {
+	// metadata annotations sorted by module qualified name
+	Annotations []*Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation is a metadata annotation defined by an md:annotation statement, RFC 7952.
+type Annotation struct {
+	Name string `json:"name,omitempty"`
+	// module defining the annotation, the one a submodule belongs to
+	Module string `json:"module,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// module qualified name, as in an RFC 7951 JSON document
+	Qualified   string                `json:"qualified,omitempty"`
+	Type        *sdcpb.SchemaLeafType `json:"type,omitempty"`
+	Units       string                `json:"units,omitempty"`
+	IfFeature   []string              `json:"if-feature,omitempty"`
+	Status      string                `json:"status,omitempty"`
+	Description string                `json:"description,omitempty"`
+}
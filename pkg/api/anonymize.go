@@ -0,0 +1,51 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type AnonymizeDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// RFC 7951 JSON config document rooted at the schema root
+	Document json.RawMessage `json:"document,omitempty"`
+	// applied in order before the default rules, the first matching rule applies
+	Rules []*AnonymizeRule `json:"rules,omitempty"`
+	// do not apply the default rules: mask the password and secret leaves, randomize
+	// the IP and MAC addresses, the IP prefixes, the host names and the serial numbers
+	NoDefaultRules bool `json:"no-default-rules,omitempty"`
+	// documents anonymized with the same seed get the same pseudonyms,
+	// a random seed is used if not set.
+	Seed string `json:"seed,omitempty"`
+}
+
+type AnonymizeRule struct {
+	// regular expression matched against the leaf schema path without keys nor module prefixes
+	Path string `json:"path,omitempty"`
+	// regular expression matched against the leaf type and typedef names
+	Type string `json:"type,omitempty"`
+	// one of "mask", "randomize" or "keep"
+	Action string `json:"action,omitempty"`
+}
+
+type AnonymizeDocumentResponse struct {
+	// anonymized document in its canonical form
+	Document json.RawMessage `json:"document,omitempty"`
+	// schema paths of the rewritten leaves
+	Paths []string `json:"paths,omitempty"`
+}
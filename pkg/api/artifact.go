@@ -0,0 +1,43 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GenerateArtifactRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// json-schema (draft-07), openapi (3.0), proto (a .proto file)
+	// or descriptor-set (a serialized google.protobuf.FileDescriptorSet)
+	Format string `json:"format,omitempty"`
+	// node the artifact describes, the whole schema if not set
+	Path             *sdcpb.Path `json:"path,omitempty"`
+	WithDescriptions bool        `json:"with-descriptions,omitempty"`
+	// leave the state nodes out, they are read only otherwise
+	ConfigOnly bool `json:"config-only,omitempty"`
+}
+
+type GenerateArtifactResponse struct {
+	// the JSON document, describing the RFC 7951 encoding of the data nodes.
+	// An openapi document has a RESTCONF data resource per top level node,
+	// or for the node of the path.
+	Document json.RawMessage `json:"document,omitempty"`
+	// the proto or descriptor-set artifact, with the messages of the top level
+	// nodes as fields of a Device message, or the message of the node of the path.
+	Content []byte `json:"content,omitempty"`
+}
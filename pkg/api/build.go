@@ -0,0 +1,41 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+const (
+	DocumentFormatJSON = "json"
+	DocumentFormatXML  = "xml"
+)
+
+type BuildDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// updates applied in order, a JSON value can be set on a
+	// container or a list entry path to merge a whole subtree.
+	Updates []*Update `json:"updates,omitempty"`
+	// one of "json" (RFC 7951, default) or "xml" (RFC 7950,
+	// wrapped in a NETCONF config element)
+	Format string `json:"format,omitempty"`
+}
+
+type BuildDocumentResponse struct {
+	Document json.RawMessage `json:"document,omitempty"`
+	XML      string          `json:"xml,omitempty"`
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// SetSchemaCanaryRequest makes Version the canary of Schema: the requests for Schema
+// from clients sending the x-schema-canary: true metadata are answered from Version.
+// An empty Version removes the canary of Schema.
+type SetSchemaCanaryRequest struct {
+	Schema  *sdcpb.Schema `json:"schema,omitempty"`
+	Version string        `json:"version,omitempty"`
+}
+
+type SetSchemaCanaryResponse struct {
+	Canaries []*SchemaCanary `json:"canaries,omitempty"`
+}
+
+type ListSchemaCanariesRequest struct{}
+
+type ListSchemaCanariesResponse struct {
+	Canaries []*SchemaCanary `json:"canaries,omitempty"`
+}
+
+// SchemaCanary is the canary version of a stable schema.
+type SchemaCanary struct {
+	Schema  *sdcpb.Schema `json:"schema,omitempty"`
+	Version string        `json:"version,omitempty"`
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type CanonicalPathRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	Paths  []*sdcpb.Path `json:"paths,omitempty"`
+}
+
+type CanonicalPathResponse struct {
+	// canonical paths in request order
+	Paths []*CanonicalPath `json:"paths,omitempty"`
+}
+
+type CanonicalPath struct {
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// xpath with the keys in the schema declared order
+	XPath string `json:"xpath,omitempty"`
+}
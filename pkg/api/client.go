@@ -0,0 +1,852 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+)
+
+// SchemaServerExtClient is the client API for the SchemaServerExt service.
+type SchemaServerExtClient interface {
+	// returns the server build info, enabled features and limits
+	GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*GetServerInfoResponse, error)
+	// pre-resolves and caches a set of paths expected to be used shortly,
+	// fails with FailedPrecondition if the store does not cache the schema lookups
+	PrefetchSchema(ctx context.Context, in *PrefetchSchemaRequest, opts ...grpc.CallOption) (*PrefetchSchemaResponse, error)
+	// EncodeJSON returns the RFC 7951 JSON encoding of the raw values found at a path.
+	EncodeJSON(ctx context.Context, in *EncodeJSONRequest, opts ...grpc.CallOption) (*EncodeJSONResponse, error)
+	// CanonicalPath validates path keys against the schema and returns the paths with keys in the schema declared order.
+	CanonicalPath(ctx context.Context, in *CanonicalPathRequest, opts ...grpc.CallOption) (*CanonicalPathResponse, error)
+	// DiffDocument returns the path level changes between two instance documents of a schema.
+	DiffDocument(ctx context.Context, in *DiffDocumentRequest, opts ...grpc.CallOption) (*DiffDocumentResponse, error)
+	// NormalizeDocument returns the canonical form of an instance document of a schema.
+	NormalizeDocument(ctx context.Context, in *NormalizeDocumentRequest, opts ...grpc.CallOption) (*NormalizeDocumentResponse, error)
+	// BuildDocument assembles a nested instance document from a set of path and value updates.
+	BuildDocument(ctx context.Context, in *BuildDocumentRequest, opts ...grpc.CallOption) (*BuildDocumentResponse, error)
+	// FlattenDocument returns the leaves of an instance document as path and typed value updates.
+	FlattenDocument(ctx context.Context, in *FlattenDocumentRequest, opts ...grpc.CallOption) (*FlattenDocumentResponse, error)
+	// GetPathTemplate returns the schema node of a path template and the types of its placeholders.
+	GetPathTemplate(ctx context.Context, in *GetPathTemplateRequest, opts ...grpc.CallOption) (*GetPathTemplateResponse, error)
+	// GetSchemaMetadata returns the organization, contact and license of the modules of a schema.
+	GetSchemaMetadata(ctx context.Context, in *GetSchemaMetadataRequest, opts ...grpc.CallOption) (*GetSchemaMetadataResponse, error)
+	// GetRequestJournal returns the last requests recorded in the request journal.
+	GetRequestJournal(ctx context.Context, in *GetRequestJournalRequest, opts ...grpc.CallOption) (*GetRequestJournalResponse, error)
+	// AnonymizeDocument masks and randomizes the sensitive leaves of a config document
+	AnonymizeDocument(ctx context.Context, in *AnonymizeDocumentRequest, opts ...grpc.CallOption) (*AnonymizeDocumentResponse, error)
+	// GetInstanceTemplate returns the instance path template of a list path with a typed placeholder per key.
+	GetInstanceTemplate(ctx context.Context, in *GetInstanceTemplateRequest, opts ...grpc.CallOption) (*GetInstanceTemplateResponse, error)
+	// GetSchemaComposition returns the unresolved imports, conflicting augments and duplicate nodes of a schema.
+	GetSchemaComposition(ctx context.Context, in *GetSchemaCompositionRequest, opts ...grpc.CallOption) (*GetSchemaCompositionResponse, error)
+	// ExpandPathStream streams the paths ExpandPath returns in batches, the paths are not sorted.
+	ExpandPathStream(ctx context.Context, in *sdcpb.ExpandPathRequest, opts ...grpc.CallOption) (SchemaServerExt_ExpandPathStreamClient, error)
+	// WatchSchemaChanges streams a SchemaEvent each time a watched schema is added, removed or reloaded.
+	WatchSchemaChanges(ctx context.Context, in *WatchSchemaChangesRequest, opts ...grpc.CallOption) (SchemaServerExt_WatchSchemaChangesClient, error)
+	// DiffSchema streams the differences between two schemas.
+	DiffSchema(ctx context.Context, in *DiffSchemaRequest, opts ...grpc.CallOption) (SchemaServerExt_DiffSchemaClient, error)
+	// ParseModules parses the YANG files streamed by the client and reports the errors found,
+	// nothing is stored.
+	ParseModules(ctx context.Context, opts ...grpc.CallOption) (SchemaServerExt_ParseModulesClient, error)
+	// ExportSchema streams a canonical text representation of a schema,
+	// stable from one load or server version to the next for golden files.
+	ExportSchema(ctx context.Context, in *ExportSchemaRequest, opts ...grpc.CallOption) (SchemaServerExt_ExportSchemaClient, error)
+	// QuerySchema returns the schema nodes selected by a query along with their attributes.
+	QuerySchema(ctx context.Context, in *QuerySchemaRequest, opts ...grpc.CallOption) (*QuerySchemaResponse, error)
+	// GetSchemaFingerprint returns the fingerprint of the current content of a schema.
+	GetSchemaFingerprint(ctx context.Context, in *GetSchemaFingerprintRequest, opts ...grpc.CallOption) (*GetSchemaFingerprintResponse, error)
+	// ExportSchemaChanges returns the containers and lists of a schema changed since a fingerprint.
+	ExportSchemaChanges(ctx context.Context, in *ExportSchemaChangesRequest, opts ...grpc.CallOption) (*ExportSchemaChangesResponse, error)
+	// re-reads the config file and loads, removes or reloads the configured schemas that changed
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	// SetSchemaState moves a schema to a lifecycle state: staged, active, deprecated or archived
+	SetSchemaState(ctx context.Context, in *SetSchemaStateRequest, opts ...grpc.CallOption) (*SetSchemaStateResponse, error)
+	// ListSchemaStates returns the lifecycle state of the schemas that are not active
+	ListSchemaStates(ctx context.Context, in *ListSchemaStatesRequest, opts ...grpc.CallOption) (*ListSchemaStatesResponse, error)
+	// SetSchemaCanary sets or removes the canary version of a schema
+	SetSchemaCanary(ctx context.Context, in *SetSchemaCanaryRequest, opts ...grpc.CallOption) (*SetSchemaCanaryResponse, error)
+	// ListSchemaCanaries returns the canary versions of the schemas
+	ListSchemaCanaries(ctx context.Context, in *ListSchemaCanariesRequest, opts ...grpc.CallOption) (*ListSchemaCanariesResponse, error)
+	// ExpandWildcardPath returns the leaf paths matching a path with wildcards, a page at a time
+	ExpandWildcardPath(ctx context.Context, in *ExpandWildcardPathRequest, opts ...grpc.CallOption) (*ExpandWildcardPathResponse, error)
+	// GetSchemaLint returns the diagnostics of the lint rules run when a schema is loaded
+	GetSchemaLint(ctx context.Context, in *GetSchemaLintRequest, opts ...grpc.CallOption) (*GetSchemaLintResponse, error)
+	// ListLintRules returns the lint rules a schema can be configured with
+	ListLintRules(ctx context.Context, in *ListLintRulesRequest, opts ...grpc.CallOption) (*ListLintRulesResponse, error)
+	// CompletePath returns the possible next elements of a partial path, for CLI completion
+	CompletePath(ctx context.Context, in *CompletePathRequest, opts ...grpc.CallOption) (*CompletePathResponse, error)
+	// ResolveLeafref returns the schema paths a leafref leaf refers to
+	ResolveLeafref(ctx context.Context, in *ResolveLeafrefRequest, opts ...grpc.CallOption) (*ResolveLeafrefResponse, error)
+	// ValidateExpression checks an XPath must or when expression against a schema
+	ValidateExpression(ctx context.Context, in *ValidateExpressionRequest, opts ...grpc.CallOption) (*ValidateExpressionResponse, error)
+	// ValidateValue checks a value against the type of a leaf or leaf-list
+	ValidateValue(ctx context.Context, in *ValidateValueRequest, opts ...grpc.CallOption) (*ValidateValueResponse, error)
+	// ListSubmodules returns the submodules of a schema with the module they belong to and their includes.
+	ListSubmodules(ctx context.Context, in *ListSubmodulesRequest, opts ...grpc.CallOption) (*ListSubmodulesResponse, error)
+	// GetDefaults returns the leaves below a container or a list path that have a default value.
+	GetDefaults(ctx context.Context, in *GetDefaultsRequest, opts ...grpc.CallOption) (*GetDefaultsResponse, error)
+	// PinSchema keeps the current tree of a schema for reproducible multi-RPC workflows, across its reloads.
+	PinSchema(ctx context.Context, in *PinSchemaRequest, opts ...grpc.CallOption) (*PinSchemaResponse, error)
+	// ListSchemaPins returns the pinned schemas.
+	ListSchemaPins(ctx context.Context, in *ListSchemaPinsRequest, opts ...grpc.CallOption) (*ListSchemaPinsResponse, error)
+	// ListIdentities returns the identities derived from a base identity, transitively and across modules.
+	ListIdentities(ctx context.Context, in *ListIdentitiesRequest, opts ...grpc.CallOption) (*ListIdentitiesResponse, error)
+	// ListSchemaElements returns a page of the schema elements along a path, trimmed to a field mask
+	ListSchemaElements(ctx context.Context, in *ListSchemaElementsRequest, opts ...grpc.CallOption) (*ListSchemaElementsResponse, error)
+	// GetSchemaSubtree walks the schema subtree found at a path and streams its elements in batches,
+	// for the subtrees too large for a single GetSchema response.
+	GetSchemaSubtree(ctx context.Context, in *GetSchemaSubtreeRequest, opts ...grpc.CallOption) (SchemaServerExt_GetSchemaSubtreeClient, error)
+	// FindSchema returns the schema nodes with a name, description or type matching a pattern.
+	FindSchema(ctx context.Context, in *FindSchemaRequest, opts ...grpc.CallOption) (*FindSchemaResponse, error)
+	// GetKeyConstraints returns the types, patterns and ranges of the wildcarded keys of a path.
+	GetKeyConstraints(ctx context.Context, in *GetKeyConstraintsRequest, opts ...grpc.CallOption) (*GetKeyConstraintsResponse, error)
+	// RenderTree renders a schema or subtree as pyang -f tree style text, with config flags, keys and types.
+	RenderTree(ctx context.Context, in *RenderTreeRequest, opts ...grpc.CallOption) (*RenderTreeResponse, error)
+	// GenerateArtifact returns a JSON Schema or OpenAPI document describing the JSON encoding of a schema or subtree.
+	GenerateArtifact(ctx context.Context, in *GenerateArtifactRequest, opts ...grpc.CallOption) (*GenerateArtifactResponse, error)
+	// GetModuleYIN returns a module or a submodule of a schema as a YIN document
+	GetModuleYIN(ctx context.Context, in *GetModuleYINRequest, opts ...grpc.CallOption) (*GetModuleYINResponse, error)
+	// GetModuleSource streams the YANG source file of a module or a submodule of a schema
+	GetModuleSource(ctx context.Context, in *GetModuleSourceRequest, opts ...grpc.CallOption) (SchemaServerExt_GetModuleSourceClient, error)
+	// WatchInvalidations streams the subtrees of a schema changed since a fingerprint, then at each reload
+	WatchInvalidations(ctx context.Context, in *WatchInvalidationsRequest, opts ...grpc.CallOption) (SchemaServerExt_WatchInvalidationsClient, error)
+	// GetYangLibrary returns the RFC 8525 YANG library (modules-state) document of a schema.
+	GetYangLibrary(ctx context.Context, in *GetYangLibraryRequest, opts ...grpc.CallOption) (*GetYangLibraryResponse, error)
+	// ValidateDocument validates a JSON or XML instance document against a schema: its structure, types, keys, mandatory nodes and the leafrefs within it.
+	ValidateDocument(ctx context.Context, in *ValidateDocumentRequest, opts ...grpc.CallOption) (*ValidateDocumentResponse, error)
+	// ConvertDocument converts an instance document between the RFC 7951 JSON, RFC 7950 XML and typed update encodings.
+	ConvertDocument(ctx context.Context, in *ConvertDocumentRequest, opts ...grpc.CallOption) (*ConvertDocumentResponse, error)
+	// TranslatePath translates a gNMI path to a schema path or back, validating it against the schema.
+	TranslatePath(ctx context.Context, in *TranslatePathRequest, opts ...grpc.CallOption) (*TranslatePathResponse, error)
+	// GetSchemaStats returns the node counts, the max depth, the parse duration and a memory estimate of a schema, or of all the loaded ones, and their total.
+	GetSchemaStats(ctx context.Context, in *GetSchemaStatsRequest, opts ...grpc.CallOption) (*GetSchemaStatsResponse, error)
+	// Hello exchanges the API versions and capabilities of the client and the server, for the client to detect the features it can use.
+	Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error)
+	// ListAnnotations returns the metadata annotations (RFC 7952) defined by the modules of a schema.
+	ListAnnotations(ctx context.Context, in *ListAnnotationsRequest, opts ...grpc.CallOption) (*ListAnnotationsResponse, error)
+	// PatchDocument applies an RFC 6902 JSON Patch to an instance document, checking each operation against the schema, and validates the patched document.
+	PatchDocument(ctx context.Context, in *PatchDocumentRequest, opts ...grpc.CallOption) (*PatchDocumentResponse, error)
+}
+
+type schemaServerExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchemaServerExtClient(cc grpc.ClientConnInterface) SchemaServerExtClient {
+	return &schemaServerExtClient{cc}
+}
+
+func (c *schemaServerExtClient) GetServerInfo(ctx context.Context, in *GetServerInfoRequest, opts ...grpc.CallOption) (*GetServerInfoResponse, error) {
+	out := new(GetServerInfoResponse)
+	err := c.invoke(ctx, "GetServerInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) PrefetchSchema(ctx context.Context, in *PrefetchSchemaRequest, opts ...grpc.CallOption) (*PrefetchSchemaResponse, error) {
+	out := new(PrefetchSchemaResponse)
+	err := c.invoke(ctx, "PrefetchSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) EncodeJSON(ctx context.Context, in *EncodeJSONRequest, opts ...grpc.CallOption) (*EncodeJSONResponse, error) {
+	out := new(EncodeJSONResponse)
+	err := c.invoke(ctx, "EncodeJSON", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) CanonicalPath(ctx context.Context, in *CanonicalPathRequest, opts ...grpc.CallOption) (*CanonicalPathResponse, error) {
+	out := new(CanonicalPathResponse)
+	err := c.invoke(ctx, "CanonicalPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) DiffDocument(ctx context.Context, in *DiffDocumentRequest, opts ...grpc.CallOption) (*DiffDocumentResponse, error) {
+	out := new(DiffDocumentResponse)
+	err := c.invoke(ctx, "DiffDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) NormalizeDocument(ctx context.Context, in *NormalizeDocumentRequest, opts ...grpc.CallOption) (*NormalizeDocumentResponse, error) {
+	out := new(NormalizeDocumentResponse)
+	err := c.invoke(ctx, "NormalizeDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) BuildDocument(ctx context.Context, in *BuildDocumentRequest, opts ...grpc.CallOption) (*BuildDocumentResponse, error) {
+	out := new(BuildDocumentResponse)
+	err := c.invoke(ctx, "BuildDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) FlattenDocument(ctx context.Context, in *FlattenDocumentRequest, opts ...grpc.CallOption) (*FlattenDocumentResponse, error) {
+	out := new(FlattenDocumentResponse)
+	err := c.invoke(ctx, "FlattenDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetPathTemplate(ctx context.Context, in *GetPathTemplateRequest, opts ...grpc.CallOption) (*GetPathTemplateResponse, error) {
+	out := new(GetPathTemplateResponse)
+	err := c.invoke(ctx, "GetPathTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetSchemaMetadata(ctx context.Context, in *GetSchemaMetadataRequest, opts ...grpc.CallOption) (*GetSchemaMetadataResponse, error) {
+	out := new(GetSchemaMetadataResponse)
+	err := c.invoke(ctx, "GetSchemaMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetRequestJournal(ctx context.Context, in *GetRequestJournalRequest, opts ...grpc.CallOption) (*GetRequestJournalResponse, error) {
+	out := new(GetRequestJournalResponse)
+	err := c.invoke(ctx, "GetRequestJournal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) AnonymizeDocument(ctx context.Context, in *AnonymizeDocumentRequest, opts ...grpc.CallOption) (*AnonymizeDocumentResponse, error) {
+	out := new(AnonymizeDocumentResponse)
+	err := c.invoke(ctx, "AnonymizeDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetInstanceTemplate(ctx context.Context, in *GetInstanceTemplateRequest, opts ...grpc.CallOption) (*GetInstanceTemplateResponse, error) {
+	out := new(GetInstanceTemplateResponse)
+	err := c.invoke(ctx, "GetInstanceTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetSchemaComposition(ctx context.Context, in *GetSchemaCompositionRequest, opts ...grpc.CallOption) (*GetSchemaCompositionResponse, error) {
+	out := new(GetSchemaCompositionResponse)
+	err := c.invoke(ctx, "GetSchemaComposition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ExpandPathStream(ctx context.Context, in *sdcpb.ExpandPathRequest, opts ...grpc.CallOption) (SchemaServerExt_ExpandPathStreamClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[0], FullMethod("ExpandPathStream"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtExpandPathStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_ExpandPathStreamClient interface {
+	Recv() (*sdcpb.ExpandPathResponse, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtExpandPathStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtExpandPathStreamClient) Recv() (*sdcpb.ExpandPathResponse, error) {
+	m := new(sdcpb.ExpandPathResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) WatchSchemaChanges(ctx context.Context, in *WatchSchemaChangesRequest, opts ...grpc.CallOption) (SchemaServerExt_WatchSchemaChangesClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[1], FullMethod("WatchSchemaChanges"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtWatchSchemaChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_WatchSchemaChangesClient interface {
+	Recv() (*SchemaEvent, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtWatchSchemaChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtWatchSchemaChangesClient) Recv() (*SchemaEvent, error) {
+	m := new(SchemaEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) DiffSchema(ctx context.Context, in *DiffSchemaRequest, opts ...grpc.CallOption) (SchemaServerExt_DiffSchemaClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[2], FullMethod("DiffSchema"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtDiffSchemaClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_DiffSchemaClient interface {
+	Recv() (*DiffSchemaResponse, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtDiffSchemaClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtDiffSchemaClient) Recv() (*DiffSchemaResponse, error) {
+	m := new(DiffSchemaResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) ParseModules(ctx context.Context, opts ...grpc.CallOption) (SchemaServerExt_ParseModulesClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[3], FullMethod("ParseModules"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &schemaServerExtParseModulesClient{stream}, nil
+}
+
+type SchemaServerExt_ParseModulesClient interface {
+	Send(*ParseModulesRequest) error
+	CloseAndRecv() (*ParseModulesResponse, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtParseModulesClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtParseModulesClient) Send(m *ParseModulesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *schemaServerExtParseModulesClient) CloseAndRecv() (*ParseModulesResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ParseModulesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) ExportSchema(ctx context.Context, in *ExportSchemaRequest, opts ...grpc.CallOption) (SchemaServerExt_ExportSchemaClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[4], FullMethod("ExportSchema"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtExportSchemaClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_ExportSchemaClient interface {
+	Recv() (*ExportSchemaResponse, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtExportSchemaClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtExportSchemaClient) Recv() (*ExportSchemaResponse, error) {
+	m := new(ExportSchemaResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) QuerySchema(ctx context.Context, in *QuerySchemaRequest, opts ...grpc.CallOption) (*QuerySchemaResponse, error) {
+	out := new(QuerySchemaResponse)
+	err := c.invoke(ctx, "QuerySchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetSchemaFingerprint(ctx context.Context, in *GetSchemaFingerprintRequest, opts ...grpc.CallOption) (*GetSchemaFingerprintResponse, error) {
+	out := new(GetSchemaFingerprintResponse)
+	err := c.invoke(ctx, "GetSchemaFingerprint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ExportSchemaChanges(ctx context.Context, in *ExportSchemaChangesRequest, opts ...grpc.CallOption) (*ExportSchemaChangesResponse, error) {
+	out := new(ExportSchemaChangesResponse)
+	err := c.invoke(ctx, "ExportSchemaChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.invoke(ctx, "ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) SetSchemaState(ctx context.Context, in *SetSchemaStateRequest, opts ...grpc.CallOption) (*SetSchemaStateResponse, error) {
+	out := new(SetSchemaStateResponse)
+	err := c.invoke(ctx, "SetSchemaState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListSchemaStates(ctx context.Context, in *ListSchemaStatesRequest, opts ...grpc.CallOption) (*ListSchemaStatesResponse, error) {
+	out := new(ListSchemaStatesResponse)
+	err := c.invoke(ctx, "ListSchemaStates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) SetSchemaCanary(ctx context.Context, in *SetSchemaCanaryRequest, opts ...grpc.CallOption) (*SetSchemaCanaryResponse, error) {
+	out := new(SetSchemaCanaryResponse)
+	err := c.invoke(ctx, "SetSchemaCanary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListSchemaCanaries(ctx context.Context, in *ListSchemaCanariesRequest, opts ...grpc.CallOption) (*ListSchemaCanariesResponse, error) {
+	out := new(ListSchemaCanariesResponse)
+	err := c.invoke(ctx, "ListSchemaCanaries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ExpandWildcardPath(ctx context.Context, in *ExpandWildcardPathRequest, opts ...grpc.CallOption) (*ExpandWildcardPathResponse, error) {
+	out := new(ExpandWildcardPathResponse)
+	err := c.invoke(ctx, "ExpandWildcardPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetSchemaLint(ctx context.Context, in *GetSchemaLintRequest, opts ...grpc.CallOption) (*GetSchemaLintResponse, error) {
+	out := new(GetSchemaLintResponse)
+	err := c.invoke(ctx, "GetSchemaLint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListLintRules(ctx context.Context, in *ListLintRulesRequest, opts ...grpc.CallOption) (*ListLintRulesResponse, error) {
+	out := new(ListLintRulesResponse)
+	err := c.invoke(ctx, "ListLintRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) CompletePath(ctx context.Context, in *CompletePathRequest, opts ...grpc.CallOption) (*CompletePathResponse, error) {
+	out := new(CompletePathResponse)
+	err := c.invoke(ctx, "CompletePath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ResolveLeafref(ctx context.Context, in *ResolveLeafrefRequest, opts ...grpc.CallOption) (*ResolveLeafrefResponse, error) {
+	out := new(ResolveLeafrefResponse)
+	err := c.invoke(ctx, "ResolveLeafref", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ValidateExpression(ctx context.Context, in *ValidateExpressionRequest, opts ...grpc.CallOption) (*ValidateExpressionResponse, error) {
+	out := new(ValidateExpressionResponse)
+	err := c.invoke(ctx, "ValidateExpression", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ValidateValue(ctx context.Context, in *ValidateValueRequest, opts ...grpc.CallOption) (*ValidateValueResponse, error) {
+	out := new(ValidateValueResponse)
+	err := c.invoke(ctx, "ValidateValue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListSubmodules(ctx context.Context, in *ListSubmodulesRequest, opts ...grpc.CallOption) (*ListSubmodulesResponse, error) {
+	out := new(ListSubmodulesResponse)
+	err := c.invoke(ctx, "ListSubmodules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetDefaults(ctx context.Context, in *GetDefaultsRequest, opts ...grpc.CallOption) (*GetDefaultsResponse, error) {
+	out := new(GetDefaultsResponse)
+	err := c.invoke(ctx, "GetDefaults", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) PinSchema(ctx context.Context, in *PinSchemaRequest, opts ...grpc.CallOption) (*PinSchemaResponse, error) {
+	out := new(PinSchemaResponse)
+	err := c.invoke(ctx, "PinSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListSchemaPins(ctx context.Context, in *ListSchemaPinsRequest, opts ...grpc.CallOption) (*ListSchemaPinsResponse, error) {
+	out := new(ListSchemaPinsResponse)
+	err := c.invoke(ctx, "ListSchemaPins", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListIdentities(ctx context.Context, in *ListIdentitiesRequest, opts ...grpc.CallOption) (*ListIdentitiesResponse, error) {
+	out := new(ListIdentitiesResponse)
+	err := c.invoke(ctx, "ListIdentities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListSchemaElements(ctx context.Context, in *ListSchemaElementsRequest, opts ...grpc.CallOption) (*ListSchemaElementsResponse, error) {
+	out := new(ListSchemaElementsResponse)
+	err := c.invoke(ctx, "ListSchemaElements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetSchemaSubtree(ctx context.Context, in *GetSchemaSubtreeRequest, opts ...grpc.CallOption) (SchemaServerExt_GetSchemaSubtreeClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[5], FullMethod("GetSchemaSubtree"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtGetSchemaSubtreeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_GetSchemaSubtreeClient interface {
+	Recv() (*GetSchemaSubtreeResponse, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtGetSchemaSubtreeClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtGetSchemaSubtreeClient) Recv() (*GetSchemaSubtreeResponse, error) {
+	m := new(GetSchemaSubtreeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) FindSchema(ctx context.Context, in *FindSchemaRequest, opts ...grpc.CallOption) (*FindSchemaResponse, error) {
+	out := new(FindSchemaResponse)
+	err := c.invoke(ctx, "FindSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetKeyConstraints(ctx context.Context, in *GetKeyConstraintsRequest, opts ...grpc.CallOption) (*GetKeyConstraintsResponse, error) {
+	out := new(GetKeyConstraintsResponse)
+	err := c.invoke(ctx, "GetKeyConstraints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) RenderTree(ctx context.Context, in *RenderTreeRequest, opts ...grpc.CallOption) (*RenderTreeResponse, error) {
+	out := new(RenderTreeResponse)
+	err := c.invoke(ctx, "RenderTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GenerateArtifact(ctx context.Context, in *GenerateArtifactRequest, opts ...grpc.CallOption) (*GenerateArtifactResponse, error) {
+	out := new(GenerateArtifactResponse)
+	err := c.invoke(ctx, "GenerateArtifact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetModuleYIN(ctx context.Context, in *GetModuleYINRequest, opts ...grpc.CallOption) (*GetModuleYINResponse, error) {
+	out := new(GetModuleYINResponse)
+	err := c.invoke(ctx, "GetModuleYIN", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetModuleSource(ctx context.Context, in *GetModuleSourceRequest, opts ...grpc.CallOption) (SchemaServerExt_GetModuleSourceClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[6], FullMethod("GetModuleSource"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtGetModuleSourceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_GetModuleSourceClient interface {
+	Recv() (*GetModuleSourceResponse, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtGetModuleSourceClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtGetModuleSourceClient) Recv() (*GetModuleSourceResponse, error) {
+	m := new(GetModuleSourceResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) WatchInvalidations(ctx context.Context, in *WatchInvalidationsRequest, opts ...grpc.CallOption) (SchemaServerExt_WatchInvalidationsClient, error) {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	stream, err := c.cc.NewStream(ctx, &SchemaServerExt_ServiceDesc.Streams[7], FullMethod("WatchInvalidations"), opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schemaServerExtWatchInvalidationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SchemaServerExt_WatchInvalidationsClient interface {
+	Recv() (*Invalidation, error)
+	grpc.ClientStream
+}
+
+type schemaServerExtWatchInvalidationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *schemaServerExtWatchInvalidationsClient) Recv() (*Invalidation, error) {
+	m := new(Invalidation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *schemaServerExtClient) GetYangLibrary(ctx context.Context, in *GetYangLibraryRequest, opts ...grpc.CallOption) (*GetYangLibraryResponse, error) {
+	out := new(GetYangLibraryResponse)
+	err := c.invoke(ctx, "GetYangLibrary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ValidateDocument(ctx context.Context, in *ValidateDocumentRequest, opts ...grpc.CallOption) (*ValidateDocumentResponse, error) {
+	out := new(ValidateDocumentResponse)
+	err := c.invoke(ctx, "ValidateDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ConvertDocument(ctx context.Context, in *ConvertDocumentRequest, opts ...grpc.CallOption) (*ConvertDocumentResponse, error) {
+	out := new(ConvertDocumentResponse)
+	err := c.invoke(ctx, "ConvertDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) TranslatePath(ctx context.Context, in *TranslatePathRequest, opts ...grpc.CallOption) (*TranslatePathResponse, error) {
+	out := new(TranslatePathResponse)
+	err := c.invoke(ctx, "TranslatePath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) GetSchemaStats(ctx context.Context, in *GetSchemaStatsRequest, opts ...grpc.CallOption) (*GetSchemaStatsResponse, error) {
+	out := new(GetSchemaStatsResponse)
+	err := c.invoke(ctx, "GetSchemaStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloResponse, error) {
+	out := new(HelloResponse)
+	err := c.invoke(ctx, "Hello", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) ListAnnotations(ctx context.Context, in *ListAnnotationsRequest, opts ...grpc.CallOption) (*ListAnnotationsResponse, error) {
+	out := new(ListAnnotationsResponse)
+	err := c.invoke(ctx, "ListAnnotations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) PatchDocument(ctx context.Context, in *PatchDocumentRequest, opts ...grpc.CallOption) (*PatchDocumentResponse, error) {
+	out := new(PatchDocumentResponse)
+	err := c.invoke(ctx, "PatchDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schemaServerExtClient) invoke(ctx context.Context, method string, in, out interface{}, opts ...grpc.CallOption) error {
+	opts = append(opts, grpc.CallContentSubtype(CodecName))
+	return c.cc.Invoke(ctx, FullMethod(method), in, out, opts...)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type CompletePathRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// xpath being typed, e.g. /interface[name=ethernet-1/1]/sub
+	// completes the children of the interface list named sub...,
+	// /interface[ its key names and /interface[name= its key values.
+	Path string `json:"path,omitempty"`
+	// only complete the config or the state nodes
+	DataType sdcpb.DataType `json:"data-type,omitempty"`
+}
+
+type CompletePathResponse struct {
+	Candidates []*PathCandidate `json:"candidates,omitempty"`
+}
+
+const (
+	CandidateContainer = "container"
+	CandidateList      = "list"
+	CandidateLeaf      = "leaf"
+	CandidateLeafList  = "leaf-list"
+	// a list key, as a child or as a [name= completion
+	CandidateKey = "key"
+	// a value of an enumeration or boolean list key
+	CandidateValue = "value"
+)
+
+type PathCandidate struct {
+	Name string `json:"name,omitempty"`
+	// see the Candidate constants
+	Kind string `json:"kind,omitempty"`
+	// the request path completed with the candidate
+	Completion string `json:"completion,omitempty"`
+	// key names of a list, in their declared order
+	Keys []string `json:"keys,omitempty"`
+	// module defining a top level node
+	Module string `json:"module,omitempty"`
+	// type of a leaf, leaf-list or key
+	Type    string `json:"type,omitempty"`
+	IsState bool   `json:"is-state,omitempty"`
+}
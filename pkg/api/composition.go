@@ -0,0 +1,48 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetSchemaCompositionRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+}
+
+type GetSchemaCompositionResponse struct {
+	UnresolvedImports []*UnresolvedImport `json:"unresolved-imports,omitempty"`
+	AugmentConflicts  []*AugmentConflict  `json:"augment-conflicts,omitempty"`
+	DuplicateNodes    []*DuplicateNode    `json:"duplicate-nodes,omitempty"`
+}
+
+type UnresolvedImport struct {
+	Module string `json:"module,omitempty"`
+	Import string `json:"import,omitempty"`
+}
+
+// AugmentConflict is a node added to an augment target by several modules.
+type AugmentConflict struct {
+	Target string `json:"target,omitempty"`
+	Node   string `json:"node,omitempty"`
+	// the module of the node kept in the schema comes first
+	Modules []string `json:"modules,omitempty"`
+}
+
+// DuplicateNode is a top level node defined by several modules.
+type DuplicateNode struct {
+	Path    string   `json:"path,omitempty"`
+	Modules []string `json:"modules,omitempty"`
+}
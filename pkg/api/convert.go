@@ -0,0 +1,47 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// DocumentFormatProto is a document as the typed value updates of its leaves.
+const DocumentFormatProto = "proto"
+
+type ConvertDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// format of the input document, one of "json" (RFC 7951, default),
+	// "xml" (RFC 7950, a top level node or a NETCONF config or data
+	// element wrapping them) or "proto" (updates)
+	From string `json:"from,omitempty"`
+	// format of the output document, "json" by default
+	To string `json:"to,omitempty"`
+	// json or xml input document, rooted at the schema root
+	Document []byte `json:"document,omitempty"`
+	// proto input document, applied in order as in BuildDocument
+	Updates []*Update `json:"updates,omitempty"`
+}
+
+// ConvertDocumentResponse holds the document in the requested format:
+// the field of the other formats is empty.
+type ConvertDocumentResponse struct {
+	Document json.RawMessage `json:"document,omitempty"`
+	XML      string          `json:"xml,omitempty"`
+	// schema validated updates in the document canonical order
+	Updates []*Update `json:"updates,omitempty"`
+}
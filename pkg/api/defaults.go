@@ -0,0 +1,48 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetDefaultsRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path of a container or a list, the schema root if empty.
+	// Its list keys are kept in the returned paths.
+	Path *sdcpb.Path `json:"path,omitempty"`
+}
+
+type GetDefaultsResponse struct {
+	// the leaves and leaf-lists with a default below the path, sorted by path
+	Defaults []*LeafDefault `json:"defaults,omitempty"`
+}
+
+type LeafDefault struct {
+	// path of the leaf or leaf-list and its default rendered in its type,
+	// the defaults of a leaf-list are set as a leaflist value.
+	Update *Update `json:"update,omitempty"`
+	// default value(s) as stated in the schema, by the leaf or its type
+	Default []string `json:"default,omitempty"`
+	// name of the leaf type
+	Type string `json:"type,omitempty"`
+	// where the default is stated: leaf, typedef or deviation,
+	// telling a schema default from a deviated one for report-all-tagged.
+	Origin string `json:"origin,omitempty"`
+	// module qualified name of the typedef stating the default, for a typedef origin
+	Typedef string `json:"typedef,omitempty"`
+	// module of the deviation stating the default, for a deviation origin
+	Module string `json:"module,omitempty"`
+}
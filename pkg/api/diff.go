@@ -0,0 +1,42 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type DiffDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// RFC 7951 JSON config documents rooted at the schema root
+	From json.RawMessage `json:"from,omitempty"`
+	To   json.RawMessage `json:"to,omitempty"`
+}
+
+type DiffDocumentResponse struct {
+	Changes []*DocumentChange `json:"changes,omitempty"`
+}
+
+type DocumentChange struct {
+	// one of "add", "delete", "update" or "reorder"
+	Op    string      `json:"op,omitempty"`
+	Path  *sdcpb.Path `json:"path,omitempty"`
+	XPath string      `json:"xpath,omitempty"`
+	// JSON encoded values before and after the change
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ListSchemaElementsRequest struct {
+	Schema          *sdcpb.Schema `json:"schema,omitempty"`
+	Path            *sdcpb.Path   `json:"path,omitempty"`
+	WithDescription bool          `json:"with-description,omitempty"`
+	// fields of the container, leaf or leaf-list schema kept in each element,
+	// all if empty. They are the proto field names, a dotted path selects the
+	// fields of a nested message, e.g. "name", "fields.name", "type.type".
+	// The kind of an element is always returned.
+	FieldMask []string `json:"field-mask,omitempty"`
+	// max number of elements returned, the server default if zero
+	PageSize int `json:"page-size,omitempty"`
+	// next page token of the previous response, empty for the first page
+	PageToken string `json:"page-token,omitempty"`
+}
+
+type ListSchemaElementsResponse struct {
+	// schemas of the path elements, from the root
+	Elements []*SchemaElem `json:"elements,omitempty"`
+	// empty on the last page
+	NextPageToken string `json:"next-page-token,omitempty"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// reasons of the ErrorInfo details of the errors returned by the server,
+// the ErrorInfo metadata keys are listed along with each reason.
+const (
+	// "name", "vendor", "version"
+	ReasonSchemaStaged = "SCHEMA_STAGED"
+	// "name", "vendor", "version"
+	ReasonSchemaArchived = "SCHEMA_ARCHIVED"
+	// "owner", "limit"
+	ReasonUploadQuotaExceeded = "UPLOAD_QUOTA_EXCEEDED"
+	// "path", "limit"
+	ReasonSubtreeTooLarge = "SUBTREE_TOO_LARGE"
+	// "limit": a streamed response exceeds the size it can be spilled to disk with
+	ReasonResponseTooLarge = "RESPONSE_TOO_LARGE"
+	// "principal", "method"
+	ReasonClientNotAllowed = "CLIENT_NOT_ALLOWED"
+	// "tenant", "name", "vendor", "version"
+	ReasonSchemaNotAllowed = "SCHEMA_NOT_ALLOWED"
+	// "tenant", "method": an RPC not referring to a schema called by a tenant that is not an admin
+	ReasonTenantNotAllowed = "TENANT_NOT_ALLOWED"
+	// "name", "vendor", "owner", "address": the schema is served by another member of a sharded deployment
+	ReasonSchemaNotOwned = "SCHEMA_NOT_OWNED"
+	// "path": a value of a document does not match the type of its leaf
+	ReasonInvalidValue = "INVALID_VALUE"
+)
@@ -0,0 +1,41 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ExpandWildcardPathRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path element names can be "*", any single element,
+	// or "...", any number of elements.
+	// List keys without a value or set to "*" are returned as "*".
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// return xpaths rather than paths
+	Xpath    bool           `json:"xpath,omitempty"`
+	DataType sdcpb.DataType `json:"data-type,omitempty"`
+	// max number of paths returned, the server default if zero
+	PageSize int `json:"page-size,omitempty"`
+	// next page token of the previous response, empty for the first page
+	PageToken string `json:"page-token,omitempty"`
+}
+
+type ExpandWildcardPathResponse struct {
+	Path  []*sdcpb.Path `json:"path,omitempty"`
+	Xpath []string      `json:"xpath,omitempty"`
+	// empty on the last page
+	NextPageToken string `json:"next-page-token,omitempty"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// ExportFormatVersion is the version of the ExportSchema text format,
+// written on its first line. It changes when the format does.
+const ExportFormatVersion = 1
+
+type ExportSchemaRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// root of the exported subtree, the whole schema if not set
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// export the descriptions, left out by default as they change often
+	WithDescriptions bool `json:"with-descriptions,omitempty"`
+}
+
+// ExportSchemaResponse carries the next part of the export text,
+// the parts are concatenated in the order they are received.
+type ExportSchemaResponse struct {
+	Text string `json:"text,omitempty"`
+	// sha256 of the whole text, set on the last response
+	Digest string `json:"digest,omitempty"`
+}
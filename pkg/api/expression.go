@@ -0,0 +1,47 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ValidateExpressionRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path of the context node, the root if not set.
+	// For a when statement of a choice, case, augment or uses,
+	// this is the parent data node.
+	Path       *sdcpb.Path `json:"path,omitempty"`
+	Expression string      `json:"expression,omitempty"`
+}
+
+type ValidateExpressionResponse struct {
+	// the expression has no issues
+	Valid  bool               `json:"valid,omitempty"`
+	Issues []*ExpressionIssue `json:"issues,omitempty"`
+	// data paths of the schema nodes the expression refers to
+	Paths []string `json:"paths,omitempty"`
+	// functions called by the expression
+	Functions []string `json:"functions,omitempty"`
+}
+
+type ExpressionIssue struct {
+	// syntax, unknown-node, unknown-prefix, unknown-function,
+	// arguments, unknown-variable or type
+	Kind string `json:"kind,omitempty"`
+	// byte offset of the issue in the expression
+	Offset  int    `json:"offset"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type FindSchemaRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// substring searched for, a regular expression if Regex is set
+	Pattern    string `json:"pattern,omitempty"`
+	Regex      bool   `json:"regex,omitempty"`
+	IgnoreCase bool   `json:"ignore-case,omitempty"`
+	// fields searched: name, description and type, all of them if not set
+	Fields []string `json:"fields,omitempty"`
+	// kinds of the nodes returned: container, list, leaf or leaf-list, all of them if not set
+	Kinds []string `json:"kinds,omitempty"`
+	// root of the searched subtree, the whole schema if not set
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// max number of matches, 0 means no limit
+	Limit int `json:"limit,omitempty"`
+}
+
+type FindSchemaResponse struct {
+	Matches []*FindMatch `json:"matches,omitempty"`
+	// more nodes than the limit match
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type FindMatch struct {
+	// xpath with wildcard list keys
+	Path string `json:"path,omitempty"`
+	// container, list, leaf or leaf-list
+	Kind   string `json:"kind,omitempty"`
+	Module string `json:"module,omitempty"`
+	// type of a leaf or leaf-list
+	Type string `json:"type,omitempty"`
+	// fields matching the pattern
+	Fields []string `json:"fields,omitempty"`
+}
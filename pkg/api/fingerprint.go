@@ -0,0 +1,57 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+const (
+	SchemaChangeAdd    = "add"
+	SchemaChangeDelete = "delete"
+	SchemaChangeUpdate = "update"
+)
+
+type GetSchemaFingerprintRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+}
+
+type GetSchemaFingerprintResponse struct {
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+type ExportSchemaChangesRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// fingerprint the changes are computed from,
+	// as returned by GetSchemaFingerprint.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+type ExportSchemaChangesResponse struct {
+	From string `json:"from,omitempty"`
+	// current fingerprint of the schema
+	To      string          `json:"to,omitempty"`
+	Changes []*SchemaChange `json:"changes,omitempty"`
+}
+
+// SchemaChange is a container or list changed between two fingerprints.
+// An added subtree is reported node by node, a deleted one as a whole.
+type SchemaChange struct {
+	Op string `json:"op,omitempty"`
+	// element names, starting with the module name
+	Path string `json:"path,omitempty"`
+	// the schema element after the change, its leaves included
+	Schema *SchemaElem `json:"schema,omitempty"`
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type TranslatePathRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// gNMI path to translate to a schema path, appended to Prefix.
+	// Exclusive with Path.
+	GNMIPath *GNMIPath `json:"gnmi-path,omitempty"`
+	Prefix   *GNMIPath `json:"prefix,omitempty"`
+	// schema path to translate to a gNMI path
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// module qualification of the gNMI path element names:
+	// "", "module" or "rfc7951"
+	Qualification string `json:"qualification,omitempty"`
+}
+
+type TranslatePathResponse struct {
+	// schema path without module qualifiers, set when translating a gNMI path
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// xpath of the schema path with the keys in the schema declared order
+	XPath string `json:"xpath,omitempty"`
+	// set when translating a schema path
+	GNMIPath *GNMIPath `json:"gnmi-path,omitempty"`
+}
+
+// GNMIPath wraps a gnmi.Path so that it is encoded using protojson.
+type GNMIPath struct {
+	*gnmipb.Path
+}
+
+func (p GNMIPath) MarshalJSON() ([]byte, error) {
+	if p.Path == nil {
+		return []byte("null"), nil
+	}
+	return protojson.Marshal(p.Path)
+}
+
+func (p *GNMIPath) UnmarshalJSON(b []byte) error {
+	p.Path = new(gnmipb.Path)
+	return protojson.Unmarshal(b, p.Path)
+}
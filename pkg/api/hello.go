@@ -0,0 +1,114 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// version of the SchemaServerExt API, increased on incompatible changes
+	APIVersion = 1
+	// oldest API version the server answers
+	MinAPIVersion = 1
+)
+
+// names of the capabilities exchanged in Hello
+const (
+	// page tokens, the values are the RPCs returning pages
+	CapabilityPagination = "pagination"
+	// field masks, the values are the RPCs applying them
+	CapabilityFieldMask = "field-mask"
+	// message compression, the values are the compressor names
+	CapabilityCompression = "compression"
+	// answering from another loaded version of a schema, the values
+	// are the accepted values of the x-schema-version-fallback header
+	CapabilityVersionFallback = "version-fallback"
+)
+
+type HelloRequest struct {
+	// name and version of the client, e.g. schemac/v0.0.1
+	Client string `json:"client,omitempty"`
+	// API version the client is built against
+	APIVersion int `json:"api-version,omitempty"`
+	// names of the capabilities the client can use
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+type HelloResponse struct {
+	// API version used by the server with this client:
+	// the lower of the client and the server ones
+	APIVersion    int `json:"api-version,omitempty"`
+	MinAPIVersion int `json:"min-api-version,omitempty"`
+	// capabilities supported by the server, sorted by name
+	Capabilities []*Capability `json:"capabilities,omitempty"`
+	// client capabilities the server supports too
+	Negotiated []string `json:"negotiated,omitempty"`
+}
+
+type Capability struct {
+	Name   string   `json:"name,omitempty"`
+	Values []string `json:"values,omitempty"`
+}
+
+// Capability returns the server capability named name, nil if not supported.
+func (r *HelloResponse) Capability(name string) *Capability {
+	if r == nil {
+		return nil
+	}
+	for _, c := range r.Capabilities {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Supports reports whether the server supports the capability name,
+// with value among its values if value is not empty.
+func (r *HelloResponse) Supports(name, value string) bool {
+	c := r.Capability(name)
+	if c == nil {
+		return false
+	}
+	if value == "" {
+		return true
+	}
+	for _, v := range c.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate sends a Hello with the capabilities of the client to the server.
+// A server older than the Hello RPC answers it with an empty response,
+// API version 0 and no capabilities, instead of an Unimplemented error.
+func Negotiate(ctx context.Context, c SchemaServerExtClient, client string, capabilities []string, opts ...grpc.CallOption) (*HelloResponse, error) {
+	rsp, err := c.Hello(ctx, &HelloRequest{
+		Client:       client,
+		APIVersion:   APIVersion,
+		Capabilities: capabilities,
+	}, opts...)
+	if status.Code(err) == codes.Unimplemented {
+		return &HelloResponse{}, nil
+	}
+	return rsp, err
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ListIdentitiesRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// base identity as "module:name", "prefix:name" or "name",
+	// all the identities of the schema are listed if empty.
+	Base string `json:"base,omitempty"`
+}
+
+type ListIdentitiesResponse struct {
+	// identities derived from the base, directly or not,
+	// sorted by module qualified name.
+	Identities []*Identity `json:"identities,omitempty"`
+}
+
+type Identity struct {
+	Name string `json:"name,omitempty"`
+	// module defining the identity, the one a submodule belongs to
+	Module string `json:"module,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// module qualified name, as in an RFC 7951 JSON value
+	Qualified string `json:"qualified,omitempty"`
+	// prefixed name, as in an XML value
+	Prefixed string `json:"prefixed,omitempty"`
+	// module qualified names of the base identities
+	Bases       []string `json:"bases,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
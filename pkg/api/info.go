@@ -0,0 +1,33 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+type GetServerInfoRequest struct{}
+
+type GetServerInfoResponse struct {
+	Version   string `json:"version,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build-date,omitempty"`
+	GoVersion string `json:"go-version,omitempty"`
+	// names of the optional server features that are enabled
+	Features []string `json:"features,omitempty"`
+	Limits   *Limits  `json:"limits,omitempty"`
+}
+
+type Limits struct {
+	MaxRecvMsgSize int `json:"max-recv-msg-size,omitempty"`
+	// RPC timeout in milliseconds
+	RPCTimeout int64 `json:"rpc-timeout,omitempty"`
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type WatchInvalidationsRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// fingerprint of the content cached by the client, as returned by GetSchemaFingerprint
+	// or the last Invalidation received. The first invalidation reports the changes since
+	// then, or only the current fingerprint if not set.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Invalidation tells a schema cache which of its entries are stale: the nodes of a
+// schema whose content changed between two fingerprints. One is sent when the stream
+// starts, then one each time the schema is reloaded, added or removed.
+// A client reconnecting sends the To fingerprint of the last one it received.
+type Invalidation struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// fingerprint the changes are computed from, empty if not known
+	From string `json:"from,omitempty"`
+	// current fingerprint of the schema, empty if it was removed
+	To string `json:"to,omitempty"`
+	// the whole cached content is stale: the From fingerprint is not known
+	// any longer or the schema was removed
+	Full bool `json:"full,omitempty"`
+	// changed nodes sorted by path, empty if the content did not change
+	Stale []*StaleNode `json:"stale,omitempty"`
+	Time  time.Time    `json:"time,omitempty"`
+}
+
+// StaleNode is a node whose cached schema element is stale. The elements
+// of its descendants are not, unless listed or Subtree is set. The cached
+// responses covering its subtree, e.g. GetSchemaSubtree, are stale, as are
+// the ones covering the subtrees of its ancestors.
+type StaleNode struct {
+	// element names, starting with the module name
+	Path string `json:"path,omitempty"`
+	// the whole subtree was added or removed
+	Subtree bool `json:"subtree,omitempty"`
+	// hex sha256 of the subtree content after the change, empty if it was removed.
+	// It changes when any node of the subtree does.
+	Checksum string `json:"checksum,omitempty"`
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetRequestJournalRequest struct {
+	// max number of entries returned, the most recent ones. All if not set.
+	Last int `json:"last,omitempty"`
+	// only return the entries of the methods ending with this suffix, e.g: GetSchema
+	Method string `json:"method,omitempty"`
+}
+
+type GetRequestJournalResponse struct {
+	// oldest first
+	Entries []*JournalEntry `json:"entries,omitempty"`
+}
+
+type JournalEntry struct {
+	Time   time.Time     `json:"time,omitempty"`
+	Method string        `json:"method,omitempty"`
+	Peer   string        `json:"peer,omitempty"`
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// xpath(s) of the request
+	Paths []string `json:"paths,omitempty"`
+	// request duration in microseconds
+	Latency int64  `json:"latency,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// x-request-id, x-tenant and traceparent request metadata
+	RequestID   string `json:"request-id,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
+	TraceParent string `json:"traceparent,omitempty"`
+	// subject of the authenticated bearer token, the tenant is then the one of the token
+	Subject string `json:"subject,omitempty"`
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetKeyConstraintsRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path with wildcarded list keys, e.g. /interface[name=*]/subinterface[index=*].
+	// a key not set is wildcarded.
+	Path *sdcpb.Path `json:"path,omitempty"`
+}
+
+type GetKeyConstraintsResponse struct {
+	// one per wildcarded key, in path order and in the declared order of the keys of a list
+	Keys []*KeyConstraints `json:"keys,omitempty"`
+}
+
+type KeyConstraints struct {
+	// index of the path element and name of the key
+	Elem int    `json:"elem"`
+	Key  string `json:"key,omitempty"`
+	// xpath of the list, with the keys of the request path
+	List string `json:"list,omitempty"`
+	// declared type of the key
+	Type *sdcpb.SchemaLeafType `json:"type,omitempty"`
+	// the types a key value can be of, one per member of a union:
+	// the nested unions are flattened and the leafrefs replaced by the type of their target.
+	Members []*TypeConstraints `json:"members,omitempty"`
+}
+
+type TypeConstraints struct {
+	// built-in type
+	Type string `json:"type,omitempty"`
+	// typedef name, if any
+	TypeName string                 `json:"type-name,omitempty"`
+	Range    string                 `json:"range,omitempty"`
+	Length   string                 `json:"length,omitempty"`
+	Patterns []*sdcpb.SchemaPattern `json:"patterns,omitempty"`
+	// enumeration values, identities of an identityref or bits
+	Values []string `json:"values,omitempty"`
+	// path statement of the leafref the type is the target of,
+	// a value must also be an instance of the target.
+	Leafref string `json:"leafref,omitempty"`
+	// xpath of the leafref target
+	Target string `json:"target,omitempty"`
+}
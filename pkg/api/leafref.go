@@ -0,0 +1,54 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ResolveLeafrefRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path of a leafref leaf or leaf-list, or of a union
+	// with leafref members, keys are ignored.
+	Path *sdcpb.Path `json:"path,omitempty"`
+}
+
+type ResolveLeafrefResponse struct {
+	// one target per leafref of the leaf type,
+	// in the order of the union members.
+	Targets []*LeafrefTarget `json:"targets,omitempty"`
+	// the leafref(s) of the leaf do not have to refer to an existing instance,
+	// require-instance false.
+	OptionalInstance bool `json:"optional-instance,omitempty"`
+}
+
+type LeafrefTarget struct {
+	// path statement of the leafref
+	Expression string `json:"expression,omitempty"`
+	// absolute path of the referenced leaf or leaf-list.
+	// The key values of its predicates are the absolute paths
+	// of the leaves they are taken from.
+	Path  *sdcpb.Path `json:"path,omitempty"`
+	Xpath string      `json:"xpath,omitempty"`
+	// paths of the leafrefs followed to reach the target
+	// when the referenced leaf is itself a leafref.
+	Chain []string `json:"chain,omitempty"`
+	// type of the target
+	Type    *sdcpb.SchemaLeafType `json:"type,omitempty"`
+	IsState bool                  `json:"is-state,omitempty"`
+	// issues found with the reference, e.g. a config leafref
+	// requiring an instance of a state leaf.
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetYangLibraryRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+}
+
+type GetYangLibraryResponse struct {
+	// the schema fingerprint, changing with the modules
+	ModuleSetID string `json:"module-set-id,omitempty"`
+	// the RFC 7951 encoding of the ietf-yang-library:modules-state container
+	// of RFC 8525: the modules with their revision, namespace, enabled features,
+	// deviation modules, submodules and conformance type.
+	Document json.RawMessage `json:"document,omitempty"`
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// schema lifecycle states, a schema is active unless set otherwise
+const (
+	// parsed but not served
+	SchemaStateStaged = "staged"
+	SchemaStateActive = "active"
+	// served with a deprecation notice
+	SchemaStateDeprecated = "deprecated"
+	// unloaded, it can be restored by setting another state
+	SchemaStateArchived = "archived"
+)
+
+type SetSchemaStateRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	State  string        `json:"state,omitempty"`
+	// deprecation notice returned with the responses of a deprecated schema
+	Message string `json:"message,omitempty"`
+}
+
+type SetSchemaStateResponse struct {
+	State *SchemaState `json:"state,omitempty"`
+}
+
+type ListSchemaStatesRequest struct{}
+
+// ListSchemaStatesResponse lists the schemas that are not active.
+type ListSchemaStatesResponse struct {
+	States []*SchemaState `json:"states,omitempty"`
+}
+
+type SchemaState struct {
+	Schema  *sdcpb.Schema `json:"schema,omitempty"`
+	State   string        `json:"state,omitempty"`
+	Message string        `json:"message,omitempty"`
+	// time the schema entered the state
+	Since time.Time `json:"since,omitempty"`
+}
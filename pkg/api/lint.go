@@ -0,0 +1,58 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetSchemaLintRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// return the diagnostics of these rules only
+	Rules []string `json:"rules,omitempty"`
+	// return the diagnostics of this severity or a higher one:
+	// "error", "warning" or "info", all the diagnostics if empty
+	Severity string `json:"severity,omitempty"`
+}
+
+type GetSchemaLintResponse struct {
+	// the schema is not linted, see the schema lint config
+	NotLinted   bool              `json:"not-linted,omitempty"`
+	Diagnostics []*LintDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type LintDiagnostic struct {
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	// schema path of the node, empty for a module statement
+	Path     string `json:"path,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type ListLintRulesRequest struct{}
+
+type ListLintRulesResponse struct {
+	Rules []*LintRule `json:"rules,omitempty"`
+}
+
+type LintRule struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	// plugin file of the rule, empty for a built-in rule
+	Plugin string `json:"plugin,omitempty"`
+}
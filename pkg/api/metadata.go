@@ -0,0 +1,38 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetSchemaMetadataRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+}
+
+type GetSchemaMetadataResponse struct {
+	Modules []*ModuleMetadata `json:"modules,omitempty"`
+	// missing or mixed licensing metadata
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type ModuleMetadata struct {
+	Name         string `json:"name,omitempty"`
+	Revision     string `json:"revision,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Contact      string `json:"contact,omitempty"`
+	// SPDX license identifier
+	License string `json:"license,omitempty"`
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type NormalizeDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// RFC 7951 JSON config document rooted at the schema root
+	Document json.RawMessage `json:"document,omitempty"`
+}
+
+type NormalizeDocumentResponse struct {
+	// canonical form of the document
+	Document json.RawMessage `json:"document,omitempty"`
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// ParseModulesRequest is a chunk of a YANG file streamed to ParseModules,
+// the content of a file can be split over several messages.
+type ParseModulesRequest struct {
+	// schema the imports of the modules are also resolved from, optional.
+	// Only read from the first message.
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// slash separated file name
+	FileName string `json:"file-name,omitempty"`
+	Contents []byte `json:"contents,omitempty"`
+	// the file is only used to resolve the imports of the modules
+	Dependency bool `json:"dependency,omitempty"`
+}
+
+// ParseModulesResponse reports the outcome of parsing the streamed modules,
+// nothing is stored.
+type ParseModulesResponse struct {
+	// modules of the parsed schema, the imported ones included.
+	// Empty if the modules failed to be parsed.
+	Modules []string           `json:"modules,omitempty"`
+	Errors  []*ParseDiagnostic `json:"errors,omitempty"`
+	// composition issues of the parsed schema, e.g. augment conflicts
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type ParseDiagnostic struct {
+	// file as streamed by the client, empty if not known
+	File string `json:"file,omitempty"`
+	// position in the file, zero if not known
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message,omitempty"`
+}
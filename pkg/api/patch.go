@@ -0,0 +1,66 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// kinds of the errors of a patch, on top of the DocumentError ones
+const (
+	// an operation refers to a location the document does not have, RFC 6901
+	PatchErrorPointer = "pointer"
+	// a test operation failed
+	PatchErrorTest = "test"
+)
+
+type PatchDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// node the document is the content of, a container or a list entry,
+	// the root if not set, as in ValidateDocumentRequest
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// RFC 7951 JSON document
+	Document json.RawMessage `json:"document,omitempty"`
+	// RFC 6902 JSON Patch: an array of add, remove, replace, move, copy and test operations
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+type PatchDocumentResponse struct {
+	Valid bool `json:"valid,omitempty"`
+	// patched document, only set if valid
+	Document json.RawMessage `json:"document,omitempty"`
+	// the errors of the first failing operation, the following ones
+	// are not applied, or else the errors of the patched document
+	Errors []*PatchError `json:"errors,omitempty"`
+	// more errors were found than returned
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type PatchError struct {
+	// index of the operation in the patch, -1 for the errors of the document
+	// before or after the patch and of a patch that is not an array
+	Index int    `json:"index"`
+	Op    string `json:"op,omitempty"`
+	// JSON pointer of the operation
+	Pointer string `json:"pointer,omitempty"`
+	// the instance path of the node, with the keys of the list entries
+	Path string `json:"path,omitempty"`
+	// one of the DocumentError kinds, pointer or test
+	Kind       string `json:"kind,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// PinSchemaRequest pins the current tree of Schema for TTL: the pinned schema is served,
+// unchanged by the reloads of Schema, at version <version>+<fingerprint> until it expires
+// or is deleted. Pinning it again extends its TTL.
+type PinSchemaRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// the fingerprint the schema is expected to have, if set
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// the server default if not set, up to the server max
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+type PinSchemaResponse struct {
+	Pin *SchemaPin `json:"pin,omitempty"`
+}
+
+type ListSchemaPinsRequest struct{}
+
+type ListSchemaPinsResponse struct {
+	Pins []*SchemaPin `json:"pins,omitempty"`
+}
+
+// SchemaPin is a pinned schema.
+type SchemaPin struct {
+	// the schema to request the pinned tree for
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// the schema pinned
+	Pinned      *sdcpb.Schema `json:"pinned,omitempty"`
+	Fingerprint string        `json:"fingerprint,omitempty"`
+	Expires     time.Time     `json:"expires,omitempty"`
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type PrefetchSchemaRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// paths the client expects to query,
+	// each path prefix is resolved as well.
+	Paths []*sdcpb.Path `json:"paths,omitempty"`
+}
+
+type PrefetchSchemaResponse struct {
+	// number of distinct paths resolved
+	Resolved int `json:"resolved,omitempty"`
+	// paths that failed to resolve
+	Failed []*PathError `json:"failed,omitempty"`
+}
+
+type PathError struct {
+	Path  string `json:"path,omitempty"`
+	Error string `json:"error,omitempty"`
+}
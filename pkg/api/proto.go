@@ -0,0 +1,55 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Update wraps an sdcpb.Update so that it is encoded using protojson,
+// encoding/json cannot decode the TypedValue oneof.
+type Update struct {
+	*sdcpb.Update
+}
+
+func (u Update) MarshalJSON() ([]byte, error) {
+	if u.Update == nil {
+		return []byte("null"), nil
+	}
+	return protojson.Marshal(u.Update)
+}
+
+func (u *Update) UnmarshalJSON(b []byte) error {
+	u.Update = new(sdcpb.Update)
+	return protojson.Unmarshal(b, u.Update)
+}
+
+// SchemaElem wraps an sdcpb.SchemaElem so that it is encoded using protojson.
+type SchemaElem struct {
+	*sdcpb.SchemaElem
+}
+
+func (se SchemaElem) MarshalJSON() ([]byte, error) {
+	if se.SchemaElem == nil {
+		return []byte("null"), nil
+	}
+	return protojson.Marshal(se.SchemaElem)
+}
+
+func (se *SchemaElem) UnmarshalJSON(b []byte) error {
+	se.SchemaElem = new(sdcpb.SchemaElem)
+	return protojson.Unmarshal(b, se.SchemaElem)
+}
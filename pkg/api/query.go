@@ -0,0 +1,38 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type QuerySchemaRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// e.g. select leaves where type=enumeration and config=true under /interface
+	Query string `json:"query,omitempty"`
+}
+
+type QuerySchemaResponse struct {
+	Matches []*QueryMatch `json:"matches,omitempty"`
+	// more nodes than the query limit match
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type QueryMatch struct {
+	Path string `json:"path,omitempty"`
+	// container, list, leaf or leaf-list
+	Kind       string            `json:"kind,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RedirectAddress returns the address of the instance owning the schema
+// of a request that failed with a ReasonSchemaNotOwned error, "" for other errors.
+func RedirectAddress(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, d := range st.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok && ei.GetReason() == ReasonSchemaNotOwned {
+			return ei.GetMetadata()["address"]
+		}
+	}
+	return ""
+}
+
+// Redirector follows the redirects of the instances of a sharded deployment:
+// a request for a schema owned by another instance is sent again to the owner,
+// over a connection dialed once per address.
+type Redirector struct {
+	dial func(ctx context.Context, addr string) (*grpc.ClientConn, error)
+
+	m     sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewRedirector returns a Redirector dialing the owners with dial.
+func NewRedirector(dial func(ctx context.Context, addr string) (*grpc.ClientConn, error)) *Redirector {
+	return &Redirector{dial: dial, conns: make(map[string]*grpc.ClientConn)}
+}
+
+func (r *Redirector) conn(ctx context.Context, addr string) (*grpc.ClientConn, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if cc, ok := r.conns[addr]; ok {
+		return cc, nil
+	}
+	cc, err := r.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	r.conns[addr] = cc
+	return cc, nil
+}
+
+// Close closes the connections to the owners.
+func (r *Redirector) Close() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	var err error
+	for addr, cc := range r.conns {
+		if cerr := cc.Close(); err == nil {
+			err = cerr
+		}
+		delete(r.conns, addr)
+	}
+	return err
+}
+
+// UnaryClientInterceptor sends a redirected unary RPC to the owner, once.
+func (r *Redirector) UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	addr := RedirectAddress(err)
+	if addr == "" {
+		return err
+	}
+	occ, oerr := r.conn(ctx, addr)
+	if oerr != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, occ, opts...)
+}
+
+// StreamClientInterceptor sends a redirected server stream to the owner, once. The request
+// sent before the first message is received is kept to be sent again to the owner.
+// Client streams, sending concurrently with receiving, are not redirected.
+func (r *Redirector) StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil || desc.ClientStreams {
+		return cs, err
+	}
+	return &redirectStream{ClientStream: cs, r: r, ctx: ctx, desc: desc, method: method, streamer: streamer, opts: opts}, nil
+}
+
+type redirectStream struct {
+	grpc.ClientStream
+	r        *Redirector
+	ctx      context.Context
+	desc     *grpc.StreamDesc
+	method   string
+	streamer grpc.Streamer
+	opts     []grpc.CallOption
+
+	// messages sent and whether the send side is closed, until a message is received
+	sent     []interface{}
+	closed   bool
+	received bool
+}
+
+func (s *redirectStream) SendMsg(m interface{}) error {
+	if !s.received {
+		s.sent = append(s.sent, m)
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *redirectStream) CloseSend() error {
+	s.closed = true
+	return s.ClientStream.CloseSend()
+}
+
+func (s *redirectStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if s.received {
+		return err
+	}
+	s.received = true
+	sent := s.sent
+	s.sent = nil
+	addr := RedirectAddress(err)
+	if addr == "" {
+		return err
+	}
+	occ, oerr := s.r.conn(s.ctx, addr)
+	if oerr != nil {
+		return err
+	}
+	cs, oerr := s.streamer(s.ctx, s.desc, occ, s.method, s.opts...)
+	if oerr != nil {
+		return oerr
+	}
+	for _, sm := range sent {
+		if err := cs.SendMsg(sm); err != nil {
+			return err
+		}
+	}
+	if s.closed {
+		if err := cs.CloseSend(); err != nil {
+			return err
+		}
+	}
+	s.ClientStream = cs
+	return cs.RecvMsg(m)
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ReloadConfigRequest struct{}
+
+// ReloadConfigResponse lists the configured schemas by outcome of the reload,
+// schemas created or uploaded at runtime are not part of it.
+type ReloadConfigResponse struct {
+	Added     []*sdcpb.Schema `json:"added,omitempty"`
+	Removed   []*sdcpb.Schema `json:"removed,omitempty"`
+	Reloaded  []*sdcpb.Schema `json:"reloaded,omitempty"`
+	Unchanged []*sdcpb.Schema `json:"unchanged,omitempty"`
+	// schemas failing to parse, the ones being reloaded are kept as they were
+	Failed []*ReloadFailure `json:"failed,omitempty"`
+}
+
+type ReloadFailure struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
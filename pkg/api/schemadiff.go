@@ -0,0 +1,58 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// schema difference kinds
+const (
+	DiffAdded          = "added"
+	DiffRemoved        = "removed"
+	DiffChanged        = "changed"
+	DiffTypeChanged    = "type-changed"
+	DiffMandatoryAdded = "mandatory-added"
+	DiffEnumAdded      = "enum-added"
+	DiffEnumRemoved    = "enum-removed"
+)
+
+type DiffSchemaRequest struct {
+	From *sdcpb.Schema `json:"from,omitempty"`
+	To   *sdcpb.Schema `json:"to,omitempty"`
+}
+
+type DiffSchemaResponse struct {
+	Diffs []*SchemaDiff `json:"diffs,omitempty"`
+}
+
+// SchemaDiff is a difference between the From and To schemas of a DiffSchema request.
+// An added or removed subtree is reported as a whole, along with the mandatory
+// leaves an added container requires.
+type SchemaDiff struct {
+	Kind string `json:"kind,omitempty"`
+	// element names, starting with the module name
+	Path string `json:"path,omitempty"`
+	// container, list, leaf or leaf-list
+	Node string `json:"node,omitempty"`
+	// attribute of a changed node: keys, config, presence, default, units,
+	// min-elements, max-elements or ordered-by
+	Attribute string `json:"attribute,omitempty"`
+	// attribute value, type or enum in the From and To schemas
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// set if a config valid against the From schema may not be against the To schema
+	Breaking bool `json:"breaking,omitempty"`
+}
@@ -0,0 +1,822 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package api defines the SchemaServerExt gRPC service.
+// It carries the RPCs that are not (yet) part of the sdc-protos
+// SchemaServer service. Messages are plain Go structs encoded
+// with the json codec registered by this package.
+package api
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ServiceName = "schema.SchemaServerExt"
+
+// SchemaServerExtServer is the server API for the SchemaServerExt service.
+type SchemaServerExtServer interface {
+	// returns the server build info, enabled features and limits
+	GetServerInfo(context.Context, *GetServerInfoRequest) (*GetServerInfoResponse, error)
+	// pre-resolves and caches a set of paths expected to be used shortly,
+	// fails with FailedPrecondition if the store does not cache the schema lookups
+	PrefetchSchema(context.Context, *PrefetchSchemaRequest) (*PrefetchSchemaResponse, error)
+	// EncodeJSON returns the RFC 7951 JSON encoding of the raw values found at a path.
+	EncodeJSON(context.Context, *EncodeJSONRequest) (*EncodeJSONResponse, error)
+	// CanonicalPath validates path keys against the schema and returns the paths with keys in the schema declared order.
+	CanonicalPath(context.Context, *CanonicalPathRequest) (*CanonicalPathResponse, error)
+	// DiffDocument returns the path level changes between two instance documents of a schema.
+	DiffDocument(context.Context, *DiffDocumentRequest) (*DiffDocumentResponse, error)
+	// NormalizeDocument returns the canonical form of an instance document of a schema.
+	NormalizeDocument(context.Context, *NormalizeDocumentRequest) (*NormalizeDocumentResponse, error)
+	// BuildDocument assembles a nested instance document from a set of path and value updates.
+	BuildDocument(context.Context, *BuildDocumentRequest) (*BuildDocumentResponse, error)
+	// FlattenDocument returns the leaves of an instance document as path and typed value updates.
+	FlattenDocument(context.Context, *FlattenDocumentRequest) (*FlattenDocumentResponse, error)
+	// GetPathTemplate returns the schema node of a path template and the types of its placeholders.
+	GetPathTemplate(context.Context, *GetPathTemplateRequest) (*GetPathTemplateResponse, error)
+	// GetSchemaMetadata returns the organization, contact and license of the modules of a schema.
+	GetSchemaMetadata(context.Context, *GetSchemaMetadataRequest) (*GetSchemaMetadataResponse, error)
+	// GetRequestJournal returns the last requests recorded in the request journal.
+	GetRequestJournal(context.Context, *GetRequestJournalRequest) (*GetRequestJournalResponse, error)
+	// AnonymizeDocument masks and randomizes the sensitive leaves of a config document
+	AnonymizeDocument(context.Context, *AnonymizeDocumentRequest) (*AnonymizeDocumentResponse, error)
+	// GetInstanceTemplate returns the instance path template of a list path with a typed placeholder per key.
+	GetInstanceTemplate(context.Context, *GetInstanceTemplateRequest) (*GetInstanceTemplateResponse, error)
+	// GetSchemaComposition returns the unresolved imports, conflicting augments and duplicate nodes of a schema.
+	GetSchemaComposition(context.Context, *GetSchemaCompositionRequest) (*GetSchemaCompositionResponse, error)
+	// ExpandPathStream streams the paths ExpandPath returns in batches, the paths are not sorted.
+	ExpandPathStream(*sdcpb.ExpandPathRequest, SchemaServerExt_ExpandPathStreamServer) error
+	// WatchSchemaChanges streams a SchemaEvent each time a watched schema is added, removed or reloaded.
+	WatchSchemaChanges(*WatchSchemaChangesRequest, SchemaServerExt_WatchSchemaChangesServer) error
+	// DiffSchema streams the differences between two schemas.
+	DiffSchema(*DiffSchemaRequest, SchemaServerExt_DiffSchemaServer) error
+	// ParseModules parses the YANG files streamed by the client and reports the errors found,
+	// nothing is stored.
+	ParseModules(SchemaServerExt_ParseModulesServer) error
+	// ExportSchema streams a canonical text representation of a schema,
+	// stable from one load or server version to the next for golden files.
+	ExportSchema(*ExportSchemaRequest, SchemaServerExt_ExportSchemaServer) error
+	// QuerySchema returns the schema nodes selected by a query along with their attributes.
+	QuerySchema(context.Context, *QuerySchemaRequest) (*QuerySchemaResponse, error)
+	// GetSchemaFingerprint returns the fingerprint of the current content of a schema.
+	GetSchemaFingerprint(context.Context, *GetSchemaFingerprintRequest) (*GetSchemaFingerprintResponse, error)
+	// ExportSchemaChanges returns the containers and lists of a schema changed since a fingerprint.
+	ExportSchemaChanges(context.Context, *ExportSchemaChangesRequest) (*ExportSchemaChangesResponse, error)
+	// re-reads the config file and loads, removes or reloads the configured schemas that changed
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	// SetSchemaState moves a schema to a lifecycle state: staged, active, deprecated or archived
+	SetSchemaState(context.Context, *SetSchemaStateRequest) (*SetSchemaStateResponse, error)
+	// ListSchemaStates returns the lifecycle state of the schemas that are not active
+	ListSchemaStates(context.Context, *ListSchemaStatesRequest) (*ListSchemaStatesResponse, error)
+	// SetSchemaCanary sets or removes the canary version of a schema
+	SetSchemaCanary(context.Context, *SetSchemaCanaryRequest) (*SetSchemaCanaryResponse, error)
+	// ListSchemaCanaries returns the canary versions of the schemas
+	ListSchemaCanaries(context.Context, *ListSchemaCanariesRequest) (*ListSchemaCanariesResponse, error)
+	// ExpandWildcardPath returns the leaf paths matching a path with wildcards, a page at a time
+	ExpandWildcardPath(context.Context, *ExpandWildcardPathRequest) (*ExpandWildcardPathResponse, error)
+	// GetSchemaLint returns the diagnostics of the lint rules run when a schema is loaded
+	GetSchemaLint(context.Context, *GetSchemaLintRequest) (*GetSchemaLintResponse, error)
+	// ListLintRules returns the lint rules a schema can be configured with
+	ListLintRules(context.Context, *ListLintRulesRequest) (*ListLintRulesResponse, error)
+	// CompletePath returns the possible next elements of a partial path, for CLI completion
+	CompletePath(context.Context, *CompletePathRequest) (*CompletePathResponse, error)
+	// ResolveLeafref returns the schema paths a leafref leaf refers to
+	ResolveLeafref(context.Context, *ResolveLeafrefRequest) (*ResolveLeafrefResponse, error)
+	// ValidateExpression checks an XPath must or when expression against a schema
+	ValidateExpression(context.Context, *ValidateExpressionRequest) (*ValidateExpressionResponse, error)
+	// ValidateValue checks a value against the type of a leaf or leaf-list
+	ValidateValue(context.Context, *ValidateValueRequest) (*ValidateValueResponse, error)
+	// ListSubmodules returns the submodules of a schema with the module they belong to and their includes.
+	ListSubmodules(context.Context, *ListSubmodulesRequest) (*ListSubmodulesResponse, error)
+	// GetDefaults returns the leaves below a container or a list path that have a default value.
+	GetDefaults(context.Context, *GetDefaultsRequest) (*GetDefaultsResponse, error)
+	// PinSchema keeps the current tree of a schema for reproducible multi-RPC workflows, across its reloads.
+	PinSchema(context.Context, *PinSchemaRequest) (*PinSchemaResponse, error)
+	// ListSchemaPins returns the pinned schemas.
+	ListSchemaPins(context.Context, *ListSchemaPinsRequest) (*ListSchemaPinsResponse, error)
+	// ListIdentities returns the identities derived from a base identity, transitively and across modules.
+	ListIdentities(context.Context, *ListIdentitiesRequest) (*ListIdentitiesResponse, error)
+	// ListSchemaElements returns a page of the schema elements along a path, trimmed to a field mask
+	ListSchemaElements(context.Context, *ListSchemaElementsRequest) (*ListSchemaElementsResponse, error)
+	// GetSchemaSubtree walks the schema subtree found at a path and streams its elements in batches,
+	// for the subtrees too large for a single GetSchema response.
+	GetSchemaSubtree(*GetSchemaSubtreeRequest, SchemaServerExt_GetSchemaSubtreeServer) error
+	// FindSchema returns the schema nodes with a name, description or type matching a pattern.
+	FindSchema(context.Context, *FindSchemaRequest) (*FindSchemaResponse, error)
+	// GetKeyConstraints returns the types, patterns and ranges of the wildcarded keys of a path.
+	GetKeyConstraints(context.Context, *GetKeyConstraintsRequest) (*GetKeyConstraintsResponse, error)
+	// RenderTree renders a schema or subtree as pyang -f tree style text, with config flags, keys and types.
+	RenderTree(context.Context, *RenderTreeRequest) (*RenderTreeResponse, error)
+	// GenerateArtifact returns a JSON Schema or OpenAPI document describing the JSON encoding of a schema or subtree.
+	GenerateArtifact(context.Context, *GenerateArtifactRequest) (*GenerateArtifactResponse, error)
+	// GetModuleYIN returns a module or a submodule of a schema as a YIN document
+	GetModuleYIN(context.Context, *GetModuleYINRequest) (*GetModuleYINResponse, error)
+	// GetModuleSource streams the YANG source file of a module or a submodule of a schema
+	GetModuleSource(*GetModuleSourceRequest, SchemaServerExt_GetModuleSourceServer) error
+	// WatchInvalidations streams the subtrees of a schema changed since a fingerprint, then at each reload
+	WatchInvalidations(*WatchInvalidationsRequest, SchemaServerExt_WatchInvalidationsServer) error
+	// GetYangLibrary returns the RFC 8525 YANG library (modules-state) document of a schema.
+	GetYangLibrary(context.Context, *GetYangLibraryRequest) (*GetYangLibraryResponse, error)
+	// ValidateDocument validates a JSON or XML instance document against a schema: its structure, types, keys, mandatory nodes and the leafrefs within it.
+	ValidateDocument(context.Context, *ValidateDocumentRequest) (*ValidateDocumentResponse, error)
+	// ConvertDocument converts an instance document between the RFC 7951 JSON, RFC 7950 XML and typed update encodings.
+	ConvertDocument(context.Context, *ConvertDocumentRequest) (*ConvertDocumentResponse, error)
+	// TranslatePath translates a gNMI path to a schema path or back, validating it against the schema.
+	TranslatePath(context.Context, *TranslatePathRequest) (*TranslatePathResponse, error)
+	// GetSchemaStats returns the node counts, the max depth, the parse duration and a memory estimate of a schema, or of all the loaded ones, and their total.
+	GetSchemaStats(context.Context, *GetSchemaStatsRequest) (*GetSchemaStatsResponse, error)
+	// Hello exchanges the API versions and capabilities of the client and the server, for the client to detect the features it can use.
+	Hello(context.Context, *HelloRequest) (*HelloResponse, error)
+	// ListAnnotations returns the metadata annotations (RFC 7952) defined by the modules of a schema.
+	ListAnnotations(context.Context, *ListAnnotationsRequest) (*ListAnnotationsResponse, error)
+	// PatchDocument applies an RFC 6902 JSON Patch to an instance document, checking each operation against the schema, and validates the patched document.
+	PatchDocument(context.Context, *PatchDocumentRequest) (*PatchDocumentResponse, error)
+}
+
+// UnimplementedSchemaServerExtServer can be embedded to have forward compatible implementations.
+type UnimplementedSchemaServerExtServer struct{}
+
+func (UnimplementedSchemaServerExtServer) GetServerInfo(context.Context, *GetServerInfoRequest) (*GetServerInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerInfo not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) PrefetchSchema(context.Context, *PrefetchSchemaRequest) (*PrefetchSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrefetchSchema not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) EncodeJSON(context.Context, *EncodeJSONRequest) (*EncodeJSONResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EncodeJSON not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) CanonicalPath(context.Context, *CanonicalPathRequest) (*CanonicalPathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CanonicalPath not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) DiffDocument(context.Context, *DiffDocumentRequest) (*DiffDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) NormalizeDocument(context.Context, *NormalizeDocumentRequest) (*NormalizeDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NormalizeDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) BuildDocument(context.Context, *BuildDocumentRequest) (*BuildDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) FlattenDocument(context.Context, *FlattenDocumentRequest) (*FlattenDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlattenDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetPathTemplate(context.Context, *GetPathTemplateRequest) (*GetPathTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPathTemplate not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetSchemaMetadata(context.Context, *GetSchemaMetadataRequest) (*GetSchemaMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaMetadata not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetRequestJournal(context.Context, *GetRequestJournalRequest) (*GetRequestJournalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRequestJournal not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) AnonymizeDocument(context.Context, *AnonymizeDocumentRequest) (*AnonymizeDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnonymizeDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetInstanceTemplate(context.Context, *GetInstanceTemplateRequest) (*GetInstanceTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstanceTemplate not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetSchemaComposition(context.Context, *GetSchemaCompositionRequest) (*GetSchemaCompositionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaComposition not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ExpandPathStream(*sdcpb.ExpandPathRequest, SchemaServerExt_ExpandPathStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExpandPathStream not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) WatchSchemaChanges(*WatchSchemaChangesRequest, SchemaServerExt_WatchSchemaChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSchemaChanges not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) DiffSchema(*DiffSchemaRequest, SchemaServerExt_DiffSchemaServer) error {
+	return status.Errorf(codes.Unimplemented, "method DiffSchema not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ParseModules(SchemaServerExt_ParseModulesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ParseModules not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ExportSchema(*ExportSchemaRequest, SchemaServerExt_ExportSchemaServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportSchema not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) QuerySchema(context.Context, *QuerySchemaRequest) (*QuerySchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuerySchema not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetSchemaFingerprint(context.Context, *GetSchemaFingerprintRequest) (*GetSchemaFingerprintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaFingerprint not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ExportSchemaChanges(context.Context, *ExportSchemaChangesRequest) (*ExportSchemaChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSchemaChanges not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) SetSchemaState(context.Context, *SetSchemaStateRequest) (*SetSchemaStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSchemaState not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListSchemaStates(context.Context, *ListSchemaStatesRequest) (*ListSchemaStatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchemaStates not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) SetSchemaCanary(context.Context, *SetSchemaCanaryRequest) (*SetSchemaCanaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSchemaCanary not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListSchemaCanaries(context.Context, *ListSchemaCanariesRequest) (*ListSchemaCanariesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchemaCanaries not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ExpandWildcardPath(context.Context, *ExpandWildcardPathRequest) (*ExpandWildcardPathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExpandWildcardPath not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetSchemaLint(context.Context, *GetSchemaLintRequest) (*GetSchemaLintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaLint not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListLintRules(context.Context, *ListLintRulesRequest) (*ListLintRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLintRules not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) CompletePath(context.Context, *CompletePathRequest) (*CompletePathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompletePath not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ResolveLeafref(context.Context, *ResolveLeafrefRequest) (*ResolveLeafrefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveLeafref not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ValidateExpression(context.Context, *ValidateExpressionRequest) (*ValidateExpressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateExpression not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ValidateValue(context.Context, *ValidateValueRequest) (*ValidateValueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateValue not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListSubmodules(context.Context, *ListSubmodulesRequest) (*ListSubmodulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubmodules not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetDefaults(context.Context, *GetDefaultsRequest) (*GetDefaultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDefaults not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) PinSchema(context.Context, *PinSchemaRequest) (*PinSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinSchema not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListSchemaPins(context.Context, *ListSchemaPinsRequest) (*ListSchemaPinsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchemaPins not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListIdentities(context.Context, *ListIdentitiesRequest) (*ListIdentitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIdentities not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListSchemaElements(context.Context, *ListSchemaElementsRequest) (*ListSchemaElementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchemaElements not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetSchemaSubtree(*GetSchemaSubtreeRequest, SchemaServerExt_GetSchemaSubtreeServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetSchemaSubtree not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) FindSchema(context.Context, *FindSchemaRequest) (*FindSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindSchema not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetKeyConstraints(context.Context, *GetKeyConstraintsRequest) (*GetKeyConstraintsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyConstraints not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) RenderTree(context.Context, *RenderTreeRequest) (*RenderTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderTree not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GenerateArtifact(context.Context, *GenerateArtifactRequest) (*GenerateArtifactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateArtifact not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetModuleYIN(context.Context, *GetModuleYINRequest) (*GetModuleYINResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModuleYIN not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetModuleSource(*GetModuleSourceRequest, SchemaServerExt_GetModuleSourceServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetModuleSource not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) WatchInvalidations(*WatchInvalidationsRequest, SchemaServerExt_WatchInvalidationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchInvalidations not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetYangLibrary(context.Context, *GetYangLibraryRequest) (*GetYangLibraryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetYangLibrary not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ValidateDocument(context.Context, *ValidateDocumentRequest) (*ValidateDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ConvertDocument(context.Context, *ConvertDocumentRequest) (*ConvertDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConvertDocument not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) TranslatePath(context.Context, *TranslatePathRequest) (*TranslatePathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TranslatePath not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) GetSchemaStats(context.Context, *GetSchemaStatsRequest) (*GetSchemaStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchemaStats not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) Hello(context.Context, *HelloRequest) (*HelloResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hello not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) ListAnnotations(context.Context, *ListAnnotationsRequest) (*ListAnnotationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnnotations not implemented")
+}
+
+func (UnimplementedSchemaServerExtServer) PatchDocument(context.Context, *PatchDocumentRequest) (*PatchDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchDocument not implemented")
+}
+
+func RegisterSchemaServerExtServer(s grpc.ServiceRegistrar, srv SchemaServerExtServer) {
+	s.RegisterService(&SchemaServerExt_ServiceDesc, srv)
+}
+
+// SchemaServerExt_ServiceDesc is the grpc.ServiceDesc for SchemaServerExt service.
+var SchemaServerExt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*SchemaServerExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetServerInfo",
+			Handler:    unaryHandler("GetServerInfo", SchemaServerExtServer.GetServerInfo),
+		},
+		{
+			MethodName: "PrefetchSchema",
+			Handler:    unaryHandler("PrefetchSchema", SchemaServerExtServer.PrefetchSchema),
+		},
+		{
+			MethodName: "EncodeJSON",
+			Handler:    unaryHandler("EncodeJSON", SchemaServerExtServer.EncodeJSON),
+		},
+		{
+			MethodName: "CanonicalPath",
+			Handler:    unaryHandler("CanonicalPath", SchemaServerExtServer.CanonicalPath),
+		},
+		{
+			MethodName: "DiffDocument",
+			Handler:    unaryHandler("DiffDocument", SchemaServerExtServer.DiffDocument),
+		},
+		{
+			MethodName: "NormalizeDocument",
+			Handler:    unaryHandler("NormalizeDocument", SchemaServerExtServer.NormalizeDocument),
+		},
+		{
+			MethodName: "BuildDocument",
+			Handler:    unaryHandler("BuildDocument", SchemaServerExtServer.BuildDocument),
+		},
+		{
+			MethodName: "FlattenDocument",
+			Handler:    unaryHandler("FlattenDocument", SchemaServerExtServer.FlattenDocument),
+		},
+		{
+			MethodName: "GetPathTemplate",
+			Handler:    unaryHandler("GetPathTemplate", SchemaServerExtServer.GetPathTemplate),
+		},
+		{
+			MethodName: "GetSchemaMetadata",
+			Handler:    unaryHandler("GetSchemaMetadata", SchemaServerExtServer.GetSchemaMetadata),
+		},
+		{
+			MethodName: "GetRequestJournal",
+			Handler:    unaryHandler("GetRequestJournal", SchemaServerExtServer.GetRequestJournal),
+		},
+		{
+			MethodName: "AnonymizeDocument",
+			Handler:    unaryHandler("AnonymizeDocument", SchemaServerExtServer.AnonymizeDocument),
+		},
+		{
+			MethodName: "GetInstanceTemplate",
+			Handler:    unaryHandler("GetInstanceTemplate", SchemaServerExtServer.GetInstanceTemplate),
+		},
+		{
+			MethodName: "GetSchemaComposition",
+			Handler:    unaryHandler("GetSchemaComposition", SchemaServerExtServer.GetSchemaComposition),
+		},
+		{
+			MethodName: "QuerySchema",
+			Handler:    unaryHandler("QuerySchema", SchemaServerExtServer.QuerySchema),
+		},
+		{
+			MethodName: "GetSchemaFingerprint",
+			Handler:    unaryHandler("GetSchemaFingerprint", SchemaServerExtServer.GetSchemaFingerprint),
+		},
+		{
+			MethodName: "ExportSchemaChanges",
+			Handler:    unaryHandler("ExportSchemaChanges", SchemaServerExtServer.ExportSchemaChanges),
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    unaryHandler("ReloadConfig", SchemaServerExtServer.ReloadConfig),
+		},
+		{
+			MethodName: "SetSchemaState",
+			Handler:    unaryHandler("SetSchemaState", SchemaServerExtServer.SetSchemaState),
+		},
+		{
+			MethodName: "ListSchemaStates",
+			Handler:    unaryHandler("ListSchemaStates", SchemaServerExtServer.ListSchemaStates),
+		},
+		{
+			MethodName: "SetSchemaCanary",
+			Handler:    unaryHandler("SetSchemaCanary", SchemaServerExtServer.SetSchemaCanary),
+		},
+		{
+			MethodName: "ListSchemaCanaries",
+			Handler:    unaryHandler("ListSchemaCanaries", SchemaServerExtServer.ListSchemaCanaries),
+		},
+		{
+			MethodName: "ExpandWildcardPath",
+			Handler:    unaryHandler("ExpandWildcardPath", SchemaServerExtServer.ExpandWildcardPath),
+		},
+		{
+			MethodName: "GetSchemaLint",
+			Handler:    unaryHandler("GetSchemaLint", SchemaServerExtServer.GetSchemaLint),
+		},
+		{
+			MethodName: "ListLintRules",
+			Handler:    unaryHandler("ListLintRules", SchemaServerExtServer.ListLintRules),
+		},
+		{
+			MethodName: "CompletePath",
+			Handler:    unaryHandler("CompletePath", SchemaServerExtServer.CompletePath),
+		},
+		{
+			MethodName: "ResolveLeafref",
+			Handler:    unaryHandler("ResolveLeafref", SchemaServerExtServer.ResolveLeafref),
+		},
+		{
+			MethodName: "ValidateExpression",
+			Handler:    unaryHandler("ValidateExpression", SchemaServerExtServer.ValidateExpression),
+		},
+		{
+			MethodName: "ValidateValue",
+			Handler:    unaryHandler("ValidateValue", SchemaServerExtServer.ValidateValue),
+		},
+		{
+			MethodName: "ListSubmodules",
+			Handler:    unaryHandler("ListSubmodules", SchemaServerExtServer.ListSubmodules),
+		},
+		{
+			MethodName: "GetDefaults",
+			Handler:    unaryHandler("GetDefaults", SchemaServerExtServer.GetDefaults),
+		},
+		{
+			MethodName: "PinSchema",
+			Handler:    unaryHandler("PinSchema", SchemaServerExtServer.PinSchema),
+		},
+		{
+			MethodName: "ListSchemaPins",
+			Handler:    unaryHandler("ListSchemaPins", SchemaServerExtServer.ListSchemaPins),
+		},
+		{
+			MethodName: "ListIdentities",
+			Handler:    unaryHandler("ListIdentities", SchemaServerExtServer.ListIdentities),
+		},
+		{
+			MethodName: "ListSchemaElements",
+			Handler:    unaryHandler("ListSchemaElements", SchemaServerExtServer.ListSchemaElements),
+		},
+		{
+			MethodName: "FindSchema",
+			Handler:    unaryHandler("FindSchema", SchemaServerExtServer.FindSchema),
+		},
+		{
+			MethodName: "GetKeyConstraints",
+			Handler:    unaryHandler("GetKeyConstraints", SchemaServerExtServer.GetKeyConstraints),
+		},
+		{
+			MethodName: "RenderTree",
+			Handler:    unaryHandler("RenderTree", SchemaServerExtServer.RenderTree),
+		},
+		{
+			MethodName: "GenerateArtifact",
+			Handler:    unaryHandler("GenerateArtifact", SchemaServerExtServer.GenerateArtifact),
+		},
+		{
+			MethodName: "GetModuleYIN",
+			Handler:    unaryHandler("GetModuleYIN", SchemaServerExtServer.GetModuleYIN),
+		},
+		{
+			MethodName: "GetYangLibrary",
+			Handler:    unaryHandler("GetYangLibrary", SchemaServerExtServer.GetYangLibrary),
+		},
+		{
+			MethodName: "ValidateDocument",
+			Handler:    unaryHandler("ValidateDocument", SchemaServerExtServer.ValidateDocument),
+		},
+		{
+			MethodName: "ConvertDocument",
+			Handler:    unaryHandler("ConvertDocument", SchemaServerExtServer.ConvertDocument),
+		},
+		{
+			MethodName: "TranslatePath",
+			Handler:    unaryHandler("TranslatePath", SchemaServerExtServer.TranslatePath),
+		},
+		{
+			MethodName: "GetSchemaStats",
+			Handler:    unaryHandler("GetSchemaStats", SchemaServerExtServer.GetSchemaStats),
+		},
+		{
+			MethodName: "Hello",
+			Handler:    unaryHandler("Hello", SchemaServerExtServer.Hello),
+		},
+		{
+			MethodName: "ListAnnotations",
+			Handler:    unaryHandler("ListAnnotations", SchemaServerExtServer.ListAnnotations),
+		},
+		{
+			MethodName: "PatchDocument",
+			Handler:    unaryHandler("PatchDocument", SchemaServerExtServer.PatchDocument),
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExpandPathStream",
+			Handler:       expandPathStreamHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchSchemaChanges",
+			Handler:       watchSchemaChangesHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DiffSchema",
+			Handler:       diffSchemaHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ParseModules",
+			Handler:       parseModulesHandler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportSchema",
+			Handler:       exportSchemaHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetSchemaSubtree",
+			Handler:       getSchemaSubtreeHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetModuleSource",
+			Handler:       getModuleSourceHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchInvalidations",
+			Handler:       watchInvalidationsHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "schema_ext",
+}
+
+// FullMethod returns the full gRPC method name of a SchemaServerExt method.
+func FullMethod(method string) string {
+	return "/" + ServiceName + "/" + method
+}
+
+func unaryHandler[Req, Rsp any](method string, call func(SchemaServerExtServer, context.Context, *Req) (*Rsp, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return call(srv.(SchemaServerExtServer), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: FullMethod(method),
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(SchemaServerExtServer), ctx, req.(*Req))
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+type SchemaServerExt_ExpandPathStreamServer interface {
+	Send(*sdcpb.ExpandPathResponse) error
+	grpc.ServerStream
+}
+
+type schemaServerExtExpandPathStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtExpandPathStreamServer) Send(m *sdcpb.ExpandPathResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func expandPathStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(sdcpb.ExpandPathRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).ExpandPathStream(in, &schemaServerExtExpandPathStreamServer{stream})
+}
+
+type SchemaServerExt_WatchSchemaChangesServer interface {
+	Send(*SchemaEvent) error
+	grpc.ServerStream
+}
+
+type schemaServerExtWatchSchemaChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtWatchSchemaChangesServer) Send(m *SchemaEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func watchSchemaChangesHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(WatchSchemaChangesRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).WatchSchemaChanges(in, &schemaServerExtWatchSchemaChangesServer{stream})
+}
+
+type SchemaServerExt_DiffSchemaServer interface {
+	Send(*DiffSchemaResponse) error
+	grpc.ServerStream
+}
+
+type schemaServerExtDiffSchemaServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtDiffSchemaServer) Send(m *DiffSchemaResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func diffSchemaHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(DiffSchemaRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).DiffSchema(in, &schemaServerExtDiffSchemaServer{stream})
+}
+
+type SchemaServerExt_ParseModulesServer interface {
+	SendAndClose(*ParseModulesResponse) error
+	Recv() (*ParseModulesRequest, error)
+	grpc.ServerStream
+}
+
+type schemaServerExtParseModulesServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtParseModulesServer) SendAndClose(m *ParseModulesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *schemaServerExtParseModulesServer) Recv() (*ParseModulesRequest, error) {
+	m := new(ParseModulesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func parseModulesHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SchemaServerExtServer).ParseModules(&schemaServerExtParseModulesServer{stream})
+}
+
+type SchemaServerExt_ExportSchemaServer interface {
+	Send(*ExportSchemaResponse) error
+	grpc.ServerStream
+}
+
+type schemaServerExtExportSchemaServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtExportSchemaServer) Send(m *ExportSchemaResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func exportSchemaHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(ExportSchemaRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).ExportSchema(in, &schemaServerExtExportSchemaServer{stream})
+}
+
+type SchemaServerExt_GetSchemaSubtreeServer interface {
+	Send(*GetSchemaSubtreeResponse) error
+	grpc.ServerStream
+}
+
+type schemaServerExtGetSchemaSubtreeServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtGetSchemaSubtreeServer) Send(m *GetSchemaSubtreeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func getSchemaSubtreeHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(GetSchemaSubtreeRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).GetSchemaSubtree(in, &schemaServerExtGetSchemaSubtreeServer{stream})
+}
+
+type SchemaServerExt_GetModuleSourceServer interface {
+	Send(*GetModuleSourceResponse) error
+	grpc.ServerStream
+}
+
+type schemaServerExtGetModuleSourceServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtGetModuleSourceServer) Send(m *GetModuleSourceResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func getModuleSourceHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(GetModuleSourceRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).GetModuleSource(in, &schemaServerExtGetModuleSourceServer{stream})
+}
+
+type SchemaServerExt_WatchInvalidationsServer interface {
+	Send(*Invalidation) error
+	grpc.ServerStream
+}
+
+type schemaServerExtWatchInvalidationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *schemaServerExtWatchInvalidationsServer) Send(m *Invalidation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func watchInvalidationsHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(WatchInvalidationsRequest)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(SchemaServerExtServer).WatchInvalidations(in, &schemaServerExtWatchInvalidationsServer{stream})
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetModuleSourceRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// name of a module or a submodule of the schema, an imported one included
+	Module string `json:"module,omitempty"`
+	// revision of the module, its latest one found in the schema sources if empty
+	Revision string `json:"revision,omitempty"`
+}
+
+// GetModuleSourceResponse carries the next part of the source file,
+// the parts are concatenated in the order they are received.
+type GetModuleSourceResponse struct {
+	// set on the first response
+	Module   string `json:"module,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	// name of the source file, e.g. module@revision.yang, and its size in bytes
+	File string `json:"file,omitempty"`
+	Size int64  `json:"size,omitempty"`
+	// the next bytes of the file, as read
+	Contents []byte `json:"contents,omitempty"`
+	// sha256 of the whole file, set on the last response
+	Digest string `json:"digest,omitempty"`
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetSchemaStatsRequest struct {
+	// the schema to get the stats of, all the loaded schemas if not set
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+}
+
+type GetSchemaStatsResponse struct {
+	// sorted by schema name, vendor and version
+	Schemas []*SchemaStats `json:"schemas,omitempty"`
+	// sum of the schemas stats, the max depth being the deepest one
+	Total *SchemaStats `json:"total,omitempty"`
+}
+
+type SchemaStats struct {
+	// not set on the total
+	Schema  *sdcpb.Schema `json:"schema,omitempty"`
+	Modules int           `json:"modules,omitempty"`
+	// data nodes, choices and cases excluded
+	Containers  int `json:"containers,omitempty"`
+	Lists       int `json:"lists,omitempty"`
+	Leaves      int `json:"leaves,omitempty"`
+	LeafLists   int `json:"leaf-lists,omitempty"`
+	ConfigNodes int `json:"config-nodes,omitempty"`
+	StateNodes  int `json:"state-nodes,omitempty"`
+	// levels of data nodes below the modules
+	MaxDepth      int           `json:"max-depth,omitempty"`
+	ParseDuration time.Duration `json:"parse-duration,omitempty"`
+	// estimate of the memory taken by the schema tree, in bytes
+	MemoryEstimate int64 `json:"memory-estimate,omitempty"`
+	// the stats of a schema stored by a previous server version are unknown
+	Unknown bool `json:"unknown,omitempty"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ListSubmodulesRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+}
+
+type ListSubmodulesResponse struct {
+	Submodules []*Submodule `json:"submodules,omitempty"`
+}
+
+type Submodule struct {
+	Name     string `json:"name,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	// module the submodule belongs to, GetSchema resolves
+	// a submodule name to it.
+	BelongsTo string `json:"belongs-to,omitempty"`
+	// submodules included by the submodule
+	Includes []string `json:"includes,omitempty"`
+	// modules and submodules including the submodule
+	IncludedBy []string `json:"included-by,omitempty"`
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetSchemaSubtreeRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// root of the subtree, the whole schema if not set
+	Path            *sdcpb.Path `json:"path,omitempty"`
+	WithDescription bool        `json:"with-description,omitempty"`
+	// levels walked below the path, unlimited if not set
+	MaxDepth int `json:"max-depth,omitempty"`
+}
+
+// GetSchemaSubtreeResponse carries the next elements of the subtree in depth first order.
+// The containers and lists carry their keys, leaves and leaf-lists, which are
+// not sent on their own unless the path points at them.
+type GetSchemaSubtreeResponse struct {
+	Elements []*SubtreeElem `json:"elements,omitempty"`
+}
+
+type SubtreeElem struct {
+	// names of the request path followed by the ones walked below it,
+	// the first name is a module when walking from the schema root
+	Path   string      `json:"path,omitempty"`
+	Schema *SchemaElem `json:"schema,omitempty"`
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type GetPathTemplateRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path with placeholders as key values, e.g: interface[name={iface}]
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// optional placeholder values, the response carries the rendered path if set.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+type GetPathTemplateResponse struct {
+	// schema node the path points to
+	Schema       *SchemaElem    `json:"schema,omitempty"`
+	Placeholders []*Placeholder `json:"placeholders,omitempty"`
+	Rendered     *sdcpb.Path    `json:"rendered,omitempty"`
+}
+
+type Placeholder struct {
+	Name string `json:"name,omitempty"`
+	// index of the path element and name of the key holding the placeholder
+	Elem int                   `json:"elem"`
+	Key  string                `json:"key,omitempty"`
+	Type *sdcpb.SchemaLeafType `json:"type,omitempty"`
+}
+
+type GetInstanceTemplateRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path to or through a list, the keys not set are turned into placeholders
+	Path *sdcpb.Path `json:"path,omitempty"`
+}
+
+type GetInstanceTemplateResponse struct {
+	// xpath with the type of each placeholder, e.g: /interface[name=<string(length=3..20)>]
+	Template string `json:"template,omitempty"`
+	// xpath with named placeholders, as accepted by GetPathTemplate, e.g: /interface[name={name}]
+	PathTemplate string         `json:"path-template,omitempty"`
+	Placeholders []*Placeholder `json:"placeholders,omitempty"`
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type RenderTreeRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// node the tree is rendered down from, its ancestors are rendered with
+	// the path element only. The whole schema if not set.
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// levels rendered below the path, the deeper nodes are elided as "...".
+	// unlimited if not set.
+	MaxDepth int `json:"max-depth,omitempty"`
+}
+
+type RenderTreeResponse struct {
+	// pyang -f tree style text: a "module: <name>" line per module
+	// followed by its nodes, one per line, e.g:
+	//   +--rw interface* [name]
+	//      +--rw name           interface-name
+	//      +--rw admin-state?   enumeration
+	Tree string `json:"tree,omitempty"`
+}
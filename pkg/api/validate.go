@@ -0,0 +1,68 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// kinds of the errors found validating a document
+const (
+	// the document is not well formed JSON or XML
+	DocumentErrorSyntax = "syntax"
+	// a member or element is not a node of the schema
+	DocumentErrorUnknown = "unknown"
+	// the value of a node is not of its kind, e.g. an array for a container
+	DocumentErrorStructure = "structure"
+	// a leaf or leaf-list value violates its type
+	DocumentErrorType = "type"
+	// a list entry has a missing, invalid or duplicate key
+	DocumentErrorKey = "key"
+	// a mandatory leaf is missing, a list or leaf-list has too few or too many elements
+	DocumentErrorMandatory = "mandatory"
+	// a leafref value is not a value of its target in the document
+	DocumentErrorLeafref = "leafref"
+	// a metadata annotation is unknown, misplaced or its value violates its type, RFC 7952
+	DocumentErrorAnnotation = "annotation"
+)
+
+type ValidateDocumentRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// node the document is the content of, a container or a list entry,
+	// the root if not set. The keys of the entry can be left out of the document.
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// one of "json" (RFC 7951, default) or "xml" (RFC 7950). An XML document
+	// is the element of the node of the path, or with no path a top level
+	// node or a NETCONF config or data element wrapping the top level nodes.
+	Format   string `json:"format,omitempty"`
+	Document []byte `json:"document,omitempty"`
+}
+
+type ValidateDocumentResponse struct {
+	Valid  bool             `json:"valid,omitempty"`
+	Errors []*DocumentError `json:"errors,omitempty"`
+	// more errors were found than returned
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type DocumentError struct {
+	// the instance path of the node, with the keys of the list entries
+	Path string `json:"path,omitempty"`
+	Kind string `json:"kind,omitempty"`
+	// the constraint violated by a value of the type kind,
+	// e.g. range or pattern, see ValueViolation
+	Constraint string `json:"constraint,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
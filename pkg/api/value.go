@@ -0,0 +1,49 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+type ValidateValueRequest struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	// path of a leaf or leaf-list, keys are ignored
+	Path *sdcpb.Path `json:"path,omitempty"`
+	// raw value, as in a gNMI path key or an XML document:
+	// identities can be prefixed with a module name, bits are space separated
+	// and binary values base64 encoded.
+	Value string `json:"value,omitempty"`
+}
+
+type ValidateValueResponse struct {
+	Valid bool `json:"valid,omitempty"`
+	// name of the type accepting the value,
+	// the matching member type for a union.
+	Type string `json:"type,omitempty"`
+	// the constraint violated by the value,
+	// the ones of each member type for a union.
+	Violations []*ValueViolation `json:"violations,omitempty"`
+}
+
+type ValueViolation struct {
+	// type, range, length, pattern, enumeration, identity, bits or path
+	Constraint string `json:"constraint,omitempty"`
+	// the constraint statement, e.g. the range or the pattern
+	Statement string `json:"statement,omitempty"`
+	// name of the type defining the constraint
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+}
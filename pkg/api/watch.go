@@ -0,0 +1,43 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// schema event types
+const (
+	SchemaEventAdded    = "added"
+	SchemaEventRemoved  = "removed"
+	SchemaEventReloaded = "reloaded"
+)
+
+// WatchSchemaChangesRequest selects the schemas to be notified about,
+// empty fields of a schema match any value. All schemas are watched if empty.
+type WatchSchemaChangesRequest struct {
+	Schemas []*sdcpb.Schema `json:"schemas,omitempty"`
+}
+
+// SchemaEvent notifies that a schema was added, removed or reloaded.
+// The events missed while not connected are not sent:
+// a client (re)connecting should consider its cached responses stale.
+type SchemaEvent struct {
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	Type   string        `json:"type,omitempty"`
+	Time   time.Time     `json:"time,omitempty"`
+}
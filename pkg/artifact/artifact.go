@@ -0,0 +1,501 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package artifact generates machine-readable contracts of a schema for the
+// northbound consumers: JSON Schema draft-07 and OpenAPI 3 documents describing
+// the RFC 7951 JSON encoding of its data nodes, and protobuf messages of its
+// data nodes following the ygot conventions.
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/value"
+)
+
+// artifact formats
+const (
+	FormatJSONSchema = "json-schema"
+	FormatOpenAPI    = "openapi"
+	// .proto file
+	FormatProto = "proto"
+	// serialized google.protobuf.FileDescriptorSet
+	FormatDescriptorSet = "descriptor-set"
+)
+
+// Formats lists the artifact formats.
+var Formats = []string{FormatJSONSchema, FormatOpenAPI, FormatProto, FormatDescriptorSet}
+
+// Generator generates the artifacts of the schema of a resolver.
+type Generator struct {
+	Resolver *store.Resolver
+	// Leafref returns the type of the target of the leafref type t of the leaf
+	// found at names, nil if it cannot be resolved. The leafrefs accept any value
+	// if it is not set.
+	Leafref func(ctx context.Context, names []string, t *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType
+	// document title and version, e.g. the schema name and version
+	Title   string
+	Version string
+	// package of the protobuf messages, e.g. the schema name
+	Package string
+	// set the descriptions of the nodes
+	Descriptions bool
+	// leave the state nodes out, they are read only otherwise
+	ConfigOnly bool
+
+	// OpenAPI 3.0 schema objects are a subset of JSON Schema
+	openapi bool
+}
+
+// Generate returns the artifact in format of the subtree at names,
+// the whole schema if names is empty.
+func (g *Generator) Generate(ctx context.Context, format string, names []string) ([]byte, error) {
+	switch format {
+	case FormatJSONSchema:
+		return g.jsonSchema(ctx, names)
+	case FormatOpenAPI:
+		g.openapi = true
+		return g.openAPI(ctx, names)
+	case FormatProto:
+		return g.protoSource(ctx, names)
+	case FormatDescriptorSet:
+		return g.descriptorSet(ctx, names)
+	}
+	return nil, fmt.Errorf("unknown artifact format %q, expecting one of %s", format, strings.Join(Formats, ", "))
+}
+
+// jsonSchema is the subset of the JSON Schema keywords describing the RFC 7951 values,
+// which is also an OpenAPI 3.0 schema object but for the types and encodings noted.
+type jsonSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	// OpenAPI only, the null type of JSON Schema
+	Nullable bool `json:"nullable,omitempty"`
+	// JSON Schema only, OpenAPI uses format byte
+	ContentEncoding string                 `json:"contentEncoding,omitempty"`
+	Format          string                 `json:"format,omitempty"`
+	Enum            []interface{}          `json:"enum,omitempty"`
+	Pattern         string                 `json:"pattern,omitempty"`
+	MinLength       *uint64                `json:"minLength,omitempty"`
+	MaxLength       *uint64                `json:"maxLength,omitempty"`
+	Minimum         json.Number            `json:"minimum,omitempty"`
+	Maximum         json.Number            `json:"maximum,omitempty"`
+	Properties      map[string]*jsonSchema `json:"properties,omitempty"`
+	Required        []string               `json:"required,omitempty"`
+	// false for the objects of the data nodes
+	AdditionalProperties *bool         `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema   `json:"items,omitempty"`
+	MinItems             *uint64       `json:"minItems,omitempty"`
+	MaxItems             *uint64       `json:"maxItems,omitempty"`
+	AllOf                []*jsonSchema `json:"allOf,omitempty"`
+	AnyOf                []*jsonSchema `json:"anyOf,omitempty"`
+	Not                  *jsonSchema   `json:"not,omitempty"`
+	Default              interface{}   `json:"default,omitempty"`
+	ReadOnly             bool          `json:"readOnly,omitempty"`
+}
+
+func (g *Generator) jsonSchema(ctx context.Context, names []string) ([]byte, error) {
+	var js *jsonSchema
+	var err error
+	if len(names) == 0 || len(names) == 1 && g.isModule(ctx, names[0]) {
+		js, err = g.modules(ctx, names)
+	} else {
+		js, err = g.node(ctx, names)
+	}
+	if err != nil {
+		return nil, err
+	}
+	js.Schema = "http://json-schema.org/draft-07/schema#"
+	js.Title = g.Title
+	return json.MarshalIndent(js, "", "  ")
+}
+
+func (g *Generator) isModule(ctx context.Context, name string) bool {
+	root, err := g.Resolver.Get(ctx, nil)
+	if err != nil {
+		return false
+	}
+	for _, m := range root.GetContainer().GetChildren() {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// modules returns the object of the top level nodes of the modules,
+// all of them if names is empty.
+func (g *Generator) modules(ctx context.Context, names []string) (*jsonSchema, error) {
+	modules := names
+	if len(modules) == 0 {
+		root, err := g.Resolver.Get(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		modules = append([]string(nil), root.GetContainer().GetChildren()...)
+		sort.Strings(modules)
+	}
+	js := object()
+	for _, m := range modules {
+		se, err := g.Resolver.Get(ctx, []string{m})
+		if err != nil {
+			return nil, err
+		}
+		// the top level nodes are qualified with their module
+		err = g.members(ctx, js, []string{m}, "", se.GetContainer())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return js, nil
+}
+
+func object() *jsonSchema {
+	no := false
+	return &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}, AdditionalProperties: &no}
+}
+
+// node returns the schema of the value of the node at names.
+func (g *Generator) node(ctx context.Context, names []string) (*jsonSchema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	se, err := g.Resolver.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		return g.container(ctx, names, se.Container)
+	case *sdcpb.SchemaElem_Field:
+		return g.leaf(ctx, names, se.Field), nil
+	case *sdcpb.SchemaElem_Leaflist:
+		return g.leafList(ctx, names, se.Leaflist), nil
+	}
+	return &jsonSchema{}, nil
+}
+
+// container returns an object, an array of objects for a list.
+func (g *Generator) container(ctx context.Context, names []string, cs *sdcpb.ContainerSchema) (*jsonSchema, error) {
+	m, err := g.Resolver.Module(ctx, &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Container{Container: cs}})
+	if err != nil {
+		return nil, err
+	}
+	js := object()
+	if err := g.members(ctx, js, names, m, cs); err != nil {
+		return nil, err
+	}
+	if len(cs.GetKeys()) == 0 {
+		g.annotate(js, cs.GetDescription(), cs.GetIsState())
+		return js, nil
+	}
+	arr := &jsonSchema{Type: "array", Items: js}
+	arr.MinItems, arr.MaxItems = elements(cs.GetMinElements(), cs.GetMaxElements())
+	g.annotate(arr, cs.GetDescription(), cs.GetIsState())
+	return arr, nil
+}
+
+// members adds the child nodes of cs to the properties of object js, their names
+// qualified with their module if it is not module. The keys, and the mandatory
+// leaves not in a choice, are required. The members of the cases of a choice are
+// all properties.
+func (g *Generator) members(ctx context.Context, js *jsonSchema, names []string, module string, cs *sdcpb.ContainerSchema) error {
+	add := func(se *sdcpb.SchemaElem, ms *jsonSchema, required bool) error {
+		m, err := g.Resolver.Module(ctx, se)
+		if err != nil {
+			return err
+		}
+		name := store.Name(se)
+		if m != module {
+			name = m + ":" + name
+		}
+		js.Properties[name] = ms
+		if required {
+			js.Required = append(js.Required, name)
+		}
+		return nil
+	}
+	child := func(name string) []string {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		return append(cnames, name)
+	}
+	for _, k := range cs.GetKeys() {
+		se := &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: k}}
+		if err := add(se, g.leaf(ctx, child(k.GetName()), k), true); err != nil {
+			return err
+		}
+	}
+	for _, f := range cs.GetFields() {
+		if g.ConfigOnly && f.GetIsState() {
+			continue
+		}
+		se := &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: f}}
+		required := f.GetIsMandatory() && f.GetChoiceInfo() == nil
+		if err := add(se, g.leaf(ctx, child(f.GetName()), f), required); err != nil {
+			return err
+		}
+	}
+	for _, ll := range cs.GetLeaflists() {
+		if g.ConfigOnly && ll.GetIsState() {
+			continue
+		}
+		se := &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Leaflist{Leaflist: ll}}
+		if err := add(se, g.leafList(ctx, child(ll.GetName()), ll), false); err != nil {
+			return err
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		cnames := child(c)
+		se, err := g.Resolver.Get(ctx, cnames)
+		if err != nil {
+			return err
+		}
+		if g.ConfigOnly && se.GetContainer().GetIsState() {
+			continue
+		}
+		ms, err := g.container(ctx, cnames, se.GetContainer())
+		if err != nil {
+			return err
+		}
+		if err := add(se, ms, false); err != nil {
+			return err
+		}
+	}
+	sort.Strings(js.Required)
+	return nil
+}
+
+func (g *Generator) annotate(js *jsonSchema, description string, state bool) {
+	if g.Descriptions {
+		js.Description = description
+	}
+	js.ReadOnly = state
+}
+
+// elements returns the min and max items of a list or leaf-list, unbounded if not set.
+func elements(min, max uint64) (*uint64, *uint64) {
+	var minItems, maxItems *uint64
+	if min > 0 {
+		minItems = &min
+	}
+	if max > 0 && max != math.MaxUint64 {
+		maxItems = &max
+	}
+	return minItems, maxItems
+}
+
+func (g *Generator) leaf(ctx context.Context, names []string, ls *sdcpb.LeafSchema) *jsonSchema {
+	js := g.leafType(ctx, names, ls.GetType())
+	if d := ls.GetDefault(); d != "" {
+		js.Default = defaultValue(ls.GetType(), d)
+	}
+	g.annotate(js, ls.GetDescription(), ls.GetIsState())
+	return js
+}
+
+func (g *Generator) leafList(ctx context.Context, names []string, lls *sdcpb.LeafListSchema) *jsonSchema {
+	js := &jsonSchema{Type: "array", Items: g.leafType(ctx, names, lls.GetType())}
+	js.MinItems, js.MaxItems = elements(lls.GetMinElements(), lls.GetMaxElements())
+	if len(lls.GetDefaults()) > 0 {
+		defaults := make([]interface{}, 0, len(lls.GetDefaults()))
+		for _, d := range lls.GetDefaults() {
+			defaults = append(defaults, defaultValue(lls.GetType(), d))
+		}
+		js.Default = defaults
+	}
+	g.annotate(js, lls.GetDescription(), lls.GetIsState())
+	return js
+}
+
+// identifierRegexp matches the module prefix of an identity
+const identifierRegexp = `[a-zA-Z_][a-zA-Z0-9_.-]*`
+
+// leafType returns the schema of the RFC 7951 values of type t: the integers of
+// up to 32 bits are numbers, the 64 bits ones and the decimals strings.
+func (g *Generator) leafType(ctx context.Context, names []string, t *sdcpb.SchemaLeafType) *jsonSchema {
+	switch typ := t.GetType(); typ {
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32":
+		return rangesSchema(typ, t.GetRange())
+	case "int64", "uint64":
+		return &jsonSchema{Type: "string", Pattern: `^[-+]?[0-9]+$`}
+	case "decimal64":
+		return &jsonSchema{Type: "string", Pattern: `^[-+]?[0-9]+(\.[0-9]+)?$`}
+	case "boolean":
+		return &jsonSchema{Type: "boolean"}
+	case "empty":
+		// [null]
+		js := &jsonSchema{Type: "array", Items: &jsonSchema{Type: "null"}}
+		if g.openapi {
+			js.Items = &jsonSchema{Nullable: true, Enum: []interface{}{nil}}
+		}
+		one := uint64(1)
+		js.MinItems, js.MaxItems = &one, &one
+		return js
+	case "enumeration":
+		js := &jsonSchema{Type: "string"}
+		for _, v := range t.GetValues() {
+			js.Enum = append(js.Enum, v)
+		}
+		return js
+	case "identityref":
+		// the identities are qualified with their module, or not if it is the one of the leaf
+		ids := make([]string, 0, len(t.GetValues()))
+		for _, v := range t.GetValues() {
+			ids = append(ids, regexp.QuoteMeta(v))
+		}
+		return &jsonSchema{Type: "string", Pattern: `^(` + identifierRegexp + `:)?(` + strings.Join(ids, "|") + `)$`}
+	case "bits":
+		bits := make([]string, 0, len(t.GetValues()))
+		for _, v := range t.GetValues() {
+			bits = append(bits, regexp.QuoteMeta(v))
+		}
+		if len(bits) == 0 {
+			return &jsonSchema{Type: "string"}
+		}
+		return &jsonSchema{Type: "string", Pattern: `^ *((` + strings.Join(bits, "|") + `) *)*$`}
+	case "binary":
+		js := &jsonSchema{Type: "string", ContentEncoding: "base64"}
+		if g.openapi {
+			js.ContentEncoding, js.Format = "", "byte"
+		}
+		lengthSchema(js, t.GetLength())
+		return js
+	case "union":
+		js := &jsonSchema{}
+		for _, ut := range t.GetUnionTypes() {
+			js.AnyOf = append(js.AnyOf, g.leafType(ctx, names, ut))
+		}
+		return js
+	case "leafref":
+		if g.Leafref == nil {
+			return &jsonSchema{}
+		}
+		rt := g.Leafref(ctx, names, t)
+		if rt == nil {
+			return &jsonSchema{}
+		}
+		return g.leafType(ctx, names, rt)
+	case "string":
+		js := &jsonSchema{Type: "string"}
+		lengthSchema(js, t.GetLength())
+		patterns := make([]*jsonSchema, 0, len(t.GetPatterns()))
+		// the patterns are translated to Go regular expressions, which JSON Schema
+		// regular expressions (ECMA 262, with the u flag for \p) read the same way
+		for _, p := range t.GetPatterns() {
+			re, err := value.TranslatePattern(p.GetPattern())
+			if err != nil {
+				// not expressible, the value is not constrained by the pattern
+				continue
+			}
+			ps := &jsonSchema{Pattern: re}
+			if p.GetInverted() {
+				ps = &jsonSchema{Not: ps}
+			}
+			patterns = append(patterns, ps)
+		}
+		switch {
+		case len(patterns) == 1 && patterns[0].Not == nil:
+			js.Pattern = patterns[0].Pattern
+		case len(patterns) > 0:
+			js.AllOf = patterns
+		}
+		return js
+	}
+	// instance-identifier
+	return &jsonSchema{Type: "string"}
+}
+
+// integer bounds of the types
+var typeBounds = map[string][2]int64{
+	"int8":   {math.MinInt8, math.MaxInt8},
+	"int16":  {math.MinInt16, math.MaxInt16},
+	"int32":  {math.MinInt32, math.MaxInt32},
+	"uint8":  {0, math.MaxUint8},
+	"uint16": {0, math.MaxUint16},
+	"uint32": {0, math.MaxUint32},
+}
+
+// rangesSchema returns an integer in the ranges of expression expr, e.g. 1..10|20,
+// min and max being the bounds of typ.
+func rangesSchema(typ, expr string) *jsonSchema {
+	b := typeBounds[typ]
+	bound := func(s string, def int64) json.Number {
+		s = strings.TrimSpace(s)
+		if s == "min" || s == "max" || s == "" {
+			return json.Number(strconv.FormatInt(def, 10))
+		}
+		return json.Number(strings.TrimPrefix(s, "+"))
+	}
+	parts := strings.Split(expr, "|")
+	ranges := make([]*jsonSchema, 0, len(parts))
+	for _, r := range parts {
+		lo, hi, ok := strings.Cut(strings.TrimSpace(r), "..")
+		if !ok {
+			hi = lo
+		}
+		ranges = append(ranges, &jsonSchema{Minimum: bound(lo, b[0]), Maximum: bound(hi, b[1])})
+	}
+	if len(ranges) == 1 {
+		ranges[0].Type = "integer"
+		return ranges[0]
+	}
+	return &jsonSchema{Type: "integer", AnyOf: ranges}
+}
+
+// lengthSchema sets the min and max length of a single length range expr,
+// the length of the values not being constrained for several.
+func lengthSchema(js *jsonSchema, expr string) {
+	if expr == "" || strings.Contains(expr, "|") {
+		return
+	}
+	lo, hi, ok := strings.Cut(expr, "..")
+	if !ok {
+		hi = lo
+	}
+	if n, err := strconv.ParseUint(strings.TrimSpace(lo), 10, 64); err == nil && n > 0 {
+		js.MinLength = &n
+	}
+	if n, err := strconv.ParseUint(strings.TrimSpace(hi), 10, 64); err == nil {
+		js.MaxLength = &n
+	}
+}
+
+// defaultValue returns default d of a leaf of type t as an RFC 7951 value.
+func defaultValue(t *sdcpb.SchemaLeafType, d string) interface{} {
+	switch t.GetType() {
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32":
+		if _, err := strconv.ParseInt(d, 10, 64); err == nil {
+			return json.Number(strings.TrimPrefix(d, "+"))
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(d); err == nil {
+			return b
+		}
+	}
+	return d
+}
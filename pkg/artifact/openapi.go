@@ -0,0 +1,227 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+	// RESTCONF data resources and media type, RFC 8040
+	dataRoot      = "/restconf/data"
+	yangDataMedia = "application/yang-data+json"
+)
+
+var componentRegexp = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+type openAPIDoc struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       openAPIInfo          `json:"info"`
+	Paths      map[string]*pathItem `json:"paths"`
+	Components openAPIComponents    `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*jsonSchema `json:"schemas"`
+}
+
+type pathItem struct {
+	Parameters []*parameter `json:"parameters,omitempty"`
+	Get        *operation   `json:"get,omitempty"`
+	Put        *operation   `json:"put,omitempty"`
+	Patch      *operation   `json:"patch,omitempty"`
+	Delete     *operation   `json:"delete,omitempty"`
+}
+
+type parameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required"`
+	Schema   *jsonSchema `json:"schema"`
+}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// resource is a RESTCONF data resource of a node.
+type resource struct {
+	// RFC 7951 member name of the node
+	member string
+	path   string
+	params []*parameter
+	schema *jsonSchema
+}
+
+// openAPI returns a document with a RESTCONF data resource per top level node of
+// the modules, or the one of the node at names, the schemas of their values
+// being components. The config nodes can be read, replaced, merged and deleted,
+// the state ones read.
+func (g *Generator) openAPI(ctx context.Context, names []string) ([]byte, error) {
+	var rs []*resource
+	var err error
+	if len(names) == 0 || len(names) == 1 && g.isModule(ctx, names[0]) {
+		var js *jsonSchema
+		js, err = g.modules(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		for member, ms := range js.Properties {
+			rs = append(rs, &resource{member: member, path: dataRoot + "/" + member, schema: ms})
+		}
+	} else {
+		var r *resource
+		r, err = g.resource(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].path < rs[j].path })
+	doc := &openAPIDoc{
+		OpenAPI:    openAPIVersion,
+		Info:       openAPIInfo{Title: g.Title, Version: g.Version},
+		Paths:      make(map[string]*pathItem, len(rs)),
+		Components: openAPIComponents{Schemas: make(map[string]*jsonSchema, len(rs))},
+	}
+	for _, r := range rs {
+		component := componentName(r.path)
+		doc.Components.Schemas[component] = r.schema
+		body := map[string]*mediaType{yangDataMedia: {Schema: &jsonSchema{
+			Type:       "object",
+			Properties: map[string]*jsonSchema{r.member: {Ref: "#/components/schemas/" + component}},
+		}}}
+		item := &pathItem{
+			Parameters: r.params,
+			Get: &operation{
+				OperationID: "get." + component,
+				Responses: map[string]*response{
+					"200": {Description: "the " + r.member + " data", Content: body},
+				},
+			},
+		}
+		if !r.schema.ReadOnly {
+			updated := map[string]*response{"204": {Description: r.member + " updated"}}
+			item.Put = &operation{
+				OperationID: "put." + component,
+				RequestBody: &requestBody{Required: true, Content: body},
+				Responses:   updated,
+			}
+			item.Patch = &operation{
+				OperationID: "patch." + component,
+				RequestBody: &requestBody{Required: true, Content: body},
+				Responses:   updated,
+			}
+			item.Delete = &operation{
+				OperationID: "delete." + component,
+				Responses:   map[string]*response{"204": {Description: r.member + " deleted"}},
+			}
+		}
+		doc.Paths[r.path] = item
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// componentName returns the name of the component of the resource at path,
+// component names are limited to [a-zA-Z0-9._-].
+func componentName(path string) string {
+	return strings.Trim(componentRegexp.ReplaceAllString(strings.TrimPrefix(path, dataRoot), "."), ".")
+}
+
+// resource returns the data resource of the node at names: the keys of
+// the lists on its path are path parameters named <list>-<key>.
+func (g *Generator) resource(ctx context.Context, names []string) (*resource, error) {
+	r := &resource{path: dataRoot}
+	module := ""
+	for i := range names {
+		// a path can start with the module element
+		if i == 0 && len(names) > 1 && g.isModule(ctx, names[0]) {
+			continue
+		}
+		se, err := g.Resolver.Get(ctx, names[:i+1])
+		if err != nil {
+			return nil, err
+		}
+		m, err := g.Resolver.Module(ctx, se)
+		if err != nil {
+			return nil, err
+		}
+		name := store.Name(se)
+		if m != module {
+			name = m + ":" + name
+		}
+		module = m
+		r.path += "/" + name
+		// the member of a resource body is qualified with its module
+		r.member = m + ":" + store.Name(se)
+		if i == len(names)-1 {
+			break
+		}
+		keys := se.GetContainer().GetKeys()
+		if len(keys) == 0 {
+			continue
+		}
+		vars := make([]string, 0, len(keys))
+		for _, k := range keys {
+			p := &parameter{
+				Name:     store.Name(se) + "-" + k.GetName(),
+				In:       "path",
+				Required: true,
+				Schema:   g.leafType(ctx, append(append([]string(nil), names[:i+1]...), k.GetName()), k.GetType()),
+			}
+			// the key values are encoded in the path
+			if p.Schema.Type != "string" {
+				p.Schema = &jsonSchema{Type: "string"}
+			}
+			r.params = append(r.params, p)
+			vars = append(vars, "{"+p.Name+"}")
+		}
+		r.path += "=" + strings.Join(vars, ",")
+	}
+	var err error
+	r.schema, err = g.node(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,698 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	// the leaves are well-known wrappers, ygot uses its own ywrapper ones
+	// which tools do not know without the ygot protos
+	wrappersFile    = "google/protobuf/wrappers.proto"
+	wrappersPackage = ".google.protobuf."
+	// message of the top level nodes, as in ygot
+	rootMessage = "Device"
+	// field numbers, the reserved range is the one of the protobuf implementation
+	maxFieldNumber     = 1<<29 - 1
+	firstReservedField = 19000
+	lastReservedField  = 19999
+)
+
+// protoMessage is a message generated for a container or a list entry,
+// or the file scope for the top level messages.
+type protoMessage struct {
+	name string
+	// fully qualified, with a leading dot
+	fullName string
+	comment  string
+	fields   []*protoField
+	oneofs   []string
+	messages []*protoMessage
+	enums    []*protoEnum
+	// names defined in the scope of the message: fields, oneofs,
+	// nested messages and enums, and the values of the enums
+	symbols map[string]bool
+	numbers map[int32]bool
+}
+
+type protoField struct {
+	name     string
+	comment  string
+	number   int32
+	repeated bool
+	typ      descriptorpb.FieldDescriptorProto_Type
+	// fully qualified name of a message or an enum
+	typeName string
+	// index of the oneof of the field, -1 if not in one
+	oneof int
+}
+
+type protoEnum struct {
+	name     string
+	fullName string
+	values   []string
+}
+
+func newProtoMessage(name, fullName, comment string) *protoMessage {
+	return &protoMessage{
+		name:     name,
+		fullName: fullName,
+		comment:  comment,
+		symbols:  make(map[string]bool),
+		numbers:  make(map[int32]bool),
+	}
+}
+
+// symbol returns name, suffixed if it is already defined in the scope of m.
+func (m *protoMessage) symbol(name string) string {
+	s := name
+	for i := 2; m.symbols[s] || m.symbols["json:"+jsonName(s)]; i++ {
+		s = fmt.Sprintf("%s_%d", name, i)
+	}
+	m.symbols[s] = true
+	// proto3 fields must not have the same JSON name either
+	m.symbols["json:"+jsonName(s)] = true
+	return s
+}
+
+// message adds a nested message to m.
+func (m *protoMessage) message(name, comment string) *protoMessage {
+	name = m.symbol(name)
+	nm := newProtoMessage(name, m.fullName+"."+name, comment)
+	m.messages = append(m.messages, nm)
+	return nm
+}
+
+// number returns the field number of the node at the schema path p: as in ygot, a
+// hash of the path, so that its number does not change when the schema evolves.
+// It is the next free number if it is reserved or taken by another field.
+func (m *protoMessage) number(p string) int32 {
+	h := fnv.New32()
+	h.Write([]byte(p))
+	n := int32(h.Sum32() & maxFieldNumber)
+	for n == 0 || n >= firstReservedField && n <= lastReservedField || m.numbers[n] {
+		n++
+		if n > maxFieldNumber {
+			n = 1
+		}
+	}
+	m.numbers[n] = true
+	return n
+}
+
+// proto returns the messages of the subtree at names as the nested messages of the
+// file scope: the Device message of the top level nodes, or the message of the
+// container or list at names.
+//
+// As in ygot, the containers and list entries are messages, the choices flattened,
+// and a list is a repeated message of its keys and its entry. The enumerations and
+// identityrefs are enums, the unions oneofs, and the other leaves wrappers telling
+// an unset leaf from a zero value.
+func (g *Generator) proto(ctx context.Context, names []string) (*protoMessage, error) {
+	file := newProtoMessage("", "."+g.protoPackage(), "")
+	if len(names) == 0 || len(names) == 1 && g.isModule(ctx, names[0]) {
+		modules := names
+		if len(modules) == 0 {
+			root, err := g.Resolver.Get(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			modules = append([]string(nil), root.GetContainer().GetChildren()...)
+			sort.Strings(modules)
+		}
+		device := file.message(rootMessage, "")
+		for _, m := range modules {
+			se, err := g.Resolver.Get(ctx, []string{m})
+			if err != nil {
+				return nil, err
+			}
+			if err := g.protoMembers(ctx, device, []string{m}, se.GetContainer()); err != nil {
+				return nil, err
+			}
+		}
+		return file, nil
+	}
+	se, err := g.Resolver.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	cs := se.GetContainer()
+	if cs == nil {
+		return nil, fmt.Errorf("%s is not a container or a list, it has no message", strings.Join(names, "/"))
+	}
+	// the schema paths the field numbers are derived from start with the module
+	if !g.isModule(ctx, names[0]) {
+		m, err := g.Resolver.Module(ctx, se)
+		if err != nil {
+			return nil, err
+		}
+		names = append([]string{m}, names...)
+	}
+	_, err = g.protoContainer(ctx, file, names, cs)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// protoPackage returns the package of the messages, the protobuf identifier of the package name.
+func (g *Generator) protoPackage() string {
+	if g.Package == "" {
+		return "schema"
+	}
+	var sb strings.Builder
+	for i, r := range strings.ToLower(g.Package) {
+		switch {
+		case r >= 'a' && r <= 'z' || r == '_' || r >= '0' && r <= '9' && i > 0:
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			sb.WriteString("_")
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+func (g *Generator) protoContainer(ctx context.Context, parent *protoMessage, names []string, cs *sdcpb.ContainerSchema) (*protoMessage, error) {
+	m := parent.message(camelCase(cs.GetName()), g.description(cs.GetDescription()))
+	if err := g.protoMembers(ctx, m, names, cs); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (g *Generator) description(d string) string {
+	if !g.Descriptions {
+		return ""
+	}
+	return d
+}
+
+// protoMembers adds the fields of the child nodes of cs to m, sorted by name.
+func (g *Generator) protoMembers(ctx context.Context, m *protoMessage, names []string, cs *sdcpb.ContainerSchema) error {
+	type member struct {
+		name string
+		se   *sdcpb.SchemaElem
+	}
+	members := make([]member, 0, len(cs.GetKeys())+len(cs.GetFields())+len(cs.GetLeaflists())+len(cs.GetChildren()))
+	for _, k := range cs.GetKeys() {
+		members = append(members, member{k.GetName(), &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: k}}})
+	}
+	for _, f := range cs.GetFields() {
+		if !g.ConfigOnly || !f.GetIsState() {
+			members = append(members, member{f.GetName(), &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: f}}})
+		}
+	}
+	for _, ll := range cs.GetLeaflists() {
+		if !g.ConfigOnly || !ll.GetIsState() {
+			members = append(members, member{ll.GetName(), &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Leaflist{Leaflist: ll}}})
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		members = append(members, member{name: c})
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].name < members[j].name })
+	for _, mb := range members {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, mb.name)
+		path := "/" + strings.Join(cnames, "/")
+		fname := protoName(mb.name)
+		switch se := mb.se.GetSchema().(type) {
+		case *sdcpb.SchemaElem_Field:
+			g.protoLeaf(ctx, m, cnames, fname, path, se.Field.GetType(), g.description(se.Field.GetDescription()))
+		case *sdcpb.SchemaElem_Leaflist:
+			g.protoLeafList(ctx, m, cnames, fname, path, se.Leaflist)
+		default:
+			cse, err := g.Resolver.Get(ctx, cnames)
+			if err != nil {
+				return err
+			}
+			ccs := cse.GetContainer()
+			if g.ConfigOnly && ccs.GetIsState() {
+				continue
+			}
+			if err := g.protoChild(ctx, m, cnames, fname, path, ccs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// protoChild adds the field of container or list cs to m.
+func (g *Generator) protoChild(ctx context.Context, m *protoMessage, names []string, fname, path string, cs *sdcpb.ContainerSchema) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	entry, err := g.protoContainer(ctx, m, names, cs)
+	if err != nil {
+		return err
+	}
+	f := &protoField{
+		name:     fname,
+		comment:  entry.comment,
+		typ:      descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		typeName: entry.fullName,
+		oneof:    -1,
+	}
+	if len(cs.GetKeys()) > 0 {
+		// the keys, numbered in order, and the entry
+		key := m.message(entry.name+"Key", "")
+		n := int32(1)
+		for _, k := range cs.GetKeys() {
+			knames := append(append([]string(nil), names...), k.GetName())
+			t := g.protoLeafType(ctx, knames, k.GetType())
+			kname := key.symbol(protoName(k.GetName()))
+			if t.GetType() == "union" {
+				n = g.protoUnion(ctx, key, knames, kname, path+"/"+k.GetName(), t, n)
+				continue
+			}
+			kf := &protoField{name: kname, number: n, oneof: -1}
+			g.protoScalar(key, knames, kf, t, false)
+			key.fields = append(key.fields, kf)
+			key.numbers[n] = true
+			n++
+		}
+		key.numbers[n] = true
+		key.fields = append(key.fields, &protoField{
+			name:     key.symbol(fname),
+			number:   n,
+			typ:      descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+			typeName: entry.fullName,
+			oneof:    -1,
+		})
+		f.repeated = true
+		f.typeName = key.fullName
+	}
+	f.name = m.symbol(fname)
+	f.number = m.number(path)
+	m.fields = append(m.fields, f)
+	return nil
+}
+
+func (g *Generator) protoLeaf(ctx context.Context, m *protoMessage, names []string, fname, path string, t *sdcpb.SchemaLeafType, comment string) {
+	t = g.protoLeafType(ctx, names, t)
+	if t.GetType() == "union" {
+		g.protoUnion(ctx, m, names, m.symbol(fname), path, t, 0)
+		return
+	}
+	f := &protoField{name: m.symbol(fname), comment: comment, number: m.number(path), oneof: -1}
+	g.protoScalar(m, names, f, t, true)
+	m.fields = append(m.fields, f)
+}
+
+// protoLeafList adds the repeated field of lls to m, a repeated message of
+// a oneof for a union.
+func (g *Generator) protoLeafList(ctx context.Context, m *protoMessage, names []string, fname, path string, lls *sdcpb.LeafListSchema) {
+	t := g.protoLeafType(ctx, names, lls.GetType())
+	f := &protoField{name: m.symbol(fname), comment: g.description(lls.GetDescription()), number: m.number(path), repeated: true, oneof: -1}
+	if t.GetType() == "union" {
+		um := m.message(camelCase(lls.GetName())+"Union", "")
+		g.protoUnion(ctx, um, names, um.symbol("value"), path, t, 0)
+		f.typ, f.typeName = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, um.fullName
+	} else {
+		// a repeated field tells an empty leaf-list already, the values are not wrapped
+		g.protoScalar(m, names, f, t, false)
+	}
+	m.fields = append(m.fields, f)
+}
+
+// protoLeafType returns t with its leafrefs resolved and its unions flattened.
+func (g *Generator) protoLeafType(ctx context.Context, names []string, t *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+	switch t.GetType() {
+	case "leafref":
+		if g.Leafref == nil {
+			return &sdcpb.SchemaLeafType{Type: "string"}
+		}
+		rt := g.Leafref(ctx, names, t)
+		if rt == nil || rt.GetType() == "leafref" {
+			return &sdcpb.SchemaLeafType{Type: "string"}
+		}
+		return g.protoLeafType(ctx, names, rt)
+	case "union":
+		ut := &sdcpb.SchemaLeafType{Type: "union"}
+		for _, mt := range t.GetUnionTypes() {
+			mt = g.protoLeafType(ctx, names, mt)
+			if mt.GetType() == "union" {
+				ut.UnionTypes = append(ut.UnionTypes, mt.GetUnionTypes()...)
+			} else {
+				ut.UnionTypes = append(ut.UnionTypes, mt)
+			}
+		}
+		return ut
+	}
+	return t
+}
+
+// protoUnion adds the oneof of union t to m, a field of each protobuf type of its
+// members, numbered in sequence from first if set, from their path otherwise, and
+// returns the next number. The enumerations and identityrefs of the union are one enum.
+func (g *Generator) protoUnion(ctx context.Context, m *protoMessage, names []string, oneof, path string, t *sdcpb.SchemaLeafType, first int32) int32 {
+	idx := len(m.oneofs)
+	m.oneofs = append(m.oneofs, oneof)
+	enum := &sdcpb.SchemaLeafType{Type: "enumeration"}
+	var members []*sdcpb.SchemaLeafType
+	for _, mt := range t.GetUnionTypes() {
+		if mt.GetType() == "enumeration" || mt.GetType() == "identityref" {
+			if len(enum.Values) == 0 {
+				members = append(members, enum)
+			}
+			enum.Values = append(enum.Values, mt.GetValues()...)
+			continue
+		}
+		members = append(members, mt)
+	}
+	seen := make(map[string]bool, len(members))
+	for _, mt := range members {
+		f := &protoField{oneof: idx}
+		g.protoScalar(m, append(names[:len(names):len(names)], oneof), f, mt, false)
+		suffix := strings.ToLower(strings.TrimPrefix(f.typ.String(), "TYPE_"))
+		if seen[suffix] {
+			continue
+		}
+		seen[suffix] = true
+		f.name = m.symbol(oneof + "_" + suffix)
+		if first > 0 {
+			// in a key message
+			f.number = first
+			m.numbers[first] = true
+			first++
+		} else {
+			f.number = m.number(path + "/" + suffix)
+		}
+		m.fields = append(m.fields, f)
+	}
+	return first
+}
+
+// protoScalar sets the type of f for type t, an enum of m for an enumeration or an
+// identityref, named after the last of names. The other types are wrappers if wrapped.
+func (g *Generator) protoScalar(m *protoMessage, names []string, f *protoField, t *sdcpb.SchemaLeafType, wrapped bool) {
+	var typ descriptorpb.FieldDescriptorProto_Type
+	var wrapper string
+	switch t.GetType() {
+	case "int8", "int16", "int32":
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_INT32, "Int32Value"
+	case "int64":
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_INT64, "Int64Value"
+	case "uint8", "uint16", "uint32":
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_UINT32, "UInt32Value"
+	case "uint64":
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_UINT64, "UInt64Value"
+	case "boolean", "empty":
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_BOOL, "BoolValue"
+	case "binary":
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_BYTES, "BytesValue"
+	case "enumeration", "identityref":
+		// the zero value of an enum is unset
+		e := m.enum(names[len(names)-1], t.GetValues())
+		f.typ, f.typeName = descriptorpb.FieldDescriptorProto_TYPE_ENUM, e.fullName
+		return
+	default:
+		// decimal64, bits and instance-identifier values are strings, as in RFC 7951
+		typ, wrapper = descriptorpb.FieldDescriptorProto_TYPE_STRING, "StringValue"
+	}
+	if !wrapped {
+		f.typ = typ
+		return
+	}
+	f.typ, f.typeName = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, wrappersPackage+wrapper
+}
+
+// enum adds the enum of the values of the leaf named leaf to m, its values prefixed
+// with the leaf name as in ygot, the enum values being in the scope of m.
+func (m *protoMessage) enum(leaf string, values []string) *protoEnum {
+	name := m.symbol(camelCase(leaf))
+	e := &protoEnum{name: name, fullName: m.fullName + "." + name}
+	prefix := upperSnakeCase(leaf)
+	e.values = append(e.values, m.symbol(prefix+"_UNSET"))
+	for _, v := range values {
+		e.values = append(e.values, m.symbol(prefix+"_"+upperSnakeCase(v)))
+	}
+	m.enums = append(m.enums, e)
+	return e
+}
+
+// camelCase returns the message name of YANG identifier s, e.g. Ipv4Address for ipv4-address.
+func camelCase(s string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range s {
+		switch {
+		case r == '-' || r == '_' || r == '.':
+			upper = true
+		case upper:
+			sb.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// protoName returns the field name of YANG identifier s, e.g. ipv4_address for ipv4-address.
+func protoName(s string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(s)
+}
+
+// upperSnakeCase returns s in upper case, the characters not allowed in an
+// identifier replaced with _.
+func upperSnakeCase(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteByte('_')
+	}
+	return sb.String()
+}
+
+// jsonName returns the proto3 JSON name of field name.
+func jsonName(name string) string {
+	var sb strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			sb.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// fileDescriptor returns the proto3 file of the messages of file, checked to be valid.
+func (g *Generator) fileDescriptor(file *protoMessage) (*descriptorpb.FileDescriptorProto, error) {
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(g.protoPackage() + ".proto"),
+		Package: proto.String(g.protoPackage()),
+		Syntax:  proto.String("proto3"),
+	}
+	if usesWrappers(file) {
+		fd.Dependency = []string{wrappersFile}
+	}
+	for _, m := range file.messages {
+		fd.MessageType = append(fd.MessageType, m.descriptor())
+	}
+	if _, err := protodesc.NewFile(fd, protoregistry.GlobalFiles); err != nil {
+		return nil, fmt.Errorf("invalid proto descriptor: %w", err)
+	}
+	return fd, nil
+}
+
+func usesWrappers(m *protoMessage) bool {
+	for _, f := range m.fields {
+		if strings.HasPrefix(f.typeName, wrappersPackage) {
+			return true
+		}
+	}
+	for _, nm := range m.messages {
+		if usesWrappers(nm) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *protoMessage) descriptor() *descriptorpb.DescriptorProto {
+	d := &descriptorpb.DescriptorProto{Name: proto.String(m.name)}
+	for _, e := range m.enums {
+		ed := &descriptorpb.EnumDescriptorProto{Name: proto.String(e.name)}
+		for i, v := range e.values {
+			ed.Value = append(ed.Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String(v), Number: proto.Int32(int32(i))})
+		}
+		d.EnumType = append(d.EnumType, ed)
+	}
+	for _, nm := range m.messages {
+		d.NestedType = append(d.NestedType, nm.descriptor())
+	}
+	for _, o := range m.oneofs {
+		d.OneofDecl = append(d.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(o)})
+	}
+	for _, f := range m.fields {
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(f.name),
+			JsonName: proto.String(jsonName(f.name)),
+			Number:   proto.Int32(f.number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     f.typ.Enum(),
+		}
+		if f.repeated {
+			fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		}
+		if f.typeName != "" {
+			fd.TypeName = proto.String(f.typeName)
+		}
+		if f.oneof >= 0 {
+			fd.OneofIndex = proto.Int32(int32(f.oneof))
+		}
+		d.Field = append(d.Field, fd)
+	}
+	return d
+}
+
+// protoSource returns the .proto file of the messages of file.
+func (g *Generator) protoSource(ctx context.Context, names []string) ([]byte, error) {
+	file, err := g.proto(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := g.fileDescriptor(file); err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by schema-server from the %s schema. DO NOT EDIT.\n\n", g.Title)
+	sb.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&sb, "package %s;\n", g.protoPackage())
+	if usesWrappers(file) {
+		fmt.Fprintf(&sb, "\nimport %q;\n", wrappersFile)
+	}
+	for _, m := range file.messages {
+		sb.WriteByte('\n')
+		m.write(&sb, "")
+	}
+	return []byte(sb.String()), nil
+}
+
+// descriptorSet returns the serialized FileDescriptorSet of the messages
+// and of the wrappers they use.
+func (g *Generator) descriptorSet(ctx context.Context, names []string) ([]byte, error) {
+	file, err := g.proto(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := g.fileDescriptor(file)
+	if err != nil {
+		return nil, err
+	}
+	fds := &descriptorpb.FileDescriptorSet{}
+	if len(fd.Dependency) > 0 {
+		fds.File = append(fds.File, protodesc.ToFileDescriptorProto(wrapperspb.File_google_protobuf_wrappers_proto))
+	}
+	fds.File = append(fds.File, fd)
+	return proto.Marshal(fds)
+}
+
+func writeComment(sb *strings.Builder, indent, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, l := range strings.Split(strings.TrimSpace(comment), "\n") {
+		sb.WriteString(strings.TrimRight(indent+"// "+strings.TrimSpace(l), " ") + "\n")
+	}
+}
+
+func (m *protoMessage) write(sb *strings.Builder, indent string) {
+	writeComment(sb, indent, m.comment)
+	sb.WriteString(indent + "message " + m.name + " {\n")
+	in := indent + "  "
+	for _, e := range m.enums {
+		sb.WriteString(in + "enum " + e.name + " {\n")
+		for i, v := range e.values {
+			fmt.Fprintf(sb, "%s  %s = %d;\n", in, v, i)
+		}
+		sb.WriteString(in + "}\n")
+	}
+	for _, nm := range m.messages {
+		nm.write(sb, in)
+	}
+	written := make(map[int]bool, len(m.oneofs))
+	for _, f := range m.fields {
+		if f.oneof < 0 {
+			m.writeField(sb, in, f)
+			continue
+		}
+		// a oneof is written where its first field is
+		if written[f.oneof] {
+			continue
+		}
+		written[f.oneof] = true
+		sb.WriteString(in + "oneof " + m.oneofs[f.oneof] + " {\n")
+		for _, of := range m.fields {
+			if of.oneof == f.oneof {
+				m.writeField(sb, in+"  ", of)
+			}
+		}
+		sb.WriteString(in + "}\n")
+	}
+	sb.WriteString(indent + "}\n")
+}
+
+func (m *protoMessage) writeField(sb *strings.Builder, indent string, f *protoField) {
+	writeComment(sb, indent, f.comment)
+	typ := strings.ToLower(strings.TrimPrefix(f.typ.String(), "TYPE_"))
+	if f.typeName != "" {
+		typ = m.typeRef(f.typeName)
+	}
+	label := ""
+	if f.repeated {
+		label = "repeated "
+	}
+	fmt.Fprintf(sb, "%s%s%s %s = %d;\n", indent, label, typ, f.name, f.number)
+}
+
+// typeRef returns how fullName is referred to in m: relative for the types nested
+// in m, fully qualified otherwise so that it does not resolve to a nested type.
+func (m *protoMessage) typeRef(fullName string) string {
+	if name, ok := strings.CutPrefix(fullName, m.fullName+"."); ok {
+		return name
+	}
+	if strings.HasPrefix(fullName, wrappersPackage) {
+		return strings.TrimPrefix(fullName, ".")
+	}
+	return fullName
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authn authenticates the bearer tokens sent along with the RPCs.
+package authn
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// ErrUnknownToken is returned by an Authenticator that does not recognize a token,
+// the next Authenticator of a Chain is then tried.
+var ErrUnknownToken = errors.New("unknown token")
+
+// Identity is the authenticated caller of an RPC.
+type Identity struct {
+	Subject string
+	Tenant  string
+}
+
+// Authenticator returns the identity of the caller a bearer token was issued to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+// New returns the Authenticator configured by cfg.
+func New(ctx context.Context, cfg *config.AuthenticationConfig) (Authenticator, error) {
+	var c Chain
+	if len(cfg.Tokens) > 0 {
+		c = append(c, NewTokens(cfg.Tokens))
+	}
+	if cfg.JWT != nil {
+		j, err := NewJWT(ctx, cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, j)
+	}
+	return c, nil
+}
+
+// Chain tries its Authenticators in order until one recognizes the token.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	for _, a := range c {
+		id, err := a.Authenticate(ctx, token)
+		if errors.Is(err, ErrUnknownToken) {
+			continue
+		}
+		return id, err
+	}
+	return nil, ErrUnknownToken
+}
@@ -0,0 +1,425 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+const (
+	// max size of a JWKS response body
+	maxJWKSResponse = 1024 * 1024
+	// min interval between two fetches triggered by an unknown key
+	minJWKSRefetch = time.Minute
+)
+
+// JWT authenticates the JWTs signed by one of the keys published at a JWKS URL.
+type JWT struct {
+	cfg    *config.JWTConfig
+	client *http.Client
+
+	m       sync.RWMutex
+	keys    map[string]*signingKey
+	fetched time.Time
+}
+
+// signingKey is a key of the JWKS, restricted to the alg of its JWK if it has one.
+type signingKey struct {
+	pub crypto.PublicKey
+	alg string
+}
+
+func NewJWT(ctx context.Context, cfg *config.JWTConfig) (*JWT, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		tlsCfg, err := cfg.TLS.NewConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tr.TLSClientConfig = tlsCfg
+	}
+	j := &JWT{
+		cfg:    cfg,
+		client: &http.Client{Transport: tr, Timeout: cfg.Timeout},
+		keys:   map[string]*signingKey{},
+	}
+	// the tokens are refused until the keys are fetched
+	if err := j.refresh(ctx); err != nil {
+		log.Errorf("failed to fetch the JWKS from %s: %v", cfg.JWKSURL, err)
+	}
+	go j.run(ctx)
+	return j, nil
+}
+
+func (j *JWT) run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.refresh(ctx); err != nil {
+				log.Errorf("failed to refresh the JWKS from %s: %v", j.cfg.JWKSURL, err)
+			}
+		}
+	}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// refresh fetches the keys, they replace the known ones
+// if at least one of them can be used.
+func (j *JWT) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.cfg.JWKSURL, nil)
+	if err != nil {
+		return err
+	}
+	rsp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %q", rsp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(rsp.Body, maxJWKSResponse))
+	if err != nil {
+		return err
+	}
+	set := new(struct {
+		Keys []*jwk `json:"keys"`
+	})
+	if err = json.Unmarshal(b, set); err != nil {
+		return fmt.Errorf("invalid JWKS: %v", err)
+	}
+	keys := make(map[string]*signingKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err == nil && k.Alg != "" {
+			err = checkAlg(k.Alg, pub)
+		}
+		if err != nil {
+			log.Warnf("JWKS %s: ignoring key %q: %v", j.cfg.JWKSURL, k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = &signingKey{pub: pub, alg: k.Alg}
+	}
+	if len(keys) == 0 {
+		return errors.New("no usable key")
+	}
+	j.m.Lock()
+	j.keys = keys
+	j.fetched = time.Now()
+	j.m.Unlock()
+	log.Debugf("fetched %d key(s) from %s", len(keys), j.cfg.JWKSURL)
+	return nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var c elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			c = elliptic.P256()
+		case "P-384":
+			c = elliptic.P384()
+		case "P-521":
+			c = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !c.IsOnCurve(x, y) {
+			return nil, errors.New("point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: c, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// key returns the key identified by kid,
+// the keys are fetched again if it is unknown.
+func (j *JWT) key(ctx context.Context, kid string) (*signingKey, error) {
+	j.m.Lock()
+	k, ok := j.keys[kid]
+	refetch := !ok && time.Since(j.fetched) >= minJWKSRefetch
+	if refetch {
+		j.fetched = time.Now()
+	}
+	j.m.Unlock()
+	if ok {
+		return k, nil
+	}
+	if refetch {
+		if err := j.refresh(ctx); err != nil {
+			log.Errorf("failed to refresh the JWKS from %s: %v", j.cfg.JWKSURL, err)
+		}
+		j.m.RLock()
+		k, ok = j.keys[kid]
+		j.m.RUnlock()
+		if ok {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+}
+
+func (j *JWT) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrUnknownToken
+	}
+	h := new(jwtHeader)
+	if err := decodeSegment(parts[0], h); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("invalid signature encoding")
+	}
+	key, err := j.key(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != "" && h.Alg != key.alg {
+		return nil, fmt.Errorf("alg %q does not match the %q of the signing key", h.Alg, key.alg)
+	}
+	if err = verify(h.Alg, key.pub, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+	claims := new(jwtClaims)
+	if err = decodeSegment(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("invalid claims: %v", err)
+	}
+	if err = j.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	raw := map[string]interface{}{}
+	_ = decodeSegment(parts[1], &raw)
+	tenant, _ := raw[j.cfg.TenantClaim].(string)
+	return &Identity{Subject: claims.Subject, Tenant: tenant}, nil
+}
+
+func (j *JWT) checkClaims(c *jwtClaims) error {
+	now := time.Now()
+	if c.ExpiresAt == nil {
+		return errors.New("missing exp claim")
+	}
+	if now.Add(-j.cfg.ClockSkew).After(numericDate(*c.ExpiresAt)) {
+		return errors.New("token expired")
+	}
+	if c.NotBefore != nil && now.Add(j.cfg.ClockSkew).Before(numericDate(*c.NotBefore)) {
+		return errors.New("token not valid yet")
+	}
+	if j.cfg.Issuer != "" && c.Issuer != j.cfg.Issuer {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+	if j.cfg.Audience != "" && !hasAudience(c.Audience, j.cfg.Audience) {
+		return fmt.Errorf("token not issued for %q", j.cfg.Audience)
+	}
+	return nil
+}
+
+func numericDate(f float64) time.Time {
+	return time.Unix(0, int64(f*float64(time.Second)))
+}
+
+// hasAudience reports whether the aud claim, a string or an array of strings, contains aud.
+func hasAudience(raw json.RawMessage, aud string) bool {
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return one == aud
+	}
+	var many []string
+	if json.Unmarshal(raw, &many) != nil {
+		return false
+	}
+	for _, a := range many {
+		if a == aud {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// jwsAlgs are the JWS algorithms, RFC 7518, and the hash they use.
+var jwsAlgs = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+	"EdDSA": 0,
+}
+
+// esCurves are the curves the ES algorithms are defined for.
+var esCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+// checkAlg checks that alg is a JWS algorithm for the type of key,
+// and for its curve if it is an EC key.
+func checkAlg(alg string, key crypto.PublicKey) error {
+	if _, ok := jwsAlgs[alg]; !ok {
+		return fmt.Errorf("unsupported alg %q", alg)
+	}
+	ok := false
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		ok = strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		ok = esCurves[alg] == k.Curve
+	case ed25519.PublicKey:
+		ok = alg == "EdDSA"
+	}
+	if !ok {
+		return fmt.Errorf("alg %q does not apply to the signing key", alg)
+	}
+	return nil
+}
+
+// verify checks the JWS signature sig of signed using alg and key.
+func verify(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	if err := checkAlg(alg, key); err != nil {
+		return err
+	}
+	errInvalid := errors.New("invalid signature")
+	h := jwsAlgs[alg]
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		d := h.New()
+		d.Write(signed)
+		var err error
+		if alg[0] == 'P' {
+			err = rsa.VerifyPSS(k, h, d.Sum(nil), sig, nil)
+		} else {
+			err = rsa.VerifyPKCS1v15(k, h, d.Sum(nil), sig)
+		}
+		if err != nil {
+			return errInvalid
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return errInvalid
+		}
+		d := h.New()
+		d.Write(signed)
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, d.Sum(nil), r, s) {
+			return errInvalid
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, signed, sig) {
+			return errInvalid
+		}
+	}
+	return nil
+}
@@ -0,0 +1,422 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// testKey is a signing key and the JWK it is published as.
+type testKey struct {
+	priv crypto.Signer
+	jwk  *jwk
+}
+
+func newTestKeys(t *testing.T) map[string]*testKey {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	ecJWK := func(kid string, k *ecdsa.PrivateKey) *jwk {
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &jwk{
+			Kty: "EC", Kid: kid, Crv: k.Curve.Params().Name,
+			X: b64(k.X.FillBytes(make([]byte, size))),
+			Y: b64(k.Y.FillBytes(make([]byte, size))),
+		}
+	}
+	rsaJWK := &jwk{
+		Kty: "RSA", Kid: "rsa",
+		N: b64(rsaKey.N.Bytes()),
+		E: b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+	}
+	rsaPinned := *rsaJWK
+	rsaPinned.Kid, rsaPinned.Alg = "rsa-rs256", "RS256"
+	rsaEnc := *rsaJWK
+	rsaEnc.Kid, rsaEnc.Use = "rsa-enc", "enc"
+	return map[string]*testKey{
+		"rsa":       {priv: rsaKey, jwk: rsaJWK},
+		"rsa-rs256": {priv: rsaKey, jwk: &rsaPinned},
+		"rsa-enc":   {priv: rsaKey, jwk: &rsaEnc},
+		"ec256":     {priv: ec256, jwk: ecJWK("ec256", ec256)},
+		"ec384":     {priv: ec384, jwk: ecJWK("ec384", ec384)},
+		"ed":        {priv: edKey, jwk: &jwk{Kty: "OKP", Kid: "ed", Crv: "Ed25519", X: b64(edKey.Public().(ed25519.PublicKey))}},
+	}
+}
+
+// newTestJWT returns a JWT fetching the keys published by a test JWKS server,
+// and the function adding a key to them.
+func newTestJWT(t *testing.T, keys map[string]*testKey, published ...string) (*JWT, func(kid string)) {
+	t.Helper()
+	m := new(sync.Mutex)
+	set := struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	publish := func(kid string) {
+		m.Lock()
+		defer m.Unlock()
+		set.Keys = append(set.Keys, keys[kid].jwk)
+	}
+	for _, kid := range published {
+		publish(kid)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(srv.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	j, err := NewJWT(ctx, &config.JWTConfig{
+		JWKSURL:         srv.URL,
+		Issuer:          "test-issuer",
+		Audience:        "schema-server",
+		TenantClaim:     "tenant",
+		RefreshInterval: time.Hour,
+		Timeout:         time.Second,
+		ClockSkew:       time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j, publish
+}
+
+// signToken returns the token of claims signed with alg by key,
+// alg does not have to apply to the key.
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	t.Helper()
+	enc := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": alg, "kid": kid}) + "." + enc(claims)
+	h := crypto.SHA256
+	switch {
+	case strings.HasSuffix(alg, "384"):
+		h = crypto.SHA384
+	case strings.HasSuffix(alg, "512"):
+		h = crypto.SHA512
+	}
+	var sig []byte
+	var err error
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		d := h.New()
+		d.Write([]byte(signed))
+		if alg[0] == 'P' {
+			sig, err = rsa.SignPSS(rand.Reader, k, h, d.Sum(nil), nil)
+		} else {
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, h, d.Sum(nil))
+		}
+	case *ecdsa.PrivateKey:
+		d := h.New()
+		d.Write([]byte(signed))
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, d.Sum(nil))
+		if err == nil {
+			size := (k.Curve.Params().BitSize + 7) / 8
+			sig = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+		}
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(signed))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWT_Authenticate(t *testing.T) {
+	keys := newTestKeys(t)
+	j, _ := newTestJWT(t, keys, "rsa", "rsa-rs256", "rsa-enc", "ec256", "ec384", "ed")
+
+	now := time.Now().Unix()
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":    "test-issuer",
+			"sub":    "alice",
+			"aud":    "schema-server",
+			"exp":    now + 60,
+			"tenant": "t1",
+		}
+	}
+	with := func(k string, v interface{}) map[string]interface{} {
+		c := valid()
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+		return c
+	}
+	alice := &Identity{Subject: "alice", Tenant: "t1"}
+
+	tests := []struct {
+		name    string
+		token   func() string
+		want    *Identity
+		wantErr bool
+	}{
+		{
+			name:  "RS256",
+			token: func() string { return signToken(t, "RS256", "rsa", keys["rsa"].priv, valid()) },
+			want:  alice,
+		},
+		{
+			name:  "PS384",
+			token: func() string { return signToken(t, "PS384", "rsa", keys["rsa"].priv, valid()) },
+			want:  alice,
+		},
+		{
+			name:  "ES256 with a P-256 key",
+			token: func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, valid()) },
+			want:  alice,
+		},
+		{
+			name:  "ES384 with a P-384 key",
+			token: func() string { return signToken(t, "ES384", "ec384", keys["ec384"].priv, valid()) },
+			want:  alice,
+		},
+		{
+			name:  "EdDSA",
+			token: func() string { return signToken(t, "EdDSA", "ed", keys["ed"].priv, valid()) },
+			want:  alice,
+		},
+		{
+			name: "aud array",
+			token: func() string {
+				return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("aud", []string{"other", "schema-server"}))
+			},
+			want: alice,
+		},
+		{
+			name:    "ES256 with an RSA key",
+			token:   func() string { return signToken(t, "ES256", "rsa", keys["rsa"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "RS256 with an EC key",
+			token:   func() string { return signToken(t, "RS256", "ec256", keys["ec256"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "EdDSA with an EC key",
+			token:   func() string { return signToken(t, "EdDSA", "ec256", keys["ec256"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "ES256 with a P-384 key",
+			token:   func() string { return signToken(t, "ES256", "ec384", keys["ec384"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "ES384 with a P-256 key",
+			token:   func() string { return signToken(t, "ES384", "ec256", keys["ec256"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "none alg",
+			token:   func() string { return signToken(t, "none", "rsa", keys["rsa"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:  "JWK alg honoured",
+			token: func() string { return signToken(t, "RS256", "rsa-rs256", keys["rsa"].priv, valid()) },
+			want:  alice,
+		},
+		{
+			name:    "other alg than the JWK one",
+			token:   func() string { return signToken(t, "PS256", "rsa-rs256", keys["rsa"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "encryption key",
+			token:   func() string { return signToken(t, "RS256", "rsa-enc", keys["rsa"].priv, valid()) },
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("exp", now-60)) },
+			wantErr: true,
+		},
+		{
+			name:  "expired within the clock skew",
+			token: func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("exp", now)) },
+			want:  alice,
+		},
+		{
+			name:    "no exp",
+			token:   func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("exp", nil)) },
+			wantErr: true,
+		},
+		{
+			name:    "nbf in the future",
+			token:   func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("nbf", now+60)) },
+			wantErr: true,
+		},
+		{
+			name:  "nbf in the past",
+			token: func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("nbf", now-60)) },
+			want:  alice,
+		},
+		{
+			name:    "wrong iss",
+			token:   func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("iss", "other-issuer")) },
+			wantErr: true,
+		},
+		{
+			name:    "wrong aud",
+			token:   func() string { return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("aud", "other")) },
+			wantErr: true,
+		},
+		{
+			name: "wrong aud array",
+			token: func() string {
+				return signToken(t, "ES256", "ec256", keys["ec256"].priv, with("aud", []string{"other"}))
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered payload",
+			token: func() string {
+				parts := strings.Split(signToken(t, "ES256", "ec256", keys["ec256"].priv, valid()), ".")
+				c, _ := json.Marshal(with("sub", "mallory"))
+				parts[1] = base64.RawURLEncoding.EncodeToString(c)
+				return strings.Join(parts, ".")
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered header",
+			token: func() string {
+				parts := strings.Split(signToken(t, "RS256", "rsa", keys["rsa"].priv, valid()), ".")
+				h, _ := json.Marshal(map[string]string{"alg": "PS256", "kid": "rsa"})
+				parts[0] = base64.RawURLEncoding.EncodeToString(h)
+				return strings.Join(parts, ".")
+			},
+			wantErr: true,
+		},
+		{
+			name:    "not a JWT",
+			token:   func() string { return "opaque" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := j.Authenticate(context.Background(), tt.token())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Authenticate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWT_AuthenticateUnknownKey(t *testing.T) {
+	keys := newTestKeys(t)
+	claims := map[string]interface{}{
+		"iss": "test-issuer",
+		"sub": "bob",
+		"aud": "schema-server",
+		"exp": time.Now().Unix() + 60,
+	}
+	bob := &Identity{Subject: "bob"}
+
+	tests := []struct {
+		name string
+		// time since the last fetch
+		fetchedAgo time.Duration
+		// key published after the JWT is created
+		rotated bool
+		want    *Identity
+		wantErr bool
+	}{
+		{
+			name:       "refetched",
+			fetchedAgo: 2 * minJWKSRefetch,
+			rotated:    true,
+			want:       bob,
+		},
+		{
+			name:       "refetched too recently",
+			fetchedAgo: 0,
+			rotated:    true,
+			wantErr:    true,
+		},
+		{
+			name:       "not published",
+			fetchedAgo: 2 * minJWKSRefetch,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, publish := newTestJWT(t, keys, "rsa")
+			if tt.rotated {
+				publish("ec256")
+			}
+			j.m.Lock()
+			j.fetched = time.Now().Add(-tt.fetchedAgo)
+			j.m.Unlock()
+			got, err := j.Authenticate(context.Background(), signToken(t, "ES256", "ec256", keys["ec256"].priv, claims))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Authenticate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authn
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// rejecting authenticates no token.
+type rejecting struct{}
+
+func (rejecting) Authenticate(context.Context, string) (*Identity, error) {
+	return nil, errors.New("rejected")
+}
+
+func TestTokens_Authenticate(t *testing.T) {
+	tokens := NewTokens([]*config.TokenConfig{
+		{Token: "secret-1", Subject: "alice", Tenant: "t1"},
+		{Token: "secret-2", Subject: "bob"},
+	})
+	tests := []struct {
+		name    string
+		a       Authenticator
+		token   string
+		want    *Identity
+		wantErr error
+	}{
+		{
+			name:  "known token",
+			a:     tokens,
+			token: "secret-1",
+			want:  &Identity{Subject: "alice", Tenant: "t1"},
+		},
+		{
+			name:  "known token without tenant",
+			a:     tokens,
+			token: "secret-2",
+			want:  &Identity{Subject: "bob"},
+		},
+		{
+			name:    "unknown token",
+			a:       tokens,
+			token:   "secret-3",
+			wantErr: ErrUnknownToken,
+		},
+		{
+			name:    "prefix of a token",
+			a:       tokens,
+			token:   "secret",
+			wantErr: ErrUnknownToken,
+		},
+		{
+			name:    "empty token",
+			a:       tokens,
+			wantErr: ErrUnknownToken,
+		},
+		{
+			name:  "chain tries the next authenticator",
+			a:     Chain{NewTokens(nil), tokens},
+			token: "secret-2",
+			want:  &Identity{Subject: "bob"},
+		},
+		{
+			name:    "chain unknown token",
+			a:       Chain{NewTokens(nil), tokens},
+			token:   "secret-3",
+			wantErr: ErrUnknownToken,
+		},
+		{
+			name:    "chain stops at a rejection",
+			a:       Chain{rejecting{}, tokens},
+			token:   "secret-1",
+			wantErr: errors.New("rejected"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Authenticate(context.Background(), tt.token)
+			if (err == nil) != (tt.wantErr == nil) || err != nil && err.Error() != tt.wantErr.Error() {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Authenticate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+)
+
+// Allowlist is an Authorizer allowing the clients to call the RPCs
+// their certificate common name or SANs are allowed to.
+type Allowlist struct {
+	clients []*config.ClientAuthorization
+}
+
+func NewAllowlist(clients []*config.ClientAuthorization) *Allowlist {
+	return &Allowlist{clients: clients}
+}
+
+func (a *Allowlist) Authorize(ctx context.Context, req *Request) error {
+	ids := make([]string, 0, len(req.SANs)+1)
+	if req.Principal != "" {
+		ids = append(ids, req.Principal)
+	}
+	ids = append(ids, req.SANs...)
+	if len(ids) == 0 {
+		return status.Errorf(codes.Unauthenticated, "%s requires a verified client certificate", req.Method)
+	}
+	for _, c := range a.clients {
+		if matchAny(c.Principals, ids...) && matchAny(c.Methods, req.Method, methodName(req.Method)) {
+			return nil
+		}
+	}
+	name := req.Principal
+	if name == "" {
+		name = ids[0]
+	}
+	st := status.Newf(codes.PermissionDenied, "client %q is not allowed to call %s", name, req.Method)
+	std, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   api.ReasonClientNotAllowed,
+		Domain:   schema.ErrorDomain,
+		Metadata: map[string]string{"principal": name, "method": req.Method},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return std.Err()
+}
+
+// methodName returns the RPC name of the full gRPC method name m.
+func methodName(m string) string {
+	return m[strings.LastIndex(m, "/")+1:]
+}
+
+// matchAny reports whether one of the patterns matches one of the values,
+// a pattern starting with a / is only matched against values starting with a /.
+func matchAny(patterns []string, values ...string) bool {
+	for _, p := range patterns {
+		for _, v := range values {
+			if strings.HasPrefix(p, "/") != strings.HasPrefix(v, "/") {
+				continue
+			}
+			if ok, _ := path.Match(p, v); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authz defines the authorization of the RPCs served by the schema server.
+package authz
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// Request is the context of an RPC submitted to an Authorizer.
+type Request struct {
+	// full gRPC method name, e.g. /schema.SchemaServer/GetSchema,
+	// the one of the SchemaServer service for the legacy services RPCs
+	Method string `json:"method"`
+	Peer   string `json:"peer,omitempty"`
+	// subject common name of the verified client certificate
+	Principal string `json:"principal,omitempty"`
+	// DNS, URI, email and IP subject alternative names of the verified client certificate
+	SANs     []string            `json:"sans,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+	// x-request-id metadata, generated if not sent by the client
+	RequestID string `json:"request-id,omitempty"`
+	Tenant    string `json:"tenant,omitempty"`
+	// subject of the authenticated bearer token
+	Subject string `json:"subject,omitempty"`
+	// schema and xpaths the request refers to, if any
+	Schema *sdcpb.Schema `json:"schema,omitempty"`
+	Paths  []string      `json:"paths,omitempty"`
+}
+
+// Authorizer decides whether an RPC is served.
+type Authorizer interface {
+	// Authorize returns nil if the request is allowed,
+	// the error returned to the client otherwise.
+	Authorize(ctx context.Context, req *Request) error
+}
+
+// SchemaFilter is implemented by the Authorizers restricting the schemas a client is served from,
+// the schemas listed to the client are filtered with it.
+type SchemaFilter interface {
+	// AllowSchema reports whether the client sending req may be served from schema sc,
+	// sc is nil for the data not referring to a schema, e.g. a journal entry of ReloadConfig.
+	AllowSchema(req *Request, sc *sdcpb.Schema) bool
+}
+
+// New returns the Authorizer configured by cfg.
+func New(ctx context.Context, cfg *config.AuthorizationConfig) (Authorizer, error) {
+	var c Chain
+	if len(cfg.Clients) > 0 {
+		c = append(c, NewAllowlist(cfg.Clients))
+	}
+	if len(cfg.Tenants) > 0 {
+		c = append(c, NewTenants(cfg.Tenants))
+	}
+	if cfg.Webhook != nil {
+		wh, err := NewWebhook(ctx, cfg.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, wh)
+	}
+	if len(c) == 1 {
+		return c[0], nil
+	}
+	return c, nil
+}
+
+// Chain is an Authorizer allowing the requests allowed by all its Authorizers,
+// they are called in order.
+type Chain []Authorizer
+
+func (c Chain) Authorize(ctx context.Context, req *Request) error {
+	for _, a := range c {
+		if err := a.Authorize(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Chain) AllowSchema(req *Request, sc *sdcpb.Schema) bool {
+	for _, a := range c {
+		if f, ok := a.(SchemaFilter); ok && !f.AllowSchema(req, sc) {
+			return false
+		}
+	}
+	return true
+}
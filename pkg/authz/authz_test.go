@@ -0,0 +1,222 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+const getSchemaMethod = "/schema.SchemaServer/GetSchema"
+
+// testConfig allows ops to call all the RPCs and dev the Get ones only,
+// tenant a is served from the srl schemas only and tenant admin from all of them.
+func testConfig() *config.AuthorizationConfig {
+	return &config.AuthorizationConfig{
+		Clients: []*config.ClientAuthorization{
+			{Principals: []string{"ops", "*.ops.example.com"}, Methods: []string{"/schema.SchemaServer/*", "/schema.SchemaServerExt/*"}},
+			{Principals: []string{"dev"}, Methods: []string{"Get*"}},
+		},
+		Tenants: []*config.TenantAuthorization{
+			{Tenant: "a", Schemas: []*config.SchemaPattern{{Name: "srl", Vendor: "nokia"}}},
+			{Tenant: "admin", Schemas: []*config.SchemaPattern{{}}, Admin: true},
+		},
+	}
+}
+
+// reason returns the ErrorInfo reason of err, if any.
+func reason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			return ei.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.AuthorizationConfig
+		wantLen int
+	}{
+		{name: "clients", cfg: &config.AuthorizationConfig{Clients: testConfig().Clients}, wantLen: 1},
+		{name: "tenants", cfg: &config.AuthorizationConfig{Tenants: testConfig().Tenants}, wantLen: 1},
+		{name: "clients and tenants", cfg: testConfig(), wantLen: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(context.Background(), tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, ok := a.(Chain)
+			if ok != (tt.wantLen > 1) {
+				t.Fatalf("New() = %T, want a Chain %v", a, tt.wantLen > 1)
+			}
+			if ok && len(c) != tt.wantLen {
+				t.Errorf("New() chains %d Authorizers, want %d", len(c), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestChain_Authorize(t *testing.T) {
+	srl := &sdcpb.Schema{Name: "srl", Vendor: "nokia", Version: "24.3.1"}
+	junos := &sdcpb.Schema{Name: "junos", Vendor: "juniper", Version: "23.2"}
+	tests := []struct {
+		name       string
+		req        *Request
+		code       codes.Code
+		wantReason string
+	}{
+		{
+			name: "allowed",
+			req:  &Request{Method: getSchemaMethod, Principal: "ops", Tenant: "a", Schema: srl},
+		},
+		{
+			name: "allowed by SAN",
+			req:  &Request{Method: getSchemaMethod, SANs: []string{"host1.ops.example.com"}, Tenant: "a", Schema: srl},
+		},
+		{
+			name: "allowed RPC name",
+			req:  &Request{Method: getSchemaMethod, Principal: "dev", Tenant: "a", Schema: srl},
+		},
+		{
+			name: "without schema",
+			req:  &Request{Method: "/schema.SchemaServer/ListSchema", Principal: "ops", Tenant: "b"},
+		},
+		{
+			name: "listed from the ext service",
+			req:  &Request{Method: api.FullMethod("GetRequestJournal"), Principal: "ops", Tenant: "a"},
+		},
+		{
+			name: "admin without schema",
+			req:  &Request{Method: api.FullMethod("ReloadConfig"), Principal: "ops", Tenant: "admin"},
+		},
+		{
+			name:       "tenant without schema",
+			req:        &Request{Method: api.FullMethod("ReloadConfig"), Principal: "ops", Tenant: "a"},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonTenantNotAllowed,
+		},
+		{
+			name:       "without tenant nor schema",
+			req:        &Request{Method: api.FullMethod("SetSchemaCanary"), Principal: "ops"},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonTenantNotAllowed,
+		},
+		{
+			// the RPCs any tenant may call are matched by their full name
+			name:       "listing RPC name of another service",
+			req:        &Request{Method: "/other.Service/ListSchema", Principal: "ops", Tenant: "a"},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonClientNotAllowed,
+		},
+		{
+			name: "without certificate",
+			req:  &Request{Method: getSchemaMethod, Tenant: "a", Schema: srl},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:       "client not allowed",
+			req:        &Request{Method: "/schema.SchemaServer/DeleteSchema", Principal: "dev", Tenant: "a", Schema: srl},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonClientNotAllowed,
+		},
+		{
+			name:       "sibling service",
+			req:        &Request{Method: "/schema.Other/GetSchema", Principal: "ops", Tenant: "a", Schema: srl},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonClientNotAllowed,
+		},
+		{
+			name:       "schema not allowed",
+			req:        &Request{Method: getSchemaMethod, Principal: "ops", Tenant: "a", Schema: junos},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonSchemaNotAllowed,
+		},
+		{
+			name:       "unknown tenant",
+			req:        &Request{Method: getSchemaMethod, Principal: "ops", Tenant: "b", Schema: srl},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonSchemaNotAllowed,
+		},
+		{
+			// the first denying Authorizer decides
+			name:       "client and schema not allowed",
+			req:        &Request{Method: "/schema.SchemaServer/DeleteSchema", Principal: "dev", Tenant: "a", Schema: junos},
+			code:       codes.PermissionDenied,
+			wantReason: api.ReasonClientNotAllowed,
+		},
+	}
+	a, err := New(context.Background(), testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.Authorize(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("Authorize() error = %v, want code %v", err, tt.code)
+			}
+			if got := reason(err); got != tt.wantReason {
+				t.Errorf("Authorize() reason = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestChain_AllowSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant string
+		sc     *sdcpb.Schema
+		want   bool
+	}{
+		{name: "allowed", tenant: "a", sc: &sdcpb.Schema{Name: "srl", Vendor: "nokia", Version: "24.3.1"}, want: true},
+		{name: "other vendor", tenant: "a", sc: &sdcpb.Schema{Name: "srl", Vendor: "other", Version: "1"}},
+		{name: "other schema", tenant: "a", sc: &sdcpb.Schema{Name: "junos", Vendor: "juniper", Version: "23.2"}},
+		{name: "unknown tenant", tenant: "b", sc: &sdcpb.Schema{Name: "srl", Vendor: "nokia", Version: "24.3.1"}},
+		{name: "without tenant", sc: &sdcpb.Schema{Name: "srl", Vendor: "nokia", Version: "24.3.1"}},
+		{name: "admin", tenant: "admin", sc: &sdcpb.Schema{Name: "junos", Vendor: "juniper", Version: "23.2"}, want: true},
+		{name: "not a schema", tenant: "a"},
+		{name: "not a schema for an admin", tenant: "admin", want: true},
+	}
+	a, err := New(context.Background(), testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, ok := a.(SchemaFilter)
+	if !ok {
+		t.Fatalf("New() = %T, not a SchemaFilter", a)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &Request{Method: "/schema.SchemaServer/ListSchema", Principal: "ops", Tenant: tt.tenant}
+			if got := f.AllowSchema(req, tt.sc); got != tt.want {
+				t.Errorf("AllowSchema() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
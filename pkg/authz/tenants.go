@@ -0,0 +1,124 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+)
+
+// Tenants is an Authorizer serving each tenant from its own schemas only.
+// The requests not referring to a schema are allowed for the admin tenants,
+// and for the RPCs listing schemas, their responses being filtered.
+type Tenants struct {
+	schemas map[string][]*config.SchemaPattern
+	admins  map[string]struct{}
+}
+
+// tenantMethods are the full names of the RPCs not referring to a schema any tenant
+// may call: the schemas and the requests they list are filtered with AllowSchema.
+var tenantMethods = map[string]struct{}{
+	"/" + sdcpb.SchemaServer_ServiceDesc.ServiceName + "/ListSchema": {},
+	api.FullMethod("ListSchemaStates"):                               {},
+	api.FullMethod("GetSchemaStats"):                                 {},
+	api.FullMethod("WatchSchemaChanges"):                             {},
+	api.FullMethod("ListSchemaPins"):                                 {},
+	api.FullMethod("ListSchemaCanaries"):                             {},
+	api.FullMethod("GetRequestJournal"):                              {},
+	api.FullMethod("ListLintRules"):                                  {},
+	api.FullMethod("GetServerInfo"):                                  {},
+	api.FullMethod("Hello"):                                          {},
+}
+
+func NewTenants(tenants []*config.TenantAuthorization) *Tenants {
+	t := &Tenants{
+		schemas: make(map[string][]*config.SchemaPattern, len(tenants)),
+		admins:  make(map[string]struct{}),
+	}
+	for _, ta := range tenants {
+		t.schemas[ta.Tenant] = ta.Schemas
+		if ta.Admin {
+			t.admins[ta.Tenant] = struct{}{}
+		}
+	}
+	return t
+}
+
+func (t *Tenants) Authorize(ctx context.Context, req *Request) error {
+	if req.Schema == nil {
+		return t.authorizeMethod(req)
+	}
+	if t.AllowSchema(req, req.Schema) {
+		return nil
+	}
+	sc := req.Schema
+	st := status.Newf(codes.PermissionDenied, "tenant %q is not allowed to use schema %s/%s/%s",
+		req.Tenant, sc.GetName(), sc.GetVendor(), sc.GetVersion())
+	std, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: api.ReasonSchemaNotAllowed,
+		Domain: schema.ErrorDomain,
+		Metadata: map[string]string{
+			"tenant":  req.Tenant,
+			"name":    sc.GetName(),
+			"vendor":  sc.GetVendor(),
+			"version": sc.GetVersion(),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return std.Err()
+}
+
+// authorizeMethod authorizes the requests not referring to a schema.
+func (t *Tenants) authorizeMethod(req *Request) error {
+	if _, ok := t.admins[req.Tenant]; ok {
+		return nil
+	}
+	if _, ok := tenantMethods[req.Method]; ok {
+		return nil
+	}
+	st := status.Newf(codes.PermissionDenied, "tenant %q is not allowed to call %s", req.Tenant, req.Method)
+	std, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   api.ReasonTenantNotAllowed,
+		Domain:   schema.ErrorDomain,
+		Metadata: map[string]string{"tenant": req.Tenant, "method": req.Method},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return std.Err()
+}
+
+func (t *Tenants) AllowSchema(req *Request, sc *sdcpb.Schema) bool {
+	if sc == nil {
+		_, ok := t.admins[req.Tenant]
+		return ok
+	}
+	for _, p := range t.schemas[req.Tenant] {
+		if p.Match(sc.GetName(), sc.GetVendor(), sc.GetVersion()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// max size of a webhook response body
+const maxWebhookResponse = 64 * 1024
+
+// Webhook is an Authorizer POSTing the requests to an external policy service.
+// The request is sent as {"input": <Request>}, the service answers
+// {"result": <bool>} or {"result": {"allow": <bool>, "reason": <string>}},
+// which is what an OPA data API endpoint returns for a boolean or an object rule.
+type Webhook struct {
+	cfg    *config.WebhookConfig
+	client *http.Client
+}
+
+func NewWebhook(ctx context.Context, cfg *config.WebhookConfig) (*Webhook, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		tlsCfg, err := cfg.TLS.NewConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// the certificate is presented as a client certificate
+		if getCert := tlsCfg.GetCertificate; getCert != nil {
+			tlsCfg.GetCertificate = nil
+			tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return getCert(nil)
+			}
+		}
+		tr.TLSClientConfig = tlsCfg
+	}
+	return &Webhook{
+		cfg: cfg,
+		client: &http.Client{
+			Transport: tr,
+			Timeout:   cfg.Timeout,
+		},
+	}, nil
+}
+
+type webhookRequest struct {
+	Input *Request `json:"input"`
+}
+
+type webhookResponse struct {
+	Result json.RawMessage `json:"result"`
+}
+
+type webhookDecision struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+func (w *Webhook) Authorize(ctx context.Context, req *Request) error {
+	d, err := w.decide(ctx, req)
+	if err != nil {
+		if w.cfg.FailOpen {
+			log.Warnf("authorization webhook failed, allowing %s: %v", req.Method, err)
+			return nil
+		}
+		log.Errorf("authorization webhook failed, denying %s: %v", req.Method, err)
+		return status.Error(codes.Unavailable, "authorization service unavailable")
+	}
+	if !d.Allow {
+		if d.Reason != "" {
+			return status.Errorf(codes.PermissionDenied, "%s denied: %s", req.Method, d.Reason)
+		}
+		return status.Errorf(codes.PermissionDenied, "%s denied", req.Method)
+	}
+	return nil
+}
+
+func (w *Webhook) decide(ctx context.Context, req *Request) (*webhookDecision, error) {
+	b, err := json.Marshal(&webhookRequest{Input: req})
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range w.cfg.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	rsp, err := w.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", rsp.Status)
+	}
+	b, err = io.ReadAll(io.LimitReader(rsp.Body, maxWebhookResponse))
+	if err != nil {
+		return nil, err
+	}
+	wr := new(webhookResponse)
+	err = json.Unmarshal(b, wr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	// an undefined OPA rule returns no result
+	if len(wr.Result) == 0 {
+		return &webhookDecision{}, nil
+	}
+	d := new(webhookDecision)
+	err = json.Unmarshal(wr.Result, &d.Allow)
+	if err == nil {
+		return d, nil
+	}
+	err = json.Unmarshal(wr.Result, d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid result %s", wr.Result)
+	}
+	return d, nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup archives the state of a schema-server for disaster recovery:
+// its config file, the YANG sources of its schemas, the uploaded schemas and
+// its persistent store, each file content stored once, see schema-server backup.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store/persiststore"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	// version of the archive format
+	Version = 1
+	// first entry of an archive
+	manifestName = "manifest.json"
+	// directory of the contents, named after their digest
+	blobsDir = "blobs/"
+)
+
+// kinds of the archived files
+const (
+	KindConfig = "config"
+	KindSource = "source"
+	KindUpload = "upload"
+)
+
+// Manifest lists the content of an archive.
+type Manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Files   []*File   `json:"files"`
+	// logical backup of the persistent store, if archived
+	Store *Store `json:"store,omitempty"`
+}
+
+// File is an archived file, restored at its absolute path.
+type File struct {
+	Kind   string      `json:"kind"`
+	Path   string      `json:"path"`
+	Mode   os.FileMode `json:"mode"`
+	Digest string      `json:"digest"`
+	Size   int64       `json:"size"`
+}
+
+// Store is the backup of the persistent store at Path.
+type Store struct {
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+// Blobs returns the number of distinct contents of the manifest and their size.
+func (m *Manifest) Blobs() (int, int64) {
+	seen := make(map[string]struct{}, len(m.Files)+1)
+	var size int64
+	add := func(digest string, n int64) {
+		if _, ok := seen[digest]; ok {
+			return
+		}
+		seen[digest] = struct{}{}
+		size += n
+	}
+	for _, f := range m.Files {
+		add(f.Digest, f.Size)
+	}
+	if m.Store != nil {
+		add(m.Store.Digest, m.Store.Size)
+	}
+	return len(seen), size
+}
+
+type Options struct {
+	// config file cfg was read from
+	ConfigFile string
+	// leave the persistent store out, it can only be backed up
+	// with the server stopped
+	NoStore bool
+}
+
+// Create writes to w a tar.gz archive of the config file, the YANG sources of the
+// schemas of cfg, the files of its schemas directory and, unless o.NoStore is set,
+// a backup of its persistent store. The archive starts with the manifest, followed
+// by one entry per distinct content.
+func Create(ctx context.Context, cfg *config.Config, w io.Writer, o *Options) (*Manifest, error) {
+	m := &Manifest{Version: Version, Created: time.Now().UTC()}
+	// path of the blob of each digest
+	blobs := make(map[string]string)
+	seen := make(map[string]struct{})
+	add := func(kind, p string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[p]; ok {
+			return nil
+		}
+		seen[p] = struct{}{}
+		fi, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		digest, err := fileDigest(p)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, &File{Kind: kind, Path: p, Mode: fi.Mode().Perm(), Digest: digest, Size: fi.Size()})
+		if _, ok := blobs[digest]; !ok {
+			blobs[digest] = p
+		}
+		return nil
+	}
+	if o.ConfigFile != "" {
+		if err := add(KindConfig, o.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, sc := range cfg.SchemaStore.Schemas {
+		ps, err := schema.SourcePaths(sc)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range ps {
+			err = utils.WalkFiles(p, func(fp string) error {
+				if !utils.IsYANGFile(fp) {
+					return nil
+				}
+				return add(KindSource, fp)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("schema %s/%s/%s: %w", sc.Name, sc.Vendor, sc.Version, err)
+			}
+		}
+	}
+	if ss := cfg.GRPCServer.SchemaServer; ss != nil && ss.SchemasDirectory != "" {
+		err := utils.WalkFiles(ss.SchemasDirectory, func(fp string) error {
+			return add(KindUpload, fp)
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if !o.NoStore && cfg.SchemaStore.Type == config.StoreTypePersistent {
+		tmp, err := backupStore(cfg.SchemaStore.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(tmp)
+		fi, err := os.Stat(tmp)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := fileDigest(tmp)
+		if err != nil {
+			return nil, err
+		}
+		p, err := filepath.Abs(cfg.SchemaStore.Path)
+		if err != nil {
+			return nil, err
+		}
+		m.Store = &Store{Path: p, Digest: digest, Size: fi.Size()}
+		if _, ok := blobs[digest]; !ok {
+			blobs[digest] = tmp
+		}
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0o644, Size: int64(len(b)), ModTime: m.Created})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return nil, err
+	}
+	digests := make([]string, 0, len(blobs))
+	for digest := range blobs {
+		digests = append(digests, digest)
+	}
+	sort.Strings(digests)
+	for _, digest := range digests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := writeBlob(tw, digest, blobs[digest], m.Created); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// backupStore writes a backup of the store at p in a temporary file and returns its path.
+func backupStore(p string) (string, error) {
+	if _, err := os.Stat(p); err != nil {
+		return "", fmt.Errorf("store %s: %w", p, err)
+	}
+	f, err := os.CreateTemp("", "schema-server-store-*")
+	if err != nil {
+		return "", err
+	}
+	err = persiststore.Backup(p, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("%w: stop the server or set --no-store", err)
+	}
+	return f.Name(), nil
+}
+
+// writeBlob writes the content of the file at p, checking it still has digest.
+func writeBlob(tw *tar.Writer, digest, p string, mtime time.Time) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	err = tw.WriteHeader(&tar.Header{Name: blobsDir + digest, Mode: 0o644, Size: fi.Size(), ModTime: mtime})
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, h), f); err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != digest {
+		return fmt.Errorf("%s changed during the backup", p)
+	}
+	return nil
+}
+
+func fileDigest(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sdcio/schema-server/pkg/store/persiststore"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+type RestoreOptions struct {
+	// directory the files and the store are restored under,
+	// their archived paths if not set
+	Root string
+	// overwrite the existing files and replace the content of the store
+	Force bool
+}
+
+// Restore restores the files and the store of the archive read from r,
+// written by Create. Without o.Force, it fails before writing anything
+// if one of the files exists or if the store directory is not empty.
+func Restore(ctx context.Context, r io.Reader, o *RestoreOptions) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if hdr.Name != manifestName {
+		return nil, fmt.Errorf("not a schema-server backup: first entry %q", hdr.Name)
+	}
+	m := new(Manifest)
+	if err := json.NewDecoder(tr).Decode(m); err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("unsupported backup version %d", m.Version)
+	}
+	// files of each digest
+	files := make(map[string][]*File)
+	for _, f := range m.Files {
+		p, err := o.path(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !o.Force {
+			if _, err := os.Lstat(p); err == nil {
+				return nil, fmt.Errorf("%s exists, set --force to overwrite it", p)
+			}
+		}
+		files[f.Digest] = append(files[f.Digest], f)
+	}
+	if m.Store != nil {
+		p, err := o.path(m.Store.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !o.Force {
+			des, err := os.ReadDir(p)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+			if len(des) > 0 {
+				return nil, fmt.Errorf("store %s is not empty, set --force to replace its content", p)
+			}
+		}
+	}
+	restored := make(map[string]bool, len(files)+1)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		digest, ok := strings.CutPrefix(hdr.Name, blobsDir)
+		if !ok {
+			return nil, fmt.Errorf("unexpected entry %q", hdr.Name)
+		}
+		store := ""
+		if m.Store != nil && m.Store.Digest == digest {
+			store = m.Store.Path
+		}
+		err = restoreBlob(tr, digest, files[digest], store, o)
+		if err != nil {
+			return nil, err
+		}
+		restored[digest] = true
+	}
+	for digest := range files {
+		if !restored[digest] {
+			return nil, fmt.Errorf("missing content %s", digest)
+		}
+	}
+	if m.Store != nil && !restored[m.Store.Digest] {
+		return nil, fmt.Errorf("missing store content %s", m.Store.Digest)
+	}
+	return m, nil
+}
+
+// path returns where the file archived at p is restored,
+// p has to be a clean absolute path and no symlink can be on its way
+// under o.Root: the archive can not write outside of o.Root.
+func (o *RestoreOptions) path(p string) (string, error) {
+	if !filepath.IsAbs(p) {
+		return "", fmt.Errorf("manifest: relative path %q", p)
+	}
+	if filepath.Clean(p) != p {
+		return "", fmt.Errorf("manifest: path %q is not clean", p)
+	}
+	if o.Root == "" {
+		return p, nil
+	}
+	rp, err := utils.JoinUnder(o.Root, filepath.ToSlash(p))
+	if err != nil {
+		return "", err
+	}
+	return rp, o.checkLinks(rp)
+}
+
+// checkLinks returns an error if p or one of its directories under o.Root
+// exists and is a symlink.
+func (o *RestoreOptions) checkLinks(p string) error {
+	rel, err := filepath.Rel(o.Root, p)
+	if err != nil {
+		return err
+	}
+	d := o.Root
+	for _, e := range strings.Split(rel, string(filepath.Separator)) {
+		d = filepath.Join(d, e)
+		fi, err := os.Lstat(d)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink, not restoring %s", d, p)
+		}
+	}
+	return nil
+}
+
+// restoreBlob writes the content of digest read from r to each of fs
+// and, if it is the one of the store at storePath, loads it in the store.
+func restoreBlob(r io.Reader, digest string, fs []*File, storePath string, o *RestoreOptions) error {
+	// the content is written to a temporary file first, to check its digest
+	tmp, err := os.CreateTemp("", "schema-server-restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != digest {
+		return fmt.Errorf("content %s: digest mismatch", digest)
+	}
+	for _, f := range fs {
+		p, err := o.path(f.Path)
+		if err != nil {
+			return err
+		}
+		if err := copyTo(tmp, p, f.Mode); err != nil {
+			return err
+		}
+	}
+	if storePath == "" {
+		return nil
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	p, err := o.path(storePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(p, 0o755); err != nil {
+		return err
+	}
+	return persiststore.Restore(p, tmp, o.Force)
+}
+
+// copyTo copies the content of f to a file at p with the permissions of mode,
+// its other bits (setuid, setgid, sticky) are not restored.
+// The file is written next to p and renamed over it: an existing p
+// is replaced, not written through if it is a symlink.
+func copyTo(f *os.File, p string, mode os.FileMode) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	out, err := os.CreateTemp(filepath.Dir(p), ".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	err = out.Chmod(mode & 0o777)
+	if err == nil {
+		_, err = io.Copy(out, f)
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	return os.Rename(out.Name(), p)
+}
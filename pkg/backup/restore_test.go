@@ -0,0 +1,295 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testArchive returns a backup archive of files, their path and content,
+// archived with mode.
+func testArchive(t *testing.T, files map[string]string, mode os.FileMode) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	m := &Manifest{Version: Version}
+	blobs := make(map[string]string)
+	for p, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		digest := hex.EncodeToString(sum[:])
+		m.Files = append(m.Files, &File{Kind: "schema", Path: p, Mode: mode, Digest: digest, Size: int64(len(content))})
+		blobs[digest] = content
+	}
+	write := func(name string, b []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(b))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(manifestName, b)
+	for digest, content := range blobs {
+		write(blobsDir+digest, []byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		mode  os.FileMode
+		// restored files, relative to the root
+		want     map[string]string
+		wantMode os.FileMode
+		wantErr  bool
+	}{
+		{
+			name:     "files",
+			files:    map[string]string{"/schemas/a.yang": "module a {}", "/schemas/sub/b.yang": "module b {}"},
+			mode:     0o640,
+			want:     map[string]string{"schemas/a.yang": "module a {}", "schemas/sub/b.yang": "module b {}"},
+			wantMode: 0o640,
+		},
+		{
+			name:     "setuid bit dropped",
+			files:    map[string]string{"/schemas/a.yang": "module a {}"},
+			mode:     os.ModeSetuid | os.ModeSetgid | os.ModeSticky | 0o755,
+			want:     map[string]string{"schemas/a.yang": "module a {}"},
+			wantMode: 0o755,
+		},
+		{
+			name:    "relative path",
+			files:   map[string]string{"schemas/a.yang": "module a {}"},
+			mode:    0o644,
+			wantErr: true,
+		},
+		{
+			name:    "path escaping the root",
+			files:   map[string]string{"/../../etc/passwd": "root::0:0::/:/bin/sh"},
+			mode:    0o644,
+			wantErr: true,
+		},
+		{
+			name:    "path not clean",
+			files:   map[string]string{"/schemas/../../a.yang": "module a {}"},
+			mode:    0o644,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			_, err := Restore(context.Background(), bytes.NewReader(testArchive(t, tt.files, tt.mode)), &RestoreOptions{Root: root})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				des, _ := os.ReadDir(root)
+				if len(des) != 0 {
+					t.Errorf("Restore() wrote %d entries under the root", len(des))
+				}
+				return
+			}
+			for p, content := range tt.want {
+				fp := filepath.Join(root, filepath.FromSlash(p))
+				b, err := os.ReadFile(fp)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(b) != content {
+					t.Errorf("%s = %q, want %q", p, b, content)
+				}
+				fi, err := os.Stat(fp)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if fi.Mode() != tt.wantMode {
+					t.Errorf("%s mode = %v, want %v", p, fi.Mode(), tt.wantMode)
+				}
+			}
+		})
+	}
+}
+
+func TestRestore_exists(t *testing.T) {
+	files := map[string]string{"/schemas/a.yang": "module a {}"}
+	tests := []struct {
+		name    string
+		force   bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "without force",
+			want:    "old",
+			wantErr: true,
+		},
+		{
+			name:  "with force",
+			force: true,
+			want:  "module a {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			p := filepath.Join(root, "schemas", "a.yang")
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte("old"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Restore(context.Background(), bytes.NewReader(testArchive(t, files, 0o644)), &RestoreOptions{Root: root, Force: tt.force})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("content = %q, want %q", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestore_symlink(t *testing.T) {
+	files := map[string]string{"/schemas/a.yang": "module a {}"}
+	tests := []struct {
+		name string
+		// creates the symlink under root to outside
+		link func(root, outside string) error
+	}{
+		{
+			name: "file",
+			link: func(root, outside string) error {
+				if err := os.MkdirAll(filepath.Join(root, "schemas"), 0o755); err != nil {
+					return err
+				}
+				return os.Symlink(filepath.Join(outside, "a.yang"), filepath.Join(root, "schemas", "a.yang"))
+			},
+		},
+		{
+			name: "dangling file",
+			link: func(root, outside string) error {
+				if err := os.MkdirAll(filepath.Join(root, "schemas"), 0o755); err != nil {
+					return err
+				}
+				return os.Symlink(filepath.Join(outside, "b.yang"), filepath.Join(root, "schemas", "a.yang"))
+			},
+		},
+		{
+			name: "directory",
+			link: func(root, outside string) error {
+				return os.Symlink(outside, filepath.Join(root, "schemas"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, force := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s force %v", tt.name, force), func(t *testing.T) {
+				root, outside := t.TempDir(), t.TempDir()
+				target := filepath.Join(outside, "a.yang")
+				if err := os.WriteFile(target, []byte("old"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if err := tt.link(root, outside); err != nil {
+					t.Skipf("symlink: %v", err)
+				}
+				_, err := Restore(context.Background(), bytes.NewReader(testArchive(t, files, 0o644)), &RestoreOptions{Root: root, Force: force})
+				if err == nil {
+					t.Error("Restore() error = nil, want an error")
+				}
+				des, err := os.ReadDir(outside)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(des) != 1 {
+					t.Errorf("Restore() wrote %d files outside of the root", len(des)-1)
+				}
+				b, err := os.ReadFile(target)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(b) != "old" {
+					t.Errorf("Restore() wrote %q outside of the root", b)
+				}
+			})
+		}
+	}
+}
+
+func Test_copyTo_symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.WriteFile(target, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(dir, "a.yang")
+	if err := os.Symlink(target, p); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+	f, err := os.CreateTemp(dir, "content-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("module a {}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := copyTo(f, p, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the symlink is replaced
+	fi, err := os.Lstat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.Mode().IsRegular() {
+		t.Errorf("%s mode = %v, want a regular file", p, fi.Mode())
+	}
+	b, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "old" {
+		t.Errorf("copyTo() wrote %q through the symlink", b)
+	}
+}
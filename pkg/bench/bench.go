@@ -0,0 +1,379 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bench runs standardized lookup and expansion workloads against
+// an in-process schema store and reports comparable numbers,
+// see schema-server bench.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/memstore"
+	"github.com/sdcio/schema-server/pkg/store/persiststore"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// FormatVersion is the version of the JSON report, a baseline
+// written by another version is not compared.
+const FormatVersion = 1
+
+// workloads, run in this order
+const (
+	WorkloadGet         = "get"
+	WorkloadGetElements = "get-elements"
+	WorkloadExpand      = "expand"
+)
+
+var workloads = []string{WorkloadGet, WorkloadGetElements, WorkloadExpand}
+
+type Options struct {
+	Schema *config.SchemaConfig
+	// xpaths the workloads run on, the top level data nodes if empty
+	Paths []string
+	// store type, memory or persistent, the persistent store
+	// is created in a temporary directory
+	Store string
+	// runs of each workload over the paths, after a warmup run
+	Iterations int
+}
+
+// Report is the result of a bench run.
+type Report struct {
+	FormatVersion int       `json:"format-version"`
+	Schema        string    `json:"schema"`
+	Store         string    `json:"store"`
+	GoVersion     string    `json:"go-version"`
+	GOMAXPROCS    int       `json:"gomaxprocs"`
+	Time          time.Time `json:"time"`
+	Paths         int       `json:"paths"`
+	Iterations    int       `json:"iterations"`
+	// time taken to parse the schema and add it to the store
+	Load      time.Duration `json:"load"`
+	Workloads []*Result     `json:"workloads"`
+}
+
+// Result are the numbers of a workload, per operation.
+type Result struct {
+	Name string        `json:"name"`
+	Ops  int           `json:"ops"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P99  time.Duration `json:"p99"`
+	// schema elements or paths returned
+	Items       float64 `json:"items"`
+	AllocsPerOp uint64  `json:"allocs-per-op"`
+	BytesPerOp  uint64  `json:"bytes-per-op"`
+}
+
+// Run loads the schema and runs the workloads.
+func Run(ctx context.Context, o *Options) (*Report, error) {
+	if o.Iterations <= 0 {
+		o.Iterations = 1
+	}
+	rpt := &Report{
+		FormatVersion: FormatVersion,
+		Schema:        o.Schema.Name + "@" + o.Schema.Vendor + "@" + o.Schema.Version,
+		Store:         o.Store,
+		GoVersion:     runtime.Version(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		Time:          time.Now(),
+		Iterations:    o.Iterations,
+	}
+	var s store.Store
+	switch o.Store {
+	case config.StoreTypeMemory, "":
+		rpt.Store = config.StoreTypeMemory
+		s = memstore.New()
+	case config.StoreTypePersistent:
+		dir, err := os.MkdirTemp("", "schema-server-bench-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		s, err = persiststore.New(ctx, dir, nil)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown store type %q", o.Store)
+	}
+	start := time.Now()
+	sc, err := schema.NewSchema(o.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.AddSchema(sc); err != nil {
+		return nil, err
+	}
+	rpt.Load = time.Since(start)
+	b := &bencher{store: s, schema: o.Schema.GetSchema()}
+	b.paths, err = b.parsePaths(ctx, o.Paths)
+	if err != nil {
+		return nil, err
+	}
+	rpt.Paths = len(b.paths)
+	for _, w := range workloads {
+		r, err := b.run(ctx, w, o.Iterations)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", w, err)
+		}
+		rpt.Workloads = append(rpt.Workloads, r)
+	}
+	return rpt, nil
+}
+
+type bencher struct {
+	store  store.Store
+	schema *sdcpb.Schema
+	paths  []*sdcpb.Path
+}
+
+func (b *bencher) parsePaths(ctx context.Context, xps []string) ([]*sdcpb.Path, error) {
+	if len(xps) == 0 {
+		return b.topLevelPaths(ctx)
+	}
+	ps := make([]*sdcpb.Path, 0, len(xps))
+	for _, xp := range xps {
+		p, err := utils.ParsePath(xp)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %v", xp, err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// topLevelPaths returns the paths of the top level data nodes.
+func (b *bencher) topLevelPaths(ctx context.Context) ([]*sdcpb.Path, error) {
+	r := store.NewResolver(b.store, b.schema)
+	root, err := r.Get(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var ps []*sdcpb.Path
+	for _, m := range root.GetContainer().GetChildren() {
+		se, err := r.Get(ctx, []string{m})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range se.GetContainer().GetChildren() {
+			ps = append(ps, &sdcpb.Path{Elem: []*sdcpb.PathElem{{Name: c}}})
+		}
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].GetElem()[0].GetName() < ps[j].GetElem()[0].GetName() })
+	return ps, nil
+}
+
+// run runs workload w over the paths iterations times, after a warmup run.
+func (b *bencher) run(ctx context.Context, w string, iterations int) (*Result, error) {
+	if _, err := b.pass(ctx, w, nil); err != nil {
+		return nil, err
+	}
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	durs := make([]time.Duration, 0, iterations*len(b.paths))
+	items := 0
+	for i := 0; i < iterations; i++ {
+		n, err := b.pass(ctx, w, &durs)
+		if err != nil {
+			return nil, err
+		}
+		items += n
+	}
+	runtime.ReadMemStats(&after)
+	r := &Result{Name: w, Ops: len(durs)}
+	if r.Ops == 0 {
+		return r, nil
+	}
+	sort.Slice(durs, func(i, j int) bool { return durs[i] < durs[j] })
+	var total time.Duration
+	for _, d := range durs {
+		total += d
+	}
+	r.Mean = total / time.Duration(r.Ops)
+	r.P50 = durs[r.Ops/2]
+	r.P99 = durs[(r.Ops*99)/100]
+	r.Items = float64(items) / float64(r.Ops)
+	r.AllocsPerOp = (after.Mallocs - before.Mallocs) / uint64(r.Ops)
+	r.BytesPerOp = (after.TotalAlloc - before.TotalAlloc) / uint64(r.Ops)
+	return r, nil
+}
+
+// pass runs workload w once on each path, appending the durations to durs if not nil.
+// It returns the number of items returned.
+func (b *bencher) pass(ctx context.Context, w string, durs *[]time.Duration) (int, error) {
+	items := 0
+	for _, p := range b.paths {
+		start := time.Now()
+		n, err := b.op(ctx, w, p)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %v", utils.ToXPath(p, false), err)
+		}
+		if durs != nil {
+			*durs = append(*durs, time.Since(start))
+		}
+		items += n
+	}
+	return items, nil
+}
+
+func (b *bencher) op(ctx context.Context, w string, p *sdcpb.Path) (int, error) {
+	switch w {
+	case WorkloadGet:
+		_, err := b.store.GetSchema(ctx, &sdcpb.GetSchemaRequest{Schema: b.schema, Path: p})
+		return 1, err
+	case WorkloadGetElements:
+		ch, err := b.store.GetSchemaElements(ctx, &sdcpb.GetSchemaRequest{Schema: b.schema, Path: p})
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for range ch {
+			n++
+		}
+		return n, nil
+	case WorkloadExpand:
+		rsp, err := b.store.ExpandPath(ctx, &sdcpb.ExpandPathRequest{Schema: b.schema, Path: p, DataType: sdcpb.DataType_ALL})
+		if err != nil {
+			return 0, err
+		}
+		return len(rsp.GetPath()), nil
+	}
+	return 0, fmt.Errorf("unknown workload %q", w)
+}
+
+// ReadPaths reads the xpaths of a file, one per line,
+// empty lines and lines starting with # are ignored.
+func ReadPaths(file string) ([]string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var xps []string
+	for _, l := range strings.Split(string(b), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		xps = append(xps, l)
+	}
+	return xps, nil
+}
+
+// ReadReport reads a JSON report written by WriteJSON.
+func ReadReport(file string) (*Report, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rpt := new(Report)
+	if err := json.Unmarshal(b, rpt); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if rpt.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%s: report format v%d, expected v%d", file, rpt.FormatVersion, FormatVersion)
+	}
+	return rpt, nil
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// Regression is a workload slower than in the baseline by more than the threshold.
+type Regression struct {
+	Workload string
+	Baseline time.Duration
+	Current  time.Duration
+}
+
+func (r *Regression) String() string {
+	return fmt.Sprintf("%s: mean %s, baseline %s (%+.1f%%)", r.Workload, r.Current, r.Baseline, change(r.Baseline, r.Current))
+}
+
+// Compare returns the workloads whose mean is more than threshold
+// (e.g. 0.1 for 10%) above the one of baseline.
+func (r *Report) Compare(baseline *Report, threshold float64) []*Regression {
+	var rs []*Regression
+	for _, w := range r.Workloads {
+		bw := baseline.workload(w.Name)
+		if bw == nil || bw.Mean == 0 {
+			continue
+		}
+		if change(bw.Mean, w.Mean) > threshold*100 {
+			rs = append(rs, &Regression{Workload: w.Name, Baseline: bw.Mean, Current: w.Mean})
+		}
+	}
+	return rs
+}
+
+func (r *Report) workload(name string) *Result {
+	for _, w := range r.Workloads {
+		if w.Name == name {
+			return w
+		}
+	}
+	return nil
+}
+
+// change returns the change from a to b in percent.
+func change(a, b time.Duration) float64 {
+	return (float64(b) - float64(a)) * 100 / float64(a)
+}
+
+// WriteText writes the report as a table, with the change of the mean
+// from baseline if not nil.
+func (r *Report) WriteText(w io.Writer, baseline *Report) error {
+	fmt.Fprintf(w, "schema %s, %s store, %s, GOMAXPROCS %d\n", r.Schema, r.Store, r.GoVersion, r.GOMAXPROCS)
+	fmt.Fprintf(w, "loaded in %s, %d path(s) x %d iteration(s)\n\n", r.Load, r.Paths, r.Iterations)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "WORKLOAD\tOPS\tMEAN\tP50\tP99\tITEMS/OP\tALLOCS/OP\tBYTES/OP"
+	if baseline != nil {
+		header += "\tBASELINE\tCHANGE"
+	}
+	fmt.Fprintln(tw, header)
+	for _, res := range r.Workloads {
+		line := fmt.Sprintf("%s\t%d\t%s\t%s\t%s\t%.1f\t%d\t%d", res.Name, res.Ops, res.Mean, res.P50, res.P99, res.Items, res.AllocsPerOp, res.BytesPerOp)
+		if baseline != nil {
+			if bw := baseline.workload(res.Name); bw != nil && bw.Mean > 0 {
+				line += fmt.Sprintf("\t%s\t%+.1f%%", bw.Mean, change(bw.Mean, res.Mean))
+			} else {
+				line += "\t-\t-"
+			}
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
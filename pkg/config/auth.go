@@ -0,0 +1,159 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTenantClaim     = "tenant"
+	defaultJWKSRefresh     = 15 * time.Minute
+	defaultJWKSTimeout     = 10 * time.Second
+	defaultJWTClockSkew    = 30 * time.Second
+	defaultTokenSubjectFmt = "token-%d"
+)
+
+// AuthenticationConfig requires each RPC to carry a bearer token
+// in its authorization metadata: "authorization: Bearer <token>".
+// The tenant of the request is the one of the token, the x-tenant metadata is ignored.
+type AuthenticationConfig struct {
+	// static tokens, checked before the JWT
+	Tokens []*TokenConfig `yaml:"tokens,omitempty" json:"tokens,omitempty"`
+	JWT    *JWTConfig     `yaml:"jwt,omitempty" json:"jwt,omitempty"`
+}
+
+type TokenConfig struct {
+	// the token or the file it is read from
+	Token     string `yaml:"token,omitempty" json:"-"`
+	TokenFile string `yaml:"token-file,omitempty" json:"token-file,omitempty"`
+	// identify the requests sent with the token
+	Subject string `yaml:"subject,omitempty" json:"subject,omitempty"`
+	Tenant  string `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+}
+
+// JWTConfig validates signed JWTs using the keys published at a JWKS URL.
+type JWTConfig struct {
+	JWKSURL string `yaml:"jwks-url,omitempty" json:"jwks-url,omitempty"`
+	// expected iss and aud claims, not checked if empty
+	Issuer   string `yaml:"issuer,omitempty" json:"issuer,omitempty"`
+	Audience string `yaml:"audience,omitempty" json:"audience,omitempty"`
+	// claim holding the tenant of the caller
+	TenantClaim string `yaml:"tenant-claim,omitempty" json:"tenant-claim,omitempty"`
+	// interval the keys are fetched again at,
+	// they are also fetched when a token is signed by an unknown key.
+	RefreshInterval time.Duration `yaml:"refresh-interval,omitempty" json:"refresh-interval,omitempty"`
+	Timeout         time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// accepted clock difference with the token issuer
+	ClockSkew time.Duration `yaml:"clock-skew,omitempty" json:"clock-skew,omitempty"`
+	TLS       *TLS          `yaml:"tls,omitempty" json:"tls,omitempty"`
+}
+
+// TenantAuthorization lists the schemas a tenant is served from.
+type TenantAuthorization struct {
+	Tenant  string           `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+	Schemas []*SchemaPattern `yaml:"schemas,omitempty" json:"schemas,omitempty"`
+	// allows the RPCs not referring to a schema, e.g. ReloadConfig,
+	// the other tenants can only call the ones listing schemas
+	Admin bool `yaml:"admin,omitempty" json:"admin,omitempty"`
+}
+
+// SchemaPattern matches a schema using shell patterns, an empty field matches any value.
+type SchemaPattern struct {
+	Name    string `yaml:"name,omitempty" json:"name,omitempty"`
+	Vendor  string `yaml:"vendor,omitempty" json:"vendor,omitempty"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
+// Match reports whether the schema name, vendor and version match the pattern.
+func (p *SchemaPattern) Match(name, vendor, version string) bool {
+	return matchField(p.Name, name) && matchField(p.Vendor, vendor) && matchField(p.Version, version)
+}
+
+func matchField(pattern, v string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, v)
+	return ok
+}
+
+func (a *AuthenticationConfig) validateSetDefaults() error {
+	if len(a.Tokens) == 0 && a.JWT == nil {
+		return errors.New("authentication: missing tokens or jwt")
+	}
+	for i, t := range a.Tokens {
+		if t.TokenFile != "" {
+			if t.Token != "" {
+				return fmt.Errorf("authentication: tokens[%d]: token and token-file are mutually exclusive", i)
+			}
+			b, err := os.ReadFile(t.TokenFile)
+			if err != nil {
+				return fmt.Errorf("authentication: tokens[%d]: %w", i, err)
+			}
+			t.Token = strings.TrimSpace(string(b))
+		}
+		if t.Token == "" {
+			return fmt.Errorf("authentication: tokens[%d]: missing token", i)
+		}
+		if t.Subject == "" {
+			t.Subject = fmt.Sprintf(defaultTokenSubjectFmt, i)
+		}
+	}
+	if j := a.JWT; j != nil {
+		if j.JWKSURL == "" {
+			return errors.New("authentication: missing jwt jwks-url")
+		}
+		if j.TenantClaim == "" {
+			j.TenantClaim = defaultTenantClaim
+		}
+		if j.RefreshInterval <= 0 {
+			j.RefreshInterval = defaultJWKSRefresh
+		}
+		if j.Timeout <= 0 {
+			j.Timeout = defaultJWKSTimeout
+		}
+		if j.ClockSkew <= 0 {
+			j.ClockSkew = defaultJWTClockSkew
+		}
+	}
+	return nil
+}
+
+func validateTenants(tenants []*TenantAuthorization) error {
+	seen := make(map[string]struct{}, len(tenants))
+	for i, t := range tenants {
+		if t.Tenant == "" {
+			return fmt.Errorf("authorization: tenants[%d]: missing tenant", i)
+		}
+		if _, ok := seen[t.Tenant]; ok {
+			return fmt.Errorf("authorization: duplicate tenant %q", t.Tenant)
+		}
+		seen[t.Tenant] = struct{}{}
+		for _, p := range t.Schemas {
+			for _, f := range []string{p.Name, p.Vendor, p.Version} {
+				if _, err := path.Match(f, ""); err != nil {
+					return fmt.Errorf("authorization: tenant %q: invalid pattern %q", t.Tenant, f)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -18,8 +18,11 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -28,9 +31,26 @@ import (
 )
 
 const (
-	defaultServerAddress = ":55000"
-	defaultMessageSize   = 4 * 1024 * 1024
-	defaultRPCTimeout    = time.Minute
+	PathQualificationNone    = ""
+	PathQualificationModule  = "module"
+	PathQualificationRFC7951 = "rfc7951"
+)
+
+const (
+	defaultServerAddress  = ":55000"
+	defaultMessageSize    = 4 * 1024 * 1024
+	defaultRPCTimeout     = time.Minute
+	defaultDrainTimeout   = 5 * time.Second
+	defaultJournalSize    = 1000
+	defaultBatchSize      = 16
+	defaultInFlightBytes  = 1024 * 1024
+	defaultSpillThreshold = 64 * 1024 * 1024
+	defaultMaxSpillSize   = 1024 * 1024 * 1024
+	defaultWebhookTimeout = 5 * time.Second
+	defaultGitDirectory   = "./git-sources"
+	defaultGitTimeout     = 10 * time.Minute
+	defaultFetchDirectory = "./fetched-sources"
+	defaultSharedMemory   = "/dev/shm/schema-server"
 )
 
 type Config struct {
@@ -38,6 +58,10 @@ type Config struct {
 	SchemaStore *SchemaStoreConfig `yaml:"schema-store,omitempty" json:"schema-store,omitempty"`
 	// Schemas     []*SchemaConfig    `yaml:"schemas,omitempty" json:"schemas,omitempty"`
 	Prometheus *PromConfig `yaml:"prometheus,omitempty" json:"prometheus,omitempty"`
+	// spreads the schemas over several instances
+	Sharding *ShardingConfig `yaml:"sharding,omitempty" json:"sharding,omitempty"`
+	// OpenTelemetry traces export
+	Tracing *TracingConfig `yaml:"tracing,omitempty" json:"tracing,omitempty"`
 }
 
 type TLS struct {
@@ -45,6 +69,8 @@ type TLS struct {
 	Cert       string `yaml:"cert,omitempty" json:"cert,omitempty"`
 	Key        string `yaml:"key,omitempty" json:"key,omitempty"`
 	SkipVerify bool   `yaml:"skip-verify,omitempty" json:"skip-verify,omitempty"`
+	// server side: require the clients to present a certificate signed by ca
+	ClientAuth bool `yaml:"client-auth,omitempty" json:"client-auth,omitempty"`
 }
 
 func New(file string) (*Config, error) {
@@ -74,15 +100,148 @@ func (c *Config) validateSetDefaults() error {
 	if c.GRPCServer.RPCTimeout <= 0 {
 		c.GRPCServer.RPCTimeout = defaultRPCTimeout
 	}
+	if c.GRPCServer.DrainTimeout <= 0 {
+		c.GRPCServer.DrainTimeout = defaultDrainTimeout
+	}
+	if c.GRPCServer.ResponseSizeWarning < 0 {
+		return errors.New("response-size-warning must not be negative")
+	}
+	if c.GRPCServer.Streaming == nil {
+		c.GRPCServer.Streaming = &StreamingConfig{}
+	}
+	if c.GRPCServer.Streaming.BatchSize <= 0 {
+		c.GRPCServer.Streaming.BatchSize = defaultBatchSize
+	}
+	if c.GRPCServer.Streaming.MaxInFlightBytes <= 0 {
+		c.GRPCServer.Streaming.MaxInFlightBytes = defaultInFlightBytes
+	}
+	if c.GRPCServer.Streaming.SpillThreshold <= 0 {
+		c.GRPCServer.Streaming.SpillThreshold = defaultSpillThreshold
+	}
+	if c.GRPCServer.Streaming.MaxSpillSize <= 0 {
+		c.GRPCServer.Streaming.MaxSpillSize = defaultMaxSpillSize
+	}
+	if c.GRPCServer.Journal != nil && c.GRPCServer.Journal.Size <= 0 {
+		c.GRPCServer.Journal.Size = defaultJournalSize
+	}
+	if c.GRPCServer.TLS != nil && c.GRPCServer.TLS.ClientAuth && c.GRPCServer.TLS.CA == "" {
+		return errors.New("tls: client-auth requires a ca")
+	}
+	if c.GRPCServer.Authentication != nil {
+		if err := c.GRPCServer.Authentication.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.GRPCServer.Authorization != nil {
+		if err := c.GRPCServer.Authorization.validateSetDefaults(c.GRPCServer.TLS, c.GRPCServer.Authentication != nil); err != nil {
+			return err
+		}
+	}
+	legacyNames := make(map[string]struct{}, len(c.GRPCServer.LegacyServiceNames))
+	for _, name := range c.GRPCServer.LegacyServiceNames {
+		if name == "" {
+			return errors.New("legacy service name cannot be empty")
+		}
+		if _, ok := legacyNames[name]; ok {
+			return fmt.Errorf("duplicate legacy service name %q", name)
+		}
+		legacyNames[name] = struct{}{}
+	}
+	switch c.GRPCServer.PathQualification {
+	case PathQualificationNone, PathQualificationModule, PathQualificationRFC7951:
+	default:
+		return fmt.Errorf("unknown path-qualification %q", c.GRPCServer.PathQualification)
+	}
+	if c.GRPCServer.SchemaServer != nil && c.GRPCServer.SchemaServer.Quotas != nil {
+		if err := c.GRPCServer.SchemaServer.Quotas.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.Prometheus != nil {
+		if err := c.Prometheus.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.Sharding != nil {
+		if err := c.Sharding.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.Tracing != nil {
+		if err := c.Tracing.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.GRPCServer.Interceptors == nil {
+		c.GRPCServer.Interceptors = &InterceptorsConfig{}
+	}
+	if err := c.GRPCServer.Interceptors.validateSetDefaults(c.GRPCServer.Journal != nil, c.Prometheus != nil, c.Sharding != nil, c.Tracing != nil,
+		c.GRPCServer.Authentication != nil, c.GRPCServer.Authorization != nil); err != nil {
+		return err
+	}
 	if c.SchemaStore == nil {
 		c.SchemaStore = &SchemaStoreConfig{}
 		return nil
 	}
 	var err error
+	if c.SchemaStore.Limits != nil {
+		if err = c.SchemaStore.Limits.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.SchemaStore.Features != nil {
+		if err = c.SchemaStore.Features.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.SchemaStore.Lint != nil {
+		if err = c.SchemaStore.Lint.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if c.SchemaStore.CacheDirectory != "" && c.SchemaStore.Type != StoreTypeMemory {
+		return errors.New("schema-store cache-directory requires the memory store")
+	}
+	if c.SchemaStore.GitDirectory == "" {
+		c.SchemaStore.GitDirectory = defaultGitDirectory
+	}
+	if c.SchemaStore.GitTimeout <= 0 {
+		c.SchemaStore.GitTimeout = defaultGitTimeout
+	}
+	if c.SchemaStore.FetchDirectory == "" {
+		c.SchemaStore.FetchDirectory = defaultFetchDirectory
+	}
+	if shm := c.SchemaStore.SharedMemory; shm != nil && shm.Directory == "" {
+		shm.Directory = defaultSharedMemory
+	}
+	if err = c.SchemaStore.expandTargetProfiles(); err != nil {
+		return err
+	}
 	for _, sc := range c.SchemaStore.Schemas {
 		if err = sc.validateSetDefaults(); err != nil {
 			return err
 		}
+		if sc.DescriptionLanguage == "" {
+			sc.DescriptionLanguage = c.SchemaStore.DescriptionLanguage
+		}
+		if sc.Features == nil {
+			sc.Features = c.SchemaStore.Features
+		}
+		if sc.Lint == nil {
+			sc.Lint = c.SchemaStore.Lint
+		}
+		if sc.Git != nil && sc.Git.Directory == "" {
+			sc.Git.Directory = filepath.Join(c.SchemaStore.GitDirectory, fmt.Sprintf("%s_%s_%s", sc.Name, sc.Vendor, sc.Version))
+		}
+		if sc.Git != nil && sc.Git.Timeout <= 0 {
+			sc.Git.Timeout = c.SchemaStore.GitTimeout
+		}
+		if sc.OCI != nil && sc.OCI.Directory == "" {
+			sc.OCI.Directory = filepath.Join(c.SchemaStore.FetchDirectory, "oci", fmt.Sprintf("%s_%s_%s", sc.Name, sc.Vendor, sc.Version))
+		}
+		if sc.Source != nil && sc.Source.Directory == "" {
+			sc.Source.Directory = filepath.Join(c.SchemaStore.FetchDirectory, sc.Source.Type, fmt.Sprintf("%s_%s_%s", sc.Name, sc.Vendor, sc.Version))
+		}
 	}
 	return nil
 }
@@ -98,6 +257,134 @@ type GRPCServer struct {
 	SchemaServer   *SchemaServer `yaml:"schema-server,omitempty" json:"schema-server,omitempty"`
 	MaxRecvMsgSize int           `yaml:"max-recv-msg-size,omitempty" json:"max-recv-msg-size,omitempty"`
 	RPCTimeout     time.Duration `yaml:"rpc-timeout,omitempty" json:"rpc-timeout,omitempty"`
+	// listen with SO_REUSEPORT, a replacement process can then bind the
+	// same address while this one drains its connections.
+	ReusePort bool `yaml:"reuse-port,omitempty" json:"reuse-port,omitempty"`
+	// time the in-flight RPCs are given to complete when the server is
+	// terminated or hands its listener over, they are cancelled past it.
+	DrainTimeout time.Duration `yaml:"drain-timeout,omitempty" json:"drain-timeout,omitempty"`
+	// a warning is logged for each response message larger than this number of bytes,
+	// the payload sizes are also recorded in histograms if prometheus is configured.
+	ResponseSizeWarning int `yaml:"response-size-warning,omitempty" json:"response-size-warning,omitempty"`
+	// additional gRPC service names the SchemaServer service is served under,
+	// used to keep clients built against a legacy proto package (e.g iptecharch schemapb) working.
+	// Their RPCs are authorized, journaled and counted under the SchemaServer method names.
+	LegacyServiceNames []string `yaml:"legacy-service-names,omitempty" json:"legacy-service-names,omitempty"`
+	// module qualification of the path elements returned by ToPath and ExpandPath,
+	// one of "" (as resolved), "module" (all elements) or "rfc7951" (only where the module changes)
+	PathQualification string `yaml:"path-qualification,omitempty" json:"path-qualification,omitempty"`
+	// in memory journal of the last requests, dumped using the GetRequestJournal RPC
+	Journal *JournalConfig `yaml:"journal,omitempty" json:"journal,omitempty"`
+	// flow control of the streamed responses
+	Streaming *StreamingConfig `yaml:"streaming,omitempty" json:"streaming,omitempty"`
+	// keepalive pings accepted from the clients
+	Keepalive *KeepaliveConfig `yaml:"keepalive,omitempty" json:"keepalive,omitempty"`
+	// bearer token authentication of each RPC
+	Authentication *AuthenticationConfig `yaml:"authentication,omitempty" json:"authentication,omitempty"`
+	// external authorization of each RPC
+	Authorization *AuthorizationConfig `yaml:"authorization,omitempty" json:"authorization,omitempty"`
+	// order and selection of the interceptors each RPC goes through
+	Interceptors *InterceptorsConfig `yaml:"interceptors,omitempty" json:"interceptors,omitempty"`
+}
+
+// KeepaliveConfig is the keepalive enforcement policy of the server,
+// a client pinging more often than MinTime is disconnected.
+type KeepaliveConfig struct {
+	// 5m if not set, as in gRPC
+	MinTime time.Duration `yaml:"min-time,omitempty" json:"min-time,omitempty"`
+	// accept pings on connections without active stream
+	PermitWithoutStream bool `yaml:"permit-without-stream,omitempty" json:"permit-without-stream,omitempty"`
+}
+
+type AuthorizationConfig struct {
+	Webhook *WebhookConfig `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	// RPCs the clients are allowed to call, identified by their verified certificate.
+	// A request is served if one of the entries allows it (and the webhook, if any).
+	Clients []*ClientAuthorization `yaml:"clients,omitempty" json:"clients,omitempty"`
+	// schemas each tenant is served from, the requests referring to another schema
+	// are denied and the schemas listed to the tenant are filtered.
+	// Requires authentication, the tenant being the one of the token.
+	Tenants []*TenantAuthorization `yaml:"tenants,omitempty" json:"tenants,omitempty"`
+}
+
+// ClientAuthorization allows the clients with a certificate matching one of Principals
+// to call the RPCs matching one of Methods.
+type ClientAuthorization struct {
+	// shell patterns matched against the subject common name and the
+	// DNS, URI, email and IP SANs of the certificate, e.g. *.ops.example.com
+	Principals []string `yaml:"principals,omitempty" json:"principals,omitempty"`
+	// shell patterns matched against the RPC name, e.g. Get*,
+	// or the full method name if they start with a /, e.g. /schema.SchemaServer/*
+	Methods []string `yaml:"methods,omitempty" json:"methods,omitempty"`
+}
+
+func (a *AuthorizationConfig) validateSetDefaults(tlsCfg *TLS, authn bool) error {
+	if a.Webhook == nil && len(a.Clients) == 0 && len(a.Tenants) == 0 {
+		return errors.New("authorization: missing webhook, clients or tenants")
+	}
+	// without it the tenant is the one of the x-tenant metadata, set by any client
+	if len(a.Tenants) > 0 && !authn {
+		return errors.New("authorization: tenants require authentication")
+	}
+	if err := validateTenants(a.Tenants); err != nil {
+		return err
+	}
+	if wh := a.Webhook; wh != nil {
+		if wh.URL == "" {
+			return errors.New("authorization: missing webhook url")
+		}
+		if wh.Timeout <= 0 {
+			wh.Timeout = defaultWebhookTimeout
+		}
+	}
+	if len(a.Clients) > 0 && (tlsCfg == nil || !tlsCfg.ClientAuth) {
+		return errors.New("authorization: clients require grpc-server tls client-auth")
+	}
+	for i, c := range a.Clients {
+		if len(c.Principals) == 0 || len(c.Methods) == 0 {
+			return fmt.Errorf("authorization: clients[%d]: missing principals or methods", i)
+		}
+		for _, p := range append(c.Principals, c.Methods...) {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("authorization: clients[%d]: invalid pattern %q", i, p)
+			}
+		}
+	}
+	return nil
+}
+
+// WebhookConfig is a policy service the requests context is POSTed to,
+// e.g. an OPA data API endpoint.
+type WebhookConfig struct {
+	URL     string        `yaml:"url,omitempty" json:"url,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	TLS     *TLS          `yaml:"tls,omitempty" json:"tls,omitempty"`
+	// headers added to the webhook requests, e.g. Authorization
+	Headers map[string]string `yaml:"headers,omitempty" json:"-"`
+	// allow the requests when the webhook cannot be reached
+	// or returns an invalid response, they are denied otherwise.
+	FailOpen bool `yaml:"fail-open,omitempty" json:"fail-open,omitempty"`
+}
+
+// StreamingConfig bounds the messages buffered server side for a response stream:
+// messages are read from the store in batches while at most MaxInFlightBytes
+// of them wait for the client to make room.
+// Responses built before being streamed, e.g. ExpandPathStream, are kept in memory
+// up to SpillThreshold bytes and in a temporary file of SpillDir beyond,
+// they fail with ResourceExhausted beyond MaxSpillSize bytes.
+type StreamingConfig struct {
+	BatchSize        int `yaml:"batch-size,omitempty" json:"batch-size,omitempty"`
+	MaxInFlightBytes int `yaml:"max-in-flight-bytes,omitempty" json:"max-in-flight-bytes,omitempty"`
+	SpillThreshold   int `yaml:"spill-threshold,omitempty" json:"spill-threshold,omitempty"`
+	// defaults to the OS temporary directory
+	SpillDir string `yaml:"spill-dir,omitempty" json:"spill-dir,omitempty"`
+	// defaults to 1GiB
+	MaxSpillSize int64 `yaml:"max-spill-size,omitempty" json:"max-spill-size,omitempty"`
+}
+
+type JournalConfig struct {
+	// max number of requests kept
+	Size int `yaml:"size,omitempty" json:"size,omitempty"`
 }
 
 func (t *TLS) NewConfig(ctx context.Context) (*tls.Config, error) {
@@ -111,6 +398,10 @@ func (t *TLS) NewConfig(ctx context.Context) (*tls.Config, error) {
 			caCertPool := x509.NewCertPool()
 			caCertPool.AppendCertsFromPEM(ca)
 			tlsCfg.RootCAs = caCertPool
+			if t.ClientAuth {
+				tlsCfg.ClientCAs = caCertPool
+				tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
 		}
 	}
 
@@ -133,4 +424,53 @@ func (t *TLS) NewConfig(ctx context.Context) (*tls.Config, error) {
 type SchemaServer struct {
 	// Enabled          bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	SchemasDirectory string `yaml:"schemas-directory,omitempty" json:"schemas-directory,omitempty"`
+	// keep the uploaded schemas across restarts: their files stay in the schemas directory,
+	// listed in a registry file, and they are loaded again on startup.
+	PersistUploads bool `yaml:"persist-uploads,omitempty" json:"persist-uploads,omitempty"`
+	// limits of the schemas each client can upload
+	Quotas *UploadQuotas `yaml:"quotas,omitempty" json:"quotas,omitempty"`
+}
+
+// UploadQuotas bounds the schemas uploaded by each client, a client is identified
+// by the common name of its certificate, or else by the tenant or subject of its
+// authenticated bearer token. The other clients share the "anonymous" owner.
+type UploadQuotas struct {
+	// quota of the clients not listed in owners
+	Default *UploadQuota `yaml:"default,omitempty" json:"default,omitempty"`
+	// quotas per client
+	Owners map[string]*UploadQuota `yaml:"owners,omitempty" json:"owners,omitempty"`
+}
+
+// UploadQuota is the quota of a client, a zero value means no limit.
+type UploadQuota struct {
+	// max number of uploaded schemas
+	MaxSchemas int `yaml:"max-schemas,omitempty" json:"max-schemas,omitempty"`
+	// max total size of the uploaded files, in bytes
+	MaxBytes int64 `yaml:"max-bytes,omitempty" json:"max-bytes,omitempty"`
+}
+
+func (q *UploadQuotas) validateSetDefaults() error {
+	if q.Default == nil {
+		q.Default = &UploadQuota{}
+	}
+	if q.Default.MaxSchemas < 0 || q.Default.MaxBytes < 0 {
+		return errors.New("upload quotas cannot be negative")
+	}
+	for owner, oq := range q.Owners {
+		if oq == nil {
+			return fmt.Errorf("missing upload quota of %q", owner)
+		}
+		if oq.MaxSchemas < 0 || oq.MaxBytes < 0 {
+			return fmt.Errorf("upload quota of %q cannot be negative", owner)
+		}
+	}
+	return nil
+}
+
+// Quota returns the upload quota of owner.
+func (q *UploadQuotas) Quota(owner string) *UploadQuota {
+	if oq, ok := q.Owners[owner]; ok {
+		return oq
+	}
+	return q.Default
 }
@@ -0,0 +1,193 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// interceptors of the gRPC server RPCs
+const (
+	// span of each RPC, requires tracing to be configured
+	InterceptorTracing = "tracing"
+	// request attribution, seen by the interceptors that follow it
+	InterceptorAttribution = "attribution"
+	// converts the panics of the interceptors and handlers that follow it into Internal errors
+	InterceptorRecovery = "recovery"
+	// logs the method, result code and latency of each RPC
+	InterceptorLogging = "logging"
+	// in memory request journal, requires the journal to be configured
+	InterceptorJournal = "journal"
+	// rpc-timeout deadline of the unary RPCs
+	InterceptorTimeout = "timeout"
+	// gRPC server metrics, requires prometheus to be configured
+	InterceptorMetrics = "metrics"
+	// token bucket limiting the rate of RPCs, requires rate-limit to be configured
+	InterceptorRateLimit = "rate-limit"
+	InterceptorAuthn     = "authn"
+	InterceptorAuthz     = "authz"
+	// redirects the requests for the schemas owned by another instance, requires sharding to be configured
+	InterceptorSharding = "sharding"
+	// routes the requests to the schema derived for the target profile they ask for
+	InterceptorTargetProfile = "target-profile"
+	InterceptorCanary        = "canary"
+	InterceptorLifecycle     = "lifecycle"
+)
+
+// defaultInterceptors is the order the interceptors are chained in if none is configured,
+// the ones depending on a disabled feature are left out.
+var defaultInterceptors = []string{
+	InterceptorTracing,
+	InterceptorAttribution,
+	InterceptorRecovery,
+	InterceptorJournal,
+	InterceptorTimeout,
+	InterceptorMetrics,
+	InterceptorAuthn,
+	InterceptorAuthz,
+	InterceptorSharding,
+	InterceptorTargetProfile,
+	InterceptorCanary,
+	InterceptorLifecycle,
+}
+
+type InterceptorsConfig struct {
+	// interceptors each RPC goes through, outermost first.
+	// defaults to tracing, attribution, recovery, journal, timeout, metrics, authn, authz, sharding, target-profile, canary and lifecycle.
+	Order []string `yaml:"order,omitempty" json:"order,omitempty"`
+	// interceptors removed from the chain
+	Disabled []string `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	// settings of the rate-limit interceptor
+	RateLimit *RateLimitConfig `yaml:"rate-limit,omitempty" json:"rate-limit,omitempty"`
+
+	chain []string
+}
+
+// RateLimitConfig is a token bucket shared by all the clients,
+// an RPC exceeding it fails with ResourceExhausted.
+type RateLimitConfig struct {
+	// RPCs per second
+	Rate float64 `yaml:"rate,omitempty" json:"rate,omitempty"`
+	// RPCs allowed in a burst, defaults to the rate rounded up
+	Burst int `yaml:"burst,omitempty" json:"burst,omitempty"`
+}
+
+// interceptors that must run after another one of the chain
+var interceptorsAfter = []struct{ name, after string }{
+	// authn sets the identity of the caller on the request attribution
+	{InterceptorAuthn, InterceptorAttribution},
+	{InterceptorAuthz, InterceptorAuthn},
+}
+
+func (c *InterceptorsConfig) validateSetDefaults(journal, metrics, sharding, tracing, authn, authz bool) error {
+	available := map[string]bool{
+		InterceptorTracing:       tracing,
+		InterceptorAttribution:   true,
+		InterceptorRecovery:      true,
+		InterceptorLogging:       true,
+		InterceptorJournal:       journal,
+		InterceptorTimeout:       true,
+		InterceptorMetrics:       metrics,
+		InterceptorRateLimit:     c.RateLimit != nil,
+		InterceptorAuthn:         true,
+		InterceptorAuthz:         true,
+		InterceptorSharding:      sharding,
+		InterceptorTargetProfile: true,
+		InterceptorCanary:        true,
+		InterceptorLifecycle:     true,
+	}
+	requires := map[string]string{
+		InterceptorJournal:   "grpc-server journal",
+		InterceptorMetrics:   "prometheus",
+		InterceptorRateLimit: "interceptors rate-limit",
+		InterceptorSharding:  "sharding",
+		InterceptorTracing:   "tracing",
+	}
+	disabled := make(map[string]bool, len(c.Disabled))
+	for _, name := range c.Disabled {
+		if _, ok := available[name]; !ok {
+			return fmt.Errorf("interceptors: unknown interceptor %q", name)
+		}
+		disabled[name] = true
+	}
+	if c.RateLimit != nil {
+		if c.RateLimit.Rate <= 0 {
+			return errors.New("interceptors: rate-limit rate must be positive")
+		}
+		if c.RateLimit.Burst <= 0 {
+			c.RateLimit.Burst = int(math.Ceil(c.RateLimit.Rate))
+		}
+	}
+	c.chain = nil
+	if len(c.Order) == 0 {
+		for _, name := range defaultInterceptors {
+			if available[name] && !disabled[name] {
+				c.chain = append(c.chain, name)
+			}
+		}
+		return c.validateChain(authn, authz)
+	}
+	seen := make(map[string]struct{}, len(c.Order))
+	for _, name := range c.Order {
+		ok, known := available[name]
+		if !known {
+			return fmt.Errorf("interceptors: unknown interceptor %q", name)
+		}
+		if _, dup := seen[name]; dup {
+			return fmt.Errorf("interceptors: duplicate interceptor %q", name)
+		}
+		seen[name] = struct{}{}
+		if disabled[name] {
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("interceptors: %s requires %s", name, requires[name])
+		}
+		c.chain = append(c.chain, name)
+	}
+	return c.validateChain(authn, authz)
+}
+
+// validateChain checks that the configured authentication and authorization
+// are enforced, and that the interceptors run after the ones they depend on.
+func (c *InterceptorsConfig) validateChain(authn, authz bool) error {
+	index := make(map[string]int, len(c.chain))
+	for i, name := range c.chain {
+		index[name] = i
+	}
+	for _, r := range []struct {
+		name       string
+		configured bool
+	}{{InterceptorAuthn, authn}, {InterceptorAuthz, authz}} {
+		if _, ok := index[r.name]; r.configured && !ok {
+			return fmt.Errorf("interceptors: %s is configured but not in the chain", r.name)
+		}
+	}
+	for _, ia := range interceptorsAfter {
+		i, ok := index[ia.name]
+		j, okAfter := index[ia.after]
+		if ok && okAfter && i < j {
+			return fmt.Errorf("interceptors: %s must come after %s", ia.name, ia.after)
+		}
+	}
+	return nil
+}
+
+// Chain returns the interceptors to chain, outermost first.
+func (c *InterceptorsConfig) Chain() []string {
+	return c.chain
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterceptorsConfig_validateSetDefaults(t *testing.T) {
+	type features struct {
+		journal, metrics, sharding, tracing, authn, authz bool
+	}
+	tests := []struct {
+		name     string
+		cfg      *InterceptorsConfig
+		features features
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "default chain",
+			cfg:  &InterceptorsConfig{},
+			want: []string{
+				InterceptorAttribution, InterceptorRecovery, InterceptorTimeout,
+				InterceptorAuthn, InterceptorAuthz,
+				InterceptorTargetProfile, InterceptorCanary, InterceptorLifecycle,
+			},
+		},
+		{
+			name:     "default chain with all the features",
+			cfg:      &InterceptorsConfig{},
+			features: features{journal: true, metrics: true, sharding: true, tracing: true, authn: true, authz: true},
+			want:     defaultInterceptors,
+		},
+		{
+			name: "disabled",
+			cfg:  &InterceptorsConfig{Disabled: []string{InterceptorTimeout, InterceptorCanary}},
+			want: []string{
+				InterceptorAttribution, InterceptorRecovery,
+				InterceptorAuthn, InterceptorAuthz,
+				InterceptorTargetProfile, InterceptorLifecycle,
+			},
+		},
+		{
+			name:     "custom order",
+			cfg:      &InterceptorsConfig{Order: []string{InterceptorRecovery, InterceptorAttribution, InterceptorAuthn, InterceptorAuthz}},
+			features: features{authn: true, authz: true},
+			want:     []string{InterceptorRecovery, InterceptorAttribution, InterceptorAuthn, InterceptorAuthz},
+		},
+		{
+			name:    "unknown interceptor",
+			cfg:     &InterceptorsConfig{Order: []string{"unknown"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate interceptor",
+			cfg:     &InterceptorsConfig{Order: []string{InterceptorRecovery, InterceptorRecovery}},
+			wantErr: true,
+		},
+		{
+			name:    "feature not configured",
+			cfg:     &InterceptorsConfig{Order: []string{InterceptorJournal}},
+			wantErr: true,
+		},
+		{
+			name:     "authn missing from the order",
+			cfg:      &InterceptorsConfig{Order: []string{InterceptorAttribution, InterceptorAuthz}},
+			features: features{authn: true, authz: true},
+			wantErr:  true,
+		},
+		{
+			name:     "authz missing from the order",
+			cfg:      &InterceptorsConfig{Order: []string{InterceptorAttribution, InterceptorAuthn}},
+			features: features{authn: true, authz: true},
+			wantErr:  true,
+		},
+		{
+			name:     "authn disabled",
+			cfg:      &InterceptorsConfig{Disabled: []string{InterceptorAuthn}},
+			features: features{authn: true},
+			wantErr:  true,
+		},
+		{
+			name:     "authz disabled",
+			cfg:      &InterceptorsConfig{Order: []string{InterceptorAuthz}, Disabled: []string{InterceptorAuthz}},
+			features: features{authz: true},
+			wantErr:  true,
+		},
+		{
+			name:     "authz before authn",
+			cfg:      &InterceptorsConfig{Order: []string{InterceptorAttribution, InterceptorAuthz, InterceptorAuthn}},
+			features: features{authn: true, authz: true},
+			wantErr:  true,
+		},
+		{
+			name:     "authn before attribution",
+			cfg:      &InterceptorsConfig{Order: []string{InterceptorAuthn, InterceptorAttribution, InterceptorAuthz}},
+			features: features{authn: true, authz: true},
+			wantErr:  true,
+		},
+		{
+			name:    "order checked without authn and authz",
+			cfg:     &InterceptorsConfig{Order: []string{InterceptorAuthz, InterceptorAuthn, InterceptorAttribution}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.features
+			err := tt.cfg.validateSetDefaults(f.journal, f.metrics, f.sharding, f.tracing, f.authn, f.authz)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateSetDefaults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(tt.cfg.Chain(), tt.want) {
+				t.Errorf("Chain() = %v, want %v", tt.cfg.Chain(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+	LintSeverityInfo    = "info"
+)
+
+// LintConfig selects the lint rules run against a schema once it is loaded,
+// see schemac schema lint --list-rules for the rule names.
+type LintConfig struct {
+	// rules run, all the rules if empty
+	Rules []string `yaml:"rules,omitempty" json:"rules,omitempty"`
+	// rules not run
+	Disable []string `yaml:"disable,omitempty" json:"disable,omitempty"`
+	// severity of the diagnostics by rule name, overriding the rule default:
+	// one of "error", "warning" or "info"
+	Severity map[string]string `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+func (l *LintConfig) validateSetDefaults() error {
+	disabled := make(map[string]bool, len(l.Disable))
+	for _, r := range l.Disable {
+		disabled[r] = true
+	}
+	for _, r := range l.Rules {
+		if disabled[r] {
+			return fmt.Errorf("lint: rule %q is both enabled and disabled", r)
+		}
+	}
+	for r, sev := range l.Severity {
+		switch sev {
+		case LintSeverityError, LintSeverityWarning, LintSeverityInfo:
+		default:
+			return fmt.Errorf("lint: rule %q: unknown severity %q", r, sev)
+		}
+	}
+	return nil
+}
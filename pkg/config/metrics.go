@@ -14,6 +14,101 @@
 
 package config
 
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+)
+
 type PromConfig struct {
 	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	// serve the HTTP endpoints over TLS,
+	// client certificates are required and verified against the CA if set.
+	TLS *TLS `yaml:"tls,omitempty" json:"tls,omitempty"`
+	// required on the HTTP requests but the REST ones when the gRPC
+	// authentication is configured, they carry its bearer token.
+	BasicAuth *BasicAuth `yaml:"basic-auth,omitempty" json:"basic-auth,omitempty"`
+	// IP addresses or prefixes allowed to reach the HTTP endpoints,
+	// all clients are allowed if empty.
+	AllowedClients []string `yaml:"allowed-clients,omitempty" json:"allowed-clients,omitempty"`
+	// expose the Go profiling endpoints under /debug/pprof/
+	Pprof bool `yaml:"pprof,omitempty" json:"pprof,omitempty"`
+	// serve the HTTP endpoints on the gRPC server port, HTTP requests are told apart
+	// from gRPC ones by their content-type. address and tls are then ignored,
+	// the gRPC server TLS config applies to both.
+	GRPCPort bool `yaml:"grpc-port,omitempty" json:"grpc-port,omitempty"`
+	// serve the schema explorer web UI under /ui/, requires rest:
+	// the page calls the REST endpoints.
+	UI bool `yaml:"ui,omitempty" json:"ui,omitempty"`
+	// serve a read-only REST/JSON gateway to the schemas under /api/v1/,
+	// the requests are authorized and journaled as the gRPC ones.
+	REST bool `yaml:"rest,omitempty" json:"rest,omitempty"`
+	// time the REST responses can be served from the browser and front caches,
+	// they are revalidated with their ETag (If-None-Match) each time if not set.
+	RESTMaxAge time.Duration `yaml:"rest-max-age,omitempty" json:"rest-max-age,omitempty"`
+
+	allowedPrefixes []netip.Prefix
+}
+
+type BasicAuth struct {
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	// file the password is read from, takes precedence over password
+	PasswordFile string `yaml:"password-file,omitempty" json:"password-file,omitempty"`
+}
+
+func (c *PromConfig) validateSetDefaults() error {
+	if c.BasicAuth != nil {
+		if c.BasicAuth.PasswordFile != "" {
+			b, err := os.ReadFile(c.BasicAuth.PasswordFile)
+			if err != nil {
+				return fmt.Errorf("failed to read basic-auth password file: %w", err)
+			}
+			c.BasicAuth.Password = strings.TrimSpace(string(b))
+		}
+		if c.BasicAuth.Username == "" || c.BasicAuth.Password == "" {
+			return errors.New("basic-auth username and password should be set")
+		}
+	}
+	if c.RESTMaxAge < 0 {
+		return errors.New("rest-max-age must not be negative")
+	}
+	if c.UI && !c.REST {
+		return errors.New("ui requires rest")
+	}
+	c.allowedPrefixes = make([]netip.Prefix, 0, len(c.AllowedClients))
+	for _, ac := range c.AllowedClients {
+		if strings.Contains(ac, "/") {
+			pf, err := netip.ParsePrefix(ac)
+			if err != nil {
+				return fmt.Errorf("invalid allowed client %q: %w", ac, err)
+			}
+			c.allowedPrefixes = append(c.allowedPrefixes, pf.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(ac)
+		if err != nil {
+			return fmt.Errorf("invalid allowed client %q: %w", ac, err)
+		}
+		c.allowedPrefixes = append(c.allowedPrefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return nil
+}
+
+// IsAllowed reports whether a client with the given address
+// can reach the HTTP endpoints.
+func (c *PromConfig) IsAllowed(addr netip.Addr) bool {
+	if len(c.allowedPrefixes) == 0 {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, pf := range c.allowedPrefixes {
+		if pf.Contains(addr) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,95 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SchemaProfile is a preset of parsing options known to work for a vendor bundle.
+// Profiles carry no deviations: deviation modules are device and release specific,
+// they are set on the schema, see SchemaConfig.Deviations.
+type SchemaProfile struct {
+	// added to the schema excludes
+	Excludes                            []string
+	IgnoreSubmoduleCircularDependencies bool
+	IgnoreDeviateNotSupported           bool
+}
+
+var schemaProfiles = map[string]*SchemaProfile{
+	// SR Linux bundles ship helper modules under a tools directory
+	"srlinux": {
+		Excludes: []string{".*tools.*"},
+	},
+	// SR OS bundles ship the same modules split in submodules
+	// and combined, only the combined ones are read.
+	"sros": {
+		Excludes: []string{".*nokia-submodule.*", ".*nokia-sros-yang-conformance.*"},
+	},
+	// the openconfig public repository carries test
+	// and vendor deviation modules next to the models.
+	"openconfig": {
+		Excludes:                  []string{".*/(testdata|test|vendor)/.*"},
+		IgnoreDeviateNotSupported: true,
+	},
+	// Junos configuration submodules include each other and
+	// the RPC modules are not part of the configuration schema.
+	"junos": {
+		Excludes:                            []string{".*/rpc/.*", ".*-rpc-.*"},
+		IgnoreSubmoduleCircularDependencies: true,
+	},
+}
+
+// ProfileNames returns the names of the built-in schema profiles, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(schemaProfiles))
+	for name := range schemaProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile merges the options of the schema profile into the schema config.
+func (sc *SchemaConfig) applyProfile() error {
+	if sc.Profile == "" {
+		return nil
+	}
+	p, ok := schemaProfiles[sc.Profile]
+	if !ok {
+		return fmt.Errorf("schema %s: unknown profile %q, expecting one of %v", sc.Name, sc.Profile, ProfileNames())
+	}
+	excludes := make([]string, 0, len(p.Excludes)+len(sc.Excludes))
+	excludes = append(excludes, p.Excludes...)
+	for _, e := range sc.Excludes {
+		if !contains(excludes, e) {
+			excludes = append(excludes, e)
+		}
+	}
+	sc.Excludes = excludes
+	sc.IgnoreSubmoduleCircularDependencies = sc.IgnoreSubmoduleCircularDependencies || p.IgnoreSubmoduleCircularDependencies
+	sc.IgnoreDeviateNotSupported = sc.IgnoreDeviateNotSupported || p.IgnoreDeviateNotSupported
+	return nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
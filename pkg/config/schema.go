@@ -16,9 +16,14 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/utils"
 )
 
 const (
@@ -31,6 +36,49 @@ type SchemaStoreConfig struct {
 	Path    string                         `yaml:"path,omitempty" json:"path,omitempty"`
 	Cache   *SchemaPersistStoreCacheConfig `json:"cache,omitempty"`
 	Schemas []*SchemaConfig                `yaml:"schemas,omitempty" json:"schemas,omitempty"`
+	// memory store only: directory a copy of the parsed schemas is kept in,
+	// on restart the schemas with unchanged sources are served from it
+	// while they are parsed again, see package cachestore.
+	CacheDirectory string `yaml:"cache-directory,omitempty" json:"cache-directory,omitempty"`
+	// default limits of the schemas not setting their own,
+	// uploaded schemas included.
+	Limits *SchemaLimits `yaml:"limits,omitempty" json:"limits,omitempty"`
+	// default description language of the schemas not setting their own,
+	// uploaded schemas included.
+	DescriptionLanguage string `yaml:"description-language,omitempty" json:"description-language,omitempty"`
+	// default features policy of the schemas not setting their own,
+	// uploaded schemas included.
+	Features *FeaturesPolicy `yaml:"features,omitempty" json:"features,omitempty"`
+	// default lint rules of the schemas not setting their own,
+	// uploaded schemas included.
+	Lint *LintConfig `yaml:"lint,omitempty" json:"lint,omitempty"`
+	// Go plugin files adding lint rules, loaded at startup,
+	// see lint.PluginSymbol.
+	LintPlugins []string `yaml:"lint-plugins,omitempty" json:"lint-plugins,omitempty"`
+	// Go plugin files adding schema source resolvers, loaded at startup,
+	// see schema.SourcePluginSymbol.
+	SourcePlugins []string `yaml:"source-plugins,omitempty" json:"source-plugins,omitempty"`
+	// directory the git sources of the schemas are checked out under,
+	// defaults to ./git-sources
+	GitDirectory string `yaml:"git-directory,omitempty" json:"git-directory,omitempty"`
+	// max duration of the clone or fetch and checkout of the git sources of a schema,
+	// defaults to 10m
+	GitTimeout time.Duration `yaml:"git-timeout,omitempty" json:"git-timeout,omitempty"`
+	// directory the oci and downloaded sources of the schemas are fetched under,
+	// defaults to ./fetched-sources
+	FetchDirectory string `yaml:"fetch-directory,omitempty" json:"fetch-directory,omitempty"`
+	// experimental: write snapshots of the schema trees for co-located
+	// processes to map, see package shmstore.
+	SharedMemory *SharedMemoryConfig `yaml:"shared-memory,omitempty" json:"shared-memory,omitempty"`
+}
+
+type SharedMemoryConfig struct {
+	// directory the snapshot files are written to, one per schema,
+	// defaults to /dev/shm/schema-server
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty"`
+	// group, by name or id, the snapshot files are readable by,
+	// they are only readable by the schema-server user if not set
+	Group string `yaml:"group,omitempty" json:"group,omitempty"`
 }
 
 type SchemaPersistStoreCacheConfig struct {
@@ -46,12 +94,254 @@ type SchemaConfig struct {
 	Files       []string `yaml:"files,omitempty" json:"files,omitempty"`
 	Directories []string `yaml:"directories,omitempty" json:"directories,omitempty"`
 	Excludes    []string `yaml:"excludes,omitempty" json:"excludes,omitempty"`
+	// files or directories of deviation modules applied to the schema,
+	// e.g. the ones shipped with a network OS release. They are read
+	// after the files and the excludes do not apply to them.
+	Deviations []string `yaml:"deviations,omitempty" json:"deviations,omitempty"`
+	// git repository the files and directories are checked out from,
+	// they are relative to the repository root if set.
+	Git *GitSource `yaml:"git,omitempty" json:"git,omitempty"`
+	// OCI artifact the files and directories are unpacked from,
+	// they are relative to the artifact root if set.
+	OCI *OCISource `yaml:"oci,omitempty" json:"oci,omitempty"`
+	// sources fetched by the resolver of their type, a built-in one (dir, http)
+	// or one registered by an integrator, see schema.SourceResolver.
+	// The files and directories are relative to the root of the sources if set.
+	Source *SourceConfig `yaml:"source,omitempty" json:"source,omitempty"`
+	// schedule the sources are fetched again on, the schema is
+	// reloaded if their content changed.
+	Refresh *RefreshConfig `yaml:"refresh,omitempty" json:"refresh,omitempty"`
+	// top-level nodes the schema is restricted to, e.g. interface or
+	// srl_nokia-network-instance:network-instance, a module name keeping all its
+	// top-level nodes. The other ones are pruned once the modules are parsed,
+	// the leafrefs pointing into them are left unresolved. All are kept if not set.
+	Subtrees []string `yaml:"subtrees,omitempty" json:"subtrees,omitempty"`
+	// guardrails applied to requests resolving a subtree of the schema
+	Limits *SchemaLimits `yaml:"limits,omitempty" json:"limits,omitempty"`
+	// extract the organization, contact and license of the modules
+	// and warn on missing or mixed licenses.
+	ScanMetadata bool `yaml:"scan-metadata,omitempty" json:"scan-metadata,omitempty"`
+	// language of the descriptions returned in responses: nodes carrying a
+	// description-<language> extension get it as description, other
+	// language variants are dropped.
+	DescriptionLanguage string `yaml:"description-language,omitempty" json:"description-language,omitempty"`
+	// YANG features considered enabled, the nodes
+	// depending on a disabled feature are removed.
+	Features *FeaturesPolicy `yaml:"features,omitempty" json:"features,omitempty"`
+	// model quality rules run once the schema is loaded,
+	// the schema is not linted if not set.
+	Lint *LintConfig `yaml:"lint,omitempty" json:"lint,omitempty"`
+	// built-in preset of excludes and parser options for a vendor bundle,
+	// see ProfileNames. Its options add to the ones set on the schema,
+	// it sets no deviations.
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	// variants of the schema compiled for classes of targets, e.g. hardware platforms,
+	// each one is served as a schema versioned <version>+<target profile name>.
+	TargetProfiles []*TargetProfile `yaml:"target-profiles,omitempty" json:"target-profiles,omitempty"`
+	// parser options
+	IgnoreSubmoduleCircularDependencies bool `yaml:"ignore-submodule-circular-dependencies,omitempty" json:"ignore-submodule-circular-dependencies,omitempty"`
+	// keep the nodes marked as "deviate not-supported"
+	IgnoreDeviateNotSupported bool `yaml:"ignore-deviate-not-supported,omitempty" json:"ignore-deviate-not-supported,omitempty"`
+}
+
+// SchemaLimits bounds the size of the schema subtree a single request can resolve,
+// a zero value means no limit.
+type SchemaLimits struct {
+	// max number of levels below the requested path
+	MaxDepth int `yaml:"max-depth,omitempty" json:"max-depth,omitempty"`
+	// max number of schema nodes in the subtree, including the requested node
+	MaxNodes int `yaml:"max-nodes,omitempty" json:"max-nodes,omitempty"`
+}
+
+func (l *SchemaLimits) validateSetDefaults() error {
+	if l.MaxDepth < 0 || l.MaxNodes < 0 {
+		return errors.New("schema limits cannot be negative")
+	}
+	return nil
+}
+
+const (
+	FeaturesEnableAll  = "enable-all"
+	FeaturesDisableAll = "disable-all"
+	FeaturesExplicit   = "explicit"
+)
+
+// FeaturesPolicy selects the features satisfying the if-feature statements of a schema.
+type FeaturesPolicy struct {
+	// one of "enable-all" (the default), "disable-all" or "explicit"
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// features enabled in explicit mode, as "module:feature"
+	// or "feature" to enable it in any module.
+	Enabled []string `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+}
+
+func (f *FeaturesPolicy) validateSetDefaults() error {
+	switch f.Mode {
+	case "":
+		f.Mode = FeaturesEnableAll
+	case FeaturesEnableAll, FeaturesDisableAll, FeaturesExplicit:
+	default:
+		return fmt.Errorf("unknown features mode %q", f.Mode)
+	}
+	if len(f.Enabled) > 0 && f.Mode != FeaturesExplicit {
+		return fmt.Errorf("enabled features are only used with the %q features mode", FeaturesExplicit)
+	}
+	return nil
+}
+
+// GitSource is a git repository checkout the YANG files of a schema are read from,
+// it is fetched each time the schema is parsed.
+type GitSource struct {
+	// repository URL or path, as accepted by git clone
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+	// branch, tag or commit to check out, the remote HEAD if not set
+	Ref string `yaml:"ref,omitempty" json:"ref,omitempty"`
+	// checkout directory, defaults to a directory
+	// named after the schema under the store git-directory
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty"`
+	// max duration of the clone or fetch and checkout, defaults to the store git-timeout
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// OCISource is an OCI artifact the YANG files of a schema are read from,
+// it is pulled each time the schema is parsed.
+// The layers are unpacked if they are tar archives, copied under
+// their org.opencontainers.image.title annotation otherwise.
+type OCISource struct {
+	// artifact reference, e.g. ghcr.io/org/yangs:24.3.1 or ghcr.io/org/yangs@sha256:...
+	Reference string `yaml:"reference,omitempty" json:"reference,omitempty"`
+	// registry credentials, anonymous if not set
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	// file the password or token is read from when pulling
+	PasswordFile string `yaml:"password-file,omitempty" json:"password-file,omitempty"`
+	// use HTTP instead of HTTPS to reach the registry
+	PlainHTTP bool `yaml:"plain-http,omitempty" json:"plain-http,omitempty"`
+	// unpack directory, defaults to a directory
+	// named after the schema under the store fetch-directory
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty"`
+}
+
+// SourceConfig selects the source resolver fetching the YANG files of a schema.
+type SourceConfig struct {
+	// resolver type, e.g. dir or http
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// settings of the resolver, e.g. the path of a dir source
+	// or the url and token-file of an http one
+	Options map[string]string `yaml:"options,omitempty" json:"options,omitempty"`
+	// directory the resolvers downloading the sources, e.g. http, fetch them into,
+	// defaults to a directory named after the schema under the store fetch-directory
+	Directory string `yaml:"directory,omitempty" json:"directory,omitempty"`
+}
+
+// RefreshConfig schedules the refresh of the sources of a schema loaded from the config file.
+type RefreshConfig struct {
+	// cron expression in the server time zone, e.g. "0 2 * * *" or @daily
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	// max random delay added to each scheduled time, spreading
+	// the fetches of the schemas and servers sharing a schedule
+	Jitter time.Duration `yaml:"jitter,omitempty" json:"jitter,omitempty"`
+}
+
+func (r *RefreshConfig) validateSetDefaults() error {
+	sched, err := utils.ParseCron(r.Schedule)
+	if err != nil {
+		return err
+	}
+	if sched.Next(time.Now()).IsZero() {
+		return fmt.Errorf("refresh schedule %q never matches", r.Schedule)
+	}
+	if r.Jitter < 0 {
+		return errors.New("refresh jitter cannot be negative")
+	}
+	return nil
+}
+
+func (sc *SchemaConfig) validateGit() error {
+	if sc.Git.URL == "" {
+		return errors.New("git sources require a repository url")
+	}
+	return sc.validateRemotePaths("git", "repository")
+}
+
+func (sc *SchemaConfig) validateOCI() error {
+	if sc.OCI.Reference == "" {
+		return errors.New("oci sources require an artifact reference")
+	}
+	if sc.Git != nil {
+		return errors.New("a schema cannot have both git and oci sources")
+	}
+	if sc.OCI.PasswordFile != "" && sc.OCI.Username == "" {
+		return errors.New("oci sources: a password file requires a username")
+	}
+	return sc.validateRemotePaths("oci", "artifact")
+}
+
+func (sc *SchemaConfig) validateSource() error {
+	if sc.Source.Type == "" {
+		return errors.New("sources require a type")
+	}
+	if sc.Git != nil || sc.OCI != nil {
+		return errors.New("a schema cannot have both git or oci sources and a source")
+	}
+	return sc.validateRemotePaths(sc.Source.Type, "source")
+}
+
+func (sc *SchemaConfig) validateRemotePaths(kind, root string) error {
+	for _, p := range append(append(append([]string{}, sc.Files...), sc.Directories...), sc.Deviations...) {
+		if !filepath.IsLocal(p) {
+			return fmt.Errorf("%s sources: path %q is not relative to the %s root", kind, p, root)
+		}
+	}
+	return nil
 }
 
 func (sc *SchemaConfig) validateSetDefaults() error {
 	if sc.Vendor == "" || sc.Version == "" {
 		return errors.New("schema name, vendor and version should be set")
 	}
+	if sc.Git != nil {
+		if err := sc.validateGit(); err != nil {
+			return err
+		}
+	}
+	if sc.OCI != nil {
+		if err := sc.validateOCI(); err != nil {
+			return err
+		}
+	}
+	if sc.Source != nil {
+		if err := sc.validateSource(); err != nil {
+			return err
+		}
+	}
+	if sc.Refresh != nil {
+		if err := sc.Refresh.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if err := sc.applyProfile(); err != nil {
+		return err
+	}
+	if sc.Features != nil {
+		if err := sc.Features.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	for i, st := range sc.Subtrees {
+		st = strings.TrimPrefix(st, "/")
+		if st == "" || strings.Contains(st, "/") {
+			return fmt.Errorf("subtree %q is not a top-level node or a module", sc.Subtrees[i])
+		}
+		sc.Subtrees[i] = st
+	}
+	if sc.Lint != nil {
+		if err := sc.Lint.validateSetDefaults(); err != nil {
+			return err
+		}
+	}
+	if sc.Limits != nil {
+		return sc.Limits.validateSetDefaults()
+	}
 	return nil
 }
 
@@ -0,0 +1,110 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const defaultVirtualNodes = 128
+
+// ShardingConfig spreads the schemas over several instances sharing the same config:
+// each schema is owned by the member its name and vendor hash to on a consistent-hash ring.
+// An instance only loads the schemas it owns and redirects the requests for the
+// other ones to their owner. The versions of a schema are owned by the same member,
+// for version fallback, canaries and target profiles to be resolved by one instance.
+type ShardingConfig struct {
+	// name of this instance, an instance that is not a member owns no schema
+	// and redirects all the requests referring to a schema.
+	// defaults to the host name, e.g. the pod name of a StatefulSet.
+	Self    string         `yaml:"self,omitempty" json:"self,omitempty"`
+	Members []*ShardMember `yaml:"members,omitempty" json:"members,omitempty"`
+	// points per member on the ring, more spread the schemas more evenly
+	VirtualNodes int `yaml:"virtual-nodes,omitempty" json:"virtual-nodes,omitempty"`
+
+	ring []ringPoint
+}
+
+type ShardMember struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// gRPC address the clients are redirected to
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+}
+
+type ringPoint struct {
+	hash   uint64
+	member *ShardMember
+}
+
+func (c *ShardingConfig) validateSetDefaults() error {
+	if len(c.Members) == 0 {
+		return errors.New("sharding: no members")
+	}
+	if c.Self == "" {
+		var err error
+		c.Self, err = os.Hostname()
+		if err != nil {
+			return fmt.Errorf("sharding: self not set: %v", err)
+		}
+	}
+	if c.VirtualNodes <= 0 {
+		c.VirtualNodes = defaultVirtualNodes
+	}
+	names := make(map[string]struct{}, len(c.Members))
+	c.ring = make([]ringPoint, 0, len(c.Members)*c.VirtualNodes)
+	for _, m := range c.Members {
+		switch {
+		case m.Name == "":
+			return errors.New("sharding: member without a name")
+		case m.Address == "":
+			return fmt.Errorf("sharding: member %q without an address", m.Name)
+		}
+		if _, ok := names[m.Name]; ok {
+			return fmt.Errorf("sharding: duplicate member %q", m.Name)
+		}
+		names[m.Name] = struct{}{}
+		for i := 0; i < c.VirtualNodes; i++ {
+			c.ring = append(c.ring, ringPoint{hash: ringHash(m.Name + "#" + strconv.Itoa(i)), member: m})
+		}
+	}
+	sort.Slice(c.ring, func(i, j int) bool { return c.ring[i].hash < c.ring[j].hash })
+	return nil
+}
+
+// Owner returns the member owning the schemas named name from vendor.
+func (c *ShardingConfig) Owner(name, vendor string) *ShardMember {
+	h := ringHash(name + "@" + vendor)
+	i := sort.Search(len(c.ring), func(i int) bool { return c.ring[i].hash >= h })
+	if i == len(c.ring) {
+		i = 0
+	}
+	return c.ring[i].member
+}
+
+// Owned reports whether this instance owns the schemas named name from vendor.
+func (c *ShardingConfig) Owned(name, vendor string) bool {
+	return c.Owner(name, vendor).Name == c.Self
+}
+
+func ringHash(s string) uint64 {
+	h := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint64(h[:8])
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TargetProfileSeparator separates the version of a schema
+// from the name of its target profile in the derived schema version.
+const TargetProfileSeparator = "+"
+
+// TargetProfile is a variant of a schema compiled for a class of targets,
+// e.g. a hardware platform, with its own features and deviations.
+type TargetProfile struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// features of the profile, the ones of the schema if not set
+	Features *FeaturesPolicy `yaml:"features,omitempty" json:"features,omitempty"`
+	// deviation modules applied after the ones of the schema
+	Deviations []string `yaml:"deviations,omitempty" json:"deviations,omitempty"`
+}
+
+// TargetProfileVersion returns the version of the schema derived
+// from the one with the given version for a target profile.
+func TargetProfileVersion(version, profile string) string {
+	return version + TargetProfileSeparator + profile
+}
+
+// expandTargetProfiles adds to the schemas the ones derived from their target profiles.
+func (c *SchemaStoreConfig) expandTargetProfiles() error {
+	var derived []*SchemaConfig
+	for _, sc := range c.Schemas {
+		names := make(map[string]struct{}, len(sc.TargetProfiles))
+		for _, tp := range sc.TargetProfiles {
+			if tp.Name == "" {
+				return fmt.Errorf("schema %s@%s@%s: target profile name cannot be empty", sc.Name, sc.Vendor, sc.Version)
+			}
+			if strings.ContainsAny(tp.Name, "+@/") {
+				return fmt.Errorf("schema %s@%s@%s: target profile name %q cannot contain +, @ or /", sc.Name, sc.Vendor, sc.Version, tp.Name)
+			}
+			if _, ok := names[tp.Name]; ok {
+				return fmt.Errorf("schema %s@%s@%s: duplicate target profile %q", sc.Name, sc.Vendor, sc.Version, tp.Name)
+			}
+			names[tp.Name] = struct{}{}
+			derived = append(derived, sc.forTargetProfile(tp))
+		}
+	}
+	c.Schemas = append(c.Schemas, derived...)
+	return nil
+}
+
+// forTargetProfile returns the config of the schema derived from sc for target profile tp.
+func (sc *SchemaConfig) forTargetProfile(tp *TargetProfile) *SchemaConfig {
+	d := *sc
+	d.Version = TargetProfileVersion(sc.Version, tp.Name)
+	d.TargetProfiles = nil
+	d.Files = append([]string{}, sc.Files...)
+	d.Directories = append([]string{}, sc.Directories...)
+	d.Excludes = append([]string{}, sc.Excludes...)
+	d.Deviations = append(append([]string{}, sc.Deviations...), tp.Deviations...)
+	if tp.Features != nil {
+		d.Features = tp.Features
+	}
+	if sc.Git != nil {
+		git := *sc.Git
+		// each schema is checked out in its own directory
+		if git.Directory != "" {
+			git.Directory += "_" + tp.Name
+		}
+		d.Git = &git
+	}
+	return &d
+}
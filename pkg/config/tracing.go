@@ -0,0 +1,67 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultTracingServiceName = "schema-server"
+	defaultTracingTimeout     = 10 * time.Second
+)
+
+// TracingConfig exports OpenTelemetry spans of the RPCs and of the
+// schema parsing, walking and serialization to an OTLP gRPC collector.
+// The W3C traceparent of the requests is propagated to their spans.
+type TracingConfig struct {
+	// host:port of the collector
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	// connect to the collector without TLS
+	Insecure bool `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+	TLS      *TLS `yaml:"tls,omitempty" json:"tls,omitempty"`
+	// metadata sent with the exports, e.g. an authorization header
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// service.name of the exported resource, defaults to schema-server
+	ServiceName string `yaml:"service-name,omitempty" json:"service-name,omitempty"`
+	// ratio of the traces started by the server that are sampled, defaults to 1.
+	// The requests carrying a traceparent follow the sampling decision of their parent.
+	SampleRatio float64 `yaml:"sample-ratio,omitempty" json:"sample-ratio,omitempty"`
+	// timeout of an export, defaults to 10s
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+func (c *TracingConfig) validateSetDefaults() error {
+	if c.Endpoint == "" {
+		return errors.New("tracing: missing endpoint")
+	}
+	if c.Insecure && c.TLS != nil {
+		return errors.New("tracing: insecure and tls are mutually exclusive")
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.New("tracing: sample-ratio must be between 0 and 1")
+	}
+	if c.SampleRatio == 0 {
+		c.SampleRatio = 1
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = defaultTracingServiceName
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTracingTimeout
+	}
+	return nil
+}
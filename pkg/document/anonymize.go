@@ -0,0 +1,286 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+const (
+	// replace the value with maskedValue
+	AnonymizeMask = "mask"
+	// replace the value with a pseudonym derived from the value and the anonymizer seed
+	AnonymizeRandomize = "randomize"
+	// leave the value as is, overrides the following rules
+	AnonymizeKeep = "keep"
+)
+
+const maskedValue = "********"
+
+// AnonymizeRule selects the leaves an action applies to.
+// A rule without Path nor Type matches all leaves.
+type AnonymizeRule struct {
+	// matched against the leaf schema path, without keys nor module prefixes, e.g: /interface/description
+	Path *regexp.Regexp
+	// matched against the leaf type and typedef names, union member types included
+	Type   *regexp.Regexp
+	Action string
+}
+
+// DefaultAnonymizeRules masks the password and secret leaves and randomizes the
+// IP and MAC addresses, the IP prefixes, the host names and the serial numbers.
+func DefaultAnonymizeRules() []*AnonymizeRule {
+	return []*AnonymizeRule{
+		{Type: regexp.MustCompile(`(?i)password|secret|crypt-hash`), Action: AnonymizeMask},
+		{Path: regexp.MustCompile(`(?i)/[^/]*(password|secret|passphrase|pre-shared-key|community)[^/]*$`), Action: AnonymizeMask},
+		{Type: regexp.MustCompile(`^(ip|ipv4|ipv6)-(address|prefix)|mac-address$`), Action: AnonymizeRandomize},
+		{Path: regexp.MustCompile(`(?i)/([^/]*serial[^/]*|host-name|domain-name)$`), Action: AnonymizeRandomize},
+	}
+}
+
+// Anonymizer rewrites the leaf values of documents according to rules,
+// the first matching rule applies.
+// Pseudonyms are consistent: a value gets the same pseudonym wherever it appears,
+// in all the documents anonymized with the same seed, and IP addresses sharing
+// a prefix get pseudonyms sharing a prefix of the same length.
+// Masked and randomized values are not validated against the leaf type.
+type Anonymizer struct {
+	rules []*AnonymizeRule
+	key   []byte
+	// schema paths of the rewritten leaves
+	paths map[string]struct{}
+}
+
+// NewAnonymizer returns an Anonymizer applying rules,
+// a random seed is used if seed is empty.
+func NewAnonymizer(rules []*AnonymizeRule, seed string) *Anonymizer {
+	key := []byte(seed)
+	if seed == "" {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &Anonymizer{
+		rules: rules,
+		key:   key,
+		paths: make(map[string]struct{}),
+	}
+}
+
+// Paths returns the schema paths of the leaves rewritten so far, sorted.
+func (a *Anonymizer) Paths() []string {
+	ps := make([]string, 0, len(a.paths))
+	for p := range a.paths {
+		ps = append(ps, p)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+// Anonymize rewrites the leaf values of the document using a,
+// list entries are identified by their rewritten keys.
+func (b *Builder) Anonymize(a *Anonymizer) {
+	a.object(b.root, "")
+}
+
+func (a *Anonymizer) object(n *node, p string) {
+	for _, c := range n.children {
+		a.member(c, p+"/"+localName(c.name))
+	}
+}
+
+func (a *Anonymizer) member(n *node, p string) {
+	switch n.kind {
+	case objectNode:
+		a.object(n, p)
+	case listNode:
+		for _, e := range n.entries {
+			a.object(e, p)
+			e.rekey()
+		}
+		n.indexEntries()
+	case leafNode:
+		if action := a.action(n, p); action != AnonymizeKeep {
+			n.value = a.rewrite(action, n.value)
+			a.paths[p] = struct{}{}
+		}
+	case leafListNode:
+		if action := a.action(n, p); action != AnonymizeKeep {
+			for i, v := range n.values {
+				n.values[i] = a.rewrite(action, v)
+			}
+			a.paths[p] = struct{}{}
+		}
+	}
+}
+
+// rekey updates the keys of list entry e from the values of its key leaves.
+func (e *node) rekey() {
+	kvs := make([]string, 0, len(e.keyNames))
+	for _, k := range e.keyNames {
+		kn := e.get(k)
+		if kn == nil {
+			return
+		}
+		e.keyValues[k] = rawValue(kn.value)
+		kvs = append(kvs, e.keyValues[k])
+	}
+	e.keys = strings.Join(kvs, "\x00")
+}
+
+// action returns the action of the first rule matching leaf n at path p.
+func (a *Anonymizer) action(n *node, p string) string {
+	for _, r := range a.rules {
+		if r.Path != nil && !r.Path.MatchString(p) {
+			continue
+		}
+		if r.Type != nil && !matchType(r.Type, n.typ) {
+			continue
+		}
+		return r.Action
+	}
+	return AnonymizeKeep
+}
+
+func matchType(re *regexp.Regexp, t *sdcpb.SchemaLeafType) bool {
+	if t == nil {
+		return false
+	}
+	if re.MatchString(t.GetType()) || (t.GetTypeName() != "" && re.MatchString(t.GetTypeName())) {
+		return true
+	}
+	for _, ut := range t.GetUnionTypes() {
+		if matchType(re, ut) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Anonymizer) rewrite(action string, v interface{}) interface{} {
+	switch v.(type) {
+	case bool, []interface{}:
+		// booleans and empty leaves carry no information worth hiding
+		return v
+	}
+	switch action {
+	case AnonymizeMask:
+		return maskedValue
+	case AnonymizeRandomize:
+		if n, ok := v.(json.Number); ok {
+			return json.Number(a.pseudonym(n.String()))
+		}
+		return a.pseudonym(rawValue(v))
+	}
+	return v
+}
+
+// pseudonym returns the pseudonym of the raw value v:
+// an address of the same family for an IP address or prefix, a locally
+// administered address for a MAC address, a string of the same length
+// and character classes otherwise.
+func (a *Anonymizer) pseudonym(v string) string {
+	if addr, err := netip.ParseAddr(v); err == nil && addr.Zone() == "" {
+		return a.addr(addr).String()
+	}
+	if pfx, err := netip.ParsePrefix(v); err == nil {
+		np := netip.PrefixFrom(a.addr(pfx.Addr()), pfx.Bits())
+		if pfx.Masked() == pfx {
+			np = np.Masked()
+		}
+		return np.String()
+	}
+	if mac, err := net.ParseMAC(v); err == nil && len(mac) == 6 {
+		h := a.hash([]byte(v))
+		h[0] = h[0]&0xfe | 0x02
+		nm := net.HardwareAddr(h[:6]).String()
+		if strings.ToUpper(v) == v {
+			nm = strings.ToUpper(nm)
+		}
+		return nm
+	}
+	h := a.stream([]byte(v), len(v))
+	sb := new(strings.Builder)
+	for i, r := range v {
+		c := h[i%len(h)]
+		switch {
+		case unicode.IsDigit(r):
+			// keep numbers free of leading zeros
+			if i == 0 && r != '0' {
+				sb.WriteByte('1' + c%9)
+				continue
+			}
+			sb.WriteByte('0' + c%10)
+		case unicode.IsUpper(r):
+			sb.WriteByte('A' + c%26)
+		case unicode.IsLower(r):
+			sb.WriteByte('a' + c%26)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// addr returns the prefix preserving pseudonym of addr: each bit is flipped
+// depending on the bits preceding it, addresses sharing their first n bits
+// get pseudonyms sharing their first n bits.
+func (a *Anonymizer) addr(addr netip.Addr) netip.Addr {
+	in := addr.AsSlice()
+	out := make([]byte, len(in))
+	prefix := make([]byte, len(in)+1)
+	for i := 0; i < len(in)*8; i++ {
+		byteIdx, bit := i/8, byte(0x80>>(i%8))
+		// the first i bits of the address, followed by the bit index
+		prefix[len(in)] = byte(i)
+		flip := a.hash(prefix)[0] & 0x80
+		if flip != 0 {
+			out[byteIdx] |= (in[byteIdx] ^ bit) & bit
+		} else {
+			out[byteIdx] |= in[byteIdx] & bit
+		}
+		prefix[byteIdx] |= in[byteIdx] & bit
+	}
+	na, _ := netip.AddrFromSlice(out)
+	return na
+}
+
+func (a *Anonymizer) hash(b []byte) []byte {
+	m := hmac.New(sha256.New, a.key)
+	m.Write(b)
+	return m.Sum(nil)
+}
+
+// stream returns at least n pseudo random bytes derived from b.
+func (a *Anonymizer) stream(b []byte, n int) []byte {
+	out := make([]byte, 0, n+sha256.Size)
+	ctr := make([]byte, 4)
+	for i := uint32(0); len(out) < n; i++ {
+		binary.BigEndian.PutUint32(ctr, i)
+		out = append(out, a.hash(append(ctr, b...))...)
+	}
+	return out
+}
@@ -0,0 +1,434 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package document builds and encodes YANG instance documents and paths
+// using the schemas of a store.
+package document
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+type nodeKind int
+
+const (
+	objectNode nodeKind = iota
+	listNode
+	leafNode
+	leafListNode
+)
+
+type node struct {
+	kind nodeKind
+	// JSON member name, module qualified if the node
+	// is not defined in the same module as its parent.
+	name   string
+	module string
+	// data path without keys, e.g. /interface/subinterface,
+	// list entries have the path of their list.
+	path string
+	// ordered-by user list or leaf-list
+	userOrdered bool
+	// list key leaf
+	isKey bool
+	// type of a leaf or leaf-list
+	typ *sdcpb.SchemaLeafType
+	// members of an object node
+	children []*node
+	// children by name
+	byName map[string]*node
+	// entries of a list node
+	entries []*node
+	// entries by keys
+	byKeys map[string]*node
+	// key values identifying a list entry
+	keys      string
+	keyValues map[string]string
+	// key names in their declared order
+	keyNames []string
+	// encoded value(s) of a leaf or leaf-list
+	value  interface{}
+	values []interface{}
+}
+
+func (n *node) child(name, module string, kind nodeKind) *node {
+	if c := n.get(name); c != nil {
+		return c
+	}
+	c := &node{kind: kind, name: name, module: module, path: n.path + "/" + localName(name)}
+	n.children = append(n.children, c)
+	if n.byName == nil {
+		n.byName = make(map[string]*node)
+	}
+	n.byName[name] = c
+	return c
+}
+
+func (n *node) get(name string) *node {
+	return n.byName[name]
+}
+
+// addValue appends ev to the values of a leaf-list,
+// an existing value is not added twice.
+func (n *node) addValue(ev interface{}) {
+	rv := rawValue(ev)
+	for _, v := range n.values {
+		if rawValue(v) == rv {
+			return
+		}
+	}
+	n.values = append(n.values, ev)
+}
+
+func (n *node) entry(keys string) *node {
+	return n.byKeys[keys]
+}
+
+// indexEntries indexes the entries of list n by their keys.
+func (n *node) indexEntries() {
+	n.byKeys = make(map[string]*node, len(n.entries))
+	for _, e := range n.entries {
+		n.byKeys[e.keys] = e
+	}
+}
+
+// Builder assembles an RFC 7951 JSON instance document from paths and values.
+type Builder struct {
+	resolver *store.Resolver
+	root     *node
+}
+
+func NewBuilder(r *store.Resolver) *Builder {
+	return &Builder{
+		resolver: r,
+		root:     &node{kind: objectNode},
+	}
+}
+
+// resolve returns the schema element found at names along with the JSON member name
+// and the module of the node to be created under parent.
+func (b *Builder) resolve(ctx context.Context, parent *node, names []string) (*sdcpb.SchemaElem, string, string, error) {
+	se, err := b.resolver.Get(ctx, names)
+	if err != nil {
+		return nil, "", "", err
+	}
+	module, err := b.resolver.Module(ctx, se)
+	if err != nil {
+		return nil, "", "", err
+	}
+	name := names[len(names)-1]
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if module != "" && module != parent.module {
+		name = module + ":" + name
+	}
+	return se, name, module, nil
+}
+
+// Add sets the raw value(s) found at path p in the document,
+// list entries are created from the path keys when needed.
+// A leaf takes a single value, a leaf-list any number of values,
+// a container or a list entry none.
+func (b *Builder) Add(ctx context.Context, p *sdcpb.Path, values ...string) error {
+	if len(p.GetElem()) == 0 {
+		return status.Error(codes.InvalidArgument, "missing path")
+	}
+	cur := b.root
+	names := make([]string, 0, len(p.GetElem()))
+	for i, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+		se, name, module, err := b.resolve(ctx, cur, names)
+		if err != nil {
+			return err
+		}
+		last := i == len(p.GetElem())-1
+		switch se := se.GetSchema().(type) {
+		case *sdcpb.SchemaElem_Container:
+			if last && len(values) > 0 {
+				return status.Errorf(codes.InvalidArgument, "path %s points to a container, it does not take values",
+					utils.ToXPath(p, false))
+			}
+			if len(se.Container.GetKeys()) == 0 {
+				cur = cur.child(name, module, objectNode)
+				continue
+			}
+			cur, err = b.entry(ctx, b.list(cur, name, module, se.Container), se.Container, pe.GetName(), pe.GetKey())
+			if err != nil {
+				return err
+			}
+		case *sdcpb.SchemaElem_Field:
+			if !last {
+				return status.Errorf(codes.InvalidArgument, "path %s: %q is a leaf",
+					utils.ToXPath(p, false), pe.GetName())
+			}
+			var v string
+			switch {
+			case len(values) == 1:
+				v = values[0]
+			case len(values) > 1 || se.Field.GetType().GetType() != "empty":
+				return status.Errorf(codes.InvalidArgument, "path %s points to a leaf, it takes exactly one value",
+					utils.ToXPath(p, false))
+			}
+			ev, err := b.encode(ctx, se.Field.GetType(), module, v)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "path %s: %v", utils.ToXPath(p, false), err)
+			}
+			b.leaf(cur, name, module, se.Field.GetType()).value = ev
+		case *sdcpb.SchemaElem_Leaflist:
+			if !last {
+				return status.Errorf(codes.InvalidArgument, "path %s: %q is a leaf-list",
+					utils.ToXPath(p, false), pe.GetName())
+			}
+			n := b.leafList(cur, name, module, se.Leaflist)
+			for _, v := range values {
+				ev, err := b.encode(ctx, se.Leaflist.GetType(), module, v)
+				if err != nil {
+					return status.Errorf(codes.InvalidArgument, "path %s: %v", utils.ToXPath(p, false), err)
+				}
+				n.addValue(ev)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) list(parent *node, name, module string, cs *sdcpb.ContainerSchema) *node {
+	n := parent.child(name, module, listNode)
+	n.userOrdered = cs.GetIsUserOrdered()
+	return n
+}
+
+func (b *Builder) leafList(parent *node, name, module string, lls *sdcpb.LeafListSchema) *node {
+	n := parent.child(name, module, leafListNode)
+	n.userOrdered = lls.GetIsUserOrdered()
+	n.typ = lls.GetType()
+	return n
+}
+
+func (b *Builder) leaf(parent *node, name, module string, t *sdcpb.SchemaLeafType) *node {
+	n := parent.child(name, module, leafNode)
+	n.typ = t
+	return n
+}
+
+func isIdentityref(t *sdcpb.SchemaLeafType) bool {
+	if t.GetType() == "identityref" {
+		return true
+	}
+	for _, ut := range t.GetUnionTypes() {
+		if isIdentityref(ut) {
+			return true
+		}
+	}
+	return false
+}
+
+// entry returns the entry of list n identified by the key values kv,
+// the entry is created if it does not exist.
+func (b *Builder) entry(ctx context.Context, n *node, cs *sdcpb.ContainerSchema, list string, kv map[string]string) (*node, error) {
+	order, err := b.resolver.Order(ctx, n.path)
+	if err != nil {
+		return nil, err
+	}
+	ks := store.SortKeys(cs.GetKeys(), order)
+	kvs := make([]string, 0, len(ks))
+	evs := make([]interface{}, 0, len(ks))
+	for _, k := range ks {
+		v, ok := kv[k.GetName()]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "list %q: missing key %q", list, k.GetName())
+		}
+		ev, err := b.encode(ctx, k.GetType(), n.module, v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "list %q key %q: %v", list, k.GetName(), err)
+		}
+		kvs = append(kvs, rawValue(ev))
+		evs = append(evs, ev)
+	}
+	keys := strings.Join(kvs, "\x00")
+	if e := n.entry(keys); e != nil {
+		return e, nil
+	}
+	e := &node{
+		kind:      objectNode,
+		module:    n.module,
+		path:      n.path,
+		keys:      keys,
+		keyValues: make(map[string]string, len(kvs)),
+	}
+	for i, k := range ks {
+		kn := b.leaf(e, k.GetName(), n.module, k.GetType())
+		kn.isKey = true
+		kn.value = evs[i]
+		e.keyValues[k.GetName()] = kvs[i]
+		e.keyNames = append(e.keyNames, k.GetName())
+	}
+	n.entries = append(n.entries, e)
+	if n.byKeys == nil {
+		n.byKeys = make(map[string]*node)
+	}
+	n.byKeys[keys] = e
+	return e, nil
+}
+
+func (b *Builder) encode(ctx context.Context, t *sdcpb.SchemaLeafType, module, v string) (interface{}, error) {
+	return encodeValue(t, v, func(lt *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+		return b.leafrefType(ctx, lt.GetLeafref())
+	}, func(_ *sdcpb.SchemaLeafType, v string) (string, error) {
+		return b.identity(ctx, module, v)
+	})
+}
+
+// identity returns the module qualified name of the identityref value v of a node of module.
+// v is qualified by the module or the prefix of the identity, an unqualified v names
+// an identity of module or, failing that, the only identity with that name.
+func (b *Builder) identity(ctx context.Context, module, v string) (string, error) {
+	ids, err := b.resolver.Identities(ctx)
+	if err != nil {
+		return "", err
+	}
+	qual, name := "", v
+	if idx := strings.Index(v, ":"); idx >= 0 {
+		qual, name = v[:idx], v[idx+1:]
+	}
+	var found []*schema.Identity
+	for _, id := range ids {
+		if id.Name != name {
+			continue
+		}
+		switch {
+		case id.Module == qual, qual == "" && id.Module == module:
+			return id.QualifiedName(), nil
+		case qual == "" || id.Prefix == qual:
+			found = append(found, id)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("unknown identity %q", v)
+	case 1:
+		return found[0].QualifiedName(), nil
+	}
+	names := make([]string, 0, len(found))
+	for _, id := range found {
+		names = append(names, id.QualifiedName())
+	}
+	return "", fmt.Errorf("ambiguous identity %q, one of %s", v, strings.Join(names, ", "))
+}
+
+// leafrefType returns the type of the leaf referenced by an absolute leafref path,
+// nil if it cannot be resolved.
+func (b *Builder) leafrefType(ctx context.Context, ref string) *sdcpb.SchemaLeafType {
+	if !strings.HasPrefix(ref, "/") {
+		return nil
+	}
+	p, err := utils.ParsePath(ref)
+	if err != nil {
+		return nil
+	}
+	for _, pe := range p.GetElem() {
+		if idx := strings.Index(pe.GetName(), ":"); idx >= 0 {
+			pe.Name = pe.GetName()[idx+1:]
+		}
+	}
+	se, err := b.resolver.GetPath(ctx, p)
+	if err != nil {
+		return nil
+	}
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Field:
+		return se.Field.GetType()
+	case *sdcpb.SchemaElem_Leaflist:
+		return se.Leaflist.GetType()
+	}
+	return nil
+}
+
+// JSON returns the RFC 7951 encoding of the document.
+func (b *Builder) JSON() ([]byte, error) {
+	return b.root.json()
+}
+
+func (n *node) json() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := n.encode(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *node) encode(buf *bytes.Buffer) error {
+	switch n.kind {
+	case objectNode:
+		buf.WriteByte('{')
+		for i, c := range n.children {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := writeJSON(buf, c.name)
+			if err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			err = c.encode(buf)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case listNode:
+		buf.WriteByte('[')
+		for i, e := range n.entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			err := e.encode(buf)
+			if err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case leafNode:
+		return writeJSON(buf, n.value)
+	case leafListNode:
+		if n.values == nil {
+			return writeJSON(buf, []interface{}{})
+		}
+		return writeJSON(buf, n.values)
+	}
+	return nil
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
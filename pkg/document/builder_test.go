@@ -0,0 +1,112 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const identityModule = `module t {
+  namespace "urn:t";
+  prefix tp;
+  identity if-type;
+  identity eth { base if-type; }
+  identity fast-eth { base eth; }
+  identity other;
+  leaf type {
+    type identityref { base if-type; }
+  }
+}
+`
+
+func TestBuilder_identityref(t *testing.T) {
+	r := testResolver(t, identityModule)
+	tests := []struct {
+		value string
+		json  string
+		code  codes.Code
+	}{
+		{value: "eth", json: `{"t:type":"t:eth"}`},
+		{value: "t:eth", json: `{"t:type":"t:eth"}`},
+		{value: "tp:eth", json: `{"t:type":"t:eth"}`},
+		{value: "fast-eth", json: `{"t:type":"t:fast-eth"}`},
+		{value: "x:eth", code: codes.InvalidArgument},
+		{value: "if-type", code: codes.InvalidArgument},
+		{value: "other", code: codes.InvalidArgument},
+		{value: "t:other", code: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			p, err := utils.ParsePath("/type")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := NewBuilder(r)
+			err = b.Add(context.Background(), p, tt.value)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if err != nil {
+				return
+			}
+			js, err := b.JSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(js) != tt.json {
+				t.Errorf("got %s, want %s", js, tt.json)
+			}
+		})
+	}
+}
+
+func TestBuilder_entries(t *testing.T) {
+	r := testResolver(t, testModule)
+	ctx := context.Background()
+	b := NewBuilder(r)
+	for _, path := range []string{
+		"/c/l[name=a]/s[id=1]/v",
+		"/c/l[name=b]/s[id=1]/v",
+		"/c/l[name=a]/s[id=2]/v",
+		"/c/l[name=a]/s[id=1]/v",
+	} {
+		p, err := utils.ParsePath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = b.Add(ctx, p, "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := b.root.get("t:c").get("l")
+	if len(l.entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(l.entries))
+	}
+	if s := l.entry("a").get("s"); len(s.entries) != 2 || len(s.entry("1").children) != 2 {
+		t.Fatalf("entry a: got %d entries of s, want 2", len(s.entries))
+	}
+	// the entries are found by their rewritten keys
+	b.Anonymize(NewAnonymizer([]*AnonymizeRule{{Path: regexp.MustCompile(`^/c/l/name$`), Action: AnonymizeMask}}, "seed"))
+	if e := l.entry(maskedValue); e == nil || l.entry("a") != nil {
+		t.Errorf("got entry %v for the masked keys, want one", e)
+	}
+}
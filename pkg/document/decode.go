@@ -0,0 +1,216 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// Merge decodes the RFC 7951 JSON document data and merges it into the document.
+// Values are validated against the schema and kept in their canonical encoding.
+func (b *Builder) Merge(ctx context.Context, data []byte) error {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid JSON document: %v", err)
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return status.Error(codes.InvalidArgument, "invalid JSON document: expecting an object")
+	}
+	return b.mergeObject(ctx, b.root, nil, obj)
+}
+
+// MergeAt merges the RFC 7951 JSON value data found at path p into the document:
+// an object for a container or a list entry, a scalar for a leaf and an array
+// for a leaf-list or a whole list.
+func (b *Builder) MergeAt(ctx context.Context, p *sdcpb.Path, data []byte) error {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "path %s: invalid JSON value: %v", utils.ToXPath(p, false), err)
+	}
+	// wrap the value in the objects leading to it from the root
+	for i := len(p.GetElem()) - 1; i >= 0; i-- {
+		pe := p.GetElem()[i]
+		if len(pe.GetKey()) == 0 {
+			v = map[string]interface{}{pe.GetName(): v}
+			continue
+		}
+		o, ok := v.(map[string]interface{})
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "path %s points to a list entry, expecting an object",
+				utils.ToXPath(p, false))
+		}
+		for k, kv := range pe.GetKey() {
+			ov, ok := o[k]
+			if !ok {
+				o[k] = kv
+				continue
+			}
+			if raw, err := jsonRawValue(ov); err != nil || raw != kv {
+				return status.Errorf(codes.InvalidArgument, "path %s: key %q value %v does not match the path",
+					utils.ToXPath(p, false), k, ov)
+			}
+		}
+		v = map[string]interface{}{pe.GetName(): []interface{}{o}}
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return status.Error(codes.InvalidArgument, "a value at the root must be an object")
+	}
+	return b.mergeObject(ctx, b.root, nil, obj)
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	err := d.Decode(&v)
+	return v, err
+}
+
+func (b *Builder) mergeObject(ctx context.Context, n *node, names []string, obj map[string]interface{}) error {
+	members := make([]string, 0, len(obj))
+	for m := range obj {
+		members = append(members, m)
+	}
+	sort.Strings(members)
+	for _, m := range members {
+		v := obj[m]
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, m)
+		se, name, module, err := b.resolve(ctx, n, cnames)
+		if err != nil {
+			return err
+		}
+		switch se := se.GetSchema().(type) {
+		case *sdcpb.SchemaElem_Container:
+			if len(se.Container.GetKeys()) == 0 {
+				o, ok := v.(map[string]interface{})
+				if !ok {
+					return invalidMember(cnames, "expecting an object")
+				}
+				err = b.mergeObject(ctx, n.child(name, module, objectNode), cnames, o)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			entries, ok := v.([]interface{})
+			if !ok {
+				return invalidMember(cnames, "expecting an array of list entries")
+			}
+			l := b.list(n, name, module, se.Container)
+			for _, ev := range entries {
+				o, ok := ev.(map[string]interface{})
+				if !ok {
+					return invalidMember(cnames, "expecting list entries to be objects")
+				}
+				kv := make(map[string]string, len(se.Container.GetKeys()))
+				rest := make(map[string]interface{}, len(o))
+				for k, v := range o {
+					rest[k] = v
+				}
+				for _, k := range se.Container.GetKeys() {
+					kn := k.GetName()
+					kval, ok := o[kn]
+					if !ok && module != "" {
+						kn = module + ":" + k.GetName()
+						kval, ok = o[kn]
+					}
+					if !ok {
+						return invalidMember(cnames, fmt.Sprintf("list entry missing key %q", k.GetName()))
+					}
+					raw, err := jsonRawValue(kval)
+					if err != nil {
+						return invalidMember(append(cnames, k.GetName()), err.Error())
+					}
+					kv[k.GetName()] = raw
+					delete(rest, kn)
+				}
+				e, err := b.entry(ctx, l, se.Container, m, kv)
+				if err != nil {
+					return err
+				}
+				err = b.mergeObject(ctx, e, cnames, rest)
+				if err != nil {
+					return err
+				}
+			}
+		case *sdcpb.SchemaElem_Field:
+			raw, err := jsonRawValue(v)
+			if err != nil {
+				return invalidMember(cnames, err.Error())
+			}
+			ev, err := b.encode(ctx, se.Field.GetType(), module, raw)
+			if err != nil {
+				return invalidMember(cnames, err.Error())
+			}
+			b.leaf(n, name, module, se.Field.GetType()).value = ev
+		case *sdcpb.SchemaElem_Leaflist:
+			vs, ok := v.([]interface{})
+			if !ok {
+				return invalidMember(cnames, "expecting an array of values")
+			}
+			ll := b.leafList(n, name, module, se.Leaflist)
+			for _, lv := range vs {
+				raw, err := jsonRawValue(lv)
+				if err != nil {
+					return invalidMember(cnames, err.Error())
+				}
+				ev, err := b.encode(ctx, se.Leaflist.GetType(), module, raw)
+				if err != nil {
+					return invalidMember(cnames, err.Error())
+				}
+				ll.addValue(ev)
+			}
+		}
+	}
+	return nil
+}
+
+// jsonRawValue returns the raw string form of a decoded JSON scalar.
+func jsonRawValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		// empty type
+		if len(v) == 1 && v[0] == nil {
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("unexpected value %v", v)
+}
+
+func invalidMember(names []string, msg string) error {
+	return status.Errorf(codes.InvalidArgument, "/%s: %s", strings.Join(names, "/"), msg)
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+const (
+	OpAdd    = "add"
+	OpDelete = "delete"
+	OpUpdate = "update"
+	// the entries of an ordered-by user list changed order
+	OpReorder = "reorder"
+)
+
+// Change is a path level difference between two documents.
+type Change struct {
+	Op   string
+	Path *sdcpb.Path
+	// xpath with the keys in their declared order
+	XPath string
+	// JSON encoded values before and after the change:
+	// a subtree for nodes added or deleted as a whole, an array of values for leaf-lists,
+	// an array of key objects in the list order for a reorder.
+	Old json.RawMessage
+	New json.RawMessage
+}
+
+// Diff returns the changes turning the document from into the document to.
+// Containers and list entries present in a single document are reported as a single change,
+// values added to or deleted from a leaf-list are grouped in a single change per leaf-list
+// unless it is ordered-by user, in which case the whole leaf-list is updated.
+func Diff(from, to *Builder) ([]*Change, error) {
+	d := &differ{changes: make([]*Change, 0)}
+	err := d.object(diffPath{}, from.root, to.root)
+	if err != nil {
+		return nil, err
+	}
+	return d.changes, nil
+}
+
+type differ struct {
+	changes []*Change
+}
+
+// diffPath is a path under construction along with its xpath segments.
+type diffPath struct {
+	elems []*sdcpb.PathElem
+	xpath []string
+}
+
+func (p diffPath) append(n *node, entry *node) diffPath {
+	pe := &sdcpb.PathElem{Name: n.name}
+	sb := new(strings.Builder)
+	sb.WriteString(n.name)
+	if entry != nil {
+		pe.Key = make(map[string]string, len(entry.keyNames))
+		for _, k := range entry.keyNames {
+			pe.Key[k] = entry.keyValues[k]
+			sb.WriteString("[")
+			sb.WriteString(k)
+			sb.WriteString("=")
+			sb.WriteString(entry.keyValues[k])
+			sb.WriteString("]")
+		}
+	}
+	np := diffPath{
+		elems: make([]*sdcpb.PathElem, 0, len(p.elems)+1),
+		xpath: make([]string, 0, len(p.xpath)+1),
+	}
+	np.elems = append(np.elems, p.elems...)
+	np.elems = append(np.elems, pe)
+	np.xpath = append(np.xpath, p.xpath...)
+	np.xpath = append(np.xpath, sb.String())
+	return np
+}
+
+func (d *differ) add(op string, p diffPath, before, after []byte) {
+	d.changes = append(d.changes, &Change{
+		Op:    op,
+		Path:  &sdcpb.Path{Elem: p.elems},
+		XPath: "/" + strings.Join(p.xpath, "/"),
+		Old:   before,
+		New:   after,
+	})
+}
+
+func (d *differ) object(p diffPath, a, b *node) error {
+	for _, ca := range a.children {
+		if ca.isKey {
+			continue
+		}
+		cb := b.get(ca.name)
+		if cb == nil {
+			if err := d.whole(OpDelete, p, ca); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.member(p, ca, cb); err != nil {
+			return err
+		}
+	}
+	for _, cb := range b.children {
+		if cb.isKey || a.get(cb.name) != nil {
+			continue
+		}
+		if err := d.whole(OpAdd, p, cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// whole reports node n added or deleted as a whole,
+// list entries are reported one by one.
+func (d *differ) whole(op string, p diffPath, n *node) error {
+	if n.kind == listNode {
+		for _, e := range n.entries {
+			if err := d.entry(op, p, n, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	b, err := n.json()
+	if err != nil {
+		return err
+	}
+	if op == OpDelete {
+		d.add(op, p.append(n, nil), b, nil)
+		return nil
+	}
+	d.add(op, p.append(n, nil), nil, b)
+	return nil
+}
+
+func (d *differ) entry(op string, p diffPath, l, e *node) error {
+	b, err := e.json()
+	if err != nil {
+		return err
+	}
+	if op == OpDelete {
+		d.add(op, p.append(l, e), b, nil)
+		return nil
+	}
+	d.add(op, p.append(l, e), nil, b)
+	return nil
+}
+
+func (d *differ) member(p diffPath, a, b *node) error {
+	switch a.kind {
+	case objectNode:
+		return d.object(p.append(a, nil), a, b)
+	case leafNode:
+		if rawValue(a.value) == rawValue(b.value) {
+			return nil
+		}
+		before, err := a.json()
+		if err != nil {
+			return err
+		}
+		after, err := b.json()
+		if err != nil {
+			return err
+		}
+		d.add(OpUpdate, p.append(a, nil), before, after)
+	case leafListNode:
+		return d.leafList(p, a, b)
+	case listNode:
+		return d.list(p, a, b)
+	}
+	return nil
+}
+
+func (d *differ) leafList(p diffPath, a, b *node) error {
+	if a.userOrdered {
+		before, err := a.json()
+		if err != nil {
+			return err
+		}
+		after, err := b.json()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(before, after) {
+			d.add(OpUpdate, p.append(a, nil), before, after)
+		}
+		return nil
+	}
+	deleted := &node{kind: leafListNode}
+	for _, v := range a.values {
+		if !hasValue(b, v) {
+			deleted.values = append(deleted.values, v)
+		}
+	}
+	added := &node{kind: leafListNode}
+	for _, v := range b.values {
+		if !hasValue(a, v) {
+			added.values = append(added.values, v)
+		}
+	}
+	if len(deleted.values) > 0 {
+		before, err := deleted.json()
+		if err != nil {
+			return err
+		}
+		d.add(OpDelete, p.append(a, nil), before, nil)
+	}
+	if len(added.values) > 0 {
+		after, err := added.json()
+		if err != nil {
+			return err
+		}
+		d.add(OpAdd, p.append(a, nil), nil, after)
+	}
+	return nil
+}
+
+func hasValue(n *node, v interface{}) bool {
+	rv := rawValue(v)
+	for _, nv := range n.values {
+		if rawValue(nv) == rv {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) list(p diffPath, a, b *node) error {
+	// keys of the entries present in both lists, in their order in each list
+	commonA := make([]string, 0, len(a.entries))
+	for _, ea := range a.entries {
+		eb := b.entry(ea.keys)
+		if eb == nil {
+			if err := d.entry(OpDelete, p, a, ea); err != nil {
+				return err
+			}
+			continue
+		}
+		commonA = append(commonA, ea.keys)
+		if err := d.object(p.append(a, ea), ea, eb); err != nil {
+			return err
+		}
+	}
+	commonB := make([]string, 0, len(b.entries))
+	for _, eb := range b.entries {
+		if a.entry(eb.keys) == nil {
+			if err := d.entry(OpAdd, p, b, eb); err != nil {
+				return err
+			}
+			continue
+		}
+		commonB = append(commonB, eb.keys)
+	}
+	if !a.userOrdered {
+		return nil
+	}
+	for i := range commonA {
+		if commonA[i] != commonB[i] {
+			before, err := keysJSON(a)
+			if err != nil {
+				return err
+			}
+			after, err := keysJSON(b)
+			if err != nil {
+				return err
+			}
+			d.add(OpReorder, p.append(a, nil), before, after)
+			return nil
+		}
+	}
+	return nil
+}
+
+// keysJSON returns the key leaves of the entries of list n, in the list order.
+func keysJSON(n *node) ([]byte, error) {
+	l := &node{kind: listNode, entries: make([]*node, 0, len(n.entries))}
+	for _, e := range n.entries {
+		ke := &node{kind: objectNode}
+		for _, c := range e.children {
+			if c.isKey {
+				ke.children = append(ke.children, c)
+			}
+		}
+		l.entries = append(l.entries, ke)
+	}
+	return l.json()
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// Normalize sorts the document in its canonical order:
+// members in the declared order of the schema, list keys first,
+// entries of ordered-by system lists sorted by keys and values of
+// ordered-by system leaf-lists sorted, numerically if their type is numeric.
+// The order of ordered-by user lists and leaf-lists is kept.
+func (b *Builder) Normalize(ctx context.Context) error {
+	return b.normalize(ctx, b.root)
+}
+
+func (b *Builder) normalize(ctx context.Context, n *node) error {
+	switch n.kind {
+	case objectNode:
+		p := n.path
+		if p == "" {
+			p = "/"
+		}
+		order, err := b.resolver.Order(ctx, p)
+		if err != nil {
+			return err
+		}
+		// position of the members in the schema, the top-level ones are module qualified
+		pos := make(map[string]int, len(order))
+		for i, name := range order {
+			if n.path == "" {
+				pos[name] = i
+				continue
+			}
+			pos[localName(name)] = i
+		}
+		rank := func(c *node) (int, bool) {
+			name := localName(c.name)
+			if n.path == "" {
+				name = c.module + ":" + name
+			}
+			i, ok := pos[name]
+			return i, ok
+		}
+		sort.SliceStable(n.children, func(i, j int) bool {
+			ci, cj := n.children[i], n.children[j]
+			if ci.isKey != cj.isKey {
+				return ci.isKey
+			}
+			ri, oki := rank(ci)
+			rj, okj := rank(cj)
+			switch {
+			case oki && okj && ri != rj:
+				return ri < rj
+			case oki != okj:
+				// the members unknown to the schema order last
+				return oki
+			}
+			li, lj := localName(ci.name), localName(cj.name)
+			if li != lj {
+				return li < lj
+			}
+			return ci.name < cj.name
+		})
+		for _, c := range n.children {
+			if err := b.normalize(ctx, c); err != nil {
+				return err
+			}
+		}
+	case listNode:
+		if !n.userOrdered && len(n.entries) > 0 {
+			types := keyTypes(n.entries[0])
+			sort.SliceStable(n.entries, func(i, j int) bool {
+				ei, ej := n.entries[i], n.entries[j]
+				for _, k := range ei.keyNames {
+					if c := compareRaw(types[k], ei.keyValues[k], ej.keyValues[k]); c != 0 {
+						return c < 0
+					}
+				}
+				return false
+			})
+		}
+		for _, e := range n.entries {
+			if err := b.normalize(ctx, e); err != nil {
+				return err
+			}
+		}
+	case leafListNode:
+		if !n.userOrdered {
+			sort.SliceStable(n.values, func(i, j int) bool {
+				return compareRaw(n.typ, rawValue(n.values[i]), rawValue(n.values[j])) < 0
+			})
+		}
+	}
+	return nil
+}
+
+// keyTypes returns the types of the keys of list entry e by key name.
+func keyTypes(e *node) map[string]*sdcpb.SchemaLeafType {
+	types := make(map[string]*sdcpb.SchemaLeafType, len(e.keyNames))
+	for _, c := range e.children {
+		if c.isKey {
+			types[c.name] = c.typ
+		}
+	}
+	return types
+}
+
+func localName(name string) string {
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+var numericTypes = map[string]struct{}{
+	"int8": {}, "int16": {}, "int32": {}, "int64": {},
+	"uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
+	"decimal64": {},
+}
+
+var decimalRe = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+
+// compareRaw compares raw values of type t: numerically if t is a numeric
+// type, byte-wise otherwise. Values of a numeric type that are not numbers
+// come after the numbers.
+func compareRaw(t *sdcpb.SchemaLeafType, a, b string) int {
+	if _, ok := numericTypes[t.GetType()]; !ok {
+		return strings.Compare(a, b)
+	}
+	okA, okB := decimalRe.MatchString(a), decimalRe.MatchString(b)
+	switch {
+	case okA && okB:
+		ra, _ := new(big.Rat).SetString(a)
+		rb, _ := new(big.Rat).SetString(b)
+		return ra.Cmp(rb)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+func TestCompareRaw(t *testing.T) {
+	uint32T := &sdcpb.SchemaLeafType{Type: "uint32"}
+	decT := &sdcpb.SchemaLeafType{Type: "decimal64"}
+	stringT := &sdcpb.SchemaLeafType{Type: "string"}
+	tests := []struct {
+		name string
+		t    *sdcpb.SchemaLeafType
+		a, b string
+		want int
+	}{
+		{name: "numbers", t: uint32T, a: "2", b: "10", want: -1},
+		{name: "equal numbers", t: decT, a: "1.50", b: "1.5", want: 0},
+		{name: "negative decimal", t: decT, a: "-0.5", b: "0.25", want: -1},
+		{name: "fraction is not a number", t: decT, a: "1/2", b: "1", want: 1},
+		{name: "strings bytewise", t: stringT, a: "2", b: "10", want: 1},
+		{name: "string numbers and letters", t: stringT, a: "1a", b: "2", want: -1},
+		{name: "no type", a: "b", b: "a", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareRaw(tt.t, tt.a, tt.b); got != tt.want {
+				t.Errorf("compareRaw(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch operations, RFC 6902
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// ErrPatchTest is returned applying a test operation whose value differs from the document one.
+var ErrPatchTest = errors.New("test failed")
+
+// PatchOperation is an operation of a JSON Patch.
+type PatchOperation struct {
+	Op   string
+	Path string
+	// source of move and copy
+	From string
+	// value of add, replace and test, decoded as by a json.Decoder using numbers
+	Value interface{}
+}
+
+// PatchError is an error of the operation Index of a patch,
+// -1 if the patch is not an array of operations.
+type PatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	if e.Index < 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// DecodePatch decodes the JSON Patch document data, RFC 6902.
+func DecodePatch(data []byte) ([]*PatchOperation, error) {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return nil, &PatchError{Index: -1, Err: fmt.Errorf("invalid JSON Patch: %v", err)}
+	}
+	vs, ok := v.([]interface{})
+	if !ok {
+		return nil, &PatchError{Index: -1, Err: errors.New("invalid JSON Patch: expecting an array of operations")}
+	}
+	ops := make([]*PatchOperation, 0, len(vs))
+	for i, v := range vs {
+		raw, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, &PatchError{Index: i, Err: errors.New("expecting an object")}
+		}
+		op, err := decodePatchOperation(raw)
+		if err != nil {
+			return nil, &PatchError{Index: i, Err: err}
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func decodePatchOperation(raw map[string]interface{}) (*PatchOperation, error) {
+	op := new(PatchOperation)
+	member := func(name string, s *string) error {
+		v, ok := raw[name]
+		if !ok {
+			return fmt.Errorf("missing %q", name)
+		}
+		if *s, ok = v.(string); !ok {
+			return fmt.Errorf("%q must be a string", name)
+		}
+		return nil
+	}
+	if err := member("op", &op.Op); err != nil {
+		return nil, err
+	}
+	if err := member("path", &op.Path); err != nil {
+		return nil, err
+	}
+	if _, err := ParsePointer(op.Path); err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case PatchAdd, PatchReplace, PatchTest:
+		var ok bool
+		if op.Value, ok = raw["value"]; !ok {
+			return nil, fmt.Errorf("missing %q", "value")
+		}
+	case PatchMove, PatchCopy:
+		if err := member("from", &op.From); err != nil {
+			return nil, err
+		}
+		if _, err := ParsePointer(op.From); err != nil {
+			return nil, err
+		}
+	case PatchRemove:
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+	return op, nil
+}
+
+// ParsePointer returns the reference tokens of the JSON Pointer p, RFC 6901,
+// none for the whole document.
+func ParsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("JSON pointer %q does not start with /", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		for j := 0; j < len(t); j++ {
+			if t[j] == '~' && (j == len(t)-1 || t[j+1] != '0' && t[j+1] != '1') {
+				return nil, fmt.Errorf("JSON pointer %q has an invalid ~ escape", p)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// Lookup returns the value at the reference tokens of a JSON pointer in doc.
+func Lookup(doc interface{}, tokens []string) (interface{}, bool) {
+	for _, t := range tokens {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			var ok bool
+			doc, ok = v[t]
+			if !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := arrayIndex(t, len(v)-1)
+			if err != nil {
+				return nil, false
+			}
+			doc = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+// Apply applies op to doc, a decoded JSON document modified in place,
+// and returns the patched document.
+func (op *PatchOperation) Apply(doc interface{}) (interface{}, error) {
+	tokens, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case PatchAdd:
+		return add(doc, tokens, op.Value)
+	case PatchRemove:
+		doc, _, err = remove(doc, tokens)
+		return doc, err
+	case PatchReplace:
+		if _, ok := Lookup(doc, tokens); !ok {
+			return nil, fmt.Errorf("%s not found", op.Path)
+		}
+		if len(tokens) == 0 {
+			return op.Value, nil
+		}
+		doc, _, err = remove(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, tokens, op.Value)
+	case PatchMove, PatchCopy:
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := Lookup(doc, from)
+		if !ok {
+			return nil, fmt.Errorf("%s not found", op.From)
+		}
+		if op.Op == PatchCopy {
+			return add(doc, tokens, deepCopy(v))
+		}
+		if op.Path == op.From {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s to its child %s", op.From, op.Path)
+		}
+		doc, _, err = remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, tokens, v)
+	case PatchTest:
+		v, ok := Lookup(doc, tokens)
+		if !ok {
+			return nil, fmt.Errorf("%s not found", op.Path)
+		}
+		if !jsonEqual(v, op.Value) {
+			return nil, ErrPatchTest
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// update returns doc with the value of the container at tokens, an object
+// or an array, replaced by the one f returns for it.
+func update(doc interface{}, tokens []string, f func(interface{}) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 0 {
+		return f(doc)
+	}
+	t := tokens[0]
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		c, ok := v[t]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", t)
+		}
+		nc, err := update(c, tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		v[t] = nc
+		return v, nil
+	case []interface{}:
+		i, err := arrayIndex(t, len(v)-1)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := update(v[i], tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = nc
+		return v, nil
+	}
+	return nil, fmt.Errorf("cannot reference %q in a %s", t, jsonType(doc))
+}
+
+// add adds val at tokens: it replaces the value of an object member or
+// is inserted in an array before the index, appended for "-".
+func add(doc interface{}, tokens []string, val interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	last := tokens[len(tokens)-1]
+	return update(doc, tokens[:len(tokens)-1], func(c interface{}) (interface{}, error) {
+		switch c := c.(type) {
+		case map[string]interface{}:
+			c[last] = val
+			return c, nil
+		case []interface{}:
+			i := len(c)
+			if last != "-" {
+				var err error
+				i, err = arrayIndex(last, len(c))
+				if err != nil {
+					return nil, err
+				}
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = val
+			return c, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to a %s", last, jsonType(c))
+	})
+}
+
+// remove removes the value at tokens, returned along with the patched document.
+func remove(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+	last := tokens[len(tokens)-1]
+	var removed interface{}
+	doc, err := update(doc, tokens[:len(tokens)-1], func(c interface{}) (interface{}, error) {
+		switch c := c.(type) {
+		case map[string]interface{}:
+			v, ok := c[last]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", last)
+			}
+			removed = v
+			delete(c, last)
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(last, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q from a %s", last, jsonType(c))
+	})
+	return doc, removed, err
+}
+
+// arrayIndex parses the array index t, up to max.
+func arrayIndex(t string, max int) (int, error) {
+	if t == "" || len(t) > 1 && t[0] == '0' || strings.TrimLeft(t, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", t)
+	}
+	i, err := strconv.Atoi(t)
+	if err != nil || i > max {
+		return 0, fmt.Errorf("array index %s out of range", t)
+	}
+	return i, nil
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// jsonEqual reports whether a and b are equal as defined by the test
+// operation: the numbers are compared by value, the objects regardless
+// of the order of their members.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ar, aok := new(big.Rat).SetString(a.String())
+		br, bok := new(big.Rat).SetString(b.String())
+		if !aok || !bok {
+			return a == b
+		}
+		return ar.Cmp(br) == 0
+	}
+	return a == b
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, cv := range v {
+			c[k] = deepCopy(cv)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, cv := range v {
+			c[i] = deepCopy(cv)
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPatchOperation_Apply(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		op   string
+		want string
+		// the operation fails, with ErrPatchTest if test is set
+		fail bool
+		test bool
+	}{
+		// add
+		{name: "add member", doc: `{"a":1}`, op: `{"op":"add","path":"/b","value":2}`, want: `{"a":1,"b":2}`},
+		{name: "add existing member", doc: `{"a":1}`, op: `{"op":"add","path":"/a","value":[3]}`, want: `{"a":[3]}`},
+		{name: "add nested member", doc: `{"a":{}}`, op: `{"op":"add","path":"/a/b","value":null}`, want: `{"a":{"b":null}}`},
+		{name: "add under missing member", doc: `{"a":1}`, op: `{"op":"add","path":"/x/y","value":1}`, fail: true},
+		{name: "add under scalar", doc: `{"a":1}`, op: `{"op":"add","path":"/a/b","value":1}`, fail: true},
+		{name: "add whole document", doc: `{"a":1}`, op: `{"op":"add","path":"","value":{"b":2}}`, want: `{"b":2}`},
+		{name: "insert array value", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/1","value":9}`, want: `{"a":[1,9,2]}`},
+		{name: "insert first array value", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/0","value":9}`, want: `{"a":[9,1,2]}`},
+		{name: "append array value", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/-","value":3}`, want: `{"a":[1,2,3]}`},
+		{name: "append to empty array", doc: `{"a":[]}`, op: `{"op":"add","path":"/a/-","value":3}`, want: `{"a":[3]}`},
+		{name: "add at array length", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/2","value":3}`, want: `{"a":[1,2,3]}`},
+		{name: "add past array length", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/3","value":3}`, fail: true},
+		{name: "add at leading zero index", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/01","value":3}`, fail: true},
+		{name: "add at negative index", doc: `{"a":[1,2]}`, op: `{"op":"add","path":"/a/-1","value":3}`, fail: true},
+		{name: "add under append index", doc: `{"a":[{}]}`, op: `{"op":"add","path":"/a/-/b","value":3}`, fail: true},
+		// remove
+		{name: "remove member", doc: `{"a":1,"b":2}`, op: `{"op":"remove","path":"/a"}`, want: `{"b":2}`},
+		{name: "remove missing member", doc: `{"a":1}`, op: `{"op":"remove","path":"/b"}`, fail: true},
+		{name: "remove array value", doc: `{"a":[1,2,3]}`, op: `{"op":"remove","path":"/a/1"}`, want: `{"a":[1,3]}`},
+		{name: "remove last array value", doc: `{"a":[1,2,3]}`, op: `{"op":"remove","path":"/a/2"}`, want: `{"a":[1,2]}`},
+		{name: "remove past array end", doc: `{"a":[1,2,3]}`, op: `{"op":"remove","path":"/a/3"}`, fail: true},
+		{name: "remove append index", doc: `{"a":[1,2,3]}`, op: `{"op":"remove","path":"/a/-"}`, fail: true},
+		{name: "remove at leading zero index", doc: `{"a":[1,2,3]}`, op: `{"op":"remove","path":"/a/00"}`, fail: true},
+		{name: "remove whole document", doc: `{"a":1}`, op: `{"op":"remove","path":""}`, fail: true},
+		// replace
+		{name: "replace member", doc: `{"a":1,"b":2}`, op: `{"op":"replace","path":"/a","value":{"c":3}}`, want: `{"a":{"c":3},"b":2}`},
+		{name: "replace missing member", doc: `{"a":1}`, op: `{"op":"replace","path":"/b","value":2}`, fail: true},
+		{name: "replace array value", doc: `{"a":[1,2]}`, op: `{"op":"replace","path":"/a/0","value":9}`, want: `{"a":[9,2]}`},
+		{name: "replace append index", doc: `{"a":[1,2]}`, op: `{"op":"replace","path":"/a/-","value":9}`, fail: true},
+		{name: "replace past array end", doc: `{"a":[1,2]}`, op: `{"op":"replace","path":"/a/2","value":9}`, fail: true},
+		{name: "replace whole document", doc: `{"a":1}`, op: `{"op":"replace","path":"","value":{"b":2}}`, want: `{"b":2}`},
+		// move
+		{name: "move member", doc: `{"a":{"b":1}}`, op: `{"op":"move","from":"/a/b","path":"/c"}`, want: `{"a":{},"c":1}`},
+		{name: "move member over another", doc: `{"a":1,"b":2}`, op: `{"op":"move","from":"/a","path":"/b"}`, want: `{"b":1}`},
+		{name: "move to itself", doc: `{"a":1}`, op: `{"op":"move","from":"/a","path":"/a"}`, want: `{"a":1}`},
+		{name: "move to a sibling sharing its prefix", doc: `{"a":1}`, op: `{"op":"move","from":"/a","path":"/ab"}`, want: `{"ab":1}`},
+		{name: "move into own child", doc: `{"a":{"b":{}}}`, op: `{"op":"move","from":"/a","path":"/a/b/c"}`, fail: true},
+		{name: "move whole document into a child", doc: `{"a":{}}`, op: `{"op":"move","from":"","path":"/a/b"}`, fail: true},
+		{name: "move missing member", doc: `{"a":1}`, op: `{"op":"move","from":"/b","path":"/c"}`, fail: true},
+		{name: "move array value forward", doc: `{"a":[1,2,3]}`, op: `{"op":"move","from":"/a/0","path":"/a/2"}`, want: `{"a":[2,3,1]}`},
+		{name: "move array value backward", doc: `{"a":[1,2,3]}`, op: `{"op":"move","from":"/a/2","path":"/a/0"}`, want: `{"a":[3,1,2]}`},
+		{name: "move array value to the end", doc: `{"a":[1,2,3]}`, op: `{"op":"move","from":"/a/0","path":"/a/-"}`, want: `{"a":[2,3,1]}`},
+		{name: "move array value to an object", doc: `{"a":[1,2],"b":{}}`, op: `{"op":"move","from":"/a/1","path":"/b/c"}`, want: `{"a":[1],"b":{"c":2}}`},
+		// copy
+		{name: "copy member", doc: `{"a":{"b":1}}`, op: `{"op":"copy","from":"/a","path":"/c"}`, want: `{"a":{"b":1},"c":{"b":1}}`},
+		{name: "copy into own child", doc: `{"a":{"b":1}}`, op: `{"op":"copy","from":"/a","path":"/a/c"}`, want: `{"a":{"b":1,"c":{"b":1}}}`},
+		{name: "copy array value", doc: `{"a":[1,2]}`, op: `{"op":"copy","from":"/a/0","path":"/a/-"}`, want: `{"a":[1,2,1]}`},
+		{name: "copy array value at an index", doc: `{"a":[1,2]}`, op: `{"op":"copy","from":"/a/1","path":"/a/0"}`, want: `{"a":[2,1,2]}`},
+		{name: "copy missing member", doc: `{"a":1}`, op: `{"op":"copy","from":"/b","path":"/c"}`, fail: true},
+		{name: "copy from leading zero index", doc: `{"a":[1,2]}`, op: `{"op":"copy","from":"/a/01","path":"/b"}`, fail: true},
+		// test
+		{name: "test member", doc: `{"a":"x"}`, op: `{"op":"test","path":"/a","value":"x"}`, want: `{"a":"x"}`},
+		{name: "test different member", doc: `{"a":"x"}`, op: `{"op":"test","path":"/a","value":"y"}`, fail: true, test: true},
+		{name: "test different type", doc: `{"a":"1"}`, op: `{"op":"test","path":"/a","value":1}`, fail: true, test: true},
+		{name: "test number value", doc: `{"a":1}`, op: `{"op":"test","path":"/a","value":1.0}`, want: `{"a":1}`},
+		{name: "test exponent number", doc: `{"a":100}`, op: `{"op":"test","path":"/a","value":1e2}`, want: `{"a":100}`},
+		{name: "test object members in any order", doc: `{"a":{"b":1,"c":2}}`, op: `{"op":"test","path":"/a","value":{"c":2,"b":1}}`, want: `{"a":{"b":1,"c":2}}`},
+		{name: "test object with more members", doc: `{"a":{"b":1}}`, op: `{"op":"test","path":"/a","value":{"b":1,"c":2}}`, fail: true, test: true},
+		{name: "test array order", doc: `{"a":[1,2]}`, op: `{"op":"test","path":"/a","value":[2,1]}`, fail: true, test: true},
+		{name: "test array value", doc: `{"a":[1,2]}`, op: `{"op":"test","path":"/a/1","value":2}`, want: `{"a":[1,2]}`},
+		{name: "test null", doc: `{"a":null}`, op: `{"op":"test","path":"/a","value":null}`, want: `{"a":null}`},
+		{name: "test missing member", doc: `{"a":1}`, op: `{"op":"test","path":"/b","value":1}`, fail: true},
+		{name: "test whole document", doc: `{"a":1}`, op: `{"op":"test","path":"","value":{"a":1}}`, want: `{"a":1}`},
+		// escapes
+		{name: "~1 escape", doc: `{"a/b":1}`, op: `{"op":"test","path":"/a~1b","value":1}`, want: `{"a/b":1}`},
+		{name: "~0 escape", doc: `{"m~n":1}`, op: `{"op":"remove","path":"/m~0n"}`, want: `{}`},
+		{name: "~01 escape", doc: `{"~1":1}`, op: `{"op":"test","path":"/~01","value":1}`, want: `{"~1":1}`},
+		{name: "~10 escape", doc: `{"/0":1}`, op: `{"op":"test","path":"/~10","value":1}`, want: `{"/0":1}`},
+		{name: "escaped from", doc: `{"a/b":1}`, op: `{"op":"move","from":"/a~1b","path":"/m~0n"}`, want: `{"m~n":1}`},
+		{name: "empty member name", doc: `{"":1}`, op: `{"op":"test","path":"/","value":1}`, want: `{"":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeJSON([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ops, err := DecodePatch([]byte("[" + tt.op + "]"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ops[0].Apply(doc)
+			if (err != nil) != tt.fail {
+				t.Fatalf("got error %v, want failure %v", err, tt.fail)
+			}
+			if errors.Is(err, ErrPatchTest) != tt.test {
+				t.Fatalf("got error %v, want a test failure %v", err, tt.test)
+			}
+			if tt.fail {
+				return
+			}
+			want, err := decodeJSON([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jsonEqual(got, want) {
+				js, _ := json.Marshal(got)
+				t.Errorf("got %s, want %s", js, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchOperation_ApplyCopyIsDeep(t *testing.T) {
+	doc, err := decodeJSON([]byte(`{"a":{"b":[1]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := DecodePatch([]byte(`[
+		{"op":"copy","from":"/a","path":"/c"},
+		{"op":"add","path":"/c/b/-","value":2},
+		{"op":"add","path":"/c/d","value":3}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range ops {
+		if doc, err = op.Apply(doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, _ := decodeJSON([]byte(`{"a":{"b":[1]},"c":{"b":[1,2],"d":3}}`))
+	if !jsonEqual(doc, want) {
+		js, _ := json.Marshal(doc)
+		t.Errorf("got %s, want the copy only patched", js)
+	}
+}
+
+func TestDecodePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		// index of the failing operation, -1 for the whole patch
+		index int
+		fail  bool
+	}{
+		{name: "operations", patch: `[{"op":"add","path":"/a","value":1},{"op":"remove","path":"/a"}]`},
+		{name: "null value", patch: `[{"op":"add","path":"/a","value":null}]`},
+		{name: "empty", patch: `[]`},
+		{name: "not JSON", patch: `[`, index: -1, fail: true},
+		{name: "not an array", patch: `{"op":"add","path":"/a","value":1}`, index: -1, fail: true},
+		{name: "not an object", patch: `[{"op":"remove","path":"/a"},1]`, index: 1, fail: true},
+		{name: "missing op", patch: `[{"path":"/a"}]`, fail: true},
+		{name: "unknown op", patch: `[{"op":"merge","path":"/a","value":1}]`, fail: true},
+		{name: "op not a string", patch: `[{"op":1,"path":"/a"}]`, fail: true},
+		{name: "missing path", patch: `[{"op":"remove"}]`, fail: true},
+		{name: "path not a pointer", patch: `[{"op":"remove","path":"a"}]`, fail: true},
+		{name: "missing value", patch: `[{"op":"add","path":"/a"}]`, fail: true},
+		{name: "missing test value", patch: `[{"op":"test","path":"/a"}]`, fail: true},
+		{name: "missing from", patch: `[{"op":"move","path":"/a"}]`, fail: true},
+		{name: "from not a pointer", patch: `[{"op":"copy","from":"a","path":"/b"}]`, fail: true},
+		{name: "invalid escape", patch: `[{"op":"remove","path":"/a~2"}]`, fail: true},
+		{name: "trailing ~", patch: `[{"op":"remove","path":"/a~"}]`, fail: true},
+		{name: "invalid from escape", patch: `[{"op":"move","from":"/~a","path":"/b"}]`, fail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodePatch([]byte(tt.patch))
+			if (err != nil) != tt.fail {
+				t.Fatalf("got error %v, want failure %v", err, tt.fail)
+			}
+			if err == nil {
+				return
+			}
+			var perr *PatchError
+			if !errors.As(err, &perr) || perr.Index != tt.index {
+				t.Errorf("got error %v, want a PatchError of operation %d", err, tt.index)
+			}
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		ptr    string
+		tokens []string
+		fail   bool
+	}{
+		{ptr: "", tokens: nil},
+		{ptr: "/", tokens: []string{""}},
+		{ptr: "/a/0/-", tokens: []string{"a", "0", "-"}},
+		{ptr: "/a~1b/m~0n", tokens: []string{"a/b", "m~n"}},
+		{ptr: "/~01/~10", tokens: []string{"~1", "/0"}},
+		{ptr: "//", tokens: []string{"", ""}},
+		{ptr: "a", fail: true},
+		{ptr: "/~", fail: true},
+		{ptr: "/~2", fail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ptr, func(t *testing.T) {
+			tokens, err := ParsePointer(tt.ptr)
+			if (err != nil) != tt.fail {
+				t.Fatalf("got error %v, want failure %v", err, tt.fail)
+			}
+			if !reflect.DeepEqual(tokens, tt.tokens) {
+				t.Errorf("got %q, want %q", tokens, tt.tokens)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// CanonicalPath validates the keys of the elements of p against the schema
+// and returns a copy of p without module prefixes and with key values in their
+// canonical form, along with its xpath with keys in the schema declared order.
+func CanonicalPath(ctx context.Context, r *store.Resolver, p *sdcpb.Path) (*sdcpb.Path, string, error) {
+	cp := &sdcpb.Path{
+		Origin: p.GetOrigin(),
+		Elem:   make([]*sdcpb.PathElem, 0, len(p.GetElem())),
+	}
+	sb := new(strings.Builder)
+	if cp.Origin != "" {
+		sb.WriteString(cp.Origin)
+		sb.WriteString(":")
+	}
+	names := make([]string, 0, len(p.GetElem()))
+	for i, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+		se, err := r.Get(ctx, names)
+		if err != nil {
+			return nil, "", err
+		}
+		name := pe.GetName()
+		if idx := strings.Index(name, ":"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		cpe := &sdcpb.PathElem{Name: name}
+		cp.Elem = append(cp.Elem, cpe)
+		if i > 0 {
+			sb.WriteString("/")
+		}
+		sb.WriteString(name)
+
+		var keys []*sdcpb.LeafSchema
+		if c, ok := se.GetSchema().(*sdcpb.SchemaElem_Container); ok {
+			keys = c.Container.GetKeys()
+		}
+		if len(pe.GetKey()) == 0 {
+			// a path can point to a whole list
+			continue
+		}
+		if err := checkKeys(pe, keys); err != nil {
+			return nil, "", err
+		}
+		// the schema lists the keys by name
+		keys, err = r.Keys(ctx, names, keys)
+		if err != nil {
+			return nil, "", err
+		}
+		cpe.Key = make(map[string]string, len(keys))
+		for _, k := range keys {
+			v, ok := pe.GetKey()[k.GetName()]
+			if !ok {
+				return nil, "", status.Errorf(codes.InvalidArgument, "%q: missing key %q", pe.GetName(), k.GetName())
+			}
+			cv, err := canonicalValue(k.GetType(), v)
+			if err != nil {
+				return nil, "", status.Errorf(codes.InvalidArgument, "%q key %q: %v", pe.GetName(), k.GetName(), err)
+			}
+			cpe.Key[k.GetName()] = cv
+			sb.WriteString("[")
+			sb.WriteString(k.GetName())
+			sb.WriteString("=")
+			sb.WriteString(cv)
+			sb.WriteString("]")
+		}
+	}
+	return cp, sb.String(), nil
+}
+
+// canonicalValue returns the canonical string form of the raw value v of type t.
+func canonicalValue(t *sdcpb.SchemaLeafType, v string) (string, error) {
+	ev, err := EncodeValue(t, v)
+	if err != nil {
+		return "", err
+	}
+	return rawValue(ev), nil
+}
+
+// checkKeys returns an InvalidArgument error if a key of path element pe
+// is not one of the keys of its list.
+func checkKeys(pe *sdcpb.PathElem, keys []*sdcpb.LeafSchema) error {
+	declared := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		declared[k.GetName()] = struct{}{}
+	}
+	for k := range pe.GetKey() {
+		if _, ok := declared[k]; !ok {
+			return status.Errorf(codes.InvalidArgument, "%q: unknown key %q, expecting %v",
+				pe.GetName(), k, keyNames(keys))
+		}
+	}
+	return nil
+}
+
+func keyNames(keys []*sdcpb.LeafSchema) []string {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const pathModule = `module t {
+  namespace "urn:t";
+  prefix t;
+  list l {
+    key "z a";
+    leaf z { type string; }
+    leaf a { type string; }
+  }
+  list d {
+    key "v";
+    leaf v {
+      type decimal64 {
+        fraction-digits 2;
+      }
+    }
+  }
+}
+`
+
+func TestCanonicalPath(t *testing.T) {
+	r := testResolver(t, pathModule)
+	tests := []struct {
+		path  string
+		xpath string
+		code  codes.Code
+	}{
+		{path: "/l[a=1][z=2]", xpath: "l[z=2][a=1]"},
+		{path: "/t:l[z=2][a=1]", xpath: "l[z=2][a=1]"},
+		{path: "/l[z=2]", code: codes.InvalidArgument},
+		{path: "/l[z=2][a=1][b=3]", code: codes.InvalidArgument},
+		{path: "/d[v=+01.50]", xpath: "d[v=1.5]"},
+		{path: "/d[v=10]", xpath: "d[v=10.0]"},
+		{path: "/d[v=-0.00]", xpath: "d[v=0.0]"},
+		{path: "/d[v=-2.05]", xpath: "d[v=-2.05]"},
+		{path: "/d[v=1.005]", code: codes.InvalidArgument},
+		{path: "/d[v=1e3]", code: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := utils.ParsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, xp, err := CanonicalPath(context.Background(), r, p)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if xp != tt.xpath {
+				t.Errorf("got %q, want %q", xp, tt.xpath)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// a placeholder is a key value made of a name between braces, e.g: interface[name={iface}]
+var placeholderRegexp = regexp.MustCompile(`^\{([A-Za-z_][A-Za-z0-9_.-]*)\}$`)
+
+// Placeholder is a named parameter of a path template.
+type Placeholder struct {
+	Name string
+	// index of the path element and name of the key the placeholder is the value of
+	Elem int
+	Key  string
+	// type of the key leaf
+	Type *sdcpb.SchemaLeafType
+}
+
+// Template is a path with placeholders as key values.
+type Template struct {
+	Path *sdcpb.Path
+	// schema node the path points to
+	Schema       *sdcpb.SchemaElem
+	Placeholders []*Placeholder
+	// declared key names of each path element
+	keys [][]string
+}
+
+// HasPlaceholder reports whether a key value of path p is a placeholder.
+func HasPlaceholder(p *sdcpb.Path) bool {
+	for _, pe := range p.GetElem() {
+		for _, v := range pe.GetKey() {
+			if _, ok := IsPlaceholder(v); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsPlaceholder reports whether the key value v is a placeholder and returns its name.
+func IsPlaceholder(v string) (string, bool) {
+	m := placeholderRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// ParseTemplate resolves the schema nodes of the path template p
+// and the types of its placeholders.
+// A placeholder used more than once must have the same type everywhere.
+func ParseTemplate(ctx context.Context, r *store.Resolver, p *sdcpb.Path) (*Template, error) {
+	t := &Template{Path: p}
+	types := make(map[string]*sdcpb.SchemaLeafType)
+	names := make([]string, 0, len(p.GetElem()))
+	var err error
+	for i, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+		t.Schema, err = r.Get(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		var keys []*sdcpb.LeafSchema
+		keys, err = r.Keys(ctx, names, t.Schema.GetContainer().GetKeys())
+		if err != nil {
+			return nil, err
+		}
+		t.keys = append(t.keys, declaredKeys(keys))
+		if len(pe.GetKey()) == 0 {
+			continue
+		}
+		if err = checkKeys(pe, keys); err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			v, ok := pe.GetKey()[k.GetName()]
+			if !ok {
+				continue
+			}
+			name, ok := IsPlaceholder(v)
+			if !ok {
+				continue
+			}
+			if pt, ok := types[name]; ok && !proto.Equal(pt, k.GetType()) {
+				return nil, status.Errorf(codes.InvalidArgument, "placeholder %q is used with different types", name)
+			}
+			types[name] = k.GetType()
+			t.Placeholders = append(t.Placeholders, &Placeholder{
+				Name: name,
+				Elem: i,
+				Key:  k.GetName(),
+				Type: k.GetType(),
+			})
+		}
+	}
+	return t, nil
+}
+
+// Render returns a copy of the template path with the placeholders replaced by
+// the given values, each value is validated against the placeholder type.
+func (t *Template) Render(values map[string]string) (*sdcpb.Path, error) {
+	p := proto.Clone(t.Path).(*sdcpb.Path)
+	for _, ph := range t.Placeholders {
+		v, ok := values[ph.Name]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "missing value for placeholder %q", ph.Name)
+		}
+		cv, err := canonicalValue(ph.Type, v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "placeholder %q: %v", ph.Name, err)
+		}
+		p.Elem[ph.Elem].Key[ph.Key] = cv
+	}
+	return p, nil
+}
+
+// InstanceTemplate returns the template of the instance paths of the lists p points to
+// or goes through: the keys missing from p are replaced by placeholders named after
+// the key, prefixed by the list name if the name is already used, e.g:
+// /interface/subinterface gives /interface[name={name}]/subinterface[index={index}].
+func InstanceTemplate(ctx context.Context, r *store.Resolver, p *sdcpb.Path) (*Template, error) {
+	t := &Template{Path: proto.Clone(p).(*sdcpb.Path)}
+	used := make(map[string]struct{})
+	names := make([]string, 0, len(p.GetElem()))
+	var err error
+	for i, pe := range t.Path.GetElem() {
+		names = append(names, pe.GetName())
+		t.Schema, err = r.Get(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		var keys []*sdcpb.LeafSchema
+		keys, err = r.Keys(ctx, names, t.Schema.GetContainer().GetKeys())
+		if err != nil {
+			return nil, err
+		}
+		t.keys = append(t.keys, declaredKeys(keys))
+		if err = checkKeys(pe, keys); err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if _, ok := pe.GetKey()[k.GetName()]; ok {
+				continue
+			}
+			name := k.GetName()
+			if _, ok := used[name]; ok {
+				name = localName(pe.GetName()) + "-" + name
+			}
+			for j := 2; ; j++ {
+				if _, ok := used[name]; !ok {
+					break
+				}
+				name = localName(pe.GetName()) + "-" + k.GetName() + strconv.Itoa(j)
+			}
+			used[name] = struct{}{}
+			if pe.Key == nil {
+				pe.Key = make(map[string]string, len(keys))
+			}
+			pe.Key[k.GetName()] = "{" + name + "}"
+			t.Placeholders = append(t.Placeholders, &Placeholder{
+				Name: name,
+				Elem: i,
+				Key:  k.GetName(),
+				Type: k.GetType(),
+			})
+		}
+	}
+	if len(t.Placeholders) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "path %s does not go through a list with missing keys",
+			utils.ToXPath(p, false))
+	}
+	return t, nil
+}
+
+// XPath returns the xpath of the template with the keys in their declared order.
+// If typed is set, the placeholders are replaced by the type of their key, e.g:
+// /interface[name=<string(length=3..20)>].
+func (t *Template) XPath(typed bool) string {
+	types := make(map[string]*sdcpb.SchemaLeafType, len(t.Placeholders))
+	for _, ph := range t.Placeholders {
+		types[ph.Name] = ph.Type
+	}
+	sb := new(strings.Builder)
+	if t.Path.GetOrigin() != "" {
+		sb.WriteString(t.Path.GetOrigin())
+		sb.WriteString(":")
+	}
+	for i, pe := range t.Path.GetElem() {
+		sb.WriteString("/")
+		sb.WriteString(pe.GetName())
+		for _, k := range t.keys[i] {
+			v, ok := pe.GetKey()[k]
+			if !ok {
+				continue
+			}
+			if name, ok := IsPlaceholder(v); ok && typed {
+				v = "<" + TypeString(types[name]) + ">"
+			}
+			sb.WriteString("[")
+			sb.WriteString(k)
+			sb.WriteString("=")
+			sb.WriteString(v)
+			sb.WriteString("]")
+		}
+	}
+	return sb.String()
+}
+
+// TypeString returns a compact description of a leaf type and its restrictions,
+// e.g: uint32(range=0..9999) or enumeration(up|down).
+func TypeString(t *sdcpb.SchemaLeafType) string {
+	if t == nil {
+		return ""
+	}
+	if t.GetType() == "union" {
+		members := make([]string, 0, len(t.GetUnionTypes()))
+		for _, ut := range t.GetUnionTypes() {
+			members = append(members, TypeString(ut))
+		}
+		return strings.Join(members, " | ")
+	}
+	rs := make([]string, 0)
+	if t.GetLength() != "" {
+		rs = append(rs, "length="+t.GetLength())
+	}
+	if t.GetRange() != "" {
+		rs = append(rs, "range="+t.GetRange())
+	}
+	for _, p := range t.GetPatterns() {
+		if p.GetInverted() {
+			rs = append(rs, "!pattern="+p.GetPattern())
+			continue
+		}
+		rs = append(rs, "pattern="+p.GetPattern())
+	}
+	switch t.GetType() {
+	case "enumeration":
+		rs = append(rs, strings.Join(t.GetValues(), "|"))
+	case "leafref":
+		rs = append(rs, "path="+t.GetLeafref())
+	}
+	if len(rs) == 0 {
+		return t.GetType()
+	}
+	return t.GetType() + "(" + strings.Join(rs, ",") + ")"
+}
+
+func declaredKeys(keys []*sdcpb.LeafSchema) []string {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.GetName())
+	}
+	return names
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/memstore"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const testModule = `module t {
+  namespace "urn:t";
+  prefix t;
+  container c {
+    list l {
+      key "name";
+      leaf name { type string; }
+      list s {
+        key "id";
+        leaf id { type uint32; }
+        leaf v { type string; }
+      }
+    }
+    leaf-list ll { type uint8; }
+  }
+}
+`
+
+// testResolver returns a resolver of a schema made of module m.
+func testResolver(t *testing.T, m string) *store.Resolver {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "t.yang"), []byte(m), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := schema.NewSchema(&config.SchemaConfig{Name: "t", Vendor: "v", Version: "1", Files: []string{dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := memstore.New()
+	if err = st.AddSchema(sc); err != nil {
+		t.Fatal(err)
+	}
+	return store.NewResolver(st, &sdcpb.Schema{Name: "t", Vendor: "v", Version: "1"})
+}
+
+func TestParseTemplate(t *testing.T) {
+	r := testResolver(t, testModule)
+	tests := []struct {
+		path         string
+		placeholders []string
+		code         codes.Code
+	}{
+		{path: "/c/l[name={n}]/s[id={i}]/v", placeholders: []string{"n", "i"}},
+		{path: "/c/l[name=a]/s[id={i}]", placeholders: []string{"i"}},
+		{path: "/c/l[nmae={n}]", code: codes.InvalidArgument},
+		{path: "/c/l[name={n}][other=x]", code: codes.InvalidArgument},
+		{path: "/c/l[name={x}]/s[id={x}]", code: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := utils.ParsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tpl, err := ParseTemplate(context.Background(), r, p)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if err != nil {
+				return
+			}
+			if len(tpl.Placeholders) != len(tt.placeholders) {
+				t.Fatalf("got %d placeholders, want %v", len(tpl.Placeholders), tt.placeholders)
+			}
+			for i, ph := range tpl.Placeholders {
+				if ph.Name != tt.placeholders[i] {
+					t.Errorf("placeholder %d: got %q, want %q", i, ph.Name, tt.placeholders[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// AddUpdate sets the value of upd at its path in the document.
+// JSON values are merged at the path, a nil value creates a container,
+// a list entry or sets an empty leaf.
+func (b *Builder) AddUpdate(ctx context.Context, upd *sdcpb.Update) error {
+	switch v := upd.GetValue().GetValue().(type) {
+	case nil:
+		return b.Add(ctx, upd.GetPath())
+	case *sdcpb.TypedValue_JsonVal:
+		return b.MergeAt(ctx, upd.GetPath(), v.JsonVal)
+	case *sdcpb.TypedValue_JsonIetfVal:
+		return b.MergeAt(ctx, upd.GetPath(), v.JsonIetfVal)
+	case *sdcpb.TypedValue_BoolVal:
+		// an empty leaf is flattened with a true value
+		if se, err := b.resolver.GetPath(ctx, upd.GetPath()); v.BoolVal && err == nil && se.GetField().GetType().GetType() == "empty" {
+			return b.Add(ctx, upd.GetPath())
+		}
+	}
+	raws, err := typedValueRaw(upd.GetValue())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "path %s: %v", utils.ToXPath(upd.GetPath(), false), err)
+	}
+	return b.Add(ctx, upd.GetPath(), raws...)
+}
+
+// typedValueRaw returns the raw string form of the values of tv.
+func typedValueRaw(tv *sdcpb.TypedValue) ([]string, error) {
+	switch v := tv.GetValue().(type) {
+	case *sdcpb.TypedValue_StringVal:
+		return []string{v.StringVal}, nil
+	case *sdcpb.TypedValue_AsciiVal:
+		return []string{v.AsciiVal}, nil
+	case *sdcpb.TypedValue_IntVal:
+		return []string{strconv.FormatInt(v.IntVal, 10)}, nil
+	case *sdcpb.TypedValue_UintVal:
+		return []string{strconv.FormatUint(v.UintVal, 10)}, nil
+	case *sdcpb.TypedValue_BoolVal:
+		return []string{strconv.FormatBool(v.BoolVal)}, nil
+	case *sdcpb.TypedValue_BytesVal:
+		return []string{base64.StdEncoding.EncodeToString(v.BytesVal)}, nil
+	case *sdcpb.TypedValue_FloatVal:
+		return []string{strconv.FormatFloat(float64(v.FloatVal), 'f', -1, 32)}, nil
+	case *sdcpb.TypedValue_DoubleVal:
+		return []string{strconv.FormatFloat(v.DoubleVal, 'f', -1, 64)}, nil
+	case *sdcpb.TypedValue_DecimalVal:
+		return []string{formatDecimal64(v.DecimalVal)}, nil
+	case *sdcpb.TypedValue_LeaflistVal:
+		rs := make([]string, 0, len(v.LeaflistVal.GetElement()))
+		for _, e := range v.LeaflistVal.GetElement() {
+			if _, ok := e.GetValue().(*sdcpb.TypedValue_LeaflistVal); ok {
+				return nil, fmt.Errorf("nested leaf-list values are not supported")
+			}
+			r, err := typedValueRaw(e)
+			if err != nil {
+				return nil, err
+			}
+			rs = append(rs, r...)
+		}
+		return rs, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", tv.GetValue())
+}
+
+func formatDecimal64(d *sdcpb.Decimal64) string {
+	s := strconv.FormatInt(d.GetDigits(), 10)
+	if d.GetPrecision() == 0 {
+		return s
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	p := int(d.GetPrecision())
+	if len(s) <= p {
+		s = strings.Repeat("0", p-len(s)+1) + s
+	}
+	s = s[:len(s)-p] + "." + s[len(s)-p:]
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// Flatten returns the leaves of the document as updates. A leaf-list is a single update
+// with a leaflist value and an empty leaf has a true boolean value. List keys are part
+// of the entry path, they are only reported as leaves for entries without other members.
+// Empty containers are reported with an empty JSON object value.
+func (b *Builder) Flatten(ctx context.Context) ([]*sdcpb.Update, error) {
+	upds := make([]*sdcpb.Update, 0)
+	err := b.flatten(ctx, nil, b.root, &upds)
+	if err != nil {
+		return nil, err
+	}
+	return upds, nil
+}
+
+func (b *Builder) flatten(ctx context.Context, p []*sdcpb.PathElem, n *node, upds *[]*sdcpb.Update) error {
+	members := 0
+	for _, c := range n.children {
+		if c.isKey {
+			continue
+		}
+		members++
+		switch c.kind {
+		case objectNode:
+			err := b.flatten(ctx, appendElem(p, &sdcpb.PathElem{Name: c.name}), c, upds)
+			if err != nil {
+				return err
+			}
+		case listNode:
+			for _, e := range c.entries {
+				pe := &sdcpb.PathElem{Name: c.name, Key: make(map[string]string, len(e.keyValues))}
+				for k, v := range e.keyValues {
+					pe.Key[k] = v
+				}
+				err := b.flatten(ctx, appendElem(p, pe), e, upds)
+				if err != nil {
+					return err
+				}
+			}
+		case leafNode:
+			err := b.flattenLeaf(ctx, p, c, upds)
+			if err != nil {
+				return err
+			}
+		case leafListNode:
+			sa := &sdcpb.ScalarArray{Element: make([]*sdcpb.TypedValue, 0, len(c.values))}
+			for _, v := range c.values {
+				tv, err := b.typedValue(ctx, c.typ, rawValue(v))
+				if err != nil {
+					return valueError(p, c, err)
+				}
+				sa.Element = append(sa.Element, tv)
+			}
+			*upds = append(*upds, &sdcpb.Update{
+				Path:  &sdcpb.Path{Elem: appendElem(p, &sdcpb.PathElem{Name: c.name})},
+				Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_LeaflistVal{LeaflistVal: sa}},
+			})
+		}
+	}
+	if members > 0 || len(p) == 0 {
+		return nil
+	}
+	if n.keyValues == nil {
+		*upds = append(*upds, &sdcpb.Update{
+			Path:  &sdcpb.Path{Elem: p},
+			Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte("{}")}},
+		})
+		return nil
+	}
+	for _, c := range n.children {
+		if c.isKey {
+			err := b.flattenLeaf(ctx, p, c, upds)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) flattenLeaf(ctx context.Context, p []*sdcpb.PathElem, n *node, upds *[]*sdcpb.Update) error {
+	tv, err := b.typedValue(ctx, n.typ, rawValue(n.value))
+	if err != nil {
+		return valueError(p, n, err)
+	}
+	*upds = append(*upds, &sdcpb.Update{
+		Path:  &sdcpb.Path{Elem: appendElem(p, &sdcpb.PathElem{Name: n.name})},
+		Value: tv,
+	})
+	return nil
+}
+
+// ValueError is returned when a value of a document does not match the type of its leaf,
+// e.g. a binary value that is not base64 encoded.
+type ValueError struct {
+	// xpath of the leaf
+	Path string
+	Err  error
+}
+
+func valueError(p []*sdcpb.PathElem, n *node, err error) *ValueError {
+	return &ValueError{
+		Path: utils.ToXPath(&sdcpb.Path{Elem: appendElem(p, &sdcpb.PathElem{Name: n.name})}, false),
+		Err:  err,
+	}
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("path %s: %v", e.Path, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus makes the error convertible to an InvalidArgument gRPC status
+// carrying the leaf path as ErrorInfo details.
+func (e *ValueError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	std, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   api.ReasonInvalidValue,
+		Domain:   schema.ErrorDomain,
+		Metadata: map[string]string{"path": e.Path},
+	})
+	if err != nil {
+		return st
+	}
+	return std
+}
+
+func appendElem(p []*sdcpb.PathElem, pe *sdcpb.PathElem) []*sdcpb.PathElem {
+	np := make([]*sdcpb.PathElem, 0, len(p)+1)
+	np = append(np, p...)
+	return append(np, pe)
+}
+
+// typedValue returns the TypedValue of the raw value v of type t.
+func (b *Builder) typedValue(ctx context.Context, t *sdcpb.SchemaLeafType, v string) (*sdcpb.TypedValue, error) {
+	return TypedValue(t, v, func(lt *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+		return b.leafrefType(ctx, lt.GetLeafref())
+	})
+}
+
+// TypedValue returns the TypedValue of the raw value v of YANG type t,
+// leafref types are resolved using fn if not nil, encoded as strings otherwise.
+func TypedValue(t *sdcpb.SchemaLeafType, v string, fn func(*sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType) (*sdcpb.TypedValue, error) {
+	switch t.GetType() {
+	case "int8", "int16", "int32", "int64":
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_IntVal{IntVal: i}}, nil
+	case "uint8", "uint16", "uint32", "uint64":
+		i, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_UintVal{UintVal: i}}, nil
+	case "boolean":
+		bv, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, err
+		}
+		return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_BoolVal{BoolVal: bv}}, nil
+	case "empty":
+		return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_BoolVal{BoolVal: true}}, nil
+	case "decimal64":
+		d, err := parseDecimal64(v)
+		if err != nil {
+			return nil, err
+		}
+		return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_DecimalVal{DecimalVal: d}}, nil
+	case "binary":
+		bs, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_BytesVal{BytesVal: bs}}, nil
+	case "union":
+		for _, ut := range t.GetUnionTypes() {
+			if _, err := encodeValue(ut, v, fn, nil); err == nil {
+				return TypedValue(ut, v, fn)
+			}
+		}
+	case "leafref":
+		if fn != nil {
+			if rt := fn(t); rt != nil {
+				return TypedValue(rt, v, fn)
+			}
+		}
+	}
+	return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: v}}, nil
+}
+
+func parseDecimal64(v string) (*sdcpb.Decimal64, error) {
+	s := v
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	var precision uint32
+	if idx := strings.Index(s, "."); idx >= 0 {
+		precision = uint32(len(s) - idx - 1)
+		s = s[:idx] + s[idx+1:]
+	}
+	digits, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid decimal64 value %q", v)
+	}
+	if neg {
+		digits = -digits
+	}
+	return &sdcpb.Decimal64{Digits: digits, Precision: precision}, nil
+}
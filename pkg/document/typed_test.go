@@ -0,0 +1,83 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const typedModule = `module t {
+  namespace "urn:t";
+  prefix t;
+  list l {
+    key "name";
+    leaf name { type string; }
+    leaf b { type binary; }
+    leaf-list bl { type binary; }
+  }
+}
+`
+
+func TestBuilder_Flatten(t *testing.T) {
+	r := testResolver(t, typedModule)
+	tests := []struct {
+		path  string
+		value string
+		code  codes.Code
+		xpath string
+	}{
+		{path: "/l[name=a]/b", value: "AQI="},
+		{path: "/l[name=a]/b", value: "not base64", code: codes.InvalidArgument, xpath: "t:l[name=a]/b"},
+		{path: "/l[name=a]/bl", value: "not base64", code: codes.InvalidArgument, xpath: "t:l[name=a]/bl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"="+tt.value, func(t *testing.T) {
+			ctx := context.Background()
+			p, err := utils.ParsePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := NewBuilder(r)
+			if err = b.Add(ctx, p, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			_, err = b.Flatten(ctx)
+			st := status.Convert(err)
+			if st.Code() != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if err == nil {
+				return
+			}
+			var ei *errdetails.ErrorInfo
+			for _, d := range st.Details() {
+				if d, ok := d.(*errdetails.ErrorInfo); ok {
+					ei = d
+				}
+			}
+			if ei.GetReason() != api.ReasonInvalidValue || ei.GetMetadata()["path"] != tt.xpath {
+				t.Errorf("got error info %v, want reason %s and path %s", ei, api.ReasonInvalidValue, tt.xpath)
+			}
+		})
+	}
+}
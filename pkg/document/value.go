@@ -0,0 +1,191 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// EncodeValue returns the RFC 7951 JSON representation of the raw value v of YANG type t.
+// Leafrefs are encoded as strings, use a Builder to encode them using the type of their target.
+// Identityref values are only checked to be derived from their base, use a Builder to module qualify them.
+func EncodeValue(t *sdcpb.SchemaLeafType, v string) (interface{}, error) {
+	return encodeValue(t, v, nil, nil)
+}
+
+// encodeValue encodes v according to t,
+// leafref types are resolved using fn and identityref values qualified using id, if not nil.
+func encodeValue(t *sdcpb.SchemaLeafType, v string, fn func(*sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType,
+	id func(*sdcpb.SchemaLeafType, string) (string, error)) (interface{}, error) {
+	switch t.GetType() {
+	case "int8", "int16", "int32":
+		i, err := strconv.ParseInt(v, 10, bitSize(t.GetType()))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", t.GetType(), v)
+		}
+		return json.Number(strconv.FormatInt(i, 10)), nil
+	case "uint8", "uint16", "uint32":
+		i, err := strconv.ParseUint(v, 10, bitSize(t.GetType()))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", t.GetType(), v)
+		}
+		return json.Number(strconv.FormatUint(i, 10)), nil
+	// 64 bit numbers are encoded as strings
+	case "int64":
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", t.GetType(), v)
+		}
+		return strconv.FormatInt(i, 10), nil
+	case "uint64":
+		i, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", t.GetType(), v)
+		}
+		return strconv.FormatUint(i, 10), nil
+	case "decimal64":
+		return canonicalDecimal(t, v)
+	case "boolean":
+		switch v {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid %s value %q", t.GetType(), v)
+	case "empty":
+		if v != "" {
+			return nil, fmt.Errorf("invalid %s value %q", t.GetType(), v)
+		}
+		return []interface{}{nil}, nil
+	case "enumeration":
+		for _, ev := range t.GetValues() {
+			if ev == v {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid %s value %q, expecting one of %v", t.GetType(), v, t.GetValues())
+	case "union":
+		for _, ut := range t.GetUnionTypes() {
+			rv, err := encodeValue(ut, v, fn, id)
+			if err == nil {
+				return rv, nil
+			}
+		}
+		return nil, fmt.Errorf("value %q does not match any of the union types", v)
+	case "leafref":
+		if fn != nil {
+			if rt := fn(t); rt != nil {
+				return encodeValue(rt, v, fn, id)
+			}
+		}
+		return v, nil
+	case "identityref":
+		if !derivedIdentity(t, v) {
+			return nil, fmt.Errorf("invalid %s value %q, expecting an identity derived from its base", t.GetType(), v)
+		}
+		if id != nil {
+			return id(t, v)
+		}
+		return v, nil
+	}
+	// string, binary, bits and instance-identifier.
+	return v, nil
+}
+
+// derivedIdentity reports whether the identityref value v, qualified or not,
+// names one of the identities derived from the base of t, listed by t.
+func derivedIdentity(t *sdcpb.SchemaLeafType, v string) bool {
+	if idx := strings.Index(v, ":"); idx >= 0 {
+		v = v[idx+1:]
+	}
+	for _, name := range t.GetValues() {
+		if name == v {
+			return true
+		}
+	}
+	return false
+}
+
+// rawValue returns the raw string form of an encoded value.
+func rawValue(ev interface{}) string {
+	switch ev := ev.(type) {
+	case string:
+		return ev
+	case json.Number:
+		return ev.String()
+	case bool:
+		return strconv.FormatBool(ev)
+	case []interface{}:
+		// empty
+		return ""
+	}
+	return fmt.Sprint(ev)
+}
+
+func bitSize(typ string) int {
+	switch typ {
+	case "int8", "uint8":
+		return 8
+	case "int16", "uint16":
+		return 16
+	}
+	return 32
+}
+
+var decimalParts = regexp.MustCompile(`^([-+]?)([0-9]+)(?:\.([0-9]+))?$`)
+
+// canonicalDecimal returns the RFC 7950 canonical form of the decimal64 value v,
+// e.g. 1.5 for +01.50, with one fraction digit at least.
+// v cannot have more fraction digits than t.
+func canonicalDecimal(t *sdcpb.SchemaLeafType, v string) (string, error) {
+	m := decimalParts.FindStringSubmatch(v)
+	if m == nil {
+		return "", fmt.Errorf("invalid %s value %q", t.GetType(), v)
+	}
+	if fd := fractionDigits(t); fd > 0 && len(m[3]) > fd {
+		return "", fmt.Errorf("invalid %s value %q, expecting %d fraction digits at most", t.GetType(), v, fd)
+	}
+	i := strings.TrimLeft(m[2], "0")
+	if i == "" {
+		i = "0"
+	}
+	f := strings.TrimRight(m[3], "0")
+	if f == "" {
+		f = "0"
+	}
+	if m[1] == "-" && (i != "0" || f != "0") {
+		return "-" + i + "." + f, nil
+	}
+	return i + "." + f, nil
+}
+
+// fractionDigits returns the fraction-digits of the decimal64 type t,
+// given by the bounds of its range, 0 if unknown.
+func fractionDigits(t *sdcpb.SchemaLeafType) int {
+	bound, _, _ := strings.Cut(t.GetRange(), "|")
+	bound, _, _ = strings.Cut(bound, "..")
+	_, f, ok := strings.Cut(strings.TrimSpace(bound), ".")
+	if !ok {
+		return 0
+	}
+	return len(f)
+}
@@ -0,0 +1,325 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const netconfBaseNamespace = "urn:ietf:params:xml:ns:netconf:base:1.0"
+
+// XML returns the RFC 7950 XML encoding of the document,
+// wrapped in a NETCONF config element.
+func (b *Builder) XML(ctx context.Context) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<config xmlns="` + netconfBaseNamespace + `">`)
+	for _, c := range b.root.children {
+		err := b.encodeXML(ctx, buf, c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("</config>")
+	return buf.Bytes(), nil
+}
+
+func (b *Builder) encodeXML(ctx context.Context, buf *bytes.Buffer, n *node) error {
+	switch n.kind {
+	case listNode:
+		for _, e := range n.entries {
+			err := b.element(ctx, buf, n, "", func() error {
+				for _, c := range e.children {
+					if err := b.encodeXML(ctx, buf, c); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	case objectNode:
+		return b.element(ctx, buf, n, "", func() error {
+			for _, c := range n.children {
+				if err := b.encodeXML(ctx, buf, c); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case leafNode:
+		return b.leafElement(ctx, buf, n, n.value)
+	case leafListNode:
+		for _, v := range n.values {
+			if err := b.leafElement(ctx, buf, n, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) leafElement(ctx context.Context, buf *bytes.Buffer, n *node, v interface{}) error {
+	if _, ok := v.([]interface{}); ok {
+		// empty
+		return b.element(ctx, buf, n, "", nil)
+	}
+	raw := rawValue(v)
+	var attr string
+	// a module qualified identity needs its module namespace declared
+	if idx := strings.Index(raw, ":"); isIdentityref(n.typ) && idx > 0 {
+		ns, err := b.resolver.ModuleNamespace(ctx, raw[:idx])
+		if err == nil && ns != "" {
+			attr = ` xmlns:` + raw[:idx] + `="` + escapeXML(ns) + `"`
+		}
+	}
+	return b.element(ctx, buf, n, attr, func() error {
+		return xml.EscapeText(buf, []byte(raw))
+	})
+}
+
+// element writes an element for node n with the attributes attr, its content is written by fn.
+// The element declares its module namespace if it is not the one of its parent.
+func (b *Builder) element(ctx context.Context, buf *bytes.Buffer, n *node, attr string, fn func() error) error {
+	name := n.name
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		name = name[idx+1:]
+		ns, err := b.resolver.ModuleNamespace(ctx, n.module)
+		if err != nil {
+			return err
+		}
+		attr = ` xmlns="` + escapeXML(ns) + `"` + attr
+	}
+	if fn == nil {
+		buf.WriteString("<" + name + attr + "/>")
+		return nil
+	}
+	buf.WriteString("<" + name + attr + ">")
+	if err := fn(); err != nil {
+		return err
+	}
+	buf.WriteString("</" + name + ">")
+	return nil
+}
+
+func escapeXML(s string) string {
+	buf := new(bytes.Buffer)
+	_ = xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
+
+// xmlElement is an element of an XML document.
+type xmlElement struct {
+	name xml.Name
+	// namespaces of the prefixes in scope
+	prefixes map[string]string
+	text     string
+	children []*xmlElement
+}
+
+// MergeXML decodes the RFC 7950 XML document data and merges it into the document,
+// data being a top level node or a NETCONF config or data element wrapping them.
+// The module qualified identityref values are converted to their JSON form,
+// prefixed with the module of their namespace.
+func (b *Builder) MergeXML(ctx context.Context, data []byte) error {
+	root, err := decodeXMLTree(data)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid XML document: %v", err)
+	}
+	es := []*xmlElement{root}
+	if root.name.Space == netconfBaseNamespace && (root.name.Local == "config" || root.name.Local == "data") {
+		es = root.children
+	}
+	return b.mergeXML(ctx, b.root, nil, es)
+}
+
+func decodeXMLTree(data []byte) (*xmlElement, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlElement
+	var root *xmlElement
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			e := &xmlElement{name: tok.Name}
+			if len(stack) > 0 {
+				p := stack[len(stack)-1]
+				p.children = append(p.children, e)
+				e.prefixes = p.prefixes
+			} else if root != nil {
+				return nil, errors.New("more than one root element")
+			} else {
+				root = e
+			}
+			for _, a := range tok.Attr {
+				if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+					continue
+				}
+				prefixes := make(map[string]string, len(e.prefixes)+1)
+				for k, v := range e.prefixes {
+					prefixes[k] = v
+				}
+				if a.Name.Space == "" {
+					prefixes[""] = a.Value
+				} else {
+					prefixes[a.Name.Local] = a.Value
+				}
+				e.prefixes = prefixes
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(tok)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+	return root, nil
+}
+
+// mergeXML merges the elements es, the children of node n found at the schema names.
+func (b *Builder) mergeXML(ctx context.Context, n *node, names []string, es []*xmlElement) error {
+	for _, e := range es {
+		module, err := b.resolver.NamespaceModule(ctx, e.name.Space)
+		if err != nil {
+			return err
+		}
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		if module == "" {
+			cnames = append(cnames, e.name.Local)
+			return invalidMember(cnames, fmt.Sprintf("unknown namespace %q", e.name.Space))
+		}
+		// as a JSON member name, qualified if not in the module of its parent
+		if module != n.module {
+			cnames = append(cnames, module+":"+e.name.Local)
+		} else {
+			cnames = append(cnames, e.name.Local)
+		}
+		se, name, module, err := b.resolve(ctx, n, cnames)
+		if err != nil {
+			return err
+		}
+		switch se := se.GetSchema().(type) {
+		case *sdcpb.SchemaElem_Container:
+			if len(se.Container.GetKeys()) == 0 {
+				err = b.mergeXML(ctx, n.child(name, module, objectNode), cnames, e.children)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			kv := make(map[string]string, len(se.Container.GetKeys()))
+			rest := make([]*xmlElement, 0, len(e.children))
+			for _, c := range e.children {
+				if c.name.Space == e.name.Space && len(c.children) == 0 && keyName(se.Container, c.name.Local) {
+					kv[c.name.Local] = c.text
+					continue
+				}
+				rest = append(rest, c)
+			}
+			for _, k := range se.Container.GetKeys() {
+				if _, ok := kv[k.GetName()]; !ok {
+					return invalidMember(cnames, fmt.Sprintf("list entry missing key %q", k.GetName()))
+				}
+			}
+			entry, err := b.entry(ctx, b.list(n, name, module, se.Container), se.Container, e.name.Local, kv)
+			if err != nil {
+				return err
+			}
+			err = b.mergeXML(ctx, entry, cnames, rest)
+			if err != nil {
+				return err
+			}
+		case *sdcpb.SchemaElem_Field:
+			ev, err := b.xmlValue(ctx, cnames, module, se.Field.GetType(), e)
+			if err != nil {
+				return err
+			}
+			b.leaf(n, name, module, se.Field.GetType()).value = ev
+		case *sdcpb.SchemaElem_Leaflist:
+			ev, err := b.xmlValue(ctx, cnames, module, se.Leaflist.GetType(), e)
+			if err != nil {
+				return err
+			}
+			b.leafList(n, name, module, se.Leaflist).addValue(ev)
+		}
+	}
+	return nil
+}
+
+func keyName(cs *sdcpb.ContainerSchema, name string) bool {
+	for _, k := range cs.GetKeys() {
+		if k.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// xmlValue returns the encoded value of the leaf or leaf-list element e of type t,
+// defined in module. A prefixed identityref value is given the module of the
+// namespace of its prefix, an unprefixed one the module of the default namespace
+// if it is not the one of the node.
+func (b *Builder) xmlValue(ctx context.Context, names []string, module string, t *sdcpb.SchemaLeafType, e *xmlElement) (interface{}, error) {
+	if len(e.children) > 0 {
+		return nil, invalidMember(names, "expecting a value, got elements")
+	}
+	raw := e.text
+	if isIdentityref(t) {
+		prefix, ident := "", strings.TrimSpace(raw)
+		if idx := strings.Index(ident, ":"); idx >= 0 {
+			prefix, ident = ident[:idx], ident[idx+1:]
+		}
+		if ns, ok := e.prefixes[prefix]; ok {
+			im, err := b.resolver.NamespaceModule(ctx, ns)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case im != "" && im != module:
+				raw = im + ":" + ident
+			case im != "" || prefix == "":
+				raw = ident
+			}
+		}
+	}
+	ev, err := b.encode(ctx, t, module, raw)
+	if err != nil {
+		return nil, invalidMember(names, err.Error())
+	}
+	return ev, nil
+}
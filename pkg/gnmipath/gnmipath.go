@@ -0,0 +1,221 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gnmipath translates gNMI paths to the paths of the schema-server API
+// and back, validating them against a schema.
+package gnmipath
+
+import (
+	"context"
+	"strings"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// Qualification modes of the element names of the gNMI paths returned by FromSchemaPath.
+const (
+	// no element is qualified
+	QualifyNone = ""
+	// every element is qualified with the name of its module
+	QualifyModule = "module"
+	// the first element and the ones defined in a module other than
+	// their parent's are qualified, as the RFC 7951 member names
+	QualifyRFC7951 = "rfc7951"
+)
+
+// ToSchemaPath returns the schema path of the gNMI path p appended to prefix,
+// either can be nil, along with its xpath with keys in the schema declared order.
+// An element name can be qualified with the name or the prefix of its module and
+// an origin naming a module qualifies the first element, other origins are kept.
+// The returned path has no module qualifiers and its keys are in canonical form.
+func ToSchemaPath(ctx context.Context, r *store.Resolver, prefix, p *gnmipb.Path) (*sdcpb.Path, string, error) {
+	if len(prefix.GetElement()) > 0 || len(p.GetElement()) > 0 {
+		return nil, "", status.Error(codes.InvalidArgument, "the deprecated element field is not supported, use elem")
+	}
+	origin := prefix.GetOrigin()
+	if p.GetOrigin() != "" {
+		if origin != "" && origin != p.GetOrigin() {
+			return nil, "", status.Errorf(codes.InvalidArgument, "path origin %q differs from the prefix one %q", p.GetOrigin(), origin)
+		}
+		origin = p.GetOrigin()
+	}
+	target := prefix.GetTarget()
+	if target == "" {
+		target = p.GetTarget()
+	}
+	elems := make([]*gnmipb.PathElem, 0, len(prefix.GetElem())+len(p.GetElem()))
+	elems = append(elems, prefix.GetElem()...)
+	elems = append(elems, p.GetElem()...)
+
+	m := &modules{r: r}
+	sp := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(elems))}
+	originModule := ""
+	if origin != "" {
+		ok, err := m.isModule(ctx, origin)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			originModule = origin
+		} else {
+			sp.Origin = origin
+		}
+	}
+	names := make([]string, 0, len(elems))
+	for i, pe := range elems {
+		name := pe.GetName()
+		module := ""
+		if q, n, ok := strings.Cut(name, ":"); ok {
+			var err error
+			module, err = m.module(ctx, q)
+			if err != nil {
+				return nil, "", err
+			}
+			if module == "" {
+				return nil, "", status.Errorf(codes.InvalidArgument, "%q: unknown module or prefix %q", name, q)
+			}
+			name = n
+		}
+		if i == 0 && originModule != "" {
+			if module != "" && module != originModule {
+				return nil, "", status.Errorf(codes.InvalidArgument, "%q: module %q differs from the origin %q", pe.GetName(), module, originModule)
+			}
+			module = originModule
+		}
+		// the store resolves the module of the first element only
+		if i == 0 && module != "" {
+			names = append(names, module+":"+name)
+		} else {
+			names = append(names, name)
+		}
+		se, err := r.Get(ctx, names)
+		if err != nil {
+			return nil, "", err
+		}
+		if module != "" {
+			sm, err := r.Module(ctx, se)
+			if err != nil {
+				return nil, "", err
+			}
+			if sm != module {
+				return nil, "", status.Errorf(codes.InvalidArgument, "%q: defined in module %q, not %q", pe.GetName(), sm, module)
+			}
+		}
+		sp.Elem = append(sp.Elem, &sdcpb.PathElem{Name: names[i], Key: pe.GetKey()})
+	}
+	cp, xp, err := document.CanonicalPath(ctx, r, sp)
+	if err != nil {
+		return nil, "", err
+	}
+	cp.Target = target
+	return cp, xp, nil
+}
+
+// FromSchemaPath returns the gNMI path of the schema path p, validated against
+// the schema, with its element names qualified according to qualification and
+// its keys in canonical form. The origin and target of p are kept.
+func FromSchemaPath(ctx context.Context, r *store.Resolver, p *sdcpb.Path, qualification string) (*gnmipb.Path, error) {
+	switch qualification {
+	case QualifyNone, QualifyModule, QualifyRFC7951:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown qualification %q", qualification)
+	}
+	cp, _, err := document.CanonicalPath(ctx, r, p)
+	if err != nil {
+		return nil, err
+	}
+	gp := &gnmipb.Path{
+		Origin: p.GetOrigin(),
+		Target: p.GetTarget(),
+		Elem:   make([]*gnmipb.PathElem, 0, len(cp.GetElem())),
+	}
+	parentModule := ""
+	names := make([]string, 0, len(p.GetElem()))
+	for i, pe := range cp.GetElem() {
+		names = append(names, p.GetElem()[i].GetName())
+		se, err := r.Get(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		module, err := r.Module(ctx, se)
+		if err != nil {
+			return nil, err
+		}
+		name := pe.GetName()
+		switch {
+		case module == "":
+		case qualification == QualifyModule,
+			qualification == QualifyRFC7951 && module != parentModule:
+			name = module + ":" + name
+		}
+		parentModule = module
+		gp.Elem = append(gp.Elem, &gnmipb.PathElem{Name: name, Key: pe.GetKey()})
+	}
+	return gp, nil
+}
+
+// modules maps the names and the prefixes of the modules of a schema
+// to the module names, loaded on first use.
+type modules struct {
+	r     *store.Resolver
+	names map[string]string
+}
+
+func (m *modules) load(ctx context.Context) error {
+	if m.names != nil {
+		return nil
+	}
+	root, err := m.r.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	children := root.GetContainer().GetChildren()
+	names := make(map[string]string, 2*len(children))
+	for _, name := range children {
+		names[name] = name
+	}
+	// a module name takes precedence over a prefix of another module
+	for _, name := range children {
+		mse, err := m.r.Get(ctx, []string{name})
+		if err != nil {
+			return err
+		}
+		if prefix := mse.GetContainer().GetPrefix(); prefix != "" {
+			if _, ok := names[prefix]; !ok {
+				names[prefix] = name
+			}
+		}
+	}
+	m.names = names
+	return nil
+}
+
+// module returns the name of the module named or prefixed q, empty if none is.
+func (m *modules) module(ctx context.Context, q string) (string, error) {
+	if err := m.load(ctx); err != nil {
+		return "", err
+	}
+	return m.names[q], nil
+}
+
+func (m *modules) isModule(ctx context.Context, name string) (bool, error) {
+	module, err := m.module(ctx, name)
+	return module == name && module != "", err
+}
@@ -0,0 +1,305 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks the modules of a schema against model quality rules:
+// naming conventions, missing descriptions, enumeration value gaps
+// and the OpenConfig style rules.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// Diagnostic is a model quality issue found by a rule.
+type Diagnostic struct {
+	Rule     string `json:"rule,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	// schema path of the node, module qualified at the top level,
+	// empty for a module statement.
+	Path   string `json:"path,omitempty"`
+	Module string `json:"module,omitempty"`
+	// position of the statement, as file:line:column
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Report lists the diagnostics of a schema sorted by module and path.
+type Report struct {
+	Diagnostics []*Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// Counts returns the number of diagnostics by severity.
+func (r *Report) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range r.Diagnostics {
+		counts[d.Severity]++
+	}
+	return counts
+}
+
+// ReportFunc reports an issue found on statement n,
+// the node or module being checked if n is nil.
+type ReportFunc func(n yang.Node, format string, args ...interface{})
+
+// Rule checks the modules and the schema tree of a schema.
+type Rule interface {
+	Name() string
+	Description() string
+	// severity of the diagnostics unless configured otherwise
+	DefaultSeverity() string
+	// CheckModule is called once for each module and submodule.
+	CheckModule(m *yang.Module, report ReportFunc)
+	// CheckEntry is called for each node of the schema tree,
+	// choices and cases included.
+	CheckEntry(e *yang.Entry, report ReportFunc)
+}
+
+var (
+	m sync.RWMutex
+	// rules by name, the built-in rules and the plugin ones
+	registry = make(map[string]Rule)
+	// plugin file by rule name
+	sources = make(map[string]string)
+)
+
+func init() {
+	for _, r := range builtinRules {
+		if err := register(r, ""); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register adds a rule to the available ones, see LoadPlugins.
+// Its name must be unique.
+func Register(r Rule) error {
+	return register(r, "")
+}
+
+func register(r Rule, source string) error {
+	name := r.Name()
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("lint: invalid rule name %q", name)
+	}
+	switch r.DefaultSeverity() {
+	case config.LintSeverityError, config.LintSeverityWarning, config.LintSeverityInfo:
+	default:
+		return fmt.Errorf("lint: rule %q: unknown severity %q", name, r.DefaultSeverity())
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("lint: rule %q is already registered", name)
+	}
+	registry[name] = r
+	if source != "" {
+		sources[name] = source
+	}
+	return nil
+}
+
+// Rules returns the available rules sorted by name.
+func Rules() []Rule {
+	m.RLock()
+	defer m.RUnlock()
+	rs := make([]Rule, 0, len(registry))
+	for _, r := range registry {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Name() < rs[j].Name()
+	})
+	return rs
+}
+
+// Source returns the plugin file rule name was loaded from,
+// empty for a built-in rule.
+func Source(name string) string {
+	m.RLock()
+	defer m.RUnlock()
+	return sources[name]
+}
+
+func lookup(name string) Rule {
+	m.RLock()
+	defer m.RUnlock()
+	return registry[name]
+}
+
+// Linter runs a set of rules.
+type Linter struct {
+	rules    []Rule
+	severity map[string]string
+}
+
+// New returns a Linter running the rules selected by cfg.
+func New(cfg *config.LintConfig) (*Linter, error) {
+	l := &Linter{severity: make(map[string]string)}
+	if len(cfg.Rules) == 0 {
+		l.rules = Rules()
+	}
+	for _, name := range cfg.Rules {
+		r := lookup(name)
+		if r == nil {
+			return nil, fmt.Errorf("lint: unknown rule %q", name)
+		}
+		l.rules = append(l.rules, r)
+	}
+	for _, name := range cfg.Disable {
+		if lookup(name) == nil {
+			return nil, fmt.Errorf("lint: unknown rule %q", name)
+		}
+		for i, r := range l.rules {
+			if r.Name() == name {
+				l.rules = append(l.rules[:i], l.rules[i+1:]...)
+				break
+			}
+		}
+	}
+	for name, sev := range cfg.Severity {
+		if lookup(name) == nil {
+			return nil, fmt.Errorf("lint: unknown rule %q", name)
+		}
+		l.severity[name] = sev
+	}
+	return l, nil
+}
+
+// Run checks modules and the schema tree under root, whose
+// children are the module entries.
+// An issue found on a statement reached through several paths,
+// e.g. in a grouping, is reported once.
+func (l *Linter) Run(modules []*yang.Module, root *yang.Entry) *Report {
+	rn := &runner{linter: l, seen: make(map[string]bool), failed: make(map[string]bool)}
+	for _, m := range modules {
+		for _, r := range l.rules {
+			rn.check(r, "", m.Name, m, func(report ReportFunc) {
+				r.CheckModule(m, report)
+			})
+		}
+	}
+	for _, me := range sortedChildren(root) {
+		for _, e := range sortedChildren(me) {
+			rn.walk(e, "/"+me.Name+":"+e.Name)
+		}
+	}
+	sort.SliceStable(rn.report.Diagnostics, func(i, j int) bool {
+		di, dj := rn.report.Diagnostics[i], rn.report.Diagnostics[j]
+		if di.Module != dj.Module {
+			return di.Module < dj.Module
+		}
+		if di.Path != dj.Path {
+			return di.Path < dj.Path
+		}
+		return di.Rule < dj.Rule
+	})
+	return &rn.report
+}
+
+type runner struct {
+	linter *Linter
+	report Report
+	// rule, location and message of the reported issues
+	seen map[string]bool
+	// rules that panicked, they are not called again
+	failed map[string]bool
+}
+
+func (rn *runner) walk(e *yang.Entry, p string) {
+	for _, r := range rn.linter.rules {
+		rn.check(r, p, moduleName(e.Node), e.Node, func(report ReportFunc) {
+			r.CheckEntry(e, report)
+		})
+	}
+	for _, c := range sortedChildren(e) {
+		rn.walk(c, p+"/"+c.Name)
+	}
+}
+
+// check calls fn with the ReportFunc of rule r, a panicking rule,
+// e.g. from a plugin, is reported and disabled for the rest of the run.
+func (rn *runner) check(r Rule, p, module string, def yang.Node, fn func(ReportFunc)) {
+	if rn.failed[r.Name()] {
+		return
+	}
+	report := rn.reportFunc(r, p, module, def)
+	defer func() {
+		if rec := recover(); rec != nil {
+			rn.failed[r.Name()] = true
+			rn.report.Diagnostics = append(rn.report.Diagnostics, &Diagnostic{
+				Rule:     r.Name(),
+				Severity: config.LintSeverityError,
+				Path:     p,
+				Module:   module,
+				Message:  fmt.Sprintf("rule failed, it is not run on the rest of the schema: %v", rec),
+			})
+		}
+	}()
+	fn(report)
+}
+
+func (rn *runner) reportFunc(r Rule, p, module string, def yang.Node) ReportFunc {
+	return func(n yang.Node, format string, args ...interface{}) {
+		if n == nil {
+			n = def
+		}
+		d := &Diagnostic{
+			Rule:     r.Name(),
+			Severity: r.DefaultSeverity(),
+			Path:     p,
+			Module:   module,
+			Message:  fmt.Sprintf(format, args...),
+		}
+		if sev, ok := rn.linter.severity[r.Name()]; ok {
+			d.Severity = sev
+		}
+		if loc := yang.Source(n); loc != "unknown" {
+			d.Location = loc
+			k := strings.Join([]string{d.Rule, d.Location, d.Message}, "\x00")
+			if rn.seen[k] {
+				return
+			}
+			rn.seen[k] = true
+		}
+		rn.report.Diagnostics = append(rn.report.Diagnostics, d)
+	}
+}
+
+func sortedChildren(e *yang.Entry) []*yang.Entry {
+	cs := make([]*yang.Entry, 0, len(e.Dir))
+	for _, c := range e.Dir {
+		cs = append(cs, c)
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].Name < cs[j].Name
+	})
+	return cs
+}
+
+func moduleName(n yang.Node) string {
+	if n == nil {
+		return ""
+	}
+	if m := yang.RootNode(n); m != nil {
+		return m.Name
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol is the function a lint plugin exports, of type func() []lint.Rule.
+// It returns the rules of the plugin, they are registered along with the built-in ones.
+//
+// A plugin is a main package built with go build -buildmode=plugin, using the Go version
+// and the module versions of the server. The server has to be built with CGO_ENABLED=1.
+const PluginSymbol = "Rules"
+
+// LoadPlugins registers the rules of the plugin files,
+// it is called once, before the schemas are loaded.
+func LoadPlugins(files []string) error {
+	for _, f := range files {
+		p, err := plugin.Open(f)
+		if err != nil {
+			return fmt.Errorf("lint plugin %s: %v", f, err)
+		}
+		sym, err := p.Lookup(PluginSymbol)
+		if err != nil {
+			return fmt.Errorf("lint plugin %s: %v", f, err)
+		}
+		fn, ok := sym.(func() []Rule)
+		if !ok {
+			return fmt.Errorf("lint plugin %s: %s is a %T, not a func() []lint.Rule", f, PluginSymbol, sym)
+		}
+		for _, r := range fn() {
+			if err := register(r, f); err != nil {
+				return fmt.Errorf("lint plugin %s: %v", f, err)
+			}
+		}
+	}
+	return nil
+}
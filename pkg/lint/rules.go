@@ -0,0 +1,249 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+var builtinRules = []Rule{
+	namingRule{},
+	descriptionRule{},
+	enumGapsRule{},
+	openconfigConfigStateRule{},
+	openconfigListKeyRule{},
+}
+
+// RFC 8407 section 4.3.1
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+type namingRule struct{}
+
+func (namingRule) Name() string { return "naming" }
+
+func (namingRule) Description() string {
+	return "module and node names are lowercase words separated by dashes"
+}
+
+func (namingRule) DefaultSeverity() string { return config.LintSeverityWarning }
+
+func (namingRule) CheckModule(m *yang.Module, report ReportFunc) {
+	if !namePattern.MatchString(m.Name) {
+		report(nil, "module name %q is not lowercase words separated by dashes", m.Name)
+	}
+}
+
+func (namingRule) CheckEntry(e *yang.Entry, report ReportFunc) {
+	if !namePattern.MatchString(e.Name) {
+		report(nil, "%s name %q is not lowercase words separated by dashes", entryKind(e), e.Name)
+	}
+}
+
+type descriptionRule struct{}
+
+func (descriptionRule) Name() string { return "description" }
+
+func (descriptionRule) Description() string {
+	return "modules, containers, lists, leaves and leaf-lists have a description"
+}
+
+func (descriptionRule) DefaultSeverity() string { return config.LintSeverityWarning }
+
+func (descriptionRule) CheckModule(m *yang.Module, report ReportFunc) {
+	if m.Description == nil || strings.TrimSpace(m.Description.Name) == "" {
+		report(nil, "module %s has no description", m.Name)
+	}
+}
+
+func (descriptionRule) CheckEntry(e *yang.Entry, report ReportFunc) {
+	if e.IsChoice() || e.IsCase() {
+		return
+	}
+	if strings.TrimSpace(e.Description) == "" {
+		report(nil, "%s %s has no description", entryKind(e), e.Name)
+	}
+}
+
+type enumGapsRule struct{}
+
+func (enumGapsRule) Name() string { return "enum-gaps" }
+
+func (enumGapsRule) Description() string {
+	return "the values assigned to the enums of an enumeration are contiguous"
+}
+
+func (enumGapsRule) DefaultSeverity() string { return config.LintSeverityInfo }
+
+func (enumGapsRule) CheckModule(*yang.Module, ReportFunc) {}
+
+func (enumGapsRule) CheckEntry(e *yang.Entry, report ReportFunc) {
+	if e.Type == nil {
+		return
+	}
+	for _, t := range enumTypes(e.Type) {
+		values := t.Enum.Values()
+		if len(values) < 2 {
+			continue
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		var gaps []string
+		for i := 1; i < len(values); i++ {
+			switch {
+			case values[i] == values[i-1]+1:
+			case values[i] == values[i-1]+2:
+				gaps = append(gaps, fmt.Sprint(values[i-1]+1))
+			default:
+				gaps = append(gaps, fmt.Sprintf("%d-%d", values[i-1]+1, values[i]-1))
+			}
+		}
+		if len(gaps) == 0 {
+			continue
+		}
+		var n yang.Node
+		if t.Base != nil {
+			n = t.Base
+		}
+		name := "enumeration"
+		if t.Name != name {
+			// typedef
+			name += " " + t.Name
+		}
+		report(n, "%s skips value(s) %s", name, strings.Join(gaps, ", "))
+	}
+}
+
+// enumTypes returns the enumerations of t, union members included.
+func enumTypes(t *yang.YangType) []*yang.YangType {
+	switch t.Kind {
+	case yang.Yenum:
+		if t.Enum != nil {
+			return []*yang.YangType{t}
+		}
+	case yang.Yunion:
+		var ts []*yang.YangType
+		for _, ut := range t.Type {
+			ts = append(ts, enumTypes(ut)...)
+		}
+		return ts
+	}
+	return nil
+}
+
+const openconfigNamespacePrefix = "http://openconfig.net/yang/"
+
+func isOpenconfig(e *yang.Entry) bool {
+	ns := e.Namespace()
+	return ns != nil && strings.HasPrefix(ns.Name, openconfigNamespacePrefix)
+}
+
+type openconfigConfigStateRule struct{}
+
+func (openconfigConfigStateRule) Name() string { return "openconfig-config-state" }
+
+func (openconfigConfigStateRule) Description() string {
+	return "in OpenConfig modules, a config container has a state sibling with the same leaves"
+}
+
+func (openconfigConfigStateRule) DefaultSeverity() string { return config.LintSeverityWarning }
+
+func (openconfigConfigStateRule) CheckModule(*yang.Module, ReportFunc) {}
+
+func (openconfigConfigStateRule) CheckEntry(e *yang.Entry, report ReportFunc) {
+	if e.Name != "config" || !e.IsContainer() || e.Parent == nil || !isOpenconfig(e) {
+		return
+	}
+	state := e.Parent.Dir["state"]
+	if state == nil || !state.IsContainer() {
+		report(nil, "config container has no state sibling")
+		return
+	}
+	for _, name := range leafNames(e) {
+		if se, ok := state.Dir[name]; !ok || se.IsDir() {
+			report(nil, "leaf %s has no counterpart in the state container", name)
+		}
+	}
+}
+
+func leafNames(e *yang.Entry) []string {
+	names := make([]string, 0, len(e.Dir))
+	for name, c := range e.Dir {
+		if c.IsLeaf() || c.IsLeafList() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+type openconfigListKeyRule struct{}
+
+func (openconfigListKeyRule) Name() string { return "openconfig-list-key" }
+
+func (openconfigListKeyRule) Description() string {
+	return "in OpenConfig modules, list keys are leafrefs to the leaves of the config container"
+}
+
+func (openconfigListKeyRule) DefaultSeverity() string { return config.LintSeverityWarning }
+
+func (openconfigListKeyRule) CheckModule(*yang.Module, ReportFunc) {}
+
+func (openconfigListKeyRule) CheckEntry(e *yang.Entry, report ReportFunc) {
+	if !e.IsList() || !isOpenconfig(e) {
+		return
+	}
+	for _, k := range strings.Fields(e.Key) {
+		ke := e.Dir[k]
+		if ke == nil {
+			continue
+		}
+		if ke.Type == nil || ke.Type.Kind != yang.Yleafref || !strings.HasSuffix(stripPrefixes(ke.Type.Path), "config/"+k) {
+			report(nil, "key %s is not a leafref to ../config/%s", k, k)
+		}
+	}
+}
+
+// stripPrefixes removes the module prefixes of the elements of path p.
+func stripPrefixes(p string) string {
+	elems := strings.Split(p, "/")
+	for i, pe := range elems {
+		if idx := strings.Index(pe, ":"); idx >= 0 {
+			elems[i] = pe[idx+1:]
+		}
+	}
+	return strings.Join(elems, "/")
+}
+
+func entryKind(e *yang.Entry) string {
+	switch {
+	case e.IsChoice():
+		return "choice"
+	case e.IsCase():
+		return "case"
+	case e.IsList():
+		return "list"
+	case e.IsLeafList():
+		return "leaf-list"
+	case e.IsLeaf():
+		return "leaf"
+	}
+	return "container"
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"regexp"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// searchFields are the fields a search looks into, with the node attributes they cover.
+var searchFields = map[string][]string{
+	"name":        {"name"},
+	"description": {"description"},
+	"type":        {"type", "type-name"},
+}
+
+// SearchFields are the fields searched by default.
+var SearchFields = []string{"name", "description", "type"}
+
+// Search returns a query selecting the nodes of the given kinds found under path
+// under with one of the given fields matching re, all the kinds and fields if none.
+func Search(re *regexp.Regexp, fields, kinds []string, under *sdcpb.Path, limit int) (*Query, error) {
+	if len(fields) == 0 {
+		fields = SearchFields
+	}
+	e := &searchExpr{re: re}
+	for _, f := range fields {
+		attrs, ok := searchFields[f]
+		if !ok {
+			return nil, fmt.Errorf("unknown search field %q", f)
+		}
+		e.fields = append(e.fields, f)
+		e.attrs = append(e.attrs, attrs)
+	}
+	if len(kinds) == 0 {
+		kinds = []string{"nodes"}
+	}
+	q := &Query{Kinds: make(map[string]bool), Under: under, Where: e, Limit: limit}
+	for _, k := range kinds {
+		ks, ok := nodeKinds(k)
+		if !ok {
+			return nil, fmt.Errorf("unknown node kind %q", k)
+		}
+		for _, k := range ks {
+			q.Kinds[k] = true
+		}
+	}
+	return q, nil
+}
+
+// SearchMatches returns the fields of the attributes of a node selected by
+// search query q that matched, nil if q is not a search.
+func SearchMatches(q *Query, attrs map[string]string) []string {
+	e, ok := q.Where.(*searchExpr)
+	if !ok {
+		return nil
+	}
+	var fields []string
+	for i, f := range e.fields {
+		if e.match(e.attrs[i], attrs) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+type searchExpr struct {
+	re     *regexp.Regexp
+	fields []string
+	// attributes of each field
+	attrs [][]string
+}
+
+func (e *searchExpr) eval(attrs map[string]string) bool {
+	for _, as := range e.attrs {
+		if e.match(as, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *searchExpr) match(names []string, attrs map[string]string) bool {
+	for _, n := range names {
+		if v, ok := attrs[n]; ok && e.re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,410 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package query implements a small query language selecting schema nodes:
+//
+//	select leaves where type=enumeration and config=true under /interface
+//
+// A query selects one or more node kinds (containers, lists, leaves, leaf-lists or nodes
+// for all of them) followed by optional clauses in any order:
+//   - where: conditions on the node attributes combined with and, or, not and parentheses.
+//     The operators are = and != comparing values ignoring case, ~ and !~ matching a regular expression.
+//   - under: the path of the subtree the nodes are selected from, the whole schema by default.
+//   - limit: the max number of nodes returned.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	KindContainer = "container"
+	KindList      = "list"
+	KindLeaf      = "leaf"
+	KindLeafList  = "leaf-list"
+)
+
+// attributes that can be used in a where clause
+var attributes = map[string]struct{}{
+	"name": {}, "path": {}, "kind": {}, "module": {}, "description": {},
+	"config": {}, "state": {}, "key": {}, "keys": {}, "mandatory": {}, "presence": {},
+	"user-ordered": {}, "min-elements": {}, "max-elements": {}, "if-feature": {},
+	"type": {}, "type-name": {}, "units": {}, "default": {}, "enum": {}, "range": {},
+	"length": {}, "leafref": {}, "encrypted": {},
+}
+
+// Query is a parsed query.
+type Query struct {
+	// selected node kinds
+	Kinds map[string]bool
+	// root of the selected subtree, empty for the whole schema
+	Under *sdcpb.Path
+	Where expr
+	// 0 means no limit
+	Limit int
+}
+
+type expr interface {
+	eval(attrs map[string]string) bool
+}
+
+type orExpr []expr
+
+func (e orExpr) eval(attrs map[string]string) bool {
+	for _, se := range e {
+		if se.eval(attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []expr
+
+func (e andExpr) eval(attrs map[string]string) bool {
+	for _, se := range e {
+		if !se.eval(attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	e expr
+}
+
+func (e notExpr) eval(attrs map[string]string) bool {
+	return !e.e.eval(attrs)
+}
+
+type condition struct {
+	attr  string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (c *condition) eval(attrs map[string]string) bool {
+	v := attrs[c.attr]
+	switch c.op {
+	case "=":
+		return strings.EqualFold(v, c.value)
+	case "!=":
+		return !strings.EqualFold(v, c.value)
+	case "~":
+		return c.re.MatchString(v)
+	case "!~":
+		return !c.re.MatchString(v)
+	}
+	return false
+}
+
+// Parse parses query s.
+func Parse(s string) (*Query, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	return p.query()
+}
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	stringToken
+	opToken
+	punctToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(s string) ([]*token, error) {
+	toks := make([]*token, 0)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == ',' || c == '(' || c == ')':
+			toks = append(toks, &token{kind: punctToken, text: string(c), pos: i})
+			i++
+		case c == '=' || c == '~':
+			toks = append(toks, &token{kind: opToken, text: string(c), pos: i})
+			i++
+		case c == '!':
+			if i+1 >= len(s) || (s[i+1] != '=' && s[i+1] != '~') {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			toks = append(toks, &token{kind: opToken, text: s[i : i+2], pos: i})
+			i += 2
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			toks = append(toks, &token{kind: stringToken, text: s[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			start := i
+			// a path can carry keys: anything goes between brackets
+			depth := 0
+		WORD:
+			for ; i < len(s); i++ {
+				switch s[i] {
+				case '[':
+					depth++
+				case ']':
+					depth--
+				case ' ', '\t', '\n', '\r', ',', '(', ')', '=', '~', '!', '"', '\'':
+					if depth <= 0 {
+						break WORD
+					}
+				}
+			}
+			toks = append(toks, &token{kind: wordToken, text: s[start:i], pos: start})
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []*token
+	i    int
+}
+
+func (p *parser) peek() *token {
+	if p.i < len(p.toks) {
+		return p.toks[p.i]
+	}
+	return nil
+}
+
+func (p *parser) next() *token {
+	t := p.peek()
+	if t != nil {
+		p.i++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the keyword kw, it is consumed if it is.
+func (p *parser) keyword(kw string) bool {
+	t := p.peek()
+	if t != nil && t.kind == wordToken && strings.EqualFold(t.text, kw) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) punct(c string) bool {
+	t := p.peek()
+	if t != nil && t.kind == punctToken && t.text == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if t := p.peek(); t != nil {
+		return fmt.Errorf("%s, got %q at %d", msg, t.text, t.pos)
+	}
+	return fmt.Errorf("%s, got end of query", msg)
+}
+
+func (p *parser) query() (*Query, error) {
+	if !p.keyword("select") {
+		return nil, p.errorf("expecting select")
+	}
+	q := &Query{Kinds: make(map[string]bool)}
+	for {
+		t := p.peek()
+		if t == nil || t.kind != wordToken {
+			return nil, p.errorf("expecting a node kind")
+		}
+		p.i++
+		kinds, ok := nodeKinds(t.text)
+		if !ok {
+			return nil, fmt.Errorf("unknown node kind %q at %d", t.text, t.pos)
+		}
+		for _, k := range kinds {
+			q.Kinds[k] = true
+		}
+		if !p.punct(",") {
+			break
+		}
+	}
+	seen := make(map[string]bool)
+	for p.peek() != nil {
+		t := p.peek()
+		clause := strings.ToLower(t.text)
+		if seen[clause] {
+			return nil, fmt.Errorf("duplicate %s clause at %d", clause, t.pos)
+		}
+		seen[clause] = true
+		switch {
+		case p.keyword("where"):
+			e, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			q.Where = e
+		case p.keyword("under"):
+			t := p.peek()
+			if t == nil || (t.kind != wordToken && t.kind != stringToken) {
+				return nil, p.errorf("expecting a path")
+			}
+			p.i++
+			under, err := utils.ParsePath(t.text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %v", t.text, err)
+			}
+			q.Under = under
+		case p.keyword("limit"):
+			t := p.next()
+			if t == nil {
+				return nil, p.errorf("expecting a number")
+			}
+			n, err := strconv.Atoi(t.text)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid limit %q at %d", t.text, t.pos)
+			}
+			q.Limit = n
+		default:
+			return nil, p.errorf("expecting where, under or limit")
+		}
+	}
+	return q, nil
+}
+
+func nodeKinds(s string) ([]string, bool) {
+	switch strings.ToLower(s) {
+	case "nodes", "node", "*":
+		return []string{KindContainer, KindList, KindLeaf, KindLeafList}, true
+	case "containers", "container":
+		return []string{KindContainer}, true
+	case "lists", "list":
+		return []string{KindList}, true
+	case "leaves", "leaf":
+		return []string{KindLeaf}, true
+	case "leaf-lists", "leaf-list", "leaflists", "leaflist":
+		return []string{KindLeafList}, true
+	}
+	return nil, false
+}
+
+// or := and { "or" and }
+func (p *parser) or() (expr, error) {
+	e, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	es := orExpr{e}
+	for p.keyword("or") {
+		e, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	if len(es) == 1 {
+		return es[0], nil
+	}
+	return es, nil
+}
+
+// and := unary { "and" unary }
+func (p *parser) and() (expr, error) {
+	e, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	es := andExpr{e}
+	for p.keyword("and") {
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	if len(es) == 1 {
+		return es[0], nil
+	}
+	return es, nil
+}
+
+// unary := "not" unary | "(" or ")" | attr op value
+func (p *parser) unary() (expr, error) {
+	if p.keyword("not") {
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+	if p.punct("(") {
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.punct(")") {
+			return nil, p.errorf("expecting )")
+		}
+		return e, nil
+	}
+	t := p.peek()
+	if t == nil || t.kind != wordToken {
+		return nil, p.errorf("expecting an attribute")
+	}
+	p.i++
+	attr := strings.ToLower(t.text)
+	if _, ok := attributes[attr]; !ok {
+		return nil, fmt.Errorf("unknown attribute %q at %d", t.text, t.pos)
+	}
+	op := p.peek()
+	if op == nil || op.kind != opToken {
+		return nil, p.errorf("expecting =, !=, ~ or !~")
+	}
+	p.i++
+	v := p.peek()
+	if v == nil || (v.kind != wordToken && v.kind != stringToken) {
+		return nil, p.errorf("expecting a value")
+	}
+	p.i++
+	c := &condition{attr: attr, op: op.text, value: v.text}
+	if c.op == "~" || c.op == "!~" {
+		re, err := regexp.Compile(v.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q at %d: %v", v.text, v.pos, err)
+		}
+		c.re = re
+	}
+	return c, nil
+}
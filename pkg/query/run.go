@@ -0,0 +1,256 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// Match is a schema node selected by a query.
+type Match struct {
+	// xpath with wildcard list keys
+	Path       string
+	Kind       string
+	Attributes map[string]string
+}
+
+// Run evaluates query q against the schema of r and calls fn for each
+// selected node, in the schema order. It reports whether more nodes than
+// the query limit match.
+func Run(ctx context.Context, r *store.Resolver, q *Query, fn func(*Match) error) (bool, error) {
+	w := &walker{resolver: r, query: q, fn: fn}
+	var names []string
+	var elems []*sdcpb.PathElem
+	for _, pe := range q.Under.GetElem() {
+		names = append(names, pe.GetName())
+		se, err := r.Get(ctx, names)
+		if err != nil {
+			return false, err
+		}
+		elems = append(elems, pathElem(se))
+	}
+	err := w.walk(ctx, names, elems, len(names) == 0)
+	if err == errLimit {
+		return true, nil
+	}
+	return false, err
+}
+
+var errLimit = errors.New("query limit reached")
+
+type walker struct {
+	resolver *store.Resolver
+	query    *Query
+	fn       func(*Match) error
+	count    int
+}
+
+// walk evaluates the descendants of the node found at names, elems is the path of the node.
+// The children of the schema root are modules, their nodes are not prefixed with the module name.
+func (w *walker) walk(ctx context.Context, names []string, elems []*sdcpb.PathElem, root bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	se, err := w.resolver.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	cs := se.GetContainer()
+	if cs == nil {
+		return nil
+	}
+	if root {
+		for _, m := range cs.GetChildren() {
+			if err := w.walk(ctx, []string{m}, nil, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, c := range cs.GetFields() {
+		if err := w.visit(ctx, &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: c}}, elems, isKey(cs, c.GetName())); err != nil {
+			return err
+		}
+	}
+	for _, c := range cs.GetLeaflists() {
+		if err := w.visit(ctx, &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Leaflist{Leaflist: c}}, elems, false); err != nil {
+			return err
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, c)
+		cse, err := w.resolver.Get(ctx, cnames)
+		if err != nil {
+			return err
+		}
+		celems := make([]*sdcpb.PathElem, 0, len(elems)+1)
+		celems = append(celems, elems...)
+		celems = append(celems, pathElem(cse))
+		if err := w.visit(ctx, cse, elems, false); err != nil {
+			return err
+		}
+		if err := w.walk(ctx, cnames, celems, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visit evaluates node se found under the path elems.
+func (w *walker) visit(ctx context.Context, se *sdcpb.SchemaElem, elems []*sdcpb.PathElem, key bool) error {
+	attrs, err := w.attributes(ctx, se, elems, key)
+	if err != nil {
+		return err
+	}
+	kind := attrs["kind"]
+	if !w.query.Kinds[kind] {
+		return nil
+	}
+	if w.query.Where != nil && !w.query.Where.eval(attrs) {
+		return nil
+	}
+	if w.query.Limit > 0 && w.count >= w.query.Limit {
+		return errLimit
+	}
+	w.count++
+	path := attrs["path"]
+	delete(attrs, "path")
+	delete(attrs, "kind")
+	return w.fn(&Match{Path: path, Kind: kind, Attributes: attrs})
+}
+
+// attributes returns the non empty attributes of node se.
+func (w *walker) attributes(ctx context.Context, se *sdcpb.SchemaElem, elems []*sdcpb.PathElem, key bool) (map[string]string, error) {
+	module, err := w.resolver.Module(ctx, se)
+	if err != nil {
+		return nil, err
+	}
+	p := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(elems)+1)}
+	p.Elem = append(p.Elem, elems...)
+	p.Elem = append(p.Elem, pathElem(se))
+	attrs := map[string]string{
+		"name":   store.Name(se),
+		"path":   "/" + utils.ToXPath(p, false),
+		"module": module,
+	}
+	var state bool
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		cs := se.Container
+		state = cs.GetIsState()
+		attrs["kind"] = KindContainer
+		attrs["description"] = cs.GetDescription()
+		attrs["presence"] = boolAttr(cs.GetIsPresence())
+		attrs["if-feature"] = strings.Join(cs.GetIfFeature(), " ")
+		if len(cs.GetKeys()) > 0 {
+			attrs["kind"] = KindList
+			keys := make([]string, 0, len(cs.GetKeys()))
+			for _, k := range cs.GetKeys() {
+				keys = append(keys, k.GetName())
+			}
+			attrs["keys"] = strings.Join(keys, " ")
+			attrs["user-ordered"] = boolAttr(cs.GetIsUserOrdered())
+			attrs["min-elements"] = uintAttr(cs.GetMinElements())
+			attrs["max-elements"] = uintAttr(cs.GetMaxElements())
+		}
+	case *sdcpb.SchemaElem_Field:
+		ls := se.Field
+		state = ls.GetIsState()
+		attrs["kind"] = KindLeaf
+		attrs["description"] = ls.GetDescription()
+		attrs["key"] = boolAttr(key)
+		attrs["mandatory"] = boolAttr(ls.GetIsMandatory())
+		attrs["units"] = ls.GetUnits()
+		attrs["default"] = ls.GetDefault()
+		attrs["encrypted"] = boolAttr(ls.GetEncrypted())
+		attrs["if-feature"] = strings.Join(ls.GetIfFeature(), " ")
+		typeAttributes(attrs, ls.GetType())
+	case *sdcpb.SchemaElem_Leaflist:
+		lls := se.Leaflist
+		state = lls.GetIsState()
+		attrs["kind"] = KindLeafList
+		attrs["description"] = lls.GetDescription()
+		attrs["units"] = lls.GetUnits()
+		attrs["default"] = strings.Join(lls.GetDefaults(), " ")
+		attrs["user-ordered"] = boolAttr(lls.GetIsUserOrdered())
+		attrs["min-elements"] = uintAttr(lls.GetMinElements())
+		attrs["max-elements"] = uintAttr(lls.GetMaxElements())
+		attrs["encrypted"] = boolAttr(lls.GetEncrypted())
+		attrs["if-feature"] = strings.Join(lls.GetIfFeature(), " ")
+		typeAttributes(attrs, lls.GetType())
+	}
+	attrs["config"] = boolAttr(!state)
+	attrs["state"] = boolAttr(state)
+	for k, v := range attrs {
+		if v == "" {
+			delete(attrs, k)
+		}
+	}
+	return attrs, nil
+}
+
+func typeAttributes(attrs map[string]string, t *sdcpb.SchemaLeafType) {
+	attrs["type"] = t.GetType()
+	attrs["type-name"] = t.GetTypeName()
+	attrs["enum"] = strings.Join(t.GetValues(), " ")
+	attrs["range"] = t.GetRange()
+	attrs["length"] = t.GetLength()
+	attrs["leafref"] = t.GetLeafref()
+	if attrs["units"] == "" {
+		attrs["units"] = t.GetUnits()
+	}
+}
+
+func boolAttr(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func uintAttr(i uint64) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.FormatUint(i, 10)
+}
+
+func isKey(cs *sdcpb.ContainerSchema, name string) bool {
+	for _, k := range cs.GetKeys() {
+		if k.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pathElem returns the path element of node se, with wildcard keys if it is a list.
+func pathElem(se *sdcpb.SchemaElem) *sdcpb.PathElem {
+	pe := &sdcpb.PathElem{Name: store.Name(se)}
+	if cs := se.GetContainer(); len(cs.GetKeys()) > 0 {
+		pe.Key = make(map[string]string, len(cs.GetKeys()))
+		for _, k := range cs.GetKeys() {
+			pe.Key[k.GetName()] = "*"
+		}
+	}
+	return pe
+}
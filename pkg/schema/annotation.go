@@ -0,0 +1,182 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// module defining the annotation extension, RFC 7952
+const metadataModule = "ietf-yang-metadata"
+
+// Annotation is a metadata annotation defined by an md:annotation
+// statement of a module of a schema, RFC 7952.
+type Annotation struct {
+	Name string `json:"name,omitempty"`
+	// module defining the annotation, the one a submodule belongs to
+	Module      string                `json:"module,omitempty"`
+	Prefix      string                `json:"prefix,omitempty"`
+	Type        *sdcpb.SchemaLeafType `json:"type,omitempty"`
+	Units       string                `json:"units,omitempty"`
+	IfFeature   []string              `json:"if-feature,omitempty"`
+	Status      string                `json:"status,omitempty"`
+	Description string                `json:"description,omitempty"`
+}
+
+// QualifiedName returns the module qualified name of the annotation,
+// as in an RFC 7951 JSON document.
+func (a *Annotation) QualifiedName() string {
+	return a.Module + ":" + a.Name
+}
+
+// scanAnnotations collects the annotations of the parsed modules and submodules,
+// it must run before the modules are released.
+func (sc *Schema) scanAnnotations() {
+	byName := make(map[string]*Annotation)
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		// modules are indexed by name and by name@revision
+		seen := make(map[*yang.Module]struct{}, len(ms))
+		for _, m := range ms {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			for _, ext := range m.Extensions {
+				prefix, kw, ok := strings.Cut(ext.Keyword, ":")
+				if !ok || kw != "annotation" {
+					continue
+				}
+				if em := yang.FindModuleByPrefix(m, prefix); em == nil || em.Name != metadataModule {
+					continue
+				}
+				a := &Annotation{
+					Name:   ext.Argument,
+					Module: owningModule(m),
+					Prefix: m.GetPrefix(),
+				}
+				for _, s := range ext.SubStatements() {
+					switch s.Keyword {
+					case "type":
+						a.Type = annotationType(m, s)
+					case "units":
+						a.Units = s.Argument
+					case "if-feature":
+						a.IfFeature = append(a.IfFeature, s.Argument)
+					case "status":
+						a.Status = s.Argument
+					case "description":
+						a.Description = strings.TrimSpace(s.Argument)
+					}
+				}
+				if a.Type != nil {
+					a.Type.Units = a.Units
+				}
+				byName[a.QualifiedName()] = a
+			}
+		}
+	}
+	sc.annotations = make([]*Annotation, 0, len(byName))
+	for _, a := range byName {
+		sc.annotations = append(sc.annotations, a)
+	}
+	sort.Slice(sc.annotations, func(i, j int) bool {
+		return sc.annotations[i].QualifiedName() < sc.annotations[j].QualifiedName()
+	})
+}
+
+// annotationType returns the leaf type of the type statement s of an annotation
+// of module m. The extension statements are not resolved by goyang: a built-in
+// type is built from the restrictions of s, a typedef is the resolved one,
+// restricted further by s.
+func annotationType(m *yang.Module, s *yang.Statement) *sdcpb.SchemaLeafType {
+	var t *sdcpb.SchemaLeafType
+	if _, ok := yang.TypeKindFromName[s.Argument]; ok {
+		t = &sdcpb.SchemaLeafType{
+			Type:       s.Argument,
+			Patterns:   []*sdcpb.SchemaPattern{},
+			UnionTypes: []*sdcpb.SchemaLeafType{},
+		}
+	} else if td := findTypedef(m, s.Argument); td != nil && td.YangType != nil {
+		t = toSchemaType(td.YangType)
+		t.TypeName = s.Argument
+	} else {
+		// unresolved typedef, any value is accepted
+		return &sdcpb.SchemaLeafType{Type: "string", TypeName: s.Argument}
+	}
+	for _, r := range s.SubStatements() {
+		switch r.Keyword {
+		case "range":
+			t.Range = r.Argument
+		case "length":
+			t.Length = r.Argument
+		case "pattern":
+			p := &sdcpb.SchemaPattern{Pattern: r.Argument}
+			for _, ps := range r.SubStatements() {
+				if ps.Keyword == "modifier" && ps.Argument == "invert-match" {
+					p.Inverted = true
+				}
+			}
+			t.Patterns = append(t.Patterns, p)
+		case "enum", "bit":
+			t.Values = append(t.Values, r.Argument)
+		case "base":
+			t.Values = append(t.Values, qualifyIdentity(m, r.Argument))
+		case "path":
+			t.Leafref = r.Argument
+		case "type":
+			t.UnionTypes = append(t.UnionTypes, annotationType(m, r))
+		}
+	}
+	return t
+}
+
+// findTypedef returns the top level typedef name, prefixed or not,
+// of module m or of a module it imports, nil if not found.
+func findTypedef(m *yang.Module, name string) *yang.Typedef {
+	prefix := ""
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		prefix, name = name[:idx], name[idx+1:]
+	}
+	tm := yang.FindModuleByPrefix(m, prefix)
+	if tm == nil {
+		return nil
+	}
+	ms := []*yang.Module{tm}
+	for _, i := range tm.Include {
+		if i.Module != nil {
+			ms = append(ms, i.Module)
+		}
+	}
+	for _, m := range ms {
+		for _, td := range m.Typedef {
+			if td.Name == name {
+				return td
+			}
+		}
+	}
+	return nil
+}
+
+// Annotations returns the annotations of the schema sorted by module qualified name.
+func (sc *Schema) Annotations() []*Annotation {
+	if sc == nil {
+		return nil
+	}
+	return sc.annotations
+}
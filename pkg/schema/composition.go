@@ -0,0 +1,179 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Composition reports the issues found assembling a schema from its modules.
+type Composition struct {
+	UnresolvedImports []*UnresolvedImport `json:"unresolved-imports,omitempty"`
+	AugmentConflicts  []*AugmentConflict  `json:"augment-conflicts,omitempty"`
+	DuplicateNodes    []*DuplicateNode    `json:"duplicate-nodes,omitempty"`
+}
+
+type UnresolvedImport struct {
+	Module string `json:"module,omitempty"`
+	Import string `json:"import,omitempty"`
+	// position of the import statement, used to report the parse error
+	location string
+}
+
+// AugmentConflict is a node added to an augment target by more than one module,
+// or by a module while the target already has it. A single definition is kept.
+type AugmentConflict struct {
+	Target string `json:"target,omitempty"`
+	Node   string `json:"node,omitempty"`
+	// the module the kept node is defined in comes first
+	Modules []string `json:"modules,omitempty"`
+}
+
+// DuplicateNode is a top level node defined by more than one module,
+// its unqualified name is ambiguous.
+type DuplicateNode struct {
+	Path    string   `json:"path,omitempty"`
+	Modules []string `json:"modules,omitempty"`
+}
+
+// Warnings returns the issues of the report as log lines.
+func (c *Composition) Warnings() []string {
+	ws := make([]string, 0, len(c.UnresolvedImports)+len(c.AugmentConflicts)+len(c.DuplicateNodes))
+	for _, ui := range c.UnresolvedImports {
+		ws = append(ws, fmt.Sprintf("module %s: unresolved import %s", ui.Module, ui.Import))
+	}
+	for _, ac := range c.AugmentConflicts {
+		ws = append(ws, fmt.Sprintf("augment %s: node %q defined by %s, keeping %s",
+			ac.Target, ac.Node, strings.Join(ac.Modules, ", "), ac.Modules[0]))
+	}
+	for _, dn := range c.DuplicateNodes {
+		ws = append(ws, fmt.Sprintf("node %s defined by %s", dn.Path, strings.Join(dn.Modules, ", ")))
+	}
+	return ws
+}
+
+// scanImports reports the imports of the modules that are not found,
+// it runs once the modules are processed, successfully or not.
+func (sc *Schema) scanImports() {
+	c := &Composition{}
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		seen := make(map[*yang.Module]struct{}, len(ms))
+		for _, m := range ms {
+			// modules are indexed by name and by name@revision
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			for _, imp := range m.Import {
+				if _, ok := sc.modules.Modules[imp.Name]; !ok {
+					c.UnresolvedImports = append(c.UnresolvedImports, &UnresolvedImport{
+						Module:   m.Name,
+						Import:   imp.Name,
+						location: imp.Source.Location(),
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(c.UnresolvedImports, func(i, j int) bool {
+		if c.UnresolvedImports[i].Module != c.UnresolvedImports[j].Module {
+			return c.UnresolvedImports[i].Module < c.UnresolvedImports[j].Module
+		}
+		return c.UnresolvedImports[i].Import < c.UnresolvedImports[j].Import
+	})
+	sc.composition = c
+}
+
+// scanComposition reports the conflicting augments and the duplicate
+// top level nodes, it runs once the schema tree is built.
+func (sc *Schema) scanComposition() {
+	if sc.composition == nil {
+		sc.composition = &Composition{}
+	}
+	c := sc.composition
+	topLevel := make(map[string][]string)
+	for _, me := range sc.root.Dir {
+		for name := range me.Dir {
+			topLevel[name] = append(topLevel[name], me.Name)
+		}
+		for name, e := range me.Dir {
+			c.scanAugments(e, "/"+me.Name+":"+name)
+		}
+	}
+	for name, mods := range topLevel {
+		if len(mods) < 2 {
+			continue
+		}
+		sort.Strings(mods)
+		c.DuplicateNodes = append(c.DuplicateNodes, &DuplicateNode{Path: "/" + name, Modules: mods})
+	}
+	sort.Slice(c.DuplicateNodes, func(i, j int) bool {
+		return c.DuplicateNodes[i].Path < c.DuplicateNodes[j].Path
+	})
+	sort.Slice(c.AugmentConflicts, func(i, j int) bool {
+		if c.AugmentConflicts[i].Target != c.AugmentConflicts[j].Target {
+			return c.AugmentConflicts[i].Target < c.AugmentConflicts[j].Target
+		}
+		return c.AugmentConflicts[i].Node < c.AugmentConflicts[j].Node
+	})
+}
+
+// scanAugments walks the tree under e, p is the path of e.
+func (c *Composition) scanAugments(e *yang.Entry, p string) {
+	if len(e.Augmented) > 0 {
+		// modules augmenting e with each node they failed to add
+		dropped := make(map[string][]string)
+		for _, a := range e.Augmented {
+			am := moduleName(a.Node)
+			for name := range a.Dir {
+				ce, ok := e.Dir[name]
+				if !ok || moduleName(ce.Node) == am {
+					continue
+				}
+				dropped[name] = append(dropped[name], am)
+			}
+		}
+		for name, mods := range dropped {
+			sort.Strings(mods)
+			c.AugmentConflicts = append(c.AugmentConflicts, &AugmentConflict{
+				Target:  p,
+				Node:    name,
+				Modules: append([]string{moduleName(e.Dir[name].Node)}, mods...),
+			})
+		}
+	}
+	for name, ce := range e.Dir {
+		c.scanAugments(ce, p+"/"+name)
+	}
+}
+
+func moduleName(n yang.Node) string {
+	if n == nil {
+		return ""
+	}
+	if m := yang.RootNode(n); m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// Composition returns the composition report of the schema.
+func (s *Schema) Composition() *Composition {
+	return s.composition
+}
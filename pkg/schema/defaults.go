@@ -0,0 +1,125 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// origins of a default value
+const (
+	// the default statement of the leaf or leaf-list
+	DefaultOriginLeaf = "leaf"
+	// the default statement of a typedef its type derives from
+	DefaultOriginTypedef = "typedef"
+	// a deviation adding or replacing the default
+	DefaultOriginDeviation = "deviation"
+)
+
+// DefaultOrigin tells where the default of a leaf or leaf-list is stated.
+type DefaultOrigin struct {
+	Origin string `json:"origin"`
+	// module qualified name of the typedef stating the default
+	Typedef string `json:"typedef,omitempty"`
+	// module with the deviation stating the default
+	Module string `json:"module,omitempty"`
+}
+
+// scanDefaultOrigins collects the origin of the defaults of the leaves and
+// leaf-lists by data path, it must run before the modules are released.
+func (sc *Schema) scanDefaultOrigins() {
+	// the nodes a deviation sets the default of, with the deviation module
+	deviated := make(map[*yang.Entry]string)
+	for _, m := range sc.modules.Modules {
+		if len(m.Deviation) == 0 {
+			continue
+		}
+		me := yang.ToEntry(m)
+		for _, d := range me.Deviations {
+			for _, des := range [][]*yang.Entry{d.Deviate[yang.DeviationAdd], d.Deviate[yang.DeviationReplace]} {
+				for _, de := range des {
+					if len(de.Default) == 0 {
+						continue
+					}
+					if e := me.Find(d.DeviatedPath); e != nil {
+						deviated[e] = m.Name
+					}
+				}
+			}
+		}
+	}
+	sc.defaultOrigins = make(map[string]*DefaultOrigin)
+	for _, m := range sc.root.Dir {
+		sc.walkDefaults(m, deviated)
+	}
+}
+
+func (sc *Schema) walkDefaults(e *yang.Entry, deviated map[*yang.Entry]string) {
+	if e.IsLeaf() || e.IsLeafList() {
+		if o := defaultOrigin(e, deviated); o != nil {
+			// the first element is the module
+			elems := buildPathUpFromEntry(e).GetElem()[1:]
+			names := make([]string, 0, len(elems))
+			for _, pe := range elems {
+				names = append(names, pe.GetName())
+			}
+			sc.defaultOrigins["/"+strings.Join(names, "/")] = o
+		}
+		return
+	}
+	for _, ce := range e.Dir {
+		sc.walkDefaults(ce, deviated)
+	}
+}
+
+// defaultOrigin returns the origin of the default of leaf or leaf-list e,
+// nil if it has none.
+func defaultOrigin(e *yang.Entry, deviated map[*yang.Entry]string) *DefaultOrigin {
+	if len(e.Default) > 0 {
+		if m, ok := deviated[e]; ok {
+			return &DefaultOrigin{Origin: DefaultOriginDeviation, Module: m}
+		}
+		return &DefaultOrigin{Origin: DefaultOriginLeaf}
+	}
+	if len(e.DefaultValues()) == 0 {
+		return nil
+	}
+	// the default of a type is the one of the closest typedef stating one
+	for t := e.Type.Base; t != nil && t.YangType != nil; t = t.YangType.Base {
+		td, ok := t.Parent.(*yang.Typedef)
+		if !ok {
+			break
+		}
+		if td.Default != nil {
+			name := td.Name
+			if m := yang.RootNode(td); m != nil {
+				name = owningModule(m) + ":" + name
+			}
+			return &DefaultOrigin{Origin: DefaultOriginTypedef, Typedef: name}
+		}
+	}
+	return &DefaultOrigin{Origin: DefaultOriginTypedef}
+}
+
+// DefaultOrigins returns the origin of the defaults of the leaves and
+// leaf-lists by data path, e.g. /interface/config/mtu.
+func (sc *Schema) DefaultOrigins() map[string]*DefaultOrigin {
+	if sc == nil {
+		return nil
+	}
+	return sc.defaultOrigins
+}
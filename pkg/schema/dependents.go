@@ -0,0 +1,169 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// Dependents returns the configs in cfgs whose schema reads a module file
+// read by the schema of cfg, directly or through another dependent schema.
+// cfg itself is not part of the result.
+// files returns the module files read by the schema of a config, see ModuleFiles,
+// nil if unknown: the ones the files and directories of the configs point to
+// are then compared.
+func Dependents(cfg *config.SchemaConfig, cfgs []*config.SchemaConfig, files func(*config.SchemaConfig) []string) []*config.SchemaConfig {
+	group := []*config.SchemaConfig{cfg}
+	rs := make([]*config.SchemaConfig, 0)
+	done := map[string]bool{configKey(cfg): true}
+	for i := 0; i < len(group); i++ {
+		for _, c := range cfgs {
+			if done[configKey(c)] || !sharesModules(group[i], c, files) {
+				continue
+			}
+			done[configKey(c)] = true
+			group = append(group, c)
+			rs = append(rs, c)
+		}
+	}
+	return rs
+}
+
+func configKey(cfg *config.SchemaConfig) string {
+	return strings.Join([]string{cfg.Name, cfg.Vendor, cfg.Version}, "@")
+}
+
+// sharesModules reports whether the schemas of a and b read the same module file.
+// Without their module files, it reports whether a file or directory of a
+// is the same as or contains one of b, or the other way around.
+func sharesModules(a, b *config.SchemaConfig, files func(*config.SchemaConfig) []string) bool {
+	if af, bf := files(a), files(b); af != nil && bf != nil {
+		read := make(map[string]struct{}, len(af))
+		for _, f := range af {
+			read[f] = struct{}{}
+		}
+		for _, f := range bf {
+			if _, ok := read[f]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	aps := schemaPaths(a)
+	bps := schemaPaths(b)
+	for _, ap := range aps {
+		for _, bp := range bps {
+			if ap == bp || isUnder(ap, bp) || isUnder(bp, ap) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func schemaPaths(cfg *config.SchemaConfig) []string {
+	ps := make([]string, 0, len(cfg.Files)+len(cfg.Directories)+len(cfg.Deviations))
+	for _, p := range append(append(append([]string{}, cfg.Files...), cfg.Directories...), cfg.Deviations...) {
+		ps = append(ps, utils.PathKey(p))
+	}
+	return ps
+}
+
+func isUnder(p, dir string) bool {
+	return strings.HasPrefix(p, dir+string(filepath.Separator))
+}
+
+// NewSchemas parses the schemas of cfgs concurrently, GOMAXPROCS of them at a time,
+// no schema is returned if any of them fails.
+func NewSchemas(cfgs []*config.SchemaConfig) ([]*Schema, error) {
+	scs := make([]*Schema, len(cfgs))
+	errs := make([]error, len(cfgs))
+	wg := new(sync.WaitGroup)
+	wg.Add(len(cfgs))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, cfg := range cfgs {
+		sem <- struct{}{}
+		go func(i int, cfg *config.SchemaConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			scs[i], errs[i] = NewSchema(cfg)
+		}(i, cfg)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", configKey(cfgs[i]), err)
+		}
+	}
+	return scs, nil
+}
+
+// ModuleFiles returns the files of the modules and submodules
+// the schema was read from, sorted.
+func (s *Schema) ModuleFiles() []string {
+	return s.moduleFiles
+}
+
+// readFiles returns the files of the modules and submodules read.
+func (s *Schema) readFiles() []string {
+	seen := make(map[string]struct{})
+	files := make([]string, 0, len(s.modules.Modules)+len(s.modules.SubModules))
+	for _, ms := range []map[string]*yang.Module{s.modules.Modules, s.modules.SubModules} {
+		for _, m := range ms {
+			f := sourceFile(m.Source)
+			if f == "" {
+				continue
+			}
+			f = utils.PathKey(f)
+			if _, ok := seen[f]; ok {
+				continue
+			}
+			seen[f] = struct{}{}
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// sourceFile returns the file of the statement location file:line:col.
+func sourceFile(st *yang.Statement) string {
+	if st == nil {
+		return ""
+	}
+	loc := st.Location()
+	for i := 0; i < 2; i++ {
+		j := strings.LastIndexByte(loc, ':')
+		if j < 0 {
+			return ""
+		}
+		loc = loc[:j]
+	}
+	return loc
+}
+
+func (s *Schema) Config() *config.SchemaConfig {
+	return s.config
+}
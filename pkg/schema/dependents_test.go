@@ -0,0 +1,142 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+func TestDependents(t *testing.T) {
+	dir := t.TempDir()
+	p := func(name string) string { return filepath.Join(dir, name) }
+	cfg := func(name string, files, dirs []string) *config.SchemaConfig {
+		return &config.SchemaConfig{Name: name, Vendor: "v", Version: "1", Files: files, Directories: dirs}
+	}
+	a := cfg("a", []string{p("a/a.yang")}, []string{p("common")})
+	b := cfg("b", []string{p("b/b.yang")}, []string{p("common")})
+	c := cfg("c", []string{p("c/c.yang")}, []string{p("other")})
+	d := cfg("d", []string{p("d/d.yang")}, []string{p("other")})
+	tests := []struct {
+		name  string
+		files map[*config.SchemaConfig][]string
+		want  []*config.SchemaConfig
+	}{
+		{
+			name: "shared directory without module files",
+			want: []*config.SchemaConfig{b},
+		},
+		{
+			name: "shared directory, different imported files",
+			files: map[*config.SchemaConfig][]string{
+				a: {p("a/a.yang"), p("common/x.yang")},
+				b: {p("b/b.yang"), p("common/y.yang")},
+				c: {p("c/c.yang")},
+				d: {p("d/d.yang")},
+			},
+			want: []*config.SchemaConfig{},
+		},
+		{
+			name: "shared imported file",
+			files: map[*config.SchemaConfig][]string{
+				a: {p("a/a.yang"), p("common/x.yang")},
+				b: {p("b/b.yang"), p("common/x.yang")},
+				c: {p("c/c.yang")},
+				d: {p("d/d.yang")},
+			},
+			want: []*config.SchemaConfig{b},
+		},
+		{
+			name: "through another dependent",
+			files: map[*config.SchemaConfig][]string{
+				a: {p("a/a.yang"), p("common/x.yang")},
+				b: {p("b/b.yang"), p("common/x.yang"), p("other/z.yang")},
+				c: {p("c/c.yang"), p("other/z.yang")},
+				d: {p("d/d.yang")},
+			},
+			want: []*config.SchemaConfig{b, c},
+		},
+		{
+			name: "module files of one schema unknown",
+			files: map[*config.SchemaConfig][]string{
+				a: {p("a/a.yang"), p("common/x.yang")},
+				c: {p("c/c.yang")},
+				d: {p("d/d.yang")},
+			},
+			want: []*config.SchemaConfig{b},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Dependents(a, []*config.SchemaConfig{a, b, c, d}, func(cfg *config.SchemaConfig) []string {
+				return tt.files[cfg]
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependents() = %v, want %v", names(got), names(tt.want))
+			}
+		})
+	}
+}
+
+func names(cfgs []*config.SchemaConfig) []string {
+	ns := make([]string, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		ns = append(ns, cfg.Name)
+	}
+	return ns
+}
+
+func TestSchema_ModuleFiles(t *testing.T) {
+	sc, err := NewSchema(&config.SchemaConfig{
+		Name:        "SRL-Native",
+		Vendor:      "Nokia",
+		Version:     "22.11.1",
+		Files:       []string{"testdata/srl-latest-yang-models/srl_nokia/models/interfaces/srl_nokia-interfaces.yang"},
+		Directories: []string{"testdata/srl-latest-yang-models"},
+		Excludes:    []string{".*tools.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := sc.ModuleFiles()
+	if len(files) == 0 {
+		t.Fatal("ModuleFiles() is empty")
+	}
+	want := map[string]bool{
+		"srl_nokia-interfaces.yang": false,
+		// imported
+		"srl_nokia-common.yang": false,
+	}
+	for _, f := range files {
+		if !filepath.IsAbs(f) {
+			t.Errorf("ModuleFiles(): %q is not absolute", f)
+		}
+		if _, ok := want[filepath.Base(f)]; ok {
+			want[filepath.Base(f)] = true
+		}
+		// not imported
+		if filepath.Base(f) == "srl_nokia-bgp.yang" {
+			t.Errorf("ModuleFiles() has %q", f)
+		}
+	}
+	for f, found := range want {
+		if !found {
+			t.Errorf("ModuleFiles() misses %s", f)
+		}
+	}
+}
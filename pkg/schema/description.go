@@ -0,0 +1,56 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// descriptionVariantPrefix is the local name prefix of the extensions carrying a
+// description in another language, e.g: vendor-ext:description-fr "...";
+const descriptionVariantPrefix = "description-"
+
+// selectDescriptions replaces the description of the entries with their
+// variant in language lang, if they have one.
+func (sc *Schema) selectDescriptions(lang string) error {
+	return sc.Walk(sc.root, func(e *yang.Entry) error {
+		if d, ok := descriptionVariant(e, lang); ok {
+			e.Description = d
+		}
+		return nil
+	})
+}
+
+func descriptionVariant(e *yang.Entry, lang string) (string, bool) {
+	for _, ext := range e.Exts {
+		kw := ext.Keyword
+		if idx := strings.Index(kw, ":"); idx >= 0 {
+			kw = kw[idx+1:]
+		}
+		if !strings.HasPrefix(kw, descriptionVariantPrefix) {
+			continue
+		}
+		if strings.EqualFold(kw[len(descriptionVariantPrefix):], lang) {
+			return ext.Argument, true
+		}
+	}
+	return "", false
+}
+
+func (s *Schema) DescriptionLanguage() string {
+	return s.config.DescriptionLanguage
+}
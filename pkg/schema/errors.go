@@ -0,0 +1,159 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ErrorDomain         = "schema-server"
+	ReasonAmbiguousPath = "AMBIGUOUS_PATH"
+)
+
+// errors found in a YANG file look like "file.yang:line:col: message" or "file.yang: message"
+var fileError = regexp.MustCompile(`^(.+?\.yang)(?::(\d+):(\d+))?: (.*)$`)
+
+// AmbiguousPathError is returned when an unqualified path element
+// matches nodes defined in more than one module.
+type AmbiguousPathError struct {
+	Elem string
+	// module qualified candidate names, e.g: "mod1:elem", "mod2:elem"
+	Candidates []string
+}
+
+func (e *AmbiguousPathError) Error() string {
+	return fmt.Sprintf("path element %q is ambiguous, qualify it with one of: %s",
+		e.Elem, strings.Join(e.Candidates, ", "))
+}
+
+// GRPCStatus makes the error convertible to a gRPC status
+// carrying the candidate names as ErrorInfo details.
+func (e *AmbiguousPathError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	std, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: ReasonAmbiguousPath,
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"element":    e.Elem,
+			"candidates": strings.Join(e.Candidates, ","),
+		},
+	})
+	if err != nil {
+		return st
+	}
+	return std
+}
+
+// ModuleError is an error found reading or processing a YANG file.
+type ModuleError struct {
+	// file the error was found in, empty if it is not known
+	File string
+	// position of the error in the file, zero if it is not known
+	Line   int
+	Column int
+	// error message, without its position
+	Message string
+}
+
+// parseModuleError returns the error found in a YANG file described by l,
+// nil if l does not refer to a file.
+func parseModuleError(l string) *ModuleError {
+	m := fileError.FindStringSubmatch(l)
+	if m == nil {
+		return nil
+	}
+	me := &ModuleError{File: m[1], Message: m[4]}
+	me.Line, _ = strconv.Atoi(m[2])
+	me.Column, _ = strconv.Atoi(m[3])
+	return me
+}
+
+// moduleErrors splits err into an error per line, lines which do not
+// refer to a YANG file are kept with the previous one.
+func moduleErrors(file string, err error) []*ModuleError {
+	mes := make([]*ModuleError, 0, 1)
+	for _, l := range strings.Split(err.Error(), "\n") {
+		if me := parseModuleError(l); me != nil {
+			mes = append(mes, me)
+			continue
+		}
+		if len(mes) > 0 {
+			mes[len(mes)-1].Message += "\n" + l
+			continue
+		}
+		mes = append(mes, &ModuleError{File: file, Message: l})
+	}
+	return mes
+}
+
+// Position returns the file, line and column of the error as "file:line:col",
+// or as much of it as is known.
+func (e *ModuleError) Position() string {
+	switch {
+	case e.File == "":
+		return ""
+	case e.Line == 0:
+		return e.File
+	}
+	return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+}
+
+func (e *ModuleError) Error() string {
+	if pos := e.Position(); pos != "" {
+		return pos + ": " + e.Message
+	}
+	return e.Message
+}
+
+// ParseError is returned when the YANG files of a schema fail to be read or processed.
+type ParseError struct {
+	Errors []*ModuleError
+}
+
+func (e *ParseError) Error() string {
+	es := make([]string, 0, len(e.Errors))
+	for _, me := range e.Errors {
+		es = append(es, "- "+me.Error())
+	}
+	return fmt.Sprintf("yang processing failed with %d error(s):\n%s", len(e.Errors), strings.Join(es, "\n"))
+}
+
+// GRPCStatus makes the error convertible to a gRPC status
+// carrying a field violation per error, the field being the error position.
+func (e *ParseError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	br := &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(e.Errors)),
+	}
+	for _, me := range e.Errors {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       me.Position(),
+			Description: me.Message,
+		})
+	}
+	std, err := st.WithDetails(br)
+	if err != nil {
+		return st
+	}
+	return std
+}
@@ -27,15 +27,28 @@ import (
 
 func (sc *Schema) ExpandPath(p *sdcpb.Path, dt sdcpb.DataType) ([]*sdcpb.Path, error) {
 	ps := make([]*sdcpb.Path, 0)
+	err := sc.ExpandPathFunc(p, dt, func(np *sdcpb.Path) error {
+		ps = append(ps, np)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// ExpandPathFunc calls fn for each of the paths ExpandPath returns,
+// as they are found. The expansion stops at the first error returned by fn.
+func (sc *Schema) ExpandPathFunc(p *sdcpb.Path, dt sdcpb.DataType, fn func(*sdcpb.Path) error) error {
 	cp := utils.ToStrings(p, false, true)
 	e, err := sc.GetEntry(cp)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	populatePathKeys(e, p)
 	switch {
 	case e.IsLeaf():
-		return []*sdcpb.Path{p}, nil
+		return fn(p)
 	}
 	keys := map[string]struct{}{}
 	for _, k := range strings.Fields(e.Key) {
@@ -46,59 +59,51 @@ func (sc *Schema) ExpandPath(p *sdcpb.Path, dt sdcpb.DataType) ([]*sdcpb.Path, e
 		if _, ok := keys[c.Name]; ok {
 			continue
 		}
-		for _, pe := range sc.getPathElems(c, dt) {
+		err := sc.walkPathElems(c, dt, p.GetElem(), func(pes []*sdcpb.PathElem) error {
 			np := &sdcpb.Path{
-				Elem: make([]*sdcpb.PathElem, 0, len(p.GetElem())+len(pe)),
+				Elem: make([]*sdcpb.PathElem, 0, len(pes)),
 			}
-			np.Elem = append(np.Elem, p.GetElem()...)
-			np.Elem = append(np.Elem, pe...)
-			ps = append(ps, np)
+			np.Elem = append(np.Elem, pes...)
+			return fn(np)
+		})
+		if err != nil {
+			return err
 		}
 	}
-	return ps, nil
+	return nil
 }
 
-func (sc *Schema) getPathElems(e *yang.Entry, dt sdcpb.DataType) [][]*sdcpb.PathElem {
-	rs := make([][]*sdcpb.PathElem, 0)
+// walkPathElems calls fn with the path elements of each leaf and leaf-list
+// found under e, prefixed with the path elements of the parent of e.
+// The slice passed to fn is reused once fn returns.
+func (sc *Schema) walkPathElems(e *yang.Entry, dt sdcpb.DataType, prefix []*sdcpb.PathElem, fn func([]*sdcpb.PathElem) error) error {
 	switch {
 	case e.IsCase():
 		log.Debugf("got case: %s", e.Name)
 		for _, c := range e.Dir {
-			rs = append(rs, sc.getPathElems(c, dt)...)
+			if err := sc.walkPathElems(c, dt, prefix, fn); err != nil {
+				return err
+			}
 		}
 	case e.IsChoice():
 		log.Debugf("got choice: %s", e.Name)
 		for _, c := range e.Dir {
-			rs = append(rs, sc.getPathElems(c, dt)...)
+			if err := sc.walkPathElems(c, dt, prefix, fn); err != nil {
+				return err
+			}
 		}
 	case e.IsLeaf():
 		log.Debugf("got leaf: %s", e.Name)
-		switch dt {
-		case sdcpb.DataType_ALL:
-		case sdcpb.DataType_CONFIG:
-			if isState(e) {
-				return nil
-			}
-		case sdcpb.DataType_STATE:
-			if !isState(e) {
-				return nil
-			}
+		if !matchDataType(e, dt) {
+			return nil
 		}
-		return [][]*sdcpb.PathElem{{&sdcpb.PathElem{Name: e.Name}}}
+		return fn(append(prefix, &sdcpb.PathElem{Name: e.Name}))
 	case e.IsLeafList():
 		log.Debugf("got leafList: %s", e.Name)
-		switch dt {
-		case sdcpb.DataType_ALL:
-		case sdcpb.DataType_CONFIG:
-			if isState(e) {
-				return nil
-			}
-		case sdcpb.DataType_STATE:
-			if !isState(e) {
-				return nil
-			}
+		if !matchDataType(e, dt) {
+			return nil
 		}
-		return [][]*sdcpb.PathElem{{&sdcpb.PathElem{Name: e.Name}}}
+		return fn(append(prefix, &sdcpb.PathElem{Name: e.Name}))
 	case e.IsList():
 		log.Debugf("got list: %s", e.Name)
 		listPE := &sdcpb.PathElem{Name: e.Name, Key: make(map[string]string)}
@@ -108,37 +113,38 @@ func (sc *Schema) getPathElems(e *yang.Entry, dt sdcpb.DataType) [][]*sdcpb.Path
 			listPE.Key[k] = "*"
 			kmap[k] = struct{}{}
 		}
-
+		branch := append(prefix[:len(prefix):len(prefix)], listPE)
 		for _, c := range e.Dir {
 			if _, ok := kmap[c.Name]; ok {
 				continue
 			}
 			log.Debugf("list parent adding child: %s", c.Name)
-
-			childrenPE := sc.getPathElems(c, dt)
-			for _, cpe := range childrenPE {
-				branch := make([]*sdcpb.PathElem, 0, len(cpe)+1)
-				branch = append(branch, listPE)
-				branch = append(branch, cpe...)
-				rs = append(rs, branch)
+			if err := sc.walkPathElems(c, dt, branch, fn); err != nil {
+				return err
 			}
 		}
 	case e.IsContainer():
 		log.Debugf("got container: %s", e.Name)
 		containerPE := &sdcpb.PathElem{Name: e.Name, Key: make(map[string]string)}
+		branch := append(prefix[:len(prefix):len(prefix)], containerPE)
 		for _, c := range e.Dir {
 			log.Debugf("container parent adding child: %s", c.Name)
-			childrenPE := sc.getPathElems(c, dt)
-
-			for _, cpe := range childrenPE {
-				branch := make([]*sdcpb.PathElem, 0, len(cpe)+1)
-				branch = append(branch, containerPE)
-				branch = append(branch, cpe...)
-				rs = append(rs, branch)
+			if err := sc.walkPathElems(c, dt, branch, fn); err != nil {
+				return err
 			}
 		}
 	}
-	return rs
+	return nil
+}
+
+func matchDataType(e *yang.Entry, dt sdcpb.DataType) bool {
+	switch dt {
+	case sdcpb.DataType_CONFIG:
+		return !isState(e)
+	case sdcpb.DataType_STATE:
+		return isState(e)
+	}
+	return true
 }
 
 func populatePathKeys(e *yang.Entry, p *sdcpb.Path) {
@@ -0,0 +1,202 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// applyFeatures removes the entries with an if-feature statement not satisfied
+// by the features policy of the schema, it returns the number of removed entries.
+func (sc *Schema) applyFeatures() (int, error) {
+	fp := sc.config.Features
+	if fp == nil || fp.Mode == "" || fp.Mode == config.FeaturesEnableAll {
+		return 0, nil
+	}
+	return pruneFeatures(sc.root, sc.featureEnabler())
+}
+
+// featureEnabler returns a func reporting whether a feature
+// of a module is enabled by the features policy of the schema.
+func (sc *Schema) featureEnabler() func(module, feature string) bool {
+	fp := sc.config.Features
+	if fp == nil || fp.Mode == "" || fp.Mode == config.FeaturesEnableAll {
+		return func(string, string) bool { return true }
+	}
+	enabled := make(map[string]struct{}, len(fp.Enabled))
+	for _, f := range fp.Enabled {
+		enabled[f] = struct{}{}
+	}
+	return func(module, feature string) bool {
+		if fp.Mode == config.FeaturesDisableAll {
+			return false
+		}
+		if _, ok := enabled[feature]; ok {
+			return true
+		}
+		_, ok := enabled[module+":"+feature]
+		return ok
+	}
+}
+
+func pruneFeatures(e *yang.Entry, isEnabled func(module, feature string) bool) (int, error) {
+	n := 0
+	for name, c := range e.Dir {
+		ok, err := ifFeaturesSatisfied(c, isEnabled)
+		if err != nil {
+			return n, err
+		}
+		if !ok {
+			delete(e.Dir, name)
+			n++
+			continue
+		}
+		cn, err := pruneFeatures(c, isEnabled)
+		n += cn
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ifFeaturesSatisfied reports whether all the if-feature statements of e are satisfied.
+func ifFeaturesSatisfied(e *yang.Entry, isEnabled func(module, feature string) bool) (bool, error) {
+	for _, expr := range getIfFeature(e) {
+		ok, err := evalIfFeature(expr, func(name string) bool {
+			module, feature := featureModule(e.Node, name)
+			return isEnabled(module, feature)
+		})
+		if err != nil {
+			return false, fmt.Errorf("%s: invalid if-feature %q: %v", e.Path(), expr, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// featureModule returns the module defining the feature name,
+// prefixed or not, referenced by an if-feature statement of node n.
+func featureModule(n yang.Node, name string) (string, string) {
+	prefix, feature := "", name
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		prefix, feature = name[:idx], name[idx+1:]
+	}
+	if n == nil {
+		return prefix, feature
+	}
+	m := yang.FindModuleByPrefix(n, prefix)
+	if m == nil {
+		return prefix, feature
+	}
+	if m.Kind() == "submodule" && m.BelongsTo != nil {
+		return m.BelongsTo.Name, feature
+	}
+	return m.Name, feature
+}
+
+// evalIfFeature evaluates a YANG 1.1 if-feature expression:
+// feature names combined with "not", "and", "or" and parentheses.
+func evalIfFeature(expr string, isEnabled func(string) bool) (bool, error) {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	p := &featureExprParser{tokens: strings.Fields(expr), isEnabled: isEnabled}
+	v, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.i < len(p.tokens) {
+		return false, fmt.Errorf("unexpected %q", p.tokens[p.i])
+	}
+	return v, nil
+}
+
+type featureExprParser struct {
+	tokens    []string
+	i         int
+	isEnabled func(string) bool
+}
+
+func (p *featureExprParser) peek() string {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i]
+	}
+	return ""
+}
+
+func (p *featureExprParser) or() (bool, error) {
+	v, err := p.and()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "or" {
+		p.i++
+		w, err := p.and()
+		if err != nil {
+			return false, err
+		}
+		v = v || w
+	}
+	return v, nil
+}
+
+func (p *featureExprParser) and() (bool, error) {
+	v, err := p.factor()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "and" {
+		p.i++
+		w, err := p.factor()
+		if err != nil {
+			return false, err
+		}
+		v = v && w
+	}
+	return v, nil
+}
+
+func (p *featureExprParser) factor() (bool, error) {
+	switch t := p.peek(); t {
+	case "":
+		return false, fmt.Errorf("unexpected end of expression")
+	case "not":
+		p.i++
+		v, err := p.factor()
+		return !v, err
+	case "(":
+		p.i++
+		v, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ")" {
+			return false, fmt.Errorf("missing ')'")
+		}
+		p.i++
+		return v, nil
+	case ")", "and", "or":
+		return false, fmt.Errorf("unexpected %q", t)
+	default:
+		p.i++
+		return p.isEnabled(t), nil
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+const gitWaitDelay = 5 * time.Second
+
+// gitResolver checks out the git sources of a schema.
+type gitResolver struct{}
+
+func (gitResolver) Type() string { return SourceTypeGit }
+
+func (gitResolver) Root(cfg *config.SchemaConfig) (string, error) {
+	return cfg.Git.Directory, nil
+}
+
+// Fetch clones or fetches the git repository of cfg and checks out its ref,
+// the git commands are killed once ctx is done or the git timeout expires.
+func (gitResolver) Fetch(ctx context.Context, cfg *config.SchemaConfig) (string, error) {
+	dir, err := filepath.Abs(cfg.Git.Directory)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Git.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Git.Timeout)
+		defer cancel()
+	}
+	commit, err := gitCheckout(ctx, cfg.Git.URL, cfg.Git.Ref, dir)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", cfg.Git.URL, err)
+	}
+	return cfg.Git.URL + " at commit " + commit, nil
+}
+
+// gitCheckout makes dir a detached checkout of ref from the repository url,
+// it returns the commit checked out.
+func gitCheckout(ctx context.Context, url, ref, dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		// git clones into a missing or empty directory only
+		if err := os.MkdirAll(filepath.Dir(dir), os.ModePerm); err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, "", "clone", "--quiet", "--no-checkout", url, dir); err != nil {
+			return "", err
+		}
+	} else {
+		if _, err := runGit(ctx, dir, "remote", "set-url", "origin", url); err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, dir, "fetch", "--quiet", "--force", "--tags", "--prune", "origin"); err != nil {
+			return "", err
+		}
+	}
+	commit, err := resolveGitRef(ctx, dir, ref)
+	if err != nil {
+		return "", err
+	}
+	if _, err := runGit(ctx, dir, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// resolveGitRef returns the commit of ref as a remote branch, a tag or a commit.
+func resolveGitRef(ctx context.Context, dir, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	for _, r := range []string{"refs/remotes/origin/" + ref, "refs/tags/" + ref, ref} {
+		commit, err := runGit(ctx, dir, "rev-parse", "--verify", "--quiet", r+"^{commit}")
+		if err == nil {
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("unknown ref %q", ref)
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// the remote helpers git spawns can hold its output open once it is killed
+	cmd.WaitDelay = gitWaitDelay
+	cmd.Dir = dir
+	// never wait on a credentials prompt
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("git %s: %v", args[0], ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
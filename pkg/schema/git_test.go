@@ -0,0 +1,112 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// testRepository returns a git repository with a commit adding a.yang.
+func testRepository(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.yang"), []byte("module a {}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "a.yang"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "a"},
+	} {
+		if _, err := runGit(context.Background(), dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// stalledRemote returns the url of a git daemon accepting connections and never answering.
+func stalledRemote(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { c.Close() })
+		}
+	}()
+	return "git://" + l.Addr().String() + "/yang.git"
+}
+
+func TestGitResolver_Fetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tests := []struct {
+		name string
+		url  func(t *testing.T) string
+		// canceled before the fetch
+		canceled bool
+		timeout  time.Duration
+		wantErr  bool
+	}{
+		{name: "checkout", url: testRepository},
+		{name: "canceled", url: testRepository, canceled: true, wantErr: true},
+		{name: "stalled remote", url: stalledRemote, timeout: 200 * time.Millisecond, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.SchemaConfig{Git: &config.GitSource{
+				URL:       tt.url(t),
+				Directory: filepath.Join(t.TempDir(), "checkout"),
+				Timeout:   tt.timeout,
+			}}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.canceled {
+				cancel()
+			}
+			start := time.Now()
+			_, err := gitResolver{}.Fetch(ctx, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := time.Since(start); d > 10*time.Second {
+				t.Errorf("Fetch() returned after %s", d)
+			}
+			if tt.wantErr {
+				return
+			}
+			if _, err := os.Stat(filepath.Join(cfg.Git.Directory, "a.yang")); err != nil {
+				t.Errorf("checkout: %v", err)
+			}
+		})
+	}
+}
@@ -22,17 +22,22 @@ import (
 	"strings"
 
 	"github.com/mitchellh/go-homedir"
-	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/utils"
 )
 
 func (sc *Schema) readYANGFiles() error {
 	if len(sc.config.Files) == 0 {
 		return nil
 	}
+	sc.modules.ParseOptions.IgnoreSubmoduleCircularDependencies = sc.config.IgnoreSubmoduleCircularDependencies
+	sc.modules.ParseOptions.DeviateOptions.IgnoreDeviateNotSupported = sc.config.IgnoreDeviateNotSupported
+	// keep the uses statements for their when statements
+	sc.modules.ParseOptions.StoreUses = true
 
 	for _, dirpath := range sc.config.Directories {
-		expanded, err := yang.PathsWithModules(dirpath)
+		expanded, err := modulePaths(dirpath)
 		if err != nil {
 			return err
 		}
@@ -48,32 +53,82 @@ func (sc *Schema) readYANGFiles() error {
 		excludeRegexes = append(excludeRegexes, r)
 	}
 
+	perr := &ParseError{}
+	read := make(map[string]bool, len(sc.config.Files))
 MAIN:
 	for _, name := range sc.config.Files {
 		for _, r := range excludeRegexes {
-			if r.MatchString(name) {
+			// excludes are written with forward slashes, whatever the OS
+			if r.MatchString(name) || r.MatchString(filepath.ToSlash(name)) {
 				continue MAIN
 			}
 		}
+		// keep reading to report the errors of all modules
+		read[name] = true
 		err := sc.modules.Read(name)
 		if err != nil {
-			return err
+			logrus.Errorf("schema %s failed with: %v", sc.UniqueName(""), err)
+			perr.Errors = append(perr.Errors, moduleErrors(name, err)...)
 		}
 	}
+	// the deviations of all the modules read are applied while processing
+	for _, name := range sc.config.Deviations {
+		if read[name] {
+			continue
+		}
+		err := sc.modules.Read(name)
+		if err != nil {
+			logrus.Errorf("schema %s failed with: %v", sc.UniqueName(""), err)
+			perr.Errors = append(perr.Errors, moduleErrors(name, err)...)
+		}
+	}
+	if len(perr.Errors) > 0 {
+		return perr
+	}
 
-	if errors := sc.modules.Process(); len(errors) > 0 {
-		es := make([]string, 0, len(errors))
+	// imports found in the directories are read while processing
+	errors := sc.modules.Process()
+	sc.scanImports()
+	if len(errors) > 0 {
 		for _, e := range errors {
-			es = append(es, "- "+e.Error())
 			logrus.Errorf("schema %s failed with: %v", sc.UniqueName(""), e)
+			perr.Errors = append(perr.Errors, sc.positionImportErrors(moduleErrors("", e))...)
+		}
+		for _, w := range sc.composition.Warnings() {
+			logrus.Warnf("schema %s: %s", sc.UniqueName(""), w)
 		}
-		//
-		fErr := fmt.Errorf("yang processing failed with %d error(s):\n%s", len(errors), strings.Join(es, "\n"))
-		return fErr
+		return perr
 	}
 	return nil
 }
 
+// positionImportErrors reports the modules not found at the statements importing them,
+// goyang reports them without a position.
+func (sc *Schema) positionImportErrors(mes []*ModuleError) []*ModuleError {
+	pmes := make([]*ModuleError, 0, len(mes))
+	for _, me := range mes {
+		name, ok := strings.CutPrefix(me.Message, "no such module: ")
+		if me.File != "" || !ok {
+			pmes = append(pmes, me)
+			continue
+		}
+		found := false
+		for _, ui := range sc.composition.UnresolvedImports {
+			if ui.Import != name {
+				continue
+			}
+			if pme := parseModuleError(ui.location + ": " + me.Message); pme != nil {
+				pmes = append(pmes, pme)
+				found = true
+			}
+		}
+		if !found {
+			pmes = append(pmes, me)
+		}
+	}
+	return pmes
+}
+
 func resolveGlobs(globs []string) ([]string, error) {
 	results := make([]string, 0, len(globs))
 	for _, pattern := range globs {
@@ -94,31 +149,44 @@ func resolveGlobs(globs []string) ([]string, error) {
 	return ExpandOSPaths(results)
 }
 
+// walkDir returns the files with extension ext found under path,
+// following symlinked directories.
 func walkDir(path, ext string) ([]string, error) {
 	fs := make([]string, 0)
-	err := filepath.Walk(path,
-		func(path string, _ os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			fi, err := os.Stat(path)
-			if err != nil {
-				return err
-			}
-			switch mode := fi.Mode(); {
-			case mode.IsRegular():
-				if filepath.Ext(path) == ext {
-					fs = append(fs, path)
-				}
-			}
-			return nil
-		})
+	err := utils.WalkFiles(path, func(p string) error {
+		if strings.EqualFold(filepath.Ext(p), ext) {
+			fs = append(fs, p)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return fs, nil
 }
 
+// modulePaths returns the directories under root containing YANG files,
+// like yang.PathsWithModules but following symlinked directories.
+func modulePaths(root string) ([]string, error) {
+	paths := make([]string, 0)
+	seen := make(map[string]struct{})
+	err := utils.WalkFiles(root, func(p string) error {
+		if !utils.IsYANGFile(p) {
+			return nil
+		}
+		dir := filepath.Dir(p)
+		if _, ok := seen[dir]; !ok {
+			seen[dir] = struct{}{}
+			paths = append(paths, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
 func findYangFiles(files []string) ([]string, error) {
 	yfiles := make([]string, 0, len(files))
 	for _, file := range files {
@@ -134,7 +202,7 @@ func findYangFiles(files []string) ([]string, error) {
 			}
 			yfiles = append(yfiles, fls...)
 		case mode.IsRegular():
-			if filepath.Ext(file) == ".yang" {
+			if utils.IsYANGFile(file) {
 				yfiles = append(yfiles, file)
 			}
 		}
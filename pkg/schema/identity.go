@@ -0,0 +1,105 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Identity is an identity of a schema.
+type Identity struct {
+	Name string `json:"name,omitempty"`
+	// module defining the identity, the one a submodule belongs to
+	Module string `json:"module,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// base identities, module qualified
+	Bases       []string `json:"bases,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// QualifiedName returns the module qualified name of the identity,
+// as in an identityref value.
+func (id *Identity) QualifiedName() string {
+	return id.Module + ":" + id.Name
+}
+
+// scanIdentities collects the identities of the parsed modules and submodules,
+// it must run before the modules are released.
+func (sc *Schema) scanIdentities() {
+	byName := make(map[string]*Identity)
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		// modules are indexed by name and by name@revision
+		seen := make(map[*yang.Module]struct{}, len(ms))
+		for _, m := range ms {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			for _, i := range m.Identity {
+				id := &Identity{
+					Name:        i.Name,
+					Module:      owningModule(m),
+					Prefix:      m.GetPrefix(),
+					Description: valueName(i.Description),
+				}
+				for _, b := range i.Base {
+					id.Bases = append(id.Bases, qualifyIdentity(m, b.Name))
+				}
+				sort.Strings(id.Bases)
+				byName[id.QualifiedName()] = id
+			}
+		}
+	}
+	sc.identities = make([]*Identity, 0, len(byName))
+	for _, id := range byName {
+		sc.identities = append(sc.identities, id)
+	}
+	sort.Slice(sc.identities, func(i, j int) bool {
+		return sc.identities[i].QualifiedName() < sc.identities[j].QualifiedName()
+	})
+}
+
+// owningModule returns the name of module m, of the module it belongs to for a submodule.
+func owningModule(m *yang.Module) string {
+	if m.Kind() == "submodule" && m.BelongsTo != nil {
+		return m.BelongsTo.Name
+	}
+	return m.Name
+}
+
+// qualifyIdentity returns the module qualified name of identity name
+// referenced from module m, with its prefix if it has one.
+func qualifyIdentity(m *yang.Module, name string) string {
+	prefix := ""
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		prefix, name = name[:idx], name[idx+1:]
+	}
+	if im := yang.FindModuleByPrefix(m, prefix); im != nil {
+		return owningModule(im) + ":" + name
+	}
+	// unresolved prefix
+	return prefix + ":" + name
+}
+
+// Identities returns the identities of the schema sorted by module qualified name.
+func (sc *Schema) Identities() []*Identity {
+	if sc == nil {
+		return nil
+	}
+	return sc.identities
+}
@@ -41,6 +41,8 @@ func leafListFromYEntry(e *yang.Entry, withDesc bool) *sdcpb.LeafListSchema {
 			ll.IsUserOrdered = e.ListAttr.OrderedBy.Name == "user"
 		}
 	}
+	// explicit or from the type, if any
+	ll.Defaults = e.DefaultValues()
 	if e.Prefix != nil {
 		ll.Prefix = e.Prefix.Name
 	}
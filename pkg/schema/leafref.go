@@ -0,0 +1,73 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// scanLeafrefs collects the data paths of the leafrefs set to
+// require-instance false, the other leafrefs require their target instance.
+func (sc *Schema) scanLeafrefs() {
+	seen := make(map[string]struct{})
+	for _, m := range sc.root.Dir {
+		sc.walkLeafrefs(m, seen)
+	}
+	sc.optionalLeafrefs = make([]string, 0, len(seen))
+	for p := range seen {
+		sc.optionalLeafrefs = append(sc.optionalLeafrefs, p)
+	}
+	sort.Strings(sc.optionalLeafrefs)
+}
+
+func (sc *Schema) walkLeafrefs(e *yang.Entry, seen map[string]struct{}) {
+	if e.Type != nil && optionalInstance(e.Type) {
+		// the first element is the module
+		elems := buildPathUpFromEntry(e).GetElem()[1:]
+		names := make([]string, 0, len(elems))
+		for _, pe := range elems {
+			names = append(names, pe.GetName())
+		}
+		seen["/"+strings.Join(names, "/")] = struct{}{}
+	}
+	for _, ce := range e.Dir {
+		sc.walkLeafrefs(ce, seen)
+	}
+}
+
+// optionalInstance reports whether yt is a leafref, or a union
+// with a leafref member, set to require-instance false.
+func optionalInstance(yt *yang.YangType) bool {
+	switch yt.Kind {
+	case yang.Yleafref:
+		return yt.OptionalInstance
+	case yang.Yunion:
+		for _, ut := range yt.Type {
+			if optionalInstance(ut) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OptionalLeafrefs returns the data paths, e.g. /interface/config/name,
+// of the leafref leaves and leaf-lists set to require-instance false.
+func (s *Schema) OptionalLeafrefs() []string {
+	return s.optionalLeafrefs
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// LibraryModule is a module of a schema as listed by an RFC 8525 YANG library.
+type LibraryModule struct {
+	Name      string `json:"name,omitempty"`
+	Revision  string `json:"revision,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// features of the module enabled by the features policy, sorted
+	Features []string `json:"features,omitempty"`
+	// modules with deviation statements targeting the module, sorted
+	Deviations []string `json:"deviations,omitempty"`
+	// submodules belonging to the module, sorted by name
+	Submodules []*LibrarySubmodule `json:"submodules,omitempty"`
+	// the module is only imported for its definitions:
+	// it has no data nodes, augments, rpcs, notifications nor deviations
+	ImportOnly bool `json:"import-only,omitempty"`
+}
+
+type LibrarySubmodule struct {
+	Name     string `json:"name,omitempty"`
+	Revision string `json:"revision,omitempty"`
+}
+
+// scanLibrary collects the modules of the schema with their features,
+// deviations and submodules. It must run after scanSubmodules and
+// before the modules are released.
+func (sc *Schema) scanLibrary() {
+	isEnabled := sc.featureEnabler()
+	byName := make(map[string]*LibraryModule, len(sc.modules.Modules))
+	seen := make(map[*yang.Module]struct{}, len(sc.modules.Modules))
+	for _, m := range sc.modules.Modules {
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		lm := &LibraryModule{
+			Name:       m.Name,
+			Revision:   m.Current(),
+			ImportOnly: true,
+		}
+		if m.Namespace != nil {
+			lm.Namespace = m.Namespace.Name
+		}
+		byName[m.Name] = lm
+	}
+	imported := make(map[string]struct{})
+	features := make(map[string]map[string]struct{})
+	deviations := make(map[string]map[string]struct{})
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		seen := make(map[*yang.Module]struct{}, len(ms))
+		for _, m := range ms {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			module := owningModule(m)
+			for _, imp := range m.Import {
+				imported[imp.Name] = struct{}{}
+			}
+			if hasDefinitions(m) {
+				if lm, ok := byName[module]; ok {
+					lm.ImportOnly = false
+				}
+			}
+			for _, f := range m.Feature {
+				if isEnabled(module, f.Name) {
+					addTo(features, module, f.Name)
+				}
+			}
+			for _, d := range m.Deviation {
+				if target := deviationTarget(m, d); target != "" {
+					addTo(deviations, target, module)
+				}
+			}
+		}
+	}
+	for _, sm := range sc.submodules {
+		if lm, ok := byName[sm.BelongsTo]; ok {
+			lm.Submodules = append(lm.Submodules, &LibrarySubmodule{Name: sm.Name, Revision: sm.Revision})
+		}
+	}
+	sc.library = make([]*LibraryModule, 0, len(byName))
+	for name, lm := range byName {
+		// a module no other module imports is implemented, as loaded on its own
+		if _, ok := imported[name]; !ok {
+			lm.ImportOnly = false
+		}
+		lm.Features = sortedKeys(features[name])
+		lm.Deviations = sortedKeys(deviations[name])
+		sc.library = append(sc.library, lm)
+	}
+	sort.Slice(sc.library, func(i, j int) bool {
+		return sc.library[i].Name < sc.library[j].Name
+	})
+}
+
+func addTo(m map[string]map[string]struct{}, k, v string) {
+	if m[k] == nil {
+		m[k] = make(map[string]struct{})
+	}
+	m[k][v] = struct{}{}
+}
+
+// hasDefinitions reports whether m defines nodes a server implements.
+func hasDefinitions(m *yang.Module) bool {
+	return len(m.Container) > 0 || len(m.List) > 0 || len(m.Leaf) > 0 || len(m.LeafList) > 0 ||
+		len(m.Choice) > 0 || len(m.Anydata) > 0 || len(m.Anyxml) > 0 || len(m.Uses) > 0 ||
+		len(m.Augment) > 0 || len(m.RPC) > 0 || len(m.Notification) > 0 || len(m.Deviation) > 0
+}
+
+// deviationTarget returns the name of the module defining the node targeted
+// by deviation d of module m, the prefix of the first node of its path.
+func deviationTarget(m *yang.Module, d *yang.Deviation) string {
+	first, _, _ := strings.Cut(strings.TrimPrefix(d.Name, "/"), "/")
+	prefix, _, ok := strings.Cut(first, ":")
+	if !ok {
+		prefix = ""
+	}
+	tm := yang.FindModuleByPrefix(m, prefix)
+	if tm == nil {
+		return ""
+	}
+	return owningModule(tm)
+}
+
+// Library returns the modules of the schema sorted by name.
+func (sc *Schema) Library() []*LibraryModule {
+	if sc == nil {
+		return nil
+	}
+	return sc.library
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/lint"
+)
+
+// runLint runs the configured lint rules, the modules are still loaded.
+func (sc *Schema) runLint() error {
+	l, err := lint.New(sc.config.Lint)
+	if err != nil {
+		return err
+	}
+	var modules []*yang.Module
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		seen := make(map[*yang.Module]struct{}, len(ms))
+		for _, m := range ms {
+			// modules are indexed by name and by name@revision
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			modules = append(modules, m)
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+	sc.lint = l.Run(modules, sc.root)
+	return nil
+}
+
+// Lint returns the lint report of the schema, nil if it is not linted.
+func (s *Schema) Lint() *lint.Report {
+	return s.lint
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Metadata holds the header statements of the modules of a schema
+// along with the warnings raised on their licensing metadata.
+type Metadata struct {
+	Modules  []*ModuleMetadata `json:"modules,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+type ModuleMetadata struct {
+	Name         string `json:"name,omitempty"`
+	Revision     string `json:"revision,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Contact      string `json:"contact,omitempty"`
+	// SPDX identifier of the license found in the module description
+	License string `json:"license,omitempty"`
+}
+
+var spdxRegexp = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+()\- ]+?)\s*(?:$|\n|")`)
+
+// license texts commonly referenced in module descriptions and their SPDX identifier
+var licenseTexts = []struct {
+	text string
+	spdx string
+}{
+	{text: "Simplified BSD License", spdx: "BSD-2-Clause"},
+	{text: "Revised BSD License", spdx: "BSD-3-Clause"},
+	{text: "Apache License, Version 2.0", spdx: "Apache-2.0"},
+	{text: "MIT License", spdx: "MIT"},
+}
+
+// scanMetadata collects the metadata of the parsed modules,
+// it must run before the modules are released.
+func (sc *Schema) scanMetadata() {
+	md := &Metadata{Modules: make([]*ModuleMetadata, 0, len(sc.modules.Modules))}
+	// modules are indexed by name and by name@revision
+	seen := make(map[*yang.Module]struct{}, len(sc.modules.Modules))
+	licenses := make(map[string][]string)
+	for _, m := range sc.modules.Modules {
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		mm := &ModuleMetadata{
+			Name:         m.Name,
+			Revision:     m.Current(),
+			Organization: valueName(m.Organization),
+			Contact:      valueName(m.Contact),
+			License:      findLicense(valueName(m.Description)),
+		}
+		md.Modules = append(md.Modules, mm)
+		licenses[mm.License] = append(licenses[mm.License], mm.Name)
+	}
+	sort.Slice(md.Modules, func(i, j int) bool {
+		return md.Modules[i].Name < md.Modules[j].Name
+	})
+	for _, mm := range md.Modules {
+		if mm.License == "" {
+			md.Warnings = append(md.Warnings, fmt.Sprintf("module %s: missing license", mm.Name))
+		}
+		if mm.Organization == "" {
+			md.Warnings = append(md.Warnings, fmt.Sprintf("module %s: missing organization", mm.Name))
+		}
+	}
+	delete(licenses, "")
+	if len(licenses) > 1 {
+		ls := make([]string, 0, len(licenses))
+		for l, ms := range licenses {
+			ls = append(ls, fmt.Sprintf("%s (%d modules)", l, len(ms)))
+		}
+		sort.Strings(ls)
+		md.Warnings = append(md.Warnings, "mixed licenses: "+strings.Join(ls, ", "))
+	}
+	sc.metadata = md
+}
+
+func valueName(v *yang.Value) string {
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(v.Name)
+}
+
+// findLicense returns the SPDX identifier of the license stated in the description d.
+func findLicense(d string) string {
+	if m := spdxRegexp.FindStringSubmatch(d); m != nil {
+		return m[1]
+	}
+	// descriptions are wrapped over multiple lines
+	d = strings.Join(strings.Fields(d), " ")
+	for _, lt := range licenseTexts {
+		if strings.Contains(d, lt.text) {
+			return lt.spdx
+		}
+	}
+	return ""
+}
+
+// Metadata returns the module metadata of the schema,
+// nil if scanning it is not enabled.
+func (sc *Schema) Metadata() *Metadata {
+	if sc == nil {
+		return nil
+	}
+	return sc.metadata
+}
@@ -70,14 +70,39 @@ func (sc *Schema) GetEntry(pe []string) (*yang.Entry, error) {
 		return getEntry(e, pe[offset:])
 	}
 	// skip first level modules and try their children
-	for _, child := range sc.root.Dir {
-		if cc, ok := child.Dir[first]; ok {
-			return getEntry(cc, pe[offset:])
-		}
+	cc, err := sc.findTopLevelEntry(first)
+	if err != nil {
+		return nil, err
+	}
+	if cc != nil {
+		return getEntry(cc, pe[offset:])
+	}
+	// a submodule name resolves to the module it belongs to
+	if e, ok := sc.root.Dir[sc.SubmoduleModule(first)]; ok && e != nil {
+		return getEntry(e, pe[offset:])
 	}
 	return nil, fmt.Errorf("entry %q not found", pe[0])
 }
 
+// findTopLevelEntry looks up name in the top level nodes of all modules.
+// It returns nil if no module defines it and an *AmbiguousPathError
+// if more than one module does.
+func (sc *Schema) findTopLevelEntry(name string) (*yang.Entry, error) {
+	var found *yang.Entry
+	candidates := make([]string, 0, 1)
+	for moduleName, m := range sc.root.Dir {
+		if cc, ok := m.Dir[name]; ok {
+			found = cc
+			candidates = append(candidates, moduleName+":"+name)
+		}
+	}
+	if len(candidates) > 1 {
+		sort.Strings(candidates)
+		return nil, &AmbiguousPathError{Elem: name, Candidates: candidates}
+	}
+	return found, nil
+}
+
 func getEntry(e *yang.Entry, pe []string) (*yang.Entry, error) {
 	log.Tracef("getEntry %s Dir=%v, Choice=%v, Case=%v, %v",
 		e.Name,
@@ -148,12 +173,13 @@ func (sc *Schema) BuildPath(pe []string, p *sdcpb.Path) error {
 		return fmt.Errorf("elem %q not found in module %q", pe[0], first)
 	}
 	// try children
-	for _, e := range sc.root.Dir {
-		if ee, ok := e.Dir[pe[0]]; ok {
-			return sc.buildPath(pe, p, ee)
-		}
+	ee, err := sc.findTopLevelEntry(pe[0])
+	if err != nil {
+		return err
+	}
+	if ee != nil {
+		return sc.buildPath(pe, p, ee)
 	}
-
 	return nil
 }
 
@@ -407,11 +433,16 @@ func (sc *Schema) GetEntryCh(pe []string, ch chan *yang.Entry) error {
 		return getEntryCh(e, pe[offset:], ch)
 	}
 	// skip first level modules and try their children
-	for _, child := range sc.root.Dir {
-		if cc, ok := child.Dir[first]; ok {
-			ch <- cc
-			return getEntryCh(cc, pe[offset:], ch)
-		}
+	cc, err := sc.findTopLevelEntry(first)
+	if err != nil {
+		return err
+	}
+	if cc != nil {
+		ch <- cc
+		return getEntryCh(cc, pe[offset:], ch)
+	}
+	if e, ok := sc.root.Dir[sc.SubmoduleModule(first)]; ok && e != nil {
+		return getEntryCh(e, pe[offset:], ch)
 	}
 	return fmt.Errorf("entry %q not found", pe[0])
 }
@@ -446,7 +477,6 @@ func getEntryCh(e *yang.Entry, pe []string, ch chan *yang.Entry) error {
 			return errors.New("not found")
 		}
 		for _, ee := range getChildren(e) {
-			// fmt.Printf("entry %s, child %s | %s\n", e.Name, ee.Name, pe)
 			if ee.Name != pe[0] {
 				continue
 			}
@@ -459,7 +489,6 @@ func getEntryCh(e *yang.Entry, pe []string, ch chan *yang.Entry) error {
 			ch <- ee
 			return getEntryCh(ee, pe[1:], ch)
 		}
-		// fmt.Println("entry name", e.Name, pe)
 		return fmt.Errorf("%q not found", pe[0])
 	}
 }
@@ -777,3 +777,59 @@ func Test_getChildren(t *testing.T) {
 		})
 	}
 }
+
+func TestSchema_findTopLevelEntry(t *testing.T) {
+	newModule := func(name string, children ...string) *yang.Entry {
+		m := &yang.Entry{Name: name, Kind: yang.DirectoryEntry, Dir: map[string]*yang.Entry{}}
+		for _, c := range children {
+			m.Dir[c] = &yang.Entry{Node: &yang.Container{}, Kind: yang.DirectoryEntry, Name: c, Parent: m}
+		}
+		return m
+	}
+	sc := &Schema{
+		root: &yang.Entry{
+			Name: RootName,
+			Kind: yang.DirectoryEntry,
+			Dir: map[string]*yang.Entry{
+				"mod1": newModule("mod1", "interface", "system"),
+				"mod2": newModule("mod2", "interface"),
+				"mod3": newModule("mod3", "acl"),
+			},
+		},
+	}
+	tests := []struct {
+		name           string
+		elem           string
+		wantName       string
+		wantCandidates []string
+	}{
+		{name: "unique", elem: "system", wantName: "system"},
+		{name: "not_found", elem: "qos"},
+		{name: "ambiguous", elem: "interface", wantCandidates: []string{"mod1:interface", "mod2:interface"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sc.findTopLevelEntry(tt.elem)
+			if tt.wantCandidates != nil {
+				aErr, ok := err.(*AmbiguousPathError)
+				if !ok {
+					t.Fatalf("findTopLevelEntry() error = %v, want *AmbiguousPathError", err)
+				}
+				if !reflect.DeepEqual(aErr.Candidates, tt.wantCandidates) {
+					t.Errorf("findTopLevelEntry() candidates = %v, want %v", aErr.Candidates, tt.wantCandidates)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findTopLevelEntry() unexpected error = %v", err)
+			}
+			var gotName string
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.wantName {
+				t.Errorf("findTopLevelEntry() = %q, want %q", gotName, tt.wantName)
+			}
+		})
+	}
+}
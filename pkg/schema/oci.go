@@ -0,0 +1,471 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	annotationTitle = "org.opencontainers.image.title"
+
+	defaultOCIRegistry = "registry-1.docker.io"
+
+	maxOCIManifestSize = 4 * 1024 * 1024
+	// size of the token endpoint responses
+	maxOCITokenSize = 1024 * 1024
+	// bytes written unpacking the sources of a schema, the layers or archive
+	// they are fetched as are bounded by it too
+	maxSourcesSize = 1024 * 1024 * 1024
+)
+
+// the only digest algorithm supported, the digests are checked
+var ociDigestRe = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+var ociClient = &http.Client{Timeout: 5 * time.Minute}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	MediaType string           `json:"mediaType"`
+	Layers    []*ociDescriptor `json:"layers"`
+	// set for an index
+	Manifests []*ociDescriptor `json:"manifests"`
+}
+
+// ociResolver pulls the OCI artifact of a schema into its directory.
+type ociResolver struct{}
+
+func (ociResolver) Type() string { return SourceTypeOCI }
+
+func (ociResolver) Root(cfg *config.SchemaConfig) (string, error) {
+	if cfg.OCI.Directory == "" {
+		return "", errors.New("oci source has no directory")
+	}
+	return cfg.OCI.Directory, nil
+}
+
+// Fetch pulls the artifact of cfg, the registry requests are canceled with ctx.
+func (r ociResolver) Fetch(ctx context.Context, cfg *config.SchemaConfig) (string, error) {
+	dir, err := r.Root(cfg)
+	if err != nil {
+		return "", err
+	}
+	ref, err := parseOCIReference(cfg.OCI.Reference)
+	if err != nil {
+		return "", err
+	}
+	reg := &ociRegistry{ref: ref, src: cfg.OCI}
+	digest, err := reg.pull(ctx, dir)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", cfg.OCI.Reference, err)
+	}
+	return cfg.OCI.Reference + " at digest " + digest, nil
+}
+
+type ociReference struct {
+	registry   string
+	repository string
+	// tag or digest
+	reference string
+}
+
+// parseOCIReference parses [registry/]repository[:tag][@digest],
+// a reference without registry is a Docker Hub one.
+func parseOCIReference(s string) (*ociReference, error) {
+	ref := &ociReference{reference: "latest"}
+	name := s
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		ref.reference = name[idx+1:]
+		name = name[:idx]
+	} else if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		ref.reference = name[idx+1:]
+		name = name[:idx]
+	}
+	// the first element is a registry if it looks like a host
+	if idx := strings.Index(name, "/"); idx >= 0 && strings.ContainsAny(name[:idx], ".:") || strings.HasPrefix(name, "localhost/") {
+		ref.registry = name[:idx]
+		name = name[idx+1:]
+	} else {
+		ref.registry = defaultOCIRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	if name == "" || ref.reference == "" {
+		return nil, fmt.Errorf("invalid oci reference %q", s)
+	}
+	if strings.Contains(ref.reference, ":") && !ociDigestRe.MatchString(ref.reference) {
+		return nil, fmt.Errorf("invalid oci reference %q: only sha256 digests are supported", s)
+	}
+	ref.repository = name
+	return ref, nil
+}
+
+// ociRegistry pulls an artifact from a registry implementing the OCI distribution API.
+type ociRegistry struct {
+	ref *ociReference
+	src *config.OCISource
+	// authorization header value, set after the first challenge
+	auth string
+}
+
+// pull unpacks the layers of the artifact into dir and returns the digest of its manifest.
+func (r *ociRegistry) pull(ctx context.Context, dir string) (string, error) {
+	m, digest, err := r.manifest(ctx, r.ref.reference)
+	if err != nil {
+		return "", err
+	}
+	if len(m.Manifests) > 0 {
+		// YANG bundles are platform independent, any manifest of an index does
+		if err := checkDigest(m.Manifests[0].Digest); err != nil {
+			return "", err
+		}
+		m, digest, err = r.manifest(ctx, m.Manifests[0].Digest)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(m.Layers) == 0 {
+		return "", errors.New("artifact has no layers")
+	}
+	for _, l := range m.Layers {
+		if err := checkDigest(l.Digest); err != nil {
+			return "", err
+		}
+	}
+	return digest, replaceDir(dir, func(tmp string) error {
+		b := newSourcesBudget()
+		for _, l := range m.Layers {
+			if err := r.unpack(ctx, tmp, l, b); err != nil {
+				return fmt.Errorf("layer %s: %v", l.Digest, err)
+			}
+		}
+		return nil
+	})
+}
+
+func checkDigest(d string) error {
+	if !ociDigestRe.MatchString(d) {
+		return fmt.Errorf("unsupported digest %q, expecting a sha256 one", d)
+	}
+	return nil
+}
+
+func (r *ociRegistry) manifest(ctx context.Context, reference string) (*ociManifest, string, error) {
+	rsp, err := r.get(ctx, "manifests/"+reference,
+		strings.Join([]string{mediaTypeOCIManifest, mediaTypeOCIIndex, mediaTypeDockerManifest, mediaTypeDockerList}, ", "))
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(rsp.Body, maxOCIManifestSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(b) > maxOCIManifestSize {
+		return nil, "", fmt.Errorf("manifest exceeds %d bytes", maxOCIManifestSize)
+	}
+	digest := "sha256:" + hex.EncodeToString(sha256Sum(b))
+	if strings.HasPrefix(reference, "sha256:") && reference != digest {
+		return nil, "", fmt.Errorf("manifest digest mismatch: got %s", digest)
+	}
+	m := new(ociManifest)
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid manifest: %v", err)
+	}
+	return m, digest, nil
+}
+
+// unpack extracts the tar layer l into dir, or copies it as a file named after its title.
+// The bytes written are taken from b.
+func (r *ociRegistry) unpack(ctx context.Context, dir string, l *ociDescriptor, b *sourcesBudget) error {
+	if l.Size < 0 || l.Size > b.left {
+		return fmt.Errorf("layer size %d exceeds the %d bytes left for the sources", l.Size, b.left)
+	}
+	rsp, err := r.get(ctx, "blobs/"+l.Digest, "")
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	// the blob is stored next to dir before it is unpacked to check its digest
+	f, err := os.CreateTemp(filepath.Dir(dir), filepath.Base(dir)+".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(rsp.Body, l.Size+1))
+	if err != nil {
+		return err
+	}
+	if n != l.Size {
+		return fmt.Errorf("size mismatch: got %d bytes or more, expecting %d", n, l.Size)
+	}
+	if d := "sha256:" + hex.EncodeToString(h.Sum(nil)); d != l.Digest {
+		return fmt.Errorf("digest mismatch: got %s", d)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	switch {
+	case strings.Contains(l.MediaType, "gzip"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return untar(dir, gz, b)
+	case strings.Contains(l.MediaType, "tar"):
+		return untar(dir, f, b)
+	}
+	title := l.Annotations[annotationTitle]
+	if title == "" {
+		return fmt.Errorf("layer of media type %q has no %s annotation", l.MediaType, annotationTitle)
+	}
+	name, err := utils.JoinUnder(dir, title)
+	if err != nil {
+		return err
+	}
+	return writeFile(name, f, b)
+}
+
+// sourcesBudget is the number of bytes left to write the sources of a schema.
+type sourcesBudget struct {
+	left int64
+}
+
+func newSourcesBudget() *sourcesBudget {
+	return &sourcesBudget{left: maxSourcesSize}
+}
+
+func untar(dir string, rd io.Reader, b *sourcesBudget) error {
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		name, err := utils.JoinUnder(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		// links and special files are not needed for YANG sources
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(name, os.ModePerm)
+		case tar.TypeReg:
+			err = writeFile(name, tr, b)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeFile writes the content of rd to file name, taking its size from b.
+func writeFile(name string, rd io.Reader, b *sourcesBudget) error {
+	err := os.MkdirAll(filepath.Dir(name), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(rd, b.left+1))
+	b.left -= n
+	if err == nil && b.left < 0 {
+		err = fmt.Errorf("sources exceed %d bytes", maxSourcesSize)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func sha256Sum(b []byte) []byte {
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
+
+// get fetches path under the repository, answering an authentication challenge if needed.
+func (r *ociRegistry) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	scheme := "https"
+	if r.src.PlainHTTP {
+		scheme = "http"
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", scheme, r.ref.registry, r.ref.repository, path)
+	for i := 0; ; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if r.auth != "" {
+			req.Header.Set("Authorization", r.auth)
+		}
+		rsp, err := ociClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if rsp.StatusCode == http.StatusOK {
+			return rsp, nil
+		}
+		b, _ := io.ReadAll(io.LimitReader(rsp.Body, 1024))
+		rsp.Body.Close()
+		if rsp.StatusCode == http.StatusUnauthorized && i == 0 {
+			err = r.authenticate(ctx, rsp.Header.Get("WWW-Authenticate"))
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return nil, fmt.Errorf("GET %s: %s: %s", u, rsp.Status, strings.TrimSpace(string(b)))
+	}
+}
+
+// authenticate sets the authorization answering challenge, a basic
+// or a bearer token one as specified by the Docker token authentication.
+func (r *ociRegistry) authenticate(ctx context.Context, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	user, password, err := r.credentials()
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if user == "" {
+			return errors.New("registry requires credentials")
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(user, password)
+		r.auth = req.Header.Get("Authorization")
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	u, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("invalid registry authentication realm %q", params["realm"])
+	}
+	q := u.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + r.ref.repository + ":pull"
+	}
+	q.Set("scope", scope)
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+	rsp, err := ociClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request: %s", rsp.Status)
+	}
+	tk := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(io.LimitReader(rsp.Body, maxOCITokenSize)).Decode(&tk)
+	if err != nil {
+		return fmt.Errorf("invalid registry token response: %v", err)
+	}
+	if tk.Token == "" {
+		tk.Token = tk.AccessToken
+	}
+	r.auth = "Bearer " + tk.Token
+	return nil
+}
+
+func (r *ociRegistry) credentials() (string, string, error) {
+	if r.src.Username == "" {
+		return "", "", nil
+	}
+	if r.src.PasswordFile == "" {
+		return r.src.Username, "", nil
+	}
+	// read on each pull for a rotated password to be used
+	b, err := os.ReadFile(r.src.PasswordFile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the registry password file: %v", err)
+	}
+	return r.src.Username, strings.TrimSpace(string(b)), nil
+}
+
+// parseChallenge parses a WWW-Authenticate header: scheme key="value", ...
+func parseChallenge(h string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(h), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var k, v string
+		k, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			v, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			v, rest, _ = strings.Cut(rest, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(k))] = v
+	}
+	return scheme, params
+}
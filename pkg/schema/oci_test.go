@@ -0,0 +1,194 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+func Test_parseOCIReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		ref     string
+		want    ociReference
+		wantErr bool
+	}{
+		{ref: "ghcr.io/org/yangs:24.3.1", want: ociReference{registry: "ghcr.io", repository: "org/yangs", reference: "24.3.1"}},
+		{ref: "yangs", want: ociReference{registry: defaultOCIRegistry, repository: "library/yangs", reference: "latest"}},
+		{ref: "localhost:5000/yangs@" + digest, want: ociReference{registry: "localhost:5000", repository: "yangs", reference: digest}},
+		{ref: "ghcr.io/org/yangs@sha512:" + strings.Repeat("ab", 64), wantErr: true},
+		{ref: "ghcr.io/org/yangs@sha256:../../x", wantErr: true},
+		{ref: "ghcr.io/org/yangs@", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := parseOCIReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_untar(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]int
+		left    int64
+		wantErr bool
+	}{
+		{name: "within budget", files: map[string]int{"a.yang": 100, "b/c.yang": 100}, left: 200},
+		{name: "over budget", files: map[string]int{"a.yang": 100, "b/c.yang": 101}, left: 200, wantErr: true},
+		{name: "outside the directory", files: map[string]int{"../a.yang": 1}, left: 200, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			tw := tar.NewWriter(buf)
+			for name, size := range tt.files {
+				if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o600, Size: int64(size)}); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := tw.Write(bytes.Repeat([]byte("x"), size)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			err := untar(t.TempDir(), buf, &sourcesBudget{left: tt.left})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// testRegistry serves the artifact yangs:1 made of the a.yang layer, with the
+// bearer tokens returned by token once the client authenticates.
+// A stalled registry does not answer the manifest requests until the client gives up.
+func testRegistry(t *testing.T, token func(w http.ResponseWriter), stalled bool) *httptest.Server {
+	t.Helper()
+	layer := []byte("module a {}")
+	layerDigest := "sha256:" + hex.EncodeToString(sha256Sum(layer))
+	manifest, err := json.Marshal(&ociManifest{
+		MediaType: mediaTypeOCIManifest,
+		Layers: []*ociDescriptor{{
+			MediaType:   "application/yang",
+			Digest:      layerDigest,
+			Size:        int64(len(layer)),
+			Annotations: map[string]string{annotationTitle: "a.yang"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			token(w)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/yangs/manifests/1":
+			if stalled {
+				<-r.Context().Done()
+				return
+			}
+			w.Header().Set("Content-Type", mediaTypeOCIManifest)
+			_, _ = w.Write(manifest)
+		case "/v2/yangs/blobs/" + layerDigest:
+			_, _ = w.Write(layer)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOCIResolver_Fetch(t *testing.T) {
+	validToken := func(w http.ResponseWriter) { _, _ = w.Write([]byte(`{"token":"t"}`)) }
+	tests := []struct {
+		name    string
+		token   func(w http.ResponseWriter)
+		stalled bool
+		wantErr bool
+	}{
+		{name: "pull", token: validToken},
+		{name: "stalled registry", token: validToken, stalled: true, wantErr: true},
+		{
+			name: "token response too large",
+			token: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"token":"t","padding":"`))
+				_, _ = w.Write(bytes.Repeat([]byte("x"), maxOCITokenSize))
+				_, _ = w.Write([]byte(`"}`))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := testRegistry(t, tt.token, tt.stalled)
+			cfg := &config.SchemaConfig{OCI: &config.OCISource{
+				Reference: strings.TrimPrefix(srv.URL, "http://") + "/yangs:1",
+				PlainHTTP: true,
+				Directory: filepath.Join(t.TempDir(), "yangs"),
+			}}
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			_, err := ociResolver{}.Fetch(ctx, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := time.Since(start); d > 10*time.Second {
+				t.Errorf("Fetch() returned after %s", d)
+			}
+			if tt.wantErr {
+				return
+			}
+			b, err := os.ReadFile(filepath.Join(cfg.OCI.Directory, "a.yang"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != "module a {}" {
+				t.Errorf("a.yang = %q", b)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// scanOrders collects the declared order of the child data nodes of the
+// data nodes by data path, e.g. /interface/subinterface, see Order.
+func (sc *Schema) scanOrders() {
+	sc.orders = make(map[string][]string)
+	mods := make([]string, 0, len(sc.root.Dir))
+	for name := range sc.root.Dir {
+		mods = append(mods, name)
+	}
+	sort.Strings(mods)
+	var top []string
+	for _, name := range mods {
+		m := sc.root.Dir[name]
+		for _, c := range declaredOrder(m) {
+			top = append(top, name+":"+c)
+		}
+		for _, e := range m.Dir {
+			sc.walkOrders(e, nil)
+		}
+	}
+	sc.orders["/"] = top
+}
+
+// walkOrders records the order of the children of entry e,
+// names is the data path of its parent.
+func (sc *Schema) walkOrders(e *yang.Entry, names []string) {
+	if !e.IsChoice() && !e.IsCase() {
+		names = append(names[:len(names):len(names)], e.Name)
+		if e.IsDir() {
+			sc.orders["/"+strings.Join(names, "/")] = declaredOrder(e)
+		}
+	}
+	for _, ce := range e.Dir {
+		sc.walkOrders(ce, names)
+	}
+}
+
+// declaredOrder returns the names of the child data nodes of e in the order
+// they are defined in, the list keys first in the order of the key statement.
+// The children augmenting e follow, by module, and the ones not found by name.
+func declaredOrder(e *yang.Entry) []string {
+	children := make(map[string]struct{})
+	for _, c := range getChildren(e) {
+		children[c.Name] = struct{}{}
+	}
+	order := make([]string, 0, len(children))
+	add := func(name string) {
+		if _, ok := children[name]; ok {
+			delete(children, name)
+			order = append(order, name)
+		}
+	}
+	for _, k := range strings.Fields(e.Key) {
+		add(k)
+	}
+	if e.Node != nil {
+		walkStatements(e.Node.Statement(), e.Node, add, 0)
+	}
+	augs := append([]*yang.Entry(nil), e.Augmented...)
+	sort.SliceStable(augs, func(i, j int) bool {
+		return augmentModule(augs[i]) < augmentModule(augs[j])
+	})
+	for _, a := range augs {
+		if a.Node != nil {
+			walkStatements(a.Node.Statement(), a.Node, add, 0)
+		}
+	}
+	rest := make([]string, 0, len(children))
+	for name := range children {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+// max nesting of the groupings used in groupings
+const maxUsesDepth = 32
+
+// walkStatements calls add with the names of the data nodes defined by the
+// sub statements of st, n is the node of st the groupings are found from.
+func walkStatements(st *yang.Statement, n yang.Node, add func(string), depth int) {
+	if st == nil || depth > maxUsesDepth {
+		return
+	}
+	for _, ss := range st.SubStatements() {
+		switch ss.Keyword {
+		case "container", "list", "leaf", "leaf-list", "anydata", "anyxml":
+			add(ss.Argument)
+		case "choice", "case":
+			walkStatements(ss, n, add, depth)
+		case "uses":
+			g := yang.FindGrouping(n, ss.Argument, map[string]bool{})
+			if g != nil {
+				walkStatements(g.Statement(), g, add, depth+1)
+			}
+		}
+	}
+}
+
+func augmentModule(a *yang.Entry) string {
+	if a.Node == nil {
+		return ""
+	}
+	if m := yang.RootNode(a.Node); m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// Order returns the names of the child data nodes of the data node at path p,
+// e.g. /interface/subinterface, in their declared order, nil if it has none.
+// The top-level nodes, at path /, are module qualified and ordered by module.
+func (s *Schema) Order(p string) []string {
+	return s.orders[p]
+}
+
+// Orders returns the declared order of the child data nodes by data path, see Order.
+func (s *Schema) Orders() map[string][]string {
+	return s.orders
+}
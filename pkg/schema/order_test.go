@@ -0,0 +1,89 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+const orderModule = `module a {
+  namespace "urn:a";
+  prefix a;
+  grouping g {
+    leaf m { type string; }
+  }
+  container c {
+    leaf z { type string; }
+    uses g;
+    choice ch {
+      leaf b { type string; }
+      case k {
+        leaf a { type string; }
+      }
+    }
+    list l {
+      key "k2 k1";
+      leaf x { type string; }
+      leaf k1 { type string; }
+      leaf k2 { type string; }
+    }
+  }
+  leaf top { type string; }
+}
+`
+
+const orderAugment = `module b {
+  namespace "urn:b";
+  prefix b;
+  import a { prefix a; }
+  augment "/a:c" {
+    leaf y { type string; }
+  }
+}
+`
+
+func TestOrder(t *testing.T) {
+	dir := t.TempDir()
+	for name, m := range map[string]string{"a.yang": orderModule, "b.yang": orderAugment} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(m), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc, err := NewSchema(&config.SchemaConfig{Name: "a", Vendor: "v", Version: "1", Files: []string{dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/", want: []string{"a:c", "a:top"}},
+		{path: "/c", want: []string{"z", "m", "b", "a", "l", "y"}},
+		{path: "/c/l", want: []string{"k2", "k1", "x"}},
+		{path: "/c/z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := sc.Order(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"github.com/openconfig/goyang/pkg/yang"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/value"
+)
+
+// compilePatterns compiles the patterns of the schema types ahead of the value
+// validations, the patterns that cannot be translated to Go regular expressions
+// are logged and are reported to the validations using them.
+func (sc *Schema) compilePatterns() {
+	seen := make(map[string]struct{})
+	for _, m := range sc.root.Dir {
+		sc.walkPatterns(m, seen)
+	}
+}
+
+func (sc *Schema) walkPatterns(e *yang.Entry, seen map[string]struct{}) {
+	if e.Type != nil {
+		sc.typePatterns(e, e.Type, seen)
+	}
+	for _, ce := range e.Dir {
+		sc.walkPatterns(ce, seen)
+	}
+}
+
+func (sc *Schema) typePatterns(e *yang.Entry, yt *yang.YangType, seen map[string]struct{}) {
+	for _, p := range yt.Pattern {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		if _, err := value.CompilePattern(p); err != nil {
+			log.Warnf("schema %s: %s: %v", sc.UniqueName(""), e.Path(), err)
+		}
+	}
+	for _, ut := range yt.Type {
+		sc.typePatterns(e, ut, seen)
+	}
+}
@@ -48,6 +48,10 @@ func (sc *Schema) buildReferences(e *yang.Entry) error {
 		// fmt.Println("normalized path:", pes)
 		refEntry, err := sc.GetEntry(pes)
 		if err != nil {
+			if sc.isPruned(pes) {
+				log.Debugf("leafref %s refers to a pruned subtree: %s", e.Path(), e.Type.Path)
+				return nil
+			}
 			return err
 		}
 		// fmt.Println("got refEntry", refEntry.Name)
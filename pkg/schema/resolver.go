@@ -0,0 +1,338 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// built-in source types
+const (
+	// checkout of the schema git repository
+	SourceTypeGit = "git"
+	// OCI artifact pulled from a registry
+	SourceTypeOCI = "oci"
+	// local directory, the path option
+	SourceTypeDir = "dir"
+	// tar archive, gzipped or not, or single YANG file downloaded from the url
+	// option, with the bearer token read from the token-file option if set
+	SourceTypeHTTP = "http"
+)
+
+// SourceResolver fetches the YANG files of the schemas having sources of its type:
+// the git and oci ones of the schema config, or the ones of a source of that type.
+// Integrators add the resolvers of their artifact stores, e.g. one reading the
+// modules of a device, with RegisterSourceResolver or LoadSourcePlugins.
+type SourceResolver interface {
+	// source type, e.g. http
+	Type() string
+	// Root returns the directory the sources of cfg are fetched into, the files,
+	// directories and deviations of cfg are relative to it. It does not fetch them.
+	Root(cfg *config.SchemaConfig) (string, error)
+	// Fetch fetches the sources of cfg into their root, each time the schema is
+	// parsed. It returns the version fetched, e.g. a commit, for the logs.
+	Fetch(ctx context.Context, cfg *config.SchemaConfig) (string, error)
+}
+
+// SourcePluginSymbol is the function a source plugin exports, of type func() []schema.SourceResolver.
+// The resolvers it returns are registered along with the built-in ones, see lint.PluginSymbol
+// for how plugins are built.
+const SourcePluginSymbol = "SourceResolvers"
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]SourceResolver{
+		SourceTypeGit:  gitResolver{},
+		SourceTypeOCI:  ociResolver{},
+		SourceTypeDir:  dirResolver{},
+		SourceTypeHTTP: httpResolver{},
+	}
+	// serializes the fetches into a source root, a channel of capacity 1 per root
+	// so that the fetches waiting for a hung one give up with their context
+	sourceLocks sync.Map
+)
+
+// RegisterSourceResolver adds the resolver of a source type,
+// it is called before the schemas are loaded.
+func RegisterSourceResolver(r SourceResolver) error {
+	if r.Type() == "" {
+		return errors.New("source resolver without type")
+	}
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	if _, ok := resolvers[r.Type()]; ok {
+		return fmt.Errorf("source resolver %q is already registered", r.Type())
+	}
+	resolvers[r.Type()] = r
+	return nil
+}
+
+// SourceTypes returns the registered source types, sorted.
+func SourceTypes() []string {
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	ts := make([]string, 0, len(resolvers))
+	for t := range resolvers {
+		ts = append(ts, t)
+	}
+	sort.Strings(ts)
+	return ts
+}
+
+// LoadSourcePlugins registers the source resolvers of the plugin files,
+// it is called once, before the schemas are loaded.
+func LoadSourcePlugins(files []string) error {
+	for _, f := range files {
+		p, err := plugin.Open(f)
+		if err != nil {
+			return fmt.Errorf("source plugin %s: %v", f, err)
+		}
+		sym, err := p.Lookup(SourcePluginSymbol)
+		if err != nil {
+			return fmt.Errorf("source plugin %s: %v", f, err)
+		}
+		fn, ok := sym.(func() []SourceResolver)
+		if !ok {
+			return fmt.Errorf("source plugin %s: %s is a %T, not a func() []schema.SourceResolver", f, SourcePluginSymbol, sym)
+		}
+		for _, r := range fn() {
+			if err := RegisterSourceResolver(r); err != nil {
+				return fmt.Errorf("source plugin %s: %v", f, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sourceType returns the type of the sources of cfg, empty if its files are local.
+func sourceType(cfg *config.SchemaConfig) string {
+	switch {
+	case cfg.Git != nil:
+		return SourceTypeGit
+	case cfg.OCI != nil:
+		return SourceTypeOCI
+	case cfg.Source != nil:
+		return cfg.Source.Type
+	}
+	return ""
+}
+
+// sourceResolver returns the resolver of the sources of cfg, nil if its files are local.
+func sourceResolver(cfg *config.SchemaConfig) (SourceResolver, error) {
+	t := sourceType(cfg)
+	if t == "" {
+		return nil, nil
+	}
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	r, ok := resolvers[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown source type %q", t)
+	}
+	return r, nil
+}
+
+// sourceRoot returns the absolute root directory of the sources of cfg,
+// empty if its files are local.
+func sourceRoot(cfg *config.SchemaConfig) (SourceResolver, string, error) {
+	r, err := sourceResolver(cfg)
+	if r == nil {
+		return nil, "", err
+	}
+	root, err := r.Root(cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s sources: %v", r.Type(), err)
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, "", err
+	}
+	return r, root, nil
+}
+
+// fetchSources fetches the sources of cfg, the files and directories
+// of cfg relative to their root are made absolute.
+// It is a noop if the files of cfg are local.
+func fetchSources(ctx context.Context, cfg *config.SchemaConfig) error {
+	r, root, err := sourceRoot(cfg)
+	if r == nil {
+		return err
+	}
+	l, _ := sourceLocks.LoadOrStore(root, make(chan struct{}, 1))
+	lock := l.(chan struct{})
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("%s sources: %v", r.Type(), ctx.Err())
+	}
+	defer func() { <-lock }()
+
+	version, err := r.Fetch(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("%s sources: %v", r.Type(), err)
+	}
+	log.Infof("schema %s@%s@%s: fetched %s sources %s", cfg.Name, cfg.Vendor, cfg.Version, r.Type(), version)
+	// the paths are already absolute when the schema is parsed again
+	for _, ps := range [][]string{cfg.Files, cfg.Directories, cfg.Deviations} {
+		for i, p := range ps {
+			if !filepath.IsAbs(p) {
+				ps[i] = filepath.Join(root, p)
+			}
+		}
+	}
+	return nil
+}
+
+// replaceDir replaces dir with the directory filled by fn, the sources
+// of a previous fetch are kept if fn fails.
+func replaceDir(dir string, fn func(tmp string) error) error {
+	err := os.MkdirAll(filepath.Dir(dir), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	err = fn(tmp)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+// dirResolver reads the sources from a local directory.
+type dirResolver struct{}
+
+func (dirResolver) Type() string { return SourceTypeDir }
+
+func (dirResolver) Root(cfg *config.SchemaConfig) (string, error) {
+	dir := cfg.Source.Options["path"]
+	if dir == "" {
+		return "", errors.New("missing path option")
+	}
+	return dir, nil
+}
+
+func (r dirResolver) Fetch(_ context.Context, cfg *config.SchemaConfig) (string, error) {
+	dir, err := r.Root(cfg)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+var httpSourceClient = &http.Client{Timeout: 5 * time.Minute}
+
+// httpResolver downloads the sources into the source directory.
+type httpResolver struct{}
+
+func (httpResolver) Type() string { return SourceTypeHTTP }
+
+func (httpResolver) Root(cfg *config.SchemaConfig) (string, error) {
+	if cfg.Source.Directory == "" {
+		return "", errors.New("http source has no directory")
+	}
+	return cfg.Source.Directory, nil
+}
+
+func (r httpResolver) Fetch(ctx context.Context, cfg *config.SchemaConfig) (string, error) {
+	u, err := url.Parse(cfg.Source.Options["url"])
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid url option %q", cfg.Source.Options["url"])
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if tf := cfg.Source.Options["token-file"]; tf != "" {
+		b, err := os.ReadFile(tf)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(b)))
+	}
+	rsp, err := httpSourceClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", u.Redacted(), rsp.Status)
+	}
+	root, err := r.Root(cfg)
+	if err != nil {
+		return "", err
+	}
+	err = replaceDir(root, func(tmp string) error {
+		b := newSourcesBudget()
+		name := path.Base(u.Path)
+		ct := rsp.Header.Get("Content-Type")
+		switch {
+		case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") || strings.Contains(ct, "gzip"):
+			gz, err := gzip.NewReader(rsp.Body)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			return untar(tmp, gz, b)
+		case strings.HasSuffix(name, ".tar") || strings.Contains(ct, "x-tar"):
+			return untar(tmp, rsp.Body, b)
+		}
+		fn, err := utils.JoinUnder(tmp, name)
+		if err != nil {
+			return err
+		}
+		return writeFile(fn, rsp.Body, b)
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", u.Redacted(), err)
+	}
+	version := u.Redacted()
+	if etag := rsp.Header.Get("ETag"); etag != "" {
+		version += " at ETag " + etag
+	}
+	return version, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// hangingResolver fetches until the context of the fetch is done,
+// started is closed once a fetch started.
+type hangingResolver struct {
+	root    string
+	started chan struct{}
+}
+
+func (r *hangingResolver) Type() string                              { return "test-hanging" }
+func (r *hangingResolver) Root(*config.SchemaConfig) (string, error) { return r.root, nil }
+
+func (r *hangingResolver) Fetch(ctx context.Context, _ *config.SchemaConfig) (string, error) {
+	close(r.started)
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func Test_fetchSources_context(t *testing.T) {
+	r := &hangingResolver{root: t.TempDir(), started: make(chan struct{})}
+	if err := RegisterSourceResolver(r); err != nil {
+		t.Fatal(err)
+	}
+	cfg := func() *config.SchemaConfig {
+		return &config.SchemaConfig{Name: "sc", Vendor: "v", Version: "1", Source: &config.SourceConfig{Type: r.Type()}}
+	}
+
+	// the first fetch hangs holding the root lock
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- fetchSources(ctx, cfg()) }()
+	<-r.started
+
+	// a fetch waiting for the lock gives up with its context
+	wctx, wcancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer wcancel()
+	if err := fetchSources(wctx, cfg()); err == nil {
+		t.Fatal("waiting fetchSources() error = nil, want the context error")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("hanging fetchSources() error = nil, want the context error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("hanging fetchSources() did not return once its context was canceled")
+	}
+}
@@ -15,20 +15,30 @@
 package schema
 
 import (
+	"context"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/openconfig/goyang/pkg/yang"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
 )
 
 const (
 	RootName = "__root__"
 )
 
+var tracer = otel.Tracer("github.com/sdcio/schema-server/pkg/schema")
+
 type Schema struct {
 	config *config.SchemaConfig
 
@@ -36,9 +46,90 @@ type Schema struct {
 	root    *yang.Entry
 	modules *yang.Modules
 	status  string
+	// set if the module metadata scanning is enabled
+	metadata *Metadata
+	// issues found assembling the modules
+	composition *Composition
+	// set if the schema is linted
+	lint *lint.Report
+	// data paths of the leafrefs not requiring an instance
+	optionalLeafrefs []string
+	// when statements by data path
+	whens map[string]*WhenStatements
+	// declared order of the child data nodes by data path
+	orders map[string][]string
+	// submodules sorted by name
+	submodules []*Submodule
+	// identities sorted by module qualified name
+	identities []*Identity
+	// metadata annotations sorted by module qualified name
+	annotations []*Annotation
+	// enabled features and deviation modules
+	variant *Variant
+	// origin of the defaults by data path
+	defaultOrigins map[string]*DefaultOrigin
+	// digest of the source files
+	sourcesDigest string
+	// files of the modules and submodules read, sorted
+	moduleFiles []string
+	// top-level nodes pruned by the subtrees of the config
+	pruned map[string]struct{}
+	// modules sorted by name, as listed by a YANG library
+	library []*LibraryModule
+	// node counts and parse duration
+	stats *Stats
+}
+
+// SourcePaths returns the absolute paths of the files and directories the schema of cfg
+// is read from, relative to the root of its git, OCI or other sources if it has some.
+// The sources are not fetched.
+func SourcePaths(cfg *config.SchemaConfig) ([]string, error) {
+	_, root, err := sourceRoot(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var ps []string
+	for _, p := range append(append(append([]string(nil), cfg.Files...), cfg.Directories...), cfg.Deviations...) {
+		if !filepath.IsAbs(p) && root != "" {
+			p = filepath.Join(root, p)
+		}
+		ap, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, ap)
+	}
+	return ps, nil
 }
 
 func NewSchema(sCfg *config.SchemaConfig) (*Schema, error) {
+	return NewSchemaContext(context.Background(), sCfg)
+}
+
+// NewSchemaContext is NewSchema, the span of the parsing being a child of the one of ctx,
+// the remote sources are fetched with ctx.
+func NewSchemaContext(ctx context.Context, sCfg *config.SchemaConfig) (*Schema, error) {
+	_, span := tracer.Start(ctx, "schema.parse", trace.WithAttributes(
+		attribute.String("schema.name", sCfg.Name),
+		attribute.String("schema.vendor", sCfg.Vendor),
+		attribute.String("schema.version", sCfg.Version),
+	))
+	defer span.End()
+	sc, err := newSchema(ctx, sCfg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return sc, err
+	}
+	st := sc.stats
+	span.SetAttributes(
+		attribute.Int("schema.modules", st.Modules),
+		attribute.Int("schema.nodes", st.Containers+st.Lists+st.Leaves+st.LeafLists),
+	)
+	return sc, nil
+}
+
+func newSchema(ctx context.Context, sCfg *config.SchemaConfig) (*Schema, error) {
 	sc := &Schema{
 		config:  sCfg,
 		m:       new(sync.RWMutex),
@@ -46,17 +137,32 @@ func NewSchema(sCfg *config.SchemaConfig) (*Schema, error) {
 		modules: yang.NewModules(),
 	}
 	now := time.Now()
-	var err error
+	err := fetchSources(ctx, sCfg)
+	if err != nil {
+		sc.status = "failed"
+		return sc, err
+	}
 	sCfg.Files, err = findYangFiles(sCfg.Files)
 	if err != nil {
 		sc.status = "failed"
 		return sc, err
 	}
+	sCfg.Deviations, err = findYangFiles(sCfg.Deviations)
+	if err != nil {
+		sc.status = "failed"
+		return sc, err
+	}
+	sc.sourcesDigest, err = sourcesDigest(sCfg)
+	if err != nil {
+		sc.status = "failed"
+		return sc, err
+	}
 	err = sc.readYANGFiles()
 	if err != nil {
 		sc.status = "failed"
 		return sc, err
 	}
+	sc.moduleFiles = sc.readFiles()
 	sc.root = &yang.Entry{
 		Name: RootName,
 		Kind: yang.DirectoryEntry,
@@ -71,13 +177,73 @@ func NewSchema(sCfg *config.SchemaConfig) (*Schema, error) {
 		e := yang.ToEntry(m)
 		sc.root.Dir[e.Name] = e
 	}
+	n, err := sc.applyFeatures()
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		log.Infof("schema %s: %d node(s) removed by the %s features policy", sc.UniqueName(""), n, sCfg.Features.Mode)
+	}
+	n, unused := sc.applySubtrees()
+	if n > 0 {
+		log.Infof("schema %s: %d top-level node(s) pruned, restricted to %v", sc.UniqueName(""), n, sCfg.Subtrees)
+	}
+	for _, st := range unused {
+		log.Warnf("schema %s: subtree %s selects no top-level node", sc.UniqueName(""), st)
+	}
+	sc.scanComposition()
+	if ws := sc.composition.Warnings(); len(ws) > 0 {
+		log.Warnf("schema %s: %d composition warning(s)", sc.UniqueName(""), len(ws))
+		for _, w := range ws {
+			log.Warnf("schema %s: %s", sc.UniqueName(""), w)
+		}
+	}
+	sc.scanSubmodules()
+	sc.scanLibrary()
+	sc.scanIdentities()
+	sc.scanAnnotations()
+	sc.scanVariant()
+	if ds := sc.variant.Deviations; len(ds) > 0 {
+		log.Infof("schema %s: deviated by %v", sc.UniqueName(""), ds)
+	}
+	if sCfg.ScanMetadata {
+		sc.scanMetadata()
+		for _, w := range sc.metadata.Warnings {
+			log.Warnf("schema %s: %s", sc.UniqueName(""), w)
+		}
+	}
+	if sCfg.DescriptionLanguage != "" {
+		err = sc.selectDescriptions(sCfg.DescriptionLanguage)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sCfg.Lint != nil {
+		err = sc.runLint()
+		if err != nil {
+			return nil, err
+		}
+		if len(sc.lint.Diagnostics) > 0 {
+			log.Infof("schema %s: %d lint diagnostic(s)", sc.UniqueName(""), len(sc.lint.Diagnostics))
+			for _, d := range sc.lint.Diagnostics {
+				log.Debugf("schema %s: %s: %s [%s]", sc.UniqueName(""), d.Location, d.Message, d.Rule)
+			}
+		}
+	}
+	sc.scanLeafrefs()
+	sc.scanDefaultOrigins()
+	sc.scanWhens()
+	sc.scanOrders()
+	sc.compilePatterns()
 	log.Infof("schema %s building references", sc.UniqueName(""))
 	err = sc.buildReferencesAnnotation()
 	if err != nil {
 		return nil, err
 	}
+	sc.scanStats()
 	sc.status = "ok"
-	log.Infof("schema %s parsed in %s", sc.UniqueName(""), time.Since(now))
+	sc.stats.ParseDuration = time.Since(now)
+	log.Infof("schema %s parsed in %s", sc.UniqueName(""), sc.stats.ParseDuration)
 	sc.modules = nil
 	return sc, nil
 }
@@ -130,6 +296,41 @@ func (s *Schema) Excludes() []string {
 	return s.config.Excludes
 }
 
+// Modules returns the sorted names of the modules of the schema,
+// the imported ones included.
+func (s *Schema) Modules() []string {
+	names := make([]string, 0, len(s.root.Dir))
+	for name := range s.root.Dir {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EntryErrors returns the errors found building the nodes of the schema, e.g. an unknown type.
+// They do not fail the parsing of the schema, the nodes are kept as built.
+// The nodes defined more than once are not included, they are part of the Composition.
+func (s *Schema) EntryErrors() []*ModuleError {
+	var mes []*ModuleError
+	for _, name := range s.Modules() {
+		for _, err := range s.root.Dir[name].GetErrors() {
+			first, rest, _ := strings.Cut(err.Error(), "\n")
+			if strings.Contains(first, "Duplicate node") || strings.Contains(first, "duplicate key from") {
+				continue
+			}
+			me := parseModuleError(first)
+			if me == nil {
+				me = &ModuleError{Message: first}
+			}
+			if rest != "" {
+				me.Message += "\n" + rest
+			}
+			mes = append(mes, me)
+		}
+	}
+	return mes
+}
+
 func (s *Schema) Walk(e *yang.Entry, fn func(ec *yang.Entry) error) error {
 	if e == nil {
 		e = s.root
@@ -0,0 +1,107 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// SourcesDigest returns a digest of the YANG files a schema config points to:
+// the module files and the dependency files, identified by path and content,
+// and of the config fields changing the parsed schema, see parseConfig.
+// Git, OCI and other remote sources are fetched first.
+func SourcesDigest(cfg *config.SchemaConfig) (string, error) {
+	return SourcesDigestContext(context.Background(), cfg)
+}
+
+// SourcesDigestContext is SourcesDigest, the remote sources being fetched with ctx.
+func SourcesDigestContext(ctx context.Context, cfg *config.SchemaConfig) (string, error) {
+	if sourceType(cfg) != "" {
+		rcfg := *cfg
+		rcfg.Files = append([]string(nil), cfg.Files...)
+		rcfg.Directories = append([]string(nil), cfg.Directories...)
+		rcfg.Deviations = append([]string(nil), cfg.Deviations...)
+		if err := fetchSources(ctx, &rcfg); err != nil {
+			return "", err
+		}
+		cfg = &rcfg
+	}
+	return sourcesDigest(cfg)
+}
+
+func sourcesDigest(cfg *config.SchemaConfig) (string, error) {
+	files, err := findYangFiles(cfg.Files)
+	if err != nil {
+		return "", err
+	}
+	// dependencies can be files or directories
+	deps, err := findYangFiles(cfg.Directories)
+	if err != nil {
+		return "", err
+	}
+	files = append(files, deps...)
+	devs, err := findYangFiles(cfg.Deviations)
+	if err != nil {
+		return "", err
+	}
+	files = append(files, devs...)
+	sort.Strings(files)
+	h := sha256.New()
+	b, err := json.Marshal(parseConfig(cfg))
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "%s\x00", b)
+	for i, f := range files {
+		if i > 0 && f == files[i-1] {
+			continue
+		}
+		b, err = os.ReadFile(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%x\x00", f, sha256.Sum256(b))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// parseConfig returns a copy of cfg without the files, digested by path and content
+// once expanded, and without the fields applied when serving the schema or fetching its sources.
+func parseConfig(cfg *config.SchemaConfig) *config.SchemaConfig {
+	pcfg := *cfg
+	pcfg.Files = nil
+	pcfg.Directories = nil
+	pcfg.Deviations = nil
+	pcfg.Git = nil
+	pcfg.OCI = nil
+	pcfg.Source = nil
+	pcfg.Refresh = nil
+	pcfg.Limits = nil
+	pcfg.TargetProfiles = nil
+	return &pcfg
+}
+
+// SourcesDigest returns the digest of the files the schema was parsed from, see SourcesDigest.
+func (s *Schema) SourcesDigest() string {
+	return s.sourcesDigest
+}
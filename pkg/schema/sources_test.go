@@ -0,0 +1,66 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+func TestSourcesDigest(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "a.yang")
+	if err := os.WriteFile(f, []byte("module a { namespace a; prefix a; }"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	base := func() *config.SchemaConfig {
+		return &config.SchemaConfig{Name: "a", Vendor: "v", Version: "1", Files: []string{dir}}
+	}
+	want, err := sourcesDigest(base())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		set     func(*config.SchemaConfig)
+		changed bool
+	}{
+		{name: "excludes", set: func(c *config.SchemaConfig) { c.Excludes = []string{"x"} }, changed: true},
+		{name: "features", set: func(c *config.SchemaConfig) { c.Features = &config.FeaturesPolicy{Mode: config.FeaturesDisableAll} }, changed: true},
+		{name: "profile", set: func(c *config.SchemaConfig) { c.Profile = "srl" }, changed: true},
+		{name: "subtrees", set: func(c *config.SchemaConfig) { c.Subtrees = []string{"a"} }, changed: true},
+		{name: "description language", set: func(c *config.SchemaConfig) { c.DescriptionLanguage = "fr" }, changed: true},
+		{name: "parser option", set: func(c *config.SchemaConfig) { c.IgnoreDeviateNotSupported = true }, changed: true},
+		{name: "expanded files", set: func(c *config.SchemaConfig) { c.Files = []string{f} }},
+		{name: "limits", set: func(c *config.SchemaConfig) { c.Limits = &config.SchemaLimits{MaxDepth: 1} }},
+		{name: "refresh", set: func(c *config.SchemaConfig) { c.Refresh = &config.RefreshConfig{} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			tt.set(cfg)
+			got, err := sourcesDigest(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed := got != want; changed != tt.changed {
+				t.Errorf("digest changed: %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Stats are the sizes of a schema and the cost of loading it.
+type Stats struct {
+	Modules int `json:"modules,omitempty"`
+	// data nodes, choices and cases excluded
+	Containers  int `json:"containers,omitempty"`
+	Lists       int `json:"lists,omitempty"`
+	Leaves      int `json:"leaves,omitempty"`
+	LeafLists   int `json:"leaf-lists,omitempty"`
+	ConfigNodes int `json:"config-nodes,omitempty"`
+	StateNodes  int `json:"state-nodes,omitempty"`
+	// levels of data nodes below the modules
+	MaxDepth      int           `json:"max-depth,omitempty"`
+	ParseDuration time.Duration `json:"parse-duration,omitempty"`
+	// estimate of the memory taken by the schema tree, in bytes:
+	// the entries and their names, descriptions and types
+	MemoryEstimate int64 `json:"memory-estimate,omitempty"`
+}
+
+var (
+	entrySize = int64(unsafe.Sizeof(yang.Entry{}))
+	typeSize  = int64(unsafe.Sizeof(yang.YangType{}))
+)
+
+// scanStats counts the nodes of the schema tree, the parse duration is set once parsed.
+func (sc *Schema) scanStats() {
+	sc.stats = &Stats{Modules: len(sc.library)}
+	for _, m := range sc.root.Dir {
+		sc.stats.MemoryEstimate += entrySize + int64(len(m.Name)+len(m.Description))
+		for _, e := range m.Dir {
+			sc.walkStats(e, 1)
+		}
+	}
+}
+
+func (sc *Schema) walkStats(e *yang.Entry, depth int) {
+	// rpcs and notifications are not data nodes
+	if e.RPC != nil || e.Kind == yang.NotificationEntry {
+		return
+	}
+	st := sc.stats
+	st.MemoryEstimate += entrySize + int64(len(e.Name)+len(e.Description))
+	if e.Type != nil {
+		st.MemoryEstimate += typeSize
+	}
+	if e.IsChoice() || e.IsCase() {
+		// not a level of the data tree
+		depth--
+	} else {
+		switch {
+		case e.IsList():
+			st.Lists++
+		case e.IsDir():
+			st.Containers++
+		case e.IsLeafList():
+			st.LeafLists++
+		default:
+			st.Leaves++
+		}
+		if isState(e) {
+			st.StateNodes++
+		} else {
+			st.ConfigNodes++
+		}
+		if depth > st.MaxDepth {
+			st.MaxDepth = depth
+		}
+	}
+	for _, ce := range e.Dir {
+		sc.walkStats(ce, depth+1)
+	}
+}
+
+// Stats returns the sizes of the schema.
+func (sc *Schema) Stats() *Stats {
+	if sc == nil {
+		return nil
+	}
+	return sc.stats
+}
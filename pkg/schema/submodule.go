@@ -0,0 +1,116 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Submodule describes a submodule of a schema and its place
+// in the include hierarchy.
+type Submodule struct {
+	Name     string `json:"name,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	// module the submodule belongs to
+	BelongsTo string `json:"belongs-to,omitempty"`
+	// submodules included by the submodule
+	Includes []string `json:"includes,omitempty"`
+	// modules and submodules including the submodule
+	IncludedBy []string `json:"included-by,omitempty"`
+}
+
+// scanSubmodules collects the submodules of the parsed modules,
+// it must run before the modules are released.
+func (sc *Schema) scanSubmodules() {
+	byName := make(map[string]*Submodule, len(sc.modules.SubModules))
+	// submodules are indexed by name and by name@revision
+	seen := make(map[*yang.Module]struct{}, len(sc.modules.SubModules))
+	for _, m := range sc.modules.SubModules {
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		sm := &Submodule{
+			Name:     m.Name,
+			Revision: m.Current(),
+			Includes: includes(m),
+		}
+		if m.BelongsTo != nil {
+			sm.BelongsTo = m.BelongsTo.Name
+		}
+		byName[m.Name] = sm
+	}
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		seen := make(map[*yang.Module]struct{}, len(ms))
+		for _, m := range ms {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			for _, name := range includes(m) {
+				if sm, ok := byName[name]; ok {
+					sm.IncludedBy = append(sm.IncludedBy, m.Name)
+				}
+			}
+		}
+	}
+	sc.submodules = make([]*Submodule, 0, len(byName))
+	for _, sm := range byName {
+		sort.Strings(sm.IncludedBy)
+		sc.submodules = append(sc.submodules, sm)
+	}
+	sort.Slice(sc.submodules, func(i, j int) bool {
+		return sc.submodules[i].Name < sc.submodules[j].Name
+	})
+}
+
+func includes(m *yang.Module) []string {
+	if len(m.Include) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(m.Include))
+	for _, inc := range m.Include {
+		names = append(names, inc.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Submodules returns the submodules of the schema sorted by name.
+func (sc *Schema) Submodules() []*Submodule {
+	if sc == nil {
+		return nil
+	}
+	return sc.submodules
+}
+
+// SubmoduleModule returns the name of the module the submodule name belongs to,
+// an empty string if the schema has no such submodule.
+func (sc *Schema) SubmoduleModule(name string) string {
+	return SubmoduleModule(sc.Submodules(), name)
+}
+
+// SubmoduleModule returns the name of the module the submodule name
+// belongs to in sms, an empty string if sms has no such submodule.
+func SubmoduleModule(sms []*Submodule, name string) string {
+	for _, sm := range sms {
+		if sm.Name == name {
+			return sm.BelongsTo
+		}
+	}
+	return ""
+}
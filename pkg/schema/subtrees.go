@@ -0,0 +1,74 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+)
+
+// applySubtrees prunes the top-level nodes not selected by the subtrees
+// of the schema config, it returns the number of nodes removed and the
+// subtrees selecting none.
+func (sc *Schema) applySubtrees() (int, []string) {
+	subtrees := sc.config.Subtrees
+	if len(subtrees) == 0 {
+		return 0, nil
+	}
+	used := make([]bool, len(subtrees))
+	sc.pruned = make(map[string]struct{})
+	n := 0
+	for module, me := range sc.root.Dir {
+		for name := range me.Dir {
+			selected := false
+			for i, st := range subtrees {
+				if subtreeSelects(st, module, name) {
+					used[i] = true
+					selected = true
+				}
+			}
+			if !selected {
+				delete(me.Dir, name)
+				sc.pruned[name] = struct{}{}
+				n++
+			}
+		}
+	}
+	var unused []string
+	for i, st := range subtrees {
+		if !used[i] {
+			unused = append(unused, st)
+		}
+	}
+	return n, unused
+}
+
+// subtreeSelects reports whether subtree st selects the
+// top-level node name of module.
+func subtreeSelects(st, module, name string) bool {
+	if m, n, ok := strings.Cut(st, ":"); ok {
+		return m == module && n == name
+	}
+	return st == module || st == name
+}
+
+// isPruned reports whether data path pes, as normalized for
+// the leafref references, starts with a pruned top-level node.
+func (sc *Schema) isPruned(pes []string) bool {
+	if len(pes) == 0 || len(sc.pruned) == 0 {
+		return false
+	}
+	_, ok := sc.pruned[pes[0]]
+	return ok
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// Variant describes how a schema differs from its modules as published:
+// the features enabled by its features policy and the modules deviating it.
+type Variant struct {
+	// enabled features as "module:feature", sorted
+	Features []string `json:"features,omitempty"`
+	// modules with deviation statements, sorted
+	Deviations []string `json:"deviations,omitempty"`
+	// top-level nodes the schema is restricted to, as configured
+	Subtrees []string `json:"subtrees,omitempty"`
+}
+
+// scanVariant collects the enabled features and the deviation modules,
+// it must run before the modules are released.
+func (sc *Schema) scanVariant() {
+	isEnabled := sc.featureEnabler()
+	features := make(map[string]struct{})
+	deviations := make(map[string]struct{})
+	for _, ms := range []map[string]*yang.Module{sc.modules.Modules, sc.modules.SubModules} {
+		for _, m := range ms {
+			module := owningModule(m)
+			for _, f := range m.Feature {
+				if isEnabled(module, f.Name) {
+					features[module+":"+f.Name] = struct{}{}
+				}
+			}
+			if len(m.Deviation) > 0 {
+				deviations[module] = struct{}{}
+			}
+		}
+	}
+	sc.variant = &Variant{
+		Features:   sortedKeys(features),
+		Deviations: sortedKeys(deviations),
+		Subtrees:   sc.config.Subtrees,
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// Variant returns the enabled features and the deviation modules of the schema.
+func (sc *Schema) Variant() *Variant {
+	if sc == nil {
+		return nil
+	}
+	return sc.variant
+}
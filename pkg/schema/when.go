@@ -0,0 +1,104 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+)
+
+// WhenStatements are the when statements a data node depends on.
+type WhenStatements struct {
+	// when of the node itself, the node is its context node
+	Node string `json:"node,omitempty"`
+	// when of the choices, cases, augments and uses the node is defined in,
+	// from the closest one. The parent data node is their context node.
+	Parent []string `json:"parent,omitempty"`
+}
+
+// scanWhens collects the when statements of the data nodes by data path,
+// e.g. /interface/subinterface.
+func (sc *Schema) scanWhens() {
+	sc.whens = make(map[string]*WhenStatements)
+	for _, m := range sc.root.Dir {
+		for _, e := range m.Dir {
+			sc.walkWhens(e, nil)
+		}
+	}
+}
+
+// walkWhens records the when statements of entry e,
+// names is the data path of its parent.
+func (sc *Schema) walkWhens(e *yang.Entry, names []string) {
+	if !e.IsChoice() && !e.IsCase() {
+		names = append(names[:len(names):len(names)], e.Name)
+		ws := new(WhenStatements)
+		if w, ok := e.GetWhenXPath(); ok {
+			ws.Node = w
+		}
+		ws.Parent = parentWhens(e)
+		if ws.Node != "" || len(ws.Parent) > 0 {
+			sc.whens["/"+strings.Join(names, "/")] = ws
+		}
+	}
+	for _, ce := range e.Dir {
+		sc.walkWhens(ce, names)
+	}
+}
+
+// parentWhens returns the when statements of the choices and cases between e
+// and its parent data node, and of the augments and uses e comes from.
+func parentWhens(e *yang.Entry) []string {
+	var ws []string
+	child := e
+	for p := e.Parent; p != nil; p = p.Parent {
+		for _, a := range p.Augmented {
+			if _, ok := a.Dir[child.Name]; !ok {
+				continue
+			}
+			if w, ok := a.GetWhenXPath(); ok {
+				ws = append(ws, w)
+			}
+		}
+		for _, u := range p.Uses {
+			if u.Uses.When == nil || u.Grouping == nil {
+				continue
+			}
+			if _, ok := u.Grouping.Dir[child.Name]; ok {
+				ws = append(ws, u.Uses.When.Name)
+			}
+		}
+		if !p.IsChoice() && !p.IsCase() {
+			break
+		}
+		if w, ok := p.GetWhenXPath(); ok {
+			ws = append(ws, w)
+		}
+		child = p
+	}
+	return ws
+}
+
+// When returns the when statements of the data node at path p,
+// e.g. /interface/subinterface, nil if it has none.
+func (s *Schema) When(p string) *WhenStatements {
+	return s.whens[p]
+}
+
+// Whens returns the when statements of the data nodes by data path.
+func (s *Schema) Whens() map[string]*WhenStatements {
+	return s.whens
+}
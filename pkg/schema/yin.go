@@ -0,0 +1,379 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// yinNamespace is the namespace of the YIN elements, RFC 7950 section 13.
+const yinNamespace = "urn:ietf:params:xml:ns:yang:yin:1"
+
+// yinArgument is how the argument of a statement is mapped to YIN:
+// an attribute, or a child element if element is set.
+type yinArgument struct {
+	name    string
+	element bool
+}
+
+// yinArguments maps the YANG statements to the name of their argument in YIN,
+// the statements without an argument are left out. RFC 7950 section 13.1.
+var yinArguments = map[string]yinArgument{
+	"action":           {name: "name"},
+	"anydata":          {name: "name"},
+	"anyxml":           {name: "name"},
+	"argument":         {name: "name"},
+	"augment":          {name: "target-node"},
+	"base":             {name: "name"},
+	"belongs-to":       {name: "module"},
+	"bit":              {name: "name"},
+	"case":             {name: "name"},
+	"choice":           {name: "name"},
+	"config":           {name: "value"},
+	"contact":          {name: "text", element: true},
+	"container":        {name: "name"},
+	"default":          {name: "value"},
+	"description":      {name: "text", element: true},
+	"deviate":          {name: "value"},
+	"deviation":        {name: "target-node"},
+	"enum":             {name: "name"},
+	"error-app-tag":    {name: "value"},
+	"error-message":    {name: "value", element: true},
+	"extension":        {name: "name"},
+	"feature":          {name: "name"},
+	"fraction-digits":  {name: "value"},
+	"grouping":         {name: "name"},
+	"identity":         {name: "name"},
+	"if-feature":       {name: "name"},
+	"import":           {name: "module"},
+	"include":          {name: "module"},
+	"key":              {name: "value"},
+	"leaf":             {name: "name"},
+	"leaf-list":        {name: "name"},
+	"length":           {name: "value"},
+	"list":             {name: "name"},
+	"mandatory":        {name: "value"},
+	"max-elements":     {name: "value"},
+	"min-elements":     {name: "value"},
+	"modifier":         {name: "value"},
+	"module":           {name: "name"},
+	"must":             {name: "condition"},
+	"namespace":        {name: "uri"},
+	"notification":     {name: "name"},
+	"ordered-by":       {name: "value"},
+	"organization":     {name: "text", element: true},
+	"path":             {name: "value"},
+	"pattern":          {name: "value"},
+	"position":         {name: "value"},
+	"prefix":           {name: "value"},
+	"presence":         {name: "value"},
+	"range":            {name: "value"},
+	"reference":        {name: "text", element: true},
+	"refine":           {name: "target-node"},
+	"require-instance": {name: "value"},
+	"revision":         {name: "date"},
+	"revision-date":    {name: "date"},
+	"rpc":              {name: "name"},
+	"status":           {name: "value"},
+	"submodule":        {name: "name"},
+	"type":             {name: "name"},
+	"typedef":          {name: "name"},
+	"unique":           {name: "tag"},
+	"units":            {name: "name"},
+	"uses":             {name: "name"},
+	"value":            {name: "value"},
+	"when":             {name: "condition"},
+	"yang-version":     {name: "value"},
+	"yin-element":      {name: "value"},
+}
+
+// ModuleNotFoundError is returned when no source file of a schema
+// defines the requested module or submodule.
+type ModuleNotFoundError struct {
+	Module   string
+	Revision string
+}
+
+func (e *ModuleNotFoundError) Error() string {
+	if e.Revision != "" {
+		return fmt.Sprintf("module %s@%s not found in the schema sources", e.Module, e.Revision)
+	}
+	return fmt.Sprintf("module %s not found in the schema sources", e.Module)
+}
+
+// SourceModule is a module or submodule read from a source file of a schema.
+type SourceModule struct {
+	Name string
+	// latest revision, empty if the module has none
+	Revision string
+	File     string
+	stmt     *yang.Statement
+}
+
+// sourceModules are the modules and submodules of source files by name.
+type sourceModules map[string][]*SourceModule
+
+// readSourceModules reads the modules and submodules defined by the
+// source files of the schema of cfg, the sources are not fetched.
+func readSourceModules(cfg *config.SchemaConfig) (sourceModules, error) {
+	paths, err := SourcePaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+	files, err := findYangFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	sms := make(sourceModules)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		stmts, err := yang.Parse(string(b), f)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range stmts {
+			if st.Keyword != "module" && st.Keyword != "submodule" {
+				continue
+			}
+			sm := &SourceModule{Name: st.Argument, File: f, stmt: st}
+			for _, ss := range st.SubStatements() {
+				if ss.Keyword == "revision" && ss.Argument > sm.Revision {
+					sm.Revision = ss.Argument
+				}
+			}
+			sms[sm.Name] = append(sms[sm.Name], sm)
+		}
+	}
+	return sms, nil
+}
+
+// find returns module or submodule name at revision,
+// its latest revision if revision is empty.
+func (sms sourceModules) find(name, revision string) (*SourceModule, error) {
+	var found *SourceModule
+	for _, sm := range sms[name] {
+		switch {
+		case revision != "":
+			if sm.Revision == revision {
+				return sm, nil
+			}
+		case found == nil || sm.Revision > found.Revision:
+			found = sm
+		}
+	}
+	if found == nil {
+		return nil, &ModuleNotFoundError{Module: name, Revision: revision}
+	}
+	return found, nil
+}
+
+// ModuleSource returns the source file of module or submodule name of the schema
+// of cfg, at revision or at its latest one if revision is empty.
+func ModuleSource(cfg *config.SchemaConfig, name, revision string) (*SourceModule, error) {
+	sms, err := readSourceModules(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sms.find(name, revision)
+}
+
+// ModuleYIN returns the YIN document of module or submodule name of the schema
+// of cfg, at revision or at its latest one if revision is empty. The module is
+// read again from the schema sources, the extensions it uses and the namespaces
+// of the modules it imports from the modules found along with it.
+func ModuleYIN(cfg *config.SchemaConfig, name, revision string) (*SourceModule, []byte, error) {
+	sms, err := readSourceModules(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	sm, err := sms.find(name, revision)
+	if err != nil {
+		return nil, nil, err
+	}
+	w := &yinWriter{sms: sms, prefixes: make(map[string]*SourceModule)}
+	w.buf.WriteString(xml.Header)
+	w.statement(sm, sm.stmt, "")
+	return sm, w.buf.Bytes(), nil
+}
+
+// yinWriter writes the statements of a module as YIN elements.
+type yinWriter struct {
+	sms sourceModules
+	// modules by the prefix the written module refers to them with
+	prefixes map[string]*SourceModule
+	buf      bytes.Buffer
+}
+
+// bindPrefixes sets the modules the prefixes of module st refer to and returns
+// the namespace declarations of the YIN root element, sorted by prefix.
+func (w *yinWriter) bindPrefixes(sm *SourceModule, st *yang.Statement) []string {
+	imports := make(map[string]string)
+	for _, ss := range st.SubStatements() {
+		switch ss.Keyword {
+		case "prefix":
+			// the prefix of the module itself
+			imports[ss.Argument] = sm.Name
+			w.prefixes[ss.Argument] = sm
+		case "belongs-to":
+			for _, bs := range ss.SubStatements() {
+				if bs.Keyword == "prefix" {
+					imports[bs.Argument] = ss.Argument
+				}
+			}
+		case "import":
+			revision := ""
+			prefix := ""
+			for _, is := range ss.SubStatements() {
+				switch is.Keyword {
+				case "prefix":
+					prefix = is.Argument
+				case "revision-date":
+					revision = is.Argument
+				}
+			}
+			if prefix == "" {
+				continue
+			}
+			imports[prefix] = ss.Argument
+			if im, err := w.sms.find(ss.Argument, revision); err == nil {
+				w.prefixes[prefix] = im
+			}
+		}
+	}
+	prefixes := make([]string, 0, len(imports))
+	for p := range imports {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	decls := []string{fmt.Sprintf("xmlns=%q", yinNamespace)}
+	for _, p := range prefixes {
+		m := w.prefixes[p]
+		if m == nil {
+			// the module itself or the module a submodule belongs to
+			found, err := w.sms.find(imports[p], "")
+			if err != nil {
+				continue
+			}
+			m = found
+			w.prefixes[p] = m
+		}
+		if ns := moduleNamespace(m.stmt); ns != "" {
+			decls = append(decls, "xmlns:"+p+"=\""+escapeAttr(ns)+"\"")
+		}
+	}
+	return decls
+}
+
+func (w *yinWriter) statement(sm *SourceModule, st *yang.Statement, indent string) {
+	var decls []string
+	if indent == "" {
+		decls = w.bindPrefixes(sm, st)
+	}
+	arg, ok := yinArguments[st.Keyword]
+	if strings.Contains(st.Keyword, ":") {
+		arg, ok = w.extensionArgument(st.Keyword)
+	}
+	w.buf.WriteString(indent + "<" + st.Keyword)
+	if st.HasArgument && ok && !arg.element {
+		w.buf.WriteString(" " + arg.name + "=\"" + escapeAttr(st.Argument) + "\"")
+	}
+	// the namespaces are declared aligned under the first attribute
+	for _, d := range decls {
+		w.buf.WriteString("\n" + indent + strings.Repeat(" ", len(st.Keyword)+2) + d)
+	}
+	elemArg := st.HasArgument && ok && arg.element
+	if !elemArg && len(st.SubStatements()) == 0 {
+		w.buf.WriteString("/>\n")
+		return
+	}
+	w.buf.WriteString(">\n")
+	if elemArg {
+		name := arg.name
+		// the argument element of an extension is in its namespace
+		if i := strings.Index(st.Keyword, ":"); i >= 0 {
+			name = st.Keyword[:i+1] + name
+		}
+		w.buf.WriteString(indent + "  <" + name + ">" + textEscaper.Replace(st.Argument) + "</" + name + ">\n")
+	}
+	for _, ss := range st.SubStatements() {
+		w.statement(sm, ss, indent+"  ")
+	}
+	w.buf.WriteString(indent + "</" + st.Keyword + ">\n")
+}
+
+// extensionArgument returns how the argument of extension keyword prefix:name
+// is mapped, as stated by the extension definition. An extension not found is
+// given its argument as a value attribute.
+func (w *yinWriter) extensionArgument(keyword string) (yinArgument, bool) {
+	prefix, name, _ := strings.Cut(keyword, ":")
+	arg := yinArgument{name: "value"}
+	m := w.prefixes[prefix]
+	if m == nil {
+		return arg, true
+	}
+	for _, ss := range m.stmt.SubStatements() {
+		if ss.Keyword != "extension" || ss.Argument != name {
+			continue
+		}
+		for _, es := range ss.SubStatements() {
+			if es.Keyword != "argument" {
+				continue
+			}
+			arg.name = es.Argument
+			for _, as := range es.SubStatements() {
+				if as.Keyword == "yin-element" {
+					arg.element = as.Argument == "true"
+				}
+			}
+			return arg, true
+		}
+		// the extension takes no argument
+		return arg, false
+	}
+	return arg, true
+}
+
+// moduleNamespace returns the namespace of module st,
+// empty for a submodule.
+func moduleNamespace(st *yang.Statement) string {
+	for _, ss := range st.SubStatements() {
+		if ss.Keyword == "namespace" {
+			return ss.Argument
+		}
+	}
+	return ""
+}
+
+var (
+	// the line breaks of a text argument are kept
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "\n", "&#10;", "\t", "&#9;")
+)
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+func (s *Server) ListAnnotations(ctx context.Context, req *api.ListAnnotationsRequest) (*api.ListAnnotationsResponse, error) {
+	log.Debugf("received ListAnnotations: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	as, err := s.schemaStore.GetSchemaAnnotations(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.ListAnnotationsResponse{
+		Annotations: make([]*api.Annotation, 0, len(as)),
+	}
+	for _, a := range as {
+		if req.Module != "" && a.Module != req.Module {
+			continue
+		}
+		rsp.Annotations = append(rsp.Annotations, &api.Annotation{
+			Name:        a.Name,
+			Module:      a.Module,
+			Prefix:      a.Prefix,
+			Qualified:   a.QualifiedName(),
+			Type:        a.Type,
+			Units:       a.Units,
+			IfFeature:   a.IfFeature,
+			Status:      a.Status,
+			Description: a.Description,
+		})
+	}
+	return rsp, nil
+}
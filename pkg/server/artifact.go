@@ -0,0 +1,93 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/artifact"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) GenerateArtifact(ctx context.Context, req *api.GenerateArtifactRequest) (*api.GenerateArtifactResponse, error) {
+	log.Debugf("received GenerateArtifact: %v", req)
+	_, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Format {
+	case artifact.FormatJSONSchema, artifact.FormatOpenAPI, artifact.FormatProto, artifact.FormatDescriptorSet:
+	case "":
+		return nil, status.Error(codes.InvalidArgument, "missing artifact format")
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown artifact format %q, expecting one of %s", req.Format, strings.Join(artifact.Formats, ", "))
+	}
+	// the document holds the whole subtree
+	err = s.checkSubtreeLimits(ctx, req.Schema, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	if req.WithDescriptions {
+		r = r.WithDescriptions()
+	}
+	lr := &leafrefResolver{r: r}
+	err = lr.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g := &artifact.Generator{
+		Resolver: r,
+		// a leafref takes the values of its target
+		Leafref: func(ctx context.Context, names []string, lt *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+			qnames, err := lr.qualify(ctx, names)
+			if err != nil {
+				return nil
+			}
+			t, err := lr.resolve(ctx, qnames, lt.GetLeafref())
+			if err != nil {
+				log.Debugf("failed to resolve leafref %s: %v", lt.GetLeafref(), err)
+				return nil
+			}
+			return t.Type
+		},
+		Title:        fmt.Sprintf("%s %s %s", req.Schema.GetName(), req.Schema.GetVendor(), req.Schema.GetVersion()),
+		Version:      req.Schema.GetVersion(),
+		Package:      req.Schema.GetName(),
+		Descriptions: req.WithDescriptions,
+		ConfigOnly:   req.ConfigOnly,
+	}
+	doc, err := g.Generate(ctx, req.Format, elemNames(req.Path))
+	if err != nil {
+		// the lookup errors are statuses already, the others are about the request
+		if _, ok := status.FromError(err); !ok {
+			err = status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+	switch req.Format {
+	case artifact.FormatProto, artifact.FormatDescriptorSet:
+		return &api.GenerateArtifactResponse{Content: doc}, nil
+	}
+	return &api.GenerateArtifactResponse{Document: doc}, nil
+}
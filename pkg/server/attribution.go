@@ -0,0 +1,220 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// request metadata propagated into the logs, the metrics exemplars and the journal
+const (
+	requestIDHeader   = "x-request-id"
+	tenantHeader      = "x-tenant"
+	traceparentHeader = "traceparent"
+)
+
+// exemplar label values are truncated to keep the
+// exemplar under the 128 runes limit of OpenMetrics.
+const maxExemplarValue = 48
+
+// max length of the request ID and tenant set by the clients
+const maxAttributionValue = 128
+
+// W3C trace context traceparent: version-traceid-parentid-flags
+var traceparentRegexp = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// attribution identifies a request across systems.
+type attribution struct {
+	// set by the client or generated
+	RequestID   string
+	Tenant      string
+	TraceParent string
+	// subject of the authenticated bearer token
+	Subject string
+	// the Tenant and Subject are the ones of an authenticated bearer token
+	Authenticated bool
+}
+
+type attributionKey struct{}
+
+// newAttribution reads the request attribution from the incoming metadata,
+// a request ID is generated if the client did not send a valid one and the
+// traceparent is the one of the RPC span if the request is traced.
+// The client values end up in the logs, the exemplars, the journal and the
+// error details: the ones that are not short printable ASCII are dropped.
+func newAttribution(ctx context.Context) *attribution {
+	a := new(attribution)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		a.RequestID = firstValue(md, requestIDHeader)
+		if !printableASCII(a.RequestID) {
+			log.Debugf("ignoring invalid %s %q", requestIDHeader, a.RequestID)
+			a.RequestID = ""
+		}
+		a.Tenant = firstValue(md, tenantHeader)
+		if !printableASCII(a.Tenant) {
+			log.Debugf("ignoring invalid %s %q", tenantHeader, a.Tenant)
+			a.Tenant = ""
+		}
+		a.TraceParent = firstValue(md, traceparentHeader)
+		if a.TraceParent != "" && !traceparentRegexp.MatchString(a.TraceParent) {
+			log.Debugf("ignoring invalid %s %q", traceparentHeader, a.TraceParent)
+			a.TraceParent = ""
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		a.TraceParent = fmt.Sprintf("00-%s-%s-%s", sc.TraceID(), sc.SpanID(), sc.TraceFlags())
+	}
+	if a.RequestID == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		a.RequestID = hex.EncodeToString(b)
+	}
+	return a
+}
+
+// printableASCII reports whether s is made of at most
+// maxAttributionValue printable ASCII characters.
+func printableASCII(s string) bool {
+	if len(s) > maxAttributionValue {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func firstValue(md metadata.MD, k string) string {
+	if vs := md.Get(k); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// attributionFromContext returns the attribution of the request ctx belongs to,
+// nil if there is none.
+func attributionFromContext(ctx context.Context) *attribution {
+	a, _ := ctx.Value(attributionKey{}).(*attribution)
+	return a
+}
+
+// traceID returns the trace ID of a W3C traceparent: version-traceid-parentid-flags.
+func (a *attribution) traceID() string {
+	parts := strings.Split(a.TraceParent, "-")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1]
+}
+
+func (a *attribution) fields() log.Fields {
+	f := log.Fields{"request-id": a.RequestID}
+	if a.Tenant != "" {
+		f["tenant"] = a.Tenant
+	}
+	if a.Subject != "" {
+		f["subject"] = a.Subject
+	}
+	if id := a.traceID(); id != "" {
+		f["trace-id"] = id
+	}
+	return f
+}
+
+func (a *attribution) exemplar() prometheus.Labels {
+	l := prometheus.Labels{"request_id": truncate(a.RequestID, maxExemplarValue)}
+	if id := a.traceID(); id != "" {
+		l["trace_id"] = truncate(id, maxExemplarValue)
+	}
+	return l
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+func (s *Server) attributionUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	a := newAttribution(ctx)
+	ctx = context.WithValue(ctx, attributionKey{}, a)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, a.RequestID))
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	return rsp, s.attributed(a, info.FullMethod, start, err)
+}
+
+func (s *Server) attributionStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	a := newAttribution(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(requestIDHeader, a.RequestID))
+	start := time.Now()
+	err := handler(srv, &attributedStream{
+		ServerStream: ss,
+		ctx:          context.WithValue(ss.Context(), attributionKey{}, a),
+	})
+	return s.attributed(a, info.FullMethod, start, err)
+}
+
+// attributed logs and observes a handled request,
+// the request ID is added to the details of the returned error.
+func (s *Server) attributed(a *attribution, method string, start time.Time, err error) error {
+	code := status.Code(err)
+	if s.rpcDuration != nil {
+		o := s.rpcDuration.WithLabelValues(method, code.String())
+		if eo, ok := o.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(time.Since(start).Seconds(), a.exemplar())
+		} else {
+			o.Observe(time.Since(start).Seconds())
+		}
+	}
+	log.WithFields(a.fields()).Debugf("handled %s: %s", method, code)
+	if code == codes.OK {
+		return err
+	}
+	st, derr := status.Convert(err).WithDetails(&errdetails.RequestInfo{RequestId: a.RequestID})
+	if derr != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// attributedStream carries the request attribution in its context.
+type attributedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *attributedStream) Context() context.Context {
+	return s.ctx
+}
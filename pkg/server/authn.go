@@ -0,0 +1,83 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/authn"
+)
+
+const authorizationHeader = "authorization"
+
+// SetAuthenticator sets the Authenticator the bearer tokens are checked with,
+// it replaces the one set from the config. It must be called before Serve.
+func (s *Server) SetAuthenticator(a authn.Authenticator) {
+	s.authenticator = a
+}
+
+// authenticate checks the bearer token of the request,
+// the request attribution takes the tenant of the token.
+func (s *Server) authenticate(ctx context.Context) error {
+	if s.authenticator == nil {
+		return nil
+	}
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		scheme, t, _ := strings.Cut(firstValue(md, authorizationHeader), " ")
+		if strings.EqualFold(scheme, "bearer") {
+			token = strings.TrimSpace(t)
+		}
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	id, err := s.authenticator.Authenticate(ctx, token)
+	if errors.Is(err, authn.ErrUnknownToken) {
+		return status.Error(codes.Unauthenticated, "unknown token")
+	}
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	if a := attributionFromContext(ctx); a != nil {
+		a.Subject = id.Subject
+		a.Tenant = id.Tenant
+		a.Authenticated = true
+	}
+	return nil
+}
+
+func (s *Server) authnUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authnStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/authz"
+)
+
+// SetAuthorizer sets the Authorizer deciding whether each RPC is served,
+// it replaces the one set from the config. It must be called before Serve.
+func (s *Server) SetAuthorizer(a authz.Authorizer) {
+	s.authorizer = a
+}
+
+func (s *Server) authorize(ctx context.Context, method string, req interface{}) error {
+	if s.authorizer == nil {
+		return nil
+	}
+	ar := authzRequest(ctx, method, req)
+	// both schemas of a diff are authorized
+	if dr, ok := req.(*api.DiffSchemaRequest); ok {
+		for _, sc := range []*sdcpb.Schema{dr.From, dr.To} {
+			ar.Schema = sc
+			if err := s.authorizer.Authorize(ctx, ar); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return s.authorizer.Authorize(ctx, ar)
+}
+
+// allowSchema reports whether the client of the request ctx belongs to
+// may be listed schema sc.
+func (s *Server) allowSchema(ctx context.Context, sc *sdcpb.Schema) bool {
+	f, ok := s.authorizer.(authz.SchemaFilter)
+	if !ok {
+		return true
+	}
+	method, _ := grpc.Method(ctx)
+	return f.AllowSchema(authzRequest(ctx, s.canonicalMethod(method), nil), sc)
+}
+
+// filterSchemas removes the schemas the client is not allowed to be listed from
+// the responses listing schemas.
+func (s *Server) filterSchemas(ctx context.Context, rsp interface{}) {
+	if _, ok := s.authorizer.(authz.SchemaFilter); !ok {
+		return
+	}
+	switch rsp := rsp.(type) {
+	case *sdcpb.ListSchemaResponse:
+		schemas := make([]*sdcpb.Schema, 0, len(rsp.GetSchema()))
+		for _, sc := range rsp.GetSchema() {
+			if s.allowSchema(ctx, sc) {
+				schemas = append(schemas, sc)
+			}
+		}
+		rsp.Schema = schemas
+	case *api.ListSchemaStatesResponse:
+		states := make([]*api.SchemaState, 0, len(rsp.States))
+		for _, st := range rsp.States {
+			if s.allowSchema(ctx, st.Schema) {
+				states = append(states, st)
+			}
+		}
+		rsp.States = states
+	case *api.GetSchemaStatsResponse:
+		stats := make([]*api.SchemaStats, 0, len(rsp.Schemas))
+		for _, st := range rsp.Schemas {
+			if s.allowSchema(ctx, st.Schema) {
+				stats = append(stats, st)
+			}
+		}
+		rsp.Schemas = stats
+		rsp.Total = totalStats(stats)
+	case *api.ListSchemaPinsResponse:
+		pins := make([]*api.SchemaPin, 0, len(rsp.Pins))
+		for _, p := range rsp.Pins {
+			if s.allowSchema(ctx, p.Schema) && s.allowSchema(ctx, p.Pinned) {
+				pins = append(pins, p)
+			}
+		}
+		rsp.Pins = pins
+	case *api.ListSchemaCanariesResponse:
+		canaries := make([]*api.SchemaCanary, 0, len(rsp.Canaries))
+		for _, c := range rsp.Canaries {
+			if s.allowSchema(ctx, c.Schema) {
+				canaries = append(canaries, c)
+			}
+		}
+		rsp.Canaries = canaries
+	case *api.GetRequestJournalResponse:
+		// the requests not referring to a schema are also listed to the tenant that sent them
+		var tenant string
+		if a := attributionFromContext(ctx); a != nil {
+			tenant = a.Tenant
+		}
+		entries := make([]*api.JournalEntry, 0, len(rsp.Entries))
+		for _, e := range rsp.Entries {
+			if s.allowSchema(ctx, e.Schema) || e.Schema == nil && tenant != "" && e.Tenant == tenant {
+				entries = append(entries, e)
+			}
+		}
+		rsp.Entries = entries
+	}
+}
+
+// metadata left out of the webhook requests
+var credentialHeaders = []string{"authorization", "proxy-authorization", "cookie", "x-api-key"}
+
+func authzRequest(ctx context.Context, method string, req interface{}) *authz.Request {
+	ar := &authz.Request{Method: method}
+	if p, ok := peer.FromContext(ctx); ok {
+		ar.Peer = p.Addr.String()
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			ar.Principal, ar.SANs = principal(ti.State)
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		// the credentials are not forwarded to the webhook
+		ar.Metadata = md.Copy()
+		for _, k := range credentialHeaders {
+			delete(ar.Metadata, k)
+		}
+	}
+	if a := attributionFromContext(ctx); a != nil {
+		ar.RequestID = a.RequestID
+		ar.Tenant = a.Tenant
+		ar.Subject = a.Subject
+	}
+	if req != nil {
+		ar.Schema, ar.Paths = requestInfo(req)
+	}
+	return ar
+}
+
+// principal returns the subject common name and the DNS, URI, email
+// and IP subject alternative names of the verified client certificate.
+func principal(cs tls.ConnectionState) (string, []string) {
+	if len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return "", nil
+	}
+	cert := cs.VerifiedChains[0][0]
+	sans := append([]string{}, cert.DNSNames...)
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return cert.Subject.CommonName, sans
+}
+
+func (s *Server) authzUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := s.authorize(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := handler(ctx, req)
+	if err == nil {
+		s.filterSchemas(ctx, rsp)
+	}
+	return rsp, err
+}
+
+func (s *Server) authzStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if s.authorizer == nil {
+		return handler(srv, ss)
+	}
+	return handler(srv, &authzStream{ServerStream: ss, s: s, method: info.FullMethod})
+}
+
+// authzStream authorizes a stream when its first message is received,
+// the request then carries the schema and path it refers to.
+type authzStream struct {
+	grpc.ServerStream
+	s          *Server
+	method     string
+	authorized bool
+}
+
+func (as *authzStream) RecvMsg(m interface{}) error {
+	err := as.ServerStream.RecvMsg(m)
+	if err != nil || as.authorized {
+		return err
+	}
+	err = as.s.authorize(as.Context(), as.method, m)
+	if err != nil {
+		return err
+	}
+	as.authorized = true
+	return nil
+}
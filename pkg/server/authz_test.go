@@ -0,0 +1,267 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/authz"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// versionAuthorizer allows the requests for version 1 only.
+type versionAuthorizer struct{}
+
+func (versionAuthorizer) Authorize(_ context.Context, req *authz.Request) error {
+	if req.Schema.GetVersion() != "1" {
+		return status.Errorf(codes.PermissionDenied, "version %s not allowed", req.Schema.GetVersion())
+	}
+	return nil
+}
+
+// loadedStore has all the schemas.
+type loadedStore struct {
+	store.Store
+}
+
+func (loadedStore) HasSchema(store.SchemaKey) bool { return true }
+
+func TestServer_routeAuthorize(t *testing.T) {
+	sck := store.SchemaKey{Name: "sc", Vendor: "v", Version: "1"}
+	s := &Server{
+		schemaStore: loadedStore{},
+		authorizer:  versionAuthorizer{},
+		canaries:    canaries{versions: map[store.SchemaKey]string{sck: "2"}},
+	}
+	const method = "/schema.SchemaServer/GetSchema"
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{name: "stable", md: metadata.Pairs()},
+		{name: "canary", md: metadata.Pairs(canaryHeader, "true"), code: codes.PermissionDenied},
+		{name: "target profile", md: metadata.Pairs(targetProfileHeader, "tp"), code: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			req := &sdcpb.GetSchemaRequest{Schema: schemaOf(sck)}
+			err := s.routeCanary(ctx, method, req)
+			if err == nil {
+				err = s.routeTargetProfile(ctx, method, req)
+			}
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if tt.code == codes.OK && req.GetSchema().GetVersion() != sck.Version {
+				t.Errorf("got version %q, want %q", req.GetSchema().GetVersion(), sck.Version)
+			}
+		})
+	}
+}
+
+func TestServer_authzUnaryInterceptor(t *testing.T) {
+	v := func(version string) *sdcpb.Schema {
+		return &sdcpb.Schema{Name: "sc", Vendor: "v", Version: version}
+	}
+	s := &Server{authorizer: versionAuthorizer{}}
+	tests := []struct {
+		name string
+		req  interface{}
+		code codes.Code
+	}{
+		{name: "allowed", req: &sdcpb.GetSchemaRequest{Schema: v("1")}},
+		{name: "denied", req: &sdcpb.GetSchemaRequest{Schema: v("2")}, code: codes.PermissionDenied},
+		{name: "diff allowed", req: &api.DiffSchemaRequest{From: v("1"), To: v("1")}},
+		{name: "diff from denied", req: &api.DiffSchemaRequest{From: v("2"), To: v("1")}, code: codes.PermissionDenied},
+		{name: "diff to denied", req: &api.DiffSchemaRequest{From: v("1"), To: v("2")}, code: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/schema.SchemaServer/GetSchema"}
+			_, err := s.authzUnaryInterceptor(context.Background(), tt.req, info, handler)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if called != (tt.code == codes.OK) {
+				t.Errorf("handler called %v, want %v", called, tt.code == codes.OK)
+			}
+		})
+	}
+}
+
+func TestServer_filterSchemas(t *testing.T) {
+	srl := &sdcpb.Schema{Name: "srl", Vendor: "nokia", Version: "1"}
+	junos := &sdcpb.Schema{Name: "junos", Vendor: "juniper", Version: "1"}
+	s := &Server{authorizer: authz.NewTenants([]*config.TenantAuthorization{
+		{Tenant: "a", Schemas: []*config.SchemaPattern{{Name: "srl"}}},
+		{Tenant: "b", Schemas: []*config.SchemaPattern{{Name: "junos"}}},
+		{Tenant: "admin", Schemas: []*config.SchemaPattern{{}}, Admin: true},
+	})}
+	// responses listing srl then junos
+	responses := func() []interface{} {
+		return []interface{}{
+			&sdcpb.ListSchemaResponse{Schema: []*sdcpb.Schema{srl, junos}},
+			&api.ListSchemaStatesResponse{States: []*api.SchemaState{{Schema: srl}, {Schema: junos}}},
+			&api.GetSchemaStatsResponse{Schemas: []*api.SchemaStats{{Schema: srl}, {Schema: junos}}},
+			&api.ListSchemaPinsResponse{Pins: []*api.SchemaPin{{Schema: srl, Pinned: srl}, {Schema: junos, Pinned: junos}}},
+			&api.ListSchemaCanariesResponse{Canaries: []*api.SchemaCanary{{Schema: srl}, {Schema: junos}}},
+			&api.GetRequestJournalResponse{Entries: []*api.JournalEntry{
+				{Schema: srl, Tenant: "a"},
+				{Schema: junos, Tenant: "b"},
+				{Method: api.FullMethod("ReloadConfig"), Tenant: "admin"},
+				{Method: api.FullMethod("GetRequestJournal"), Tenant: "a"},
+			}},
+		}
+	}
+	tests := []struct {
+		name   string
+		tenant string
+		// names of the schemas listed by each response
+		want []string
+		// schema names, or methods for the entries without schema, listed by the journal
+		wantJournal []string
+	}{
+		{name: "tenant", tenant: "a", want: []string{"srl"}, wantJournal: []string{"srl", "GetRequestJournal"}},
+		{name: "other tenant", tenant: "b", want: []string{"junos"}, wantJournal: []string{"junos"}},
+		{name: "admin", tenant: "admin", want: []string{"srl", "junos"}, wantJournal: []string{"srl", "junos", "ReloadConfig", "GetRequestJournal"}},
+		{name: "unknown tenant", tenant: "c"},
+		{name: "without tenant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), attributionKey{}, &attribution{Tenant: tt.tenant})
+			for _, rsp := range responses() {
+				s.filterSchemas(ctx, rsp)
+				var got []string
+				want := tt.want
+				switch rsp := rsp.(type) {
+				case *sdcpb.ListSchemaResponse:
+					for _, sc := range rsp.GetSchema() {
+						got = append(got, sc.GetName())
+					}
+				case *api.ListSchemaStatesResponse:
+					for _, st := range rsp.States {
+						got = append(got, st.Schema.GetName())
+					}
+				case *api.GetSchemaStatsResponse:
+					for _, st := range rsp.Schemas {
+						got = append(got, st.Schema.GetName())
+					}
+				case *api.ListSchemaPinsResponse:
+					for _, p := range rsp.Pins {
+						got = append(got, p.Schema.GetName())
+					}
+				case *api.ListSchemaCanariesResponse:
+					for _, c := range rsp.Canaries {
+						got = append(got, c.Schema.GetName())
+					}
+				case *api.GetRequestJournalResponse:
+					want = tt.wantJournal
+					for _, e := range rsp.Entries {
+						if e.Schema != nil {
+							got = append(got, e.Schema.GetName())
+							continue
+						}
+						got = append(got, e.Method[strings.LastIndex(e.Method, "/")+1:])
+					}
+				}
+				if strings.Join(got, ",") != strings.Join(want, ",") {
+					t.Errorf("%T lists %v, want %v", rsp, got, want)
+				}
+			}
+		})
+	}
+}
+
+// testStream receives msgs.
+type testStream struct {
+	grpc.ServerStream
+	msgs []*sdcpb.GetSchemaRequest
+}
+
+func (ts *testStream) Context() context.Context { return context.Background() }
+
+func (ts *testStream) RecvMsg(m interface{}) error {
+	if len(ts.msgs) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), ts.msgs[0])
+	ts.msgs = ts.msgs[1:]
+	return nil
+}
+
+func TestServer_authzStreamInterceptor(t *testing.T) {
+	v := func(version string) *sdcpb.GetSchemaRequest {
+		return &sdcpb.GetSchemaRequest{Schema: &sdcpb.Schema{Name: "sc", Vendor: "v", Version: version}}
+	}
+	tests := []struct {
+		name string
+		msgs []*sdcpb.GetSchemaRequest
+		code codes.Code
+		// messages received by the handler
+		want int
+	}{
+		{name: "allowed", msgs: []*sdcpb.GetSchemaRequest{v("1"), v("1")}, want: 2},
+		{name: "denied", msgs: []*sdcpb.GetSchemaRequest{v("2"), v("1")}, code: codes.PermissionDenied},
+		// the stream is authorized by its first message
+		{name: "allowed first", msgs: []*sdcpb.GetSchemaRequest{v("1"), v("2")}, want: 2},
+		{name: "empty"},
+	}
+	s := &Server{authorizer: versionAuthorizer{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := 0
+			handler := func(_ interface{}, ss grpc.ServerStream) error {
+				for {
+					err := ss.RecvMsg(new(sdcpb.GetSchemaRequest))
+					if err == io.EOF {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					got++
+				}
+			}
+			info := &grpc.StreamServerInfo{FullMethod: "/schema.SchemaServer/GetSchema"}
+			err := s.authzStreamInterceptor(nil, &testStream{msgs: tt.msgs}, info, handler)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if got != tt.want {
+				t.Errorf("handler received %d messages, want %d", got, tt.want)
+			}
+		})
+	}
+}
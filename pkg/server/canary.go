@@ -0,0 +1,196 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"sort"
+	"sync"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// request metadata set to "true" to be answered from the canary version of the requested schema,
+// staged schemas are served to these clients.
+const canaryHeader = "x-schema-canary"
+
+// RPCs managing a schema, always answered from the requested one
+var canaryExempt = map[string]bool{
+	"SetSchemaCanary":    true,
+	"ListSchemaCanaries": true,
+	"SetSchemaState":     true,
+	"CreateSchema":       true,
+	"ReloadSchema":       true,
+	"DeleteSchema":       true,
+	"UploadSchema":       true,
+}
+
+// canaries maps the stable schemas to their canary version.
+type canaries struct {
+	m        sync.RWMutex
+	versions map[store.SchemaKey]string
+}
+
+func (c *canaries) get(sck store.SchemaKey) string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.versions[sck]
+}
+
+func (c *canaries) list() []*api.SchemaCanary {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	scs := make([]*api.SchemaCanary, 0, len(c.versions))
+	for sck, v := range c.versions {
+		scs = append(scs, &api.SchemaCanary{Schema: schemaOf(sck), Version: v})
+	}
+	sort.Slice(scs, func(i, j int) bool {
+		return schemaLess(scs[i].Schema, scs[j].Schema)
+	})
+	return scs
+}
+
+func (s *Server) SetSchemaCanary(ctx context.Context, req *api.SetSchemaCanaryRequest) (*api.SetSchemaCanaryResponse, error) {
+	log.Debugf("received SetSchemaCanary: %v", req)
+	sc := req.Schema
+	if sc.GetVendor() == "" || sc.GetVersion() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing schema vendor or version")
+	}
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+	if req.Version == sck.Version {
+		return nil, status.Error(codes.InvalidArgument, "a schema cannot be its own canary")
+	}
+	s.canaries.m.Lock()
+	if req.Version == "" {
+		delete(s.canaries.versions, sck)
+		log.Infof("schema %s has no canary", sck)
+	} else {
+		ck := store.SchemaKey{Name: sck.Name, Vendor: sck.Vendor, Version: req.Version}
+		if !s.schemaStore.HasSchema(ck) {
+			s.canaries.m.Unlock()
+			return nil, status.Errorf(codes.InvalidArgument, "unknown canary schema %s", ck)
+		}
+		if s.canaries.versions == nil {
+			s.canaries.versions = make(map[store.SchemaKey]string)
+		}
+		s.canaries.versions[sck] = req.Version
+		log.Infof("schema %s has canary version %s", sck, req.Version)
+	}
+	s.canaries.m.Unlock()
+	return &api.SetSchemaCanaryResponse{Canaries: s.canaries.list()}, nil
+}
+
+func (s *Server) ListSchemaCanaries(ctx context.Context, req *api.ListSchemaCanariesRequest) (*api.ListSchemaCanariesResponse, error) {
+	log.Debugf("received ListSchemaCanaries: %v", req)
+	return &api.ListSchemaCanariesResponse{Canaries: s.canaries.list()}, nil
+}
+
+func canaryClient(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && firstValue(md, canaryHeader) == "true"
+}
+
+// routeCanary points the schema of req at its canary version
+// if the client asked for canaries and the canary is loaded.
+// The client must be allowed to query the canary.
+func (s *Server) routeCanary(ctx context.Context, method string, req interface{}) error {
+	if canaryExempt[path.Base(method)] || !canaryClient(ctx) {
+		return nil
+	}
+	sc, _ := requestInfo(req)
+	if sc == nil {
+		return nil
+	}
+	v := s.canaries.get(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+	if v == "" || !s.schemaStore.HasSchema(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: v}) {
+		return nil
+	}
+	csc := proto.Clone(sc).(*sdcpb.Schema)
+	csc.Version = v
+	if !setRequestSchema(req, csc) {
+		return nil
+	}
+	if err := s.authorize(ctx, method, req); err != nil {
+		return err
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(servedVersionHeader, v))
+	return nil
+}
+
+var schemaDescriptor = (&sdcpb.Schema{}).ProtoReflect().Descriptor()
+
+// setRequestSchema sets the schema field of req to sc, it reports whether req has one.
+func setRequestSchema(req interface{}, sc *sdcpb.Schema) bool {
+	if m, ok := req.(proto.Message); ok {
+		pm := m.ProtoReflect()
+		fd := pm.Descriptor().Fields().ByName("schema")
+		if fd == nil || fd.Message() == nil || fd.Message().FullName() != schemaDescriptor.FullName() {
+			return false
+		}
+		pm.Set(fd, protoreflect.ValueOfMessage(sc.ProtoReflect()))
+		return true
+	}
+	// the SchemaServerExt requests are plain structs
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	f := v.Elem().FieldByName("Schema")
+	if !f.IsValid() || !f.CanSet() || f.Type() != reflect.TypeOf(sc) {
+		return false
+	}
+	f.Set(reflect.ValueOf(sc))
+	return true
+}
+
+func (s *Server) canaryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.routeCanary(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) canaryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &canaryStream{ServerStream: ss, s: s, method: info.FullMethod})
+}
+
+// canaryStream routes the first message of a stream, the one carrying the schema.
+type canaryStream struct {
+	grpc.ServerStream
+	s      *Server
+	method string
+	routed bool
+}
+
+func (cs *canaryStream) RecvMsg(m interface{}) error {
+	err := cs.ServerStream.RecvMsg(m)
+	if err != nil || cs.routed {
+		return err
+	}
+	cs.routed = true
+	return cs.s.routeCanary(cs.Context(), cs.method, m)
+}
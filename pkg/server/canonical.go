@@ -0,0 +1,42 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) CanonicalPath(ctx context.Context, req *api.CanonicalPathRequest) (*api.CanonicalPathResponse, error) {
+	log.Debugf("received CanonicalPath: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	rsp := &api.CanonicalPathResponse{Paths: make([]*api.CanonicalPath, 0, len(req.Paths))}
+	for _, p := range req.Paths {
+		cp, xp, err := document.CanonicalPath(ctx, r, p)
+		if err != nil {
+			return nil, err
+		}
+		rsp.Paths = append(rsp.Paths, &api.CanonicalPath{Path: cp, XPath: xp})
+	}
+	return rsp, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// registerLegacyServices registers the SchemaServer implementation
+// under each of the given service names.
+// The legacy protos share the same messages, only the service name differs.
+func (s *Server) registerLegacyServices(names []string) {
+	s.legacyServices = make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if name == sdcpb.SchemaServer_ServiceDesc.ServiceName {
+			continue
+		}
+		s.legacyServices[name] = struct{}{}
+		sd := sdcpb.SchemaServer_ServiceDesc
+		sd.ServiceName = name
+		s.srv.RegisterService(&sd, s)
+		log.Infof("serving SchemaServer as legacy service %q", name)
+	}
+}
+
+// canonicalMethod returns the full method name m of the SchemaServer service
+// if m is the one of a legacy service, m otherwise.
+func (s *Server) canonicalMethod(m string) string {
+	service, method, ok := strings.Cut(strings.TrimPrefix(m, "/"), "/")
+	if !ok {
+		return m
+	}
+	if _, ok := s.legacyServices[service]; !ok {
+		return m
+	}
+	return "/" + sdcpb.SchemaServer_ServiceDesc.ServiceName + "/" + method
+}
+
+// canonicalUnaryInterceptor runs ui with the canonical method of the legacy services RPCs:
+// the authorization, journal, logs, metrics and spans do not tell them apart.
+func (s *Server) canonicalUnaryInterceptor(ui grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if m := s.canonicalMethod(info.FullMethod); m != info.FullMethod {
+			info = &grpc.UnaryServerInfo{Server: info.Server, FullMethod: m}
+		}
+		return ui(ctx, req, info, handler)
+	}
+}
+
+// canonicalStreamInterceptor is canonicalUnaryInterceptor for the streaming RPCs.
+func (s *Server) canonicalStreamInterceptor(si grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if m := s.canonicalMethod(info.FullMethod); m != info.FullMethod {
+			info = &grpc.StreamServerInfo{FullMethod: m, IsClientStream: info.IsClientStream, IsServerStream: info.IsServerStream}
+		}
+		return si(srv, ss, info, handler)
+	}
+}
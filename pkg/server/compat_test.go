@@ -0,0 +1,96 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/authz"
+)
+
+// methodAuthorizer denies the requests for its method.
+type methodAuthorizer string
+
+func (m methodAuthorizer) Authorize(_ context.Context, req *authz.Request) error {
+	if req.Method == string(m) {
+		return status.Errorf(codes.PermissionDenied, "%s not allowed", req.Method)
+	}
+	return nil
+}
+
+func TestServer_canonicalInterceptors(t *testing.T) {
+	const legacy = "schema.proto.SchemaServer"
+	s := &Server{
+		authorizer:     methodAuthorizer("/" + sdcpb.SchemaServer_ServiceDesc.ServiceName + "/DeleteSchema"),
+		legacyServices: map[string]struct{}{legacy: {}},
+	}
+	tests := []struct {
+		name   string
+		method string
+		code   codes.Code
+	}{
+		{name: "canonical", method: "/" + sdcpb.SchemaServer_ServiceDesc.ServiceName + "/DeleteSchema", code: codes.PermissionDenied},
+		{name: "legacy", method: "/" + legacy + "/DeleteSchema", code: codes.PermissionDenied},
+		{name: "other RPC", method: "/" + legacy + "/GetSchema"},
+		// only the configured legacy services are mapped
+		{name: "unknown service", method: "/schema.other.SchemaServer/DeleteSchema"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ui := s.canonicalUnaryInterceptor(s.authzUnaryInterceptor)
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+			_, err := ui(context.Background(), &sdcpb.DeleteSchemaRequest{}, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.code {
+				t.Errorf("unary: got error %v, want code %v", err, tt.code)
+			}
+
+			si := s.canonicalStreamInterceptor(s.authzStreamInterceptor)
+			shandler := func(_ interface{}, ss grpc.ServerStream) error {
+				return ss.RecvMsg(new(sdcpb.GetSchemaRequest))
+			}
+			ss := &testStream{msgs: []*sdcpb.GetSchemaRequest{{}}}
+			err = si(nil, ss, &grpc.StreamServerInfo{FullMethod: tt.method}, shandler)
+			if status.Code(err) != tt.code {
+				t.Errorf("stream: got error %v, want code %v", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestServer_canonicalMethod(t *testing.T) {
+	s := &Server{legacyServices: map[string]struct{}{"schema.proto.SchemaServer": {}}}
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{method: "/schema.proto.SchemaServer/GetSchema", want: "/schema.SchemaServer/GetSchema"},
+		{method: "/schema.SchemaServer/GetSchema", want: "/schema.SchemaServer/GetSchema"},
+		{method: "/schema.SchemaServerExt/ReloadConfig", want: "/schema.SchemaServerExt/ReloadConfig"},
+		{method: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := s.canonicalMethod(tt.method); got != tt.want {
+				t.Errorf("canonicalMethod() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
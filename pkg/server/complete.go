@@ -0,0 +1,235 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+func (s *Server) CompletePath(ctx context.Context, req *api.CompletePathRequest) (*api.CompletePathResponse, error) {
+	log.Debugf("received CompletePath: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	parent, last := splitPartialPath(req.Path)
+	// the last element has an open key, e.g. interface[na or interface[name=eth
+	if idx := strings.LastIndex(last, "["); idx >= 0 && !strings.Contains(last[idx:], "]") {
+		list := parent + "/" + last[:idx]
+		key, value, isValue := strings.Cut(last[idx+1:], "=")
+		if isValue {
+			return completeKeyValue(ctx, r, list, key, value)
+		}
+		return completeKeyName(ctx, r, list, key)
+	}
+	// the last element is a list with all its keys set so far, e.g. interface[name=eth0]
+	if strings.HasSuffix(last, "]") {
+		return completeKeyName(ctx, r, parent+"/"+last, "")
+	}
+	p, err := parsePartialPath(parent)
+	if err != nil {
+		return nil, err
+	}
+	children, err := schemaChildren(ctx, r, elemNames(p))
+	if err != nil {
+		return nil, err
+	}
+	rsp := new(api.CompletePathResponse)
+	for _, c := range children {
+		if !strings.HasPrefix(c.elemName, last) && !strings.HasPrefix(c.name, last) &&
+			(c.module == "" || !strings.HasPrefix(c.module+":"+c.name, last)) {
+			continue
+		}
+		pc := &api.PathCandidate{
+			Name:       c.elemName,
+			Completion: parent + "/" + c.elemName,
+			Module:     c.module,
+			IsState:    c.isState,
+		}
+		switch {
+		case c.dir:
+			se, err := r.Get(ctx, c.names)
+			if err != nil {
+				return nil, err
+			}
+			cs := se.GetContainer()
+			pc.Kind = api.CandidateContainer
+			pc.IsState = cs.GetIsState()
+			if len(cs.GetKeys()) > 0 {
+				pc.Kind = api.CandidateList
+				keys, err := r.Keys(ctx, c.names, cs.GetKeys())
+				if err != nil {
+					return nil, err
+				}
+				for _, k := range keys {
+					pc.Keys = append(pc.Keys, k.GetName())
+				}
+			}
+		case c.isKey:
+			pc.Kind = api.CandidateKey
+			pc.Type = c.typ.GetType()
+		case c.leafList:
+			pc.Kind = api.CandidateLeafList
+			pc.Type = c.typ.GetType()
+		default:
+			pc.Kind = api.CandidateLeaf
+			pc.Type = c.typ.GetType()
+		}
+		switch req.DataType {
+		case sdcpb.DataType_CONFIG:
+			if pc.IsState {
+				continue
+			}
+		case sdcpb.DataType_STATE:
+			// config containers can have state descendants
+			if !pc.IsState && !c.dir {
+				continue
+			}
+		}
+		rsp.Candidates = append(rsp.Candidates, pc)
+	}
+	return rsp, nil
+}
+
+// completeKeyName completes the names of the keys of the list at path list
+// that are not set yet, starting with prefix.
+func completeKeyName(ctx context.Context, r *store.Resolver, list, prefix string) (*api.CompletePathResponse, error) {
+	p, err := parsePartialPath(list)
+	if err != nil {
+		return nil, err
+	}
+	se, err := r.Get(ctx, elemNames(p))
+	if err != nil {
+		return nil, err
+	}
+	keys, err := r.Keys(ctx, elemNames(p), se.GetContainer().GetKeys())
+	if err != nil {
+		return nil, err
+	}
+	set := p.GetElem()[len(p.GetElem())-1].GetKey()
+	rsp := new(api.CompletePathResponse)
+	for _, k := range keys {
+		if _, ok := set[k.GetName()]; ok || !strings.HasPrefix(k.GetName(), prefix) {
+			continue
+		}
+		rsp.Candidates = append(rsp.Candidates, &api.PathCandidate{
+			Name:       k.GetName(),
+			Kind:       api.CandidateKey,
+			Completion: list + "[" + k.GetName() + "=",
+			Type:       k.GetType().GetType(),
+			IsState:    k.GetIsState(),
+		})
+	}
+	return rsp, nil
+}
+
+// completeKeyValue completes the value of key of the list at path list
+// when the key is an enumeration or a boolean.
+func completeKeyValue(ctx context.Context, r *store.Resolver, list, key, prefix string) (*api.CompletePathResponse, error) {
+	p, err := parsePartialPath(list)
+	if err != nil {
+		return nil, err
+	}
+	se, err := r.Get(ctx, elemNames(p))
+	if err != nil {
+		return nil, err
+	}
+	rsp := new(api.CompletePathResponse)
+	for _, k := range se.GetContainer().GetKeys() {
+		if k.GetName() != key {
+			continue
+		}
+		for _, v := range typeValues(k.GetType()) {
+			if !strings.HasPrefix(v, prefix) {
+				continue
+			}
+			rsp.Candidates = append(rsp.Candidates, &api.PathCandidate{
+				Name:       v,
+				Kind:       api.CandidateValue,
+				Completion: list + "[" + key + "=" + v + "]",
+				Type:       k.GetType().GetType(),
+			})
+		}
+	}
+	return rsp, nil
+}
+
+// typeValues returns the values of an enumeration or boolean type, union members included.
+func typeValues(t *sdcpb.SchemaLeafType) []string {
+	switch t.GetType() {
+	case "enumeration":
+		return t.GetValues()
+	case "boolean":
+		return []string{"false", "true"}
+	case "union":
+		var vs []string
+		for _, ut := range t.GetUnionTypes() {
+			vs = append(vs, typeValues(ut)...)
+		}
+		return vs
+	}
+	return nil
+}
+
+// splitPartialPath splits a partial xpath at its last element separator,
+// the ones in key values are skipped.
+func splitPartialPath(xp string) (string, string) {
+	depth := 0
+	last := -1
+	for i, c := range xp {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '/':
+			if depth == 0 {
+				last = i
+			}
+		}
+	}
+	if last < 0 {
+		return "", xp
+	}
+	return xp[:last], xp[last+1:]
+}
+
+func parsePartialPath(xp string) (*sdcpb.Path, error) {
+	p, err := utils.ParsePath(xp)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid path %q: %v", xp, err)
+	}
+	return p, nil
+}
+
+func elemNames(p *sdcpb.Path) []string {
+	names := make([]string, 0, len(p.GetElem()))
+	for _, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+	}
+	return names
+}
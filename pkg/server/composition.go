@@ -0,0 +1,59 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+func (s *Server) GetSchemaComposition(ctx context.Context, req *api.GetSchemaCompositionRequest) (*api.GetSchemaCompositionResponse, error) {
+	log.Debugf("received GetSchemaComposition: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.schemaStore.GetSchemaComposition(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.GetSchemaCompositionResponse{}
+	if c == nil {
+		return rsp, nil
+	}
+	for _, ui := range c.UnresolvedImports {
+		rsp.UnresolvedImports = append(rsp.UnresolvedImports, &api.UnresolvedImport{
+			Module: ui.Module,
+			Import: ui.Import,
+		})
+	}
+	for _, ac := range c.AugmentConflicts {
+		rsp.AugmentConflicts = append(rsp.AugmentConflicts, &api.AugmentConflict{
+			Target:  ac.Target,
+			Node:    ac.Node,
+			Modules: ac.Modules,
+		})
+	}
+	for _, dn := range c.DuplicateNodes {
+		rsp.DuplicateNodes = append(rsp.DuplicateNodes, &api.DuplicateNode{
+			Path:    dn.Path,
+			Modules: dn.Modules,
+		})
+	}
+	return rsp, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) ConvertDocument(ctx context.Context, req *api.ConvertDocumentRequest) (*api.ConvertDocumentResponse, error) {
+	log.Debugf("received ConvertDocument for schema %v from %q to %q", req.Schema, req.From, req.To)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	for _, f := range []string{req.From, req.To} {
+		switch f {
+		case "", api.DocumentFormatJSON, api.DocumentFormatXML, api.DocumentFormatProto:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unknown document format %q", f)
+		}
+	}
+	b := document.NewBuilder(store.NewResolver(s.schemaStore, req.Schema))
+	var err error
+	switch req.From {
+	case api.DocumentFormatProto:
+		if len(req.Updates) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "missing updates")
+		}
+		for _, upd := range req.Updates {
+			if upd == nil || upd.Update == nil {
+				continue
+			}
+			err = b.AddUpdate(ctx, upd.Update)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case api.DocumentFormatXML:
+		if len(req.Document) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "missing document")
+		}
+		err = b.MergeXML(ctx, req.Document)
+	default:
+		if len(req.Document) == 0 {
+			return nil, status.Error(codes.InvalidArgument, "missing document")
+		}
+		err = b.Merge(ctx, req.Document)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Normalize(ctx); err != nil {
+		return nil, err
+	}
+	switch req.To {
+	case api.DocumentFormatProto:
+		upds, err := b.Flatten(ctx)
+		if err != nil {
+			return nil, flattenError(err)
+		}
+		rsp := &api.ConvertDocumentResponse{Updates: make([]*api.Update, 0, len(upds))}
+		for _, upd := range upds {
+			rsp.Updates = append(rsp.Updates, &api.Update{Update: upd})
+		}
+		return rsp, nil
+	case api.DocumentFormatXML:
+		doc, err := b.XML(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &api.ConvertDocumentResponse{XML: string(doc)}, nil
+	}
+	doc, err := b.JSON()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.ConvertDocumentResponse{Document: doc}, nil
+}
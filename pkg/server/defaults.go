@@ -0,0 +1,176 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+	"github.com/sdcio/schema-server/pkg/value"
+)
+
+func (s *Server) GetDefaults(ctx context.Context, req *api.GetDefaultsRequest) (*api.GetDefaultsResponse, error) {
+	log.Debugf("received GetDefaults: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	dc := &defaultsCollector{
+		lr: &leafrefResolver{r: store.NewResolver(s.schemaStore, req.Schema)},
+	}
+	dc.origins, err = s.schemaStore.GetSchemaDefaultOrigins(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	err = dc.lr.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := dc.lr.qualify(ctx, elemNames(req.Path))
+	if err != nil {
+		return nil, err
+	}
+	// the request path elements carrying the keys of the qualified names
+	dc.keys = req.Path.GetElem()[len(req.Path.GetElem())-len(names):]
+	se, err := dc.lr.r.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	if se.GetContainer() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "path %s is not a container or a list", utils.ToXPath(req.Path, true))
+	}
+	err = dc.container(ctx, names, se.GetContainer())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(dc.defaults, func(i, j int) bool {
+		return utils.ToXPath(dc.defaults[i].Update.GetPath(), false) < utils.ToXPath(dc.defaults[j].Update.GetPath(), false)
+	})
+	return &api.GetDefaultsResponse{Defaults: dc.defaults}, nil
+}
+
+type defaultsCollector struct {
+	lr       *leafrefResolver
+	keys     []*sdcpb.PathElem
+	defaults []*api.LeafDefault
+	// origin of the defaults by data path, nil for a schema stored without them
+	origins map[string]*schema.DefaultOrigin
+}
+
+func (dc *defaultsCollector) walk(ctx context.Context, names []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	se, err := dc.lr.r.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	if cs := se.GetContainer(); cs != nil {
+		return dc.container(ctx, names, cs)
+	}
+	return nil
+}
+
+func (dc *defaultsCollector) container(ctx context.Context, names []string, cs *sdcpb.ContainerSchema) error {
+	// list keys do not take a default
+	for _, f := range cs.GetFields() {
+		if f.GetDefault() != "" {
+			dc.add(ctx, dc.child(names, f.GetName()), f.GetType(), false, f.GetDefault())
+		}
+	}
+	for _, ll := range cs.GetLeaflists() {
+		if len(ll.GetDefaults()) > 0 {
+			dc.add(ctx, dc.child(names, ll.GetName()), ll.GetType(), true, ll.GetDefaults()...)
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		if err := dc.walk(ctx, dc.child(names, c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// child returns the names of child c of the node found at names,
+// qualified with their module below a module.
+func (dc *defaultsCollector) child(names []string, c string) []string {
+	switch {
+	case len(names) == 0:
+		return []string{c}
+	case len(names) == 1 && dc.lr.modules[names[0]]:
+		return []string{names[0] + ":" + c}
+	}
+	cnames := make([]string, 0, len(names)+1)
+	cnames = append(cnames, names...)
+	return append(cnames, c)
+}
+
+func (dc *defaultsCollector) add(ctx context.Context, names []string, t *sdcpb.SchemaLeafType, leafList bool, defaults ...string) {
+	// leafref defaults are rendered in the type of their target
+	fn := func(lt *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+		lt2, err := dc.lr.resolve(ctx, names, lt.GetLeafref())
+		if err != nil {
+			log.Debugf("failed to resolve leafref %s: %v", lt.GetLeafref(), err)
+			return nil
+		}
+		return lt2.Type
+	}
+	tvs := make([]*sdcpb.TypedValue, 0, len(defaults))
+	for _, d := range defaults {
+		tv, err := document.TypedValue(t, d, fn)
+		if err != nil {
+			// the schema is loaded with it, keep it as a string
+			log.Warnf("default %q of %v: %v", d, names, err)
+			tv = &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: d}}
+		}
+		tvs = append(tvs, tv)
+	}
+	p := pathOf(names)
+	for i, pe := range dc.keys {
+		if len(pe.GetKey()) == 0 {
+			continue
+		}
+		p.Elem[i].Key = make(map[string]string, len(pe.GetKey()))
+		for k, v := range pe.GetKey() {
+			p.Elem[i].Key[k] = v
+		}
+	}
+	upd := &sdcpb.Update{Path: p, Value: tvs[0]}
+	if leafList {
+		upd.Value = &sdcpb.TypedValue{Value: &sdcpb.TypedValue_LeaflistVal{LeaflistVal: &sdcpb.ScalarArray{Element: tvs}}}
+	}
+	ld := &api.LeafDefault{
+		Update:  &api.Update{Update: upd},
+		Default: defaults,
+		Type:    value.TypeName(t),
+	}
+	if o := dc.origins["/"+strings.Join(stripPrefixes(names), "/")]; o != nil {
+		ld.Origin = o.Origin
+		ld.Typedef = o.Typedef
+		ld.Module = o.Module
+	}
+	dc.defaults = append(dc.defaults, ld)
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) DiffDocument(ctx context.Context, req *api.DiffDocumentRequest) (*api.DiffDocumentResponse, error) {
+	log.Debugf("received DiffDocument for schema %v", req.Schema)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	from, err := decodeDocument(ctx, r, "from", req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := decodeDocument(ctx, r, "to", req.To)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := document.Diff(from, to)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	rsp := &api.DiffDocumentResponse{Changes: make([]*api.DocumentChange, 0, len(changes))}
+	for _, c := range changes {
+		rsp.Changes = append(rsp.Changes, &api.DocumentChange{
+			Op:    c.Op,
+			Path:  c.Path,
+			XPath: c.XPath,
+			Old:   c.Old,
+			New:   c.New,
+		})
+	}
+	return rsp, nil
+}
+
+// decodeDocument decodes the JSON document doc, an empty document is allowed.
+func decodeDocument(ctx context.Context, r *store.Resolver, name string, doc []byte) (*document.Builder, error) {
+	b := document.NewBuilder(r)
+	if len(doc) == 0 {
+		return b, nil
+	}
+	err := b.Merge(ctx, doc)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			return nil, status.Errorf(codes.InvalidArgument, "%s document: %s", name, st.Message())
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// flattenError returns the error of a document Flatten,
+// InvalidArgument with a ReasonInvalidValue detail for a value not matching its type.
+func flattenError(err error) error {
+	var verr *document.ValueError
+	if errors.As(err, &verr) {
+		return verr.GRPCStatus().Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *Server) NormalizeDocument(ctx context.Context, req *api.NormalizeDocumentRequest) (*api.NormalizeDocumentResponse, error) {
+	log.Debugf("received NormalizeDocument for schema %v", req.Schema)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Document) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing document")
+	}
+	b, err := decodeDocument(ctx, store.NewResolver(s.schemaStore, req.Schema), "input", req.Document)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Normalize(ctx); err != nil {
+		return nil, err
+	}
+	doc, err := b.JSON()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.NormalizeDocumentResponse{Document: doc}, nil
+}
+
+func (s *Server) BuildDocument(ctx context.Context, req *api.BuildDocumentRequest) (*api.BuildDocumentResponse, error) {
+	log.Debugf("received BuildDocument for schema %v with %d update(s)", req.Schema, len(req.Updates))
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	switch req.Format {
+	case "", api.DocumentFormatJSON, api.DocumentFormatXML:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown document format %q", req.Format)
+	}
+	b := document.NewBuilder(store.NewResolver(s.schemaStore, req.Schema))
+	for _, upd := range req.Updates {
+		if upd == nil || upd.Update == nil {
+			continue
+		}
+		err := b.AddUpdate(ctx, upd.Update)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := b.Normalize(ctx); err != nil {
+		return nil, err
+	}
+	if req.Format == api.DocumentFormatXML {
+		doc, err := b.XML(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &api.BuildDocumentResponse{XML: string(doc)}, nil
+	}
+	doc, err := b.JSON()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.BuildDocumentResponse{Document: doc}, nil
+}
+
+func (s *Server) FlattenDocument(ctx context.Context, req *api.FlattenDocumentRequest) (*api.FlattenDocumentResponse, error) {
+	log.Debugf("received FlattenDocument for schema %v", req.Schema)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Document) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing document")
+	}
+	b, err := decodeDocument(ctx, store.NewResolver(s.schemaStore, req.Schema), "input", req.Document)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Normalize(ctx); err != nil {
+		return nil, err
+	}
+	upds, err := b.Flatten(ctx)
+	if err != nil {
+		return nil, flattenError(err)
+	}
+	rsp := &api.FlattenDocumentResponse{Updates: make([]*api.Update, 0, len(upds))}
+	for _, upd := range upds {
+		rsp.Updates = append(rsp.Updates, &api.Update{Update: upd})
+	}
+	return rsp, nil
+}
+
+func (s *Server) AnonymizeDocument(ctx context.Context, req *api.AnonymizeDocumentRequest) (*api.AnonymizeDocumentResponse, error) {
+	log.Debugf("received AnonymizeDocument for schema %v with %d rule(s)", req.Schema, len(req.Rules))
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Document) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing document")
+	}
+	rules := make([]*document.AnonymizeRule, 0, len(req.Rules))
+	for i, r := range req.Rules {
+		if r == nil {
+			continue
+		}
+		switch r.Action {
+		case document.AnonymizeMask, document.AnonymizeRandomize, document.AnonymizeKeep:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "rule %d: unknown action %q", i, r.Action)
+		}
+		dr := &document.AnonymizeRule{Action: r.Action}
+		var err error
+		if r.Path != "" {
+			dr.Path, err = regexp.Compile(r.Path)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "rule %d: invalid path: %v", i, err)
+			}
+		}
+		if r.Type != "" {
+			dr.Type, err = regexp.Compile(r.Type)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "rule %d: invalid type: %v", i, err)
+			}
+		}
+		rules = append(rules, dr)
+	}
+	if !req.NoDefaultRules {
+		rules = append(rules, document.DefaultAnonymizeRules()...)
+	}
+	b, err := decodeDocument(ctx, store.NewResolver(s.schemaStore, req.Schema), "input", req.Document)
+	if err != nil {
+		return nil, err
+	}
+	an := document.NewAnonymizer(rules, req.Seed)
+	b.Anonymize(an)
+	if err := b.Normalize(ctx); err != nil {
+		return nil, err
+	}
+	doc, err := b.JSON()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.AnonymizeDocumentResponse{
+		Document: doc,
+		Paths:    an.Paths(),
+	}, nil
+}
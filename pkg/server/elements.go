@@ -0,0 +1,220 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	defaultElementsPageSize = 100
+	maxElementsPageSize     = 1000
+)
+
+// messages the field mask paths of an element are resolved in
+var elementDescriptors = []protoreflect.MessageDescriptor{
+	(&sdcpb.ContainerSchema{}).ProtoReflect().Descriptor(),
+	(&sdcpb.LeafSchema{}).ProtoReflect().Descriptor(),
+	(&sdcpb.LeafListSchema{}).ProtoReflect().Descriptor(),
+}
+
+func (s *Server) ListSchemaElements(ctx context.Context, req *api.ListSchemaElementsRequest) (*api.ListSchemaElementsResponse, error) {
+	log.Debugf("received ListSchemaElements: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := req.PageSize
+	switch {
+	case pageSize < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page size %d", pageSize)
+	case pageSize == 0:
+		pageSize = defaultElementsPageSize
+	case pageSize > maxElementsPageSize:
+		pageSize = maxElementsPageSize
+	}
+	mask, err := newFieldMask(req.FieldMask)
+	if err != nil {
+		return nil, err
+	}
+	sourcesDigest, err := s.schemaStore.GetSchemaSourcesDigest(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	reqDigest := elementsRequestDigest(req)
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = decodePageToken(req.PageToken, reqDigest, sourcesDigest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch, err := s.schemaStore.GetSchemaElements(rctx, &sdcpb.GetSchemaRequest{
+		Schema:          req.Schema,
+		Path:            req.Path,
+		WithDescription: req.WithDescription,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// let the store goroutines writing to ch terminate
+	defer func() {
+		go func() {
+			for range ch {
+			}
+		}()
+	}()
+	rsp := new(api.ListSchemaElementsResponse)
+	count := 0
+	for se := range ch {
+		count++
+		switch {
+		case count <= offset:
+			continue
+		case count > offset+pageSize:
+			rsp.NextPageToken = encodePageToken(offset+pageSize, reqDigest, sourcesDigest)
+			return rsp, nil
+		}
+		mask.apply(se)
+		rsp.Elements = append(rsp.Elements, &api.SchemaElem{SchemaElem: se})
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return rsp, nil
+}
+
+// elementsRequestDigest returns the digest of the request attributes
+// the elements depend on, a page token is only valid for the same ones.
+func elementsRequestDigest(req *api.ListSchemaElementsRequest) string {
+	mask := append([]string{}, req.FieldMask...)
+	sort.Strings(mask)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%t\x00%s", req.Schema.GetName(), req.Schema.GetVendor(),
+		req.Schema.GetVersion(), utils.ToXPath(req.Path, false), req.WithDescription, strings.Join(mask, ","))
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// fieldMask is a tree of the proto field names kept in a message,
+// a leaf of the tree keeps the whole field.
+type fieldMask map[string]fieldMask
+
+// newFieldMask parses the field mask paths, each one has to
+// name a field of the container, leaf or leaf-list schema.
+// A nil mask keeps all the fields.
+func newFieldMask(paths []string) (fieldMask, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	m := make(fieldMask)
+	for _, p := range paths {
+		names := strings.Split(p, ".")
+		if !maskPathValid(names) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown field mask path %q", p)
+		}
+		cur := m
+		for i, name := range names {
+			next, ok := cur[name]
+			if ok && next == nil {
+				// the whole field is already kept
+				break
+			}
+			if i == len(names)-1 {
+				cur[name] = nil
+				break
+			}
+			if !ok {
+				next = make(fieldMask)
+				cur[name] = next
+			}
+			cur = next
+		}
+	}
+	return m, nil
+}
+
+func maskPathValid(names []string) bool {
+NEXT:
+	for _, md := range elementDescriptors {
+		for i, name := range names {
+			fd := md.Fields().ByName(protoreflect.Name(name))
+			if fd == nil {
+				continue NEXT
+			}
+			if i < len(names)-1 {
+				if fd.Message() == nil {
+					continue NEXT
+				}
+				md = fd.Message()
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// apply clears the fields of the element schema not kept by the mask.
+func (m fieldMask) apply(se *sdcpb.SchemaElem) {
+	if m == nil {
+		return
+	}
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		m.prune(se.Container.ProtoReflect())
+	case *sdcpb.SchemaElem_Field:
+		m.prune(se.Field.ProtoReflect())
+	case *sdcpb.SchemaElem_Leaflist:
+		m.prune(se.Leaflist.ProtoReflect())
+	}
+}
+
+func (m fieldMask) prune(pm protoreflect.Message) {
+	var cleared []protoreflect.FieldDescriptor
+	pm.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := m[string(fd.Name())]
+		switch {
+		case !ok:
+			cleared = append(cleared, fd)
+		case sub == nil || fd.Message() == nil:
+		case fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				sub.prune(l.Get(i).Message())
+			}
+		case !fd.IsMap():
+			sub.prune(v.Message())
+		}
+		return true
+	})
+	for _, fd := range cleared {
+		pm.Clear(fd)
+	}
+}
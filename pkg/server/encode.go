@@ -0,0 +1,47 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) EncodeJSON(ctx context.Context, req *api.EncodeJSONRequest) (*api.EncodeJSONResponse, error) {
+	log.Debugf("received EncodeJSON: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Path.GetElem()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+	b := document.NewBuilder(store.NewResolver(s.schemaStore, req.Schema))
+	err := b.Add(ctx, req.Path, req.Values...)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := b.JSON()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.EncodeJSONResponse{Document: doc}, nil
+}
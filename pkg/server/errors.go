@@ -0,0 +1,45 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// reasonError returns a status error carrying an ErrorInfo with reason and md,
+// for clients to tell the error cause without parsing its message.
+func reasonError(code codes.Code, reason string, md map[string]string, format string, args ...interface{}) error {
+	st := status.New(code, fmt.Sprintf(format, args...))
+	std, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   schema.ErrorDomain,
+		Metadata: md,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return std.Err()
+}
+
+func schemaErrorInfo(sck store.SchemaKey) map[string]string {
+	return map[string]string{"name": sck.Name, "vendor": sck.Vendor, "version": sck.Version}
+}
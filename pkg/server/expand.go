@@ -0,0 +1,113 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// ExpandPathStream expands the path into a spill buffer before streaming the
+// paths in batches: the expansion does not wait for a slow client and the
+// response is held in a temporary file rather than in memory when it is large.
+func (s *Server) ExpandPathStream(req *sdcpb.ExpandPathRequest, stream api.SchemaServerExt_ExpandPathStreamServer) error {
+	log.Debugf("received ExpandPathStream: %v", req)
+	ctx := stream.Context()
+	err := s.checkSubtreeLimits(ctx, req.GetSchema(), req.GetPath())
+	if err != nil {
+		return err
+	}
+	md, err := s.pathPlaceholders(ctx, req.GetSchema(), req.GetPath())
+	if err != nil {
+		return err
+	}
+	if md != nil {
+		_ = stream.SetHeader(md)
+	}
+	var q *pathQualifier
+	if s.config.GRPCServer.PathQualification != config.PathQualificationNone {
+		q = newPathQualifier(s.schemaStore, req.GetSchema(), s.config.GRPCServer.PathQualification)
+	}
+	expand := func(fn func(*sdcpb.Path) error) error {
+		return s.schemaStore.ExpandPathFunc(ctx, req, func(p *sdcpb.Path) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if q != nil {
+				if err := q.qualify(ctx, p); err != nil {
+					return err
+				}
+			}
+			return fn(p)
+		})
+	}
+
+	batchSize := s.config.GRPCServer.Streaming.BatchSize
+	rsp := new(sdcpb.ExpandPathResponse)
+	count := 0
+	send := func() error {
+		if count == 0 {
+			return nil
+		}
+		err := stream.Send(rsp)
+		rsp = new(sdcpb.ExpandPathResponse)
+		count = 0
+		return err
+	}
+	add := func() error {
+		count++
+		if count < batchSize {
+			return nil
+		}
+		return send()
+	}
+	if req.GetXpath() {
+		// the xpaths are sorted, as ExpandPath sorts them
+		xs := newXPathSorter(s.config.GRPCServer.Streaming)
+		defer xs.close()
+		err = expand(func(p *sdcpb.Path) error {
+			return xs.add(utils.ToXPath(p, false))
+		})
+		if err != nil {
+			return err
+		}
+		err = xs.each(func(xp string) error {
+			rsp.Xpath = append(rsp.Xpath, xp)
+			return add()
+		})
+	} else {
+		buf := newSpillBuffer(s.config.GRPCServer.Streaming)
+		defer buf.close()
+		err = expand(func(p *sdcpb.Path) error {
+			return buf.add(p)
+		})
+		if err != nil {
+			return err
+		}
+		err = buf.each(func() proto.Message { return new(sdcpb.Path) }, func(m proto.Message) error {
+			rsp.Path = append(rsp.Path, m.(*sdcpb.Path))
+			return add()
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return send()
+}
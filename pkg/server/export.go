@@ -0,0 +1,347 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// ExportSchema exports the schema into a spill buffer before streaming it:
+// the schema walk does not wait for a slow client and a large export is held
+// in a temporary file rather than in memory.
+func (s *Server) ExportSchema(req *api.ExportSchemaRequest, stream api.SchemaServerExt_ExportSchemaServer) error {
+	log.Debugf("received ExportSchema: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	// the whole schema can be exported, not a subtree resolved beyond the limits
+	if len(req.Path.GetElem()) > 0 {
+		if err := s.checkSubtreeLimits(ctx, req.Schema, req.Path); err != nil {
+			return err
+		}
+	}
+	buf := newSpillBuffer(s.config.GRPCServer.Streaming)
+	defer buf.close()
+	e := &schemaExporter{
+		r:            store.NewResolver(s.schemaStore, req.Schema),
+		descriptions: req.WithDescriptions,
+		batchSize:    s.config.GRPCServer.Streaming.BatchSize,
+		h:            sha256.New(),
+		send: func(text string) error {
+			return buf.add(wrapperspb.String(text))
+		},
+	}
+	root, err := e.r.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	e.modules = make(map[string]bool, len(root.GetContainer().GetChildren()))
+	for _, m := range root.GetContainer().GetChildren() {
+		e.modules[m] = true
+	}
+	e.line(0, "# schema export v"+strconv.Itoa(api.ExportFormatVersion))
+	err = e.export(ctx, elemNames(req.Path))
+	if err != nil {
+		return err
+	}
+	if e.buf.Len() > 0 {
+		if err := e.flush(); err != nil {
+			return err
+		}
+	}
+	err = buf.each(func() proto.Message { return new(wrapperspb.StringValue) }, func(m proto.Message) error {
+		return stream.Send(&api.ExportSchemaResponse{Text: m.(*wrapperspb.StringValue).GetValue()})
+	})
+	if err != nil {
+		return err
+	}
+	return stream.Send(&api.ExportSchemaResponse{Digest: hex.EncodeToString(e.h.Sum(nil))})
+}
+
+// schemaExporter writes the nodes of a schema as blocks of lines in path order:
+// a node line, "kind path", followed by its set attributes, indented.
+// The keys of a list come first in their declared order, then its leaves,
+// leaf-lists and children sorted by name.
+type schemaExporter struct {
+	r            *store.Resolver
+	descriptions bool
+	modules      map[string]bool
+	// nodes per response
+	batchSize int
+	nodes     int
+	buf       strings.Builder
+	h         hash.Hash
+	send      func(string) error
+}
+
+func (e *schemaExporter) line(indent int, s string) {
+	e.buf.WriteString(strings.Repeat("  ", indent))
+	e.buf.WriteString(s)
+	e.buf.WriteByte('\n')
+}
+
+// attr writes attribute name with value v, if set.
+func (e *schemaExporter) attr(indent int, name, v string) {
+	if v == "" {
+		return
+	}
+	e.line(indent, name+" "+exportValue(v))
+}
+
+func (e *schemaExporter) flag(indent int, name string, set bool) {
+	if set {
+		e.line(indent, name+" true")
+	}
+}
+
+// count writes a min or max elements count, unbounded is the default.
+func (e *schemaExporter) count(indent int, name string, v uint64) {
+	if v > 0 && v != math.MaxUint64 {
+		e.line(indent, name+" "+strconv.FormatUint(v, 10))
+	}
+}
+
+// done ends a node block, a response is sent every batchSize nodes.
+func (e *schemaExporter) done() error {
+	e.nodes++
+	if e.nodes < e.batchSize {
+		return nil
+	}
+	return e.flush()
+}
+
+func (e *schemaExporter) flush() error {
+	text := e.buf.String()
+	e.buf.Reset()
+	e.nodes = 0
+	e.h.Write([]byte(text))
+	return e.send(text)
+}
+
+func (e *schemaExporter) export(ctx context.Context, names []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	se, err := e.r.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	se = proto.Clone(se).(*sdcpb.SchemaElem)
+	normalizeElem(se)
+	p := nodePath(names)
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		se.Container.Keys, err = e.r.Keys(ctx, names, se.Container.GetKeys())
+		if err != nil {
+			return err
+		}
+		return e.container(ctx, names, se.Container)
+	case *sdcpb.SchemaElem_Field:
+		e.leaf(p, "leaf", se.Field)
+		return e.done()
+	case *sdcpb.SchemaElem_Leaflist:
+		e.leafList(p, se.Leaflist)
+		return e.done()
+	}
+	return nil
+}
+
+func (e *schemaExporter) container(ctx context.Context, names []string, cs *sdcpb.ContainerSchema) error {
+	p := nodePath(names)
+	switch {
+	case len(names) == 0:
+		// the root only has the modules as children
+	case len(names) == 1 && e.modules[names[0]]:
+		e.line(0, "module "+names[0])
+		e.attr(1, "namespace", cs.GetNamespace())
+		e.attr(1, "prefix", cs.GetPrefix())
+	default:
+		kind := "container"
+		if len(cs.GetKeys()) > 0 {
+			kind = "list"
+		}
+		e.line(0, kind+" "+p)
+		if e.descriptions {
+			e.attr(1, "description", cs.GetDescription())
+		}
+		for _, k := range cs.GetKeys() {
+			e.attr(1, "key", k.GetName())
+		}
+		e.flag(1, "presence", cs.GetIsPresence())
+		if cs.GetIsState() {
+			e.line(1, "config false")
+		}
+		e.flag(1, "user-ordered", cs.GetIsUserOrdered())
+		e.count(1, "min-elements", cs.GetMinElements())
+		e.count(1, "max-elements", cs.GetMaxElements())
+		e.features(1, cs.GetIfFeature())
+		e.musts(1, cs.GetMustStatements())
+		e.choice(1, cs.GetChoiceInfo())
+	}
+	if len(names) > 0 {
+		if err := e.done(); err != nil {
+			return err
+		}
+	}
+	for _, k := range cs.GetKeys() {
+		e.leaf(p+"/"+k.GetName(), "key", k)
+		if err := e.done(); err != nil {
+			return err
+		}
+	}
+	for _, f := range cs.GetFields() {
+		e.leaf(p+"/"+f.GetName(), "leaf", f)
+		if err := e.done(); err != nil {
+			return err
+		}
+	}
+	for _, ll := range cs.GetLeaflists() {
+		e.leafList(p+"/"+ll.GetName(), ll)
+		if err := e.done(); err != nil {
+			return err
+		}
+	}
+	children := append([]string(nil), cs.GetChildren()...)
+	sort.Strings(children)
+	for _, c := range children {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, c)
+		if err := e.export(ctx, cnames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *schemaExporter) leaf(p, kind string, ls *sdcpb.LeafSchema) {
+	e.line(0, kind+" "+p)
+	if e.descriptions {
+		e.attr(1, "description", ls.GetDescription())
+	}
+	e.leafType(1, ls.GetType())
+	e.attr(1, "units", ls.GetUnits())
+	e.attr(1, "default", ls.GetDefault())
+	e.flag(1, "mandatory", ls.GetIsMandatory())
+	if ls.GetIsState() {
+		e.line(1, "config false")
+	}
+	e.flag(1, "encrypted", ls.GetEncrypted())
+	e.features(1, ls.GetIfFeature())
+	e.musts(1, ls.GetMustStatements())
+	e.choice(1, ls.GetChoiceInfo())
+	for _, r := range ls.GetReference() {
+		e.attr(1, "referenced-by", r)
+	}
+}
+
+func (e *schemaExporter) leafList(p string, lls *sdcpb.LeafListSchema) {
+	e.line(0, "leaf-list "+p)
+	if e.descriptions {
+		e.attr(1, "description", lls.GetDescription())
+	}
+	e.leafType(1, lls.GetType())
+	e.attr(1, "units", lls.GetUnits())
+	for _, d := range lls.GetDefaults() {
+		e.attr(1, "default", d)
+	}
+	if lls.GetIsState() {
+		e.line(1, "config false")
+	}
+	e.flag(1, "user-ordered", lls.GetIsUserOrdered())
+	e.count(1, "min-elements", lls.GetMinElements())
+	e.count(1, "max-elements", lls.GetMaxElements())
+	e.features(1, lls.GetIfFeature())
+	e.musts(1, lls.GetMustStatements())
+	e.choice(1, lls.GetChoiceInfo())
+}
+
+func (e *schemaExporter) leafType(indent int, t *sdcpb.SchemaLeafType) {
+	if t == nil {
+		return
+	}
+	e.line(indent, "type "+t.GetType())
+	if t.GetTypeName() != t.GetType() {
+		e.attr(indent+1, "type-name", t.GetTypeName())
+	}
+	e.attr(indent+1, "range", t.GetRange())
+	e.attr(indent+1, "length", t.GetLength())
+	for _, pat := range t.GetPatterns() {
+		if pat.GetInverted() {
+			e.attr(indent+1, "invert-match", pat.GetPattern())
+			continue
+		}
+		e.attr(indent+1, "pattern", pat.GetPattern())
+	}
+	values := t.GetValues()
+	if t.GetType() == "identityref" {
+		values = append([]string(nil), values...)
+		sort.Strings(values)
+	}
+	for _, v := range values {
+		e.attr(indent+1, "value", v)
+	}
+	e.attr(indent+1, "path", t.GetLeafref())
+	for _, ut := range t.GetUnionTypes() {
+		e.leafType(indent+1, ut)
+	}
+}
+
+func (e *schemaExporter) features(indent int, fs []string) {
+	fs = append([]string(nil), fs...)
+	sort.Strings(fs)
+	for _, f := range fs {
+		e.attr(indent, "if-feature", f)
+	}
+}
+
+func (e *schemaExporter) musts(indent int, ms []*sdcpb.MustStatement) {
+	for _, m := range ms {
+		e.attr(indent, "must", m.GetStatement())
+		e.attr(indent+1, "error-message", m.GetError())
+	}
+}
+
+func (e *schemaExporter) choice(indent int, ci *sdcpb.ChoiceInfo) {
+	if ci == nil {
+		return
+	}
+	e.attr(indent, "choice", ci.GetChoice())
+	e.attr(indent, "case", ci.GetCase())
+}
+
+// exportValue quotes v if it would not read back as a single token.
+func exportValue(v string) string {
+	if strings.ContainsAny(v, " \t\n\r\"'\\#") {
+		return strconv.Quote(v)
+	}
+	return v
+}
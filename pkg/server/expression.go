@@ -0,0 +1,125 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/xpath"
+)
+
+const (
+	// when statement of the node returned by GetSchema, the node is its context node
+	whenHeader = "x-schema-when"
+	// when statements of the choices, cases, augments and uses the node
+	// is defined in, the parent data node is their context node
+	parentWhenHeader = "x-schema-parent-when"
+)
+
+func (s *Server) ValidateExpression(ctx context.Context, req *api.ValidateExpressionRequest) (*api.ValidateExpressionResponse, error) {
+	log.Debugf("received ValidateExpression: %v", req)
+	_, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Expression) == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing expression")
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	names := elemNames(req.Path)
+	// the context node must exist
+	if _, err := r.Get(ctx, names); err != nil {
+		return nil, err
+	}
+	res, err := xpath.Check(ctx, r, names, req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.ValidateExpressionResponse{
+		Valid:     len(res.Issues) == 0,
+		Paths:     res.Paths,
+		Functions: res.Functions,
+	}
+	for _, is := range res.Issues {
+		rsp.Issues = append(rsp.Issues, &api.ExpressionIssue{
+			Kind:    is.Kind,
+			Offset:  is.Offset,
+			Message: is.Message,
+		})
+	}
+	return rsp, nil
+}
+
+// setWhenHeaders sets the when statements of the node found at path p as response headers.
+func (s *Server) setWhenHeaders(ctx context.Context, sc *sdcpb.Schema, p *sdcpb.Path) {
+	names := stripPrefixes(elemNames(p))
+	if len(names) == 0 {
+		return
+	}
+	// the path can start with the module name
+	if len(names) > 1 && !strings.Contains(p.GetElem()[0].GetName(), ":") {
+		rsp, err := s.schemaStore.GetSchema(ctx, &sdcpb.GetSchemaRequest{Schema: sc})
+		if err != nil {
+			return
+		}
+		for _, m := range rsp.GetSchema().GetContainer().GetChildren() {
+			if m == names[0] {
+				names = names[1:]
+				break
+			}
+		}
+	}
+	ws, err := s.schemaStore.GetSchemaWhen(ctx, store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()},
+		"/"+strings.Join(names, "/"))
+	if err != nil {
+		log.Debugf("failed to get the when statements of %v: %v", names, err)
+		return
+	}
+	if ws == nil {
+		return
+	}
+	md := metadata.MD{}
+	if ws.Node != "" {
+		md.Set(whenHeader, headerValue(ws.Node))
+	}
+	for _, w := range ws.Parent {
+		md.Append(parentWhenHeader, headerValue(w))
+	}
+	_ = grpc.SetHeader(ctx, md)
+}
+
+// headerValue returns expression w on a single line, non printable ASCII characters
+// are escaped as they are not allowed in a header value.
+func headerValue(w string) string {
+	w = strings.Join(strings.Fields(w), " ")
+	for i := 0; i < len(w); i++ {
+		if w[i] < 0x20 || w[i] > 0x7e {
+			q := strconv.QuoteToASCII(w)
+			return q[1 : len(q)-1]
+		}
+	}
+	return w
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	// request metadata asking to answer from the nearest loaded
+	// version if the requested one is not loaded, set to "nearest".
+	versionFallbackHeader  = "x-schema-version-fallback"
+	versionFallbackNearest = "nearest"
+	// response metadata set to the version answering the request
+	// when it is not the requested one.
+	servedVersionHeader = "x-schema-served-version"
+)
+
+// fallbackSchema returns the schema to answer a request for sc from.
+// It is sc unless it is not loaded and the client asked for the nearest version.
+func (s *Server) fallbackSchema(ctx context.Context, sc *sdcpb.Schema) *sdcpb.Schema {
+	if sc == nil {
+		return sc
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || firstValue(md, versionFallbackHeader) != versionFallbackNearest {
+		return sc
+	}
+	if s.schemaStore.HasSchema(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}) {
+		return sc
+	}
+	rsp, err := s.schemaStore.ListSchema(ctx, &sdcpb.ListSchemaRequest{})
+	if err != nil {
+		return sc
+	}
+	versions := make([]string, 0, len(rsp.GetSchema()))
+	for _, lsc := range rsp.GetSchema() {
+		if lsc.GetName() != sc.GetName() || lsc.GetVendor() != sc.GetVendor() {
+			continue
+		}
+		// a schema derived for a target profile is not a version of the requested one
+		if strings.Contains(lsc.GetVersion(), config.TargetProfileSeparator) != strings.Contains(sc.GetVersion(), config.TargetProfileSeparator) {
+			continue
+		}
+		versions = append(versions, lsc.GetVersion())
+	}
+	version := utils.NearestVersion(sc.GetVersion(), versions)
+	if version == "" {
+		return sc
+	}
+	log.Infof("schema %s@%s@%s is not loaded, answering from version %s",
+		sc.GetName(), sc.GetVendor(), sc.GetVersion(), version)
+	fsc := proto.Clone(sc).(*sdcpb.Schema)
+	fsc.Version = version
+	return fsc
+}
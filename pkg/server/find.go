@@ -0,0 +1,80 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/query"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) FindSchema(ctx context.Context, req *api.FindSchemaRequest) (*api.FindSchemaResponse, error) {
+	log.Debugf("received FindSchema: %v", req)
+	_, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if req.Pattern == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing pattern")
+	}
+	expr := req.Pattern
+	if !req.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if req.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pattern: %v", err)
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative limit")
+	}
+	q, err := query.Search(re, req.Fields, req.Kinds, req.Path, req.Limit)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// a search walks the subtree like a query
+	err = s.checkSubtreeLimits(ctx, req.Schema, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.FindSchemaResponse{
+		Matches: make([]*api.FindMatch, 0),
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema).WithDescriptions()
+	rsp.Truncated, err = query.Run(ctx, r, q, func(m *query.Match) error {
+		rsp.Matches = append(rsp.Matches, &api.FindMatch{
+			Path:   m.Path,
+			Kind:   m.Kind,
+			Module: m.Attributes["module"],
+			Type:   m.Attributes["type"],
+			Fields: query.SearchMatches(q, m.Attributes),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
@@ -0,0 +1,368 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// max number of snapshots kept per schema
+const maxSnapshots = 8
+
+// schemaSnapshot holds the hashes of the containers and lists of a schema at a point in time.
+// The hash of a node covers its schema element, leaves included, and the hashes of its children:
+// the fingerprint of the schema is the hash of its root.
+type schemaSnapshot struct {
+	fingerprint string
+	// by node path, the module names are the first elements
+	nodes map[string]*nodeHash
+}
+
+type nodeHash struct {
+	// hash of the node schema element, descriptions excluded
+	self []byte
+	// hash of the node and its subtree
+	tree     []byte
+	children []string
+}
+
+// snapshots keeps the last snapshots of each schema,
+// a schema is snapshotted when its fingerprint is requested and before it is reloaded.
+type snapshots struct {
+	m sync.Mutex
+	// most recent last
+	bySchema map[store.SchemaKey][]*schemaSnapshot
+}
+
+func (ss *snapshots) add(sck store.SchemaKey, snap *schemaSnapshot) {
+	ss.m.Lock()
+	defer ss.m.Unlock()
+	if ss.bySchema == nil {
+		ss.bySchema = make(map[store.SchemaKey][]*schemaSnapshot)
+	}
+	snaps := ss.bySchema[sck]
+	for i, s := range snaps {
+		if s.fingerprint == snap.fingerprint {
+			snaps = append(snaps[:i], snaps[i+1:]...)
+			break
+		}
+	}
+	snaps = append(snaps, snap)
+	if len(snaps) > maxSnapshots {
+		snaps = snaps[len(snaps)-maxSnapshots:]
+	}
+	ss.bySchema[sck] = snaps
+}
+
+func (ss *snapshots) get(sck store.SchemaKey, fingerprint string) *schemaSnapshot {
+	ss.m.Lock()
+	defer ss.m.Unlock()
+	for _, s := range ss.bySchema[sck] {
+		if s.fingerprint == fingerprint {
+			return s
+		}
+	}
+	return nil
+}
+
+// fingerprints caches the fingerprint of the schemas, until they change.
+type fingerprints struct {
+	m        sync.Mutex
+	bySchema map[store.SchemaKey]string
+	// incremented when a schema changes, a fingerprint computed
+	// across a change is not cached
+	gen uint64
+}
+
+func (fs *fingerprints) get(sck store.SchemaKey) (string, uint64, bool) {
+	fs.m.Lock()
+	defer fs.m.Unlock()
+	fp, ok := fs.bySchema[sck]
+	return fp, fs.gen, ok
+}
+
+func (fs *fingerprints) set(sck store.SchemaKey, fp string, gen uint64) {
+	fs.m.Lock()
+	defer fs.m.Unlock()
+	if gen != fs.gen {
+		return
+	}
+	if fs.bySchema == nil {
+		fs.bySchema = make(map[store.SchemaKey]string)
+	}
+	fs.bySchema[sck] = fp
+}
+
+func (fs *fingerprints) drop(sck store.SchemaKey) {
+	fs.m.Lock()
+	defer fs.m.Unlock()
+	delete(fs.bySchema, sck)
+	fs.gen++
+}
+
+// fingerprint returns the fingerprint of schema sc, from the cache
+// if it did not change since it was last computed.
+func (s *Server) fingerprint(ctx context.Context, sc *sdcpb.Schema) (string, error) {
+	sck, err := s.checkSchema(sc)
+	if err != nil {
+		return "", err
+	}
+	fp, gen, ok := s.fingerprints.get(sck)
+	if ok {
+		return fp, nil
+	}
+	snap, err := s.snapshot(ctx, sc, sck)
+	if err != nil {
+		return "", err
+	}
+	s.fingerprints.set(sck, snap.fingerprint, gen)
+	return snap.fingerprint, nil
+}
+
+// snapshot walks schema sc and records its snapshot.
+func (s *Server) snapshot(ctx context.Context, sc *sdcpb.Schema, sck store.SchemaKey) (*schemaSnapshot, error) {
+	snap := &schemaSnapshot{nodes: make(map[string]*nodeHash)}
+	r := store.NewResolver(s.schemaStore, sc)
+	root, err := hashNode(ctx, r, nil, snap.nodes)
+	if err != nil {
+		return nil, err
+	}
+	snap.fingerprint = hex.EncodeToString(root.tree[:16])
+	s.snapshots.add(sck, snap)
+	return snap, nil
+}
+
+func hashNode(ctx context.Context, r *store.Resolver, names []string, nodes map[string]*nodeHash) (*nodeHash, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	se, err := r.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	self, err := hashElem(se)
+	if err != nil {
+		return nil, err
+	}
+	n := &nodeHash{self: self[:]}
+	h := sha256.New()
+	h.Write(n.self)
+	children := append([]string(nil), se.GetContainer().GetChildren()...)
+	sort.Strings(children)
+	for _, c := range children {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, c)
+		cn, err := hashNode(ctx, r, cnames, nodes)
+		if err != nil {
+			return nil, err
+		}
+		h.Write([]byte(c))
+		h.Write(cn.tree)
+		n.children = append(n.children, c)
+	}
+	n.tree = h.Sum(nil)
+	nodes[nodePath(names)] = n
+	return n, nil
+}
+
+// hashElem returns the hash of schema element se.
+// The leaves of a container are listed in no particular order, they are sorted first.
+func hashElem(se *sdcpb.SchemaElem) ([32]byte, error) {
+	se = proto.Clone(se).(*sdcpb.SchemaElem)
+	normalizeElem(se)
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(se)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(b), nil
+}
+
+// normalizeElem sorts the repeated attributes of se built from maps,
+// their order changes from one GetSchema to the next.
+func normalizeElem(se *sdcpb.SchemaElem) {
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		cs := se.Container
+		sort.Slice(cs.Fields, func(i, j int) bool {
+			return cs.Fields[i].GetName() < cs.Fields[j].GetName()
+		})
+		sort.Slice(cs.Leaflists, func(i, j int) bool {
+			return cs.Leaflists[i].GetName() < cs.Leaflists[j].GetName()
+		})
+		for _, ls := range cs.Keys {
+			normalizeLeaf(ls)
+		}
+		for _, ls := range cs.Fields {
+			normalizeLeaf(ls)
+		}
+		for _, lls := range cs.Leaflists {
+			normalizeChoice(lls.GetChoiceInfo())
+		}
+		normalizeChoice(cs.GetChoiceInfo())
+	case *sdcpb.SchemaElem_Field:
+		normalizeLeaf(se.Field)
+	case *sdcpb.SchemaElem_Leaflist:
+		normalizeChoice(se.Leaflist.GetChoiceInfo())
+	}
+}
+
+func normalizeLeaf(ls *sdcpb.LeafSchema) {
+	sort.Strings(ls.Reference)
+	normalizeChoice(ls.GetChoiceInfo())
+}
+
+func normalizeChoice(ci *sdcpb.ChoiceInfo) {
+	if ci != nil {
+		sort.Strings(ci.AltCase)
+	}
+}
+
+func nodePath(names []string) string {
+	return "/" + strings.Join(names, "/")
+}
+
+func (s *Server) GetSchemaFingerprint(ctx context.Context, req *api.GetSchemaFingerprintRequest) (*api.GetSchemaFingerprintResponse, error) {
+	log.Debugf("received GetSchemaFingerprint: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := s.snapshot(ctx, req.Schema, sck)
+	if err != nil {
+		return nil, err
+	}
+	return &api.GetSchemaFingerprintResponse{Fingerprint: snap.fingerprint}, nil
+}
+
+func (s *Server) ExportSchemaChanges(ctx context.Context, req *api.ExportSchemaChangesRequest) (*api.ExportSchemaChangesResponse, error) {
+	log.Debugf("received ExportSchemaChanges: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if req.Fingerprint == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing fingerprint")
+	}
+	from := s.snapshots.get(sck, req.Fingerprint)
+	if from == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown fingerprint %q for schema %s, a full export is needed", req.Fingerprint, sck)
+	}
+	to, err := s.snapshot(ctx, req.Schema, sck)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.ExportSchemaChangesResponse{
+		From:    from.fingerprint,
+		To:      to.fingerprint,
+		Changes: make([]*api.SchemaChange, 0),
+	}
+	if from.fingerprint == to.fingerprint {
+		return rsp, nil
+	}
+	e := &changeExporter{
+		resolver: store.NewResolver(s.schemaStore, req.Schema),
+		from:     from,
+		to:       to,
+		rsp:      rsp,
+	}
+	err = e.diff(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+type changeExporter struct {
+	resolver *store.Resolver
+	from, to *schemaSnapshot
+	rsp      *api.ExportSchemaChangesResponse
+}
+
+// diff reports the changes of the subtree found at names, present in both snapshots.
+func (e *changeExporter) diff(ctx context.Context, names []string) error {
+	p := nodePath(names)
+	fn, tn := e.from.nodes[p], e.to.nodes[p]
+	if bytes.Equal(fn.tree, tn.tree) {
+		return nil
+	}
+	if !bytes.Equal(fn.self, tn.self) {
+		if err := e.add(ctx, api.SchemaChangeUpdate, names); err != nil {
+			return err
+		}
+	}
+	for _, c := range fn.children {
+		if _, ok := e.to.nodes[nodePath(append(names[:len(names):len(names)], c))]; !ok {
+			e.rsp.Changes = append(e.rsp.Changes, &api.SchemaChange{
+				Op:   api.SchemaChangeDelete,
+				Path: nodePath(append(names[:len(names):len(names)], c)),
+			})
+		}
+	}
+	for _, c := range tn.children {
+		cnames := append(names[:len(names):len(names)], c)
+		if _, ok := e.from.nodes[nodePath(cnames)]; !ok {
+			if err := e.addTree(ctx, cnames); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.diff(ctx, cnames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTree reports the nodes of the subtree found at names as added.
+func (e *changeExporter) addTree(ctx context.Context, names []string) error {
+	if err := e.add(ctx, api.SchemaChangeAdd, names); err != nil {
+		return err
+	}
+	for _, c := range e.to.nodes[nodePath(names)].children {
+		if err := e.addTree(ctx, append(names[:len(names):len(names)], c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *changeExporter) add(ctx context.Context, op string, names []string) error {
+	se, err := e.resolver.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	e.rsp.Changes = append(e.rsp.Changes, &api.SchemaChange{
+		Op:     op,
+		Path:   nodePath(names),
+		Schema: &api.SchemaElem{SchemaElem: se},
+	})
+	return nil
+}
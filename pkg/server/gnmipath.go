@@ -0,0 +1,62 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/gnmipath"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) TranslatePath(ctx context.Context, req *api.TranslatePathRequest) (*api.TranslatePathResponse, error) {
+	log.Debugf("received TranslatePath: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	fromGNMI := req.GNMIPath != nil || req.Prefix != nil
+	switch {
+	case fromGNMI && req.Path != nil:
+		return nil, status.Error(codes.InvalidArgument, "a gNMI path and a schema path are mutually exclusive")
+	case !fromGNMI && req.Path == nil:
+		return nil, status.Error(codes.InvalidArgument, "missing gNMI path or schema path")
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	if req.Path != nil {
+		gp, err := gnmipath.FromSchemaPath(ctx, r, req.Path, req.Qualification)
+		if err != nil {
+			return nil, err
+		}
+		return &api.TranslatePathResponse{GNMIPath: &api.GNMIPath{Path: gp}}, nil
+	}
+	var prefix, p *gnmipb.Path
+	if req.Prefix != nil {
+		prefix = req.Prefix.Path
+	}
+	if req.GNMIPath != nil {
+		p = req.GNMIPath.Path
+	}
+	sp, xp, err := gnmipath.ToSchemaPath(ctx, r, prefix, p)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TranslatePathResponse{Path: sp, XPath: xp}, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// compressors the server may have registered
+var compressorNames = []string{"gzip", "snappy", "zstd"}
+
+func (s *Server) Hello(ctx context.Context, req *api.HelloRequest) (*api.HelloResponse, error) {
+	log.Debugf("received Hello: %v", req)
+	version := api.APIVersion
+	if req.APIVersion > 0 {
+		if req.APIVersion < api.MinAPIVersion {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"API version %d is not supported, the oldest supported one is %d", req.APIVersion, api.MinAPIVersion)
+		}
+		if req.APIVersion < version {
+			version = req.APIVersion
+		}
+	}
+	rsp := &api.HelloResponse{
+		APIVersion:    version,
+		MinAPIVersion: api.MinAPIVersion,
+		Capabilities:  capabilities(),
+	}
+	for _, name := range req.Capabilities {
+		if rsp.Capability(name) != nil {
+			rsp.Negotiated = append(rsp.Negotiated, name)
+		}
+	}
+	return rsp, nil
+}
+
+// capabilities returns the capabilities of the server, sorted by name.
+func capabilities() []*api.Capability {
+	cs := []*api.Capability{
+		{Name: api.CapabilityPagination, Values: []string{"ExpandWildcardPath", "ListSchemaElements"}},
+		{Name: api.CapabilityFieldMask, Values: []string{"ListSchemaElements"}},
+		{Name: api.CapabilityVersionFallback, Values: []string{versionFallbackNearest}},
+	}
+	var compressors []string
+	for _, name := range compressorNames {
+		if encoding.GetCompressor(name) != nil {
+			compressors = append(compressors, name)
+		}
+	}
+	if len(compressors) > 0 {
+		cs = append(cs, &api.Capability{Name: api.CapabilityCompression, Values: compressors})
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Name < cs[j].Name })
+	return cs
+}
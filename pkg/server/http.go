@@ -0,0 +1,131 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"net/netip"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *Server) registerPprof() {
+	s.router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	s.router.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	s.router.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	s.router.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	s.router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
+}
+
+// allowedClientsMiddleware rejects requests from clients
+// not in the configured allowed list.
+func (s *Server) allowedClientsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		addr, err := netip.ParseAddr(host)
+		if err != nil || !s.config.Prometheus.IsAllowed(addr) {
+			log.Debugf("rejected HTTP request from %s to %s", r.RemoteAddr, r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// basicAuthMiddleware requires the basic auth credentials on the HTTP requests
+// but the REST ones when the gRPC authentication is configured: they carry
+// a bearer token in their Authorization header, checked as the gRPC ones.
+func (s *Server) basicAuthMiddleware(next http.Handler) http.Handler {
+	ba := s.config.Prometheus.BasicAuth
+	bearer := s.config.GRPCServer != nil && s.config.GRPCServer.Authentication != nil
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bearer && (r.URL.Path == restPrefix || strings.HasPrefix(r.URL.Path, restPrefix+"/")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(ba.Username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(pass), []byte(ba.Password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="schema-server"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeHTTPProto(w http.ResponseWriter, m proto.Message) {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+func writeHTTPJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Errorf("failed to write HTTP response: %v", err)
+	}
+}
+
+func writeHTTPError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.DeadlineExceeded:
+		code = http.StatusGatewayTimeout
+	case codes.Unavailable:
+		code = http.StatusServiceUnavailable
+	case codes.FailedPrecondition, codes.ResourceExhausted:
+		code = http.StatusUnprocessableEntity
+	}
+	body := map[string]string{
+		"code":  st.Code().String(),
+		"error": st.Message(),
+	}
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RequestInfo); ok {
+			body["request-id"] = ri.GetRequestId()
+		}
+	}
+	writeHTTPJSON(w, code, body)
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/schema"
+)
+
+func (s *Server) ListIdentities(ctx context.Context, req *api.ListIdentitiesRequest) (*api.ListIdentitiesResponse, error) {
+	log.Debugf("received ListIdentities: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.schemaStore.GetSchemaIdentities(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	if req.Base != "" {
+		base, err := findIdentity(ids, req.Base)
+		if err != nil {
+			return nil, err
+		}
+		ids = derivedIdentities(ids, base)
+	}
+	rsp := &api.ListIdentitiesResponse{
+		Identities: make([]*api.Identity, 0, len(ids)),
+	}
+	for _, id := range ids {
+		rsp.Identities = append(rsp.Identities, &api.Identity{
+			Name:        id.Name,
+			Module:      id.Module,
+			Prefix:      id.Prefix,
+			Qualified:   id.QualifiedName(),
+			Prefixed:    id.Prefix + ":" + id.Name,
+			Bases:       id.Bases,
+			Description: id.Description,
+		})
+	}
+	return rsp, nil
+}
+
+// findIdentity returns the identity named name, qualified by
+// its module or prefix or not qualified if it is not ambiguous.
+func findIdentity(ids []*schema.Identity, name string) (*schema.Identity, error) {
+	qual := ""
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		qual, name = name[:idx], name[idx+1:]
+	}
+	var found []*schema.Identity
+	for _, id := range ids {
+		if id.Name != name {
+			continue
+		}
+		if qual == "" || qual == id.Module || qual == id.Prefix {
+			found = append(found, id)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown identity %q", name)
+	case 1:
+		return found[0], nil
+	}
+	names := make([]string, 0, len(found))
+	for _, id := range found {
+		names = append(names, id.QualifiedName())
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "ambiguous identity %q, one of %s",
+		name, strings.Join(names, ", "))
+}
+
+// derivedIdentities returns the identities derived from base, directly or not,
+// sorted by module qualified name.
+func derivedIdentities(ids []*schema.Identity, base *schema.Identity) []*schema.Identity {
+	derived := make(map[string][]*schema.Identity)
+	for _, id := range ids {
+		for _, b := range id.Bases {
+			derived[b] = append(derived[b], id)
+		}
+	}
+	seen := map[string]struct{}{base.QualifiedName(): {}}
+	var rs []*schema.Identity
+	queue := []string{base.QualifiedName()}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, id := range derived[cur] {
+			qn := id.QualifiedName()
+			if _, ok := seen[qn]; ok {
+				continue
+			}
+			seen[qn] = struct{}{}
+			rs = append(rs, id)
+			queue = append(queue, qn)
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].QualifiedName() < rs[j].QualifiedName()
+	})
+	return rs
+}
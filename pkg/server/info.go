@@ -0,0 +1,77 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+// BuildInfo holds the values set at build time using ldflags.
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+func (s *Server) SetBuildInfo(bi BuildInfo) {
+	s.buildInfo = bi
+}
+
+func (s *Server) GetServerInfo(ctx context.Context, req *api.GetServerInfoRequest) (*api.GetServerInfoResponse, error) {
+	log.Debugf("received GetServerInfo: %v", req)
+	return &api.GetServerInfoResponse{
+		Version:   s.buildInfo.Version,
+		Commit:    s.buildInfo.Commit,
+		BuildDate: s.buildInfo.Date,
+		GoVersion: runtime.Version(),
+		Features:  s.features(),
+		Limits: &api.Limits{
+			MaxRecvMsgSize: s.config.GRPCServer.MaxRecvMsgSize,
+			RPCTimeout:     s.config.GRPCServer.RPCTimeout.Milliseconds(),
+		},
+	}, nil
+}
+
+// features returns the list of optional features enabled in the server config.
+func (s *Server) features() []string {
+	fs := []string{"store-" + s.config.SchemaStore.Type}
+	if s.config.SchemaStore.Cache != nil {
+		fs = append(fs, "store-cache")
+	}
+	if s.config.GRPCServer.TLS != nil {
+		fs = append(fs, "tls")
+	}
+	if s.config.GRPCServer.SchemaServer != nil && s.config.GRPCServer.SchemaServer.SchemasDirectory != "" {
+		fs = append(fs, "upload")
+	}
+	if s.config.Prometheus != nil {
+		fs = append(fs, "metrics")
+		if s.config.Prometheus.UI {
+			fs = append(fs, "ui")
+		}
+	}
+	if s.config.GRPCServer.Journal != nil {
+		fs = append(fs, "journal")
+	}
+	if s.authorizer != nil {
+		fs = append(fs, "authz")
+	}
+	return fs
+}
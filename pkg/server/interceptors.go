@@ -0,0 +1,165 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// interceptors returns the unary and stream interceptors chained in the configured order.
+// A nil interceptor does not apply to that kind of RPC.
+func (s *Server) interceptors(c *config.Config, grpcMetrics *grpc_prometheus.ServerMetrics) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	chain := c.GRPCServer.Interceptors.Chain()
+	unary := make([]grpc.UnaryServerInterceptor, 0, len(chain))
+	stream := make([]grpc.StreamServerInterceptor, 0, len(chain))
+	for _, name := range chain {
+		var ui grpc.UnaryServerInterceptor
+		var si grpc.StreamServerInterceptor
+		switch name {
+		case config.InterceptorTracing:
+			ui, si = tracingUnaryInterceptor, tracingStreamInterceptor
+		case config.InterceptorAttribution:
+			ui, si = s.attributionUnaryInterceptor, s.attributionStreamInterceptor
+		case config.InterceptorRecovery:
+			ui, si = recoveryUnaryInterceptor, recoveryStreamInterceptor
+		case config.InterceptorLogging:
+			ui, si = loggingUnaryInterceptor, loggingStreamInterceptor
+		case config.InterceptorJournal:
+			ui, si = s.journal.unaryInterceptor, s.journal.streamInterceptor
+		case config.InterceptorTimeout:
+			ui = timeoutUnaryInterceptor(c.GRPCServer.RPCTimeout)
+		case config.InterceptorMetrics:
+			ui = grpc_middleware.ChainUnaryServer(s.load.unaryInterceptor, grpcMetrics.UnaryServerInterceptor())
+			si = grpc_middleware.ChainStreamServer(s.load.streamInterceptor, grpcMetrics.StreamServerInterceptor())
+		case config.InterceptorRateLimit:
+			rl := c.GRPCServer.Interceptors.RateLimit
+			l := &rateLimiter{limiter: rate.NewLimiter(rate.Limit(rl.Rate), rl.Burst)}
+			ui, si = l.unaryInterceptor, l.streamInterceptor
+		case config.InterceptorAuthn:
+			ui, si = s.authnUnaryInterceptor, s.authnStreamInterceptor
+		case config.InterceptorAuthz:
+			ui, si = s.authzUnaryInterceptor, s.authzStreamInterceptor
+		case config.InterceptorSharding:
+			ui, si = s.shardingUnaryInterceptor, s.shardingStreamInterceptor
+		case config.InterceptorTargetProfile:
+			ui, si = s.targetProfileUnaryInterceptor, s.targetProfileStreamInterceptor
+		case config.InterceptorCanary:
+			ui, si = s.canaryUnaryInterceptor, s.canaryStreamInterceptor
+		case config.InterceptorLifecycle:
+			ui, si = s.lifecycleUnaryInterceptor, s.lifecycleStreamInterceptor
+		}
+		if ui != nil {
+			unary = append(unary, ui)
+		}
+		if si != nil {
+			stream = append(stream, si)
+		}
+	}
+	log.Infof("gRPC interceptors: %v", chain)
+	return unary, stream
+}
+
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cfn := context.WithTimeout(ctx, timeout)
+		defer cfn()
+		return handler(ctx, req)
+	}
+}
+
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (rsp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func recovered(method string, r interface{}) error {
+	log.Errorf("panic handling %s: %v\n%s", method, r, debug.Stack())
+	return status.Errorf(codes.Internal, "panic handling %s", method)
+}
+
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	logRPC(ctx, info.FullMethod, start, err)
+	return rsp, err
+}
+
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logRPC(ss.Context(), info.FullMethod, start, err)
+	return err
+}
+
+func logRPC(ctx context.Context, method string, start time.Time, err error) {
+	f := log.Fields{
+		"code":     status.Code(err).String(),
+		"duration": time.Since(start).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		f["peer"] = p.Addr.String()
+	}
+	if a := attributionFromContext(ctx); a != nil {
+		for k, v := range a.fields() {
+			f[k] = v
+		}
+	}
+	log.WithFields(f).Infof("rpc %s", method)
+}
+
+// rateLimiter fails the RPCs exceeding its token bucket.
+type rateLimiter struct {
+	limiter *rate.Limiter
+}
+
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !l.limiter.Allow() {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded, %s not served", info.FullMethod)
+	}
+	return handler(ctx, req)
+}
+
+func (l *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !l.limiter.Allow() {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, %s not served", info.FullMethod)
+	}
+	return handler(srv, ss)
+}
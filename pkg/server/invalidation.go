@@ -0,0 +1,135 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+func (s *Server) WatchInvalidations(req *api.WatchInvalidationsRequest, stream api.SchemaServerExt_WatchInvalidationsServer) error {
+	log.Debugf("received WatchInvalidations: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return err
+	}
+	sc := schemaOf(sck)
+	// subscribed first, a reload happening while the first invalidation
+	// is computed is reported by the next one
+	w, cancel := s.watchers.subscribe([]*sdcpb.Schema{sc})
+	defer cancel()
+	ctx := stream.Context()
+	cur, err := s.snapshot(ctx, sc, sck)
+	if err != nil {
+		return err
+	}
+	inv := &api.Invalidation{Schema: sc, To: cur.fingerprint, Time: time.Now()}
+	if req.Fingerprint != "" {
+		inv.From = req.Fingerprint
+		if from := s.snapshots.get(sck, req.Fingerprint); from != nil {
+			inv.Stale = staleNodes(from, cur)
+		} else {
+			inv.Full = true
+		}
+	}
+	if err := stream.Send(inv); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.dropped:
+			// the client resumes from the last fingerprint it received
+			return status.Errorf(codes.ResourceExhausted, "more than %d schema events pending, watch again", watchBufferSize)
+		case ev := <-w.events:
+			inv := &api.Invalidation{Schema: sc, Time: ev.Time}
+			if cur != nil {
+				inv.From = cur.fingerprint
+			}
+			switch ev.Type {
+			case api.SchemaEventRemoved:
+				inv.Full = true
+				cur = nil
+			default:
+				next, err := s.snapshot(ctx, sc, sck)
+				if err != nil {
+					// removed since
+					log.Debugf("failed to snapshot schema %s: %v", sck, err)
+					continue
+				}
+				inv.To = next.fingerprint
+				switch {
+				case cur == nil:
+					inv.Full = true
+				case cur.fingerprint == next.fingerprint:
+					// the content did not change, e.g. a reload of unchanged sources
+					cur = next
+					continue
+				default:
+					inv.Stale = staleNodes(cur, next)
+				}
+				cur = next
+			}
+			if err := stream.Send(inv); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// staleNodes returns the nodes changed between snapshots from and to, sorted by path.
+func staleNodes(from, to *schemaSnapshot) []*api.StaleNode {
+	var stale []*api.StaleNode
+	var walk func(names []string)
+	walk = func(names []string) {
+		p := nodePath(names)
+		fn, tn := from.nodes[p], to.nodes[p]
+		if bytes.Equal(fn.tree, tn.tree) {
+			return
+		}
+		if !bytes.Equal(fn.self, tn.self) {
+			stale = append(stale, &api.StaleNode{Path: p, Checksum: hex.EncodeToString(tn.tree)})
+		}
+		cnames := func(c string) []string {
+			return append(names[:len(names):len(names)], c)
+		}
+		for _, c := range fn.children {
+			if _, ok := to.nodes[nodePath(cnames(c))]; !ok {
+				stale = append(stale, &api.StaleNode{Path: nodePath(cnames(c)), Subtree: true})
+			}
+		}
+		for _, c := range tn.children {
+			cp := nodePath(cnames(c))
+			if _, ok := from.nodes[cp]; !ok {
+				stale = append(stale, &api.StaleNode{Path: cp, Subtree: true, Checksum: hex.EncodeToString(to.nodes[cp].tree)})
+				continue
+			}
+			walk(cnames(c))
+		}
+	}
+	walk(nil)
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Path < stale[j].Path })
+	return stale
+}
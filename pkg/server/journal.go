@@ -0,0 +1,178 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// journal is a ring buffer of the last requests handled by the server.
+type journal struct {
+	m       *sync.Mutex
+	entries []*api.JournalEntry
+	// index of the next entry to overwrite once the buffer is full
+	next int
+}
+
+func newJournal(size int) *journal {
+	return &journal{
+		m:       new(sync.Mutex),
+		entries: make([]*api.JournalEntry, 0, size),
+	}
+}
+
+func (j *journal) add(e *api.JournalEntry) {
+	j.m.Lock()
+	defer j.m.Unlock()
+	if len(j.entries) < cap(j.entries) {
+		j.entries = append(j.entries, e)
+		return
+	}
+	j.entries[j.next] = e
+	j.next = (j.next + 1) % len(j.entries)
+}
+
+// list returns the last entries of the methods ending with method, oldest first.
+func (j *journal) list(last int, method string) []*api.JournalEntry {
+	j.m.Lock()
+	defer j.m.Unlock()
+	rs := make([]*api.JournalEntry, 0, len(j.entries))
+	for i := range j.entries {
+		e := j.entries[(j.next+i)%len(j.entries)]
+		if method != "" && !strings.HasSuffix(e.Method, method) {
+			continue
+		}
+		rs = append(rs, e)
+	}
+	if last > 0 && len(rs) > last {
+		rs = rs[len(rs)-last:]
+	}
+	return rs
+}
+
+func (j *journal) record(ctx context.Context, method string, req interface{}, start time.Time, err error) {
+	e := &api.JournalEntry{
+		Time:    start,
+		Method:  method,
+		Latency: time.Since(start).Microseconds(),
+		Code:    status.Code(err).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		e.Peer = p.Addr.String()
+	}
+	if err != nil {
+		e.Error = status.Convert(err).Message()
+	}
+	if a := attributionFromContext(ctx); a != nil {
+		e.RequestID = a.RequestID
+		e.Tenant = a.Tenant
+		e.TraceParent = a.TraceParent
+		e.Subject = a.Subject
+	}
+	e.Schema, e.Paths = requestInfo(req)
+	j.add(e)
+}
+
+func (j *journal) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	// do not journal the journal dumps
+	if !strings.HasSuffix(info.FullMethod, "/GetRequestJournal") {
+		j.record(ctx, info.FullMethod, req, start, err)
+	}
+	return rsp, err
+}
+
+func (j *journal) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	js := &journalStream{ServerStream: ss}
+	err := handler(srv, js)
+	j.record(ss.Context(), info.FullMethod, js.first, start, err)
+	return err
+}
+
+// journalStream keeps the first message received on a stream
+// to journal the schema and path it refers to.
+type journalStream struct {
+	grpc.ServerStream
+	first interface{}
+}
+
+func (s *journalStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first = m
+	}
+	return err
+}
+
+// requestInfo returns the schema and the xpaths a request refers to.
+func requestInfo(req interface{}) (*sdcpb.Schema, []string) {
+	var sc *sdcpb.Schema
+	var paths []*sdcpb.Path
+	switch req := req.(type) {
+	case interface{ GetSchema() *sdcpb.Schema }:
+		sc = req.GetSchema()
+	case *sdcpb.UploadSchemaRequest:
+		sc = req.GetCreateSchema().GetSchema()
+	}
+	if req, ok := req.(interface{ GetPath() *sdcpb.Path }); ok && req.GetPath() != nil {
+		paths = append(paths, req.GetPath())
+	}
+	// the SchemaServerExt requests are plain structs
+	if v := reflect.Indirect(reflect.ValueOf(req)); v.Kind() == reflect.Struct && v.Type().PkgPath() != reflect.TypeOf(sdcpb.Schema{}).PkgPath() {
+		if f := v.FieldByName("Schema"); f.IsValid() && f.CanInterface() {
+			sc, _ = f.Interface().(*sdcpb.Schema)
+		}
+		if f := v.FieldByName("Path"); f.IsValid() && f.CanInterface() {
+			if p, ok := f.Interface().(*sdcpb.Path); ok && p != nil {
+				paths = append(paths, p)
+			}
+		}
+		if f := v.FieldByName("Paths"); f.IsValid() && f.CanInterface() {
+			ps, _ := f.Interface().([]*sdcpb.Path)
+			paths = append(paths, ps...)
+		}
+	}
+	xpaths := make([]string, 0, len(paths))
+	for _, p := range paths {
+		xpaths = append(xpaths, utils.ToXPath(p, false))
+	}
+	return sc, xpaths
+}
+
+func (s *Server) GetRequestJournal(ctx context.Context, req *api.GetRequestJournalRequest) (*api.GetRequestJournalResponse, error) {
+	log.Debugf("received GetRequestJournal: %v", req)
+	if s.journal == nil {
+		return nil, status.Error(codes.FailedPrecondition, "request journal is not enabled")
+	}
+	return &api.GetRequestJournalResponse{
+		Entries: s.journal.list(req.Last, req.Method),
+	}, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+func (s *Server) GetKeyConstraints(ctx context.Context, req *api.GetKeyConstraintsRequest) (*api.GetKeyConstraintsResponse, error) {
+	log.Debugf("received GetKeyConstraints: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Path.GetElem()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+	lr := &leafrefResolver{r: store.NewResolver(s.schemaStore, req.Schema)}
+	err := lr.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := lr.qualify(ctx, elemNames(req.Path))
+	if err != nil {
+		return nil, err
+	}
+	// module elements do not have keys
+	offset := len(req.Path.GetElem()) - len(names)
+	rsp := &api.GetKeyConstraintsResponse{Keys: make([]*api.KeyConstraints, 0)}
+	list := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(req.Path.GetElem()))}
+	for i, pe := range req.Path.GetElem() {
+		if i < offset {
+			continue
+		}
+		lnames := names[:i-offset+1]
+		se, err := lr.r.Get(ctx, lnames)
+		if err != nil {
+			return nil, err
+		}
+		cs := se.GetContainer()
+		for k := range pe.GetKey() {
+			if !isListKey(cs, k) {
+				return nil, status.Errorf(codes.InvalidArgument, "element %s of path %s has no key %s",
+					pe.GetName(), utils.ToXPath(req.Path, false), k)
+			}
+		}
+		keys, err := lr.r.Keys(ctx, lnames, cs.GetKeys())
+		if err != nil {
+			return nil, err
+		}
+		lpe := &sdcpb.PathElem{Name: pe.GetName()}
+		if len(keys) > 0 {
+			lpe.Key = make(map[string]string, len(keys))
+		}
+		var wildcarded []*sdcpb.LeafSchema
+		for _, k := range keys {
+			v, ok := pe.GetKey()[k.GetName()]
+			if !ok || v == wildcardElem {
+				wildcarded = append(wildcarded, k)
+				v = wildcardElem
+			}
+			lpe.Key[k.GetName()] = v
+		}
+		list.Elem = append(list.Elem, lpe)
+		for _, k := range wildcarded {
+			kc := &api.KeyConstraints{
+				Elem: i,
+				Key:  k.GetName(),
+				List: "/" + utils.ToXPath(list, false),
+				Type: k.GetType(),
+			}
+			knames := make([]string, 0, len(lnames)+1)
+			knames = append(knames, lnames...)
+			kc.Members, err = lr.typeConstraints(ctx, append(knames, k.GetName()), k.GetType(), nil)
+			if err != nil {
+				return nil, err
+			}
+			rsp.Keys = append(rsp.Keys, kc)
+		}
+	}
+	return rsp, nil
+}
+
+func isListKey(cs *sdcpb.ContainerSchema, name string) bool {
+	for _, k := range cs.GetKeys() {
+		if k.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeConstraints appends the constraints of type t of the leaf found at names to cs,
+// one per union member. The leafrefs are replaced by the type of their target.
+func (lr *leafrefResolver) typeConstraints(ctx context.Context, names []string, t *sdcpb.SchemaLeafType, cs []*api.TypeConstraints) ([]*api.TypeConstraints, error) {
+	switch t.GetType() {
+	case "union":
+		var err error
+		for _, mt := range t.GetUnionTypes() {
+			cs, err = lr.typeConstraints(ctx, names, mt, cs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return cs, nil
+	case "leafref":
+		target, err := lr.resolve(ctx, names, t.GetLeafref())
+		if err != nil {
+			return nil, err
+		}
+		n := len(cs)
+		cs, err = lr.typeConstraints(ctx, elemNames(target.Path), target.Type, cs)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs[n:] {
+			c.Leafref = t.GetLeafref()
+			c.Target = target.Xpath
+		}
+		return cs, nil
+	}
+	return append(cs, &api.TypeConstraints{
+		Type:     t.GetType(),
+		TypeName: t.GetTypeName(),
+		Range:    t.GetRange(),
+		Length:   t.GetLength(),
+		Patterns: t.GetPatterns(),
+		Values:   t.GetValues(),
+	}), nil
+}
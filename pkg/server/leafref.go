@@ -0,0 +1,313 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// max number of leafrefs followed to reach a target
+// that is not a leafref itself
+const maxLeafrefChain = 16
+
+func (s *Server) ResolveLeafref(ctx context.Context, req *api.ResolveLeafrefRequest) (*api.ResolveLeafrefResponse, error) {
+	log.Debugf("received ResolveLeafref: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Path.GetElem()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+	lr := &leafrefResolver{r: store.NewResolver(s.schemaStore, req.Schema)}
+	err = lr.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := lr.qualify(ctx, elemNames(req.Path))
+	if err != nil {
+		return nil, err
+	}
+	se, err := lr.r.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	typ, isState, ok := leafType(se)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "path %s is not a leaf or a leaf-list", utils.ToXPath(req.Path, true))
+	}
+	exprs := leafrefPaths(typ)
+	if len(exprs) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "path %s is not a leafref", utils.ToXPath(req.Path, true))
+	}
+	optional, err := s.schemaStore.GetSchemaOptionalLeafrefs(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	key := "/" + strings.Join(stripPrefixes(names), "/")
+	idx := sort.SearchStrings(optional, key)
+	rsp := &api.ResolveLeafrefResponse{
+		OptionalInstance: idx < len(optional) && optional[idx] == key,
+	}
+	for _, expr := range exprs {
+		t, err := lr.resolve(ctx, names, expr)
+		if err != nil {
+			return nil, err
+		}
+		if !rsp.OptionalInstance && !isState && t.IsState {
+			t.Warnings = append(t.Warnings,
+				fmt.Sprintf("config leafref requiring an instance refers to the state node %s", t.Xpath))
+		}
+		rsp.Targets = append(rsp.Targets, t)
+	}
+	return rsp, nil
+}
+
+type leafrefResolver struct {
+	r *store.Resolver
+	// module names and module names by prefix
+	modules  map[string]bool
+	prefixes map[string]string
+}
+
+func (lr *leafrefResolver) loadModules(ctx context.Context) error {
+	root, err := lr.r.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	lr.modules = make(map[string]bool)
+	lr.prefixes = make(map[string]string)
+	for _, m := range root.GetContainer().GetChildren() {
+		mse, err := lr.r.Get(ctx, []string{m})
+		if err != nil {
+			return err
+		}
+		lr.modules[m] = true
+		if p := mse.GetContainer().GetPrefix(); p != "" {
+			lr.prefixes[p] = m
+		}
+	}
+	return nil
+}
+
+// qualify returns the data path names with its first node qualified with its module,
+// names can start with the module element.
+func (lr *leafrefResolver) qualify(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 || strings.Contains(names[0], ":") {
+		return names, nil
+	}
+	dp := make([]string, 0, len(names))
+	if len(names) > 1 && lr.modules[names[0]] {
+		dp = append(dp, names[0]+":"+names[1])
+		return append(dp, names[2:]...), nil
+	}
+	se, err := lr.r.Get(ctx, names[:1])
+	if err != nil {
+		return nil, err
+	}
+	m, err := lr.r.Module(ctx, se)
+	if err != nil {
+		return nil, err
+	}
+	dp = append(dp, m+":"+names[0])
+	return append(dp, names[1:]...), nil
+}
+
+// resolve returns the target of the leafref path expr of the leaf found at names,
+// the referenced leafrefs are followed.
+func (lr *leafrefResolver) resolve(ctx context.Context, names []string, expr string) (*api.LeafrefTarget, error) {
+	t := &api.LeafrefTarget{Expression: expr}
+	visited := map[string]bool{strings.Join(names, "/"): true}
+	for {
+		p, err := lr.targetPath(ctx, names, expr)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "leafref %q of %s: %v",
+				expr, utils.ToXPath(pathOf(names), true), err)
+		}
+		se, err := lr.r.GetPath(ctx, p)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "leafref %q of %s: %v",
+				expr, utils.ToXPath(pathOf(names), true), err)
+		}
+		typ, isState, ok := leafType(se)
+		if !ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "leafref %q of %s refers to a container",
+				expr, utils.ToXPath(pathOf(names), true))
+		}
+		t.Path = p
+		t.Xpath = utils.ToXPath(p, false)
+		t.Type = typ
+		t.IsState = isState
+		if typ.GetType() != "leafref" {
+			return t, nil
+		}
+		// the target is a leafref itself
+		names = elemNames(p)
+		k := strings.Join(names, "/")
+		if visited[k] {
+			return nil, status.Errorf(codes.FailedPrecondition, "leafref %q: loop through %s", t.Expression, t.Xpath)
+		}
+		if len(t.Chain) >= maxLeafrefChain {
+			return nil, status.Errorf(codes.FailedPrecondition, "leafref %q: more than %d chained leafrefs", t.Expression, maxLeafrefChain)
+		}
+		visited[k] = true
+		t.Chain = append(t.Chain, t.Xpath)
+		expr = typ.GetLeafref()
+	}
+}
+
+// targetPath applies the leafref path expr to the leaf found at names.
+// The key values of its predicates are replaced by the absolute paths of their source leaves.
+func (lr *leafrefResolver) targetPath(ctx context.Context, names []string, expr string) (*sdcpb.Path, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.Contains(expr, "deref(") {
+		return nil, fmt.Errorf("deref() is not supported")
+	}
+	p, err := utils.ParsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	var base []string
+	if !strings.HasPrefix(expr, "/") {
+		base = names
+	}
+	tnames, err := lr.steps(ctx, base, p.GetElem())
+	if err != nil {
+		return nil, err
+	}
+	tp := pathOf(tnames)
+	// predicates apply to the elements they are set on, the last ones of the target path
+	offset := len(tnames) - (len(p.GetElem()) - countParents(p))
+	for i, pe := range p.GetElem()[countParents(p):] {
+		for k, v := range pe.GetKey() {
+			kp, err := utils.ParsePath(strings.TrimSpace(v))
+			if err != nil {
+				return nil, err
+			}
+			// a path key expression starts with current(), the leafref leaf
+			kes := kp.GetElem()
+			if len(kes) == 0 || kes[0].GetName() != "current()" {
+				return nil, fmt.Errorf("predicate %s=%s does not start with current()", strings.TrimSpace(k), strings.TrimSpace(v))
+			}
+			src, err := lr.steps(ctx, names, kes[1:])
+			if err != nil {
+				return nil, err
+			}
+			if _, err := lr.r.Get(ctx, src); err != nil {
+				return nil, fmt.Errorf("predicate %s=%s: %v", strings.TrimSpace(k), strings.TrimSpace(v), err)
+			}
+			tpe := tp.GetElem()[offset+i]
+			if tpe.Key == nil {
+				tpe.Key = make(map[string]string)
+			}
+			tpe.Key[stripPrefix(strings.TrimSpace(k))] = "/" + utils.ToXPath(pathOf(src), true)
+		}
+	}
+	return tp, nil
+}
+
+// steps applies the path elements elems, ".." or a node name, to the data path base.
+// The first data node is qualified with its module.
+func (lr *leafrefResolver) steps(ctx context.Context, base []string, elems []*sdcpb.PathElem) ([]string, error) {
+	rs := append(make([]string, 0, len(base)+len(elems)), base...)
+	for _, pe := range elems {
+		name := pe.GetName()
+		if name == ".." {
+			if len(rs) == 0 {
+				return nil, fmt.Errorf("path goes above the root")
+			}
+			rs = rs[:len(rs)-1]
+			continue
+		}
+		if len(rs) == 0 {
+			if prefix, n, ok := strings.Cut(name, ":"); ok && lr.prefixes[prefix] != "" {
+				rs = append(rs, lr.prefixes[prefix]+":"+n)
+				continue
+			}
+		}
+		rs = append(rs, stripPrefix(name))
+	}
+	return lr.qualify(ctx, rs)
+}
+
+// countParents returns the number of leading ".." elements of p.
+func countParents(p *sdcpb.Path) int {
+	n := 0
+	for _, pe := range p.GetElem() {
+		if pe.GetName() != ".." {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// leafrefPaths returns the path statements of the leafrefs of t
+// and of its union members.
+func leafrefPaths(t *sdcpb.SchemaLeafType) []string {
+	if t.GetType() == "leafref" {
+		return []string{t.GetLeafref()}
+	}
+	var rs []string
+	for _, ut := range t.GetUnionTypes() {
+		rs = append(rs, leafrefPaths(ut)...)
+	}
+	return rs
+}
+
+func leafType(se *sdcpb.SchemaElem) (*sdcpb.SchemaLeafType, bool, bool) {
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Field:
+		return se.Field.GetType(), se.Field.GetIsState(), true
+	case *sdcpb.SchemaElem_Leaflist:
+		return se.Leaflist.GetType(), se.Leaflist.GetIsState(), true
+	}
+	return nil, false, false
+}
+
+func stripPrefix(name string) string {
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+func stripPrefixes(names []string) []string {
+	rs := make([]string, 0, len(names))
+	for _, n := range names {
+		rs = append(rs, stripPrefix(n))
+	}
+	return rs
+}
+
+func pathOf(names []string) *sdcpb.Path {
+	p := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(names))}
+	for _, n := range names {
+		p.Elem = append(p.Elem, &sdcpb.PathElem{Name: n})
+	}
+	return p
+}
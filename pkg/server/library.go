@@ -0,0 +1,104 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+const (
+	conformanceImplement = "implement"
+	conformanceImport    = "import"
+)
+
+// yangLibrary is the RFC 7951 encoding of the modules-state container
+// of the ietf-yang-library module, RFC 8525.
+type yangLibrary struct {
+	ModulesState *modulesState `json:"ietf-yang-library:modules-state"`
+}
+
+type modulesState struct {
+	ModuleSetID string           `json:"module-set-id"`
+	Module      []*libraryModule `json:"module"`
+}
+
+type libraryModule struct {
+	Name string `json:"name"`
+	// the empty string if the module has no revision
+	Revision        string        `json:"revision"`
+	Namespace       string        `json:"namespace"`
+	Feature         []string      `json:"feature,omitempty"`
+	Deviation       []*libraryRef `json:"deviation,omitempty"`
+	ConformanceType string        `json:"conformance-type"`
+	Submodule       []*libraryRef `json:"submodule,omitempty"`
+}
+
+type libraryRef struct {
+	Name     string `json:"name"`
+	Revision string `json:"revision"`
+}
+
+func (s *Server) GetYangLibrary(ctx context.Context, req *api.GetYangLibraryRequest) (*api.GetYangLibraryResponse, error) {
+	log.Debugf("received GetYangLibrary: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := s.fingerprint(ctx, req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	lms, err := s.schemaStore.GetSchemaLibrary(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make(map[string]string, len(lms))
+	for _, lm := range lms {
+		revisions[lm.Name] = lm.Revision
+	}
+	ms := &modulesState{
+		ModuleSetID: fp,
+		Module:      make([]*libraryModule, 0, len(lms)),
+	}
+	for _, lm := range lms {
+		m := &libraryModule{
+			Name:            lm.Name,
+			Revision:        lm.Revision,
+			Namespace:       lm.Namespace,
+			Feature:         lm.Features,
+			ConformanceType: conformanceImplement,
+		}
+		if lm.ImportOnly {
+			m.ConformanceType = conformanceImport
+		}
+		for _, d := range lm.Deviations {
+			m.Deviation = append(m.Deviation, &libraryRef{Name: d, Revision: revisions[d]})
+		}
+		for _, sm := range lm.Submodules {
+			m.Submodule = append(m.Submodule, &libraryRef{Name: sm.Name, Revision: sm.Revision})
+		}
+		ms.Module = append(ms.Module, m)
+	}
+	doc, err := json.Marshal(&yangLibrary{ModulesState: ms})
+	if err != nil {
+		return nil, err
+	}
+	return &api.GetYangLibraryResponse{ModuleSetID: fp, Document: doc}, nil
+}
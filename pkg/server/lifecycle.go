@@ -0,0 +1,267 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+const (
+	// request metadata set to "true" to be served from a staged schema
+	includeStagedHeader = "x-schema-include-staged"
+	// response metadata carrying the deprecation notice of the schema answering the request
+	deprecatedHeader = "x-schema-deprecated"
+)
+
+// RPCs served whatever the lifecycle state of the schema they refer to
+var lifecycleExempt = map[string]bool{
+	"SetSchemaState":   true,
+	"ListSchemaStates": true,
+	"GetSchemaDetails": true,
+	"ReloadSchema":     true,
+	"DeleteSchema":     true,
+	"UploadSchema":     true,
+}
+
+// lifecycle holds the state of the schemas that are not active,
+// it is not kept across restarts.
+type lifecycle struct {
+	m      sync.RWMutex
+	states map[store.SchemaKey]*schemaState
+	// serializes the state changes
+	transition sync.Mutex
+}
+
+type schemaState struct {
+	state   string
+	message string
+	since   time.Time
+	// config an archived schema is restored from
+	config *config.SchemaConfig
+}
+
+func (l *lifecycle) get(sck store.SchemaKey) *schemaState {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	return l.states[sck]
+}
+
+func (l *lifecycle) set(sck store.SchemaKey, st *schemaState) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if st == nil {
+		delete(l.states, sck)
+		return
+	}
+	if l.states == nil {
+		l.states = make(map[store.SchemaKey]*schemaState)
+	}
+	l.states[sck] = st
+}
+
+func (s *Server) SetSchemaState(ctx context.Context, req *api.SetSchemaStateRequest) (*api.SetSchemaStateResponse, error) {
+	log.Debugf("received SetSchemaState: %v", req)
+	switch req.State {
+	case api.SchemaStateStaged, api.SchemaStateActive, api.SchemaStateDeprecated, api.SchemaStateArchived:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema state %q", req.State)
+	}
+	sc := req.Schema
+	if sc.GetVendor() == "" || sc.GetVersion() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing schema vendor or version")
+	}
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+
+	s.lifecycle.transition.Lock()
+	defer s.lifecycle.transition.Unlock()
+	cur := s.lifecycle.get(sck)
+	archived := cur != nil && cur.state == api.SchemaStateArchived
+	var cfg *config.SchemaConfig
+	switch {
+	case archived && req.State == api.SchemaStateArchived:
+		cfg = cur.config
+	case archived:
+		// restore
+		rsc, err := schema.NewSchemaContext(ctx, cur.config)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to restore schema %s: %v", sck, err)
+		}
+		err = s.schemaStore.AddSchema(rsc)
+		if err != nil {
+			return nil, err
+		}
+		s.notify(sck, api.SchemaEventAdded)
+	case !s.schemaStore.HasSchema(sck):
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sc)
+	case req.State == api.SchemaStateArchived:
+		var err error
+		cfg, err = s.schemaStore.GetSchemaConfig(ctx, sck)
+		if err != nil {
+			return nil, err
+		}
+		// the store unloads the schema only, uploaded files are kept to restore it
+		_, err = s.schemaStore.DeleteSchema(ctx, &sdcpb.DeleteSchemaRequest{Schema: schemaOf(sck)})
+		if err != nil {
+			return nil, err
+		}
+		s.notify(sck, api.SchemaEventRemoved)
+	}
+	st := &schemaState{
+		state:   req.State,
+		message: req.Message,
+		since:   time.Now(),
+		config:  cfg,
+	}
+	if cur != nil && cur.state == req.State && cur.message == req.Message {
+		st = cur
+	}
+	if req.State == api.SchemaStateActive {
+		s.lifecycle.set(sck, nil)
+	} else {
+		s.lifecycle.set(sck, st)
+	}
+	log.Infof("schema %s is %s", sck, req.State)
+	return &api.SetSchemaStateResponse{State: st.api(sck)}, nil
+}
+
+func (s *Server) ListSchemaStates(ctx context.Context, req *api.ListSchemaStatesRequest) (*api.ListSchemaStatesResponse, error) {
+	log.Debugf("received ListSchemaStates: %v", req)
+	s.lifecycle.m.RLock()
+	defer s.lifecycle.m.RUnlock()
+	rsp := &api.ListSchemaStatesResponse{States: make([]*api.SchemaState, 0, len(s.lifecycle.states))}
+	for sck, st := range s.lifecycle.states {
+		rsp.States = append(rsp.States, st.api(sck))
+	}
+	sort.Slice(rsp.States, func(i, j int) bool {
+		return schemaLess(rsp.States[i].Schema, rsp.States[j].Schema)
+	})
+	return rsp, nil
+}
+
+func (st *schemaState) api(sck store.SchemaKey) *api.SchemaState {
+	return &api.SchemaState{
+		Schema:  schemaOf(sck),
+		State:   st.state,
+		Message: st.message,
+		Since:   st.since,
+	}
+}
+
+// checkLifecycle refuses the requests for a staged or an archived schema and adds the
+// deprecation notice of a deprecated one to the response metadata.
+func (s *Server) checkLifecycle(ctx context.Context, method string, req interface{}) error {
+	if lifecycleExempt[path.Base(method)] {
+		return nil
+	}
+	sc, _ := requestInfo(req)
+	if sc == nil {
+		return nil
+	}
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+	st := s.lifecycle.get(sck)
+	if st == nil {
+		return nil
+	}
+	switch st.state {
+	case api.SchemaStateStaged:
+		if includeStaged(ctx) || canaryClient(ctx) {
+			return nil
+		}
+		return reasonError(codes.FailedPrecondition, api.ReasonSchemaStaged, schemaErrorInfo(sck),
+			"schema %s is staged", sck)
+	case api.SchemaStateArchived:
+		return reasonError(codes.FailedPrecondition, api.ReasonSchemaArchived, schemaErrorInfo(sck),
+			"schema %s is archived", sck)
+	case api.SchemaStateDeprecated:
+		msg := st.message
+		if msg == "" {
+			msg = fmt.Sprintf("schema %s is deprecated", sck)
+		}
+		log.Warnf("%s called for deprecated schema %s", path.Base(method), sck)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(deprecatedHeader, msg))
+	}
+	return nil
+}
+
+func schemaOf(sck store.SchemaKey) *sdcpb.Schema {
+	return &sdcpb.Schema{Name: sck.Name, Vendor: sck.Vendor, Version: sck.Version}
+}
+
+func includeStaged(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && firstValue(md, includeStagedHeader) == "true"
+}
+
+// hideStaged removes the staged schemas from a ListSchema response,
+// unless the client asked for them.
+func (s *Server) hideStaged(ctx context.Context, rsp *sdcpb.ListSchemaResponse) {
+	if includeStaged(ctx) {
+		return
+	}
+	scs := rsp.GetSchema()[:0]
+	for _, sc := range rsp.GetSchema() {
+		st := s.lifecycle.get(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+		if st == nil || st.state != api.SchemaStateStaged {
+			scs = append(scs, sc)
+		}
+	}
+	rsp.Schema = scs
+}
+
+func (s *Server) lifecycleUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := s.checkLifecycle(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) lifecycleStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &lifecycleStream{ServerStream: ss, s: s, method: info.FullMethod})
+}
+
+// lifecycleStream checks the schema a stream refers to when its first message is received.
+type lifecycleStream struct {
+	grpc.ServerStream
+	s       *Server
+	method  string
+	checked bool
+}
+
+func (ls *lifecycleStream) RecvMsg(m interface{}) error {
+	err := ls.ServerStream.RecvMsg(m)
+	if err != nil || ls.checked {
+		return err
+	}
+	ls.checked = true
+	return ls.s.checkLifecycle(ls.Context(), ls.method, m)
+}
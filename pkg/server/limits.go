@@ -0,0 +1,123 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// schemaLimits returns the limits configured for schema sc,
+// the schema store default limits if it has none. They are the ones
+// of the config file last read.
+func (s *Server) schemaLimits(sc *sdcpb.Schema) *config.SchemaLimits {
+	return s.configured.schemaLimits(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+}
+
+// checkSubtreeLimits walks the schema subtree found at path p and fails
+// as soon as it exceeds the depth or node count limits of the schema.
+// With a depth limit only, the walk is skipped if the max depth of the schema
+// leaves no room for a subtree deeper than the limit.
+func (s *Server) checkSubtreeLimits(ctx context.Context, sc *sdcpb.Schema, p *sdcpb.Path) error {
+	l := s.schemaLimits(sc)
+	if l == nil || (l.MaxDepth == 0 && l.MaxNodes == 0) {
+		return nil
+	}
+	if l.MaxNodes == 0 {
+		sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+		// the first path element can be a module, which is not a level of the schema tree
+		if st, err := s.schemaStore.GetSchemaStats(ctx, sck); err == nil && st != nil && st.MaxDepth > 0 &&
+			st.MaxDepth-len(p.GetElem())+1 <= l.MaxDepth {
+			return nil
+		}
+	}
+	w := &limitsWalker{
+		resolver: store.NewResolver(s.schemaStore, sc),
+		limits:   l,
+		path:     p,
+	}
+	names := make([]string, 0, len(p.GetElem()))
+	for _, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+	}
+	return w.walk(ctx, names, 0)
+}
+
+type limitsWalker struct {
+	resolver *store.Resolver
+	limits   *config.SchemaLimits
+	path     *sdcpb.Path
+	nodes    int
+}
+
+func (w *limitsWalker) visit(depth int) error {
+	w.nodes++
+	if w.limits.MaxNodes > 0 && w.nodes > w.limits.MaxNodes {
+		return reasonError(codes.ResourceExhausted, api.ReasonSubtreeTooLarge,
+			map[string]string{"path": utils.ToXPath(w.path, true), "limit": fmt.Sprintf("%d node(s)", w.limits.MaxNodes)},
+			"schema subtree at %q exceeds the max node count of %d", utils.ToXPath(w.path, true), w.limits.MaxNodes)
+	}
+	if w.limits.MaxDepth > 0 && depth > w.limits.MaxDepth {
+		return reasonError(codes.ResourceExhausted, api.ReasonSubtreeTooLarge,
+			map[string]string{"path": utils.ToXPath(w.path, true), "limit": fmt.Sprintf("depth %d", w.limits.MaxDepth)},
+			"schema subtree at %q exceeds the max depth of %d", utils.ToXPath(w.path, true), w.limits.MaxDepth)
+	}
+	return nil
+}
+
+func (w *limitsWalker) walk(ctx context.Context, names []string, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := w.visit(depth); err != nil {
+		return err
+	}
+	se, err := w.resolver.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	cs := se.GetContainer()
+	if cs == nil {
+		return nil
+	}
+	// keys are part of the fields, without a node count limit
+	// a single leaf tells whether they exceed the depth limit
+	leaves := len(cs.GetFields()) + len(cs.GetLeaflists())
+	if w.limits.MaxNodes == 0 && leaves > 1 {
+		leaves = 1
+	}
+	for i := 0; i < leaves; i++ {
+		if err := w.visit(depth + 1); err != nil {
+			return err
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, c)
+		if err := w.walk(ctx, cnames, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
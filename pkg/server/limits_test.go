@@ -0,0 +1,199 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/memstore"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const limitsModule = `module t {
+  namespace "urn:t";
+  prefix t;
+  container c {
+    leaf y { type string; }
+    container d {
+      leaf x { type string; }
+    }
+  }
+}
+`
+
+// countingStore counts the schema elements resolved.
+type countingStore struct {
+	store.Store
+	gets int
+}
+
+func (c *countingStore) GetSchema(ctx context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
+	c.gets++
+	return c.Store.GetSchema(ctx, req)
+}
+
+// testStore returns a store with the schema t@v@1 made of module m.
+func testStore(t *testing.T, m string) store.Store {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "t.yang"), []byte(m), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sc, err := schema.NewSchema(&config.SchemaConfig{Name: "t", Vendor: "v", Version: "1", Files: []string{dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := memstore.New()
+	if err = st.AddSchema(sc); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestServer_checkSubtreeLimits(t *testing.T) {
+	st := testStore(t, limitsModule)
+	tests := []struct {
+		name   string
+		path   string
+		limits config.SchemaLimits
+		walked bool
+		code   codes.Code
+	}{
+		// the modules are a level below the root
+		{name: "root within schema depth", limits: config.SchemaLimits{MaxDepth: 4}},
+		{name: "root too deep", limits: config.SchemaLimits{MaxDepth: 3}, walked: true, code: codes.ResourceExhausted},
+		{name: "within schema depth", path: "/c", limits: config.SchemaLimits{MaxDepth: 3}},
+		{name: "within depth", path: "/c", limits: config.SchemaLimits{MaxDepth: 2}, walked: true},
+		{name: "too deep", path: "/c", limits: config.SchemaLimits{MaxDepth: 1}, walked: true, code: codes.ResourceExhausted},
+		{name: "within nodes", path: "/c", limits: config.SchemaLimits{MaxNodes: 4}, walked: true},
+		{name: "too many nodes", path: "/c", limits: config.SchemaLimits{MaxNodes: 3}, walked: true, code: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &countingStore{Store: st}
+			s := &Server{
+				schemaStore: cs,
+				configured:  configuredSchemas{defaultLimits: &tt.limits},
+			}
+			p := &sdcpb.Path{}
+			if tt.path != "" {
+				var err error
+				p, err = utils.ParsePath(tt.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := s.checkSubtreeLimits(context.Background(), &sdcpb.Schema{Name: "t", Vendor: "v", Version: "1"}, p)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if walked := cs.gets > 0; walked != tt.walked {
+				t.Errorf("got walked %v, want %v", walked, tt.walked)
+			}
+		})
+	}
+}
+
+// subtreeStream counts the responses sent.
+type subtreeStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (ss *subtreeStream) Context() context.Context { return context.Background() }
+
+func (ss *subtreeStream) Send(*api.GetSchemaSubtreeResponse) error {
+	ss.sent++
+	return nil
+}
+
+// exportStream counts the responses sent.
+type exportStream struct {
+	grpc.ServerStream
+	sent int
+}
+
+func (es *exportStream) Context() context.Context { return context.Background() }
+
+func (es *exportStream) Send(*api.ExportSchemaResponse) error {
+	es.sent++
+	return nil
+}
+
+func TestServer_streamedSubtreeLimits(t *testing.T) {
+	st := testStore(t, limitsModule)
+	sc := &sdcpb.Schema{Name: "t", Vendor: "v", Version: "1"}
+	tests := []struct {
+		name   string
+		path   string
+		limits config.SchemaLimits
+		// codes of GetSchemaSubtree and ExportSchema
+		subtreeCode codes.Code
+		exportCode  codes.Code
+	}{
+		{name: "within limits", path: "/c", limits: config.SchemaLimits{MaxNodes: 4}},
+		{name: "too many nodes", path: "/c", limits: config.SchemaLimits{MaxNodes: 3},
+			subtreeCode: codes.ResourceExhausted, exportCode: codes.ResourceExhausted},
+		{name: "too deep", path: "/c", limits: config.SchemaLimits{MaxDepth: 1},
+			subtreeCode: codes.ResourceExhausted, exportCode: codes.ResourceExhausted},
+		// the whole schema can be exported
+		{name: "root", limits: config.SchemaLimits{MaxNodes: 3}, subtreeCode: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{
+				config:      &config.Config{GRPCServer: &config.GRPCServer{Streaming: &config.StreamingConfig{BatchSize: 16}}},
+				schemaStore: st,
+				configured:  configuredSchemas{defaultLimits: &tt.limits},
+			}
+			var p *sdcpb.Path
+			if tt.path != "" {
+				var err error
+				p, err = utils.ParsePath(tt.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			ss := new(subtreeStream)
+			err := s.GetSchemaSubtree(&api.GetSchemaSubtreeRequest{Schema: sc, Path: p}, ss)
+			if status.Code(err) != tt.subtreeCode {
+				t.Errorf("GetSchemaSubtree() error = %v, want code %v", err, tt.subtreeCode)
+			}
+			if (ss.sent > 0) != (tt.subtreeCode == codes.OK) {
+				t.Errorf("GetSchemaSubtree() sent %d responses", ss.sent)
+			}
+			es := new(exportStream)
+			err = s.ExportSchema(&api.ExportSchemaRequest{Schema: sc, Path: p}, es)
+			if status.Code(err) != tt.exportCode {
+				t.Errorf("ExportSchema() error = %v, want code %v", err, tt.exportCode)
+			}
+			if (es.sent > 0) != (tt.exportCode == codes.OK) {
+				t.Errorf("ExportSchema() sent %d responses", es.sent)
+			}
+		})
+	}
+}
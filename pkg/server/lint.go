@@ -0,0 +1,93 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
+)
+
+var lintSeverityRank = map[string]int{
+	config.LintSeverityInfo:    0,
+	config.LintSeverityWarning: 1,
+	config.LintSeverityError:   2,
+}
+
+func (s *Server) GetSchemaLint(ctx context.Context, req *api.GetSchemaLintRequest) (*api.GetSchemaLintResponse, error) {
+	log.Debugf("received GetSchemaLint: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	minRank := 0
+	if req.Severity != "" {
+		var ok bool
+		minRank, ok = lintSeverityRank[req.Severity]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown severity %q", req.Severity)
+		}
+	}
+	rules := make(map[string]bool, len(req.Rules))
+	for _, r := range req.Rules {
+		rules[r] = true
+	}
+	lr, err := s.schemaStore.GetSchemaLint(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.GetSchemaLintResponse{}
+	if lr == nil {
+		rsp.NotLinted = true
+		return rsp, nil
+	}
+	for _, d := range lr.Diagnostics {
+		if len(rules) > 0 && !rules[d.Rule] {
+			continue
+		}
+		if lintSeverityRank[d.Severity] < minRank {
+			continue
+		}
+		rsp.Diagnostics = append(rsp.Diagnostics, &api.LintDiagnostic{
+			Rule:     d.Rule,
+			Severity: d.Severity,
+			Path:     d.Path,
+			Module:   d.Module,
+			Location: d.Location,
+			Message:  d.Message,
+		})
+	}
+	return rsp, nil
+}
+
+func (s *Server) ListLintRules(ctx context.Context, req *api.ListLintRulesRequest) (*api.ListLintRulesResponse, error) {
+	log.Debugf("received ListLintRules: %v", req)
+	rsp := &api.ListLintRulesResponse{}
+	for _, r := range lint.Rules() {
+		rsp.Rules = append(rsp.Rules, &api.LintRule{
+			Name:        r.Name(),
+			Description: r.Description(),
+			Severity:    r.DefaultSeverity(),
+			Plugin:      lint.Source(r.Name()),
+		})
+	}
+	return rsp, nil
+}
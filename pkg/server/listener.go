@@ -0,0 +1,107 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// environment of a process started with a listening socket, set by systemd
+// socket activation or by a server handing its listener over (see Takeover).
+const (
+	listenFdsEnv     = "LISTEN_FDS"
+	listenPidEnv     = "LISTEN_PID"
+	listenFdNamesEnv = "LISTEN_FDNAMES"
+	// first file descriptor passed
+	listenFdsStart = 3
+	// file descriptor of the pipe a process taking over a listener
+	// notifies the server handing it over once it serves.
+	takeoverReadyEnv = "SCHEMA_SERVER_TAKEOVER_READY_FD"
+)
+
+// listen returns the listener passed to the process, if any,
+// otherwise it listens on the configured address.
+func (s *Server) listen(ctx context.Context) (net.Listener, error) {
+	l, err := inheritedListener()
+	if err != nil {
+		return nil, err
+	}
+	if l != nil {
+		log.Infof("serving on the inherited listener %s", l.Addr())
+		return l, nil
+	}
+	lc := new(net.ListenConfig)
+	if s.config.GRPCServer.ReusePort {
+		lc.Control = reusePort
+	}
+	return lc.Listen(ctx, "tcp", s.config.GRPCServer.Address)
+}
+
+// inheritedListener returns the first listening socket passed to the process,
+// nil if there is none.
+func inheritedListener() (net.Listener, error) {
+	fds := os.Getenv(listenFdsEnv)
+	if fds == "" {
+		return nil, nil
+	}
+	// passed to another process
+	if pid := os.Getenv(listenPidEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	// not passed on to the processes started by this one
+	os.Unsetenv(listenFdsEnv)
+	os.Unsetenv(listenPidEnv)
+	os.Unsetenv(listenFdNamesEnv)
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid %s %q", listenFdsEnv, fds)
+	}
+	if n > 1 {
+		log.Warnf("%d sockets passed, serving on the first one", n)
+	}
+	f := os.NewFile(listenFdsStart, "listener")
+	defer f.Close()
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("inherited listener: %v", err)
+	}
+	return l, nil
+}
+
+// notifyTakeoverReady tells the server handing its listener over
+// to this process that it serves, if there is one.
+func notifyTakeoverReady() {
+	v := os.Getenv(takeoverReadyEnv)
+	if v == "" {
+		return
+	}
+	os.Unsetenv(takeoverReadyEnv)
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		log.Errorf("invalid %s %q", takeoverReadyEnv, v)
+		return
+	}
+	f := os.NewFile(uintptr(fd), "takeover")
+	defer f.Close()
+	if _, err := f.Write([]byte{1}); err != nil {
+		log.Errorf("failed to notify the takeover: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// load indicators a replica count can be scaled on, e.g. by a Kubernetes
+// HorizontalPodAutoscaler reading them through the custom metrics adapter.
+var (
+	inflightRPCsDesc = prometheus.NewDesc("schema_server_inflight_rpcs",
+		"RPCs being served",
+		[]string{"type"}, nil)
+	streamQueueDesc = prometheus.NewDesc("schema_server_stream_queued_bytes",
+		"Size of the streamed responses queued waiting for the clients to read them",
+		nil, nil)
+	cacheHitsDesc = prometheus.NewDesc("schema_server_cache_hits_total",
+		"Schema lookups answered from the persistent store cache",
+		nil, nil)
+	cacheMissesDesc = prometheus.NewDesc("schema_server_cache_misses_total",
+		"Schema lookups missing the persistent store cache",
+		nil, nil)
+	cacheHitRatioDesc = prometheus.NewDesc("schema_server_cache_hit_ratio",
+		"Share of the schema lookups answered from the persistent store cache since the start",
+		nil, nil)
+)
+
+// cacheMetrics is implemented by the stores with a cache.
+type cacheMetrics interface {
+	CacheMetrics() (hits, misses uint64, ok bool)
+}
+
+// loadCollector tracks the load of the server.
+type loadCollector struct {
+	store          store.Store
+	unaryInflight  atomic.Int64
+	streamInflight atomic.Int64
+	queuedBytes    atomic.Int64
+}
+
+func newLoadCollector(s store.Store) *loadCollector {
+	return &loadCollector{store: s}
+}
+
+// queued adds n bytes to the size of the queued stream responses, n is negative once
+// they are sent. It is a no-op on a nil collector.
+func (l *loadCollector) queued(n int64) {
+	if l != nil {
+		l.queuedBytes.Add(n)
+	}
+}
+
+func (l *loadCollector) unaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	l.unaryInflight.Add(1)
+	defer l.unaryInflight.Add(-1)
+	return handler(ctx, req)
+}
+
+func (l *loadCollector) streamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	l.streamInflight.Add(1)
+	defer l.streamInflight.Add(-1)
+	return handler(srv, ss)
+}
+
+func (l *loadCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inflightRPCsDesc
+	ch <- streamQueueDesc
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheHitRatioDesc
+}
+
+func (l *loadCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(inflightRPCsDesc, prometheus.GaugeValue, float64(l.unaryInflight.Load()), "unary")
+	ch <- prometheus.MustNewConstMetric(inflightRPCsDesc, prometheus.GaugeValue, float64(l.streamInflight.Load()), "stream")
+	ch <- prometheus.MustNewConstMetric(streamQueueDesc, prometheus.GaugeValue, float64(l.queuedBytes.Load()))
+	cm, ok := l.store.(cacheMetrics)
+	if !ok {
+		return
+	}
+	hits, misses, ok := cm.CacheMetrics()
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(hits))
+	ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(misses))
+	var ratio float64
+	if hits+misses > 0 {
+		ratio = float64(hits) / float64(hits+misses)
+	}
+	ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, ratio)
+}
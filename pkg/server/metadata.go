@@ -0,0 +1,54 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+func (s *Server) GetSchemaMetadata(ctx context.Context, req *api.GetSchemaMetadataRequest) (*api.GetSchemaMetadataResponse, error) {
+	log.Debugf("received GetSchemaMetadata: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	md, err := s.schemaStore.GetSchemaMetadata(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	if md == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "metadata scanning is not enabled for schema %s", sck)
+	}
+	rsp := &api.GetSchemaMetadataResponse{
+		Modules:  make([]*api.ModuleMetadata, 0, len(md.Modules)),
+		Warnings: md.Warnings,
+	}
+	for _, mm := range md.Modules {
+		rsp.Modules = append(rsp.Modules, &api.ModuleMetadata{
+			Name:         mm.Name,
+			Revision:     mm.Revision,
+			Organization: mm.Organization,
+			Contact:      mm.Contact,
+			License:      mm.License,
+		})
+	}
+	return rsp, nil
+}
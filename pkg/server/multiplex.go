@@ -0,0 +1,68 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// serveMultiplexed serves gRPC and the HTTP endpoints on listener l:
+// HTTP/2 requests with a gRPC content-type go to the gRPC server, the others to the HTTP router.
+// Without TLS, HTTP/2 is served in cleartext (h2c) next to HTTP/1.1.
+func (s *Server) serveMultiplexed(ctx context.Context, l net.Listener) error {
+	httpHandler := s.httpHandler()
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			s.srv.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+	h2s := &http2.Server{}
+	if s.tlsConfig == nil {
+		handler = h2c.NewHandler(handler, h2s)
+	}
+	s.httpSrv = &http.Server{
+		Handler: handler,
+		// no read/write timeouts, they would cut the gRPC streams
+		ReadHeaderTimeout: time.Minute,
+		TLSConfig:         s.tlsConfig,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	err := http2.ConfigureServer(s.httpSrv, h2s)
+	if err != nil {
+		return err
+	}
+	log.Infof("serving the HTTP endpoints on the gRPC port")
+	if s.tlsConfig != nil {
+		// the certificate is provided by the TLS config
+		err = s.httpSrv.ServeTLS(l, "", "")
+	} else {
+		err = s.httpSrv.Serve(l)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
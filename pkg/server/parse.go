@@ -0,0 +1,172 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// max total size of the files streamed to ParseModules
+const maxParseModulesSize = 64 * 1024 * 1024
+
+func (s *Server) ParseModules(stream api.SchemaServerExt_ParseModulesServer) error {
+	ctx := stream.Context()
+	dir, err := os.MkdirTemp("", "parse-modules-")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	modDir := filepath.Join(dir, "modules")
+	depDir := filepath.Join(dir, "dependencies")
+
+	scConfig := &config.SchemaConfig{
+		Name:                "parse",
+		Vendor:              "parse",
+		Version:             "0",
+		DescriptionLanguage: s.config.SchemaStore.DescriptionLanguage,
+		Features:            s.config.SchemaStore.Features,
+	}
+	files := newStreamedFiles()
+	defer files.close()
+	var size int
+	for i := 0; ; i++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			log.Debugf("received ParseModules: %v", req.Schema)
+			if req.Schema != nil {
+				err = s.parseBase(ctx, scConfig, req)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if req.FileName == "" {
+			if len(req.Contents) > 0 {
+				return status.Error(codes.InvalidArgument, "missing file name")
+			}
+			continue
+		}
+		root := modDir
+		if req.Dependency {
+			root = depDir
+		}
+		name, err := utils.JoinUnder(root, req.FileName)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid file name: %v", err)
+		}
+		if files.full(name) {
+			return status.Errorf(codes.ResourceExhausted, "the stream exceeds %d files", maxStreamedFiles)
+		}
+		f, created, err := files.open(name)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to open file %s: %v", req.FileName, err)
+		}
+		if created && !req.Dependency {
+			scConfig.Files = append(scConfig.Files, name)
+		}
+		size += len(req.Contents)
+		if size > maxParseModulesSize {
+			return status.Errorf(codes.ResourceExhausted, "the streamed files exceed %d bytes", maxParseModulesSize)
+		}
+		_, err = f.Write(req.Contents)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to write file %s: %v", req.FileName, err)
+		}
+	}
+	if err := files.close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to close file: %v", err)
+	}
+	if len(scConfig.Files) == 0 {
+		return status.Error(codes.InvalidArgument, "no module streamed")
+	}
+	if _, err := os.Stat(depDir); err == nil {
+		scConfig.Directories = append(scConfig.Directories, depDir)
+	}
+
+	rsp := new(api.ParseModulesResponse)
+	sc, err := schema.NewSchemaContext(stream.Context(), scConfig)
+	if err != nil {
+		var perr *schema.ParseError
+		if !errors.As(err, &perr) {
+			rsp.Errors = append(rsp.Errors, &api.ParseDiagnostic{Message: err.Error()})
+			return stream.SendAndClose(rsp)
+		}
+		rsp.Errors = parseDiagnostics(perr, modDir, depDir)
+		return stream.SendAndClose(rsp)
+	}
+	// reported as errors, the schema server loads a schema with such errors
+	if mes := sc.EntryErrors(); len(mes) > 0 {
+		rsp.Errors = parseDiagnostics(&schema.ParseError{Errors: mes}, modDir, depDir)
+		return stream.SendAndClose(rsp)
+	}
+	rsp.Modules = sc.Modules()
+	rsp.Warnings = sc.Composition().Warnings()
+	return stream.SendAndClose(rsp)
+}
+
+// parseDiagnostics returns the errors of perr, the files as streamed by the client.
+func parseDiagnostics(perr *schema.ParseError, dirs ...string) []*api.ParseDiagnostic {
+	for _, dir := range dirs {
+		trimUploadDir(perr, dir)
+	}
+	ds := make([]*api.ParseDiagnostic, 0, len(perr.Errors))
+	for _, me := range perr.Errors {
+		ds = append(ds, &api.ParseDiagnostic{
+			File:    me.File,
+			Line:    me.Line,
+			Column:  me.Column,
+			Message: me.Message,
+		})
+	}
+	return ds
+}
+
+// parseBase resolves the imports of the modules streamed to ParseModules
+// from the files of the schema of req, parsed with its features.
+func (s *Server) parseBase(ctx context.Context, scConfig *config.SchemaConfig, req *api.ParseModulesRequest) error {
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return err
+	}
+	baseCfg, err := s.schemaStore.GetSchemaConfig(ctx, sck)
+	if err != nil {
+		return err
+	}
+	scConfig.Directories = append(scConfig.Directories, baseCfg.Files...)
+	scConfig.Directories = append(scConfig.Directories, baseCfg.Directories...)
+	if baseCfg.Features != nil {
+		scConfig.Features = baseCfg.Features
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+)
+
+func (s *Server) PatchDocument(ctx context.Context, req *api.PatchDocumentRequest) (*api.PatchDocumentResponse, error) {
+	log.Debugf("received PatchDocument for schema %v at %v", req.Schema, req.Path)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Document) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing document")
+	}
+	if len(req.Patch) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing patch")
+	}
+	v, err := s.newDocumentValidator(ctx, req.Schema, sck, false)
+	if err != nil {
+		return nil, err
+	}
+	names, cs, err := v.rootNode(ctx, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	p := &patcher{v: v, names: names, ipath: append([]*sdcpb.PathElem(nil), req.Path.GetElem()...)}
+	rsp := new(api.PatchDocumentResponse)
+	doc, err := decodeJSONObject(req.Document)
+	if err != nil {
+		rsp.Errors = append(rsp.Errors, &api.PatchError{Index: -1, Kind: api.DocumentErrorSyntax, Message: err.Error()})
+		return rsp, nil
+	}
+	ops, err := document.DecodePatch(req.Patch)
+	if err != nil {
+		var perr *document.PatchError
+		if !errors.As(err, &perr) {
+			return nil, err
+		}
+		rsp.Errors = append(rsp.Errors, &api.PatchError{Index: perr.Index, Kind: api.DocumentErrorSyntax, Message: perr.Err.Error()})
+		return rsp, nil
+	}
+	var patched interface{} = doc
+	for i, op := range ops {
+		patched, err = p.apply(ctx, patched, op)
+		if err != nil {
+			return nil, err
+		}
+		if len(v.errs) > 0 {
+			rsp.Errors = patchErrors(i, op, v.errs)
+			rsp.Truncated = v.truncated
+			return rsp, nil
+		}
+	}
+	// the mandatory nodes and the leafrefs are checked once patched
+	doc, ok := patched.(map[string]interface{})
+	if !ok {
+		v.add(p.ipath, api.DocumentErrorStructure, "", "the patched document is not an object")
+	} else {
+		v.reset()
+		err = v.document(ctx, names, req.Path, cs, doc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(v.errs) > 0 {
+		rsp.Errors = patchErrors(-1, nil, v.errs)
+		rsp.Truncated = v.truncated
+		return rsp, nil
+	}
+	rsp.Document, err = json.Marshal(doc)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	rsp.Valid = true
+	return rsp, nil
+}
+
+func patchErrors(index int, op *document.PatchOperation, errs []*api.DocumentError) []*api.PatchError {
+	pes := make([]*api.PatchError, 0, len(errs))
+	for _, e := range errs {
+		pe := &api.PatchError{
+			Index:      index,
+			Path:       e.Path,
+			Kind:       e.Kind,
+			Constraint: e.Constraint,
+			Message:    e.Message,
+		}
+		if op != nil {
+			pe.Op = op.Op
+			pe.Pointer = op.Path
+		}
+		pes = append(pes, pe)
+	}
+	return pes
+}
+
+// patcher applies the operations of a patch to a document, the content of
+// the node at the schema names and instance path ipath, checking their
+// locations and the values they add against the schema. The validator
+// holds the errors of the last applied operation.
+type patcher struct {
+	v     *documentValidator
+	names []string
+	ipath []*sdcpb.PathElem
+}
+
+// patchTarget is the node of the schema a JSON pointer refers to, as the member
+// of an object: the list entries and the leaf-list values are checked with the
+// list or leaf-list they belong to.
+type patchTarget struct {
+	// schema names and instance path of the object
+	names []string
+	ipath []*sdcpb.PathElem
+	// pointer tokens of the object
+	tokens []string
+	// empty for the whole document
+	member string
+	// a metadata annotation, checked with the patched document
+	annotation bool
+}
+
+// apply applies op to doc and returns the patched document,
+// the errors of op being added to the validator.
+func (p *patcher) apply(ctx context.Context, doc interface{}, op *document.PatchOperation) (interface{}, error) {
+	p.v.reset()
+	if op.Op == document.PatchMove || op.Op == document.PatchCopy {
+		if _, err := p.target(ctx, doc, op.From); err != nil || len(p.v.errs) > 0 {
+			return doc, err
+		}
+	}
+	t, err := p.target(ctx, doc, op.Path)
+	if err != nil || len(p.v.errs) > 0 {
+		return doc, err
+	}
+	patched, err := op.Apply(doc)
+	if err != nil {
+		kind := api.PatchErrorPointer
+		if errors.Is(err, document.ErrPatchTest) {
+			kind = api.PatchErrorTest
+		}
+		ipath := t.ipath
+		if t.member != "" {
+			ipath = appendElem(ipath, &sdcpb.PathElem{Name: t.member})
+		}
+		p.v.add(ipath, kind, "", err.Error())
+		return doc, nil
+	}
+	switch op.Op {
+	case document.PatchRemove, document.PatchTest:
+		return patched, nil
+	}
+	return patched, p.check(ctx, patched, t)
+}
+
+// target resolves the JSON pointer ptr against the document doc and the schema.
+// The last token of the pointer can name a node not in the document yet.
+func (p *patcher) target(ctx context.Context, doc interface{}, ptr string) (*patchTarget, error) {
+	tokens, err := document.ParsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	t := &patchTarget{names: p.names, ipath: p.ipath}
+	names, ipath := p.names, p.ipath
+	cur := doc
+	for i := 0; i < len(tokens); i++ {
+		m := tokens[i]
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			// op.Apply reports it
+			return t, nil
+		}
+		t = &patchTarget{names: names, ipath: ipath, tokens: tokens[:i], member: m}
+		if strings.HasPrefix(m, "@") {
+			t.annotation = true
+			return t, nil
+		}
+		cnames := appendName(names, m)
+		cip := appendElem(ipath, &sdcpb.PathElem{Name: m})
+		se, err := p.v.lr.r.Get(ctx, cnames)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			p.v.add(cip, api.DocumentErrorUnknown, "", fmt.Sprintf("%q is not a node of the schema", m))
+			return t, nil
+		}
+		rest := tokens[i+1:]
+		switch se := se.GetSchema().(type) {
+		case *sdcpb.SchemaElem_Container:
+			names = cnames
+			if len(se.Container.GetKeys()) == 0 {
+				ipath, cur = cip, obj[m]
+				continue
+			}
+			if len(rest) == 0 {
+				return t, nil
+			}
+			entries, _ := obj[m].([]interface{})
+			idx, ok := entryIndex(rest[0], len(entries))
+			if !ok {
+				p.v.add(cip, api.DocumentErrorStructure, "", fmt.Sprintf("%q is not an index of the list entries", rest[0]))
+				return t, nil
+			}
+			if idx >= len(entries) {
+				// op.Apply reports a location to add to
+				return t, nil
+			}
+			i++
+			cur = entries[idx]
+			ipath = appendElem(ipath, entryElem(m, se.Container, cur))
+		case *sdcpb.SchemaElem_Field:
+			if len(rest) > 0 {
+				p.v.add(cip, api.DocumentErrorStructure, "", fmt.Sprintf("leaf %q has no children", m))
+			}
+			return t, nil
+		case *sdcpb.SchemaElem_Leaflist:
+			if len(rest) > 1 {
+				p.v.add(cip, api.DocumentErrorStructure, "", fmt.Sprintf("leaf-list %q values have no children", m))
+			} else if len(rest) == 1 {
+				vs, _ := obj[m].([]interface{})
+				if _, ok := entryIndex(rest[0], len(vs)); !ok {
+					p.v.add(cip, api.DocumentErrorStructure, "", fmt.Sprintf("%q is not an index of the leaf-list values", rest[0]))
+				}
+			}
+			return t, nil
+		}
+	}
+	return t, nil
+}
+
+// entryIndex parses the array index token of a pointer, "-" being the index past the end.
+func entryIndex(token string, n int) (int, bool) {
+	if token == "-" {
+		return n, true
+	}
+	if token == "" || len(token) > 1 && token[0] == '0' || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(token)
+	return idx, err == nil
+}
+
+// entryElem returns the path element of the entry val of the list m, with the keys it has.
+func entryElem(m string, cs *sdcpb.ContainerSchema, val interface{}) *sdcpb.PathElem {
+	pe := &sdcpb.PathElem{Name: m, Key: make(map[string]string, len(cs.GetKeys()))}
+	o, _ := val.(map[string]interface{})
+	for _, k := range cs.GetKeys() {
+		kval, ok := member(o, k.GetName())
+		if !ok {
+			continue
+		}
+		if raw, err := jsonRaw(kval); err == nil {
+			pe.Key[k.GetName()] = raw
+		}
+	}
+	return pe
+}
+
+// check validates the value of the target t in the patched document doc,
+// leaving out the mandatory nodes that later operations can add.
+func (p *patcher) check(ctx context.Context, doc interface{}, t *patchTarget) error {
+	if t.annotation {
+		return nil
+	}
+	var obj map[string]interface{}
+	if t.member == "" {
+		var ok bool
+		obj, ok = doc.(map[string]interface{})
+		if !ok {
+			p.v.add(t.ipath, api.DocumentErrorStructure, "", "expecting an object")
+			return nil
+		}
+	} else {
+		parent, ok := document.Lookup(doc, t.tokens)
+		if !ok {
+			return nil
+		}
+		po, ok := parent.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		val, ok := po[t.member]
+		if !ok {
+			return nil
+		}
+		obj = map[string]interface{}{t.member: val}
+	}
+	err := p.v.object(ctx, t.names, t.ipath, nil, obj)
+	if err != nil {
+		return err
+	}
+	errs := p.v.errs[:0]
+	for _, e := range p.v.errs {
+		if e.Kind != api.DocumentErrorMandatory {
+			errs = append(errs, e)
+		}
+	}
+	p.v.errs = errs
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+const patchModule = `module t {
+  namespace "urn:t";
+  prefix t;
+  container c {
+    list l {
+      key "name";
+      leaf name { type string; }
+      leaf v { type uint8; }
+    }
+    leaf-list ll { type string; }
+    leaf d { type string; }
+  }
+}
+`
+
+const patchDocument = `{"t:c":{"l":[{"name":"a","v":1}],"ll":["x"],"d":"y"}}`
+
+func TestServer_PatchDocument(t *testing.T) {
+	s := &Server{schemaStore: testStore(t, patchModule)}
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+		// index and kind of the first error
+		index int
+		kind  string
+	}{
+		{name: "add entry", patch: `[{"op":"add","path":"/t:c/l/-","value":{"name":"b","v":2}}]`,
+			want: `{"t:c":{"d":"y","l":[{"name":"a","v":1},{"name":"b","v":2}],"ll":["x"]}}`},
+		{name: "insert entry", patch: `[{"op":"add","path":"/t:c/l/0","value":{"name":"b"}}]`,
+			want: `{"t:c":{"d":"y","l":[{"name":"b"},{"name":"a","v":1}],"ll":["x"]}}`},
+		{name: "add entry of wrong type", patch: `[{"op":"add","path":"/t:c/l/-","value":{"name":"b","v":300}}]`,
+			index: 0, kind: api.DocumentErrorType},
+		{name: "add unknown member", patch: `[{"op":"add","path":"/t:c/z","value":1}]`,
+			index: 0, kind: api.DocumentErrorUnknown},
+		{name: "add at leading zero index", patch: `[{"op":"add","path":"/t:c/l/01","value":{"name":"b"}}]`,
+			index: 0, kind: api.DocumentErrorStructure},
+		{name: "add under a leaf", patch: `[{"op":"add","path":"/t:c/d/x","value":1}]`,
+			index: 0, kind: api.DocumentErrorStructure},
+		{name: "add leaf-list value", patch: `[{"op":"add","path":"/t:c/ll/-","value":"z"}]`,
+			want: `{"t:c":{"d":"y","l":[{"name":"a","v":1}],"ll":["x","z"]}}`},
+		{name: "add under a leaf-list value", patch: `[{"op":"add","path":"/t:c/ll/0/x","value":1}]`,
+			index: 0, kind: api.DocumentErrorStructure},
+		{name: "add leaf-list value of wrong type", patch: `[{"op":"add","path":"/t:c/ll/-","value":1}]`,
+			index: 0, kind: api.DocumentErrorType},
+		{name: "replace leaf", patch: `[{"op":"replace","path":"/t:c/l/0/v","value":3}]`,
+			want: `{"t:c":{"d":"y","l":[{"name":"a","v":3}],"ll":["x"]}}`},
+		{name: "replace missing entry", patch: `[{"op":"replace","path":"/t:c/l/1","value":{"name":"b"}}]`,
+			index: 0, kind: api.PatchErrorPointer},
+		{name: "remove entry", patch: `[{"op":"remove","path":"/t:c/l/0"}]`,
+			want: `{"t:c":{"d":"y","l":[],"ll":["x"]}}`},
+		{name: "remove append index", patch: `[{"op":"remove","path":"/t:c/l/-"}]`,
+			index: 0, kind: api.PatchErrorPointer},
+		{name: "remove key", patch: `[{"op":"remove","path":"/t:c/l/0/name"}]`,
+			index: -1, kind: api.DocumentErrorKey},
+		{name: "move leaf", patch: `[{"op":"move","from":"/t:c/d","path":"/t:c/l/0/name"}]`,
+			want: `{"t:c":{"l":[{"name":"y","v":1}],"ll":["x"]}}`},
+		{name: "move into own child", patch: `[{"op":"move","from":"/t:c/l","path":"/t:c/l/0/v"}]`,
+			index: 0, kind: api.PatchErrorPointer},
+		{name: "move from unknown member", patch: `[{"op":"move","from":"/t:c/z","path":"/t:c/d"}]`,
+			index: 0, kind: api.DocumentErrorUnknown},
+		{name: "copy leaf to a leaf of another type", patch: `[{"op":"copy","from":"/t:c/d","path":"/t:c/l/0/v"}]`,
+			index: 0, kind: api.DocumentErrorType},
+		{name: "test", patch: `[{"op":"test","path":"/t:c/l/0/v","value":1},{"op":"remove","path":"/t:c/d"}]`,
+			want: `{"t:c":{"l":[{"name":"a","v":1}],"ll":["x"]}}`},
+		{name: "failed test", patch: `[{"op":"remove","path":"/t:c/d"},{"op":"test","path":"/t:c/l/0/v","value":2}]`,
+			index: 1, kind: api.PatchErrorTest},
+		{name: "invalid escape", patch: `[{"op":"remove","path":"/t:c/d~2"}]`,
+			index: 0, kind: api.DocumentErrorSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := s.PatchDocument(context.Background(), &api.PatchDocumentRequest{
+				Schema:   &sdcpb.Schema{Name: "t", Vendor: "v", Version: "1"},
+				Document: []byte(patchDocument),
+				Patch:    []byte(tt.patch),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.kind == "" {
+				if !rsp.Valid || len(rsp.Errors) > 0 {
+					t.Fatalf("got errors %v, want a valid document", rsp.Errors)
+				}
+				if string(rsp.Document) != tt.want {
+					t.Errorf("got %s, want %s", rsp.Document, tt.want)
+				}
+				return
+			}
+			if rsp.Valid || len(rsp.Errors) == 0 {
+				t.Fatalf("got a valid document %s, want an error", rsp.Document)
+			}
+			if e := rsp.Errors[0]; e.Index != tt.index || e.Kind != tt.kind {
+				t.Errorf("got error %+v, want index %d and kind %s", e, tt.index, tt.kind)
+			}
+		})
+	}
+}
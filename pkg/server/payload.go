@@ -0,0 +1,137 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// 64B to 16MiB
+var payloadSizeBuckets = prometheus.ExponentialBuckets(64, 4, 10)
+
+// payloadSizes is a gRPC stats handler recording the size of the messages of
+// each RPC and warning of the response messages larger than warnSize.
+type payloadSizes struct {
+	store store.Store
+	// returns the method the RPCs are labeled with, see Server.canonicalMethod
+	canonical func(string) string
+	// nil without prometheus
+	requests  *prometheus.HistogramVec
+	responses *prometheus.HistogramVec
+	// 0 to not warn
+	warnSize int
+}
+
+func newPayloadSizes(s store.Store, canonical func(string) string, metrics bool, warnSize int) *payloadSizes {
+	p := &payloadSizes{store: s, canonical: canonical, warnSize: warnSize}
+	if metrics {
+		p.requests = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "schema_server_rpc_request_size_bytes",
+			Help:    "size of the RPC request messages",
+			Buckets: payloadSizeBuckets,
+		}, []string{"method", "schema"})
+		p.responses = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "schema_server_rpc_response_size_bytes",
+			Help:    "size of the RPC response messages",
+			Buckets: payloadSizeBuckets,
+		}, []string{"method", "schema"})
+	}
+	return p
+}
+
+func (p *payloadSizes) Describe(ch chan<- *prometheus.Desc) {
+	p.requests.Describe(ch)
+	p.responses.Describe(ch)
+}
+
+func (p *payloadSizes) Collect(ch chan<- prometheus.Metric) {
+	p.requests.Collect(ch)
+	p.responses.Collect(ch)
+}
+
+type payloadRPCKey struct{}
+
+// payloadRPC is the method of an RPC and the schema of its first request.
+type payloadRPC struct {
+	method string
+	m      sync.Mutex
+	seen   bool
+	schema string
+}
+
+func (p *payloadSizes) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, payloadRPCKey{}, &payloadRPC{method: p.canonical(info.FullMethodName)})
+}
+
+func (p *payloadSizes) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	r, ok := ctx.Value(payloadRPCKey{}).(*payloadRPC)
+	if !ok {
+		return
+	}
+	switch rs := rs.(type) {
+	case *stats.InPayload:
+		schema := r.schemaOf(p.store, rs.Payload)
+		if p.requests != nil {
+			p.requests.WithLabelValues(r.method, schema).Observe(float64(rs.Length))
+		}
+	case *stats.OutPayload:
+		schema := r.schemaOf(p.store, nil)
+		if p.responses != nil {
+			p.responses.WithLabelValues(r.method, schema).Observe(float64(rs.Length))
+		}
+		if p.warnSize > 0 && rs.Length > p.warnSize {
+			f := log.Fields{"method": r.method, "size": rs.Length}
+			if schema != "" {
+				f["schema"] = schema
+			}
+			if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
+				f["peer"] = pr.Addr.String()
+			}
+			log.WithFields(f).Warnf("response of %d bytes exceeds the %d bytes warning size", rs.Length, p.warnSize)
+		}
+	}
+}
+
+// schemaOf returns the schema of the RPC, the one the first request msg refers to.
+// Only the stored schemas are used as label values, to bound the metrics cardinality.
+func (r *payloadRPC) schemaOf(s store.Store, msg interface{}) string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.seen || msg == nil {
+		return r.schema
+	}
+	r.seen = true
+	if sc, _ := requestInfo(msg); sc != nil {
+		sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+		if s.HasSchema(sck) {
+			r.schema = sck.String()
+		}
+	}
+	return r.schema
+}
+
+func (p *payloadSizes) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (p *payloadSizes) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,417 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/memstore"
+)
+
+const (
+	// separates the version of a pinned schema from its fingerprint
+	pinSep        = "+"
+	defaultPinTTL = 10 * time.Minute
+	maxPinTTL     = time.Hour
+	// max number of pinned schemas
+	maxPins = 16
+)
+
+// schemaHolder is implemented by the stores keeping the parsed schemas,
+// a pin shares the schema with them instead of parsing it again.
+type schemaHolder interface {
+	Schema(scKey store.SchemaKey) (*schema.Schema, bool)
+}
+
+type schemaPin struct {
+	// pinned schema
+	schema      store.SchemaKey
+	fingerprint string
+	expires     time.Time
+	timer       *time.Timer
+	// holds the pinned schema under its own key
+	store store.Store
+}
+
+func (p *schemaPin) key() store.SchemaKey {
+	return pinKey(p.schema, p.fingerprint)
+}
+
+func pinKey(sck store.SchemaKey, fingerprint string) store.SchemaKey {
+	return store.SchemaKey{Name: sck.Name, Vendor: sck.Vendor, Version: sck.Version + pinSep + fingerprint}
+}
+
+func (p *schemaPin) api() *api.SchemaPin {
+	return &api.SchemaPin{
+		Schema:      schemaOf(p.key()),
+		Pinned:      schemaOf(p.schema),
+		Fingerprint: p.fingerprint,
+		Expires:     p.expires,
+	}
+}
+
+// request returns a copy of req for the pinned schema.
+func (p *schemaPin) request(req proto.Message) proto.Message {
+	req = proto.Clone(req)
+	setRequestSchema(req, schemaOf(p.schema))
+	return req
+}
+
+// pinnedStore serves the pinned schemas along with the schemas of its Store.
+// The pinned schemas are not listed and cannot be reloaded, deleting one releases it.
+type pinnedStore struct {
+	store.Store
+	m    sync.RWMutex
+	pins map[store.SchemaKey]*schemaPin
+}
+
+func newPinnedStore(s store.Store) *pinnedStore {
+	return &pinnedStore{Store: s, pins: make(map[store.SchemaKey]*schemaPin)}
+}
+
+func (ps *pinnedStore) get(sck store.SchemaKey) *schemaPin {
+	if !strings.Contains(sck.Version, pinSep) {
+		return nil
+	}
+	ps.m.RLock()
+	defer ps.m.RUnlock()
+	return ps.pins[sck]
+}
+
+func (ps *pinnedStore) getSchema(sc *sdcpb.Schema) *schemaPin {
+	return ps.get(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+}
+
+func (ps *pinnedStore) list() []*api.SchemaPin {
+	ps.m.RLock()
+	defer ps.m.RUnlock()
+	pins := make([]*api.SchemaPin, 0, len(ps.pins))
+	for _, p := range ps.pins {
+		pins = append(pins, p.api())
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return schemaLess(pins[i].Schema, pins[j].Schema)
+	})
+	return pins
+}
+
+// add pins p, or extends the pin of the same tree up to the expiry of p.
+func (ps *pinnedStore) add(p *schemaPin) (*schemaPin, error) {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	k := p.key()
+	if cur, ok := ps.pins[k]; ok {
+		if p.expires.After(cur.expires) {
+			cur.expires = p.expires
+			cur.timer.Reset(time.Until(cur.expires))
+		}
+		return cur, nil
+	}
+	if len(ps.pins) >= maxPins {
+		return nil, status.Errorf(codes.ResourceExhausted, "max %d pinned schemas", maxPins)
+	}
+	p.timer = time.AfterFunc(time.Until(p.expires), func() {
+		ps.release(k, p)
+		log.Infof("pinned schema %s expired", k)
+	})
+	ps.pins[k] = p
+	return p, nil
+}
+
+// release removes pin p of key k if it is still the current one.
+func (ps *pinnedStore) release(k store.SchemaKey, p *schemaPin) bool {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	if ps.pins[k] != p {
+		return false
+	}
+	p.timer.Stop()
+	delete(ps.pins, k)
+	return true
+}
+
+func (ps *pinnedStore) HasSchema(sck store.SchemaKey) bool {
+	return ps.get(sck) != nil || ps.Store.HasSchema(sck)
+}
+
+func (ps *pinnedStore) GetSchemaDetails(ctx context.Context, req *sdcpb.GetSchemaDetailsRequest) (*sdcpb.GetSchemaDetailsResponse, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return p.store.GetSchemaDetails(ctx, p.request(req).(*sdcpb.GetSchemaDetailsRequest))
+	}
+	return ps.Store.GetSchemaDetails(ctx, req)
+}
+
+func (ps *pinnedStore) ReloadSchema(ctx context.Context, req *sdcpb.ReloadSchemaRequest) (*sdcpb.ReloadSchemaResponse, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "schema %s is pinned, it cannot be reloaded", p.key())
+	}
+	return ps.Store.ReloadSchema(ctx, req)
+}
+
+func (ps *pinnedStore) DeleteSchema(ctx context.Context, req *sdcpb.DeleteSchemaRequest) (*sdcpb.DeleteSchemaResponse, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		if ps.release(p.key(), p) {
+			log.Infof("pinned schema %s released", p.key())
+		}
+		return &sdcpb.DeleteSchemaResponse{}, nil
+	}
+	return ps.Store.DeleteSchema(ctx, req)
+}
+
+func (ps *pinnedStore) GetSchemaMetadata(ctx context.Context, sck store.SchemaKey) (*schema.Metadata, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaMetadata(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaMetadata(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaComposition(ctx context.Context, sck store.SchemaKey) (*schema.Composition, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaComposition(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaComposition(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaLint(ctx context.Context, sck store.SchemaKey) (*lint.Report, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaLint(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaLint(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaOptionalLeafrefs(ctx context.Context, sck store.SchemaKey) ([]string, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaOptionalLeafrefs(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaOptionalLeafrefs(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaWhen(ctx context.Context, sck store.SchemaKey, path string) (*schema.WhenStatements, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaWhen(ctx, p.schema, path)
+	}
+	return ps.Store.GetSchemaWhen(ctx, sck, path)
+}
+
+func (ps *pinnedStore) GetSchemaOrder(ctx context.Context, sck store.SchemaKey, path string) ([]string, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaOrder(ctx, p.schema, path)
+	}
+	return ps.Store.GetSchemaOrder(ctx, sck, path)
+}
+
+func (ps *pinnedStore) GetSchemaSubmodules(ctx context.Context, sck store.SchemaKey) ([]*schema.Submodule, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaSubmodules(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaSubmodules(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaIdentities(ctx context.Context, sck store.SchemaKey) ([]*schema.Identity, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaIdentities(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaIdentities(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaAnnotations(ctx context.Context, sck store.SchemaKey) ([]*schema.Annotation, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaAnnotations(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaAnnotations(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaVariant(ctx context.Context, sck store.SchemaKey) (*schema.Variant, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaVariant(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaVariant(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaLibrary(ctx context.Context, sck store.SchemaKey) ([]*schema.LibraryModule, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaLibrary(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaLibrary(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaStats(ctx context.Context, sck store.SchemaKey) (*schema.Stats, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaStats(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaStats(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaDefaultOrigins(ctx context.Context, sck store.SchemaKey) (map[string]*schema.DefaultOrigin, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaDefaultOrigins(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaDefaultOrigins(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaSourcesDigest(ctx context.Context, sck store.SchemaKey) (string, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaSourcesDigest(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaSourcesDigest(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchemaConfig(ctx context.Context, sck store.SchemaKey) (*config.SchemaConfig, error) {
+	if p := ps.get(sck); p != nil {
+		return p.store.GetSchemaConfig(ctx, p.schema)
+	}
+	return ps.Store.GetSchemaConfig(ctx, sck)
+}
+
+func (ps *pinnedStore) GetSchema(ctx context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return p.store.GetSchema(ctx, p.request(req).(*sdcpb.GetSchemaRequest))
+	}
+	return ps.Store.GetSchema(ctx, req)
+}
+
+func (ps *pinnedStore) GetSchemaElements(ctx context.Context, req *sdcpb.GetSchemaRequest) (chan *sdcpb.SchemaElem, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return p.store.GetSchemaElements(ctx, p.request(req).(*sdcpb.GetSchemaRequest))
+	}
+	return ps.Store.GetSchemaElements(ctx, req)
+}
+
+func (ps *pinnedStore) ToPath(ctx context.Context, req *sdcpb.ToPathRequest) (*sdcpb.ToPathResponse, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return p.store.ToPath(ctx, p.request(req).(*sdcpb.ToPathRequest))
+	}
+	return ps.Store.ToPath(ctx, req)
+}
+
+func (ps *pinnedStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest) (*sdcpb.ExpandPathResponse, error) {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return p.store.ExpandPath(ctx, p.request(req).(*sdcpb.ExpandPathRequest))
+	}
+	return ps.Store.ExpandPath(ctx, req)
+}
+
+func (ps *pinnedStore) ExpandPathFunc(ctx context.Context, req *sdcpb.ExpandPathRequest, fn func(*sdcpb.Path) error) error {
+	if p := ps.getSchema(req.GetSchema()); p != nil {
+		return p.store.ExpandPathFunc(ctx, p.request(req).(*sdcpb.ExpandPathRequest), fn)
+	}
+	return ps.Store.ExpandPathFunc(ctx, req, fn)
+}
+
+func (s *Server) PinSchema(ctx context.Context, req *api.PinSchemaRequest) (*api.PinSchemaResponse, error) {
+	log.Debugf("received PinSchema: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if s.pins.get(sck) != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "schema %s is pinned already", sck)
+	}
+	ttl := req.TTL
+	switch {
+	case ttl < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ttl %s", ttl)
+	case ttl == 0:
+		ttl = defaultPinTTL
+	case ttl > maxPinTTL:
+		ttl = maxPinTTL
+	}
+	p, err := s.newPin(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	if req.Fingerprint != "" && req.Fingerprint != p.fingerprint {
+		return nil, status.Errorf(codes.FailedPrecondition, "schema %s has fingerprint %s, not %s",
+			sck, p.fingerprint, req.Fingerprint)
+	}
+	p.expires = time.Now().Add(ttl)
+	p, err = s.pins.add(p)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("schema %s pinned as %s until %s", sck, p.key(), p.expires.Format(time.RFC3339))
+	return &api.PinSchemaResponse{Pin: p.api()}, nil
+}
+
+func (s *Server) ListSchemaPins(ctx context.Context, req *api.ListSchemaPinsRequest) (*api.ListSchemaPinsResponse, error) {
+	log.Debugf("received ListSchemaPins: %v", req)
+	return &api.ListSchemaPinsResponse{Pins: s.pins.list()}, nil
+}
+
+// newPin returns a pin of the current tree of schema sck, its parsed schema is shared
+// with the store keeping it or parsed again from its config.
+func (s *Server) newPin(ctx context.Context, sck store.SchemaKey) (*schemaPin, error) {
+	var sc *schema.Schema
+	h, ok := s.pins.Store.(schemaHolder)
+	if ok {
+		sc, ok = h.Schema(sck)
+	}
+	if !ok {
+		cfg, err := s.pins.Store.GetSchemaConfig(ctx, sck)
+		if err != nil {
+			return nil, err
+		}
+		sc, err = schema.NewSchemaContext(ctx, cfg)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to parse schema %s: %v", sck, err)
+		}
+	}
+	p := &schemaPin{schema: sck, store: memstore.New()}
+	err := p.store.AddSchema(sc)
+	if err != nil {
+		return nil, err
+	}
+	p.fingerprint, err = treeFingerprint(ctx, store.NewResolver(p.store, schemaOf(sck)))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return p, nil
+	}
+	// the sources may have changed since the schema was loaded
+	fp, err := treeFingerprint(ctx, store.NewResolver(s.pins.Store, schemaOf(sck)))
+	if err != nil {
+		return nil, err
+	}
+	if fp != p.fingerprint {
+		return nil, status.Errorf(codes.FailedPrecondition, "the sources of schema %s changed since it was loaded, reload it first", sck)
+	}
+	return p, nil
+}
+
+// treeFingerprint returns the fingerprint of the tree r resolves, see GetSchemaFingerprint.
+func treeFingerprint(ctx context.Context, r *store.Resolver) (string, error) {
+	root, err := hashNode(ctx, r, nil, make(map[string]*nodeHash))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(root.tree[:16]), nil
+}
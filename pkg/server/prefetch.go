@@ -0,0 +1,96 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// max number of concurrent lookups triggered by a single PrefetchSchema request
+const prefetchConcurrency = 16
+
+func (s *Server) PrefetchSchema(ctx context.Context, req *api.PrefetchSchemaRequest) (*api.PrefetchSchemaResponse, error) {
+	log.Debugf("received PrefetchSchema: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	// only the persistent store caches the lookups, pinned schemas are not stored there
+	cm, ok := s.pins.Store.(cacheMetrics)
+	if ok {
+		_, _, ok = cm.CacheMetrics()
+	}
+	if !ok || s.pins.get(sck) != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "schema %v lookups are not cached, nothing to prefetch", req.Schema)
+	}
+	// collect all distinct paths and path prefixes,
+	// GetSchemaElements and ToPath lookups go through the prefixes.
+	paths := make(map[string]*sdcpb.Path)
+	for _, p := range req.Paths {
+		for i := 1; i <= len(p.GetElem()); i++ {
+			sp := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, i)}
+			for _, pe := range p.GetElem()[:i] {
+				sp.Elem = append(sp.Elem, &sdcpb.PathElem{Name: pe.GetName()})
+			}
+			paths[strings.Join(utils.ToStrings(sp, false, true), "/")] = sp
+		}
+	}
+
+	rsp := &api.PrefetchSchemaResponse{}
+	m := new(sync.Mutex)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(prefetchConcurrency)
+	for _, p := range paths {
+		p := p
+		eg.Go(func() error {
+			_, err := s.schemaStore.GetSchema(ctx, &sdcpb.GetSchemaRequest{
+				Path:   p,
+				Schema: req.Schema,
+			})
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				rsp.Failed = append(rsp.Failed, &api.PathError{
+					Path:  utils.ToXPath(p, true),
+					Error: err.Error(),
+				})
+				return ctx.Err()
+			}
+			rsp.Resolved++
+			return nil
+		})
+	}
+	err = eg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rsp.Failed, func(i, j int) bool {
+		return rsp.Failed[i].Path < rsp.Failed[j].Path
+	})
+	log.Debugf("prefetched %d path(s) for schema %v, %d failed", rsp.Resolved, req.Schema, len(rsp.Failed))
+	return rsp, nil
+}
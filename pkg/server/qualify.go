@@ -0,0 +1,107 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// pathQualifier adds module names to path elements.
+// It is meant to be used for the duration of a single request.
+type pathQualifier struct {
+	resolver *store.Resolver
+	mode     string
+}
+
+func newPathQualifier(st store.Store, sc *sdcpb.Schema, mode string) *pathQualifier {
+	return &pathQualifier{
+		resolver: store.NewResolver(st, sc),
+		mode:     mode,
+	}
+}
+
+// qualify sets the module name on the path elements of p
+// according to the qualifier mode.
+func (q *pathQualifier) qualify(ctx context.Context, p *sdcpb.Path) error {
+	parentModule := ""
+	names := make([]string, 0, len(p.GetElem()))
+	for _, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+		se, err := q.resolver.Get(ctx, names)
+		if err != nil {
+			return err
+		}
+		module, err := q.resolver.Module(ctx, se)
+		if err != nil {
+			return err
+		}
+		name := pe.GetName()
+		if idx := strings.Index(name, ":"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		switch {
+		case module == "":
+		case q.mode == config.PathQualificationModule,
+			q.mode == config.PathQualificationRFC7951 && module != parentModule:
+			pe.Name = module + ":" + name
+		default:
+			pe.Name = name
+		}
+		parentModule = module
+	}
+	return nil
+}
+
+// qualifyToPath applies the configured path qualification to a ToPath response.
+func (s *Server) qualifyToPath(ctx context.Context, sc *sdcpb.Schema, rsp *sdcpb.ToPathResponse) error {
+	if s.config.GRPCServer.PathQualification == config.PathQualificationNone {
+		return nil
+	}
+	q := newPathQualifier(s.schemaStore, sc, s.config.GRPCServer.PathQualification)
+	return q.qualify(ctx, rsp.GetPath())
+}
+
+// qualifyExpandPath applies the configured path qualification to an ExpandPath response.
+func (s *Server) qualifyExpandPath(ctx context.Context, sc *sdcpb.Schema, rsp *sdcpb.ExpandPathResponse) error {
+	if s.config.GRPCServer.PathQualification == config.PathQualificationNone {
+		return nil
+	}
+	q := newPathQualifier(s.schemaStore, sc, s.config.GRPCServer.PathQualification)
+	for _, p := range rsp.GetPath() {
+		err := q.qualify(ctx, p)
+		if err != nil {
+			return err
+		}
+	}
+	for i, xp := range rsp.GetXpath() {
+		p, err := utils.ParsePath(xp)
+		if err != nil {
+			return err
+		}
+		err = q.qualify(ctx, p)
+		if err != nil {
+			return err
+		}
+		rsp.Xpath[i] = utils.ToXPath(p, false)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/query"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) QuerySchema(ctx context.Context, req *api.QuerySchemaRequest) (*api.QuerySchemaResponse, error) {
+	log.Debugf("received QuerySchema: %v", req)
+	_, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	q, err := query.Parse(req.Query)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid query: %v", err)
+	}
+	// a query walks the subtree it selects nodes from
+	err = s.checkSubtreeLimits(ctx, req.Schema, q.Under)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.QuerySchemaResponse{
+		Matches: make([]*api.QueryMatch, 0),
+	}
+	rsp.Truncated, err = query.Run(ctx, store.NewResolver(s.schemaStore, req.Schema), q, func(m *query.Match) error {
+		rsp.Matches = append(rsp.Matches, &api.QueryMatch{
+			Path:       m.Path,
+			Kind:       m.Kind,
+			Attributes: m.Attributes,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+var (
+	quotaUsageDesc = prometheus.NewDesc("schema_server_upload_quota_usage",
+		"Uploaded schemas and bytes counted in the upload quota of a client",
+		[]string{"owner", "resource"}, nil)
+	quotaLimitDesc = prometheus.NewDesc("schema_server_upload_quota_limit",
+		"Upload quota of a client, 0 means no limit",
+		[]string{"owner", "resource"}, nil)
+)
+
+// owner the uploads of the unidentified clients are counted against together
+const anonymousUploadOwner = "anonymous"
+
+// uploadQuotas tracks the schemas uploaded by each client against its quota.
+type uploadQuotas struct {
+	cfg *config.UploadQuotas
+
+	m sync.Mutex
+	// size of the uploaded schemas per owner,
+	// an upload in progress counts as a schema.
+	usage map[string]map[store.SchemaKey]int64
+}
+
+func newUploadQuotas(cfg *config.UploadQuotas) *uploadQuotas {
+	return &uploadQuotas{
+		cfg:   cfg,
+		usage: make(map[string]map[store.SchemaKey]int64),
+	}
+}
+
+// uploadOwner returns the client the quota of an upload is counted against:
+// the common name of its certificate, or else the tenant or subject of its bearer token.
+// The metadata set by the client, as the x-tenant one without authentication, is not used.
+func uploadOwner(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if cn, _ := principal(ti.State); cn != "" {
+				return cn
+			}
+		}
+	}
+	if a := attributionFromContext(ctx); a != nil && a.Authenticated {
+		if a.Tenant != "" {
+			return a.Tenant
+		}
+		if a.Subject != "" {
+			return a.Subject
+		}
+	}
+	return anonymousUploadOwner
+}
+
+// reserve counts the upload of schema sck in the quota of owner,
+// a schema uploaded again by its owner is not counted twice.
+func (q *uploadQuotas) reserve(owner string, sck store.SchemaKey) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+	_, again := q.usage[owner][sck]
+	max := q.cfg.Quota(owner).MaxSchemas
+	if !again && max > 0 && len(q.usage[owner]) >= max {
+		return reasonError(codes.ResourceExhausted, api.ReasonUploadQuotaExceeded,
+			map[string]string{"owner": owner, "limit": fmt.Sprintf("%d schema(s)", max)},
+			"upload quota of %q exceeded: max %d schema(s)", owner, max)
+	}
+	// the schema is counted against its last uploader
+	for o, scs := range q.usage {
+		if o != owner {
+			delete(scs, sck)
+			if len(scs) == 0 {
+				delete(q.usage, o)
+			}
+		}
+	}
+	q.set(owner, sck, 0)
+	return nil
+}
+
+// grow adds n bytes to the size of the schema sck uploaded by owner.
+func (q *uploadQuotas) grow(owner string, sck store.SchemaKey, n int64) error {
+	q.m.Lock()
+	defer q.m.Unlock()
+	max := q.cfg.Quota(owner).MaxBytes
+	if max > 0 && q.bytes(owner)+n > max {
+		return reasonError(codes.ResourceExhausted, api.ReasonUploadQuotaExceeded,
+			map[string]string{"owner": owner, "limit": fmt.Sprintf("%d byte(s)", max)},
+			"upload quota of %q exceeded: max %d byte(s)", owner, max)
+	}
+	q.set(owner, sck, q.usage[owner][sck]+n)
+	return nil
+}
+
+// restore counts an uploaded schema loaded on startup, whatever the quota.
+func (q *uploadQuotas) restore(owner string, sck store.SchemaKey, size int64) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	q.set(owner, sck, size)
+}
+
+// size returns the size of the schema sck uploaded by owner.
+func (q *uploadQuotas) size(owner string, sck store.SchemaKey) int64 {
+	q.m.Lock()
+	defer q.m.Unlock()
+	return q.usage[owner][sck]
+}
+
+// release frees schema sck from the quota it is counted in.
+func (q *uploadQuotas) release(sck store.SchemaKey) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	for owner, scs := range q.usage {
+		delete(scs, sck)
+		if len(scs) == 0 {
+			delete(q.usage, owner)
+		}
+	}
+}
+
+func (q *uploadQuotas) set(owner string, sck store.SchemaKey, size int64) {
+	if q.usage[owner] == nil {
+		q.usage[owner] = make(map[store.SchemaKey]int64)
+	}
+	q.usage[owner][sck] = size
+}
+
+func (q *uploadQuotas) bytes(owner string) int64 {
+	var n int64
+	for _, size := range q.usage[owner] {
+		n += size
+	}
+	return n
+}
+
+func (q *uploadQuotas) Describe(ch chan<- *prometheus.Desc) {
+	ch <- quotaUsageDesc
+	ch <- quotaLimitDesc
+}
+
+func (q *uploadQuotas) Collect(ch chan<- prometheus.Metric) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	owners := make(map[string]struct{}, len(q.usage)+len(q.cfg.Owners))
+	for owner := range q.usage {
+		owners[owner] = struct{}{}
+	}
+	for owner := range q.cfg.Owners {
+		owners[owner] = struct{}{}
+	}
+	for owner := range owners {
+		quota := q.cfg.Quota(owner)
+		ch <- prometheus.MustNewConstMetric(quotaUsageDesc, prometheus.GaugeValue, float64(len(q.usage[owner])), owner, "schemas")
+		ch <- prometheus.MustNewConstMetric(quotaUsageDesc, prometheus.GaugeValue, float64(q.bytes(owner)), owner, "bytes")
+		ch <- prometheus.MustNewConstMetric(quotaLimitDesc, prometheus.GaugeValue, float64(quota.MaxSchemas), owner, "schemas")
+		ch <- prometheus.MustNewConstMetric(quotaLimitDesc, prometheus.GaugeValue, float64(quota.MaxBytes), owner, "bytes")
+	}
+}
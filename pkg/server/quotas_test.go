@@ -0,0 +1,135 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func Test_uploadOwner(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *attribution
+		want string
+	}{
+		{
+			name: "no attribution",
+			want: anonymousUploadOwner,
+		},
+		{
+			name: "x-tenant metadata",
+			a:    &attribution{Tenant: "tenant-a"},
+			want: anonymousUploadOwner,
+		},
+		{
+			name: "token tenant",
+			a:    &attribution{Tenant: "tenant-a", Subject: "alice", Authenticated: true},
+			want: "tenant-a",
+		},
+		{
+			name: "token subject",
+			a:    &attribution{Subject: "alice", Authenticated: true},
+			want: "alice",
+		},
+		{
+			name: "token without identity",
+			a:    &attribution{Authenticated: true},
+			want: anonymousUploadOwner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.a != nil {
+				ctx = context.WithValue(ctx, attributionKey{}, tt.a)
+			}
+			if got := uploadOwner(ctx); got != tt.want {
+				t.Errorf("uploadOwner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_uploadQuotas_reserve(t *testing.T) {
+	sc1 := store.SchemaKey{Name: "sc1", Vendor: "v", Version: "1"}
+	sc2 := store.SchemaKey{Name: "sc2", Vendor: "v", Version: "1"}
+	type upload struct {
+		owner   string
+		sck     store.SchemaKey
+		wantErr bool
+	}
+	tests := []struct {
+		name    string
+		uploads []upload
+		// schemas counted per owner after the uploads
+		want map[string]int
+	}{
+		{
+			name: "within the quota",
+			uploads: []upload{
+				{owner: "a", sck: sc1},
+			},
+			want: map[string]int{"a": 1},
+		},
+		{
+			name: "quota exceeded",
+			uploads: []upload{
+				{owner: "a", sck: sc1},
+				{owner: "a", sck: sc2, wantErr: true},
+			},
+			want: map[string]int{"a": 1},
+		},
+		{
+			name: "own schema uploaded again",
+			uploads: []upload{
+				{owner: "a", sck: sc1},
+				{owner: "a", sck: sc1},
+			},
+			want: map[string]int{"a": 1},
+		},
+		{
+			name: "schema uploaded again by another owner",
+			uploads: []upload{
+				{owner: "a", sck: sc1},
+				{owner: "b", sck: sc1},
+				{owner: "a", sck: sc2},
+			},
+			want: map[string]int{"a": 1, "b": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newUploadQuotas(&config.UploadQuotas{Default: &config.UploadQuota{MaxSchemas: 1}})
+			for i, u := range tt.uploads {
+				err := q.reserve(u.owner, u.sck)
+				if (err != nil) != u.wantErr {
+					t.Fatalf("upload %d: reserve() error = %v, wantErr %v", i, err, u.wantErr)
+				}
+			}
+			for owner, n := range tt.want {
+				if got := len(q.usage[owner]); got != n {
+					t.Errorf("owner %q: %d schema(s), want %d", owner, got, n)
+				}
+			}
+			if len(q.usage) != len(tt.want) {
+				t.Errorf("owners = %v, want %v", q.usage, tt.want)
+			}
+		})
+	}
+}
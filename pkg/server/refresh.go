@@ -0,0 +1,163 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// refreshState is the schedule of a configured schema refresh.
+type refreshState struct {
+	// schedule the next time was computed from
+	schedule string
+	next     time.Time
+	running  bool
+}
+
+// refreshSchemas refreshes the configured schemas with a refresh schedule until ctx is done.
+// The configured schemas are checked every minute, their current config is used:
+// a config reload adding, changing or removing a schedule applies from the next check.
+func (s *Server) refreshSchemas(ctx context.Context) {
+	states := make(map[store.SchemaKey]*refreshState)
+	done := make(chan store.SchemaKey)
+	for {
+		now := time.Now()
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case sck := <-done:
+			timer.Stop()
+			if st, ok := states[sck]; ok {
+				st.running = false
+			}
+			continue
+		case <-timer.C:
+		}
+		now = time.Now()
+		scheduled := make(map[store.SchemaKey]struct{})
+		for _, sck := range s.configured.keys() {
+			b, ok := s.configured.get(sck)
+			if !ok {
+				continue
+			}
+			sCfg := new(config.SchemaConfig)
+			if err := json.Unmarshal(b, sCfg); err != nil || sCfg.Refresh == nil {
+				continue
+			}
+			sched, err := utils.ParseCron(sCfg.Refresh.Schedule)
+			if err != nil {
+				log.Errorf("schema %s: %v", sck, err)
+				continue
+			}
+			scheduled[sck] = struct{}{}
+			st, ok := states[sck]
+			if !ok || st.schedule != sCfg.Refresh.Schedule {
+				st = &refreshState{schedule: sCfg.Refresh.Schedule, next: sched.Next(now.Add(-time.Minute))}
+				states[sck] = st
+				log.Infof("schema %s: next refresh at %s", sck, st.next.Format(time.RFC3339))
+			}
+			if st.next.IsZero() || now.Before(st.next) {
+				continue
+			}
+			st.next = sched.Next(now)
+			if st.running {
+				log.Warnf("schema %s: previous refresh still running, skipping this one", sck)
+				continue
+			}
+			st.running = true
+			go func(sck store.SchemaKey, b []byte, jitter time.Duration) {
+				defer func() {
+					select {
+					case done <- sck:
+					case <-ctx.Done():
+					}
+				}()
+				if jitter > 0 {
+					select {
+					case <-time.After(time.Duration(rand.Int63n(int64(jitter)))):
+					case <-ctx.Done():
+						return
+					}
+				}
+				s.refreshSchema(ctx, sck, b)
+			}(sck, b, sCfg.Refresh.Jitter)
+		}
+		for sck, st := range states {
+			if _, ok := scheduled[sck]; !ok && !st.running {
+				delete(states, sck)
+			}
+		}
+	}
+}
+
+// refreshSchema fetches the sources of the configured schema sck, b being its encoded config,
+// and reloads it if their content changed. If parsing fails the stored schema is kept.
+func (s *Server) refreshSchema(ctx context.Context, sck store.SchemaKey, b []byte) {
+	// a schema deleted at runtime is not brought back
+	if !s.schemaStore.HasSchema(sck) {
+		return
+	}
+	sCfg := new(config.SchemaConfig)
+	if err := json.Unmarshal(b, sCfg); err != nil {
+		log.Errorf("schema %s refresh: %v", sck, err)
+		return
+	}
+	log.Infof("schema %s: refreshing sources", sck)
+	if s.storedSchemaCurrent(ctx, sck, sCfg) {
+		log.Infof("schema %s: sources unchanged", sck)
+		return
+	}
+	sc, err := schema.NewSchemaContext(ctx, sCfg)
+	if err != nil {
+		log.Errorf("schema %s refresh: parsing failed: %v", sck, err)
+		log.Warnf("schema %s: serving the stored schema", sck)
+		return
+	}
+	// parsed outside of the reload lock, the schema is not replaced
+	// if a config reload changed or removed it meanwhile
+	s.configured.reload.Lock()
+	defer s.configured.reload.Unlock()
+	if cb, ok := s.configured.get(sck); !ok || !bytes.Equal(cb, b) || !s.schemaStore.HasSchema(sck) {
+		log.Infof("schema %s changed during its refresh: not replacing it", sck)
+		return
+	}
+	// keep the content being replaced for ExportSchemaChanges
+	if _, err := s.snapshot(ctx, sCfg.GetSchema(), sck); err != nil {
+		log.Warnf("failed to snapshot schema %s before refresh: %v", sck, err)
+	}
+	now := time.Now()
+	err = s.schemaStore.ReplaceSchema(sc)
+	if err != nil {
+		log.Errorf("schema %s refresh: failed to replace the stored schema: %v", sck, err)
+		log.Warnf("schema %s: serving the stored schema", sck)
+		return
+	}
+	log.Infof("schema %s saved in %s", sc.UniqueName(""), time.Since(now))
+	s.notify(sck, api.SchemaEventReloaded)
+}
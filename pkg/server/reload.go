@@ -0,0 +1,268 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// configuredSchemas tracks the schemas loaded from the config file,
+// the ones created or uploaded at runtime are left alone by a config reload.
+// It holds the schema settings of the config last read, s.config being
+// the one the server was started with.
+type configuredSchemas struct {
+	m sync.Mutex
+	// serializes the reloads
+	reload sync.Mutex
+	// config file path, set by SetConfigFile
+	file string
+	// JSON encoding of each schema config, as read from the file
+	schemas map[store.SchemaKey][]byte
+	// limits of the loaded schemas that have some
+	limits map[store.SchemaKey]*config.SchemaLimits
+	// schema store limits
+	defaultLimits *config.SchemaLimits
+	// all the schemas of the file, including the ones owned by another instance
+	inFile map[store.SchemaKey]struct{}
+}
+
+// setFile records the schema store settings of c, read from the config file.
+func (c *configuredSchemas) setFile(sc *config.SchemaStoreConfig) {
+	inFile := make(map[store.SchemaKey]struct{}, len(sc.Schemas))
+	for _, sCfg := range sc.Schemas {
+		inFile[schemaConfigKey(sCfg)] = struct{}{}
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.defaultLimits = sc.Limits
+	c.inFile = inFile
+}
+
+// set records the encoded config b of the loaded schema sck, and its limits.
+func (c *configuredSchemas) set(sck store.SchemaKey, b []byte, l *config.SchemaLimits) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.schemas[sck] = b
+	if l != nil {
+		c.limits[sck] = l
+	} else {
+		delete(c.limits, sck)
+	}
+}
+
+// schemaLimits returns the limits of schema sck, the schema store ones if it has none.
+func (c *configuredSchemas) schemaLimits(sck store.SchemaKey) *config.SchemaLimits {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if l, ok := c.limits[sck]; ok {
+		return l
+	}
+	return c.defaultLimits
+}
+
+// isInFile reports whether schema sck is in the config file.
+func (c *configuredSchemas) isInFile(sck store.SchemaKey) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	_, ok := c.inFile[sck]
+	return ok
+}
+
+func (c *configuredSchemas) get(sck store.SchemaKey) ([]byte, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	b, ok := c.schemas[sck]
+	return b, ok
+}
+
+func (c *configuredSchemas) delete(sck store.SchemaKey) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	delete(c.schemas, sck)
+	delete(c.limits, sck)
+}
+
+func (c *configuredSchemas) keys() []store.SchemaKey {
+	c.m.Lock()
+	defer c.m.Unlock()
+	scks := make([]store.SchemaKey, 0, len(c.schemas))
+	for sck := range c.schemas {
+		scks = append(scks, sck)
+	}
+	return scks
+}
+
+// SetConfigFile sets the path of the config file re-read by ReloadConfig.
+func (s *Server) SetConfigFile(file string) {
+	s.configured.m.Lock()
+	defer s.configured.m.Unlock()
+	s.configured.file = file
+}
+
+func (s *Server) ReloadConfig(ctx context.Context, req *api.ReloadConfigRequest) (*api.ReloadConfigResponse, error) {
+	log.Debugf("received ReloadConfig: %v", req)
+	s.configured.m.Lock()
+	file := s.configured.file
+	s.configured.m.Unlock()
+	if file == "" {
+		return nil, status.Error(codes.FailedPrecondition, "the server was not started from a config file")
+	}
+	c, err := config.New(file)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to read config: %v", err)
+	}
+	s.configured.setFile(c.SchemaStore)
+	return s.reloadSchemas(ctx, s.ownedSchemas(c.SchemaStore.Schemas)), nil
+}
+
+// reloadSchemas applies the configured schemas list cfgs: the schemas no longer in the list
+// are deleted, the new ones are loaded and the ones with a changed config are reloaded.
+// The requests against the other schemas are not affected.
+func (s *Server) reloadSchemas(ctx context.Context, cfgs []*config.SchemaConfig) *api.ReloadConfigResponse {
+	s.configured.reload.Lock()
+	defer s.configured.reload.Unlock()
+
+	rsp := &api.ReloadConfigResponse{}
+	wanted := make(map[store.SchemaKey]struct{}, len(cfgs))
+	// schemas to parse, along with their encoded config and limits
+	toLoad := make([]*config.SchemaConfig, 0)
+	encoded := make(map[store.SchemaKey][]byte, len(cfgs))
+	limits := make(map[store.SchemaKey]*config.SchemaLimits, len(cfgs))
+	for _, sCfg := range cfgs {
+		sck := store.SchemaKey{Name: sCfg.Name, Vendor: sCfg.Vendor, Version: sCfg.Version}
+		wanted[sck] = struct{}{}
+		b, err := json.Marshal(sCfg)
+		if err != nil {
+			rsp.Failed = append(rsp.Failed, &api.ReloadFailure{Schema: sCfg.GetSchema(), Error: err.Error()})
+			continue
+		}
+		ob, ok := s.configured.get(sck)
+		switch {
+		case ok && bytes.Equal(ob, b):
+			rsp.Unchanged = append(rsp.Unchanged, sCfg.GetSchema())
+			continue
+		case !ok && s.schemaStore.HasSchema(sck) && s.storedSchemaCurrent(ctx, sck, sCfg):
+			// as on startup, a stored schema with the same sources is not reloaded
+			log.Infof("schema %s already exists in the store with the same sources: not reloading it...", sck)
+			s.configured.set(sck, b, sCfg.Limits)
+			rsp.Unchanged = append(rsp.Unchanged, sCfg.GetSchema())
+			continue
+		}
+		encoded[sck] = b
+		limits[sck] = sCfg.Limits
+		toLoad = append(toLoad, sCfg)
+	}
+	for _, sck := range s.configured.keys() {
+		if _, ok := wanted[sck]; ok {
+			continue
+		}
+		sc := &sdcpb.Schema{Name: sck.Name, Vendor: sck.Vendor, Version: sck.Version}
+		if s.schemaStore.HasSchema(sck) {
+			_, err := s.schemaStore.DeleteSchema(ctx, &sdcpb.DeleteSchemaRequest{Schema: sc})
+			if err != nil {
+				rsp.Failed = append(rsp.Failed, &api.ReloadFailure{Schema: sc, Error: err.Error()})
+				continue
+			}
+		}
+		s.configured.delete(sck)
+		s.notify(sck, api.SchemaEventRemoved)
+		log.Infof("schema %s removed from the config: deleted", sck)
+		rsp.Removed = append(rsp.Removed, sc)
+	}
+
+	// parse before replacing, a schema failing to parse is kept as it was
+	scs := make([]*schema.Schema, len(toLoad))
+	errs := make([]error, len(toLoad))
+	wg := new(sync.WaitGroup)
+	wg.Add(len(toLoad))
+	for i, sCfg := range toLoad {
+		go func(i int, sCfg *config.SchemaConfig) {
+			defer wg.Done()
+			scs[i], errs[i] = schema.NewSchemaContext(ctx, sCfg)
+		}(i, sCfg)
+	}
+	wg.Wait()
+	for i, sCfg := range toLoad {
+		if errs[i] != nil {
+			log.Errorf("schema %s parsing failed: %v", sCfg.Name, errs[i])
+			rsp.Failed = append(rsp.Failed, &api.ReloadFailure{Schema: sCfg.GetSchema(), Error: errs[i].Error()})
+			continue
+		}
+		sck := store.Key(scs[i])
+		reloaded := s.schemaStore.HasSchema(sck)
+		now := time.Now()
+		var err error
+		if reloaded {
+			// keep the content being replaced for ExportSchemaChanges
+			if _, err := s.snapshot(ctx, sCfg.GetSchema(), sck); err != nil {
+				log.Warnf("failed to snapshot schema %s before reload: %v", sck, err)
+			}
+			// the stored schema is kept if it can not be replaced
+			err = s.schemaStore.ReplaceSchema(scs[i])
+		} else {
+			err = s.schemaStore.AddSchema(scs[i])
+		}
+		if err != nil {
+			rsp.Failed = append(rsp.Failed, &api.ReloadFailure{Schema: sCfg.GetSchema(), Error: err.Error()})
+			continue
+		}
+		s.configured.set(sck, encoded[sck], limits[sck])
+		log.Infof("schema %s saved in %s", scs[i].UniqueName(""), time.Since(now))
+		if reloaded {
+			s.notify(sck, api.SchemaEventReloaded)
+			rsp.Reloaded = append(rsp.Reloaded, sCfg.GetSchema())
+			continue
+		}
+		s.notify(sck, api.SchemaEventAdded)
+		rsp.Added = append(rsp.Added, sCfg.GetSchema())
+	}
+	for _, l := range [][]*sdcpb.Schema{rsp.Added, rsp.Removed, rsp.Reloaded, rsp.Unchanged} {
+		sortSchemas(l)
+	}
+	log.Infof("config reloaded: %d schema(s) added, %d removed, %d reloaded, %d unchanged, %d failed",
+		len(rsp.Added), len(rsp.Removed), len(rsp.Reloaded), len(rsp.Unchanged), len(rsp.Failed))
+	return rsp
+}
+
+func sortSchemas(scs []*sdcpb.Schema) {
+	sort.Slice(scs, func(i, j int) bool {
+		return schemaLess(scs[i], scs[j])
+	})
+}
+
+func schemaLess(a, b *sdcpb.Schema) bool {
+	if a.GetName() != b.GetName() {
+		return a.GetName() < b.GetName()
+	}
+	if a.GetVendor() != b.GetVendor() {
+		return a.GetVendor() < b.GetVendor()
+	}
+	return a.GetVersion() < b.GetVersion()
+}
@@ -0,0 +1,352 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	restPrefix = "/api/v1"
+	// media type of the RFC 7951 encoded documents
+	yangDataJSON = "application/yang-data+json"
+)
+
+// registerREST registers the read-only REST/JSON endpoints:
+//
+//	GET /api/v1/schemas
+//	GET /api/v1/schemas/{name}/{vendor}/{version}
+//	GET /api/v1/schemas/{name}/{vendor}/{version}/schema?path=
+//	GET /api/v1/schemas/{name}/{vendor}/{version}/elements?path=
+//	GET /api/v1/schemas/{name}/{vendor}/{version}/paths?path=
+//	GET /api/v1/schemas/{name}/{vendor}/{version}/yang-library
+//
+// The responses are the protojson encoding of the gRPC ones,
+// the elements endpoint returns the streamed elements as an "elements" array,
+// the paths one the ExpandPath xpaths of all the data types
+// and the yang-library one the document of the GetYangLibrary response.
+// They have a weak ETag, a request with a matching If-None-Match gets a 304:
+// the one of a schema, elements or paths response is derived from the schema fingerprint
+// and checked before the response is built, the others are a hash of the response.
+func (s *Server) registerREST() {
+	r := s.router.PathPrefix(restPrefix).Subrouter()
+	r.HandleFunc("/schemas", s.restListSchema).Methods(http.MethodGet)
+	r.HandleFunc("/schemas/{name}/{vendor}/{version}", s.restGetSchemaDetails).Methods(http.MethodGet)
+	r.HandleFunc("/schemas/{name}/{vendor}/{version}/schema", s.restGetSchema).Methods(http.MethodGet)
+	r.HandleFunc("/schemas/{name}/{vendor}/{version}/elements", s.restGetSchemaElements).Methods(http.MethodGet)
+	r.HandleFunc("/schemas/{name}/{vendor}/{version}/paths", s.restExpandPath).Methods(http.MethodGet)
+	r.HandleFunc("/schemas/{name}/{vendor}/{version}/yang-library", s.restGetYangLibrary).Methods(http.MethodGet)
+}
+
+func (s *Server) restListSchema(w http.ResponseWriter, r *http.Request) {
+	rsp, ok := s.restCall(w, r, "ListSchema", &sdcpb.ListSchemaRequest{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.ListSchema(ctx, req.(*sdcpb.ListSchemaRequest))
+		})
+	if ok {
+		s.writeRESTProto(w, r, rsp.(proto.Message))
+	}
+}
+
+func (s *Server) restGetSchemaDetails(w http.ResponseWriter, r *http.Request) {
+	rsp, ok := s.restCall(w, r, "GetSchemaDetails", &sdcpb.GetSchemaDetailsRequest{Schema: restSchema(r)},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.GetSchemaDetails(ctx, req.(*sdcpb.GetSchemaDetailsRequest))
+		})
+	if ok {
+		s.writeRESTProto(w, r, rsp.(proto.Message))
+	}
+}
+
+func (s *Server) restGetSchema(w http.ResponseWriter, r *http.Request) {
+	req, err := restGetSchemaRequest(r)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	rsp, ok := s.restCall(w, r, "GetSchema", req,
+		s.restConditional(w, r, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.GetSchema(ctx, req.(*sdcpb.GetSchemaRequest))
+		}))
+	if ok {
+		writeHTTPProto(w, rsp.(proto.Message))
+	}
+}
+
+type restSchemaElements struct {
+	Elements []json.RawMessage `json:"elements"`
+}
+
+func (s *Server) restGetSchemaElements(w http.ResponseWriter, r *http.Request) {
+	req, err := restGetSchemaRequest(r)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	rsp, ok := s.restCall(w, r, "GetSchemaElements", req,
+		s.restConditional(w, r, func(ctx context.Context, req interface{}) (interface{}, error) {
+			ch, err := s.schemaStore.GetSchemaElements(ctx, req.(*sdcpb.GetSchemaRequest))
+			if err != nil {
+				return nil, err
+			}
+			elems := &restSchemaElements{Elements: make([]json.RawMessage, 0)}
+			for se := range ch {
+				b, err := protojson.Marshal(se)
+				if err != nil {
+					// let the store goroutine writing to ch terminate
+					go func() {
+						for range ch {
+						}
+					}()
+					return nil, err
+				}
+				elems.Elements = append(elems.Elements, b)
+			}
+			return elems, nil
+		}))
+	if ok {
+		writeHTTPJSON(w, http.StatusOK, rsp)
+	}
+}
+
+func (s *Server) restExpandPath(w http.ResponseWriter, r *http.Request) {
+	gr, err := restGetSchemaRequest(r)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	req := &sdcpb.ExpandPathRequest{
+		Path:     gr.GetPath(),
+		Schema:   gr.GetSchema(),
+		DataType: sdcpb.DataType_ALL,
+		Xpath:    true,
+	}
+	rsp, ok := s.restCall(w, r, "ExpandPath", req,
+		s.restConditional(w, r, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.ExpandPath(ctx, req.(*sdcpb.ExpandPathRequest))
+		}))
+	if ok {
+		writeHTTPProto(w, rsp.(proto.Message))
+	}
+}
+
+func (s *Server) restGetYangLibrary(w http.ResponseWriter, r *http.Request) {
+	rsp, ok := s.restInvoke(w, r, api.FullMethod("GetYangLibrary"), &api.GetYangLibraryRequest{Schema: restSchema(r)},
+		s.restConditional(w, r, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.GetYangLibrary(ctx, req.(*api.GetYangLibraryRequest))
+		}))
+	if ok {
+		w.Header().Set("Content-Type", yangDataJSON)
+		w.Write(rsp.(*api.GetYangLibraryResponse).Document)
+	}
+}
+
+// restCall runs handler through the gRPC unary interceptors as the method of the SchemaServer service,
+// the response metadata is returned as HTTP headers.
+// The error, if any, is written to w.
+func (s *Server) restCall(w http.ResponseWriter, r *http.Request, method string, req interface{}, handler grpc.UnaryHandler) (interface{}, bool) {
+	return s.restInvoke(w, r, "/"+sdcpb.SchemaServer_ServiceDesc.ServiceName+"/"+method, req, handler)
+}
+
+// restInvoke is restCall of the gRPC method fullMethod, of any service.
+func (s *Server) restInvoke(w http.ResponseWriter, r *http.Request, fullMethod string, req interface{}, handler grpc.UnaryHandler) (interface{}, bool) {
+	ts := &restTransportStream{method: fullMethod, header: metadata.MD{}}
+	ctx := grpc.NewContextWithServerTransportStream(restContext(r), ts)
+	rsp, err := s.unaryInterceptor(ctx, req, &grpc.UnaryServerInfo{Server: s, FullMethod: fullMethod}, handler)
+	for k, vs := range ts.header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	if err != nil {
+		// an error is not cached
+		w.Header().Del("ETag")
+		w.Header().Del("Cache-Control")
+		writeHTTPError(w, err)
+		return nil, false
+	}
+	if _, ok := rsp.(notModified); ok {
+		w.WriteHeader(http.StatusNotModified)
+		return nil, false
+	}
+	return rsp, true
+}
+
+// notModified is the response of a handler to a request
+// whose If-None-Match matches the ETag of the response.
+type notModified struct{}
+
+// restConditional returns handler of a request of a schema, answering notModified
+// if its If-None-Match matches the ETag derived from the schema fingerprint.
+// The schema is the one of the request reaching the handler, the interceptors
+// can change it.
+func (s *Server) restConditional(w http.ResponseWriter, r *http.Request, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		sc, _ := requestInfo(req)
+		fp, err := s.fingerprint(ctx, sc)
+		if err != nil {
+			return nil, err
+		}
+		tag := etag(fp, sc.GetName(), sc.GetVendor(), sc.GetVersion(), r.URL.Path, r.URL.RawQuery)
+		s.setCacheHeaders(w, tag)
+		if ifNoneMatch(r, tag) {
+			return notModified{}, nil
+		}
+		return handler(ctx, req)
+	}
+}
+
+// writeRESTProto writes m with an ETag hashing it, or a 304 if the If-None-Match of r matches it.
+func (s *Server) writeRESTProto(w http.ResponseWriter, r *http.Request, m proto.Message) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	tag := etag(string(b))
+	s.setCacheHeaders(w, tag)
+	if ifNoneMatch(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeHTTPProto(w, m)
+}
+
+// setCacheHeaders sets the ETag and the caching policy of a response.
+// With authentication or authorization the answer depends on the client:
+// only its own cache can keep it, keyed by its credentials and tenant.
+func (s *Server) setCacheHeaders(w http.ResponseWriter, tag string) {
+	w.Header().Set("ETag", tag)
+	cc := "no-cache"
+	if maxAge := s.config.Prometheus.RESTMaxAge; maxAge > 0 {
+		cc = "max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	}
+	if s.authenticator != nil || s.authorizer != nil {
+		cc = "private, " + cc
+		w.Header().Set("Vary", "Authorization, X-Tenant")
+	}
+	w.Header().Set("Cache-Control", cc)
+}
+
+// etag returns a weak ETag hashing parts: the JSON encoding of
+// the responses is not byte for byte stable.
+func etag(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// ifNoneMatch reports whether the If-None-Match header of r matches tag,
+// compared weakly as RFC 9110 requires.
+func ifNoneMatch(r *http.Request, tag string) bool {
+	for _, v := range r.Header.Values("If-None-Match") {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.TrimSpace(t)
+			if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(tag, "W/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// restContext returns the context of a REST request as the one of a gRPC request:
+// the request headers are the incoming metadata, the client certificate, if any, is the peer's.
+func restContext(r *http.Request) context.Context {
+	md := make(metadata.MD, len(r.Header))
+	for k, vs := range r.Header {
+		md[strings.ToLower(k)] = vs
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	p := &peer.Peer{}
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		p.Addr = addr
+	}
+	if r.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+	}
+	return peer.NewContext(ctx, p)
+}
+
+// restTransportStream collects the response metadata set by the interceptors and handlers.
+type restTransportStream struct {
+	method string
+	header metadata.MD
+}
+
+func (ts *restTransportStream) Method() string {
+	return ts.method
+}
+
+func (ts *restTransportStream) SetHeader(md metadata.MD) error {
+	for k, vs := range md {
+		ts.header[k] = append(ts.header[k], vs...)
+	}
+	return nil
+}
+
+func (ts *restTransportStream) SendHeader(md metadata.MD) error {
+	return ts.SetHeader(md)
+}
+
+// SetTrailer adds the trailers to the headers, they are all sent before the body.
+func (ts *restTransportStream) SetTrailer(md metadata.MD) error {
+	return ts.SetHeader(md)
+}
+
+func restSchema(r *http.Request) *sdcpb.Schema {
+	vars := mux.Vars(r)
+	return &sdcpb.Schema{
+		Name:    vars["name"],
+		Vendor:  vars["vendor"],
+		Version: vars["version"],
+	}
+}
+
+func restGetSchemaRequest(r *http.Request) (*sdcpb.GetSchemaRequest, error) {
+	xp := r.URL.Query().Get("path")
+	if xp == "" {
+		xp = "/"
+	}
+	p, err := utils.ParsePath(xp)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid path %q: %v", xp, err)
+	}
+	return &sdcpb.GetSchemaRequest{Schema: restSchema(r), Path: p}, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+func TestServer_setCacheHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		authz  bool
+		cc     string
+		vary   string
+	}{
+		{name: "revalidated", cc: "no-cache"},
+		{name: "max age", maxAge: time.Minute, cc: "max-age=60"},
+		{name: "authorized", authz: true, cc: "private, no-cache", vary: "Authorization, X-Tenant"},
+		{name: "authorized max age", maxAge: time.Minute, authz: true, cc: "private, max-age=60", vary: "Authorization, X-Tenant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{config: &config.Config{Prometheus: &config.PromConfig{RESTMaxAge: tt.maxAge}}}
+			if tt.authz {
+				s.authorizer = versionAuthorizer{}
+			}
+			w := httptest.NewRecorder()
+			s.setCacheHeaders(w, `W/"tag"`)
+			if got := w.Header().Get("Cache-Control"); got != tt.cc {
+				t.Errorf("got Cache-Control %q, want %q", got, tt.cc)
+			}
+			if got := w.Header().Get("Vary"); got != tt.vary {
+				t.Errorf("got Vary %q, want %q", got, tt.vary)
+			}
+		})
+	}
+}
+
+func TestServer_basicAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name  string
+		authn bool
+		path  string
+		// basic auth credentials of the request, a bearer token if empty
+		user string
+		code int
+	}{
+		{name: "metrics", path: "/metrics", user: "admin", code: http.StatusOK},
+		{name: "metrics without credentials", path: "/metrics", code: http.StatusUnauthorized},
+		{name: "metrics with authn", authn: true, path: "/metrics", user: "admin", code: http.StatusOK},
+		{name: "metrics bearer", authn: true, path: "/metrics", code: http.StatusUnauthorized},
+		{name: "REST", path: restPrefix + "/schemas", user: "admin", code: http.StatusOK},
+		{name: "REST without credentials", path: restPrefix + "/schemas", code: http.StatusUnauthorized},
+		// the bearer token is checked by the authn interceptor
+		{name: "REST bearer", authn: true, path: restPrefix + "/schemas", code: http.StatusOK},
+		{name: "UI with authn", authn: true, path: "/ui/", code: http.StatusUnauthorized},
+		{name: "REST prefix", authn: true, path: restPrefix + "x/schemas", code: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GRPCServer: &config.GRPCServer{},
+				Prometheus: &config.PromConfig{BasicAuth: &config.BasicAuth{Username: "admin", Password: "secret"}},
+			}
+			if tt.authn {
+				cfg.GRPCServer.Authentication = &config.AuthenticationConfig{}
+			}
+			s := &Server{config: cfg}
+			h := s.basicAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.user != "" {
+				r.SetBasicAuth(tt.user, "secret")
+			} else {
+				r.Header.Set("Authorization", "Bearer token")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("got status %d, want %d", w.Code, tt.code)
+			}
+		})
+	}
+}
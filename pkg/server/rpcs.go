@@ -25,59 +25,180 @@ import (
 	"hash"
 	"io"
 	"os"
-	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/sdcio/schema-server/pkg/api"
 	"github.com/sdcio/schema-server/pkg/config"
 	"github.com/sdcio/schema-server/pkg/schema"
 	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
 )
 
 func (s *Server) GetSchema(ctx context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
 	log.Debugf("received GetSchemaRequest: %v", req)
-	return s.schemaStore.GetSchema(ctx, req)
+	if sc := s.fallbackSchema(ctx, req.GetSchema()); sc != req.GetSchema() {
+		req = proto.Clone(req).(*sdcpb.GetSchemaRequest)
+		req.Schema = sc
+		// the client must be allowed to query the version answering
+		method, _ := grpc.Method(ctx)
+		if err := s.authorize(ctx, s.canonicalMethod(method), req); err != nil {
+			return nil, err
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(servedVersionHeader, sc.GetVersion()))
+	}
+	start := time.Now()
+	wctx, walk := tracer.Start(ctx, "schema.walk")
+	rsp, err := s.schemaStore.GetSchema(wctx, req)
+	endSpan(walk, err)
+	walk.End()
+	if err != nil {
+		return nil, err
+	}
+	s.schemaMetrics.observeGetSchema(req.GetSchema(), req.GetPath(), start)
+	s.setWhenHeaders(ctx, req.GetSchema(), req.GetPath())
+	return rsp, nil
 }
 
 func (s *Server) ListSchema(ctx context.Context, req *sdcpb.ListSchemaRequest) (*sdcpb.ListSchemaResponse, error) {
 	log.Debugf("received ListSchema: %v", req)
-	return s.schemaStore.ListSchema(ctx, req)
+	rsp, err := s.schemaStore.ListSchema(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.hideStaged(ctx, rsp)
+	return rsp, nil
 }
 
 func (s *Server) GetSchemaDetails(ctx context.Context, req *sdcpb.GetSchemaDetailsRequest) (*sdcpb.GetSchemaDetailsResponse, error) {
 	log.Debugf("received GetSchemaDetails: %v", req)
-	return s.schemaStore.GetSchemaDetails(ctx, req)
+	rsp, err := s.schemaStore.GetSchemaDetails(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.setVariantHeaders(ctx, req.GetSchema())
+	return rsp, nil
 }
 
 func (s *Server) CreateSchema(ctx context.Context, req *sdcpb.CreateSchemaRequest) (*sdcpb.CreateSchemaResponse, error) {
 	log.Debugf("received CreateSchema: %v", req)
-	return s.schemaStore.CreateSchema(ctx, req)
+	rsp, err := s.schemaStore.CreateSchema(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sc := req.GetSchema()
+	s.notify(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}, api.SchemaEventAdded)
+	return rsp, nil
 }
 
 func (s *Server) ReloadSchema(ctx context.Context, req *sdcpb.ReloadSchemaRequest) (*sdcpb.ReloadSchemaResponse, error) {
 	log.Debugf("received ReloadSchema: %v", req)
-	return s.schemaStore.ReloadSchema(ctx, req)
+	// keep the content being replaced for ExportSchemaChanges
+	if sck, err := s.checkSchema(req.GetSchema()); err == nil {
+		if _, err := s.snapshot(ctx, req.GetSchema(), sck); err != nil {
+			log.Warnf("failed to snapshot schema %s before reload: %v", sck, err)
+		}
+	}
+	rsp, err := s.schemaStore.ReloadSchema(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	sc := req.GetSchema()
+	s.notify(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}, api.SchemaEventReloaded)
+	return rsp, nil
 }
 
 func (s *Server) DeleteSchema(ctx context.Context, req *sdcpb.DeleteSchemaRequest) (*sdcpb.DeleteSchemaResponse, error) {
 	log.Debugf("received DeleteSchema: %v", req)
-	return s.schemaStore.DeleteSchema(ctx, req)
+	sc := req.GetSchema()
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+	rsp := &sdcpb.DeleteSchemaResponse{}
+	// an archived schema is no longer in the store
+	if st := s.lifecycle.get(sck); st == nil || st.state != api.SchemaStateArchived {
+		var err error
+		rsp, err = s.schemaStore.DeleteSchema(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.lifecycle.set(sck, nil)
+	s.notify(sck, api.SchemaEventRemoved)
+	if s.quotas != nil {
+		s.quotas.release(sck)
+	}
+	if s.uploads != nil {
+		ok, err := s.uploads.remove(sck)
+		if err != nil {
+			log.Errorf("failed to remove schema %s from the uploaded schemas registry: %v", sc, err)
+		}
+		if ok {
+			s.cleanSchemaDir(fmt.Sprintf("%s_%s_%s", sc.GetName(), sc.GetVendor(), sc.GetVersion()))
+		}
+	}
+	return rsp, nil
 }
 
 func (s *Server) ToPath(ctx context.Context, req *sdcpb.ToPathRequest) (*sdcpb.ToPathResponse, error) {
 	log.Debugf("received ToPath: %v", req)
-	return s.schemaStore.ToPath(ctx, req)
+	rsp, err := s.schemaStore.ToPath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// placeholders are key values
+	md, err := s.pathPlaceholders(ctx, req.GetSchema(), rsp.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	err = s.qualifyToPath(ctx, req.GetSchema(), rsp)
+	if err != nil {
+		return nil, err
+	}
+	if md != nil {
+		_ = grpc.SetHeader(ctx, md)
+	}
+	return rsp, nil
 }
 
 func (s *Server) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest) (*sdcpb.ExpandPathResponse, error) {
 	log.Debugf("received ExpandPath: %v", req)
-	return s.schemaStore.ExpandPath(ctx, req)
+	err := s.checkSubtreeLimits(ctx, req.GetSchema(), req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	md, err := s.pathPlaceholders(ctx, req.GetSchema(), req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := s.schemaStore.ExpandPath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	err = s.qualifyExpandPath(ctx, req.GetSchema(), rsp)
+	if err != nil {
+		return nil, err
+	}
+	if md != nil {
+		_ = grpc.SetHeader(ctx, md)
+	}
+	return rsp, nil
 }
 
+// response metadata identifying the schema registered by an upload
+const (
+	registeredNameHeader    = "x-schema-registered-name"
+	registeredVendorHeader  = "x-schema-registered-vendor"
+	registeredVersionHeader = "x-schema-registered-version"
+)
+
 func (s *Server) UploadSchema(stream sdcpb.SchemaServer_UploadSchemaServer) error {
 	log.Infof("starting upload stream")
 	createReq, err := stream.Recv()
@@ -86,10 +207,14 @@ func (s *Server) UploadSchema(stream sdcpb.SchemaServer_UploadSchemaServer) erro
 	}
 	log.Debugf("received first msg in upload stream: %v", createReq)
 	scConfig := &config.SchemaConfig{
-		Files:       []string{},
-		Directories: []string{},
-		Excludes:    []string{},
+		Files:               []string{},
+		Directories:         []string{},
+		Excludes:            []string{},
+		DescriptionLanguage: s.config.SchemaStore.DescriptionLanguage,
+		Features:            s.config.SchemaStore.Features,
+		Lint:                s.config.SchemaStore.Lint,
 	}
+	var scKey store.SchemaKey
 	switch req := createReq.Upload.(type) {
 	default:
 		return status.Error(codes.InvalidArgument, "unexpected msg type: expecting UploadSchemaRequest_CreateSchema")
@@ -105,25 +230,44 @@ func (s *Server) UploadSchema(stream sdcpb.SchemaServer_UploadSchemaServer) erro
 		scConfig.Name = req.CreateSchema.GetSchema().GetName()
 		scConfig.Vendor = req.CreateSchema.GetSchema().GetVendor()
 		scConfig.Version = req.CreateSchema.GetSchema().GetVersion()
-		scKey := store.SchemaKey{
+		scKey = store.SchemaKey{
 			Name:    scConfig.Name,
 			Vendor:  scConfig.Vendor,
 			Version: scConfig.Version,
 		}
 		scConfig.Excludes = req.CreateSchema.Exclude
 		log.Infof("uploading schema %s@%s@%s", scConfig.Name, scConfig.Vendor, scConfig.Version)
+		if st := s.lifecycle.get(scKey); st != nil && st.state == api.SchemaStateArchived {
+			return reasonError(codes.InvalidArgument, api.ReasonSchemaArchived, schemaErrorInfo(scKey),
+				"schema %s is archived", scKey)
+		}
 		if s.schemaStore.HasSchema(scKey) {
 			log.Errorf("schema %s@%s@%s already exists", scConfig.Name, scConfig.Vendor, scConfig.Version)
 			return status.Errorf(codes.InvalidArgument, "schema %s@%s@%s already exists", scConfig.Name, scConfig.Vendor, scConfig.Version)
 		}
 	}
+	owner := uploadOwner(stream.Context())
+	var registered bool
+	if s.quotas != nil {
+		err = s.quotas.reserve(owner, scKey)
+		if err != nil {
+			log.Errorf("schema %s: %v", scKey, err)
+			return err
+		}
+		defer func() {
+			if !registered {
+				s.quotas.release(scKey)
+			}
+		}()
+	}
 	dirname := fmt.Sprintf("%s_%s_%s", scConfig.Name, scConfig.Vendor, scConfig.Version)
-	err = os.RemoveAll(path.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname))
+	err = os.RemoveAll(filepath.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname))
 	if err != nil {
 		log.Errorf("failed to clean directory %s: %v", dirname, err)
 		return status.Errorf(codes.Internal, "failed to clean directory %s: %v", dirname, err)
 	}
-	handledFiles := make(map[string]*os.File)
+	handledFiles := newStreamedFiles()
+	defer handledFiles.close()
 LOOP:
 	for {
 		updloadFileReq, err := stream.Recv()
@@ -139,21 +283,36 @@ LOOP:
 			}
 			var uplFile *os.File
 			var ok bool
-			fileName := path.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname, updloadFileReq.SchemaFile.GetFileName())
+			// file names are slash separated, whatever the client OS
+			fileName, err := utils.JoinUnder(filepath.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname), updloadFileReq.SchemaFile.GetFileName())
+			if err != nil {
+				s.cleanSchemaDir(dirname)
+				return status.Errorf(codes.InvalidArgument, "invalid file name: %v", err)
+			}
+			if handledFiles.full(fileName) {
+				s.cleanSchemaDir(dirname)
+				return status.Errorf(codes.ResourceExhausted, "the upload exceeds %d files", maxStreamedFiles)
+			}
 			log.Debugf("creating file if it doesn't exist: %s", fileName)
-			log.Debugf("handled files: %v", handledFiles)
-			uplFile, ok = handledFiles[fileName]
-			if !ok {
-				log.Debugf("file doesn't exist %s, creating it", fileName)
-				uplFile, err = createFileWithDir(fileName)
-				if err != nil {
-					return err
-				}
-				handledFiles[fileName] = uplFile
+			uplFile, ok, err = handledFiles.open(fileName)
+			if err != nil {
+				s.cleanSchemaDir(dirname)
+				return err
+			}
+			if ok {
 				log.Debugf("created file: %s", fileName)
 			}
 
 			if len(updloadFileReq.SchemaFile.GetContents()) > 0 {
+				if s.quotas != nil {
+					err = s.quotas.grow(owner, scKey, int64(len(updloadFileReq.SchemaFile.GetContents())))
+					if err != nil {
+						log.Errorf("schema %s: %v", scKey, err)
+						uplFile.Close()
+						s.cleanSchemaDir(dirname)
+						return err
+					}
+				}
 				log.Debugf("writing %d to %s", len(updloadFileReq.SchemaFile.GetContents()), fileName)
 				_, err = uplFile.Write(updloadFileReq.SchemaFile.GetContents())
 				if err != nil {
@@ -215,7 +374,7 @@ LOOP:
 					s.cleanSchemaDir(dirname)
 					return status.Errorf(codes.FailedPrecondition, "file %s has wrong hash", updloadFileReq.SchemaFile.GetFileName())
 				}
-				err = uplFile.Close()
+				err = handledFiles.done(fileName)
 				if err != nil {
 					log.Errorf("failed to close file: %v", err)
 				}
@@ -225,11 +384,10 @@ LOOP:
 				case sdcpb.UploadSchemaFile_DEPENDENCY:
 					scConfig.Directories = append(scConfig.Directories, fileName)
 				}
-				delete(handledFiles, fileName)
 			}
 		case *sdcpb.UploadSchemaRequest_Finalize:
 			log.Debugf("got finalize msg")
-			if len(handledFiles) != 0 {
+			if len(handledFiles.pending) != 0 {
 				log.Errorf("got finalize but there are pending files")
 				s.cleanSchemaDir(dirname)
 				return status.Errorf(codes.FailedPrecondition, "not all files are fully uploaded")
@@ -241,42 +399,69 @@ LOOP:
 		}
 	}
 	log.Infof("all files uploaded, parsing schema...")
+	// parsing expands the files and directories
+	upCfg := *scConfig
+	upCfg.Files = append([]string(nil), scConfig.Files...)
+	upCfg.Directories = append([]string(nil), scConfig.Directories...)
 
-	sc, err := schema.NewSchema(scConfig)
+	sc, err := schema.NewSchemaContext(stream.Context(), scConfig)
 	if err != nil {
 		s.cleanSchemaDir(dirname)
+		var perr *schema.ParseError
+		if errors.As(err, &perr) {
+			// report the files as uploaded by the client
+			trimUploadDir(perr, filepath.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname))
+			return perr
+		}
 		return err
 	}
 	err = s.schemaStore.AddSchema(sc)
 	if err != nil {
 		return err
 	}
+	registered = true
+	log.Infof("registered uploaded schema %s", sc.UniqueName(""))
+	s.notify(scKey, api.SchemaEventAdded)
+	if s.uploads != nil {
+		var size int64
+		if s.quotas != nil {
+			size = s.quotas.size(owner, scKey)
+		}
+		err = s.uploads.add(&upCfg, owner, size)
+		if err != nil {
+			log.Errorf("failed to persist uploaded schema %s: %v", sc.UniqueName(""), err)
+		}
+	}
+	err = stream.SetHeader(metadata.Pairs(
+		registeredNameHeader, sc.Name(),
+		registeredVendorHeader, sc.Vendor(),
+		registeredVersionHeader, sc.Version(),
+	))
+	if err != nil {
+		log.Errorf("failed to set upload response header: %v", err)
+	}
 	stream.SendAndClose(&sdcpb.UploadSchemaResponse{})
 	return nil
 }
 
+// trimUploadDir makes the file names of a parse error relative to the upload directory dir.
+func trimUploadDir(perr *schema.ParseError, dir string) {
+	prefix := dir + string(filepath.Separator)
+	for _, me := range perr.Errors {
+		if rel, err := filepath.Rel(dir, me.File); err == nil && !strings.HasPrefix(rel, "..") {
+			me.File = filepath.ToSlash(rel)
+		}
+		me.Message = strings.ReplaceAll(me.Message, prefix, "")
+	}
+}
+
 func (s *Server) GetSchemaElements(req *sdcpb.GetSchemaRequest, stream sdcpb.SchemaServer_GetSchemaElementsServer) error {
 	ctx := stream.Context()
 	ch, err := s.schemaStore.GetSchemaElements(ctx, req)
 	if err != nil {
 		return err
 	}
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case sce, ok := <-ch:
-			if !ok {
-				return nil
-			}
-			err = stream.Send(&sdcpb.GetSchemaResponse{
-				Schema: sce,
-			})
-			if err != nil {
-				return err
-			}
-		}
-	}
+	return s.streamSchemaElems(ctx, ch, stream.Send)
 }
 
 func createFileWithDir(filePath string) (*os.File, error) {
@@ -291,8 +476,26 @@ func createFileWithDir(filePath string) (*os.File, error) {
 }
 
 func (s *Server) cleanSchemaDir(dirname string) {
-	err := os.RemoveAll(path.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname))
+	err := os.RemoveAll(filepath.Join(s.config.GRPCServer.SchemaServer.SchemasDirectory, dirname))
 	if err != nil {
 		log.Errorf("failed to clean directory %s: %v", dirname, err)
 	}
 }
+
+// checkSchema validates the schema details in a request
+// and verifies that the schema exists in the store.
+func (s *Server) checkSchema(sc *sdcpb.Schema) (store.SchemaKey, error) {
+	switch {
+	case sc == nil:
+		return store.SchemaKey{}, status.Error(codes.InvalidArgument, "missing schema details")
+	case sc.GetVendor() == "":
+		return store.SchemaKey{}, status.Error(codes.InvalidArgument, "missing schema vendor")
+	case sc.GetVersion() == "":
+		return store.SchemaKey{}, status.Error(codes.InvalidArgument, "missing schema version")
+	}
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+	if !s.schemaStore.HasSchema(sck) {
+		return store.SchemaKey{}, status.Errorf(codes.InvalidArgument, "unknown schema %v", sc)
+	}
+	return sck, nil
+}
@@ -0,0 +1,554 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) DiffSchema(req *api.DiffSchemaRequest, stream api.SchemaServerExt_DiffSchemaServer) error {
+	log.Debugf("received DiffSchema: %v", req)
+	if req.From == nil || req.To == nil {
+		return status.Error(codes.InvalidArgument, "missing from or to schema")
+	}
+	if _, err := s.checkSchema(req.From); err != nil {
+		return err
+	}
+	if _, err := s.checkSchema(req.To); err != nil {
+		return err
+	}
+	batchSize := s.config.GRPCServer.Streaming.BatchSize
+	rsp := new(api.DiffSchemaResponse)
+	send := func() error {
+		if len(rsp.Diffs) == 0 {
+			return nil
+		}
+		err := stream.Send(rsp)
+		rsp = new(api.DiffSchemaResponse)
+		return err
+	}
+	d := &schemaDiffer{
+		from: store.NewResolver(s.schemaStore, req.From),
+		to:   store.NewResolver(s.schemaStore, req.To),
+		emit: func(sd *api.SchemaDiff) error {
+			rsp.Diffs = append(rsp.Diffs, sd)
+			if len(rsp.Diffs) < batchSize {
+				return nil
+			}
+			return send()
+		},
+	}
+	err := d.diff(stream.Context(), nil)
+	if err != nil {
+		return err
+	}
+	return send()
+}
+
+// schemaDiffer walks two schemas side by side, from the root,
+// and emits their differences in path order.
+type schemaDiffer struct {
+	from, to *store.Resolver
+	emit     func(*api.SchemaDiff) error
+}
+
+// diff compares the containers or lists found at names in both schemas.
+func (d *schemaDiffer) diff(ctx context.Context, names []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fse, err := d.from.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	tse, err := d.to.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	fc, tc := fse.GetContainer(), tse.GetContainer()
+	if len(names) > 0 {
+		err = d.diffContainer(nodePath(names), fc, tc)
+		if err != nil {
+			return err
+		}
+	}
+	err = d.diffLeaves(names, fc, tc)
+	if err != nil {
+		return err
+	}
+	fchildren := make(map[string]bool, len(fc.GetChildren()))
+	for _, c := range fc.GetChildren() {
+		fchildren[c] = true
+	}
+	tchildren := make(map[string]bool, len(tc.GetChildren()))
+	for _, c := range tc.GetChildren() {
+		tchildren[c] = true
+	}
+	for _, c := range sortedUnion(fc.GetChildren(), tc.GetChildren()) {
+		cnames := append(names[:len(names):len(names)], c)
+		switch {
+		case !tchildren[c]:
+			se, err := d.from.Get(ctx, cnames)
+			if err != nil {
+				return err
+			}
+			err = d.emit(&api.SchemaDiff{
+				Kind:     api.DiffRemoved,
+				Path:     nodePath(cnames),
+				Node:     containerNode(se.GetContainer()),
+				Breaking: true,
+			})
+			if err != nil {
+				return err
+			}
+		case !fchildren[c]:
+			err := d.added(ctx, cnames)
+			if err != nil {
+				return err
+			}
+		default:
+			err := d.diff(ctx, cnames)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// added reports the container or list found at names in the To schema as added.
+func (d *schemaDiffer) added(ctx context.Context, names []string) error {
+	se, err := d.to.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	cs := se.GetContainer()
+	err = d.emit(&api.SchemaDiff{
+		Kind: api.DiffAdded,
+		Path: nodePath(names),
+		Node: containerNode(cs),
+	})
+	if err != nil {
+		return err
+	}
+	return d.requiredLeaves(ctx, names, cs)
+}
+
+// requiredLeaves reports the mandatory leaves of the added container cs,
+// a non presence container exists as soon as its parent does.
+func (d *schemaDiffer) requiredLeaves(ctx context.Context, names []string, cs *sdcpb.ContainerSchema) error {
+	if len(cs.GetKeys()) > 0 || cs.GetIsPresence() || cs.GetIsState() {
+		return nil
+	}
+	for _, ls := range sortedLeaves(cs.GetFields()) {
+		if !ls.GetIsMandatory() || ls.GetIsState() {
+			continue
+		}
+		err := d.emit(&api.SchemaDiff{
+			Kind:     api.DiffMandatoryAdded,
+			Path:     nodePath(append(names[:len(names):len(names)], ls.GetName())),
+			Node:     "leaf",
+			Breaking: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	children := append([]string(nil), cs.GetChildren()...)
+	sort.Strings(children)
+	for _, c := range children {
+		cnames := append(names[:len(names):len(names)], c)
+		se, err := d.to.Get(ctx, cnames)
+		if err != nil {
+			return err
+		}
+		err = d.requiredLeaves(ctx, cnames, se.GetContainer())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *schemaDiffer) diffContainer(p string, fc, tc *sdcpb.ContainerSchema) error {
+	node := containerNode(tc)
+	changed := func(attr, from, to string, breaking bool) error {
+		if from == to {
+			return nil
+		}
+		return d.emit(&api.SchemaDiff{
+			Kind:      api.DiffChanged,
+			Path:      p,
+			Node:      node,
+			Attribute: attr,
+			From:      from,
+			To:        to,
+			Breaking:  breaking,
+		})
+	}
+	err := changed("keys", keyNames(fc), keyNames(tc), true)
+	if err != nil {
+		return err
+	}
+	err = changed("config", configValue(fc.GetIsState()), configValue(tc.GetIsState()), tc.GetIsState())
+	if err != nil {
+		return err
+	}
+	err = changed("presence", strconv.FormatBool(fc.GetIsPresence()), strconv.FormatBool(tc.GetIsPresence()), false)
+	if err != nil {
+		return err
+	}
+	err = changed("ordered-by", orderedBy(fc.GetIsUserOrdered()), orderedBy(tc.GetIsUserOrdered()), false)
+	if err != nil {
+		return err
+	}
+	return d.diffElements(node, p, fc.GetMinElements(), fc.GetMaxElements(), tc.GetMinElements(), tc.GetMaxElements())
+}
+
+// diffElements reports the min and max elements changes, a zero max is unbounded.
+func (d *schemaDiffer) diffElements(node, p string, fmin, fmax, tmin, tmax uint64) error {
+	if fmin != tmin {
+		err := d.emit(&api.SchemaDiff{
+			Kind:      api.DiffChanged,
+			Path:      p,
+			Node:      node,
+			Attribute: "min-elements",
+			From:      strconv.FormatUint(fmin, 10),
+			To:        strconv.FormatUint(tmin, 10),
+			Breaking:  tmin > fmin,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if fmax != tmax {
+		err := d.emit(&api.SchemaDiff{
+			Kind:      api.DiffChanged,
+			Path:      p,
+			Node:      node,
+			Attribute: "max-elements",
+			From:      maxElements(fmax),
+			To:        maxElements(tmax),
+			Breaking:  tmax != 0 && (fmax == 0 || tmax < fmax),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffLeaves compares the leaves and leaf-lists of the containers found at names,
+// list keys included.
+func (d *schemaDiffer) diffLeaves(names []string, fc, tc *sdcpb.ContainerSchema) error {
+	fLeaves, tLeaves := leavesByName(fc), leavesByName(tc)
+	fLists, tLists := leafListsByName(fc), leafListsByName(tc)
+	all := sortedUnion(keysOf(fLeaves), keysOf(tLeaves), keysOf(fLists), keysOf(tLists))
+	for _, n := range all {
+		p := nodePath(append(names[:len(names):len(names)], n))
+		fl, tl := fLeaves[n], tLeaves[n]
+		fll, tll := fLists[n], tLists[n]
+		var err error
+		switch {
+		case fl != nil && tl != nil:
+			err = d.diffLeaf(p, fl, tl)
+		case fll != nil && tll != nil:
+			err = d.diffLeafList(p, fll, tll)
+		default:
+			// added, removed or turned from a leaf to a leaf-list
+			if fl != nil || fll != nil {
+				err = d.emit(&api.SchemaDiff{
+					Kind:     api.DiffRemoved,
+					Path:     p,
+					Node:     leafNode(fl != nil),
+					Breaking: true,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if tl != nil || tll != nil {
+				sd := &api.SchemaDiff{
+					Kind: api.DiffAdded,
+					Path: p,
+					Node: leafNode(tl != nil),
+				}
+				if tl.GetIsMandatory() && !tl.GetIsState() {
+					sd.Kind = api.DiffMandatoryAdded
+					sd.Breaking = true
+				}
+				err = d.emit(sd)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func leavesByName(cs *sdcpb.ContainerSchema) map[string]*sdcpb.LeafSchema {
+	m := make(map[string]*sdcpb.LeafSchema, len(cs.GetKeys())+len(cs.GetFields()))
+	for _, ls := range cs.GetKeys() {
+		m[ls.GetName()] = ls
+	}
+	for _, ls := range cs.GetFields() {
+		m[ls.GetName()] = ls
+	}
+	return m
+}
+
+func leafListsByName(cs *sdcpb.ContainerSchema) map[string]*sdcpb.LeafListSchema {
+	m := make(map[string]*sdcpb.LeafListSchema, len(cs.GetLeaflists()))
+	for _, lls := range cs.GetLeaflists() {
+		m[lls.GetName()] = lls
+	}
+	return m
+}
+
+func (d *schemaDiffer) diffLeaf(p string, fl, tl *sdcpb.LeafSchema) error {
+	err := d.diffType(p, "leaf", fl.GetType(), tl.GetType())
+	if err != nil {
+		return err
+	}
+	if !fl.GetIsMandatory() && tl.GetIsMandatory() && !tl.GetIsState() {
+		err = d.emit(&api.SchemaDiff{
+			Kind:     api.DiffMandatoryAdded,
+			Path:     p,
+			Node:     "leaf",
+			Breaking: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return d.diffAttributes(p, "leaf", []leafAttribute{
+		{"config", configValue(fl.GetIsState()), configValue(tl.GetIsState()), tl.GetIsState()},
+		{"default", fl.GetDefault(), tl.GetDefault(), false},
+		{"units", fl.GetUnits(), tl.GetUnits(), false},
+	})
+}
+
+func (d *schemaDiffer) diffLeafList(p string, fll, tll *sdcpb.LeafListSchema) error {
+	err := d.diffType(p, "leaf-list", fll.GetType(), tll.GetType())
+	if err != nil {
+		return err
+	}
+	err = d.diffAttributes(p, "leaf-list", []leafAttribute{
+		{"config", configValue(fll.GetIsState()), configValue(tll.GetIsState()), tll.GetIsState()},
+		{"default", strings.Join(fll.GetDefaults(), ","), strings.Join(tll.GetDefaults(), ","), false},
+		{"units", fll.GetUnits(), tll.GetUnits(), false},
+		{"ordered-by", orderedBy(fll.GetIsUserOrdered()), orderedBy(tll.GetIsUserOrdered()), false},
+	})
+	if err != nil {
+		return err
+	}
+	return d.diffElements("leaf-list", p, fll.GetMinElements(), fll.GetMaxElements(), tll.GetMinElements(), tll.GetMaxElements())
+}
+
+type leafAttribute struct {
+	name     string
+	from, to string
+	breaking bool
+}
+
+func (d *schemaDiffer) diffAttributes(p, node string, attrs []leafAttribute) error {
+	for _, a := range attrs {
+		if a.from == a.to {
+			continue
+		}
+		err := d.emit(&api.SchemaDiff{
+			Kind:      api.DiffChanged,
+			Path:      p,
+			Node:      node,
+			Attribute: a.name,
+			From:      a.from,
+			To:        a.to,
+			Breaking:  a.breaking,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffType reports a change of type, the enum values added or removed are reported one by one.
+func (d *schemaDiffer) diffType(p, node string, ft, tt *sdcpb.SchemaLeafType) error {
+	if from, to := typeString(ft), typeString(tt); from != to {
+		err := d.emit(&api.SchemaDiff{
+			Kind:     api.DiffTypeChanged,
+			Path:     p,
+			Node:     node,
+			From:     from,
+			To:       to,
+			Breaking: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fe, te := enumValues(ft, nil), enumValues(tt, nil)
+	for _, v := range sortedUnion(keysOf(fe), keysOf(te)) {
+		var err error
+		switch {
+		case !te[v]:
+			err = d.emit(&api.SchemaDiff{Kind: api.DiffEnumRemoved, Path: p, Node: node, From: v, Breaking: true})
+		case !fe[v]:
+			err = d.emit(&api.SchemaDiff{Kind: api.DiffEnumAdded, Path: p, Node: node, To: v})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeString describes t along with its restrictions, the enum values excluded.
+func typeString(t *sdcpb.SchemaLeafType) string {
+	if t == nil {
+		return ""
+	}
+	s := t.GetType()
+	if t.GetTypeName() != "" && t.GetTypeName() != t.GetType() {
+		s = t.GetTypeName() + "(" + s + ")"
+	}
+	var rs []string
+	if t.GetRange() != "" {
+		rs = append(rs, "range "+t.GetRange())
+	}
+	if t.GetLength() != "" {
+		rs = append(rs, "length "+t.GetLength())
+	}
+	for _, pt := range t.GetPatterns() {
+		rs = append(rs, "pattern "+pt.GetPattern())
+	}
+	if t.GetLeafref() != "" {
+		rs = append(rs, "path "+t.GetLeafref())
+	}
+	if t.GetType() != "enumeration" && len(t.GetValues()) > 0 {
+		rs = append(rs, "values "+strings.Join(t.GetValues(), ","))
+	}
+	if len(rs) > 0 {
+		s += " {" + strings.Join(rs, "; ") + "}"
+	}
+	if len(t.GetUnionTypes()) > 0 {
+		uts := make([]string, 0, len(t.GetUnionTypes()))
+		for _, ut := range t.GetUnionTypes() {
+			uts = append(uts, typeString(ut))
+		}
+		s += " [" + strings.Join(uts, " | ") + "]"
+	}
+	return s
+}
+
+// enumValues adds the enum values of t and its union members to vs.
+func enumValues(t *sdcpb.SchemaLeafType, vs map[string]bool) map[string]bool {
+	if vs == nil {
+		vs = make(map[string]bool)
+	}
+	if t.GetType() == "enumeration" {
+		for _, v := range t.GetValues() {
+			vs[v] = true
+		}
+	}
+	for _, ut := range t.GetUnionTypes() {
+		enumValues(ut, vs)
+	}
+	return vs
+}
+
+func keysOf[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+// sortedUnion returns the sorted distinct strings of ls.
+func sortedUnion(ls ...[]string) []string {
+	seen := make(map[string]bool)
+	u := make([]string, 0)
+	for _, l := range ls {
+		for _, s := range l {
+			if !seen[s] {
+				seen[s] = true
+				u = append(u, s)
+			}
+		}
+	}
+	sort.Strings(u)
+	return u
+}
+
+func sortedLeaves(ls []*sdcpb.LeafSchema) []*sdcpb.LeafSchema {
+	ls = append([]*sdcpb.LeafSchema(nil), ls...)
+	sort.Slice(ls, func(i, j int) bool {
+		return ls[i].GetName() < ls[j].GetName()
+	})
+	return ls
+}
+
+func containerNode(cs *sdcpb.ContainerSchema) string {
+	if len(cs.GetKeys()) > 0 {
+		return "list"
+	}
+	return "container"
+}
+
+func leafNode(leaf bool) string {
+	if leaf {
+		return "leaf"
+	}
+	return "leaf-list"
+}
+
+func keyNames(cs *sdcpb.ContainerSchema) string {
+	ks := make([]string, 0, len(cs.GetKeys()))
+	for _, k := range cs.GetKeys() {
+		ks = append(ks, k.GetName())
+	}
+	return strings.Join(ks, " ")
+}
+
+func configValue(isState bool) string {
+	return strconv.FormatBool(!isState)
+}
+
+func orderedBy(user bool) string {
+	if user {
+		return "user"
+	}
+	return "system"
+}
+
+func maxElements(n uint64) string {
+	if n == 0 {
+		return "unbounded"
+	}
+	return strconv.FormatUint(n, 10)
+}
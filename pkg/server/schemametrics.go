@@ -0,0 +1,144 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// the GetSchema latency of paths deeper than this is recorded under this depth
+const maxDepthLabel = 16
+
+// schema internals, labelled with the schema the size and latency come from
+var (
+	schemaNodesDesc = prometheus.NewDesc("schema_server_schema_nodes",
+		"Data nodes of a schema by kind: container, list, leaf or leaf-list",
+		[]string{"schema", "kind"}, nil)
+	schemaModulesDesc = prometheus.NewDesc("schema_server_schema_modules",
+		"Modules of a schema",
+		[]string{"schema"}, nil)
+	schemaMaxDepthDesc = prometheus.NewDesc("schema_server_schema_max_depth",
+		"Levels of data nodes of a schema below its modules",
+		[]string{"schema"}, nil)
+	schemaParseDesc = prometheus.NewDesc("schema_server_schema_parse_duration_seconds",
+		"Time it took to parse a schema when it was loaded",
+		[]string{"schema"}, nil)
+	schemaMemoryDesc = prometheus.NewDesc("schema_server_schema_memory_bytes",
+		"Estimate of the memory taken by the tree of a schema",
+		[]string{"schema"}, nil)
+	schemaCacheHitsDesc = prometheus.NewDesc("schema_server_schema_cache_hits_total",
+		"Lookups of a schema answered from the persistent store cache",
+		[]string{"schema"}, nil)
+	schemaCacheMissesDesc = prometheus.NewDesc("schema_server_schema_cache_misses_total",
+		"Lookups of a schema missing the persistent store cache",
+		[]string{"schema"}, nil)
+)
+
+// schemaCacheMetrics is implemented by the stores with a cache.
+type schemaCacheMetrics interface {
+	SchemaCacheMetrics(sck store.SchemaKey) (hits, misses uint64, ok bool)
+}
+
+// schemaCollector exposes the sizes and the GetSchema latency of each stored schema.
+type schemaCollector struct {
+	store store.Store
+	// GetSchema latency by schema and requested path depth
+	getSchema *prometheus.HistogramVec
+}
+
+func newSchemaCollector(s store.Store) *schemaCollector {
+	return &schemaCollector{
+		store: s,
+		getSchema: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "schema_server_get_schema_duration_seconds",
+			Help: "GetSchema latency by schema and depth of the requested path",
+		}, []string{"schema", "depth"}),
+	}
+}
+
+// observeGetSchema records the latency of a GetSchema of path p of the stored schema sc.
+// It is a no-op on a nil collector.
+func (c *schemaCollector) observeGetSchema(sc *sdcpb.Schema, p *sdcpb.Path, start time.Time) {
+	if c == nil {
+		return
+	}
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+	depth := len(p.GetElem())
+	if depth > maxDepthLabel {
+		depth = maxDepthLabel
+	}
+	c.getSchema.WithLabelValues(sck.String(), strconv.Itoa(depth)).Observe(time.Since(start).Seconds())
+}
+
+// forget drops the latency of schema sck once it is removed.
+// It is a no-op on a nil collector.
+func (c *schemaCollector) forget(sck store.SchemaKey) {
+	if c == nil {
+		return
+	}
+	c.getSchema.DeletePartialMatch(prometheus.Labels{"schema": sck.String()})
+}
+
+func (c *schemaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- schemaNodesDesc
+	ch <- schemaModulesDesc
+	ch <- schemaMaxDepthDesc
+	ch <- schemaParseDesc
+	ch <- schemaMemoryDesc
+	ch <- schemaCacheHitsDesc
+	ch <- schemaCacheMissesDesc
+	c.getSchema.Describe(ch)
+}
+
+func (c *schemaCollector) Collect(ch chan<- prometheus.Metric) {
+	c.getSchema.Collect(ch)
+	ctx := context.Background()
+	ls, err := c.store.ListSchema(ctx, &sdcpb.ListSchemaRequest{})
+	if err != nil {
+		log.Warnf("failed to list the schemas for their metrics: %v", err)
+		return
+	}
+	cm, _ := c.store.(schemaCacheMetrics)
+	for _, sc := range ls.GetSchema() {
+		sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+		name := sck.String()
+		if cm != nil {
+			if hits, misses, ok := cm.SchemaCacheMetrics(sck); ok {
+				ch <- prometheus.MustNewConstMetric(schemaCacheHitsDesc, prometheus.CounterValue, float64(hits), name)
+				ch <- prometheus.MustNewConstMetric(schemaCacheMissesDesc, prometheus.CounterValue, float64(misses), name)
+			}
+		}
+		st, err := c.store.GetSchemaStats(ctx, sck)
+		if err != nil || st == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(schemaNodesDesc, prometheus.GaugeValue, float64(st.Containers), name, "container")
+		ch <- prometheus.MustNewConstMetric(schemaNodesDesc, prometheus.GaugeValue, float64(st.Lists), name, "list")
+		ch <- prometheus.MustNewConstMetric(schemaNodesDesc, prometheus.GaugeValue, float64(st.Leaves), name, "leaf")
+		ch <- prometheus.MustNewConstMetric(schemaNodesDesc, prometheus.GaugeValue, float64(st.LeafLists), name, "leaf-list")
+		ch <- prometheus.MustNewConstMetric(schemaModulesDesc, prometheus.GaugeValue, float64(st.Modules), name)
+		ch <- prometheus.MustNewConstMetric(schemaMaxDepthDesc, prometheus.GaugeValue, float64(st.MaxDepth), name)
+		ch <- prometheus.MustNewConstMetric(schemaParseDesc, prometheus.GaugeValue, st.ParseDuration.Seconds(), name)
+		ch <- prometheus.MustNewConstMetric(schemaMemoryDesc, prometheus.GaugeValue, float64(st.MemoryEstimate), name)
+	}
+}
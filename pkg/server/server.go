@@ -16,6 +16,8 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -30,13 +32,20 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	log "github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	_ "google.golang.org/grpc/encoding/gzip" // Install the gzip compressor
+	"google.golang.org/grpc/keepalive"
 
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/authn"
+	"github.com/sdcio/schema-server/pkg/authz"
 	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
 	"github.com/sdcio/schema-server/pkg/schema"
 	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/cachestore"
 	"github.com/sdcio/schema-server/pkg/store/memstore"
 	"github.com/sdcio/schema-server/pkg/store/persiststore"
 )
@@ -49,13 +58,66 @@ type Server struct {
 	schemaStore store.Store
 
 	srv *grpc.Server
+	// set once serving, handed over by Takeover
+	listener net.Listener
 	sdcpb.UnimplementedSchemaServerServer
+	api.UnimplementedSchemaServerExtServer
+
+	buildInfo BuildInfo
+	// nil if the request journal is disabled
+	journal *journal
+	// nil if the RPCs are not authorized
+	authorizer authz.Authorizer
+	// nil if the RPCs are not authenticated
+	authenticator authn.Authenticator
 
 	router *mux.Router
-	reg    *prometheus.Registry
+	// set when the HTTP endpoints share the gRPC port
+	httpSrv   *http.Server
+	tlsConfig *tls.Config
+	reg       *prometheus.Registry
+	// nil if metrics are disabled
+	rpcDuration *prometheus.HistogramVec
+	// nil if metrics are disabled
+	load *loadCollector
+	// nil if metrics are disabled
+	schemaMetrics *schemaCollector
+	// schema snapshots ExportSchemaChanges diffs from
+	snapshots snapshots
+	// fingerprints of the schemas the REST responses are validated with
+	fingerprints fingerprints
+	// schemas loaded from the config file, see ReloadConfig
+	configured configuredSchemas
+	// nil if the uploaded schemas are not persisted
+	uploads *uploadRegistry
+	// nil if the uploads have no quotas
+	quotas *uploadQuotas
+	// staged, deprecated and archived schemas
+	lifecycle lifecycle
+	canaries  canaries
+	// wraps the store, schemaStore serves the pinned schemas from it
+	pins *pinnedStore
+	// WatchSchemaChanges subscribers
+	watchers watchers
+	// nil if the schemas are not exported to shared memory
+	shm *shmExporter
+	// nil if tracing is disabled
+	tracing *sdktrace.TracerProvider
+	// chained unary interceptors, the REST requests go through them too
+	unaryInterceptor grpc.UnaryServerInterceptor
+	// names of the legacy services the SchemaServer is also registered as
+	legacyServices map[string]struct{}
 }
 
 func NewServer(c *config.Config) (*Server, error) {
+	// plugin rules are registered before the schemas are linted
+	if err := lint.LoadPlugins(c.SchemaStore.LintPlugins); err != nil {
+		return nil, err
+	}
+	// as the resolvers of the sources the schemas are fetched from
+	if err := schema.LoadSourcePlugins(c.SchemaStore.SourcePlugins); err != nil {
+		return nil, err
+	}
 	ctx, cancel := context.WithCancel(context.TODO())
 	var s = &Server{
 		config: c,
@@ -63,6 +125,14 @@ func NewServer(c *config.Config) (*Server, error) {
 		router: mux.NewRouter(),
 		reg:    prometheus.NewRegistry(),
 	}
+	// set up first for the parsing of the configured schemas to be traced
+	if c.Tracing != nil {
+		var err error
+		s.tracing, err = newTracerProvider(ctx, c.Tracing)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	switch c.SchemaStore.Type {
 	case config.StoreTypePersistent:
@@ -73,9 +143,19 @@ func NewServer(c *config.Config) (*Server, error) {
 		}
 	case config.StoreTypeMemory:
 		s.schemaStore = memstore.New()
+		if c.SchemaStore.CacheDirectory != "" {
+			cs, err := cachestore.New(ctx, c.SchemaStore.CacheDirectory)
+			if err != nil {
+				log.Warnf("schema cache %s: %v: not using it", c.SchemaStore.CacheDirectory, err)
+			} else {
+				s.schemaStore = cs
+			}
+		}
 	default:
 		return nil, fmt.Errorf("unknown schema store type %q", c.SchemaStore.Type)
 	}
+	s.pins = newPinnedStore(s.schemaStore)
+	s.schemaStore = s.pins
 	ls, err := s.schemaStore.ListSchema(ctx, &sdcpb.ListSchemaRequest{})
 	if err != nil {
 		return nil, err
@@ -87,42 +167,88 @@ func NewServer(c *config.Config) (*Server, error) {
 	opts := []grpc.ServerOption{
 		grpc.MaxRecvMsgSize(c.GRPCServer.MaxRecvMsgSize),
 	}
+	if ka := c.GRPCServer.Keepalive; ka != nil {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             ka.MinTime,
+			PermitWithoutStream: ka.PermitWithoutStream,
+		}))
+	}
 
+	if c.Prometheus != nil {
+		s.rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "schema_server_rpc_duration_seconds",
+			Help: "RPC latency, exemplars carry the request and trace IDs",
+		}, []string{"method", "code"})
+		s.reg.MustRegister(s.rpcDuration)
+	}
+	if c.Prometheus != nil || c.GRPCServer.ResponseSizeWarning > 0 {
+		ps := newPayloadSizes(s.schemaStore, s.canonicalMethod, c.Prometheus != nil, c.GRPCServer.ResponseSizeWarning)
+		if c.Prometheus != nil {
+			s.reg.MustRegister(ps)
+		}
+		opts = append(opts, grpc.StatsHandler(ps))
+	}
+	if c.GRPCServer.Journal != nil {
+		s.journal = newJournal(c.GRPCServer.Journal.Size)
+	}
+	var grpcMetrics *grpc_prometheus.ServerMetrics
 	if c.Prometheus != nil {
 		grpcClientMetrics := grpc_prometheus.NewClientMetrics()
 		s.reg.MustRegister(grpcClientMetrics)
 
-		// add gRPC server interceptors for the Schema/Data server
-		grpcMetrics := grpc_prometheus.NewServerMetrics()
-		opts = append(opts,
-			grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor()),
-		)
-		unaryInterceptors := []grpc.UnaryServerInterceptor{
-			func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-				ctx, cfn := context.WithTimeout(ctx, c.GRPCServer.RPCTimeout)
-				defer cfn()
-				return handler(ctx, req)
-			},
-		}
-		unaryInterceptors = append(unaryInterceptors, grpcMetrics.UnaryServerInterceptor())
-		opts = append(opts, grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)))
+		// gRPC server metrics of the Schema/Data server
+		grpcMetrics = grpc_prometheus.NewServerMetrics()
 		s.reg.MustRegister(grpcMetrics)
+		s.load = newLoadCollector(s.pins.Store)
+		s.reg.MustRegister(s.load)
+		s.schemaMetrics = newSchemaCollector(s.pins.Store)
+		s.reg.MustRegister(s.schemaMetrics)
+	}
+	if c.GRPCServer.Authentication != nil {
+		s.authenticator, err = authn.New(ctx, c.GRPCServer.Authentication)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.GRPCServer.Authorization != nil {
+		s.authorizer, err = authz.New(ctx, c.GRPCServer.Authorization)
+		if err != nil {
+			return nil, err
+		}
 	}
+	// by default the tracing and the attribution come first for them to be seen by all interceptors,
+	// authn, authz, canary and lifecycle last for denied requests to be journaled and counted.
+	// the authenticator and the authorizer can be set after the server is created.
+	unaryInterceptors, streamInterceptors := s.interceptors(c, grpcMetrics)
+	s.unaryInterceptor = s.canonicalUnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...))
+	opts = append(opts,
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.canonicalStreamInterceptor(grpc_middleware.ChainStreamServer(streamInterceptors...))),
+	)
 
 	if c.GRPCServer.TLS != nil {
 		tlsCfg, err := c.GRPCServer.TLS.NewConfig(ctx)
 		if err != nil {
 			return nil, err
 		}
-		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+		if c.Prometheus != nil && c.Prometheus.GRPCPort {
+			// TLS is terminated by the multiplexing HTTP server
+			s.tlsConfig = tlsCfg
+		} else {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+		}
 	}
 
 	s.srv = grpc.NewServer(opts...)
 	// parse schemas
 	log.Infof("%d schema(s) configured...", len(c.SchemaStore.Schemas))
+	owned := s.ownedSchemas(c.SchemaStore.Schemas)
+	s.configured.schemas = make(map[store.SchemaKey][]byte, len(owned))
+	s.configured.limits = make(map[store.SchemaKey]*config.SchemaLimits)
+	s.configured.setFile(c.SchemaStore)
 	wg := new(sync.WaitGroup)
-	wg.Add(len(c.SchemaStore.Schemas))
-	for _, sCfg := range c.SchemaStore.Schemas {
+	wg.Add(len(owned))
+	for _, sCfg := range owned {
 		go func(sCfg *config.SchemaConfig) {
 			defer wg.Done()
 			sck := store.SchemaKey{
@@ -130,37 +256,156 @@ func NewServer(c *config.Config) (*Server, error) {
 				Vendor:  sCfg.Vendor,
 				Version: sCfg.Version,
 			}
-			if s.schemaStore.HasSchema(sck) {
-				log.Infof("schema %s already exists in the store: not reloading it...", sck)
-				return
-			}
-			sc, err := schema.NewSchema(sCfg)
+			// encoded before parsing expands the files
+			b, err := json.Marshal(sCfg)
 			if err != nil {
-				log.Errorf("schema %s parsing failed: %v", sCfg.Name, err)
+				log.Errorf("schema %s: %v", sck, err)
 				return
 			}
-			now := time.Now()
-			err = s.schemaStore.AddSchema(sc)
-			if err != nil {
-				log.Errorf("failed to add schema %s: %v", sc.UniqueName(""), err)
+			if err := s.loadSchema(ctx, sck, sCfg); err != nil {
+				log.Errorf("schema %s: %v", sck, err)
 				return
 			}
-			log.Infof("schema %s saved in %s", sc.UniqueName(""), time.Since(now))
+			s.configured.set(sck, b, sCfg.Limits)
 		}(sCfg)
 	}
 	wg.Wait()
+	if c.GRPCServer.SchemaServer != nil && c.GRPCServer.SchemaServer.Quotas != nil {
+		s.quotas = newUploadQuotas(c.GRPCServer.SchemaServer.Quotas)
+		if c.Prometheus != nil {
+			s.reg.MustRegister(s.quotas)
+		}
+	}
+	if c.GRPCServer.SchemaServer != nil && c.GRPCServer.SchemaServer.PersistUploads {
+		s.uploads = newUploadRegistry(c.GRPCServer.SchemaServer.SchemasDirectory)
+		s.loadUploads(ctx)
+	}
+	if shm := c.SchemaStore.SharedMemory; shm != nil {
+		s.shm, err = newShmExporter(ctx, shm, s.schemaStore)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.shm.sync(); err != nil {
+			return nil, err
+		}
+	}
+	s.logStatsSummary(ctx)
+	go s.refreshSchemas(ctx)
 	// register Schema server gRPC Methods
 	sdcpb.RegisterSchemaServerServer(s.srv, s)
+	s.registerLegacyServices(c.GRPCServer.LegacyServiceNames)
+	api.RegisterSchemaServerExtServer(s.srv, s)
 	return s, nil
 }
 
+// loadSchema parses sCfg and adds the schema to the store, unless the store
+// has it already parsed from the same sources.
+// If parsing fails the stored schema, if any, is kept and served.
+func (s *Server) loadSchema(ctx context.Context, sck store.SchemaKey, sCfg *config.SchemaConfig) error {
+	stored := s.schemaStore.HasSchema(sck)
+	if stored && s.storedSchemaCurrent(ctx, sck, sCfg) {
+		log.Infof("schema %s already exists in the store with the same sources: not reloading it...", sck)
+		return nil
+	}
+	if !stored && s.warmSchema(ctx, sck, sCfg) {
+		return nil
+	}
+	sc, err := schema.NewSchemaContext(ctx, sCfg)
+	if err != nil {
+		if stored {
+			log.Errorf("schema %s parsing failed: %v", sck, err)
+			log.Warnf("schema %s: serving the stored schema", sck)
+			return nil
+		}
+		return fmt.Errorf("parsing failed: %v", err)
+	}
+	now := time.Now()
+	if stored {
+		err = s.schemaStore.ReplaceSchema(sc)
+		if err != nil {
+			log.Errorf("schema %s: failed to replace the stored schema: %v", sck, err)
+			log.Warnf("schema %s: serving the stored schema", sck)
+			return nil
+		}
+	} else {
+		err = s.schemaStore.AddSchema(sc)
+		if err != nil {
+			return fmt.Errorf("failed to add schema: %v", err)
+		}
+	}
+	log.Infof("schema %s saved in %s", sc.UniqueName(""), time.Since(now))
+	return nil
+}
+
+// warmStore is implemented by the stores serving a cached copy of
+// a schema while it is parsed, see package cachestore.
+type warmStore interface {
+	Warm(ctx context.Context, sck store.SchemaKey, digest string) bool
+}
+
+// warmSchema serves schema sck from the cache of the store if it has it parsed
+// from the same sources as sCfg, and adds it to the store once parsed in the background.
+func (s *Server) warmSchema(ctx context.Context, sck store.SchemaKey, sCfg *config.SchemaConfig) bool {
+	ws, ok := s.pins.Store.(warmStore)
+	if !ok {
+		return false
+	}
+	digest, err := schema.SourcesDigestContext(ctx, sCfg)
+	if err != nil || !ws.Warm(ctx, sck, digest) {
+		return false
+	}
+	log.Infof("schema %s served from the schema cache while it is parsed", sck)
+	// the schema is parsed after the load returns
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		now := time.Now()
+		sc, err := schema.NewSchemaContext(ctx, sCfg)
+		if err != nil {
+			log.Errorf("schema %s parsing failed: %v", sck, err)
+			log.Warnf("schema %s: serving the cached schema", sck)
+			return
+		}
+		if err = s.schemaStore.ReplaceSchema(sc); err != nil {
+			log.Errorf("schema %s: failed to replace the cached schema: %v", sck, err)
+			return
+		}
+		log.Infof("schema %s parsed in %s, served from memory", sc.UniqueName(""), time.Since(now))
+	}()
+	return true
+}
+
+// storedSchemaCurrent reports whether the stored schema sck was parsed
+// from the same source files as the ones sCfg points to.
+func (s *Server) storedSchemaCurrent(ctx context.Context, sck store.SchemaKey, sCfg *config.SchemaConfig) bool {
+	stored, err := s.schemaStore.GetSchemaSourcesDigest(ctx, sck)
+	if err != nil || stored == "" {
+		log.Infof("schema %s: unknown sources digest of the stored schema", sck)
+		return false
+	}
+	digest, err := schema.SourcesDigestContext(ctx, sCfg)
+	if err != nil {
+		log.Warnf("schema %s: %v", sck, err)
+		return false
+	}
+	if digest != stored {
+		log.Infof("schema %s: sources changed since the schema was stored", sck)
+		return false
+	}
+	return true
+}
+
 func (s *Server) Serve(ctx context.Context) error {
-	l, err := net.Listen("tcp", s.config.GRPCServer.Address)
+	l, err := s.listen(ctx)
 	if err != nil {
 		return err
 	}
-	log.Infof("running server on %s", s.config.GRPCServer.Address)
+	s.listener = l
+	log.Infof("running server on %s", l.Addr())
+	notifyTakeoverReady()
 	if s.config.Prometheus != nil {
+		if s.config.Prometheus.GRPCPort {
+			return s.serveMultiplexed(ctx, l)
+		}
 		go s.ServeHTTP()
 	}
 	err = s.srv.Serve(l)
@@ -171,25 +416,95 @@ func (s *Server) Serve(ctx context.Context) error {
 	return nil
 }
 
-func (s *Server) ServeHTTP() {
-	s.router.Handle("/metrics", promhttp.HandlerFor(s.reg, promhttp.HandlerOpts{}))
+// httpHandler registers the HTTP endpoints and returns the handler serving them.
+func (s *Server) httpHandler() http.Handler {
+	s.router.Handle("/metrics", promhttp.HandlerFor(s.reg, promhttp.HandlerOpts{
+		// exemplars are only exposed in the OpenMetrics format
+		EnableOpenMetrics: true,
+	}))
 	s.reg.MustRegister(collectors.NewGoCollector())
 	s.reg.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	if s.config.Prometheus.Pprof {
+		s.registerPprof()
+	}
+	if s.config.Prometheus.UI {
+		s.registerUI()
+	}
+	if s.config.Prometheus.REST {
+		s.registerREST()
+	}
+	if s.config.Prometheus.BasicAuth != nil {
+		s.router.Use(s.basicAuthMiddleware)
+	}
+	return s.allowedClientsMiddleware(s.router)
+}
+
+func (s *Server) ServeHTTP() {
 	srv := &http.Server{
 		Addr:         s.config.Prometheus.Address,
-		Handler:      s.router,
+		Handler:      s.httpHandler(),
 		ReadTimeout:  time.Minute,
 		WriteTimeout: time.Minute,
 	}
-	err := srv.ListenAndServe()
+	var err error
+	if s.config.Prometheus.TLS != nil {
+		srv.TLSConfig, err = s.config.Prometheus.TLS.NewConfig(context.TODO())
+		if err != nil {
+			log.Errorf("HTTP server TLS config failed: %v", err)
+			return
+		}
+		if srv.TLSConfig.RootCAs != nil {
+			srv.TLSConfig.ClientCAs = srv.TLSConfig.RootCAs
+			srv.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		// the certificate is provided by the TLS config
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
 	if err != nil {
 		log.Errorf("HTTP server stopped: %v", err)
 	}
 }
 
+// Drain stops the server once the in-flight RPCs completed: it stops accepting
+// connections and requests, the RPCs still running past the drain-timeout are cancelled.
+func (s *Server) Drain() {
+	timeout := s.config.GRPCServer.DrainTimeout
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if s.httpSrv != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			_ = s.httpSrv.Shutdown(ctx)
+			return
+		}
+		s.srv.GracefulStop()
+	}()
+	select {
+	case <-done:
+		log.Infof("server drained")
+	case <-time.After(timeout):
+		log.Warnf("RPCs still running after %s, cancelling them", timeout)
+	}
+	s.Stop()
+}
+
 func (s *Server) Stop() {
+	if s.httpSrv != nil {
+		s.httpSrv.Close()
+	}
 	s.srv.Stop()
 	s.cfn()
+	if s.tracing != nil {
+		// flush the spans of the last RPCs
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.Tracing.Timeout)
+		defer cancel()
+		if err := s.tracing.Shutdown(ctx); err != nil {
+			log.Warnf("failed to flush the traces: %v", err)
+		}
+	}
 }
 
 func (s *Server) SchemaStore() store.Store {
@@ -0,0 +1,88 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// ownedSchemas returns the schema configs of cfgs owned by this instance,
+// all of them if sharding is not configured.
+func (s *Server) ownedSchemas(cfgs []*config.SchemaConfig) []*config.SchemaConfig {
+	sh := s.config.Sharding
+	if sh == nil {
+		return cfgs
+	}
+	owned := make([]*config.SchemaConfig, 0, len(cfgs))
+	for _, sCfg := range cfgs {
+		if !sh.Owned(sCfg.Name, sCfg.Vendor) {
+			log.Debugf("schema %s@%s@%s is owned by %s", sCfg.Name, sCfg.Vendor, sCfg.Version, sh.Owner(sCfg.Name, sCfg.Vendor).Name)
+			continue
+		}
+		owned = append(owned, sCfg)
+	}
+	log.Infof("sharding: %s owns %d of the %d configured schema(s)", sh.Self, len(owned), len(cfgs))
+	return owned
+}
+
+// routeShard fails the requests for a schema owned by another instance with
+// a ReasonSchemaNotOwned error carrying the address of the owner.
+func (s *Server) routeShard(req interface{}) error {
+	sc, _ := requestInfo(req)
+	if sc == nil {
+		return nil
+	}
+	owner := s.config.Sharding.Owner(sc.GetName(), sc.GetVendor())
+	if owner.Name == s.config.Sharding.Self {
+		return nil
+	}
+	return reasonError(codes.FailedPrecondition, api.ReasonSchemaNotOwned,
+		map[string]string{"name": sc.GetName(), "vendor": sc.GetVendor(), "owner": owner.Name, "address": owner.Address},
+		"schema %s@%s is served by %s at %s", sc.GetName(), sc.GetVendor(), owner.Name, owner.Address)
+}
+
+func (s *Server) shardingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.routeShard(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) shardingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &shardingStream{ServerStream: ss, s: s})
+}
+
+// shardingStream routes the first message of a stream, the one carrying the schema.
+type shardingStream struct {
+	grpc.ServerStream
+	s      *Server
+	routed bool
+}
+
+func (ss *shardingStream) RecvMsg(m interface{}) error {
+	err := ss.ServerStream.RecvMsg(m)
+	if err != nil || ss.routed {
+		return err
+	}
+	ss.routed = true
+	return ss.s.routeShard(m)
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/shmstore"
+)
+
+// shmExporter writes a shmstore snapshot of each schema of the store
+// to a directory, they are rewritten when a schema changes.
+type shmExporter struct {
+	ctx   context.Context
+	dir   string
+	store store.Store
+	// group the snapshots are readable by, -1 for the owner only
+	gid int
+
+	m sync.Mutex
+	// schemas being exported, true if changed since the export started
+	running map[store.SchemaKey]bool
+}
+
+func newShmExporter(ctx context.Context, cfg *config.SharedMemoryConfig, s store.Store) (*shmExporter, error) {
+	gid := -1
+	if cfg.Group != "" {
+		var err error
+		gid, err = lookupGID(cfg.Group)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+		return nil, err
+	}
+	return &shmExporter{
+		ctx:     ctx,
+		dir:     cfg.Directory,
+		store:   s,
+		gid:     gid,
+		running: make(map[store.SchemaKey]bool),
+	}, nil
+}
+
+// lookupGID returns the id of the group with name or id g.
+func lookupGID(g string) (int, error) {
+	grp, err := user.LookupGroup(g)
+	if err != nil {
+		grp, err = user.LookupGroupId(g)
+	}
+	if err != nil {
+		return -1, fmt.Errorf("shared memory group %q: %v", g, err)
+	}
+	return strconv.Atoi(grp.Gid)
+}
+
+// sync exports the schemas of the store and removes the snapshots
+// of the schemas it does not have, e.g. left by a previous run.
+func (x *shmExporter) sync() error {
+	ls, err := x.store.ListSchema(x.ctx, &sdcpb.ListSchemaRequest{})
+	if err != nil {
+		return err
+	}
+	files := make(map[string]bool, len(ls.GetSchema()))
+	for _, sc := range ls.GetSchema() {
+		files[shmstore.FileName(sc.GetName(), sc.GetVendor(), sc.GetVersion())] = true
+		x.schedule(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+	}
+	des, err := os.ReadDir(x.dir)
+	if err != nil {
+		return err
+	}
+	for _, de := range des {
+		if strings.HasSuffix(de.Name(), shmstore.FileExt) && !files[de.Name()] {
+			_ = os.Remove(filepath.Join(x.dir, de.Name()))
+		}
+	}
+	return nil
+}
+
+// schedule exports schema sck in the background, or removes its snapshot
+// if the store does not have it. A schema changing while it is exported
+// is exported again once done.
+func (x *shmExporter) schedule(sck store.SchemaKey) {
+	x.m.Lock()
+	defer x.m.Unlock()
+	if _, ok := x.running[sck]; ok {
+		x.running[sck] = true
+		return
+	}
+	x.running[sck] = false
+	go x.run(sck)
+}
+
+func (x *shmExporter) run(sck store.SchemaKey) {
+	for {
+		if err := x.export(sck); err != nil {
+			log.Errorf("schema %s: shared memory snapshot: %v", sck, err)
+		}
+		x.m.Lock()
+		if !x.running[sck] {
+			delete(x.running, sck)
+			x.m.Unlock()
+			return
+		}
+		x.running[sck] = false
+		x.m.Unlock()
+	}
+}
+
+func (x *shmExporter) export(sck store.SchemaKey) error {
+	fn := filepath.Join(x.dir, shmstore.FileName(sck.Name, sck.Vendor, sck.Version))
+	if !x.store.HasSchema(sck) {
+		err := os.Remove(fn)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	now := time.Now()
+	w := &shmWalker{r: store.NewResolver(x.store, schemaOf(sck))}
+	root, err := w.r.Get(x.ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, m := range root.GetContainer().GetChildren() {
+		if err := w.walk(x.ctx, []string{m}); err != nil {
+			return err
+		}
+	}
+	f, err := os.CreateTemp(x.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	// the snapshots of all the tenants are in the file: readable by
+	// the owner only, or by the co-located processes of the group
+	err = f.Chmod(0o600)
+	if err == nil && x.gid >= 0 {
+		err = f.Chown(-1, x.gid)
+		if err == nil {
+			err = f.Chmod(0o640)
+		}
+	}
+	if err == nil {
+		err = shmstore.Write(f, uint64(now.UnixNano()), w.entries)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	// the mapped snapshots stay valid while replaced
+	err = os.Rename(f.Name(), fn)
+	if err != nil {
+		return err
+	}
+	log.Infof("schema %s: shared memory snapshot of %d elements written to %s in %s", sck, len(w.entries), fn, time.Since(now))
+	return nil
+}
+
+// shmWalker collects the snapshot entries of the data nodes below a module.
+type shmWalker struct {
+	r       *store.Resolver
+	seen    map[string]bool
+	entries []*shmstore.Entry
+}
+
+func (w *shmWalker) walk(ctx context.Context, names []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	se, err := w.r.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	cs := se.GetContainer()
+	if len(names) > 1 {
+		// the modules are not data nodes
+		w.add(names, se)
+	}
+	for _, k := range cs.GetKeys() {
+		w.add(append(names, k.GetName()), &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: k}})
+	}
+	for _, f := range cs.GetFields() {
+		w.add(append(names, f.GetName()), &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: f}})
+	}
+	for _, ll := range cs.GetLeaflists() {
+		w.add(append(names, ll.GetName()), &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Leaflist{Leaflist: ll}})
+	}
+	for _, c := range cs.GetChildren() {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		if err := w.walk(ctx, append(cnames, c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *shmWalker) add(names []string, se *sdcpb.SchemaElem) {
+	var sb strings.Builder
+	for _, name := range names[1:] {
+		if idx := strings.Index(name, ":"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		sb.WriteByte('/')
+		sb.WriteString(name)
+	}
+	p := sb.String()
+	if w.seen == nil {
+		w.seen = make(map[string]bool)
+	}
+	// a top level node defined by several modules is served from the first one
+	if w.seen[p] {
+		return
+	}
+	w.seen[p] = true
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(se)
+	if err != nil {
+		log.Warnf("shared memory snapshot: %s: %v", p, err)
+		return
+	}
+	w.entries = append(w.entries, &shmstore.Entry{Path: p, Elem: b})
+}
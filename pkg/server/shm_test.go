@@ -0,0 +1,76 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/shmstore"
+)
+
+func TestShmExporter_export(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no unix file modes")
+	}
+	st := testStore(t, limitsModule)
+	sck := store.SchemaKey{Name: "t", Vendor: "v", Version: "1"}
+	tests := []struct {
+		name  string
+		group string
+		mode  os.FileMode
+	}{
+		// the snapshots hold the schemas of all the tenants
+		{name: "owner only", mode: 0o600},
+		{name: "group", group: strconv.Itoa(os.Getgid()), mode: 0o640},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.SharedMemoryConfig{Directory: t.TempDir(), Group: tt.group}
+			x, err := newShmExporter(context.Background(), cfg, st)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := x.export(sck); err != nil {
+				t.Fatal(err)
+			}
+			fn := filepath.Join(cfg.Directory, shmstore.FileName(sck.Name, sck.Vendor, sck.Version))
+			fi, err := os.Stat(fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fi.Mode().Perm() != tt.mode {
+				t.Errorf("snapshot mode = %v, want %v", fi.Mode().Perm(), tt.mode)
+			}
+			s, err := shmstore.Open(fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			if s.Len() == 0 {
+				t.Error("snapshot without entries")
+			}
+		})
+	}
+	if _, err := newShmExporter(context.Background(), &config.SharedMemoryConfig{Directory: t.TempDir(), Group: "no-such-group-schema-server"}, st); err == nil {
+		t.Error("newShmExporter() error = nil, want an unknown group error")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/schema"
+)
+
+// size of the source file parts sent by GetModuleSource
+const sourceChunkSize = 64 << 10
+
+func (s *Server) GetModuleSource(req *api.GetModuleSourceRequest, stream api.SchemaServerExt_GetModuleSourceServer) error {
+	log.Debugf("received GetModuleSource: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return err
+	}
+	if req.Module == "" {
+		return status.Error(codes.InvalidArgument, "missing module name")
+	}
+	ctx := stream.Context()
+	ok, err := s.hasModule(ctx, sck, req.Module)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return status.Errorf(codes.NotFound, "schema %v has no module %s", sck, req.Module)
+	}
+	cfg, err := s.schemaStore.GetSchemaConfig(ctx, sck)
+	if err != nil {
+		return err
+	}
+	sm, err := schema.ModuleSource(cfg, req.Module, req.Revision)
+	if err != nil {
+		var nfe *schema.ModuleNotFoundError
+		if errors.As(err, &nfe) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		return status.Errorf(codes.Internal, "failed to read the module sources: %v", err)
+	}
+	f, err := os.Open(sm.File)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open the module source: %v", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to open the module source: %v", err)
+	}
+	rsp := &api.GetModuleSourceResponse{
+		Module:   sm.Name,
+		Revision: sm.Revision,
+		File:     filepath.Base(sm.File),
+		Size:     fi.Size(),
+	}
+	h := sha256.New()
+	buf := make([]byte, sourceChunkSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			rsp.Contents = buf[:n]
+			h.Write(rsp.Contents)
+			if err := stream.Send(rsp); err != nil {
+				return err
+			}
+			rsp = new(api.GetModuleSourceResponse)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read the module source: %v", err)
+		}
+	}
+	rsp.Digest = hex.EncodeToString(h.Sum(nil))
+	return stream.Send(rsp)
+}
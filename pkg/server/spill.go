@@ -0,0 +1,304 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// spillBuffer holds the messages of a response built before being streamed.
+// The messages are kept in memory up to the spill threshold, once it is exceeded
+// they are all moved to a temporary file the remaining messages are appended to.
+type spillBuffer struct {
+	threshold int
+	max       int64
+	dir       string
+	mem       *bytes.Buffer
+	// bytes added
+	size int64
+	// set once spilled
+	f *os.File
+	w *bufio.Writer
+}
+
+func newSpillBuffer(cfg *config.StreamingConfig) *spillBuffer {
+	return &spillBuffer{
+		threshold: cfg.SpillThreshold,
+		max:       cfg.MaxSpillSize,
+		dir:       cfg.SpillDir,
+		mem:       new(bytes.Buffer),
+	}
+}
+
+// spillTooLarge is the error of a response exceeding max bytes.
+func spillTooLarge(max int64) error {
+	return reasonError(codes.ResourceExhausted, api.ReasonResponseTooLarge,
+		map[string]string{"limit": strconv.FormatInt(max, 10)},
+		"the response exceeds %d bytes", max)
+}
+
+// add appends message m to the buffer.
+func (b *spillBuffer) add(m proto.Message) error {
+	if b.f == nil && b.mem.Len() > b.threshold {
+		if err := b.spill(); err != nil {
+			return err
+		}
+	}
+	var w io.Writer = b.mem
+	if b.f != nil {
+		w = b.w
+	}
+	n, err := protodelim.MarshalTo(w, m)
+	if err != nil {
+		return err
+	}
+	b.size += int64(n)
+	if b.max > 0 && b.size > b.max {
+		return spillTooLarge(b.max)
+	}
+	return nil
+}
+
+func (b *spillBuffer) spill() error {
+	f, err := os.CreateTemp(b.dir, "schema-server-spill-*")
+	if err != nil {
+		return err
+	}
+	log.Debugf("response exceeds %d bytes, spilling to %s", b.threshold, f.Name())
+	b.f = f
+	b.w = bufio.NewWriter(f)
+	_, err = b.w.Write(b.mem.Bytes())
+	b.mem = nil
+	return err
+}
+
+// each calls fn for each message in the order they were added,
+// the messages are read into a message returned by newMsg.
+func (b *spillBuffer) each(newMsg func() proto.Message, fn func(proto.Message) error) error {
+	var r *bufio.Reader
+	if b.f == nil {
+		r = bufio.NewReader(bytes.NewReader(b.mem.Bytes()))
+	} else {
+		if err := b.w.Flush(); err != nil {
+			return err
+		}
+		if _, err := b.f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		r = bufio.NewReader(b.f)
+	}
+	for {
+		m := newMsg()
+		err := protodelim.UnmarshalFrom(r, m)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+}
+
+// close removes the spill file if any.
+func (b *spillBuffer) close() {
+	if b.f == nil {
+		return
+	}
+	b.f.Close()
+	if err := os.Remove(b.f.Name()); err != nil {
+		log.Warnf("failed to remove spill file %s: %v", b.f.Name(), err)
+	}
+}
+
+// xpathSorter sorts the xpaths of a response built before being streamed.
+// The xpaths are kept in memory up to the spill threshold, beyond it they are
+// sorted and moved to a temporary file, one per run, the runs are merged when read.
+type xpathSorter struct {
+	threshold int
+	max       int64
+	dir       string
+	mem       []string
+	memSize   int
+	// bytes added
+	size int64
+	runs []*os.File
+}
+
+func newXPathSorter(cfg *config.StreamingConfig) *xpathSorter {
+	return &xpathSorter{
+		threshold: cfg.SpillThreshold,
+		max:       cfg.MaxSpillSize,
+		dir:       cfg.SpillDir,
+	}
+}
+
+// add appends xpath xp to the sorter.
+func (s *xpathSorter) add(xp string) error {
+	s.mem = append(s.mem, xp)
+	s.memSize += len(xp)
+	s.size += int64(len(xp))
+	if s.max > 0 && s.size > s.max {
+		return spillTooLarge(s.max)
+	}
+	if s.memSize > s.threshold {
+		return s.spill()
+	}
+	return nil
+}
+
+// spill writes the sorted xpaths held in memory to a new run, each prefixed with its length.
+func (s *xpathSorter) spill() error {
+	f, err := os.CreateTemp(s.dir, "schema-server-spill-*")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f)
+	log.Debugf("response exceeds %d bytes, spilling %d xpaths to %s", s.threshold, len(s.mem), f.Name())
+	sort.Strings(s.mem)
+	w := bufio.NewWriter(f)
+	var l [binary.MaxVarintLen64]byte
+	for _, xp := range s.mem {
+		n := binary.PutUvarint(l[:], uint64(len(xp)))
+		if _, err := w.Write(l[:n]); err != nil {
+			return err
+		}
+		if _, err := w.WriteString(xp); err != nil {
+			return err
+		}
+	}
+	s.mem = s.mem[:0]
+	s.memSize = 0
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
+
+// each calls fn for each xpath in sorted order.
+func (s *xpathSorter) each(fn func(string) error) error {
+	sort.Strings(s.mem)
+	runs := make(xpathRuns, 0, len(s.runs)+1)
+	// the xpaths left in memory are a run too
+	all := []*xpathRun{{mem: s.mem}}
+	for _, f := range s.runs {
+		all = append(all, &xpathRun{r: bufio.NewReader(f)})
+	}
+	for _, r := range all {
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			runs = append(runs, r)
+		}
+	}
+	heap.Init(&runs)
+	for runs.Len() > 0 {
+		r := runs[0]
+		if err := fn(r.cur); err != nil {
+			return err
+		}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(&runs, 0)
+		} else {
+			heap.Pop(&runs)
+		}
+	}
+	return nil
+}
+
+// close removes the run files.
+func (s *xpathSorter) close() {
+	for _, f := range s.runs {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			log.Warnf("failed to remove spill file %s: %v", f.Name(), err)
+		}
+	}
+}
+
+// xpathRun is a sorted run of xpaths read from a spill file, or from memory if r is nil.
+type xpathRun struct {
+	r   *bufio.Reader
+	mem []string
+	// current xpath
+	cur string
+}
+
+// next moves to the next xpath of the run, it reports whether there is one.
+func (r *xpathRun) next() (bool, error) {
+	if r.r == nil {
+		if len(r.mem) == 0 {
+			return false, nil
+		}
+		r.cur, r.mem = r.mem[0], r.mem[1:]
+		return true, nil
+	}
+	l, err := binary.ReadUvarint(r.r)
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return false, err
+	}
+	r.cur = string(b)
+	return true, nil
+}
+
+// xpathRuns is a heap of runs ordered by their current xpath.
+type xpathRuns []*xpathRun
+
+func (h xpathRuns) Len() int           { return len(h) }
+func (h xpathRuns) Less(i, j int) bool { return h[i].cur < h[j].cur }
+func (h xpathRuns) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *xpathRuns) Push(x interface{}) {
+	*h = append(*h, x.(*xpathRun))
+}
+
+func (h *xpathRuns) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+func Test_xpathSorter(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		max       int64
+		count     int
+		code      codes.Code
+	}{
+		{name: "in memory", threshold: 1 << 20, count: 100},
+		{name: "spilled runs", threshold: 4096, count: 1000},
+		{name: "empty", threshold: 64},
+		{name: "too large", threshold: 4096, max: 8192, count: 1000, code: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := newXPathSorter(&config.StreamingConfig{SpillThreshold: tt.threshold, MaxSpillSize: tt.max, SpillDir: t.TempDir()})
+			defer xs.close()
+			want := make([]string, 0, tt.count)
+			var err error
+			for _, i := range rand.Perm(tt.count) {
+				xp := fmt.Sprintf("interface[name=ethernet-1/%d]/description", i)
+				want = append(want, xp)
+				if err = xs.add(xp); err != nil {
+					break
+				}
+			}
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if err != nil {
+				return
+			}
+			sort.Strings(want)
+			got := make([]string, 0, tt.count)
+			if err := xs.each(func(xp string) error {
+				got = append(got, xp)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d xpaths, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("xpath %d: got %q, want %q", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_spillBuffer(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		max       int64
+		count     int
+		code      codes.Code
+	}{
+		{name: "in memory", threshold: 1 << 20, count: 100},
+		{name: "spilled", threshold: 64, count: 100},
+		{name: "too large", threshold: 64, max: 256, count: 100, code: codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newSpillBuffer(&config.StreamingConfig{SpillThreshold: tt.threshold, MaxSpillSize: tt.max, SpillDir: t.TempDir()})
+			defer b.close()
+			var err error
+			for i := 0; i < tt.count && err == nil; i++ {
+				err = b.add(wrapperspb.String(fmt.Sprintf("line %d", i)))
+			}
+			if status.Code(err) != tt.code {
+				t.Fatalf("got error %v, want code %v", err, tt.code)
+			}
+			if err != nil {
+				return
+			}
+			i := 0
+			err = b.each(func() proto.Message { return new(wrapperspb.StringValue) }, func(m proto.Message) error {
+				if want := fmt.Sprintf("line %d", i); m.(*wrapperspb.StringValue).GetValue() != want {
+					t.Errorf("message %d: got %q, want %q", i, m.(*wrapperspb.StringValue).GetValue(), want)
+				}
+				i++
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i != tt.count {
+				t.Errorf("got %d messages, want %d", i, tt.count)
+			}
+		})
+	}
+}
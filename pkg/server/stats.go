@@ -0,0 +1,124 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) GetSchemaStats(ctx context.Context, req *api.GetSchemaStatsRequest) (*api.GetSchemaStatsResponse, error) {
+	log.Debugf("received GetSchemaStats: %v", req)
+	var scs []*sdcpb.Schema
+	if req.Schema != nil {
+		if _, err := s.checkSchema(req.Schema); err != nil {
+			return nil, err
+		}
+		scs = []*sdcpb.Schema{req.Schema}
+	} else {
+		ls, err := s.schemaStore.ListSchema(ctx, &sdcpb.ListSchemaRequest{})
+		if err != nil {
+			return nil, err
+		}
+		scs = ls.GetSchema()
+	}
+	rsp := &api.GetSchemaStatsResponse{Schemas: make([]*api.SchemaStats, 0, len(scs))}
+	for _, sc := range scs {
+		sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+		st, err := s.schemaStore.GetSchemaStats(ctx, sck)
+		if err != nil {
+			return nil, err
+		}
+		rsp.Schemas = append(rsp.Schemas, schemaStats(sc, st))
+	}
+	sort.Slice(rsp.Schemas, func(i, j int) bool {
+		return schemaLess(rsp.Schemas[i].Schema, rsp.Schemas[j].Schema)
+	})
+	rsp.Total = totalStats(rsp.Schemas)
+	return rsp, nil
+}
+
+func schemaStats(sc *sdcpb.Schema, st *schema.Stats) *api.SchemaStats {
+	if st == nil {
+		return &api.SchemaStats{Schema: sc, Unknown: true}
+	}
+	return &api.SchemaStats{
+		Schema:         sc,
+		Modules:        st.Modules,
+		Containers:     st.Containers,
+		Lists:          st.Lists,
+		Leaves:         st.Leaves,
+		LeafLists:      st.LeafLists,
+		ConfigNodes:    st.ConfigNodes,
+		StateNodes:     st.StateNodes,
+		MaxDepth:       st.MaxDepth,
+		ParseDuration:  st.ParseDuration,
+		MemoryEstimate: st.MemoryEstimate,
+	}
+}
+
+// totalStats sums the known stats of sts, the max depth being the deepest one.
+func totalStats(sts []*api.SchemaStats) *api.SchemaStats {
+	total := &api.SchemaStats{}
+	for _, st := range sts {
+		if st.Unknown {
+			continue
+		}
+		total.Modules += st.Modules
+		total.Containers += st.Containers
+		total.Lists += st.Lists
+		total.Leaves += st.Leaves
+		total.LeafLists += st.LeafLists
+		total.ConfigNodes += st.ConfigNodes
+		total.StateNodes += st.StateNodes
+		if st.MaxDepth > total.MaxDepth {
+			total.MaxDepth = st.MaxDepth
+		}
+		total.ParseDuration += st.ParseDuration
+		total.MemoryEstimate += st.MemoryEstimate
+	}
+	return total
+}
+
+// logStatsSummary logs the stats of the loaded schemas and their total.
+func (s *Server) logStatsSummary(ctx context.Context) {
+	rsp, err := s.GetSchemaStats(ctx, &api.GetSchemaStatsRequest{})
+	if err != nil {
+		log.Warnf("failed to get the schema stats: %v", err)
+		return
+	}
+	for _, st := range rsp.Schemas {
+		sck := store.SchemaKey{Name: st.Schema.GetName(), Vendor: st.Schema.GetVendor(), Version: st.Schema.GetVersion()}
+		if st.Unknown {
+			log.Infof("schema %s: unknown stats", sck)
+			continue
+		}
+		logStats("schema "+sck.String(), st)
+	}
+	logStats("total", rsp.Total)
+}
+
+func logStats(name string, st *api.SchemaStats) {
+	log.Infof("%s: %d module(s), %d container(s), %d list(s), %d leaves, %d leaf-list(s), %d config and %d state node(s), max depth %d, parsed in %s, ~%d KiB",
+		name, st.Modules, st.Containers, st.Lists, st.Leaves, st.LeafLists, st.ConfigNodes, st.StateNodes,
+		st.MaxDepth, st.ParseDuration, st.MemoryEstimate/1024)
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/protobuf/proto"
+)
+
+// max number of batches queued for a stream, on top of the bytes limit
+const maxQueuedBatches = 64
+
+type responseBatch struct {
+	rsps []*sdcpb.GetSchemaResponse
+	// bytes held in the in-flight bucket
+	weight int64
+	// encoded size of the responses
+	size int64
+}
+
+// streamSchemaElems sends the schema elements read from ch using send.
+// The elements are read in batches and queued as long as the queued bytes stay
+// under the configured max in-flight bytes: once the bucket is full, reading from
+// the store waits for the client to drain it, a slow client slows the store down
+// instead of growing the server side buffers.
+func (s *Server) streamSchemaElems(ctx context.Context, ch chan *sdcpb.SchemaElem, send func(*sdcpb.GetSchemaResponse) error) error {
+	cfg := s.config.GRPCServer.Streaming
+	bucket := semaphore.NewWeighted(int64(cfg.MaxInFlightBytes))
+	batches := make(chan *responseBatch, maxQueuedBatches)
+
+	rctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// queued bytes not sent when the stream ends
+	var queued atomic.Int64
+	defer func() { s.load.queued(-queued.Load()) }()
+	// the elements are read from the store while the previous ones are sent
+	_, walk := tracer.Start(ctx, "schema.walk")
+	_, serialize := tracer.Start(ctx, "schema.serialize")
+	var messages int
+	var size int64
+	defer func() {
+		serialize.SetAttributes(attribute.Int("messages", messages), attribute.Int64("bytes", size))
+		serialize.End()
+	}()
+	go func() {
+		defer close(batches)
+		var elems int
+		defer func() {
+			walk.SetAttributes(attribute.Int("schema.elements", elems))
+			walk.End()
+		}()
+		// let the store goroutines writing to ch terminate
+		defer func() {
+			go func() {
+				for range ch {
+				}
+			}()
+		}()
+		for {
+			b, more := readBatch(rctx, ch, cfg.BatchSize)
+			elems += len(b.rsps)
+			if len(b.rsps) > 0 {
+				// a batch larger than the bucket goes through alone
+				if b.weight > int64(cfg.MaxInFlightBytes) {
+					b.weight = int64(cfg.MaxInFlightBytes)
+				}
+				if err := bucket.Acquire(rctx, b.weight); err != nil {
+					return
+				}
+				queued.Add(b.weight)
+				s.load.queued(b.weight)
+				select {
+				case <-rctx.Done():
+					return
+				case batches <- b:
+				}
+			}
+			if !more {
+				return
+			}
+		}
+	}()
+
+	for b := range batches {
+		for _, rsp := range b.rsps {
+			if err := send(rsp); err != nil {
+				return err
+			}
+		}
+		messages += len(b.rsps)
+		size += b.size
+		bucket.Release(b.weight)
+		queued.Add(-b.weight)
+		s.load.queued(-b.weight)
+	}
+	return ctx.Err()
+}
+
+// readBatch waits for an element on ch and reads up to size elements already
+// available, more is false once ch is closed or ctx is done.
+func readBatch(ctx context.Context, ch chan *sdcpb.SchemaElem, size int) (*responseBatch, bool) {
+	b := &responseBatch{rsps: make([]*sdcpb.GetSchemaResponse, 0, size)}
+	add := func(sce *sdcpb.SchemaElem) {
+		rsp := &sdcpb.GetSchemaResponse{Schema: sce}
+		b.rsps = append(b.rsps, rsp)
+		b.size += int64(proto.Size(rsp))
+		b.weight = b.size
+	}
+	select {
+	case <-ctx.Done():
+		return b, false
+	case sce, ok := <-ch:
+		if !ok {
+			return b, false
+		}
+		add(sce)
+	}
+	for len(b.rsps) < size {
+		select {
+		case <-ctx.Done():
+			return b, false
+		case sce, ok := <-ch:
+			if !ok {
+				return b, false
+			}
+			add(sce)
+		default:
+			return b, true
+		}
+	}
+	return b, true
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"os"
+)
+
+// max number of files streamed to UploadSchema or ParseModules
+const maxStreamedFiles = 10000
+
+// streamedFiles tracks the files written from a stream of file chunks.
+// Only the file of the last chunk is open: the previous one is closed when
+// the stream switches to another file and reopened if it switches back to it.
+type streamedFiles struct {
+	// files being written, by name
+	pending map[string]struct{}
+	// files created
+	count int
+	// open file
+	name string
+	f    *os.File
+}
+
+func newStreamedFiles() *streamedFiles {
+	return &streamedFiles{pending: make(map[string]struct{})}
+}
+
+// full reports whether file name would exceed the max number of files.
+func (sf *streamedFiles) full(name string) bool {
+	_, ok := sf.pending[name]
+	return !ok && sf.count >= maxStreamedFiles
+}
+
+// open returns file name, created if it is not pending, and reports whether
+// it was created. The file open before is closed.
+func (sf *streamedFiles) open(name string) (*os.File, bool, error) {
+	if sf.f != nil && sf.name == name {
+		return sf.f, false, nil
+	}
+	if err := sf.close(); err != nil {
+		return nil, false, err
+	}
+	var f *os.File
+	var err error
+	_, ok := sf.pending[name]
+	if ok {
+		f, err = os.OpenFile(name, os.O_RDWR|os.O_APPEND, 0)
+	} else {
+		f, err = createFileWithDir(name)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		sf.pending[name] = struct{}{}
+		sf.count++
+	}
+	sf.name, sf.f = name, f
+	return f, !ok, nil
+}
+
+// done closes file name if it is open, it is not pending anymore.
+func (sf *streamedFiles) done(name string) error {
+	delete(sf.pending, name)
+	if sf.name != name {
+		return nil
+	}
+	return sf.close()
+}
+
+// close closes the open file, if any.
+func (sf *streamedFiles) close() error {
+	if sf.f == nil {
+		return nil
+	}
+	err := sf.f.Close()
+	sf.name, sf.f = "", nil
+	return err
+}
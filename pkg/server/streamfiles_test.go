@@ -0,0 +1,85 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_streamedFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.yang"), filepath.Join(dir, "sub", "b.yang")
+	// chunks written in order, by file
+	chunks := []struct {
+		name    string
+		data    string
+		created bool
+	}{
+		{name: a, data: "a1", created: true},
+		{name: a, data: "a2"},
+		{name: b, data: "b1", created: true},
+		{name: a, data: "a3"},
+		{name: b, data: "b2"},
+	}
+	sf := newStreamedFiles()
+	defer sf.close()
+	var prev *os.File
+	for i, c := range chunks {
+		f, created, err := sf.open(c.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created != c.created {
+			t.Errorf("chunk %d: got created %v, want %v", i, created, c.created)
+		}
+		if prev != nil && prev != f {
+			// switching files closes the previous one
+			if _, err := prev.Write(nil); err == nil {
+				t.Errorf("chunk %d: previous file still open", i)
+			}
+		}
+		if _, err := f.WriteString(c.data); err != nil {
+			t.Fatal(err)
+		}
+		prev = f
+	}
+	if err := sf.done(a); err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.pending) != 1 {
+		t.Errorf("got %d pending files, want 1", len(sf.pending))
+	}
+	if err := sf.close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{a: "a1a2a3", b: "b1b2"} {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+	sf.count = maxStreamedFiles
+	if sf.full(b) {
+		t.Error("a pending file does not count twice")
+	}
+	if !sf.full(filepath.Join(dir, "c.yang")) {
+		t.Errorf("expecting more than %d files to be refused", maxStreamedFiles)
+	}
+}
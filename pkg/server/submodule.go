@@ -0,0 +1,48 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+func (s *Server) ListSubmodules(ctx context.Context, req *api.ListSubmodulesRequest) (*api.ListSubmodulesResponse, error) {
+	log.Debugf("received ListSubmodules: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	sms, err := s.schemaStore.GetSchemaSubmodules(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.ListSubmodulesResponse{
+		Submodules: make([]*api.Submodule, 0, len(sms)),
+	}
+	for _, sm := range sms {
+		rsp.Submodules = append(rsp.Submodules, &api.Submodule{
+			Name:       sm.Name,
+			Revision:   sm.Revision,
+			BelongsTo:  sm.BelongsTo,
+			Includes:   sm.Includes,
+			IncludedBy: sm.IncludedBy,
+		})
+	}
+	return rsp, nil
+}
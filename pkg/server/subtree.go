@@ -0,0 +1,132 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/api"
+)
+
+func (s *Server) GetSchemaSubtree(req *api.GetSchemaSubtreeRequest, stream api.SchemaServerExt_GetSchemaSubtreeServer) error {
+	log.Debugf("received GetSchemaSubtree: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	err := s.checkSubtreeLimits(ctx, req.Schema, req.Path)
+	if err != nil {
+		return err
+	}
+	w := &subtreeWalker{
+		s:         s,
+		req:       req,
+		batchSize: s.config.GRPCServer.Streaming.BatchSize,
+		maxBytes:  s.config.GRPCServer.Streaming.MaxInFlightBytes,
+		send:      stream.Send,
+	}
+	// the queued bytes of a response not sent
+	defer func() { s.load.queued(-int64(w.bytes)) }()
+	err = w.walk(ctx, elemNames(req.Path), 0)
+	if err == nil && len(w.rsp.Elements) > 0 {
+		err = w.flush()
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
+
+// subtreeWalker sends the elements of a subtree as it walks it depth first.
+// Only the elements of the walked branch and the next response are held:
+// the elements are read from the store without being cached and the walk
+// waits for each response to be sent, a slow client slows the walk down.
+type subtreeWalker struct {
+	s   *Server
+	req *api.GetSchemaSubtreeRequest
+	// elements and bytes per response
+	batchSize int
+	maxBytes  int
+	rsp       api.GetSchemaSubtreeResponse
+	bytes     int
+	send      func(*api.GetSchemaSubtreeResponse) error
+}
+
+func (w *subtreeWalker) walk(ctx context.Context, names []string, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(names))}
+	for i, name := range names {
+		// only the first name can carry a module prefix
+		if idx := strings.Index(name, ":"); i > 0 && idx >= 0 {
+			name = name[idx+1:]
+		}
+		p.Elem = append(p.Elem, &sdcpb.PathElem{Name: name})
+	}
+	rsp, err := w.s.schemaStore.GetSchema(ctx, &sdcpb.GetSchemaRequest{
+		Schema:          w.req.Schema,
+		Path:            p,
+		WithDescription: w.req.WithDescription,
+	})
+	if err != nil {
+		return err
+	}
+	se := rsp.GetSchema()
+	// the root only has the modules as children
+	if len(names) > 0 {
+		if err := w.add(nodePath(names), se); err != nil {
+			return err
+		}
+	}
+	if w.req.MaxDepth > 0 && depth >= w.req.MaxDepth {
+		return nil
+	}
+	for _, c := range se.GetContainer().GetChildren() {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		if err := w.walk(ctx, append(cnames, c), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add queues an element, a response is sent every batchSize elements
+// or once its elements are larger than maxBytes.
+func (w *subtreeWalker) add(p string, se *sdcpb.SchemaElem) error {
+	w.rsp.Elements = append(w.rsp.Elements, &api.SubtreeElem{Path: p, Schema: &api.SchemaElem{SchemaElem: se}})
+	n := proto.Size(se)
+	w.bytes += n
+	w.s.load.queued(int64(n))
+	if len(w.rsp.Elements) < w.batchSize && w.bytes < w.maxBytes {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *subtreeWalker) flush() error {
+	err := w.send(&w.rsp)
+	w.s.load.queued(-int64(w.bytes))
+	w.rsp.Elements = nil
+	w.bytes = 0
+	return err
+}
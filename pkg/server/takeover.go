@@ -0,0 +1,90 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// Takeover starts a new process of the server executable, with the same arguments,
+// and hands it the listener. It returns once the new process serves,
+// the server is then to be drained, see Drain.
+// The clients keep connecting to the same socket while the new process loads its schemas.
+//
+// It is refused with the persistent store: its directory is locked by this process
+// until it exits, the new one would fail to open it. Closing the store first would
+// leave this process serving without its schemas while the new one loads them.
+func (s *Server) Takeover(ctx context.Context) error {
+	if s.config.SchemaStore.Type == config.StoreTypePersistent {
+		return errors.New("the persistent store can not be handed over to a new process")
+	}
+	tl, ok := s.listener.(*net.TCPListener)
+	if !ok {
+		return errors.New("the server is not serving on a TCP listener")
+	}
+	lf, err := tl.File()
+	if err != nil {
+		return err
+	}
+	defer lf.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	exe, err := os.Executable()
+	if err != nil {
+		w.Close()
+		return err
+	}
+	cmd := exec.CommandContext(ctx, exe, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// fds 3 and 4
+	cmd.ExtraFiles = []*os.File{lf, w}
+	cmd.Env = append(os.Environ(),
+		listenFdsEnv+"=1",
+		takeoverReadyEnv+"="+strconv.Itoa(listenFdsStart+1))
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return err
+	}
+	pid := cmd.Process.Pid
+	go func() {
+		err := cmd.Wait()
+		log.Infof("process %d taking over the listener exited: %v", pid, err)
+	}()
+	log.Infof("process %d is taking over the listener", pid)
+	// the pipe is closed without being written to if the process exits before serving
+	b := make([]byte, 1)
+	if _, err := r.Read(b); err != nil {
+		return fmt.Errorf("process %d did not take over the listener", pid)
+	}
+	log.Infof("process %d took over the listener", pid)
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// request metadata set to the target profile the requested schema is answered from,
+// the schema versioned <version>+<target profile>.
+const targetProfileHeader = "x-schema-target-profile"
+
+func targetProfile(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return firstValue(md, targetProfileHeader)
+}
+
+// routeTargetProfile points the schema of req at the one derived for
+// the target profile the client asked for, if any.
+// The client must be allowed to query the derived schema.
+func (s *Server) routeTargetProfile(ctx context.Context, method string, req interface{}) error {
+	tp := targetProfile(ctx)
+	if tp == "" {
+		return nil
+	}
+	sc, _ := requestInfo(req)
+	if sc == nil {
+		return nil
+	}
+	v := config.TargetProfileVersion(sc.GetVersion(), tp)
+	if !s.schemaStore.HasSchema(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: v}) {
+		return status.Errorf(codes.NotFound, "schema %s@%s@%s has no target profile %q",
+			sc.GetName(), sc.GetVendor(), sc.GetVersion(), tp)
+	}
+	psc := proto.Clone(sc).(*sdcpb.Schema)
+	psc.Version = v
+	if !setRequestSchema(req, psc) {
+		return nil
+	}
+	if err := s.authorize(ctx, method, req); err != nil {
+		return err
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(servedVersionHeader, v))
+	return nil
+}
+
+func (s *Server) targetProfileUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.routeTargetProfile(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) targetProfileStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &targetProfileStream{ServerStream: ss, s: s, method: info.FullMethod})
+}
+
+// targetProfileStream routes the first message of a stream, the one carrying the schema.
+type targetProfileStream struct {
+	grpc.ServerStream
+	s      *Server
+	method string
+	routed bool
+}
+
+func (ts *targetProfileStream) RecvMsg(m interface{}) error {
+	err := ts.ServerStream.RecvMsg(m)
+	if err != nil || ts.routed {
+		return err
+	}
+	ts.routed = true
+	return ts.s.routeTargetProfile(ts.Context(), ts.method, m)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) GetPathTemplate(ctx context.Context, req *api.GetPathTemplateRequest) (*api.GetPathTemplateResponse, error) {
+	log.Debugf("received GetPathTemplate: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Path.GetElem()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+	r := store.NewResolver(s.schemaStore, req.Schema)
+	t, err := document.ParseTemplate(ctx, r, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &api.GetPathTemplateResponse{
+		Schema: &api.SchemaElem{SchemaElem: t.Schema},
+	}
+	rsp.Placeholders = placeholders(t)
+	if len(req.Values) > 0 {
+		rsp.Rendered, err = t.Render(req.Values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rsp, nil
+}
+
+func (s *Server) GetInstanceTemplate(ctx context.Context, req *api.GetInstanceTemplateRequest) (*api.GetInstanceTemplateResponse, error) {
+	log.Debugf("received GetInstanceTemplate: %v", req)
+	if _, err := s.checkSchema(req.Schema); err != nil {
+		return nil, err
+	}
+	if len(req.Path.GetElem()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+	t, err := document.InstanceTemplate(ctx, store.NewResolver(s.schemaStore, req.Schema), req.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &api.GetInstanceTemplateResponse{
+		Template:     t.XPath(true),
+		PathTemplate: t.XPath(false),
+		Placeholders: placeholders(t),
+	}, nil
+}
+
+// response metadata telling the type of each placeholder of
+// the path of an ExpandPath or ToPath request, as name=type
+const placeholderHeader = "x-schema-placeholder"
+
+// pathPlaceholders validates the placeholders of path p and returns the metadata
+// telling their types, see GetPathTemplate. It is nil if p has no placeholder.
+func (s *Server) pathPlaceholders(ctx context.Context, sc *sdcpb.Schema, p *sdcpb.Path) (metadata.MD, error) {
+	if !document.HasPlaceholder(p) {
+		return nil, nil
+	}
+	t, err := document.ParseTemplate(ctx, store.NewResolver(s.schemaStore, sc), p)
+	if err != nil {
+		return nil, err
+	}
+	md := metadata.MD{}
+	for _, ph := range t.Placeholders {
+		md.Append(placeholderHeader, headerValue(ph.Name+"="+document.TypeString(ph.Type)))
+	}
+	return md, nil
+}
+
+func placeholders(t *document.Template) []*api.Placeholder {
+	phs := make([]*api.Placeholder, 0, len(t.Placeholders))
+	for _, ph := range t.Placeholders {
+		phs = append(phs, &api.Placeholder{
+			Name: ph.Name,
+			Elem: ph.Elem,
+			Key:  ph.Key,
+			Type: ph.Type,
+		})
+	}
+	return phs
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/config"
+)
+
+// the paths of a request beyond it are counted but not set on its span
+const maxSpanPaths = 16
+
+var tracer = otel.Tracer("github.com/sdcio/schema-server/pkg/server")
+
+// newTracerProvider returns the provider exporting the spans to the collector of c,
+// set as the global one for the schemas parsing to be traced too.
+func newTracerProvider(ctx context.Context, c *config.TracingConfig) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(c.Endpoint),
+		otlptracegrpc.WithTimeout(c.Timeout),
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+	}
+	switch {
+	case c.Insecure:
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	case c.TLS != nil:
+		tlsCfg, err := c.TLS.NewConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// the certificate, if any, authenticates the server to the collector
+		if get := tlsCfg.GetCertificate; get != nil {
+			tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return get(nil)
+			}
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	}
+	// the exporter connects lazily, an unreachable collector does not fail the startup
+	exp, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", c.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp, nil
+}
+
+func tracingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startRPCSpan(ctx, info.FullMethod)
+	defer span.End()
+	setRequestAttributes(span, req)
+	rsp, err := handler(ctx, req)
+	endRPCSpan(span, err)
+	return rsp, err
+}
+
+func tracingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startRPCSpan(ss.Context(), info.FullMethod)
+	defer span.End()
+	err := handler(srv, &tracedStream{ServerStream: ss, ctx: ctx, span: span})
+	endRPCSpan(span, err)
+	return err
+}
+
+// startRPCSpan starts the server span of an RPC, child of the
+// traceparent of the incoming metadata if it has one.
+func startRPCSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	}
+	name := strings.TrimPrefix(method, "/")
+	service, rpc, _ := strings.Cut(name, "/")
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", service),
+			attribute.String("rpc.method", rpc),
+		),
+	)
+}
+
+// setRequestAttributes sets the schema and the paths of req on span.
+func setRequestAttributes(span trace.Span, req interface{}) {
+	sc, paths := requestInfo(req)
+	if sc != nil {
+		span.SetAttributes(
+			attribute.String("schema.name", sc.GetName()),
+			attribute.String("schema.vendor", sc.GetVendor()),
+			attribute.String("schema.version", sc.GetVersion()),
+		)
+	}
+	if len(paths) == 0 {
+		return
+	}
+	if len(paths) > maxSpanPaths {
+		span.SetAttributes(attribute.Int("schema.path_count", len(paths)))
+		paths = paths[:maxSpanPaths]
+	}
+	span.SetAttributes(attribute.StringSlice("schema.paths", paths))
+}
+
+// endRPCSpan sets the gRPC status code of an RPC on its span.
+func endRPCSpan(span trace.Span, err error) {
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(status.Code(err))))
+	endSpan(span, err)
+}
+
+// endSpan marks span as failed if err is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+}
+
+// tracedStream carries the span of the RPC in its context,
+// the attributes of the request are set from its first message.
+type tracedStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	span     trace.Span
+	received bool
+}
+
+func (s *tracedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *tracedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && !s.received {
+		s.received = true
+		setRequestAttributes(s.span, m)
+	}
+	return err
+}
+
+// metadataCarrier reads the trace context propagated in the gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	return firstValue(metadata.MD(c), key)
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,371 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) RenderTree(ctx context.Context, req *api.RenderTreeRequest) (*api.RenderTreeResponse, error) {
+	log.Debugf("received RenderTree: %v", req)
+	_, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if req.MaxDepth < 0 {
+		return nil, status.Error(codes.InvalidArgument, "negative max depth")
+	}
+	// the tree holds the whole subtree in one response
+	err = s.checkSubtreeLimits(ctx, req.Schema, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	t := &treeRenderer{
+		r:        store.NewResolver(s.schemaStore, req.Schema),
+		maxDepth: req.MaxDepth,
+	}
+	root, err := t.r.Get(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	modules := append([]string(nil), root.GetContainer().GetChildren()...)
+	sort.Strings(modules)
+	names := elemNames(req.Path)
+	switch {
+	case len(names) == 0:
+		for i, m := range modules {
+			if i > 0 {
+				t.buf.WriteByte('\n')
+			}
+			if err := t.module(ctx, m); err != nil {
+				return nil, err
+			}
+		}
+	case len(names) == 1 && contains(modules, names[0]):
+		err = t.module(ctx, names[0])
+	default:
+		err = t.path(ctx, names)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &api.RenderTreeResponse{Tree: t.buf.String()}, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// treeRenderer writes the nodes of a schema as pyang -f tree does:
+//
+//	+--<flags> <name><suffix>   <type> {<features>}?
+//
+// with flags rw or ro, suffix ? for an optional leaf or choice, ! for a presence
+// container and * for a list or leaf-list, followed by its keys. The children of
+// a node are indented below it, the keys of a list first, then the other nodes
+// sorted by name, the augmenting ones last, with the members of a choice grouped
+// under (choice) and :(case).
+type treeRenderer struct {
+	r        *store.Resolver
+	maxDepth int
+	// prefix of the module being rendered, the nodes augmenting it
+	// from other modules are rendered with their prefix
+	prefix string
+	buf    strings.Builder
+}
+
+// treeNode is a node line, before its indentation, and its children.
+type treeNode struct {
+	flags string
+	name  string
+	// leaves only
+	typ      string
+	keys     []string
+	features []string
+	// names of a container, nil for a leaf
+	names    []string
+	children []*treeNode
+	// a choice or a case
+	group bool
+}
+
+func (t *treeRenderer) module(ctx context.Context, m string) error {
+	se, err := t.r.Get(ctx, []string{m})
+	if err != nil {
+		return err
+	}
+	t.prefix = se.GetContainer().GetPrefix()
+	t.buf.WriteString("module: " + m + "\n")
+	nodes, err := t.children(ctx, []string{m}, se.GetContainer())
+	if err != nil {
+		return err
+	}
+	return t.write(ctx, "  ", nodes, 1)
+}
+
+// path renders the ancestors of the node at names, each with the next one
+// as its only child, followed by the subtree of the node.
+func (t *treeRenderer) path(ctx context.Context, names []string) error {
+	se, err := t.r.Get(ctx, names)
+	if err != nil {
+		return err
+	}
+	m, err := t.r.Module(ctx, se)
+	if err != nil {
+		return err
+	}
+	mse, err := t.r.Get(ctx, []string{m})
+	if err != nil {
+		return err
+	}
+	t.prefix = mse.GetContainer().GetPrefix()
+	t.buf.WriteString("module: " + m + "\n")
+	indent := "  "
+	for i := range names[:len(names)-1] {
+		ase, err := t.r.Get(ctx, names[:i+1])
+		if err != nil {
+			return err
+		}
+		n := t.node(names[:i+1], ase)
+		// the ancestor is where the path goes on
+		n.names = nil
+		t.line(indent, n, 0)
+		indent += "   "
+	}
+	return t.write(ctx, indent, []*treeNode{t.node(names, se)}, 0)
+}
+
+// node returns the node of se, names being its path.
+func (t *treeRenderer) node(names []string, se *sdcpb.SchemaElem) *treeNode {
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		return t.container(names, se.Container)
+	case *sdcpb.SchemaElem_Field:
+		return t.leaf(se.Field, false)
+	case *sdcpb.SchemaElem_Leaflist:
+		return t.leafList(se.Leaflist)
+	}
+	return &treeNode{name: names[len(names)-1]}
+}
+
+func (t *treeRenderer) container(names []string, cs *sdcpb.ContainerSchema) *treeNode {
+	n := &treeNode{
+		flags:    treeFlags(cs.GetIsState()),
+		name:     t.name(cs.GetPrefix(), cs.GetName()),
+		features: cs.GetIfFeature(),
+		names:    names,
+	}
+	switch {
+	case len(cs.GetKeys()) > 0:
+		n.name += "*"
+		for _, k := range cs.GetKeys() {
+			n.keys = append(n.keys, k.GetName())
+		}
+	case cs.GetIsPresence():
+		n.name += "!"
+	}
+	return n
+}
+
+func (t *treeRenderer) leaf(ls *sdcpb.LeafSchema, key bool) *treeNode {
+	n := &treeNode{
+		flags:    treeFlags(ls.GetIsState()),
+		name:     t.name(ls.GetPrefix(), ls.GetName()),
+		typ:      treeType(ls.GetType()),
+		features: ls.GetIfFeature(),
+	}
+	if !key && !ls.GetIsMandatory() {
+		n.name += "?"
+	}
+	return n
+}
+
+func (t *treeRenderer) leafList(lls *sdcpb.LeafListSchema) *treeNode {
+	return &treeNode{
+		flags:    treeFlags(lls.GetIsState()),
+		name:     t.name(lls.GetPrefix(), lls.GetName()) + "*",
+		typ:      treeType(lls.GetType()),
+		features: lls.GetIfFeature(),
+	}
+}
+
+func (t *treeRenderer) name(prefix, name string) string {
+	if prefix == "" || prefix == t.prefix {
+		return name
+	}
+	return prefix + ":" + name
+}
+
+func treeFlags(state bool) string {
+	if state {
+		return "ro"
+	}
+	return "rw"
+}
+
+func treeType(lt *sdcpb.SchemaLeafType) string {
+	switch {
+	case lt.GetLeafref() != "":
+		return "-> " + lt.GetLeafref()
+	case lt.GetTypeName() != "":
+		return lt.GetTypeName()
+	}
+	return lt.GetType()
+}
+
+// children returns the child nodes of cs, the ones in a choice grouped under it.
+func (t *treeRenderer) children(ctx context.Context, names []string, cs *sdcpb.ContainerSchema) ([]*treeNode, error) {
+	type member struct {
+		n  *treeNode
+		ci *sdcpb.ChoiceInfo
+	}
+	keys := make([]*treeNode, 0, len(cs.GetKeys()))
+	for _, k := range cs.GetKeys() {
+		keys = append(keys, t.leaf(k, true))
+	}
+	members := make([]member, 0, len(cs.GetFields())+len(cs.GetLeaflists())+len(cs.GetChildren()))
+	for _, f := range cs.GetFields() {
+		members = append(members, member{t.leaf(f, false), f.GetChoiceInfo()})
+	}
+	for _, ll := range cs.GetLeaflists() {
+		members = append(members, member{t.leafList(ll), ll.GetChoiceInfo()})
+	}
+	for _, c := range cs.GetChildren() {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, c)
+		se, err := t.r.Get(ctx, cnames)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member{t.node(cnames, se), se.GetContainer().GetChoiceInfo()})
+	}
+	// as in pyang, the nodes augmenting the module come after its own ones
+	sort.SliceStable(members, func(i, j int) bool {
+		ai, aj := strings.Contains(members[i].n.name, ":"), strings.Contains(members[j].n.name, ":")
+		if ai != aj {
+			return aj
+		}
+		return members[i].n.name < members[j].n.name
+	})
+	nodes := keys
+	choices := make(map[string]*treeNode)
+	cases := make(map[string]*treeNode)
+	for _, m := range members {
+		if m.ci.GetChoice() == "" {
+			nodes = append(nodes, m.n)
+			continue
+		}
+		ch, ok := choices[m.ci.GetChoice()]
+		if !ok {
+			// a choice is listed where its first member would be
+			ch = &treeNode{flags: m.n.flags, name: "(" + m.ci.GetChoice() + ")?", group: true}
+			choices[m.ci.GetChoice()] = ch
+			nodes = append(nodes, ch)
+		}
+		cname := m.ci.GetCase()
+		if cname == "" {
+			cname = strings.TrimRight(m.n.name, "?*!")
+		}
+		key := m.ci.GetChoice() + "/" + cname
+		cas, ok := cases[key]
+		if !ok {
+			cas = &treeNode{name: ":(" + cname + ")", group: true}
+			cases[key] = cas
+			ch.children = append(ch.children, cas)
+		}
+		cas.children = append(cas.children, m.n)
+	}
+	return nodes, nil
+}
+
+// write writes the lines of nodes and their subtrees, each prefixed with indent.
+func (t *treeRenderer) write(ctx context.Context, indent string, nodes []*treeNode, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// the types of sibling leaves are aligned
+	width := 0
+	for _, n := range nodes {
+		if n.typ != "" && len(n.name) > width {
+			width = len(n.name)
+		}
+	}
+	for i, n := range nodes {
+		t.line(indent, n, width)
+		cindent := indent + "|  "
+		if i == len(nodes)-1 {
+			cindent = indent + "   "
+		}
+		children := n.children
+		if n.names != nil {
+			if t.maxDepth > 0 && depth >= t.maxDepth {
+				t.buf.WriteString(cindent + "...\n")
+				continue
+			}
+			se, err := t.r.Get(ctx, n.names)
+			if err != nil {
+				return err
+			}
+			children, err = t.children(ctx, n.names, se.GetContainer())
+			if err != nil {
+				return err
+			}
+		}
+		// choices and cases are not levels of the data tree
+		cdepth := depth + 1
+		if n.group {
+			cdepth = depth
+		}
+		if err := t.write(ctx, cindent, children, cdepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *treeRenderer) line(indent string, n *treeNode, width int) {
+	t.buf.WriteString(indent + "+--")
+	if n.flags != "" {
+		t.buf.WriteString(n.flags + " ")
+	}
+	if n.typ == "" {
+		t.buf.WriteString(n.name)
+	} else {
+		t.buf.WriteString(n.name + strings.Repeat(" ", width-len(n.name)) + "   " + n.typ)
+	}
+	if len(n.keys) > 0 {
+		t.buf.WriteString(" [" + strings.Join(n.keys, " ") + "]")
+	}
+	if len(n.features) > 0 {
+		t.buf.WriteString(" {" + strings.Join(n.features, ",") + "}?")
+	}
+	t.buf.WriteByte('\n')
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const uiPrefix = "/ui/"
+
+//go:embed ui
+var uiFiles embed.FS
+
+// registerUI registers the schema explorer page, it calls the REST endpoints:
+// its requests go through the gRPC interceptors as the other REST ones.
+func (s *Server) registerUI() {
+	files, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		log.Errorf("failed to load UI files: %v", err)
+		return
+	}
+	s.router.Handle("/ui", http.RedirectHandler(uiPrefix, http.StatusMovedPermanently))
+	s.router.PathPrefix(uiPrefix).Handler(http.StripPrefix(uiPrefix, http.FileServer(http.FS(files))))
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// name of the registry file in the schemas directory
+const uploadRegistryFile = "uploads.json"
+
+// uploadRegistry lists the uploaded schemas loaded again on startup,
+// their files are kept in the schemas directory.
+type uploadRegistry struct {
+	m   sync.Mutex
+	dir string
+}
+
+func newUploadRegistry(dir string) *uploadRegistry {
+	return &uploadRegistry{dir: dir}
+}
+
+// uploadEntry is an uploaded schema in the registry.
+type uploadEntry struct {
+	*config.SchemaConfig
+	// client the upload is counted against, see uploadOwner
+	Owner string `json:"owner,omitempty"`
+	// total size of the uploaded files
+	Size int64 `json:"size,omitempty"`
+}
+
+// list returns the registered schemas, their files under the schemas directory.
+func (r *uploadRegistry) list() ([]*uploadEntry, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	cfgs, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	for _, sCfg := range cfgs {
+		for i, f := range sCfg.Files {
+			sCfg.Files[i] = filepath.Join(r.dir, filepath.FromSlash(f))
+		}
+		for i, d := range sCfg.Directories {
+			sCfg.Directories[i] = filepath.Join(r.dir, filepath.FromSlash(d))
+		}
+	}
+	return cfgs, nil
+}
+
+// add registers the schema sCfg uploaded by owner, replacing one with the same key.
+func (r *uploadRegistry) add(sCfg *config.SchemaConfig, owner string, size int64) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	cfgs, err := r.read()
+	if err != nil {
+		return err
+	}
+	cfgs = removeSchemaConfig(cfgs, schemaConfigKey(sCfg))
+	// paths are stored relative to the schemas directory
+	rCfg := &config.SchemaConfig{
+		Name:                sCfg.Name,
+		Vendor:              sCfg.Vendor,
+		Version:             sCfg.Version,
+		Files:               make([]string, 0, len(sCfg.Files)),
+		Directories:         make([]string, 0, len(sCfg.Directories)),
+		Excludes:            sCfg.Excludes,
+		DescriptionLanguage: sCfg.DescriptionLanguage,
+		Features:            sCfg.Features,
+	}
+	for _, fs := range []struct {
+		from []string
+		to   *[]string
+	}{
+		{sCfg.Files, &rCfg.Files},
+		{sCfg.Directories, &rCfg.Directories},
+	} {
+		for _, f := range fs.from {
+			rel, err := filepath.Rel(r.dir, f)
+			if err != nil {
+				return err
+			}
+			*fs.to = append(*fs.to, filepath.ToSlash(rel))
+		}
+	}
+	return r.write(append(cfgs, &uploadEntry{SchemaConfig: rCfg, Owner: owner, Size: size}))
+}
+
+// remove unregisters schema sck, it reports whether it was registered.
+func (r *uploadRegistry) remove(sck store.SchemaKey) (bool, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	cfgs, err := r.read()
+	if err != nil {
+		return false, err
+	}
+	rcfgs := removeSchemaConfig(cfgs, sck)
+	if len(rcfgs) == len(cfgs) {
+		return false, nil
+	}
+	return true, r.write(rcfgs)
+}
+
+func (r *uploadRegistry) read() ([]*uploadEntry, error) {
+	b, err := os.ReadFile(filepath.Join(r.dir, uploadRegistryFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cfgs := make([]*uploadEntry, 0)
+	err = json.Unmarshal(b, &cfgs)
+	if err != nil {
+		return nil, err
+	}
+	for _, sCfg := range cfgs {
+		if sCfg.SchemaConfig == nil {
+			return nil, errors.New("invalid registry entry")
+		}
+	}
+	return cfgs, nil
+}
+
+// write replaces the registry file, through a rename for a crash not to leave it truncated.
+func (r *uploadRegistry) write(cfgs []*uploadEntry) error {
+	b, err := json.MarshalIndent(cfgs, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(r.dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(r.dir, uploadRegistryFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(r.dir, uploadRegistryFile))
+}
+
+func schemaConfigKey(sCfg *config.SchemaConfig) store.SchemaKey {
+	return store.SchemaKey{Name: sCfg.Name, Vendor: sCfg.Vendor, Version: sCfg.Version}
+}
+
+func removeSchemaConfig(cfgs []*uploadEntry, sck store.SchemaKey) []*uploadEntry {
+	rcfgs := make([]*uploadEntry, 0, len(cfgs))
+	for _, sCfg := range cfgs {
+		if schemaConfigKey(sCfg.SchemaConfig) != sck {
+			rcfgs = append(rcfgs, sCfg)
+		}
+	}
+	return rcfgs
+}
+
+// loadUploads loads the registered uploaded schemas,
+// the ones also in the config file are loaded from it.
+func (s *Server) loadUploads(ctx context.Context) {
+	cfgs, err := s.uploads.list()
+	if err != nil {
+		log.Errorf("failed to read the uploaded schemas registry: %v", err)
+		return
+	}
+	log.Infof("%d uploaded schema(s) registered...", len(cfgs))
+	for _, ue := range cfgs {
+		sck := schemaConfigKey(ue.SchemaConfig)
+		if s.inConfigFile(sck) {
+			log.Infof("uploaded schema %s is in the config file: not loading the uploaded one...", sck)
+			continue
+		}
+		if err := s.loadSchema(ctx, sck, ue.SchemaConfig); err != nil {
+			log.Errorf("uploaded schema %s: %v", sck, err)
+			continue
+		}
+		if s.quotas != nil {
+			s.quotas.restore(ue.Owner, sck, ue.Size)
+		}
+	}
+}
+
+func (s *Server) inConfigFile(sck store.SchemaKey) bool {
+	return s.configured.isInFile(sck)
+}
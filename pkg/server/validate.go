@@ -0,0 +1,967 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/document"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+	"github.com/sdcio/schema-server/pkg/value"
+)
+
+const (
+	// max number of errors returned by ValidateDocument
+	maxDocumentErrors = 1000
+	// namespace of the NETCONF config and data elements wrapping an XML document
+	netconfBaseNamespace = "urn:ietf:params:xml:ns:netconf:base:1.0"
+)
+
+func (s *Server) ValidateDocument(ctx context.Context, req *api.ValidateDocumentRequest) (*api.ValidateDocumentResponse, error) {
+	log.Debugf("received ValidateDocument for schema %v at %v", req.Schema, req.Path)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Document) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing document")
+	}
+	switch req.Format {
+	case "", api.DocumentFormatJSON, api.DocumentFormatXML:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown document format %q", req.Format)
+	}
+	v, err := s.newDocumentValidator(ctx, req.Schema, sck, req.Format == api.DocumentFormatXML)
+	if err != nil {
+		return nil, err
+	}
+	names, cs, err := v.rootNode(ctx, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if v.xml {
+		doc, err = v.decodeXML(ctx, req.Document, len(names) > 0)
+	} else {
+		doc, err = decodeJSONObject(req.Document)
+	}
+	if err != nil {
+		v.add(nil, api.DocumentErrorSyntax, "", err.Error())
+		return v.response(), nil
+	}
+	err = v.document(ctx, names, req.Path, cs, doc)
+	if err != nil {
+		return nil, err
+	}
+	return v.response(), nil
+}
+
+func (s *Server) newDocumentValidator(ctx context.Context, sc *sdcpb.Schema, sck store.SchemaKey, xml bool) (*documentValidator, error) {
+	v := &documentValidator{
+		lr:       &leafrefResolver{r: store.NewResolver(s.schemaStore, sc)},
+		xml:      xml,
+		values:   make(map[string]map[string]struct{}),
+		required: make(map[string][]string),
+	}
+	err := v.lr.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.optional, err = s.schemaStore.GetSchemaOptionalLeafrefs(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	as, err := s.schemaStore.GetSchemaAnnotations(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	v.annotations = make(map[string]*schema.Annotation, len(as))
+	for _, a := range as {
+		v.annotations[a.QualifiedName()] = a
+	}
+	return v, nil
+}
+
+// rootNode returns the schema names and the container or list entry of the node
+// at p a document is the content of, nil for the root.
+func (v *documentValidator) rootNode(ctx context.Context, p *sdcpb.Path) ([]string, *sdcpb.ContainerSchema, error) {
+	names, err := v.lr.qualify(ctx, elemNames(p))
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "path %s: %v", utils.ToXPath(p, false), err)
+	}
+	v.root = dataPath(names)
+	if len(names) == 0 {
+		return nil, nil, nil
+	}
+	se, err := v.lr.r.Get(ctx, names)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "path %s: %v", utils.ToXPath(p, false), err)
+	}
+	cs := se.GetContainer()
+	if cs == nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "path %s does not point to a container or a list entry", utils.ToXPath(p, false))
+	}
+	if len(cs.GetKeys()) > 0 && len(p.GetElem()[len(names)-1].GetKey()) == 0 {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "path %s points to a list, expecting the keys of an entry", utils.ToXPath(p, false))
+	}
+	return names, cs, nil
+}
+
+// document validates doc, the content of the node cs found at the schema
+// names and path p, and the leafrefs within it.
+func (v *documentValidator) document(ctx context.Context, names []string, p *sdcpb.Path, cs *sdcpb.ContainerSchema, doc map[string]interface{}) error {
+	var err error
+	if cs == nil {
+		err = v.object(ctx, nil, nil, nil, doc)
+	} else {
+		err = v.entryAt(ctx, names, append([]*sdcpb.PathElem(nil), p.GetElem()...), cs, doc)
+	}
+	if err != nil {
+		return err
+	}
+	return v.checkLeafrefs(ctx)
+}
+
+// documentValidator collects the errors of an instance document.
+// The document is walked as a decoded JSON one, an XML document
+// having its elements converted to objects and strings.
+type documentValidator struct {
+	lr  *leafrefResolver
+	xml bool
+	// data paths of the leafrefs not requiring an instance
+	optional []string
+	// metadata annotations of the schema by module qualified name
+	annotations map[string]*schema.Annotation
+	// data path of the node the document is the content of
+	root string
+	// values of the leaves and leaf-lists by data path
+	values map[string]map[string]struct{}
+	// leafref values to look up once the document is walked
+	refs []*leafrefValue
+	// mandatory descendants of the non presence containers by data path
+	required  map[string][]string
+	errs      []*api.DocumentError
+	truncated bool
+}
+
+type leafrefValue struct {
+	path  []*sdcpb.PathElem
+	names []string
+	expr  string
+	value string
+}
+
+func (v *documentValidator) add(ipath []*sdcpb.PathElem, kind, constraint, msg string) {
+	if len(v.errs) >= maxDocumentErrors {
+		v.truncated = true
+		return
+	}
+	v.errs = append(v.errs, &api.DocumentError{
+		Path:       "/" + utils.ToXPath(&sdcpb.Path{Elem: ipath}, false),
+		Kind:       kind,
+		Constraint: constraint,
+		Message:    msg,
+	})
+}
+
+// reset drops the errors and the values found so far, to validate another document.
+func (v *documentValidator) reset() {
+	v.values = make(map[string]map[string]struct{})
+	v.refs = nil
+	v.errs = nil
+	v.truncated = false
+}
+
+func (v *documentValidator) response() *api.ValidateDocumentResponse {
+	return &api.ValidateDocumentResponse{
+		Valid:     len(v.errs) == 0,
+		Errors:    v.errs,
+		Truncated: v.truncated,
+	}
+}
+
+// dataPath returns the data path of the schema names, without module prefixes.
+func dataPath(names []string) string {
+	return "/" + strings.Join(stripPrefixes(names), "/")
+}
+
+func appendName(names []string, name string) []string {
+	return append(append(make([]string, 0, len(names)+1), names...), name)
+}
+
+func appendElem(ipath []*sdcpb.PathElem, pe *sdcpb.PathElem) []*sdcpb.PathElem {
+	return append(append(make([]*sdcpb.PathElem, 0, len(ipath)+1), ipath...), pe)
+}
+
+// members tracks the members of an object, for its mandatory nodes.
+type members struct {
+	names map[string]struct{}
+	// choice/case of the members in a choice
+	cases map[string]struct{}
+}
+
+func (ms *members) add(name string, ci *sdcpb.ChoiceInfo) {
+	ms.names[stripPrefix(name)] = struct{}{}
+	if ci.GetChoice() != "" {
+		ms.cases[ci.GetChoice()+"/"+ci.GetCase()] = struct{}{}
+	}
+}
+
+func (ms *members) has(name string) bool {
+	_, ok := ms.names[name]
+	return ok
+}
+
+// active reports whether a node of choice info ci applies:
+// it is not in a choice or a member of its case is present.
+func (ms *members) active(ci *sdcpb.ChoiceInfo) bool {
+	if ci.GetChoice() == "" {
+		return true
+	}
+	_, ok := ms.cases[ci.GetChoice()+"/"+ci.GetCase()]
+	return ok
+}
+
+// object validates the members of obj, the content of the container or
+// list entry cs found at the schema names, the root if cs is nil.
+// The members named in skip, the keys of an entry, are already validated.
+func (v *documentValidator) object(ctx context.Context, names []string, ipath []*sdcpb.PathElem, cs *sdcpb.ContainerSchema, obj map[string]interface{}, skip ...string) error {
+	ms := &members{names: make(map[string]struct{}, len(obj)), cases: make(map[string]struct{})}
+	for _, k := range skip {
+		ms.add(k, nil)
+	}
+	keys := make([]string, 0, len(obj))
+	for m := range obj {
+		keys = append(keys, m)
+	}
+	sort.Strings(keys)
+	for _, m := range keys {
+		if strings.HasPrefix(m, "@") {
+			err := v.annotationsMember(ctx, names, ipath, obj, m)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if contains(skip, stripPrefix(m)) {
+			continue
+		}
+		cnames := appendName(names, m)
+		cip := appendElem(ipath, &sdcpb.PathElem{Name: m})
+		se, err := v.lr.r.Get(ctx, cnames)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			v.add(cip, api.DocumentErrorUnknown, "", fmt.Sprintf("%q is not a node of the schema", m))
+			continue
+		}
+		switch se := se.GetSchema().(type) {
+		case *sdcpb.SchemaElem_Container:
+			ms.add(m, se.Container.GetChoiceInfo())
+			if len(se.Container.GetKeys()) == 0 {
+				o, ok := v.asObject(obj[m])
+				if !ok {
+					v.add(cip, api.DocumentErrorStructure, "", "expecting an object")
+					continue
+				}
+				err = v.object(ctx, cnames, cip, se.Container, o)
+			} else {
+				err = v.list(ctx, cnames, ipath, m, se.Container, obj[m])
+			}
+			if err != nil {
+				return err
+			}
+		case *sdcpb.SchemaElem_Field:
+			ms.add(m, se.Field.GetChoiceInfo())
+			v.leaf(ctx, cnames, cip, se.Field.GetType(), obj[m])
+		case *sdcpb.SchemaElem_Leaflist:
+			ms.add(m, se.Leaflist.GetChoiceInfo())
+			vs, ok := v.asArray(obj[m])
+			if !ok {
+				v.add(cip, api.DocumentErrorStructure, "", "expecting an array of values")
+				continue
+			}
+			v.elements(cip, uint64(len(vs)), se.Leaflist.GetMinElements(), se.Leaflist.GetMaxElements())
+			for _, lv := range vs {
+				v.leaf(ctx, cnames, cip, se.Leaflist.GetType(), lv)
+			}
+		}
+	}
+	if cs == nil {
+		return nil
+	}
+	return v.mandatory(ctx, names, ipath, cs, ms)
+}
+
+// list validates the entries of the list cs, the value of member m.
+func (v *documentValidator) list(ctx context.Context, names []string, ipath []*sdcpb.PathElem, m string, cs *sdcpb.ContainerSchema, val interface{}) error {
+	lip := appendElem(ipath, &sdcpb.PathElem{Name: m})
+	entries, ok := v.asArray(val)
+	if !ok {
+		v.add(lip, api.DocumentErrorStructure, "", "expecting an array of list entries")
+		return nil
+	}
+	v.elements(lip, uint64(len(entries)), cs.GetMinElements(), cs.GetMaxElements())
+	seen := make(map[string]struct{}, len(entries))
+	for _, ev := range entries {
+		o, ok := v.asObject(ev)
+		if !ok {
+			v.add(lip, api.DocumentErrorStructure, "", "expecting list entries to be objects")
+			continue
+		}
+		pe := &sdcpb.PathElem{Name: m, Key: make(map[string]string, len(cs.GetKeys()))}
+		kvs := make([]string, 0, len(cs.GetKeys()))
+		for _, k := range cs.GetKeys() {
+			kval, ok := member(o, k.GetName())
+			if !ok {
+				continue
+			}
+			raw, err := jsonRaw(kval)
+			if err != nil {
+				continue
+			}
+			pe.Key[k.GetName()] = raw
+			kvs = append(kvs, raw)
+		}
+		eip := appendElem(ipath, pe)
+		if len(kvs) < len(cs.GetKeys()) {
+			for _, k := range cs.GetKeys() {
+				if _, ok := pe.Key[k.GetName()]; !ok {
+					v.add(eip, api.DocumentErrorKey, "", fmt.Sprintf("list entry missing key %q", k.GetName()))
+				}
+			}
+			continue
+		}
+		kv := strings.Join(kvs, "\x00")
+		if _, ok := seen[kv]; ok {
+			v.add(eip, api.DocumentErrorKey, "", "duplicate list entry")
+			continue
+		}
+		seen[kv] = struct{}{}
+		err := v.entry(ctx, names, eip, cs, o)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entry validates the list entry o at ipath, with the keys of its path element.
+func (v *documentValidator) entry(ctx context.Context, names []string, ipath []*sdcpb.PathElem, cs *sdcpb.ContainerSchema, o map[string]interface{}) error {
+	skip := make([]string, 0, len(cs.GetKeys()))
+	for _, k := range cs.GetKeys() {
+		kip := appendElem(ipath, &sdcpb.PathElem{Name: k.GetName()})
+		val, ok := member(o, k.GetName())
+		if !ok {
+			// set by the path of the document
+			kv, ok := ipath[len(ipath)-1].GetKey()[k.GetName()]
+			if !ok {
+				v.add(ipath, api.DocumentErrorKey, "", fmt.Sprintf("list entry missing key %q", k.GetName()))
+				continue
+			}
+			val = kv
+		}
+		v.leaf(ctx, appendName(names, k.GetName()), kip, k.GetType(), val)
+		skip = append(skip, k.GetName())
+	}
+	return v.object(ctx, names, ipath, cs, o, skip...)
+}
+
+// entryAt validates the document doc, the content of the container
+// or list entry cs at the schema names and instance path ipath.
+func (v *documentValidator) entryAt(ctx context.Context, names []string, ipath []*sdcpb.PathElem, cs *sdcpb.ContainerSchema, doc map[string]interface{}) error {
+	if len(cs.GetKeys()) == 0 {
+		return v.object(ctx, names, ipath, cs, doc)
+	}
+	pe := ipath[len(ipath)-1]
+	for _, k := range cs.GetKeys() {
+		kval, ok := member(doc, k.GetName())
+		if !ok {
+			continue
+		}
+		if raw, err := jsonRaw(kval); err != nil || raw != pe.GetKey()[k.GetName()] {
+			v.add(appendElem(ipath, &sdcpb.PathElem{Name: k.GetName()}), api.DocumentErrorKey, "",
+				fmt.Sprintf("key value %v does not match the path", kval))
+		}
+	}
+	return v.entry(ctx, names, ipath, cs, doc)
+}
+
+// annotationsMember validates the member m of obj holding metadata annotations,
+// RFC 7952: "@" holds the ones of the container or list entry obj is the content of,
+// "@<name>" the ones of the leaf member name or an array of them, aligned with its
+// values, for a leaf-list. An empty XML container has its annotations as a leaf.
+func (v *documentValidator) annotationsMember(ctx context.Context, names []string, ipath []*sdcpb.PathElem, obj map[string]interface{}, m string) error {
+	if m == "@" {
+		if len(names) == 0 {
+			v.add(ipath, api.DocumentErrorAnnotation, "", "the root cannot be annotated")
+			return nil
+		}
+		v.annotate(ipath, obj[m])
+		return nil
+	}
+	name := m[1:]
+	cip := appendElem(ipath, &sdcpb.PathElem{Name: name})
+	val, ok := obj[name]
+	if !ok {
+		v.add(cip, api.DocumentErrorAnnotation, "", fmt.Sprintf("annotations of missing member %q", name))
+		return nil
+	}
+	se, err := v.lr.r.Get(ctx, appendName(names, name))
+	if err != nil {
+		// reported as an unknown member
+		return ctx.Err()
+	}
+	switch se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Field:
+		v.annotate(cip, obj[m])
+	case *sdcpb.SchemaElem_Leaflist:
+		as, ok := v.asArray(obj[m])
+		if !ok {
+			v.add(cip, api.DocumentErrorAnnotation, "", "expecting an array of annotations")
+			return nil
+		}
+		if vs, _ := v.asArray(val); len(as) > len(vs) {
+			v.add(cip, api.DocumentErrorAnnotation, "", fmt.Sprintf("%d annotations for %d value(s)", len(as), len(vs)))
+		}
+		for _, a := range as {
+			// a value without annotations
+			if a == nil {
+				continue
+			}
+			v.annotate(cip, a)
+		}
+	case *sdcpb.SchemaElem_Container:
+		if v.xml {
+			v.annotate(cip, obj[m])
+			return nil
+		}
+		v.add(cip, api.DocumentErrorAnnotation, "", fmt.Sprintf("the annotations of %q must be its \"@\" member", name))
+	}
+	return nil
+}
+
+// annotate validates the annotations val of the node at ipath: each one has to be
+// module qualified, an annotation of the schema and of a value of its type.
+func (v *documentValidator) annotate(ipath []*sdcpb.PathElem, val interface{}) {
+	o, ok := val.(map[string]interface{})
+	if !ok {
+		v.add(ipath, api.DocumentErrorAnnotation, "", "expecting an object of annotations")
+		return
+	}
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if !strings.Contains(n, ":") {
+			v.add(ipath, api.DocumentErrorAnnotation, "", fmt.Sprintf("annotation %q is not qualified with its module", n))
+			continue
+		}
+		a, ok := v.annotations[n]
+		if !ok {
+			v.add(ipath, api.DocumentErrorAnnotation, "", fmt.Sprintf("%q is not an annotation of the schema", n))
+			continue
+		}
+		raw, err := jsonRaw(o[n])
+		if err != nil {
+			v.add(ipath, api.DocumentErrorAnnotation, "", fmt.Sprintf("annotation %s: %v", n, err))
+			continue
+		}
+		if a.Type == nil {
+			continue
+		}
+		// the targets of leafref annotations are not looked up
+		ck := &value.Checker{}
+		var vls []*value.Violation
+		if v.xml {
+			_, vls = ck.Check(a.Type, raw)
+		} else {
+			_, vls = checkJSON(ck, a.Type, raw, o[n], func(*sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType { return nil })
+		}
+		for _, vl := range vls {
+			v.add(ipath, api.DocumentErrorAnnotation, vl.Constraint, fmt.Sprintf("annotation %s: %s", n, vl.Message))
+		}
+	}
+}
+
+// member returns the member of o named name, module qualified or not.
+func member(o map[string]interface{}, name string) (interface{}, bool) {
+	if val, ok := o[name]; ok {
+		return val, true
+	}
+	for m, val := range o {
+		if !strings.HasPrefix(m, "@") && stripPrefix(m) == name {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+// leaf validates the value val of the leaf or leaf-list at the schema names.
+func (v *documentValidator) leaf(ctx context.Context, names []string, ipath []*sdcpb.PathElem, typ *sdcpb.SchemaLeafType, val interface{}) {
+	raw, err := jsonRaw(val)
+	if err != nil {
+		v.add(ipath, api.DocumentErrorStructure, "", err.Error())
+		return
+	}
+	target := func(lt *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+		t, err := v.lr.resolve(ctx, names, lt.GetLeafref())
+		if err != nil {
+			log.Debugf("failed to resolve leafref %s: %v", lt.GetLeafref(), err)
+			return nil
+		}
+		return t.Type
+	}
+	ck := &value.Checker{
+		Leafref: target,
+		InstanceIdentifier: func(p *sdcpb.Path) error {
+			ns, err := v.lr.steps(ctx, nil, p.GetElem())
+			if err != nil {
+				return err
+			}
+			_, err = v.lr.r.Get(ctx, ns)
+			return err
+		},
+	}
+	var mt *sdcpb.SchemaLeafType
+	var vls []*value.Violation
+	if v.xml {
+		mt, vls = ck.Check(typ, raw)
+	} else {
+		mt, vls = checkJSON(ck, typ, raw, val, target)
+	}
+	if mt == nil {
+		for _, vl := range vls {
+			v.add(ipath, api.DocumentErrorType, vl.Constraint, vl.Message)
+		}
+		return
+	}
+	key := dataPath(names)
+	if v.values[key] == nil {
+		v.values[key] = make(map[string]struct{})
+	}
+	v.values[key][raw] = struct{}{}
+	if mt.GetType() != "leafref" {
+		return
+	}
+	if idx := sort.SearchStrings(v.optional, key); idx < len(v.optional) && v.optional[idx] == key {
+		return
+	}
+	v.refs = append(v.refs, &leafrefValue{path: ipath, names: names, expr: mt.GetLeafref(), value: raw})
+}
+
+// checkJSON is ck.Check also checking the JSON encoding of val: RFC 7951 encodes
+// the numbers up to 32 bits as JSON numbers, the booleans as JSON booleans and the
+// other values as strings, a union member only matches a value of its encoding.
+func checkJSON(ck *value.Checker, t *sdcpb.SchemaLeafType, raw string, val interface{}, target func(*sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType) (*sdcpb.SchemaLeafType, []*value.Violation) {
+	if t.GetType() == "union" {
+		var vls []*value.Violation
+		for _, ut := range t.GetUnionTypes() {
+			mt, mvls := checkJSON(ck, ut, raw, val, target)
+			if mt != nil {
+				return mt, nil
+			}
+			vls = append(vls, mvls...)
+		}
+		return nil, vls
+	}
+	mt, vls := ck.Check(t, raw)
+	if mt == nil {
+		return nil, vls
+	}
+	et := mt
+	if et.GetType() == "leafref" {
+		et = target(et)
+	}
+	if ev, err := document.EncodeValue(et, raw); et != nil && err == nil && jsonKind(ev) != jsonKind(val) {
+		return nil, []*value.Violation{{
+			Constraint: value.TypeConstraint,
+			Type:       value.TypeName(et),
+			Message:    fmt.Sprintf("%s value %v must be encoded as a JSON %s", value.TypeName(et), val, jsonKind(ev)),
+		}}
+	}
+	return mt, nil
+}
+
+// elements checks the number of elements n of a list or leaf-list against its min and max elements.
+func (v *documentValidator) elements(ipath []*sdcpb.PathElem, n, min, max uint64) {
+	switch {
+	case n < min:
+		v.add(ipath, api.DocumentErrorMandatory, "", fmt.Sprintf("%d element(s), expecting at least %d", n, min))
+	case max > 0 && n > max:
+		v.add(ipath, api.DocumentErrorMandatory, "", fmt.Sprintf("%d element(s), expecting at most %d", n, max))
+	}
+}
+
+// mandatory reports the mandatory nodes of cs missing from its members ms:
+// the mandatory leaves, the lists and leaf-lists with min-elements and the ones
+// under the non presence containers. The nodes of a choice only apply if their case is present.
+func (v *documentValidator) mandatory(ctx context.Context, names []string, ipath []*sdcpb.PathElem, cs *sdcpb.ContainerSchema, ms *members) error {
+	for _, f := range cs.GetFields() {
+		if f.GetIsMandatory() && !ms.has(f.GetName()) && ms.active(f.GetChoiceInfo()) {
+			v.add(appendElem(ipath, &sdcpb.PathElem{Name: f.GetName()}), api.DocumentErrorMandatory, "", "missing mandatory leaf")
+		}
+	}
+	for _, ll := range cs.GetLeaflists() {
+		if ll.GetMinElements() > 0 && !ms.has(ll.GetName()) && ms.active(ll.GetChoiceInfo()) {
+			v.elements(appendElem(ipath, &sdcpb.PathElem{Name: ll.GetName()}), 0, ll.GetMinElements(), 0)
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		if ms.has(c) {
+			continue
+		}
+		cnames := appendName(names, c)
+		se, err := v.lr.r.Get(ctx, cnames)
+		if err != nil {
+			return err
+		}
+		ccs := se.GetContainer()
+		if !ms.active(ccs.GetChoiceInfo()) {
+			continue
+		}
+		cip := appendElem(ipath, &sdcpb.PathElem{Name: c})
+		if len(ccs.GetKeys()) > 0 {
+			if ccs.GetMinElements() > 0 {
+				v.elements(cip, 0, ccs.GetMinElements(), 0)
+			}
+			continue
+		}
+		if ccs.GetIsPresence() {
+			continue
+		}
+		rs, err := v.requiredIn(ctx, cnames)
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			v.add(appendElem(cip, &sdcpb.PathElem{Name: r}), api.DocumentErrorMandatory, "", "missing mandatory node")
+		}
+	}
+	return nil
+}
+
+// requiredIn returns the paths, relative to it, of the mandatory nodes an absent
+// non presence container found at the schema names requires, outside of choices.
+func (v *documentValidator) requiredIn(ctx context.Context, names []string) ([]string, error) {
+	key := dataPath(names)
+	if rs, ok := v.required[key]; ok {
+		return rs, nil
+	}
+	se, err := v.lr.r.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	cs := se.GetContainer()
+	var rs []string
+	for _, f := range cs.GetFields() {
+		if f.GetIsMandatory() && f.GetChoiceInfo().GetChoice() == "" {
+			rs = append(rs, f.GetName())
+		}
+	}
+	for _, ll := range cs.GetLeaflists() {
+		if ll.GetMinElements() > 0 && ll.GetChoiceInfo().GetChoice() == "" {
+			rs = append(rs, ll.GetName())
+		}
+	}
+	for _, c := range cs.GetChildren() {
+		cnames := appendName(names, c)
+		cse, err := v.lr.r.Get(ctx, cnames)
+		if err != nil {
+			return nil, err
+		}
+		ccs := cse.GetContainer()
+		if ccs.GetChoiceInfo().GetChoice() != "" || ccs.GetIsPresence() {
+			continue
+		}
+		if len(ccs.GetKeys()) > 0 {
+			if ccs.GetMinElements() > 0 {
+				rs = append(rs, c)
+			}
+			continue
+		}
+		crs, err := v.requiredIn(ctx, cnames)
+		if err != nil {
+			return nil, err
+		}
+		for _, cr := range crs {
+			rs = append(rs, c+"/"+cr)
+		}
+	}
+	sort.Strings(rs)
+	v.required[key] = rs
+	return rs, nil
+}
+
+// checkLeafrefs reports the leafref values that are not a value of their target
+// in the document. The predicates of the leafref paths are not applied and the
+// targets outside of the document are not checked.
+func (v *documentValidator) checkLeafrefs(ctx context.Context) error {
+	for _, ref := range v.refs {
+		tp, err := v.lr.targetPath(ctx, ref.names, ref.expr)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Debugf("failed to resolve leafref %s: %v", ref.expr, err)
+			continue
+		}
+		target := dataPath(elemNames(tp))
+		if v.root != "/" && target != v.root && !strings.HasPrefix(target, v.root+"/") {
+			continue
+		}
+		if _, ok := v.values[target][ref.value]; !ok {
+			v.add(ref.path, api.DocumentErrorLeafref, "", fmt.Sprintf("%q is not a value of %s in the document", ref.value, target))
+		}
+	}
+	return nil
+}
+
+// asObject returns val as an object, an empty XML element being an empty one.
+func (v *documentValidator) asObject(val interface{}) (map[string]interface{}, bool) {
+	switch val := val.(type) {
+	case map[string]interface{}:
+		return val, true
+	case string:
+		if v.xml && strings.TrimSpace(val) == "" {
+			return map[string]interface{}{}, true
+		}
+	}
+	return nil, false
+}
+
+// asArray returns val as an array, a single XML element being an array of one.
+func (v *documentValidator) asArray(val interface{}) ([]interface{}, bool) {
+	if vs, ok := val.([]interface{}); ok {
+		return vs, true
+	}
+	if v.xml {
+		return []interface{}{val}, true
+	}
+	return nil, false
+}
+
+func decodeJSONObject(data []byte) (map[string]interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var val interface{}
+	err := d.Decode(&val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %v", err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON document: data after the top level value")
+	}
+	obj, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid JSON document: expecting an object")
+	}
+	return obj, nil
+}
+
+// jsonRaw returns the raw string form of a decoded JSON scalar.
+func jsonRaw(val interface{}) (string, error) {
+	switch val := val.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case []interface{}:
+		// empty type
+		if len(val) == 1 && val[0] == nil {
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("expecting a value, got %s", jsonKind(val))
+}
+
+func jsonKind(val interface{}) string {
+	switch val := val.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		if len(val) == 1 && val[0] == nil {
+			return "[null]"
+		}
+		return "array"
+	}
+	return "null"
+}
+
+// xmlElement is an element of an XML document.
+type xmlElement struct {
+	name     xml.Name
+	text     string
+	children []*xmlElement
+	// attributes in the namespace of a module, the metadata
+	// annotations of the element by module qualified name
+	annotations map[string]interface{}
+}
+
+// decodeXML decodes the XML document data as a decoded JSON one: an element
+// with children is an object, one without a string, the repeated elements an array.
+// The member names are qualified with the module of their namespace if it is not
+// the one of their parent, the annotations are members as in RFC 7952 JSON, see
+// annotationsMember. The document is the content of its root element
+// if content is set or if it is a NETCONF config or data element.
+func (v *documentValidator) decodeXML(ctx context.Context, data []byte, content bool) (map[string]interface{}, error) {
+	modules := make(map[string]string, len(v.lr.modules))
+	for m := range v.lr.modules {
+		ns, err := v.lr.r.ModuleNamespace(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		modules[ns] = m
+	}
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlElement
+	var root *xmlElement
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML document: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			e := &xmlElement{name: tok.Name}
+			for _, a := range tok.Attr {
+				// the attributes in no or another namespace, e.g. NETCONF operations, are not annotations
+				if m := modules[a.Name.Space]; m != "" {
+					if e.annotations == nil {
+						e.annotations = make(map[string]interface{})
+					}
+					e.annotations[m+":"+a.Name.Local] = a.Value
+				}
+			}
+			if len(stack) > 0 {
+				p := stack[len(stack)-1]
+				p.children = append(p.children, e)
+			} else if root != nil {
+				return nil, errors.New("invalid XML document: more than one root element")
+			} else {
+				root = e
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(tok)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("invalid XML document: no root element")
+	}
+	if root.name.Space == netconfBaseNamespace || modules[root.name.Space] == "" && (root.name.Local == "config" || root.name.Local == "data") {
+		content = true
+	}
+	if content {
+		obj, _ := xmlValue(root, modules[root.name.Space], modules).(map[string]interface{})
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+		return obj, nil
+	}
+	return xmlObject([]*xmlElement{root}, "", modules), nil
+}
+
+func xmlValue(e *xmlElement, module string, modules map[string]string) interface{} {
+	if len(e.children) == 0 {
+		return e.text
+	}
+	obj := xmlObject(e.children, module, modules)
+	if e.annotations != nil {
+		obj["@"] = e.annotations
+	}
+	return obj
+}
+
+func xmlObject(es []*xmlElement, module string, modules map[string]string) map[string]interface{} {
+	obj := make(map[string]interface{}, len(es))
+	// annotations of the elements without children by member name,
+	// nil for an element without annotations
+	var annotations map[string][]interface{}
+	annotated := make(map[string]bool)
+	for _, c := range es {
+		name := c.name.Local
+		cmodule := modules[c.name.Space]
+		if cmodule == "" {
+			cmodule = module
+		}
+		if cmodule != module {
+			name = cmodule + ":" + name
+		}
+		val := xmlValue(c, cmodule, modules)
+		switch prev := obj[name].(type) {
+		case nil:
+			obj[name] = val
+		case []interface{}:
+			obj[name] = append(prev, val)
+		default:
+			obj[name] = []interface{}{prev, val}
+		}
+		if len(c.children) == 0 {
+			if annotations == nil {
+				annotations = make(map[string][]interface{})
+			}
+			var a interface{}
+			if c.annotations != nil {
+				a = c.annotations
+				annotated[name] = true
+			}
+			annotations[name] = append(annotations[name], a)
+		}
+	}
+	for name := range annotated {
+		if as := annotations[name]; len(as) == 1 {
+			obj["@"+name] = as[0]
+		} else {
+			obj["@"+name] = as
+		}
+	}
+	return obj
+}
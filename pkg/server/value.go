@@ -0,0 +1,93 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+	"github.com/sdcio/schema-server/pkg/value"
+)
+
+func (s *Server) ValidateValue(ctx context.Context, req *api.ValidateValueRequest) (*api.ValidateValueResponse, error) {
+	log.Debugf("received ValidateValue: %v", req)
+	_, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Path.GetElem()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+	lr := &leafrefResolver{r: store.NewResolver(s.schemaStore, req.Schema)}
+	err = lr.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := lr.qualify(ctx, elemNames(req.Path))
+	if err != nil {
+		return nil, err
+	}
+	se, err := lr.r.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	typ, _, ok := leafType(se)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "path %s is not a leaf or a leaf-list", utils.ToXPath(req.Path, true))
+	}
+	ck := &value.Checker{
+		// leafref values are checked against the type of their target
+		Leafref: func(lt *sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType {
+			t, err := lr.resolve(ctx, names, lt.GetLeafref())
+			if err != nil {
+				log.Debugf("failed to resolve leafref %s: %v", lt.GetLeafref(), err)
+				return nil
+			}
+			return t.Type
+		},
+		// the node of an instance-identifier must exist in the schema
+		InstanceIdentifier: func(p *sdcpb.Path) error {
+			ns, err := lr.steps(ctx, nil, p.GetElem())
+			if err != nil {
+				return err
+			}
+			_, err = lr.r.Get(ctx, ns)
+			return err
+		},
+	}
+	mt, vs := ck.Check(typ, req.Value)
+	rsp := &api.ValidateValueResponse{
+		Valid: mt != nil,
+	}
+	if mt != nil {
+		rsp.Type = value.TypeName(mt)
+	}
+	for _, vl := range vs {
+		rsp.Violations = append(rsp.Violations, &api.ValueViolation{
+			Constraint: vl.Constraint,
+			Statement:  vl.Statement,
+			Type:       vl.Type,
+			Message:    vl.Message,
+		})
+	}
+	return rsp, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+const (
+	// features enabled by the features policy of the schema returned by GetSchemaDetails,
+	// as module:feature, one value each
+	featuresHeader = "x-schema-features"
+	// modules deviating the schema returned by GetSchemaDetails, one value each
+	deviationsHeader = "x-schema-deviations"
+	// top-level nodes the schema returned by GetSchemaDetails is restricted to, one value each
+	subtreesHeader = "x-schema-subtrees"
+)
+
+// setVariantHeaders sets the enabled features, the deviation modules
+// and the subtrees of schema sc as response headers.
+func (s *Server) setVariantHeaders(ctx context.Context, sc *sdcpb.Schema) {
+	v, err := s.schemaStore.GetSchemaVariant(ctx, store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+	if err != nil {
+		log.Debugf("failed to get the variant of schema %v: %v", sc, err)
+		return
+	}
+	if v == nil || len(v.Features)+len(v.Deviations)+len(v.Subtrees) == 0 {
+		return
+	}
+	md := metadata.MD{}
+	if len(v.Features) > 0 {
+		md.Append(featuresHeader, v.Features...)
+	}
+	if len(v.Deviations) > 0 {
+		md.Append(deviationsHeader, v.Deviations...)
+	}
+	if len(v.Subtrees) > 0 {
+		md.Append(subtreesHeader, v.Subtrees...)
+	}
+	_ = grpc.SetHeader(ctx, md)
+}
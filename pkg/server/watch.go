@@ -0,0 +1,151 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// events buffered per watcher, a watcher falling further behind is disconnected
+const watchBufferSize = 64
+
+// watchers fans out the schema events to the WatchSchemaChanges streams.
+type watchers struct {
+	m    sync.Mutex
+	subs map[*watcher]struct{}
+}
+
+type watcher struct {
+	schemas []*sdcpb.Schema
+	events  chan *api.SchemaEvent
+	// closed when the watcher is dropped for being too slow
+	dropped chan struct{}
+}
+
+func (w *watcher) matches(sc *sdcpb.Schema) bool {
+	if len(w.schemas) == 0 {
+		return true
+	}
+	for _, f := range w.schemas {
+		if (f.GetName() == "" || f.GetName() == sc.GetName()) &&
+			(f.GetVendor() == "" || f.GetVendor() == sc.GetVendor()) &&
+			(f.GetVersion() == "" || f.GetVersion() == sc.GetVersion()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ws *watchers) subscribe(schemas []*sdcpb.Schema) (*watcher, func()) {
+	w := &watcher{
+		schemas: schemas,
+		events:  make(chan *api.SchemaEvent, watchBufferSize),
+		dropped: make(chan struct{}),
+	}
+	ws.m.Lock()
+	defer ws.m.Unlock()
+	if ws.subs == nil {
+		ws.subs = make(map[*watcher]struct{})
+	}
+	ws.subs[w] = struct{}{}
+	return w, func() {
+		ws.m.Lock()
+		defer ws.m.Unlock()
+		delete(ws.subs, w)
+	}
+}
+
+// publish never blocks, a watcher whose buffer is full is dropped.
+func (ws *watchers) publish(ev *api.SchemaEvent) {
+	ws.m.Lock()
+	defer ws.m.Unlock()
+	for w := range ws.subs {
+		if !w.matches(ev.Schema) {
+			continue
+		}
+		select {
+		case w.events <- ev:
+		default:
+			delete(ws.subs, w)
+			close(w.dropped)
+		}
+	}
+}
+
+// notify publishes a change of schema sck to the watchers, updates its
+// shared memory snapshot and drops its cached fingerprint.
+func (s *Server) notify(sck store.SchemaKey, typ string) {
+	s.fingerprints.drop(sck)
+	if typ == api.SchemaEventRemoved {
+		s.schemaMetrics.forget(sck)
+	}
+	if s.shm != nil {
+		s.shm.schedule(sck)
+	}
+	s.watchers.publish(&api.SchemaEvent{
+		Schema: schemaOf(sck),
+		Type:   typ,
+		Time:   time.Now(),
+	})
+}
+
+func (s *Server) WatchSchemaChanges(req *api.WatchSchemaChangesRequest, stream api.SchemaServerExt_WatchSchemaChangesServer) error {
+	log.Debugf("received WatchSchemaChanges: %v", req)
+	w, cancel := s.watchers.subscribe(req.Schemas)
+	defer cancel()
+	// the stream is established once the headers are received
+	err := stream.SendHeader(nil)
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-w.events:
+			if !s.allowSchema(ctx, ev.Schema) {
+				continue
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-w.dropped:
+			// deliver the events buffered before the one that was lost
+			for {
+				select {
+				case ev := <-w.events:
+					if !s.allowSchema(ctx, ev.Schema) {
+						continue
+					}
+					if err := stream.Send(ev); err != nil {
+						return err
+					}
+				default:
+					return status.Errorf(codes.ResourceExhausted, "more than %d schema events pending, watch again", watchBufferSize)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+const (
+	defaultExpandPageSize = 1000
+	maxExpandPageSize     = 10000
+)
+
+const (
+	// matches any single path element
+	wildcardElem = "*"
+	// matches any number of path elements, none included
+	recursiveElem = "..."
+)
+
+var errPageFull = errors.New("page full")
+
+// ExpandWildcardPath walks the schema in path order and returns a page of the leaf
+// paths matching the request path. The walk restarts from the root for each page,
+// the page token holds the number of paths already returned.
+func (s *Server) ExpandWildcardPath(ctx context.Context, req *api.ExpandWildcardPathRequest) (*api.ExpandWildcardPathResponse, error) {
+	log.Debugf("received ExpandWildcardPath: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := req.PageSize
+	switch {
+	case pageSize < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page size %d", pageSize)
+	case pageSize == 0:
+		pageSize = defaultExpandPageSize
+	case pageSize > maxExpandPageSize:
+		pageSize = maxExpandPageSize
+	}
+	sourcesDigest, err := s.schemaStore.GetSchemaSourcesDigest(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	reqDigest := expandRequestDigest(req)
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = decodePageToken(req.PageToken, reqDigest, sourcesDigest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	w := &wildcardWalker{
+		resolver: store.NewResolver(s.schemaStore, req.Schema),
+		pattern:  req.Path.GetElem(),
+		dataType: req.DataType,
+	}
+	if l := s.schemaLimits(req.Schema); l != nil && (l.MaxDepth > 0 || l.MaxNodes > 0) {
+		w.limits = &limitsWalker{limits: l, path: req.Path}
+	}
+	var q *pathQualifier
+	if s.config.GRPCServer.PathQualification != config.PathQualificationNone {
+		q = newPathQualifier(s.schemaStore, req.Schema, s.config.GRPCServer.PathQualification)
+	}
+	rsp := new(api.ExpandWildcardPathResponse)
+	count := 0
+	err = w.walk(ctx, func(p *sdcpb.Path) error {
+		count++
+		switch {
+		case count <= offset:
+			return nil
+		case count > offset+pageSize:
+			return errPageFull
+		}
+		if q != nil {
+			if err := q.qualify(ctx, p); err != nil {
+				return err
+			}
+		}
+		if req.Xpath {
+			rsp.Xpath = append(rsp.Xpath, utils.ToXPath(p, false))
+			return nil
+		}
+		rsp.Path = append(rsp.Path, p)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errPageFull):
+		rsp.NextPageToken = encodePageToken(offset+pageSize, reqDigest, sourcesDigest)
+	case err != nil:
+		return nil, err
+	}
+	return rsp, nil
+}
+
+// expandRequestDigest returns the digest of the request attributes
+// the expansion depends on, a page token is only valid for the same ones.
+func expandRequestDigest(req *api.ExpandWildcardPathRequest) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%d", req.Schema.GetName(), req.Schema.GetVendor(),
+		req.Schema.GetVersion(), utils.ToXPath(req.Path, false), req.DataType)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+func encodePageToken(offset int, reqDigest, sourcesDigest string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + reqDigest + ":" + sourcesDigest))
+}
+
+func decodePageToken(token, reqDigest, sourcesDigest string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	parts := strings.SplitN(string(b), ":", 3)
+	if len(parts) != 3 {
+		return 0, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	offset, err := strconv.Atoi(parts[0])
+	if err != nil || offset < 0 {
+		return 0, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	if parts[1] != reqDigest {
+		return 0, status.Error(codes.InvalidArgument, "page token does not match the request")
+	}
+	if parts[2] != sourcesDigest {
+		return 0, status.Error(codes.Aborted, "schema changed since the first page, restart the expansion")
+	}
+	return offset, nil
+}
+
+// wildcardWalker matches the schema nodes against a path pattern
+// while walking the schema once: each node is visited with the set
+// of pattern positions its path can be at, so a leaf matching the
+// pattern in more than one way is returned once.
+type wildcardWalker struct {
+	resolver *store.Resolver
+	pattern  []*sdcpb.PathElem
+	dataType sdcpb.DataType
+	// nil without subtree limits
+	limits *limitsWalker
+}
+
+// schemaChild is a node found under a container or list.
+type schemaChild struct {
+	name string
+	// resolver names of the node
+	names []string
+	// set for top level nodes
+	module string
+	// path element name, module qualified if more than one module defines the node
+	elemName string
+	dir      bool
+	leafList bool
+	isKey    bool
+	isState  bool
+	// type of a leaf or leaf-list
+	typ *sdcpb.SchemaLeafType
+}
+
+func (w *wildcardWalker) walk(ctx context.Context, fn func(*sdcpb.Path) error) error {
+	return w.walkDir(ctx, nil, nil, w.closure([]int{0}), 0, fn)
+}
+
+func (w *wildcardWalker) walkDir(ctx context.Context, names []string, elems []*sdcpb.PathElem, states []int, depth int, fn func(*sdcpb.Path) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	children, err := schemaChildren(ctx, w.resolver, names)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		next, exact, keys := w.step(states, c)
+		if len(next) == 0 {
+			continue
+		}
+		// pruned nodes do not count against the subtree limits
+		if w.limits != nil {
+			if err := w.limits.visit(depth + 1); err != nil {
+				return err
+			}
+		}
+		matched := next[len(next)-1] == len(w.pattern)
+		if !c.dir {
+			// keys are only returned when the pattern names them
+			if !matched || (c.isKey && !exact) || !dataTypeMatches(w.dataType, c.isState) {
+				continue
+			}
+			p := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(elems)+1)}
+			p.Elem = append(p.Elem, elems...)
+			p.Elem = append(p.Elem, &sdcpb.PathElem{Name: c.elemName})
+			if err := fn(p); err != nil {
+				return err
+			}
+			continue
+		}
+		se, err := w.resolver.Get(ctx, c.names)
+		if err != nil {
+			return err
+		}
+		pe := &sdcpb.PathElem{Name: c.elemName}
+		for _, k := range se.GetContainer().GetKeys() {
+			if pe.Key == nil {
+				pe.Key = make(map[string]string)
+			}
+			pe.Key[k.GetName()] = wildcardElem
+			if v, ok := keys[k.GetName()]; ok && v != "" {
+				pe.Key[k.GetName()] = v
+			}
+		}
+		err = w.walkDir(ctx, c.names, append(elems[:len(elems):len(elems)], pe), next, depth+1, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaChildren returns the nodes found under the container or list at names sorted by name,
+// the top level nodes of all modules for the root.
+func schemaChildren(ctx context.Context, r *store.Resolver, names []string) ([]*schemaChild, error) {
+	se, err := r.Get(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	var children []*schemaChild
+	if len(names) == 0 {
+		count := make(map[string]int)
+		for _, m := range se.GetContainer().GetChildren() {
+			mse, err := r.Get(ctx, []string{m})
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range containerChildren([]string{m}, mse.GetContainer()) {
+				c.module = m
+				count[c.name]++
+				children = append(children, c)
+			}
+		}
+		for _, c := range children {
+			if count[c.name] > 1 {
+				c.elemName = c.module + ":" + c.name
+			}
+		}
+	} else {
+		children = containerChildren(names, se.GetContainer())
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		if children[i].name != children[j].name {
+			return children[i].name < children[j].name
+		}
+		return children[i].module < children[j].module
+	})
+	return children, nil
+}
+
+func containerChildren(names []string, cs *sdcpb.ContainerSchema) []*schemaChild {
+	children := make([]*schemaChild, 0, len(cs.GetKeys())+len(cs.GetFields())+len(cs.GetLeaflists())+len(cs.GetChildren()))
+	add := func(name string) *schemaChild {
+		cnames := make([]string, 0, len(names)+1)
+		cnames = append(cnames, names...)
+		cnames = append(cnames, name)
+		c := &schemaChild{
+			name:     name,
+			names:    cnames,
+			elemName: name,
+		}
+		children = append(children, c)
+		return c
+	}
+	for _, k := range cs.GetKeys() {
+		c := add(k.GetName())
+		c.isKey, c.isState, c.typ = true, k.GetIsState(), k.GetType()
+	}
+	for _, f := range cs.GetFields() {
+		c := add(f.GetName())
+		c.isState, c.typ = f.GetIsState(), f.GetType()
+	}
+	for _, ll := range cs.GetLeaflists() {
+		c := add(ll.GetName())
+		c.leafList, c.isState, c.typ = true, ll.GetIsState(), ll.GetType()
+	}
+	for _, name := range cs.GetChildren() {
+		add(name).dir = true
+	}
+	return children
+}
+
+// step returns the pattern positions reached from states by child c, sorted.
+// exact reports whether the pattern end is reached with an element naming c,
+// keys are the keys of the first pattern element matching c that has some.
+func (w *wildcardWalker) step(states []int, c *schemaChild) (next []int, exact bool, keys map[string]string) {
+	n := len(w.pattern)
+	for _, i := range states {
+		if i == n {
+			// the pattern matched an ancestor, everything below it matches
+			next = append(next, n)
+			continue
+		}
+		pe := w.pattern[i]
+		switch pe.GetName() {
+		case recursiveElem:
+			next = append(next, i)
+			continue
+		case wildcardElem:
+		default:
+			if !matchElemName(pe.GetName(), c) {
+				continue
+			}
+			if i+1 == n {
+				exact = true
+			}
+		}
+		next = append(next, i+1)
+		if keys == nil && len(pe.GetKey()) > 0 {
+			keys = pe.GetKey()
+		}
+	}
+	return w.closure(next), exact, keys
+}
+
+// closure adds to states the positions following a recursive element,
+// it matches no element as well.
+func (w *wildcardWalker) closure(states []int) []int {
+	sort.Ints(states)
+	rs := make([]int, 0, len(states))
+	seen := make(map[int]bool, len(states))
+	add := func(i int) {
+		if !seen[i] {
+			seen[i] = true
+			rs = append(rs, i)
+		}
+	}
+	for _, i := range states {
+		add(i)
+		for i < len(w.pattern) && w.pattern[i].GetName() == recursiveElem {
+			i++
+			add(i)
+		}
+	}
+	sort.Ints(rs)
+	return rs
+}
+
+// matchElemName reports whether the pattern element name matches child c,
+// a module prefix only applies to top level nodes.
+func matchElemName(name string, c *schemaChild) bool {
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		if c.module != "" && name[:idx] != c.module {
+			return false
+		}
+		name = name[idx+1:]
+	}
+	return name == c.name
+}
+
+func dataTypeMatches(dt sdcpb.DataType, isState bool) bool {
+	switch dt {
+	case sdcpb.DataType_CONFIG:
+		return !isState
+	case sdcpb.DataType_STATE:
+		return isState
+	}
+	return true
+}
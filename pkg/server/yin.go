@@ -0,0 +1,85 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sdcio/schema-server/pkg/api"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+func (s *Server) GetModuleYIN(ctx context.Context, req *api.GetModuleYINRequest) (*api.GetModuleYINResponse, error) {
+	log.Debugf("received GetModuleYIN: %v", req)
+	sck, err := s.checkSchema(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if req.Module == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing module name")
+	}
+	ok, err := s.hasModule(ctx, sck, req.Module)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "schema %v has no module %s", sck, req.Module)
+	}
+	cfg, err := s.schemaStore.GetSchemaConfig(ctx, sck)
+	if err != nil {
+		return nil, err
+	}
+	sm, yin, err := schema.ModuleYIN(cfg, req.Module, req.Revision)
+	if err != nil {
+		var nfe *schema.ModuleNotFoundError
+		if errors.As(err, &nfe) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to read the module sources: %v", err)
+	}
+	return &api.GetModuleYINResponse{
+		Module:   sm.Name,
+		Revision: sm.Revision,
+		File:     sm.File,
+		YIN:      string(yin),
+	}, nil
+}
+
+// hasModule reports whether module, or submodule, name is part of the schema sck.
+func (s *Server) hasModule(ctx context.Context, sck store.SchemaKey, name string) (bool, error) {
+	root, err := store.NewResolver(s.schemaStore, schemaOf(sck)).Get(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if contains(root.GetContainer().GetChildren(), name) {
+		return true, nil
+	}
+	sms, err := s.schemaStore.GetSchemaSubmodules(ctx, sck)
+	if err != nil {
+		return false, err
+	}
+	for _, sm := range sms {
+		if sm.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
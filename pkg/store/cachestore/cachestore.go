@@ -0,0 +1,280 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachestore keeps a copy of the schemas of a memory store on disk,
+// in a persistent store. On restart a configured schema found in the cache
+// with the same sources digest is served from it while it is parsed again
+// in memory, instead of being unavailable until then.
+package cachestore
+
+import (
+	"context"
+	"sync"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+	"github.com/sdcio/schema-server/pkg/store/memstore"
+	"github.com/sdcio/schema-server/pkg/store/persiststore"
+)
+
+// cacheStore serves the schemas of its memory Store and the warm ones,
+// found in the cache and not parsed in memory yet.
+type cacheStore struct {
+	store.Store
+	cache store.Store
+	m     sync.RWMutex
+	warm  map[store.SchemaKey]struct{}
+	// serializes the writes to the cache
+	wm sync.Mutex
+}
+
+// New returns a memory store writing the schemas added to it to the cache directory dir.
+func New(ctx context.Context, dir string) (store.Store, error) {
+	cache, err := persiststore.NewCache(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &cacheStore{
+		Store: memstore.New(),
+		cache: cache,
+		warm:  make(map[store.SchemaKey]struct{}),
+	}, nil
+}
+
+// Warm serves schema sck from the cache until it is added to the store,
+// if the cache has it with the sources digest digest.
+func (s *cacheStore) Warm(ctx context.Context, sck store.SchemaKey, digest string) bool {
+	if digest == "" || !s.cache.HasSchema(sck) {
+		return false
+	}
+	cached, err := s.cache.GetSchemaSourcesDigest(ctx, sck)
+	if err != nil || cached != digest {
+		return false
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.warm[sck] = struct{}{}
+	return true
+}
+
+func (s *cacheStore) isWarm(sck store.SchemaKey) bool {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	_, ok := s.warm[sck]
+	return ok
+}
+
+// served returns the store serving schema sck.
+func (s *cacheStore) served(sck store.SchemaKey) store.Store {
+	if s.isWarm(sck) {
+		return s.cache
+	}
+	return s.Store
+}
+
+func (s *cacheStore) servedSchema(sc *sdcpb.Schema) store.Store {
+	return s.served(store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+}
+
+func (s *cacheStore) AddSchema(sc *schema.Schema) error {
+	err := s.Store.AddSchema(sc)
+	if err != nil {
+		return err
+	}
+	s.added(sc)
+	return nil
+}
+
+func (s *cacheStore) ReplaceSchema(sc *schema.Schema) error {
+	err := s.Store.ReplaceSchema(sc)
+	if err != nil {
+		return err
+	}
+	s.added(sc)
+	return nil
+}
+
+// added serves sc from memory and writes it to the cache in the background,
+// unless the cache has it parsed from the same sources.
+func (s *cacheStore) added(sc *schema.Schema) {
+	sck := store.Key(sc)
+	s.m.Lock()
+	delete(s.warm, sck)
+	s.m.Unlock()
+	go func() {
+		s.wm.Lock()
+		defer s.wm.Unlock()
+		var err error
+		switch {
+		case !s.cache.HasSchema(sck):
+			err = s.cache.AddSchema(sc)
+		default:
+			cached, _ := s.cache.GetSchemaSourcesDigest(context.Background(), sck)
+			if cached != "" && cached == sc.SourcesDigest() {
+				return
+			}
+			err = s.cache.ReplaceSchema(sc)
+		}
+		if err != nil {
+			log.Errorf("schema %s: failed to write the schema cache: %v", sck, err)
+		}
+	}()
+}
+
+// Schema returns the parsed schema stored with key scKey, a warm one is not parsed.
+func (s *cacheStore) Schema(scKey store.SchemaKey) (*schema.Schema, bool) {
+	h, ok := s.Store.(interface {
+		Schema(store.SchemaKey) (*schema.Schema, bool)
+	})
+	if !ok {
+		return nil, false
+	}
+	return h.Schema(scKey)
+}
+
+func (s *cacheStore) HasSchema(scKey store.SchemaKey) bool {
+	return s.Store.HasSchema(scKey) || s.isWarm(scKey)
+}
+
+func (s *cacheStore) ListSchema(ctx context.Context, req *sdcpb.ListSchemaRequest) (*sdcpb.ListSchemaResponse, error) {
+	rsp, err := s.Store.ListSchema(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.m.RLock()
+	defer s.m.RUnlock()
+	for sck := range s.warm {
+		rsp.Schema = append(rsp.Schema, &sdcpb.Schema{
+			Name:    sck.Name,
+			Vendor:  sck.Vendor,
+			Version: sck.Version,
+		})
+	}
+	return rsp, nil
+}
+
+func (s *cacheStore) GetSchemaDetails(ctx context.Context, req *sdcpb.GetSchemaDetailsRequest) (*sdcpb.GetSchemaDetailsResponse, error) {
+	return s.servedSchema(req.GetSchema()).GetSchemaDetails(ctx, req)
+}
+
+func (s *cacheStore) ReloadSchema(ctx context.Context, req *sdcpb.ReloadSchemaRequest) (*sdcpb.ReloadSchemaResponse, error) {
+	return s.servedSchema(req.GetSchema()).ReloadSchema(ctx, req)
+}
+
+// DeleteSchema deletes the schema from the cache as well.
+func (s *cacheStore) DeleteSchema(ctx context.Context, req *sdcpb.DeleteSchemaRequest) (*sdcpb.DeleteSchemaResponse, error) {
+	sc := req.GetSchema()
+	sck := store.SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}
+	if s.isWarm(sck) {
+		s.m.Lock()
+		delete(s.warm, sck)
+		s.m.Unlock()
+	} else {
+		rsp, err := s.Store.DeleteSchema(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if !s.cache.HasSchema(sck) {
+			return rsp, nil
+		}
+	}
+	s.wm.Lock()
+	defer s.wm.Unlock()
+	return s.cache.DeleteSchema(ctx, req)
+}
+
+func (s *cacheStore) GetSchemaMetadata(ctx context.Context, scKey store.SchemaKey) (*schema.Metadata, error) {
+	return s.served(scKey).GetSchemaMetadata(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaComposition(ctx context.Context, scKey store.SchemaKey) (*schema.Composition, error) {
+	return s.served(scKey).GetSchemaComposition(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaLint(ctx context.Context, scKey store.SchemaKey) (*lint.Report, error) {
+	return s.served(scKey).GetSchemaLint(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaOptionalLeafrefs(ctx context.Context, scKey store.SchemaKey) ([]string, error) {
+	return s.served(scKey).GetSchemaOptionalLeafrefs(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaWhen(ctx context.Context, scKey store.SchemaKey, p string) (*schema.WhenStatements, error) {
+	return s.served(scKey).GetSchemaWhen(ctx, scKey, p)
+}
+
+func (s *cacheStore) GetSchemaOrder(ctx context.Context, scKey store.SchemaKey, p string) ([]string, error) {
+	return s.served(scKey).GetSchemaOrder(ctx, scKey, p)
+}
+
+func (s *cacheStore) GetSchemaSubmodules(ctx context.Context, scKey store.SchemaKey) ([]*schema.Submodule, error) {
+	return s.served(scKey).GetSchemaSubmodules(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaIdentities(ctx context.Context, scKey store.SchemaKey) ([]*schema.Identity, error) {
+	return s.served(scKey).GetSchemaIdentities(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaAnnotations(ctx context.Context, scKey store.SchemaKey) ([]*schema.Annotation, error) {
+	return s.served(scKey).GetSchemaAnnotations(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaVariant(ctx context.Context, scKey store.SchemaKey) (*schema.Variant, error) {
+	return s.served(scKey).GetSchemaVariant(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaLibrary(ctx context.Context, scKey store.SchemaKey) ([]*schema.LibraryModule, error) {
+	return s.served(scKey).GetSchemaLibrary(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaDefaultOrigins(ctx context.Context, scKey store.SchemaKey) (map[string]*schema.DefaultOrigin, error) {
+	return s.served(scKey).GetSchemaDefaultOrigins(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaStats(ctx context.Context, scKey store.SchemaKey) (*schema.Stats, error) {
+	return s.served(scKey).GetSchemaStats(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaSourcesDigest(ctx context.Context, scKey store.SchemaKey) (string, error) {
+	return s.served(scKey).GetSchemaSourcesDigest(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchemaConfig(ctx context.Context, scKey store.SchemaKey) (*config.SchemaConfig, error) {
+	return s.served(scKey).GetSchemaConfig(ctx, scKey)
+}
+
+func (s *cacheStore) GetSchema(ctx context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
+	return s.servedSchema(req.GetSchema()).GetSchema(ctx, req)
+}
+
+func (s *cacheStore) GetSchemaElements(ctx context.Context, req *sdcpb.GetSchemaRequest) (chan *sdcpb.SchemaElem, error) {
+	return s.servedSchema(req.GetSchema()).GetSchemaElements(ctx, req)
+}
+
+func (s *cacheStore) ToPath(ctx context.Context, req *sdcpb.ToPathRequest) (*sdcpb.ToPathResponse, error) {
+	return s.servedSchema(req.GetSchema()).ToPath(ctx, req)
+}
+
+func (s *cacheStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest) (*sdcpb.ExpandPathResponse, error) {
+	return s.servedSchema(req.GetSchema()).ExpandPath(ctx, req)
+}
+
+func (s *cacheStore) ExpandPathFunc(ctx context.Context, req *sdcpb.ExpandPathRequest, fn func(*sdcpb.Path) error) error {
+	return s.servedSchema(req.GetSchema()).ExpandPathFunc(ctx, req, fn)
+}
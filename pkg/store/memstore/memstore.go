@@ -26,6 +26,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
 	"github.com/sdcio/schema-server/pkg/schema"
 	"github.com/sdcio/schema-server/pkg/store"
 	"github.com/sdcio/schema-server/pkg/utils"
@@ -171,13 +172,32 @@ func (s *memStore) ReloadSchema(ctx context.Context, req *sdcpb.ReloadSchemaRequ
 	if !ok {
 		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", reqSchema)
 	}
-	nsc, err := sc.Reload()
+	// the schemas sharing module files with sc are reloaded along with it
+	// and all of them are swapped at once, or none if one fails to parse.
+	s.ms.RLock()
+	cfgs := make([]*config.SchemaConfig, 0, len(s.schemas))
+	files := make(map[*config.SchemaConfig][]string, len(s.schemas))
+	for _, osc := range s.schemas {
+		cfgs = append(cfgs, osc.Config())
+		files[osc.Config()] = osc.ModuleFiles()
+	}
+	s.ms.RUnlock()
+	cfgs = append([]*config.SchemaConfig{sc.Config()}, schema.Dependents(sc.Config(), cfgs, func(cfg *config.SchemaConfig) []string {
+		return files[cfg]
+	})...)
+	if len(cfgs) > 1 {
+		log.Infof("reloading schema %s along with %d dependent schema(s)", sc.UniqueName(""), len(cfgs)-1)
+	}
+	nscs, err := schema.NewSchemas(cfgs)
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.FailedPrecondition, "no schema reloaded: %v", err)
 	}
 	s.ms.Lock()
 	defer s.ms.Unlock()
-	s.schemas[store.Key(nsc)] = nsc
+	// replaced together under the lock
+	for _, nsc := range nscs {
+		s.schemas[store.Key(nsc)] = nsc
+	}
 	return &sdcpb.ReloadSchemaResponse{}, nil
 }
 
@@ -231,7 +251,7 @@ func (s *memStore) ToPath(ctx context.Context, req *sdcpb.ToPathRequest) (*sdcpb
 	}
 	err := sc.BuildPath(req.GetPathElement(), p)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%v", err)
+		return nil, toStatusError(err)
 	}
 	rsp := &sdcpb.ToPathResponse{
 		Path: p,
@@ -240,27 +260,13 @@ func (s *memStore) ToPath(ctx context.Context, req *sdcpb.ToPathRequest) (*sdcpb
 }
 
 func (s *memStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest) (*sdcpb.ExpandPathResponse, error) {
-	reqSchema := req.GetSchema()
-	if reqSchema == nil {
-		return nil, status.Error(codes.InvalidArgument, "missing schema details")
-	}
-	switch {
-	// case req.GetSchema().GetName() == "":
-	// 	return nil, status.Error(codes.InvalidArgument, "missing schema name")
-	case req.GetSchema().GetVendor() == "":
-		return nil, status.Error(codes.InvalidArgument, "missing schema vendor")
-	case req.GetSchema().GetVersion() == "":
-		return nil, status.Error(codes.InvalidArgument, "missing schema version")
-	}
-	s.ms.RLock()
-	sc, ok := s.schemas[store.SchemaKey{Name: reqSchema.Name, Vendor: reqSchema.Vendor, Version: reqSchema.Version}]
-	s.ms.RUnlock()
-	if !ok {
-		return nil, status.Errorf(codes.InvalidArgument, "schema %v does not exist", reqSchema)
+	sc, err := s.expandPathSchema(req)
+	if err != nil {
+		return nil, err
 	}
 	paths, err := sc.ExpandPath(req.GetPath(), req.GetDataType())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%v", err)
+		return nil, toStatusError(err)
 	}
 	if req.GetXpath() {
 		xpaths := make([]string, 0, len(paths))
@@ -279,6 +285,40 @@ func (s *memStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest)
 	return rsp, nil
 }
 
+func (s *memStore) ExpandPathFunc(ctx context.Context, req *sdcpb.ExpandPathRequest, fn func(*sdcpb.Path) error) error {
+	sc, err := s.expandPathSchema(req)
+	if err != nil {
+		return err
+	}
+	err = sc.ExpandPathFunc(req.GetPath(), req.GetDataType(), fn)
+	if err != nil {
+		return toStatusError(err)
+	}
+	return nil
+}
+
+func (s *memStore) expandPathSchema(req *sdcpb.ExpandPathRequest) (*schema.Schema, error) {
+	reqSchema := req.GetSchema()
+	if reqSchema == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing schema details")
+	}
+	switch {
+	// case req.GetSchema().GetName() == "":
+	// 	return nil, status.Error(codes.InvalidArgument, "missing schema name")
+	case req.GetSchema().GetVendor() == "":
+		return nil, status.Error(codes.InvalidArgument, "missing schema vendor")
+	case req.GetSchema().GetVersion() == "":
+		return nil, status.Error(codes.InvalidArgument, "missing schema version")
+	}
+	s.ms.RLock()
+	sc, ok := s.schemas[store.SchemaKey{Name: reqSchema.Name, Vendor: reqSchema.Vendor, Version: reqSchema.Version}]
+	s.ms.RUnlock()
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "schema %v does not exist", reqSchema)
+	}
+	return sc, nil
+}
+
 func (s *memStore) AddSchema(sc *schema.Schema) error {
 	s.ms.Lock()
 	defer s.ms.Unlock()
@@ -286,6 +326,169 @@ func (s *memStore) AddSchema(sc *schema.Schema) error {
 	return nil
 }
 
+// ReplaceSchema is AddSchema, a schema is swapped in one write.
+func (s *memStore) ReplaceSchema(sc *schema.Schema) error {
+	return s.AddSchema(sc)
+}
+
+// Schema returns the parsed schema stored with key scKey.
+func (s *memStore) Schema(scKey store.SchemaKey) (*schema.Schema, bool) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	return sc, ok
+}
+
+func (s *memStore) GetSchemaMetadata(ctx context.Context, scKey store.SchemaKey) (*schema.Metadata, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Metadata(), nil
+}
+
+func (s *memStore) GetSchemaSourcesDigest(ctx context.Context, scKey store.SchemaKey) (string, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.SourcesDigest(), nil
+}
+
+func (s *memStore) GetSchemaConfig(ctx context.Context, scKey store.SchemaKey) (*config.SchemaConfig, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Config(), nil
+}
+
+func (s *memStore) GetSchemaComposition(ctx context.Context, scKey store.SchemaKey) (*schema.Composition, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Composition(), nil
+}
+
+func (s *memStore) GetSchemaLint(ctx context.Context, scKey store.SchemaKey) (*lint.Report, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Lint(), nil
+}
+
+func (s *memStore) GetSchemaOptionalLeafrefs(ctx context.Context, scKey store.SchemaKey) ([]string, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.OptionalLeafrefs(), nil
+}
+
+func (s *memStore) GetSchemaWhen(ctx context.Context, scKey store.SchemaKey, p string) (*schema.WhenStatements, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.When(p), nil
+}
+
+func (s *memStore) GetSchemaOrder(ctx context.Context, scKey store.SchemaKey, p string) ([]string, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Order(p), nil
+}
+
+func (s *memStore) GetSchemaSubmodules(ctx context.Context, scKey store.SchemaKey) ([]*schema.Submodule, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Submodules(), nil
+}
+
+func (s *memStore) GetSchemaIdentities(ctx context.Context, scKey store.SchemaKey) ([]*schema.Identity, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Identities(), nil
+}
+
+func (s *memStore) GetSchemaAnnotations(ctx context.Context, scKey store.SchemaKey) ([]*schema.Annotation, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Annotations(), nil
+}
+
+func (s *memStore) GetSchemaVariant(ctx context.Context, scKey store.SchemaKey) (*schema.Variant, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Variant(), nil
+}
+
+func (s *memStore) GetSchemaLibrary(ctx context.Context, scKey store.SchemaKey) ([]*schema.LibraryModule, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Library(), nil
+}
+
+func (s *memStore) GetSchemaStats(ctx context.Context, scKey store.SchemaKey) (*schema.Stats, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.Stats(), nil
+}
+
+func (s *memStore) GetSchemaDefaultOrigins(ctx context.Context, scKey store.SchemaKey) (map[string]*schema.DefaultOrigin, error) {
+	s.ms.RLock()
+	defer s.ms.RUnlock()
+	sc, ok := s.schemas[scKey]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", scKey)
+	}
+	return sc.DefaultOrigins(), nil
+}
+
 func (s *memStore) GetSchemaElements(ctx context.Context, req *sdcpb.GetSchemaRequest) (chan *sdcpb.SchemaElem, error) {
 	s.ms.RLock()
 	defer s.ms.RUnlock()
@@ -336,3 +539,12 @@ func (s *memStore) GetSchemaElements(ctx context.Context, req *sdcpb.GetSchemaRe
 
 	return sch, nil
 }
+
+// toStatusError keeps errors that already carry a gRPC status
+// and wraps the others in a codes.Internal status.
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
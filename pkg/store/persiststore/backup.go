@@ -0,0 +1,69 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persiststore
+
+import (
+	"fmt"
+	"io"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+// writes pending while loading a backup
+const maxPendingWrites = 256
+
+// Backup writes a logical backup of the store db at path p to w. The db is
+// opened, it cannot be backed up while a running server has it open.
+func Backup(p string, w io.Writer) error {
+	db, err := badger.Open(dbOptions(p))
+	if err != nil {
+		return fmt.Errorf("open store %s: %w", p, err)
+	}
+	defer db.Close()
+	_, err = db.Backup(w, 0)
+	return err
+}
+
+// Restore loads a backup written by Backup from r into the store db at path p,
+// its current content is dropped if replace is set. It fails for a db that is
+// not empty otherwise.
+func Restore(p string, r io.Reader, replace bool) error {
+	db, err := badger.Open(dbOptions(p))
+	if err != nil {
+		return fmt.Errorf("open store %s: %w", p, err)
+	}
+	defer db.Close()
+	if replace {
+		if err := db.DropAll(); err != nil {
+			return err
+		}
+	} else {
+		empty := true
+		err = db.View(func(txn *badger.Txn) error {
+			it := txn.NewIterator(badger.IteratorOptions{})
+			defer it.Close()
+			it.Rewind()
+			empty = !it.Valid()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return fmt.Errorf("store %s is not empty", p)
+		}
+	}
+	return db.Load(r, maxPendingWrites)
+}
@@ -20,7 +20,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	badger "github.com/dgraph-io/badger/v4"
@@ -34,14 +38,28 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
 	"github.com/sdcio/schema-server/pkg/schema"
 	"github.com/sdcio/schema-server/pkg/store"
 	"github.com/sdcio/schema-server/pkg/utils"
 )
 
 const (
-	schemasPrefix       uint8 = 1
-	schemaObjectsPrefix uint8 = 2
+	schemasPrefix           uint8 = 1
+	schemaObjectsPrefix     uint8 = 2
+	schemaMetadataPrefix    uint8 = 3
+	schemaCompositionPrefix uint8 = 4
+	schemaLintPrefix        uint8 = 5
+	schemaLeafrefsPrefix    uint8 = 6
+	schemaWhenPrefix        uint8 = 7
+	schemaSubmodulesPrefix  uint8 = 8
+	schemaIdentitiesPrefix  uint8 = 9
+	schemaVariantPrefix     uint8 = 10
+	schemaDefaultsPrefix    uint8 = 11
+	schemaLibraryPrefix     uint8 = 12
+	schemaStatsPrefix       uint8 = 13
+	schemaAnnotationsPrefix uint8 = 14
+	schemaOrderPrefix       uint8 = 15
 	//
 	schemaNameSep = "@"
 )
@@ -62,6 +80,14 @@ type persistStore struct {
 	cfn                  context.CancelFunc
 	db                   *badger.DB
 	cache                *ttlcache.Cache[cacheKey, *sdcpb.GetSchemaResponse]
+	// cache hits and misses by schema, store.SchemaKey to *cacheCounts
+	cacheCounts sync.Map
+	// the stored schemas are not reset, see NewCache
+	keepParsed bool
+}
+
+type cacheCounts struct {
+	hits, misses atomic.Uint64
 }
 
 func New(ctx context.Context, p string, cfg *config.SchemaPersistStoreCacheConfig) (store.Store, error) {
@@ -86,6 +112,54 @@ func New(ctx context.Context, p string, cfg *config.SchemaPersistStoreCacheConfi
 	return s, nil
 }
 
+// NewCache returns a store at p without response cache keeping the schemas
+// it stores parsed, for a memory store to write a copy of its schemas to.
+func NewCache(ctx context.Context, p string) (store.Store, error) {
+	s := &persistStore{path: p, keepParsed: true}
+	var err error
+	s.db, err = s.openDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CacheMetrics returns the number of schema lookups answered from the cache
+// and the ones that missed it, ok is false if the store has no cache.
+func (s *persistStore) CacheMetrics() (hits, misses uint64, ok bool) {
+	if s.cache == nil {
+		return 0, 0, false
+	}
+	m := s.cache.Metrics()
+	return m.Hits, m.Misses, true
+}
+
+// SchemaCacheMetrics is like CacheMetrics for the lookups of schema sck.
+func (s *persistStore) SchemaCacheMetrics(sck store.SchemaKey) (hits, misses uint64, ok bool) {
+	if s.cache == nil {
+		return 0, 0, false
+	}
+	v, ok := s.cacheCounts.Load(sck)
+	if !ok {
+		return 0, 0, true
+	}
+	c := v.(*cacheCounts)
+	return c.hits.Load(), c.misses.Load(), true
+}
+
+func (s *persistStore) countCache(sck store.SchemaKey, hit bool) {
+	v, ok := s.cacheCounts.Load(sck)
+	if !ok {
+		v, _ = s.cacheCounts.LoadOrStore(sck, new(cacheCounts))
+	}
+	c := v.(*cacheCounts)
+	if hit {
+		c.hits.Add(1)
+		return
+	}
+	c.misses.Add(1)
+}
+
 func (s *persistStore) GetSchema(ctx context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
 	sck := store.SchemaKey{
 		Name:    req.GetSchema().GetName(),
@@ -152,13 +226,25 @@ func (s *persistStore) GetSchemaDetails(ctx context.Context, req *sdcpb.GetSchem
 		Directory: []string{},
 		Exclude:   []string{},
 	}
+	cfg, err := s.getSchemaConfig(store.SchemaKey{
+		Name:    req.GetSchema().GetName(),
+		Vendor:  req.GetSchema().GetVendor(),
+		Version: req.GetSchema().GetVersion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rs.File = cfg["files"]
+	rs.Directory = cfg["directories"]
+	rs.Exclude = cfg["excludes"]
+	return rs, nil
+}
+
+// getSchemaConfig returns the config saved along with schema sck.
+func (s *persistStore) getSchemaConfig(sck store.SchemaKey) (map[string][]string, error) {
+	cfg := map[string][]string{}
 	err := s.db.View(func(txn *badger.Txn) error {
-		k := buildSchemaKey(store.SchemaKey{
-			Name:    req.GetSchema().GetName(),
-			Vendor:  req.GetSchema().GetVendor(),
-			Version: req.GetSchema().GetVersion(),
-		})
-		item, err := txn.Get(k)
+		item, err := txn.Get(buildSchemaKey(sck))
 		if err != nil {
 			return err
 		}
@@ -166,20 +252,12 @@ func (s *persistStore) GetSchemaDetails(ctx context.Context, req *sdcpb.GetSchem
 		if err != nil {
 			return err
 		}
-		cfg := map[string][]string{}
-		err = json.Unmarshal(val, &cfg)
-		if err != nil {
-			return err
-		}
-		rs.File = cfg["files"]
-		rs.Directory = cfg["directories"]
-		rs.Exclude = cfg["excludes"]
-		return nil
+		return json.Unmarshal(val, &cfg)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return rs, nil
+	return cfg, nil
 }
 
 func (s *persistStore) CreateSchema(ctx context.Context, req *sdcpb.CreateSchemaRequest) (*sdcpb.CreateSchemaResponse, error) {
@@ -228,100 +306,812 @@ func (s *persistStore) CreateSchema(ctx context.Context, req *sdcpb.CreateSchema
 }
 
 func (s *persistStore) ReloadSchema(ctx context.Context, req *sdcpb.ReloadSchemaRequest) (*sdcpb.ReloadSchemaResponse, error) {
-	details, err := s.GetSchemaDetails(ctx, &sdcpb.GetSchemaDetailsRequest{
-		Schema: req.GetSchema(),
+	scConfig, err := s.schemaConfig(store.SchemaKey{
+		Name:    req.GetSchema().GetName(),
+		Vendor:  req.GetSchema().GetVendor(),
+		Version: req.GetSchema().GetVersion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the schemas sharing module files with the reloaded one are reloaded along with it,
+	// all of them are parsed before any is replaced so that a parsing failure leaves the store untouched.
+	ls, err := s.ListSchema(ctx, &sdcpb.ListSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	cfgs := make([]*config.SchemaConfig, 0, len(ls.GetSchema()))
+	// module files of the schemas, unknown for the ones stored by a previous version
+	files := make(map[store.SchemaKey][]string, len(ls.GetSchema()))
+	for _, lsc := range ls.GetSchema() {
+		sck := store.SchemaKey{Name: lsc.GetName(), Vendor: lsc.GetVendor(), Version: lsc.GetVersion()}
+		cfg, err := s.schemaConfig(sck)
+		if err != nil {
+			return nil, err
+		}
+		cfgs = append(cfgs, cfg)
+		if raw, err := s.getSchemaConfig(sck); err == nil {
+			files[sck] = raw["module-files"]
+		}
+	}
+	cfgs = append([]*config.SchemaConfig{scConfig}, schema.Dependents(scConfig, cfgs, func(cfg *config.SchemaConfig) []string {
+		return files[store.SchemaKey{Name: cfg.Name, Vendor: cfg.Vendor, Version: cfg.Version}]
+	})...)
+	if len(cfgs) > 1 {
+		log.Infof("reloading schema %s along with %d dependent schema(s)", store.SchemaKey{
+			Name:    scConfig.Name,
+			Vendor:  scConfig.Vendor,
+			Version: scConfig.Version,
+		}, len(cfgs)-1)
+	}
+	// parse
+	scs, err := schema.NewSchemas(cfgs)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "no schema reloaded: %v", err)
+	}
+	// replaced in one batch, a failure leaves all of them as they were
+	now := time.Now()
+	err = s.writeSchemas(scs, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range scs {
+		log.Infof("schema %s saved in %s", sc.UniqueName(""), time.Since(now))
+	}
+	return &sdcpb.ReloadSchemaResponse{}, nil
+}
+
+// schemaConfig rebuilds the config schema sck was created with.
+func (s *persistStore) schemaConfig(sck store.SchemaKey) (*config.SchemaConfig, error) {
+	cfg, err := s.getSchemaConfig(sck)
+	if err != nil {
+		return nil, err
+	}
+	scConfig := &config.SchemaConfig{
+		Name:        sck.Name,
+		Vendor:      sck.Vendor,
+		Version:     sck.Version,
+		Files:       cfg["files"],
+		Directories: cfg["directories"],
+		Excludes:    cfg["excludes"],
+		Deviations:  cfg["deviations"],
+		// keep scanning the metadata if it was done on the first load
+		ScanMetadata: s.hasMetadata(sck),
+	}
+	if lang := cfg["description-language"]; len(lang) > 0 {
+		scConfig.DescriptionLanguage = lang[0]
+	}
+	if profile := cfg["profile"]; len(profile) > 0 {
+		scConfig.Profile = profile[0]
+	}
+	if mode := cfg["features-mode"]; len(mode) > 0 {
+		scConfig.Features = &config.FeaturesPolicy{
+			Mode:    mode[0],
+			Enabled: cfg["features-enabled"],
+		}
+	}
+	if rules, ok := cfg["lint-rules"]; ok {
+		scConfig.Lint = &config.LintConfig{
+			Rules:   rules,
+			Disable: cfg["lint-disable"],
+		}
+		for _, rs := range cfg["lint-severity"] {
+			rule, sev, _ := strings.Cut(rs, "=")
+			if scConfig.Lint.Severity == nil {
+				scConfig.Lint.Severity = make(map[string]string)
+			}
+			scConfig.Lint.Severity[rule] = sev
+		}
+	}
+	if git := cfg["git"]; len(git) == 3 {
+		scConfig.Git = &config.GitSource{
+			URL:       git[0],
+			Ref:       git[1],
+			Directory: git[2],
+		}
+	}
+	if oci := cfg["oci"]; len(oci) == 4 {
+		scConfig.OCI = &config.OCISource{
+			Reference:    oci[0],
+			Username:     oci[1],
+			PasswordFile: oci[2],
+			PlainHTTP:    oci[3] == "true",
+		}
+	}
+	if source := cfg["source"]; len(source) == 1 {
+		scConfig.Source = &config.SourceConfig{Type: source[0]}
+		for _, o := range cfg["source-options"] {
+			k, v, _ := strings.Cut(o, "=")
+			if scConfig.Source.Options == nil {
+				scConfig.Source.Options = make(map[string]string)
+			}
+			scConfig.Source.Options[k] = v
+		}
+	}
+	for _, opt := range cfg["parse-options"] {
+		switch opt {
+		case optIgnoreSubmoduleCircularDependencies:
+			scConfig.IgnoreSubmoduleCircularDependencies = true
+		case optIgnoreDeviateNotSupported:
+			scConfig.IgnoreDeviateNotSupported = true
+		}
+	}
+	return scConfig, nil
+}
+
+const (
+	optIgnoreSubmoduleCircularDependencies = "ignore-submodule-circular-dependencies"
+	optIgnoreDeviateNotSupported           = "ignore-deviate-not-supported"
+)
+
+// parseOptions returns the names of the parser options set in scCfg.
+func parseOptions(scCfg *config.SchemaConfig) []string {
+	opts := make([]string, 0, 2)
+	if scCfg.IgnoreSubmoduleCircularDependencies {
+		opts = append(opts, optIgnoreSubmoduleCircularDependencies)
+	}
+	if scCfg.IgnoreDeviateNotSupported {
+		opts = append(opts, optIgnoreDeviateNotSupported)
+	}
+	return opts
+}
+
+func (s *persistStore) DeleteSchema(ctx context.Context, req *sdcpb.DeleteSchemaRequest) (*sdcpb.DeleteSchemaResponse, error) {
+	reqSchema := req.GetSchema()
+	if reqSchema == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing schema details")
+	}
+	switch {
+	// case req.GetSchema().GetName() == "":
+	// 	return nil, status.Error(codes.InvalidArgument, "missing schema name")
+	case req.GetSchema().GetVendor() == "":
+		return nil, status.Error(codes.InvalidArgument, "missing schema vendor")
+	case req.GetSchema().GetVersion() == "":
+		return nil, status.Error(codes.InvalidArgument, "missing schema version")
+	}
+	schemaKey := store.SchemaKey{Name: reqSchema.Name, Vendor: reqSchema.Vendor, Version: reqSchema.Version}
+	if !s.HasSchema(schemaKey) {
+		return nil, status.Errorf(codes.InvalidArgument, "schema %v does not exist", reqSchema)
+	}
+	err := s.db.DropPrefix(append(schemaKeyPrefixes(schemaKey), schemaKeys(schemaKey)...)...)
+	if err != nil {
+		return nil, err
+	}
+	s.dropCached(schemaKey)
+	s.cacheCounts.Delete(schemaKey)
+	return &sdcpb.DeleteSchemaResponse{}, nil
+}
+
+// schemaKeyPrefixes returns the prefixes of the keys of schema sck
+// that are one per schema node, see schemaKeys for the others.
+func schemaKeyPrefixes(sck store.SchemaKey) [][]byte {
+	return [][]byte{
+		// [1]$Name@$Vendor@$Version:::
+		buildEntryKey(sck, []string{""}),
+		buildWhenKey(sck, ""),
+		buildOrderKey(sck, ""),
+	}
+}
+
+// schemaKeys returns the keys of schema sck that are one per schema.
+func schemaKeys(sck store.SchemaKey) [][]byte {
+	return [][]byte{
+		// [0]$Name@$Vendor@$Version
+		buildSchemaKey(sck),
+		buildMetadataKey(sck),
+		buildCompositionKey(sck),
+		buildLintKey(sck),
+		buildLeafrefsKey(sck),
+		buildSubmodulesKey(sck),
+		buildIdentitiesKey(sck),
+		buildVariantKey(sck),
+		buildDefaultOriginsKey(sck),
+		buildLibraryKey(sck),
+		buildStatsKey(sck),
+		buildAnnotationsKey(sck),
+	}
+}
+
+// dropCached removes the cached GetSchema responses of schema sck.
+func (s *persistStore) dropCached(sck store.SchemaKey) {
+	if s.cache == nil {
+		return
+	}
+	for _, k := range s.cache.Keys() {
+		if k.SchemaKey == sck {
+			s.cache.Delete(k)
+		}
+	}
+}
+
+func (s *persistStore) AddSchema(sc *schema.Schema) error {
+	return s.writeSchemas([]*schema.Schema{sc}, false)
+}
+
+// ReplaceSchema deletes the keys of the stored schema in the write batch
+// storing sc, no request finds the schema missing. Nothing is written
+// if the batch fails to build.
+func (s *persistStore) ReplaceSchema(sc *schema.Schema) error {
+	return s.writeSchemas([]*schema.Schema{sc}, true)
+}
+
+// writeSchemas stores scs in one write batch,
+// in place of the stored ones if replace is set.
+func (s *persistStore) writeSchemas(scs []*schema.Schema, replace bool) error {
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, sc := range scs {
+		if err := s.batchSchema(wb, sc, replace); err != nil {
+			return err
+		}
+	}
+	err := wb.Flush()
+	if err != nil {
+		return err
+	}
+	for _, sc := range scs {
+		if replace {
+			s.dropCached(store.Key(sc))
+		}
+		if !s.keepParsed {
+			sc.Reset()
+		}
+	}
+	return nil
+}
+
+// batchSchema adds the keys of sc to wb.
+func (s *persistStore) batchSchema(wb *badger.WriteBatch, sc *schema.Schema, replace bool) error {
+	sck := store.Key(sc)
+	e, err := sc.GetEntry(nil)
+	if err != nil {
+		return err
+	}
+	if replace {
+		// deleted before the new keys are set, the ones
+		// set again are kept
+		err = s.deleteSchemaKeys(wb, sck)
+		if err != nil {
+			return err
+		}
+	}
+	err = s.addSchemaElem(wb, sc, e)
+	if err != nil {
+		return err
+	}
+	cfg := map[string][]string{
+		"files":       sc.Files(),
+		"directories": sc.Dirs(),
+		"excludes":    sc.Excludes(),
+	}
+	if lang := sc.DescriptionLanguage(); lang != "" {
+		cfg["description-language"] = []string{lang}
+	}
+	if digest := sc.SourcesDigest(); digest != "" {
+		cfg["sources-digest"] = []string{digest}
+	}
+	// tell the schemas reloaded along with this one, see schema.Dependents
+	if mfs := sc.ModuleFiles(); len(mfs) > 0 {
+		cfg["module-files"] = mfs
+	}
+	// the profile excludes are part of the schema excludes, its name is informative
+	if scCfg := sc.Config(); scCfg != nil {
+		if scCfg.Profile != "" {
+			cfg["profile"] = []string{scCfg.Profile}
+		}
+		if len(scCfg.Deviations) > 0 {
+			cfg["deviations"] = scCfg.Deviations
+		}
+		if opts := parseOptions(scCfg); len(opts) > 0 {
+			cfg["parse-options"] = opts
+		}
+		if scCfg.Features != nil {
+			cfg["features-mode"] = []string{scCfg.Features.Mode}
+			cfg["features-enabled"] = scCfg.Features.Enabled
+		}
+		if scCfg.Lint != nil {
+			// set even if empty, all the rules run
+			cfg["lint-rules"] = append([]string{}, scCfg.Lint.Rules...)
+			cfg["lint-disable"] = scCfg.Lint.Disable
+			for rule, sev := range scCfg.Lint.Severity {
+				cfg["lint-severity"] = append(cfg["lint-severity"], rule+"="+sev)
+			}
+		}
+		// reloading the schema fetches the repository again
+		if scCfg.Git != nil {
+			cfg["git"] = []string{scCfg.Git.URL, scCfg.Git.Ref, scCfg.Git.Directory}
+		}
+		if scCfg.OCI != nil {
+			cfg["oci"] = []string{scCfg.OCI.Reference, scCfg.OCI.Username, scCfg.OCI.PasswordFile, strconv.FormatBool(scCfg.OCI.PlainHTTP)}
+		}
+		if scCfg.Source != nil {
+			cfg["source"] = []string{scCfg.Source.Type}
+			for k, v := range scCfg.Source.Options {
+				cfg["source-options"] = append(cfg["source-options"], k+"="+v)
+			}
+		}
+	}
+	err = s.addSchema(wb, sck, cfg)
+	if err != nil {
+		return err
+	}
+	if md := sc.Metadata(); md != nil {
+		err = s.addMetadata(wb, sck, md)
+		if err != nil {
+			return err
+		}
+	}
+	if c := sc.Composition(); c != nil {
+		err = s.addComposition(wb, sck, c)
+		if err != nil {
+			return err
+		}
+	}
+	if lr := sc.Lint(); lr != nil {
+		err = s.addLint(wb, sck, lr)
+		if err != nil {
+			return err
+		}
+	}
+	if ps := sc.OptionalLeafrefs(); len(ps) > 0 {
+		err = s.addLeafrefs(wb, sck, ps)
+		if err != nil {
+			return err
+		}
+	}
+	for p, ws := range sc.Whens() {
+		err = s.addWhen(wb, sck, p, ws)
+		if err != nil {
+			return err
+		}
+	}
+	for p, names := range sc.Orders() {
+		err = s.addOrder(wb, sck, p, names)
+		if err != nil {
+			return err
+		}
+	}
+	if sms := sc.Submodules(); len(sms) > 0 {
+		err = s.addSubmodules(wb, sck, sms)
+		if err != nil {
+			return err
+		}
+	}
+	if ids := sc.Identities(); len(ids) > 0 {
+		err = s.addIdentities(wb, sck, ids)
+		if err != nil {
+			return err
+		}
+	}
+	if as := sc.Annotations(); len(as) > 0 {
+		err = s.addAnnotations(wb, sck, as)
+		if err != nil {
+			return err
+		}
+	}
+	if v := sc.Variant(); v != nil {
+		err = s.addVariant(wb, sck, v)
+		if err != nil {
+			return err
+		}
+	}
+	if dos := sc.DefaultOrigins(); len(dos) > 0 {
+		err = s.addDefaultOrigins(wb, sck, dos)
+		if err != nil {
+			return err
+		}
+	}
+	if lms := sc.Library(); len(lms) > 0 {
+		err = s.addLibrary(wb, sck, lms)
+		if err != nil {
+			return err
+		}
+	}
+	if st := sc.Stats(); st != nil {
+		err = s.addStats(wb, sck, st)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSchemaKeys adds the deletion of the keys of schema sck to wb.
+func (s *persistStore) deleteSchemaKeys(wb *badger.WriteBatch, sck store.SchemaKey) error {
+	for _, k := range schemaKeys(sck) {
+		if err := wb.Delete(k); err != nil {
+			return err
+		}
+	}
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		for _, prefix := range schemaKeyPrefixes(sck) {
+			opts.Prefix = prefix
+			it := txn.NewIterator(opts)
+			for it.Rewind(); it.Valid(); it.Next() {
+				if err := wb.Delete(it.Item().KeyCopy(nil)); err != nil {
+					it.Close()
+					return err
+				}
+			}
+			it.Close()
+		}
+		return nil
+	})
+}
+
+func (s *persistStore) GetSchemaMetadata(ctx context.Context, sck store.SchemaKey) (*schema.Metadata, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var md *schema.Metadata
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildMetadataKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		md = new(schema.Metadata)
+		return json.Unmarshal(val, md)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+func (s *persistStore) GetSchemaComposition(ctx context.Context, sck store.SchemaKey) (*schema.Composition, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	c := new(schema.Composition)
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildCompositionKey(sck))
+		if err != nil {
+			// schemas stored before the composition was reported
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *persistStore) GetSchemaLint(ctx context.Context, sck store.SchemaKey) (*lint.Report, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var lr *lint.Report
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildLintKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		lr = new(lint.Report)
+		return json.Unmarshal(val, lr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lr, nil
+}
+
+func (s *persistStore) GetSchemaOptionalLeafrefs(ctx context.Context, sck store.SchemaKey) ([]string, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var ps []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildLeafrefsKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &ps)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func (s *persistStore) GetSchemaWhen(ctx context.Context, sck store.SchemaKey, p string) (*schema.WhenStatements, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var ws *schema.WhenStatements
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildWhenKey(sck, p))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		ws = new(schema.WhenStatements)
+		return json.Unmarshal(val, ws)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+func (s *persistStore) GetSchemaOrder(ctx context.Context, sck store.SchemaKey, p string) ([]string, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var names []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildOrderKey(sck, p))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &names)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (s *persistStore) GetSchemaSubmodules(ctx context.Context, sck store.SchemaKey) ([]*schema.Submodule, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var sms []*schema.Submodule
+	err := s.db.View(func(txn *badger.Txn) error {
+		var err error
+		sms, err = getSubmodules(txn, sck)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sms, nil
+}
+
+func getSubmodules(txn *badger.Txn, sck store.SchemaKey) ([]*schema.Submodule, error) {
+	item, err := txn.Get(buildSubmodulesKey(sck))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	val, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	var sms []*schema.Submodule
+	err = json.Unmarshal(val, &sms)
+	if err != nil {
+		return nil, err
+	}
+	return sms, nil
+}
+
+func (s *persistStore) GetSchemaIdentities(ctx context.Context, sck store.SchemaKey) ([]*schema.Identity, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var ids []*schema.Identity
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildIdentitiesKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *persistStore) GetSchemaAnnotations(ctx context.Context, sck store.SchemaKey) ([]*schema.Annotation, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var as []*schema.Annotation
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildAnnotationsKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &as)
 	})
 	if err != nil {
 		return nil, err
 	}
-	// parse
-	sc, err := schema.NewSchema(
-		&config.SchemaConfig{
-			Name:        req.GetSchema().GetName(),
-			Vendor:      req.GetSchema().GetVendor(),
-			Version:     req.GetSchema().GetVersion(),
-			Files:       details.GetFile(),
-			Directories: details.GetDirectory(),
-			Excludes:    details.GetExclude(),
-		},
-	)
-	if err != nil {
-		return nil, err
+	return as, nil
+}
+
+func (s *persistStore) GetSchemaVariant(ctx context.Context, sck store.SchemaKey) (*schema.Variant, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
 	}
-	_, err = s.DeleteSchema(ctx, &sdcpb.DeleteSchemaRequest{
-		Schema: req.GetSchema(),
+	var v *schema.Variant
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildVariantKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		v = new(schema.Variant)
+		return json.Unmarshal(val, v)
 	})
 	if err != nil {
 		return nil, err
 	}
-	now := time.Now()
-	err = s.AddSchema(sc)
+	return v, nil
+}
+
+func (s *persistStore) GetSchemaLibrary(ctx context.Context, sck store.SchemaKey) ([]*schema.LibraryModule, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var lms []*schema.LibraryModule
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildLibraryKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &lms)
+	})
 	if err != nil {
 		return nil, err
 	}
-	log.Infof("schema %s saved in %s", sc.UniqueName(""), time.Since(now))
-	return &sdcpb.ReloadSchemaResponse{}, nil
+	return lms, nil
 }
 
-func (s *persistStore) DeleteSchema(ctx context.Context, req *sdcpb.DeleteSchemaRequest) (*sdcpb.DeleteSchemaResponse, error) {
-	reqSchema := req.GetSchema()
-	if reqSchema == nil {
-		return nil, status.Error(codes.InvalidArgument, "missing schema details")
-	}
-	switch {
-	// case req.GetSchema().GetName() == "":
-	// 	return nil, status.Error(codes.InvalidArgument, "missing schema name")
-	case req.GetSchema().GetVendor() == "":
-		return nil, status.Error(codes.InvalidArgument, "missing schema vendor")
-	case req.GetSchema().GetVersion() == "":
-		return nil, status.Error(codes.InvalidArgument, "missing schema version")
-	}
-	schemaKey := store.SchemaKey{Name: reqSchema.Name, Vendor: reqSchema.Vendor, Version: reqSchema.Version}
-	if !s.HasSchema(schemaKey) {
-		return nil, status.Errorf(codes.InvalidArgument, "schema %v does not exist", reqSchema)
+func (s *persistStore) GetSchemaStats(ctx context.Context, sck store.SchemaKey) (*schema.Stats, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
 	}
-	// schemaObjectsPrefix [1]$Name@$Vendor@$Version:::
-	schemaObjectsPrefix := buildEntryKey(schemaKey, []string{""})
-	// schemaPrefix [0]$Name@$Vendor@$Version
-	schemaPrefix := buildSchemaKey(schemaKey)
-	err := s.db.DropPrefix(schemaObjectsPrefix, schemaPrefix)
+	var st *schema.Stats
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildStatsKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		st = new(schema.Stats)
+		return json.Unmarshal(val, st)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &sdcpb.DeleteSchemaResponse{}, nil
+	return st, nil
 }
 
-func (s *persistStore) AddSchema(sc *schema.Schema) error {
-	sck := store.Key(sc)
-	// TODO: delete all
-	e, err := sc.GetEntry(nil)
+func (s *persistStore) GetSchemaDefaultOrigins(ctx context.Context, sck store.SchemaKey) (map[string]*schema.DefaultOrigin, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	var dos map[string]*schema.DefaultOrigin
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(buildDefaultOriginsKey(sck))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(val, &dos)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return dos, nil
+}
 
-	wb := s.db.NewWriteBatch()
-	defer wb.Cancel()
+func (s *persistStore) GetSchemaConfig(ctx context.Context, sck store.SchemaKey) (*config.SchemaConfig, error) {
+	if !s.HasSchema(sck) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
+	}
+	return s.schemaConfig(sck)
+}
 
-	err = s.addSchemaElem(wb, sc, e)
-	if err != nil {
-		return err
+func (s *persistStore) GetSchemaSourcesDigest(ctx context.Context, sck store.SchemaKey) (string, error) {
+	if !s.HasSchema(sck) {
+		return "", status.Errorf(codes.InvalidArgument, "unknown schema %v", sck)
 	}
-	err = s.addSchema(wb, sck,
-		map[string][]string{
-			"files":       sc.Files(),
-			"directories": sc.Dirs(),
-			"excludes":    sc.Excludes(),
-		})
+	cfg, err := s.getSchemaConfig(sck)
 	if err != nil {
-		return err
+		return "", err
+	}
+	// empty for the schemas stored before the digest was recorded
+	if digest := cfg["sources-digest"]; len(digest) > 0 {
+		return digest[0], nil
 	}
+	return "", nil
+}
 
-	err = wb.Flush()
-	if err != nil {
+func (s *persistStore) hasMetadata(sck store.SchemaKey) bool {
+	err := s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(buildMetadataKey(sck))
 		return err
-	}
-	sc.Reset()
-	return nil
+	})
+	return err == nil
 }
 
 func (s *persistStore) GetSchemaElements(ctx context.Context, req *sdcpb.GetSchemaRequest) (chan *sdcpb.SchemaElem, error) {
@@ -459,6 +1249,26 @@ OUTER:
 }
 
 func (s *persistStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest) (*sdcpb.ExpandPathResponse, error) {
+	// final response
+	pRsp := &sdcpb.ExpandPathResponse{
+		Path:  []*sdcpb.Path{},
+		Xpath: []string{},
+	}
+	err := s.ExpandPathFunc(ctx, req, func(p *sdcpb.Path) error {
+		if req.GetXpath() {
+			pRsp.Xpath = append(pRsp.Xpath, utils.ToXPath(p, false))
+			return nil
+		}
+		pRsp.Path = append(pRsp.Path, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pRsp, nil
+}
+
+func (s *persistStore) ExpandPathFunc(ctx context.Context, req *sdcpb.ExpandPathRequest, fn func(*sdcpb.Path) error) error {
 	p := req.GetPath()
 	// does the path exist ?
 	rsp, err := s.GetSchema(ctx, &sdcpb.GetSchemaRequest{
@@ -466,12 +1276,7 @@ func (s *persistStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequ
 		Schema: req.GetSchema(),
 	})
 	if err != nil {
-		return nil, err
-	}
-	// final response
-	pRsp := &sdcpb.ExpandPathResponse{
-		Path:  []*sdcpb.Path{},
-		Xpath: []string{},
+		return err
 	}
 
 	switch rsp := rsp.GetSchema().Schema.(type) {
@@ -496,7 +1301,9 @@ func (s *persistStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequ
 				pp.Elem = make([]*sdcpb.PathElem, 0, 1)
 			}
 			pp.Elem = append(pp.Elem, &sdcpb.PathElem{Name: field.Name})
-			addPath(pRsp, pp, req.GetDataType(), field.IsState, req.GetXpath())
+			if err := addPath(fn, pp, req.GetDataType(), field.IsState); err != nil {
+				return err
+			}
 		}
 		// add leaf-lists
 		for _, lf := range rsp.Container.GetLeaflists() {
@@ -505,7 +1312,9 @@ func (s *persistStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequ
 				pp.Elem = make([]*sdcpb.PathElem, 0, 1)
 			}
 			pp.Elem = append(pp.Elem, &sdcpb.PathElem{Name: lf.Name})
-			addPath(pRsp, pp, req.GetDataType(), lf.IsState, req.GetXpath())
+			if err := addPath(fn, pp, req.GetDataType(), lf.IsState); err != nil {
+				return err
+			}
 		}
 		// add containers(YANG container, list, choice, case,...)
 		for _, child := range rsp.Container.GetChildren() {
@@ -514,47 +1323,37 @@ func (s *persistStore) ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequ
 				pp.Elem = make([]*sdcpb.PathElem, 0, 1)
 			}
 			pp.Elem = append(pp.Elem, &sdcpb.PathElem{Name: child})
-			expRsp, err := s.ExpandPath(ctx, &sdcpb.ExpandPathRequest{
+			err := s.ExpandPathFunc(ctx, &sdcpb.ExpandPathRequest{
 				Path:     pp,
 				Schema:   req.GetSchema(),
 				DataType: req.GetDataType(),
-				Xpath:    req.GetXpath(),
-			})
+			}, fn)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			pRsp.Path = append(pRsp.Path, expRsp.Path...)
-			pRsp.Xpath = append(pRsp.Xpath, expRsp.Xpath...)
 		}
 	case *sdcpb.SchemaElem_Field:
-		addPath(pRsp, p, req.GetDataType(), rsp.Field.IsState, req.GetXpath())
+		return addPath(fn, p, req.GetDataType(), rsp.Field.IsState)
 	case *sdcpb.SchemaElem_Leaflist:
-		addPath(pRsp, p, req.GetDataType(), rsp.Leaflist.IsState, req.GetXpath())
+		return addPath(fn, p, req.GetDataType(), rsp.Leaflist.IsState)
 	}
-	return pRsp, nil
+	return nil
 }
 
-// addPath adds path p to response rsp based on the requested dataType and the schema object isState value
-func addPath(rsp *sdcpb.ExpandPathResponse, p *sdcpb.Path, dt sdcpb.DataType, isState, xpath bool) {
+// addPath calls fn with path p based on the requested dataType and the schema object isState value
+func addPath(fn func(*sdcpb.Path) error, p *sdcpb.Path, dt sdcpb.DataType, isState bool) error {
 	switch dt {
 	case sdcpb.DataType_ALL:
 	case sdcpb.DataType_CONFIG:
 		if isState {
-			return
+			return nil
 		}
 	case sdcpb.DataType_STATE:
 		if !isState {
-			return
+			return nil
 		}
 	}
-	if xpath {
-		rsp.Xpath = append(
-			rsp.Xpath,
-			utils.ToXPath(p, false),
-		)
-		return
-	}
-	rsp.Path = append(rsp.Path, p)
+	return fn(p)
 }
 
 // helpers
@@ -602,6 +1401,209 @@ func (s *persistStore) addSchema(wb *badger.WriteBatch, sck store.SchemaKey, cfg
 	return wb.Set(k, v)
 }
 
+// save schema module metadata with prefix 3
+func (s *persistStore) addMetadata(wb *badger.WriteBatch, sck store.SchemaKey, md *schema.Metadata) error {
+	v, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildMetadataKey(sck), v)
+}
+
+// save schema composition report with prefix 4
+func (s *persistStore) addComposition(wb *badger.WriteBatch, sck store.SchemaKey, c *schema.Composition) error {
+	v, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildCompositionKey(sck), v)
+}
+
+func buildCompositionKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaCompositionPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the schema lint report with prefix 5
+func (s *persistStore) addLint(wb *badger.WriteBatch, sck store.SchemaKey, lr *lint.Report) error {
+	v, err := json.Marshal(lr)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildLintKey(sck), v)
+}
+
+func buildLintKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaLintPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the optional leafref paths with prefix 6
+func (s *persistStore) addLeafrefs(wb *badger.WriteBatch, sck store.SchemaKey, ps []string) error {
+	v, err := json.Marshal(ps)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildLeafrefsKey(sck), v)
+}
+
+func buildLeafrefsKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaLeafrefsPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the when statements of a data node with prefix 7
+func (s *persistStore) addWhen(wb *badger.WriteBatch, sck store.SchemaKey, p string, ws *schema.WhenStatements) error {
+	v, err := json.Marshal(ws)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildWhenKey(sck, p), v)
+}
+
+// buildWhenKey returns the key of the when statements of data path p,
+// the prefix of all the schema data paths if p is empty.
+func buildWhenKey(sck store.SchemaKey, p string) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+len(p)+4)
+	k = append(k, schemaWhenPrefix)
+	k = append(k, schemaKeyString(sck)...)
+	k = append(k, ":::"...)
+	return append(k, p...)
+}
+
+// save the declared order of the children of a data node with prefix 15
+func (s *persistStore) addOrder(wb *badger.WriteBatch, sck store.SchemaKey, p string, names []string) error {
+	v, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildOrderKey(sck, p), v)
+}
+
+// buildOrderKey returns the key of the order of the children of data path p,
+// the prefix of all the schema data paths if p is empty.
+func buildOrderKey(sck store.SchemaKey, p string) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+len(p)+4)
+	k = append(k, schemaOrderPrefix)
+	k = append(k, schemaKeyString(sck)...)
+	k = append(k, ":::"...)
+	return append(k, p...)
+}
+
+// save the submodules with prefix 8
+func (s *persistStore) addSubmodules(wb *badger.WriteBatch, sck store.SchemaKey, sms []*schema.Submodule) error {
+	v, err := json.Marshal(sms)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildSubmodulesKey(sck), v)
+}
+
+func buildSubmodulesKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaSubmodulesPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the identities with prefix 9
+func (s *persistStore) addIdentities(wb *badger.WriteBatch, sck store.SchemaKey, ids []*schema.Identity) error {
+	v, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildIdentitiesKey(sck), v)
+}
+
+func buildIdentitiesKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaIdentitiesPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the metadata annotations with prefix 14
+func (s *persistStore) addAnnotations(wb *badger.WriteBatch, sck store.SchemaKey, as []*schema.Annotation) error {
+	v, err := json.Marshal(as)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildAnnotationsKey(sck), v)
+}
+
+func buildAnnotationsKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaAnnotationsPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the enabled features and the deviation modules with prefix 10
+func (s *persistStore) addVariant(wb *badger.WriteBatch, sck store.SchemaKey, v *schema.Variant) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildVariantKey(sck), b)
+}
+
+func buildVariantKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaVariantPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the origin of the defaults by data path with prefix 11
+func (s *persistStore) addDefaultOrigins(wb *badger.WriteBatch, sck store.SchemaKey, dos map[string]*schema.DefaultOrigin) error {
+	b, err := json.Marshal(dos)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildDefaultOriginsKey(sck), b)
+}
+
+func buildDefaultOriginsKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaDefaultsPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the modules listed by the YANG library with prefix 12
+func (s *persistStore) addLibrary(wb *badger.WriteBatch, sck store.SchemaKey, lms []*schema.LibraryModule) error {
+	b, err := json.Marshal(lms)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildLibraryKey(sck), b)
+}
+
+func buildLibraryKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaLibraryPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+// save the node counts and the parse duration with prefix 13
+func (s *persistStore) addStats(wb *badger.WriteBatch, sck store.SchemaKey, st *schema.Stats) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return wb.Set(buildStatsKey(sck), b)
+}
+
+func buildStatsKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaStatsPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
+func buildMetadataKey(sck store.SchemaKey) []byte {
+	k := make([]byte, 0, len(schemaKeyString(sck))+1)
+	k = append(k, schemaMetadataPrefix)
+	return append(k, schemaKeyString(sck)...)
+}
+
 func buildSchemaKey(sck store.SchemaKey) []byte {
 	k := []byte(schemaKeyString(sck))
 	k = append(k, 0)
@@ -659,11 +1661,15 @@ func reverse(arr []string) {
 	}
 }
 
-func (s *persistStore) openDB(ctx context.Context) (*badger.DB, error) {
-	opts := badger.DefaultOptions(s.path).
+func dbOptions(p string) badger.Options {
+	return badger.DefaultOptions(p).
 		WithLoggingLevel(badger.WARNING).
 		WithCompression(options.None).
 		WithBlockCacheSize(0)
+}
+
+func (s *persistStore) openDB(ctx context.Context) (*badger.DB, error) {
+	opts := dbOptions(s.path)
 
 	ctx, s.cfn = context.WithCancel(ctx)
 
@@ -724,7 +1730,9 @@ func (s *persistStore) getSchema(ctx context.Context, req *sdcpb.GetSchemaReques
 		Path:      strings.Join(pes, "/"),
 	}
 	if s.cache != nil {
-		if item := s.cache.Get(cKey, ttlcache.WithDisableTouchOnHit[cacheKey, *sdcpb.GetSchemaResponse]()); item != nil {
+		item := s.cache.Get(cKey, ttlcache.WithDisableTouchOnHit[cacheKey, *sdcpb.GetSchemaResponse]())
+		s.countCache(sck, item != nil)
+		if item != nil {
 			// clone it
 			rsp := proto.Clone(item.Value()).(*sdcpb.GetSchemaResponse)
 			// apply modifiers
@@ -785,6 +1793,8 @@ func (s *persistStore) getSchema(ctx context.Context, req *sdcpb.GetSchemaReques
 	npe := make([]string, 1+len(pes))
 	copy(npe[1:], pes)
 	err = s.db.View(func(txn *badger.Txn) error {
+		var found []byte
+		candidates := make([]string, 0, 1)
 		for _, module := range modules {
 			var k []byte
 			if npe[1] == module { // query module name
@@ -804,13 +1814,51 @@ func (s *persistStore) getSchema(ctx context.Context, req *sdcpb.GetSchemaReques
 			if err != nil {
 				return err
 			}
-			err = proto.Unmarshal(v, sce)
+			if npe[1] == module {
+				return proto.Unmarshal(v, sce)
+			}
+			// keep looking in the other modules to detect
+			// ambiguous unqualified path elements.
+			found = v
+			candidates = append(candidates, module+":"+npe[1])
+		}
+		switch len(candidates) {
+		case 0:
+			// a submodule name resolves to the module it belongs to
+			name := moduleName
+			if name == "" {
+				name = npe[1]
+			}
+			sms, err := getSubmodules(txn, sck)
 			if err != nil {
 				return err
 			}
-			return nil
+			module := schema.SubmoduleModule(sms, name)
+			if module == "" {
+				return fmt.Errorf("%s: %w", req.GetPath(), ErrKeyNotFound)
+			}
+			var k []byte
+			if moduleName == "" {
+				npe[1] = module
+				k = buildEntryKey(sck, npe[1:])
+			} else {
+				npe[0] = module
+				k = buildEntryKey(sck, npe)
+			}
+			item, err := txn.Get(k)
+			if err != nil {
+				return fmt.Errorf("%s: %w", req.GetPath(), ErrKeyNotFound)
+			}
+			found, err = item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			return proto.Unmarshal(found, sce)
+		case 1:
+			return proto.Unmarshal(found, sce)
 		}
-		return fmt.Errorf("%s: %w", req.GetPath(), ErrKeyNotFound)
+		sort.Strings(candidates)
+		return &schema.AmbiguousPathError{Elem: npe[1], Candidates: candidates}
 	})
 	if err != nil {
 		return nil, err
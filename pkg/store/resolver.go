@@ -0,0 +1,303 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/schema"
+)
+
+// Resolver resolves schema elements of a single schema from a Store
+// and caches them. It is meant to be used for the duration of a request.
+type Resolver struct {
+	store  Store
+	schema *sdcpb.Schema
+	// resolve the elements with their descriptions
+	descriptions bool
+
+	m     *sync.Mutex
+	elems map[string]*sdcpb.SchemaElem
+	// namespace to module name
+	modules map[string]string
+	// declared order of the children by data path
+	orders map[string][]string
+
+	identities []*schema.Identity
+}
+
+func NewResolver(st Store, sc *sdcpb.Schema) *Resolver {
+	return &Resolver{
+		store:  st,
+		schema: sc,
+		m:      new(sync.Mutex),
+		elems:  make(map[string]*sdcpb.SchemaElem),
+		orders: make(map[string][]string),
+	}
+}
+
+func (r *Resolver) Schema() *sdcpb.Schema {
+	return r.schema
+}
+
+// WithDescriptions makes r resolve the elements with their descriptions,
+// it is meant to be called before r is used.
+func (r *Resolver) WithDescriptions() *Resolver {
+	r.descriptions = true
+	return r
+}
+
+// Get returns the schema element found at the path made of the given element names.
+// Only the first name can carry a module prefix.
+func (r *Resolver) Get(ctx context.Context, names []string) (*sdcpb.SchemaElem, error) {
+	p := &sdcpb.Path{Elem: make([]*sdcpb.PathElem, 0, len(names))}
+	for i, name := range names {
+		if idx := strings.Index(name, ":"); i > 0 && idx >= 0 {
+			name = name[idx+1:]
+		}
+		p.Elem = append(p.Elem, &sdcpb.PathElem{Name: name})
+	}
+	k := strings.Join(names, "/")
+	r.m.Lock()
+	se, ok := r.elems[k]
+	r.m.Unlock()
+	if ok {
+		return se, nil
+	}
+	rsp, err := r.store.GetSchema(ctx, &sdcpb.GetSchemaRequest{
+		Schema:          r.schema,
+		Path:            p,
+		WithDescription: r.descriptions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.m.Lock()
+	r.elems[k] = rsp.GetSchema()
+	r.m.Unlock()
+	return rsp.GetSchema(), nil
+}
+
+// GetPath is like Get but takes a path, keys are ignored.
+func (r *Resolver) GetPath(ctx context.Context, p *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	names := make([]string, 0, len(p.GetElem()))
+	for _, pe := range p.GetElem() {
+		names = append(names, pe.GetName())
+	}
+	return r.Get(ctx, names)
+}
+
+// Order returns the names of the child data nodes of the data node at path p
+// in their declared order, see Store.GetSchemaOrder.
+func (r *Resolver) Order(ctx context.Context, p string) ([]string, error) {
+	r.m.Lock()
+	names, ok := r.orders[p]
+	r.m.Unlock()
+	if ok {
+		return names, nil
+	}
+	sc := r.schema
+	names, err := r.store.GetSchemaOrder(ctx, SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()}, p)
+	if err != nil {
+		return nil, err
+	}
+	r.m.Lock()
+	r.orders[p] = names
+	r.m.Unlock()
+	return names, nil
+}
+
+// Identities returns the identities of the schema, see Store.GetSchemaIdentities.
+func (r *Resolver) Identities(ctx context.Context) ([]*schema.Identity, error) {
+	r.m.Lock()
+	ids := r.identities
+	r.m.Unlock()
+	if ids != nil {
+		return ids, nil
+	}
+	sc := r.schema
+	ids, err := r.store.GetSchemaIdentities(ctx, SchemaKey{Name: sc.GetName(), Vendor: sc.GetVendor(), Version: sc.GetVersion()})
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []*schema.Identity{}
+	}
+	r.m.Lock()
+	r.identities = ids
+	r.m.Unlock()
+	return ids, nil
+}
+
+// Keys returns keys, the keys of the list found at names, in the order
+// of its key statement. See Get for names and Order.
+func (r *Resolver) Keys(ctx context.Context, names []string, keys []*sdcpb.LeafSchema) ([]*sdcpb.LeafSchema, error) {
+	if len(keys) < 2 {
+		return keys, nil
+	}
+	p, err := r.dataPath(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	order, err := r.Order(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return SortKeys(keys, order), nil
+}
+
+// dataPath returns the data path of the node found at names, e.g. /interface/subinterface.
+func (r *Resolver) dataPath(ctx context.Context, names []string) (string, error) {
+	if len(names) > 1 && !strings.Contains(names[0], ":") {
+		// the path can start with the module name
+		r.m.Lock()
+		loaded := r.modules != nil
+		r.m.Unlock()
+		if !loaded {
+			err := r.loadModules(ctx)
+			if err != nil {
+				return "", err
+			}
+		}
+		r.m.Lock()
+		for _, m := range r.modules {
+			if m == names[0] {
+				names = names[1:]
+				break
+			}
+		}
+		r.m.Unlock()
+	}
+	sb := new(strings.Builder)
+	for _, name := range names {
+		if idx := strings.Index(name, ":"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		sb.WriteString("/")
+		sb.WriteString(name)
+	}
+	return sb.String(), nil
+}
+
+// SortKeys returns a copy of keys sorted by their position in order,
+// the names of the children of their list, see Store.GetSchemaOrder.
+// The keys missing from order come last.
+func SortKeys(keys []*sdcpb.LeafSchema, order []string) []*sdcpb.LeafSchema {
+	pos := make(map[string]int, len(order))
+	for i, name := range order {
+		pos[name] = i
+	}
+	sorted := append([]*sdcpb.LeafSchema(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, oki := pos[sorted[i].GetName()]
+		pj, okj := pos[sorted[j].GetName()]
+		if oki != okj {
+			return oki
+		}
+		return pi < pj
+	})
+	return sorted
+}
+
+// Module returns the name of the module defining the schema element se.
+func (r *Resolver) Module(ctx context.Context, se *sdcpb.SchemaElem) (string, error) {
+	r.m.Lock()
+	loaded := r.modules != nil
+	r.m.Unlock()
+	if !loaded {
+		err := r.loadModules(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.modules[Namespace(se)], nil
+}
+
+// ModuleNamespace returns the namespace of a module of the schema.
+func (r *Resolver) ModuleNamespace(ctx context.Context, module string) (string, error) {
+	mse, err := r.Get(ctx, []string{module})
+	if err != nil {
+		return "", err
+	}
+	return Namespace(mse), nil
+}
+
+// NamespaceModule returns the module of the schema with namespace ns, empty if none has it.
+func (r *Resolver) NamespaceModule(ctx context.Context, ns string) (string, error) {
+	r.m.Lock()
+	loaded := r.modules != nil
+	r.m.Unlock()
+	if !loaded {
+		err := r.loadModules(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.modules[ns], nil
+}
+
+func (r *Resolver) loadModules(ctx context.Context) error {
+	root, err := r.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	modules := make(map[string]string, len(root.GetContainer().GetChildren()))
+	for _, m := range root.GetContainer().GetChildren() {
+		mse, err := r.Get(ctx, []string{m})
+		if err != nil {
+			return err
+		}
+		modules[Namespace(mse)] = m
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.modules = modules
+	return nil
+}
+
+// Namespace returns the namespace of a schema element.
+func Namespace(se *sdcpb.SchemaElem) string {
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		return se.Container.GetNamespace()
+	case *sdcpb.SchemaElem_Field:
+		return se.Field.GetNamespace()
+	case *sdcpb.SchemaElem_Leaflist:
+		return se.Leaflist.GetNamespace()
+	}
+	return ""
+}
+
+// Name returns the name of a schema element.
+func Name(se *sdcpb.SchemaElem) string {
+	switch se := se.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		return se.Container.GetName()
+	case *sdcpb.SchemaElem_Field:
+		return se.Field.GetName()
+	case *sdcpb.SchemaElem_Leaflist:
+		return se.Leaflist.GetName()
+	}
+	return ""
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shmstore is an experimental read-only schema store backed by
+// snapshot files of the schema trees, written by the schema-server to a
+// shared memory directory (e.g. /dev/shm) and mapped by co-located processes.
+// A data-server running next to the schema-server looks the schema elements
+// up in the mapped file without a gRPC round trip.
+//
+// A snapshot file is made of a header, an index sorted by path and the data:
+//
+//	magic    [8]byte "SDCSHM01"
+//	count    uint32  number of entries
+//	reserved uint32
+//	gen      uint64  generation, the unix time in ns the snapshot was written at
+//	index    count x {pathOff, pathLen, elemOff, elemLen uint32}, offsets from the file start
+//	data     the paths and the protobuf encoded sdcpb.SchemaElem
+//
+// Integers are little endian. The paths are data paths without keys or
+// module prefixes, e.g. /interface/subinterface/index.
+// A snapshot is replaced by renaming a new file over it: a mapped file
+// stays valid until it is closed, Stale reports that a newer one exists.
+package shmstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	magic      = "SDCSHM01"
+	headerSize = 24
+	indexSize  = 16
+	// FileExt is the extension of the snapshot files.
+	FileExt = ".shm"
+)
+
+// ErrNotFound is returned when a path is not in the snapshot.
+var ErrNotFound = errors.New("schema element not found")
+
+// FileName returns the name of the snapshot file of a schema.
+func FileName(name, vendor, version string) string {
+	fn := name + "@" + vendor + "@" + version
+	return strings.ReplaceAll(fn, string(filepath.Separator), "_") + FileExt
+}
+
+// Entry is a schema element of a snapshot.
+type Entry struct {
+	// data path without keys or module prefixes
+	Path string
+	// protobuf encoded sdcpb.SchemaElem
+	Elem []byte
+}
+
+// Write writes a snapshot of the entries with generation gen to w,
+// the entries are sorted by path and must be unique.
+func Write(w io.Writer, gen uint64, entries []*Entry) error {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	size := uint64(headerSize + indexSize*len(entries))
+	for i, e := range entries {
+		if i > 0 && entries[i-1].Path == e.Path {
+			return fmt.Errorf("duplicate path %s", e.Path)
+		}
+		size += uint64(len(e.Path) + len(e.Elem))
+	}
+	if size > math.MaxUint32 {
+		return fmt.Errorf("snapshot too large: %d bytes", size)
+	}
+	buf := make([]byte, headerSize+indexSize*len(entries), size)
+	copy(buf, magic)
+	binary.LittleEndian.PutUint32(buf[8:], uint32(len(entries)))
+	binary.LittleEndian.PutUint64(buf[16:], gen)
+	for i, e := range entries {
+		idx := buf[headerSize+indexSize*i:]
+		binary.LittleEndian.PutUint32(idx, uint32(len(buf)))
+		binary.LittleEndian.PutUint32(idx[4:], uint32(len(e.Path)))
+		buf = append(buf, e.Path...)
+		binary.LittleEndian.PutUint32(idx[8:], uint32(len(buf)))
+		binary.LittleEndian.PutUint32(idx[12:], uint32(len(e.Elem)))
+		buf = append(buf, e.Elem...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// Store is a mapped snapshot, its lookups are safe for concurrent use
+// but not with Close.
+type Store struct {
+	path  string
+	fi    os.FileInfo
+	data  []byte
+	count int
+	gen   uint64
+}
+
+// Open maps the snapshot file at path.
+func Open(path string) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < headerSize {
+		return nil, fmt.Errorf("%s: not a schema snapshot", path)
+	}
+	data, err := mmap(f, int(fi.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	s := &Store{path: path, fi: fi, data: data}
+	if err := s.check(); err != nil {
+		_ = munmap(data)
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+// check validates the header and the index.
+func (s *Store) check() error {
+	if string(s.data[:8]) != magic {
+		return errors.New("not a schema snapshot")
+	}
+	s.count = int(binary.LittleEndian.Uint32(s.data[8:]))
+	s.gen = binary.LittleEndian.Uint64(s.data[16:])
+	if headerSize+indexSize*uint64(s.count) > uint64(len(s.data)) {
+		return errors.New("truncated index")
+	}
+	for i := 0; i < s.count; i++ {
+		po, pl, eo, el := s.entry(i)
+		if uint64(po)+uint64(pl) > uint64(len(s.data)) || uint64(eo)+uint64(el) > uint64(len(s.data)) {
+			return fmt.Errorf("entry %d out of bounds", i)
+		}
+	}
+	return nil
+}
+
+func (s *Store) entry(i int) (pathOff, pathLen, elemOff, elemLen uint32) {
+	idx := s.data[headerSize+indexSize*i:]
+	return binary.LittleEndian.Uint32(idx), binary.LittleEndian.Uint32(idx[4:]),
+		binary.LittleEndian.Uint32(idx[8:]), binary.LittleEndian.Uint32(idx[12:])
+}
+
+func (s *Store) entryPath(i int) string {
+	po, pl, _, _ := s.entry(i)
+	return string(s.data[po : po+pl])
+}
+
+// Lookup returns the protobuf encoded schema element at data path p,
+// e.g. /interface/subinterface, nil if not found.
+// The returned bytes are the mapped file: they must not be modified
+// and are only valid until the Store is closed.
+func (s *Store) Lookup(p string) []byte {
+	i := sort.Search(s.count, func(i int) bool {
+		po, pl, _, _ := s.entry(i)
+		return string(s.data[po:po+pl]) >= p
+	})
+	if i == s.count || s.entryPath(i) != p {
+		return nil
+	}
+	_, _, eo, el := s.entry(i)
+	return s.data[eo : eo+el]
+}
+
+// Get returns the schema element at path p, its keys are ignored
+// and its module prefixes stripped.
+func (s *Store) Get(p *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	var sb strings.Builder
+	for _, pe := range p.GetElem() {
+		name := pe.GetName()
+		if idx := strings.Index(name, ":"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		sb.WriteByte('/')
+		sb.WriteString(name)
+	}
+	b := s.Lookup(sb.String())
+	if b == nil {
+		return nil, fmt.Errorf("%s: %w", sb.String(), ErrNotFound)
+	}
+	se := new(sdcpb.SchemaElem)
+	if err := proto.Unmarshal(b, se); err != nil {
+		return nil, err
+	}
+	return se, nil
+}
+
+// Paths calls fn with the paths of the snapshot in order until it returns false.
+func (s *Store) Paths(fn func(p string) bool) {
+	for i := 0; i < s.count; i++ {
+		if !fn(s.entryPath(i)) {
+			return
+		}
+	}
+}
+
+// Len returns the number of schema elements of the snapshot.
+func (s *Store) Len() int {
+	return s.count
+}
+
+// Generation returns the generation of the snapshot,
+// the unix time in ns it was written at.
+func (s *Store) Generation() uint64 {
+	return s.gen
+}
+
+// Stale reports whether the snapshot file was replaced or removed since
+// it was opened, the Store should then be closed and opened again.
+func (s *Store) Stale() bool {
+	fi, err := os.Stat(s.path)
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(fi, s.fi)
+}
+
+// Close unmaps the snapshot.
+func (s *Store) Close() error {
+	data := s.data
+	s.data, s.count = nil, 0
+	if data == nil {
+		return nil
+	}
+	return munmap(data)
+}
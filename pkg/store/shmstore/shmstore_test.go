@@ -0,0 +1,254 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shmstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// testEntries returns the entries of a snapshot, unsorted.
+func testEntries(t *testing.T) []*Entry {
+	t.Helper()
+	var entries []*Entry
+	for _, name := range []string{"mtu", "name", "index"} {
+		b, err := proto.Marshal(&sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{Name: name}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, &Entry{Path: "/interface/" + name, Elem: b})
+	}
+	return append(entries, &Entry{Path: "/interface", Elem: []byte{}})
+}
+
+// writeFile writes b to a snapshot file and returns its path.
+func writeFile(t *testing.T, b []byte) string {
+	t.Helper()
+	fn := filepath.Join(t.TempDir(), FileName("srl", "nokia", "24.3.1"))
+	if err := os.WriteFile(fn, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
+
+func TestWrite_Open(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, 42, testEntries(t)); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(writeFile(t, buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Len() != 4 || s.Generation() != 42 {
+		t.Errorf("Open() = %d entries of generation %d, want 4 of generation 42", s.Len(), s.Generation())
+	}
+	var paths []string
+	s.Paths(func(p string) bool {
+		paths = append(paths, p)
+		return true
+	})
+	if got, want := strings.Join(paths, " "), "/interface /interface/index /interface/mtu /interface/name"; got != want {
+		t.Errorf("Paths() = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		path string
+		// leaf name, empty if not found
+		want string
+	}{
+		{path: "/interface/mtu", want: "mtu"},
+		{path: "/interface/index", want: "index"},
+		{path: "/interface/name", want: "name"},
+		{path: "/interface/description"},
+		{path: "/a"},
+		{path: "/zzz"},
+		{path: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			b := s.Lookup(tt.path)
+			if (b != nil) != (tt.want != "") {
+				t.Fatalf("Lookup() = %v, want found %v", b, tt.want != "")
+			}
+			if b == nil {
+				return
+			}
+			se := new(sdcpb.SchemaElem)
+			if err := proto.Unmarshal(b, se); err != nil {
+				t.Fatal(err)
+			}
+			if got := se.GetField().GetName(); got != tt.want {
+				t.Errorf("Lookup() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+	if b := s.Lookup("/interface"); b == nil || len(b) != 0 {
+		t.Errorf("Lookup(/interface) = %v, want an empty element", b)
+	}
+	p := &sdcpb.Path{Elem: []*sdcpb.PathElem{{Name: "srl_nokia-interfaces:interface", Key: map[string]string{"name": "e1"}}, {Name: "mtu"}}}
+	if se, err := s.Get(p); err != nil || se.GetField().GetName() != "mtu" {
+		t.Errorf("Get() = %v, %v, want mtu", se, err)
+	}
+	p.Elem[1].Name = "description"
+	if _, err := s.Get(p); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWrite_duplicate(t *testing.T) {
+	entries := append(testEntries(t), &Entry{Path: "/interface/mtu"})
+	if err := Write(&bytes.Buffer{}, 1, entries); err == nil {
+		t.Error("Write() error = nil, want a duplicate path error")
+	}
+}
+
+func TestOpen_invalid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, 1, testEntries(t)); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	// the offset of field off of index entry i
+	idx := func(i, off int) int { return headerSize + indexSize*i + off }
+	tests := []struct {
+		name string
+		// modifies a copy of the valid snapshot
+		modify  func(b []byte) []byte
+		wantErr string
+	}{
+		{
+			name:    "empty",
+			modify:  func(b []byte) []byte { return nil },
+			wantErr: "not a schema snapshot",
+		},
+		{
+			name:    "short header",
+			modify:  func(b []byte) []byte { return b[:headerSize-1] },
+			wantErr: "not a schema snapshot",
+		},
+		{
+			name: "magic",
+			modify: func(b []byte) []byte {
+				copy(b, "SDCSHM00")
+				return b
+			},
+			wantErr: "not a schema snapshot",
+		},
+		{
+			name:    "truncated index",
+			modify:  func(b []byte) []byte { return b[:idx(3, 8)] },
+			wantErr: "truncated index",
+		},
+		{
+			name: "count past the file",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint32(b[8:], 1<<30)
+				return b
+			},
+			wantErr: "truncated index",
+		},
+		{
+			name:    "truncated data",
+			modify:  func(b []byte) []byte { return b[:len(b)-1] },
+			wantErr: "entry 3 out of bounds",
+		},
+		{
+			name: "path out of bounds",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint32(b[idx(1, 0):], uint32(len(b)))
+				return b
+			},
+			wantErr: "entry 1 out of bounds",
+		},
+		{
+			name: "path length out of bounds",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint32(b[idx(0, 4):], uint32(len(b)))
+				return b
+			},
+			wantErr: "entry 0 out of bounds",
+		},
+		{
+			name: "element out of bounds",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint32(b[idx(2, 8):], uint32(len(b))+1)
+				return b
+			},
+			wantErr: "entry 2 out of bounds",
+		},
+		{
+			// the offset and length do not overflow in uint32
+			name: "element length overflow",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint32(b[idx(2, 12):], 0xffffffff)
+				return b
+			},
+			wantErr: "entry 2 out of bounds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.modify(bytes.Clone(valid))
+			s, err := Open(writeFile(t, b))
+			if err == nil {
+				s.Close()
+				t.Fatal("Open() error = nil, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Open() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStore_Stale(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, 1, testEntries(t)); err != nil {
+		t.Fatal(err)
+	}
+	fn := writeFile(t, buf.Bytes())
+	s, err := Open(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Stale() {
+		t.Error("Stale() = true for the opened file")
+	}
+	// replaced by a rename, the mapped snapshot stays readable
+	tmp := fn + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, fn); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Stale() {
+		t.Error("Stale() = false for a replaced file")
+	}
+	if s.Lookup("/interface/mtu") == nil {
+		t.Error("Lookup() = nil after the snapshot was replaced")
+	}
+}
@@ -19,6 +19,8 @@ import (
 
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/lint"
 	"github.com/sdcio/schema-server/pkg/schema"
 )
 
@@ -46,9 +48,54 @@ type Store interface {
 	ReloadSchema(ctx context.Context, req *sdcpb.ReloadSchemaRequest) (*sdcpb.ReloadSchemaResponse, error)
 	DeleteSchema(ctx context.Context, req *sdcpb.DeleteSchemaRequest) (*sdcpb.DeleteSchemaResponse, error)
 	AddSchema(sc *schema.Schema) error
+	// ReplaceSchema stores sc in place of the stored schema of the same key, if any:
+	// the schema does not go missing meanwhile, and is kept if sc can not be stored.
+	ReplaceSchema(sc *schema.Schema) error
+	// GetSchemaMetadata returns the module metadata of a schema,
+	// nil if it was not scanned when the schema was loaded.
+	GetSchemaMetadata(ctx context.Context, scKey SchemaKey) (*schema.Metadata, error)
+	// GetSchemaComposition returns the composition report of a schema.
+	GetSchemaComposition(ctx context.Context, scKey SchemaKey) (*schema.Composition, error)
+	// GetSchemaLint returns the lint report of a schema, nil if it is not linted.
+	GetSchemaLint(ctx context.Context, scKey SchemaKey) (*lint.Report, error)
+	// GetSchemaOptionalLeafrefs returns the data paths of the leafrefs
+	// of a schema set to require-instance false.
+	GetSchemaOptionalLeafrefs(ctx context.Context, scKey SchemaKey) ([]string, error)
+	// GetSchemaWhen returns the when statements of the data node of a schema
+	// at data path p, e.g. /interface/subinterface, nil if it has none.
+	GetSchemaWhen(ctx context.Context, scKey SchemaKey, p string) (*schema.WhenStatements, error)
+	// GetSchemaOrder returns the names of the child data nodes of the data node of a schema
+	// at path p in their declared order, nil if unknown. See schema.Schema.Order.
+	GetSchemaOrder(ctx context.Context, scKey SchemaKey, p string) ([]string, error)
+	// GetSchemaSubmodules returns the submodules of a schema sorted by name.
+	GetSchemaSubmodules(ctx context.Context, scKey SchemaKey) ([]*schema.Submodule, error)
+	// GetSchemaIdentities returns the identities of a schema sorted by module qualified name.
+	GetSchemaIdentities(ctx context.Context, scKey SchemaKey) ([]*schema.Identity, error)
+	// GetSchemaAnnotations returns the metadata annotations of a schema sorted by module qualified name.
+	GetSchemaAnnotations(ctx context.Context, scKey SchemaKey) ([]*schema.Annotation, error)
+	// GetSchemaVariant returns the enabled features and the deviation modules of a schema.
+	GetSchemaVariant(ctx context.Context, scKey SchemaKey) (*schema.Variant, error)
+	// GetSchemaLibrary returns the modules of a schema sorted by name,
+	// with their features, deviations and submodules.
+	GetSchemaLibrary(ctx context.Context, scKey SchemaKey) ([]*schema.LibraryModule, error)
+	// GetSchemaDefaultOrigins returns the origin of the defaults of the leaves and
+	// leaf-lists of a schema by data path, e.g. /interface/config/mtu.
+	GetSchemaDefaultOrigins(ctx context.Context, scKey SchemaKey) (map[string]*schema.DefaultOrigin, error)
+	// GetSchemaStats returns the node counts and the parse duration of a schema,
+	// nil if unknown, e.g. for a schema stored by a previous server version.
+	GetSchemaStats(ctx context.Context, scKey SchemaKey) (*schema.Stats, error)
+	// GetSchemaSourcesDigest returns the digest of the source files of a schema
+	// when it was loaded, empty if unknown. See schema.SourcesDigest.
+	GetSchemaSourcesDigest(ctx context.Context, scKey SchemaKey) (string, error)
+	// GetSchemaConfig returns the config a schema was loaded from,
+	// the schema is parsed again from it.
+	GetSchemaConfig(ctx context.Context, scKey SchemaKey) (*config.SchemaConfig, error)
 
 	GetSchema(ctx context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error)
 	GetSchemaElements(ctx context.Context, req *sdcpb.GetSchemaRequest) (chan *sdcpb.SchemaElem, error)
 	ToPath(ctx context.Context, req *sdcpb.ToPathRequest) (*sdcpb.ToPathResponse, error)
 	ExpandPath(ctx context.Context, req *sdcpb.ExpandPathRequest) (*sdcpb.ExpandPathResponse, error)
+	// ExpandPathFunc calls fn for each path ExpandPath returns, as it is found.
+	// The Xpath field of the request is ignored.
+	ExpandPathFunc(ctx context.Context, req *sdcpb.ExpandPathRequest, fn func(*sdcpb.Path) error) error
 }
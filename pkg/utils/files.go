@@ -0,0 +1,103 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// IsYANGFile reports whether p has a .yang extension, in any case.
+func IsYANGFile(p string) bool {
+	return strings.EqualFold(filepath.Ext(p), ".yang")
+}
+
+// WalkFiles calls fn for each regular file found under root, root included.
+// Unlike filepath.Walk, symlinked directories are followed,
+// a directory reached twice through symlinks is walked once.
+func WalkFiles(root string, fn func(path string) error) error {
+	return walkFiles(root, make(map[string]struct{}), fn)
+}
+
+func walkFiles(p string, visited map[string]struct{}, fn func(path string) error) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if fi.Mode().IsRegular() {
+		return fn(p)
+	}
+	if !fi.IsDir() {
+		return nil
+	}
+	rp, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return err
+	}
+	rp = PathKey(rp)
+	if _, ok := visited[rp]; ok {
+		return nil
+	}
+	visited[rp] = struct{}{}
+	des, err := os.ReadDir(p)
+	if err != nil {
+		return err
+	}
+	for _, de := range des {
+		err = walkFiles(filepath.Join(p, de.Name()), visited, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PathKey returns a form of the absolute path p suited for comparisons:
+// symlinks resolved if it exists, and case folded on the platforms
+// with case-insensitive file systems by default (windows and macOS).
+func PathKey(p string) string {
+	if ap, err := filepath.Abs(p); err == nil {
+		p = ap
+	}
+	if rp, err := filepath.EvalSymlinks(p); err == nil {
+		p = rp
+	}
+	p = filepath.Clean(p)
+	switch runtime.GOOS {
+	case "windows", "darwin":
+		return strings.ToLower(p)
+	}
+	return p
+}
+
+// SlashPath returns p with forward slashes and without volume name,
+// the portable form of a file name sent to a server.
+func SlashPath(p string) string {
+	return filepath.ToSlash(strings.TrimPrefix(p, filepath.VolumeName(p)))
+}
+
+// JoinUnder joins the slash separated relative path name to dir,
+// it fails if the result is not under dir.
+func JoinUnder(dir, name string) (string, error) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is not under %q", name, dir)
+	}
+	return p, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestJoinUnder(t *testing.T) {
+	dir := filepath.FromSlash("/srv/schemas")
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{name: "file", file: "a.yang", want: "/srv/schemas/a.yang"},
+		{name: "sub directory", file: "models/a.yang", want: "/srv/schemas/models/a.yang"},
+		{name: "cleaned", file: "models/../a.yang", want: "/srv/schemas/a.yang"},
+		{name: "absolute", file: "/etc/passwd", want: "/srv/schemas/etc/passwd"},
+		{name: "dir itself", file: ".", want: "/srv/schemas"},
+		{name: "parent", file: "..", wantErr: true},
+		{name: "escaping", file: "../../etc/passwd", wantErr: true},
+		{name: "escaping absolute", file: "/../../etc/passwd", wantErr: true},
+		{name: "sibling with the dir prefix", file: "../schemas-other/a.yang", wantErr: true},
+		{name: "file starting with dots", file: "..a.yang", want: "/srv/schemas/..a.yang"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JoinUnder(dir, tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JoinUnder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != filepath.FromSlash(tt.want) {
+				t.Errorf("JoinUnder() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NearestVersion returns the version among candidates closest to version, "" if there are none.
+// Versions are compared component by component, components being separated by dots or dashes:
+// the candidate sharing the longest prefix with version is the closest, then the one
+// with the smallest difference on the first component differing.
+// On a tie the lower version is returned.
+func NearestVersion(version string, candidates []string) string {
+	vc := versionComponents(version)
+	var nearest string
+	var bestPrefix int
+	var bestDiff uint64
+	for _, c := range candidates {
+		cc := versionComponents(c)
+		prefix := 0
+		for prefix < len(vc) && prefix < len(cc) && vc[prefix] == cc[prefix] {
+			prefix++
+		}
+		diff := componentDiff(vc, cc, prefix)
+		switch {
+		case nearest == "",
+			prefix > bestPrefix,
+			prefix == bestPrefix && diff < bestDiff,
+			prefix == bestPrefix && diff == bestDiff && CompareVersions(c, nearest) < 0:
+			nearest, bestPrefix, bestDiff = c, prefix, diff
+		}
+	}
+	return nearest
+}
+
+// CompareVersions compares the versions a and b component by component,
+// numeric components are compared as numbers.
+// It returns -1 if a is lower than b, 1 if it is greater and 0 if they are equal.
+func CompareVersions(a, b string) int {
+	ac, bc := versionComponents(a), versionComponents(b)
+	for i := 0; i < len(ac) && i < len(bc); i++ {
+		if ac[i] == bc[i] {
+			continue
+		}
+		an, aerr := strconv.ParseUint(ac[i], 10, 64)
+		bn, berr := strconv.ParseUint(bc[i], 10, 64)
+		switch {
+		case aerr == nil && berr == nil && an < bn,
+			(aerr != nil || berr != nil) && ac[i] < bc[i]:
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case len(ac) < len(bc):
+		return -1
+	case len(ac) > len(bc):
+		return 1
+	}
+	return 0
+}
+
+func versionComponents(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '-'
+	})
+}
+
+// componentDiff returns the distance between the component i of a and b,
+// non numeric or missing components are the farthest.
+func componentDiff(a, b []string, i int) uint64 {
+	if i >= len(a) || i >= len(b) {
+		if len(a) == len(b) {
+			return 0
+		}
+		return ^uint64(0)
+	}
+	an, aerr := strconv.ParseUint(a[i], 10, 64)
+	bn, berr := strconv.ParseUint(b[i], 10, 64)
+	if aerr != nil || berr != nil {
+		return ^uint64(0)
+	}
+	if an > bn {
+		return an - bn
+	}
+	return bn - an
+}
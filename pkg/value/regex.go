@@ -0,0 +1,348 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package value
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// compiled patterns by YANG pattern
+var patterns sync.Map
+
+type compiled struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// CompilePattern returns the regular expression of a YANG pattern, a W3C XML Schema
+// regular expression. The patterns are compiled once, later calls get the cached result.
+func CompilePattern(p string) (*regexp.Regexp, error) {
+	if c, ok := patterns.Load(p); ok {
+		return c.(*compiled).re, c.(*compiled).err
+	}
+	c := new(compiled)
+	expr, err := TranslatePattern(p)
+	if err == nil {
+		c.re, err = regexp.Compile(expr)
+	}
+	if err != nil {
+		c.err = fmt.Errorf("pattern %q: %v", p, err)
+	}
+	patterns.Store(p, c)
+	return c.re, c.err
+}
+
+// XML Schema multi-character escapes, as character class items
+var classEscapes = map[byte]string{
+	// whitespace
+	's': `\t\n\r `,
+	// name start and name characters
+	'i': `:A-Z_a-z\x{C0}-\x{D6}\x{D8}-\x{F6}\x{F8}-\x{2FF}\x{370}-\x{37D}\x{37F}-\x{1FFF}\x{200C}-\x{200D}` +
+		`\x{2070}-\x{218F}\x{2C00}-\x{2FEF}\x{3001}-\x{D7FF}\x{F900}-\x{FDCF}\x{FDF0}-\x{FFFD}`,
+	'c': `\-.0-9:A-Z_a-z\x{B7}\x{C0}-\x{D6}\x{D8}-\x{F6}\x{F8}-\x{37D}\x{37F}-\x{1FFF}\x{200C}-\x{200D}` +
+		`\x{203F}-\x{2040}\x{2070}-\x{218F}\x{2C00}-\x{2FEF}\x{3001}-\x{D7FF}\x{F900}-\x{FDCF}\x{FDF0}-\x{FFFD}`,
+	// decimal digits, all the scripts
+	'd': `\p{Nd}`,
+	// all the characters but punctuation, separators and others
+	'w': `\p{L}\p{M}\p{N}\p{S}`,
+}
+
+// Unicode blocks of the \p{IsBlock} escapes
+var blocks = map[string]string{
+	"BasicLatin":                  `\x00-\x7F`,
+	"Latin-1Supplement":           `\x{80}-\x{FF}`,
+	"LatinExtended-A":             `\x{100}-\x{17F}`,
+	"LatinExtended-B":             `\x{180}-\x{24F}`,
+	"IPAExtensions":               `\x{250}-\x{2AF}`,
+	"SpacingModifierLetters":      `\x{2B0}-\x{2FF}`,
+	"CombiningDiacriticalMarks":   `\x{300}-\x{36F}`,
+	"Greek":                       `\x{370}-\x{3FF}`,
+	"Cyrillic":                    `\x{400}-\x{4FF}`,
+	"Armenian":                    `\x{530}-\x{58F}`,
+	"Hebrew":                      `\x{590}-\x{5FF}`,
+	"Arabic":                      `\x{600}-\x{6FF}`,
+	"LatinExtendedAdditional":     `\x{1E00}-\x{1EFF}`,
+	"GreekExtended":               `\x{1F00}-\x{1FFF}`,
+	"GeneralPunctuation":          `\x{2000}-\x{206F}`,
+	"CurrencySymbols":             `\x{20A0}-\x{20CF}`,
+	"LetterlikeSymbols":           `\x{2100}-\x{214F}`,
+	"NumberForms":                 `\x{2150}-\x{218F}`,
+	"Arrows":                      `\x{2190}-\x{21FF}`,
+	"MathematicalOperators":       `\x{2200}-\x{22FF}`,
+	"BoxDrawing":                  `\x{2500}-\x{257F}`,
+	"CJKSymbolsandPunctuation":    `\x{3000}-\x{303F}`,
+	"Hiragana":                    `\x{3040}-\x{309F}`,
+	"Katakana":                    `\x{30A0}-\x{30FF}`,
+	"CJKUnifiedIdeographs":        `\x{4E00}-\x{9FFF}`,
+	"HangulSyllables":             `\x{AC00}-\x{D7A3}`,
+	"PrivateUse":                  `\x{E000}-\x{F8FF}`,
+	"AlphabeticPresentationForms": `\x{FB00}-\x{FB4F}`,
+	"HalfwidthandFullwidthForms":  `\x{FF00}-\x{FFEF}`,
+	"Specials":                    `\x{FFF0}-\x{FFFF}`,
+}
+
+// TranslatePattern translates a W3C XML Schema regular expression, the syntax of YANG patterns,
+// to an anchored Go regular expression. The differences handled are:
+//   - the expression matches the whole value, ^ and $ are not anchors.
+//   - . does not match \r, \s is limited to XML whitespace, \d and \w are not limited to ASCII.
+//   - the \i, \I, \c and \C name characters escapes and the \p{IsBlock} Unicode block escapes.
+//   - character class subtractions, e.g. [a-z-[aeiou]].
+func TranslatePattern(p string) (string, error) {
+	t := &translator{s: p}
+	sb := new(strings.Builder)
+	sb.WriteString(`^(?:`)
+	for t.i < len(t.s) {
+		c := t.s[t.i]
+		switch c {
+		case '[':
+			cls, err := t.class()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(cls)
+			continue
+		case '\\':
+			item, err := t.escape(false)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(item)
+			continue
+		case '.':
+			sb.WriteString(`[^\n\r]`)
+		case '^', '$':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '(':
+			if t.i+1 < len(t.s) && t.s[t.i+1] == '?' {
+				return "", fmt.Errorf("unexpected \"(?\" at %d", t.i)
+			}
+			sb.WriteByte(c)
+		case ']':
+			return "", fmt.Errorf("unexpected \"]\" at %d", t.i)
+		default:
+			sb.WriteByte(c)
+		}
+		t.i++
+	}
+	sb.WriteString(`)$`)
+	return sb.String(), nil
+}
+
+type translator struct {
+	s string
+	i int
+}
+
+// escape translates the escape at the current position, as a character class item
+// if inClass is set, the position is moved after it.
+func (t *translator) escape(inClass bool) (string, error) {
+	start := t.i
+	if t.i+1 >= len(t.s) {
+		return "", fmt.Errorf("trailing \\ at %d", start)
+	}
+	c := t.s[t.i+1]
+	t.i += 2
+	switch c {
+	case 'n', 'r', 't', '\\', '|', '.', '?', '*', '+', '(', ')', '{', '}', '-', '[', ']', '^', '$':
+		return `\` + string(c), nil
+	case 's', 'i', 'c', 'd', 'w':
+		return wrap(classEscapes[c], inClass), nil
+	case 'S', 'I', 'C', 'D', 'W':
+		cls, err := complement(classEscapes[c+'a'-'A'])
+		if err != nil {
+			return "", err
+		}
+		return wrap(cls, inClass), nil
+	case 'p', 'P':
+		end := strings.IndexByte(t.s[t.i:], '}')
+		if t.i >= len(t.s) || t.s[t.i] != '{' || end < 0 {
+			return "", fmt.Errorf("invalid \\%c escape at %d", c, start)
+		}
+		name := t.s[t.i+1 : t.i+end]
+		t.i += end + 1
+		var cls string
+		if strings.HasPrefix(name, "Is") {
+			r, ok := blocks[name[2:]]
+			if !ok {
+				return "", fmt.Errorf("unsupported Unicode block %q at %d", name[2:], start)
+			}
+			cls = r
+		} else {
+			if _, ok := unicode.Categories[name]; !ok {
+				return "", fmt.Errorf("unknown Unicode category %q at %d", name, start)
+			}
+			cls = `\p{` + name + `}`
+		}
+		if c == 'P' {
+			var err error
+			cls, err = complement(cls)
+			if err != nil {
+				return "", err
+			}
+		}
+		return wrap(cls, inClass), nil
+	}
+	return "", fmt.Errorf("invalid escape \\%c at %d", c, start)
+}
+
+func wrap(cls string, inClass bool) string {
+	if inClass {
+		return cls
+	}
+	return "[" + cls + "]"
+}
+
+// class translates the character class at the current position,
+// the position is moved after it.
+func (t *translator) class() (string, error) {
+	start := t.i
+	t.i++
+	sb := new(strings.Builder)
+	sb.WriteByte('[')
+	if t.i < len(t.s) && t.s[t.i] == '^' {
+		sb.WriteByte('^')
+		t.i++
+	}
+	items := 0
+	for {
+		if t.i >= len(t.s) {
+			return "", fmt.Errorf("unterminated character class at %d", start)
+		}
+		c := t.s[t.i]
+		switch {
+		case c == ']':
+			if items == 0 {
+				return "", fmt.Errorf("empty character class at %d", start)
+			}
+			t.i++
+			sb.WriteByte(']')
+			return sb.String(), nil
+		case c == '-' && t.i+1 < len(t.s) && t.s[t.i+1] == '[':
+			// subtraction, the last part of the class
+			t.i++
+			sub, err := t.class()
+			if err != nil {
+				return "", err
+			}
+			if t.i >= len(t.s) || t.s[t.i] != ']' {
+				return "", fmt.Errorf("character class subtraction must end the class at %d", start)
+			}
+			t.i++
+			sb.WriteByte(']')
+			return subtract(sb.String(), sub)
+		case c == '\\':
+			item, err := t.escape(true)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(item)
+		case c == '[':
+			return "", fmt.Errorf("unescaped [ in character class at %d", t.i)
+		default:
+			// a literal character or a range bound, multi-byte characters are copied as is
+			if c == '^' || c == '&' || c == '~' || c == ':' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(c)
+			t.i++
+		}
+		items++
+	}
+}
+
+// ranges returns the rune ranges of character class cls.
+func ranges(cls string) ([]rune, error) {
+	re, err := syntax.Parse(cls, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	switch re.Op {
+	case syntax.OpCharClass:
+		return re.Rune, nil
+	case syntax.OpLiteral:
+		rs := make([]rune, 0, 2*len(re.Rune))
+		for _, r := range re.Rune {
+			rs = append(rs, r, r)
+		}
+		return rs, nil
+	case syntax.OpAnyChar:
+		return []rune{0, unicode.MaxRune}, nil
+	case syntax.OpAnyCharNotNL:
+		return []rune{0, '\n' - 1, '\n' + 1, unicode.MaxRune}, nil
+	case syntax.OpNoMatch:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected character class %s", cls)
+}
+
+// classItems returns rune ranges rs as character class items.
+func classItems(rs []rune) string {
+	sb := new(strings.Builder)
+	for i := 0; i+1 < len(rs); i += 2 {
+		fmt.Fprintf(sb, `\x{%X}`, rs[i])
+		if rs[i+1] != rs[i] {
+			fmt.Fprintf(sb, `-\x{%X}`, rs[i+1])
+		}
+	}
+	return sb.String()
+}
+
+// complement returns the class items matching the characters not matched by items.
+func complement(items string) (string, error) {
+	rs, err := ranges("[^" + items + "]")
+	if err != nil {
+		return "", err
+	}
+	return classItems(rs), nil
+}
+
+// subtract returns the character class matching the characters of class a not in class b.
+func subtract(a, b string) (string, error) {
+	ra, err := ranges(a)
+	if err != nil {
+		return "", err
+	}
+	rb, err := ranges(b)
+	if err != nil {
+		return "", err
+	}
+	var rs []rune
+	for i := 0; i+1 < len(ra); i += 2 {
+		lo, hi := ra[i], ra[i+1]
+		for j := 0; j+1 < len(rb) && lo <= hi; j += 2 {
+			blo, bhi := rb[j], rb[j+1]
+			if bhi < lo || blo > hi {
+				continue
+			}
+			if blo > lo {
+				rs = append(rs, lo, blo-1)
+			}
+			lo = bhi + 1
+		}
+		if lo <= hi {
+			rs = append(rs, lo, hi)
+		}
+	}
+	if len(rs) == 0 {
+		return `[^\x00-\x{10FFFF}]`, nil
+	}
+	return "[" + classItems(rs) + "]", nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package value checks raw values against the constraints of YANG leaf types:
+// their lexical form and the ranges, lengths, patterns, enumerations and identities
+// allowed by the schema.
+package value
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// constraints a value can violate
+const (
+	TypeConstraint        = "type"
+	RangeConstraint       = "range"
+	LengthConstraint      = "length"
+	PatternConstraint     = "pattern"
+	EnumerationConstraint = "enumeration"
+	IdentityConstraint    = "identity"
+	BitsConstraint        = "bits"
+	PathConstraint        = "path"
+)
+
+// Violation is a constraint of a type a value does not satisfy.
+type Violation struct {
+	Constraint string
+	// the constraint statement, e.g. the range or the pattern
+	Statement string
+	// name of the type defining the constraint
+	Type    string
+	Message string
+}
+
+// Checker checks raw values against leaf types.
+type Checker struct {
+	// Leafref returns the type of the target of a leafref type,
+	// nil if it cannot be resolved. Leafref values are not checked if it is not set.
+	Leafref func(*sdcpb.SchemaLeafType) *sdcpb.SchemaLeafType
+	// InstanceIdentifier checks the schema path of an instance-identifier value,
+	// the values are only parsed if it is not set.
+	InstanceIdentifier func(*sdcpb.Path) error
+}
+
+var decimalRegexp = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// Check checks the raw value v against type t. It returns the type accepting v,
+// the union member type for a union, or the violations found. A union value gets
+// the violations of all its member types.
+func (c *Checker) Check(t *sdcpb.SchemaLeafType, v string) (*sdcpb.SchemaLeafType, []*Violation) {
+	switch t.GetType() {
+	case "union":
+		var vs []*Violation
+		for _, ut := range t.GetUnionTypes() {
+			mt, mvs := c.Check(ut, v)
+			if mt != nil {
+				return mt, nil
+			}
+			vs = append(vs, mvs...)
+		}
+		return nil, vs
+	case "leafref":
+		if c.Leafref == nil {
+			return t, nil
+		}
+		rt := c.Leafref(t)
+		if rt == nil {
+			return t, nil
+		}
+		if _, vs := c.Check(rt, v); len(vs) > 0 {
+			return nil, vs
+		}
+		return t, nil
+	}
+	if vl := c.check(t, v); vl != nil {
+		vl.Type = TypeName(t)
+		return nil, []*Violation{vl}
+	}
+	return t, nil
+}
+
+// TypeName returns the name of type t, its typedef name if it has one.
+func TypeName(t *sdcpb.SchemaLeafType) string {
+	if t.GetTypeName() != "" {
+		return t.GetTypeName()
+	}
+	return t.GetType()
+}
+
+func (c *Checker) check(t *sdcpb.SchemaLeafType, v string) *Violation {
+	switch typ := t.GetType(); typ {
+	case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+		var err error
+		if strings.HasPrefix(typ, "u") {
+			_, err = strconv.ParseUint(strings.TrimPrefix(v, "+"), 10, bitSize(typ))
+		} else {
+			_, err = strconv.ParseInt(v, 10, bitSize(typ))
+		}
+		if err != nil {
+			return &Violation{Constraint: TypeConstraint, Message: fmt.Sprintf("invalid %s value %q", typ, v)}
+		}
+		return checkRange(t, v)
+	case "decimal64":
+		if !decimalRegexp.MatchString(v) {
+			return &Violation{Constraint: TypeConstraint, Message: fmt.Sprintf("invalid %s value %q", typ, v)}
+		}
+		return checkRange(t, v)
+	case "boolean":
+		if v != "true" && v != "false" {
+			return &Violation{Constraint: TypeConstraint, Message: fmt.Sprintf("invalid %s value %q, expecting true or false", typ, v)}
+		}
+	case "empty":
+		if v != "" {
+			return &Violation{Constraint: TypeConstraint, Message: fmt.Sprintf("an empty leaf takes no value, got %q", v)}
+		}
+	case "enumeration":
+		for _, ev := range t.GetValues() {
+			if ev == v {
+				return nil
+			}
+		}
+		return &Violation{
+			Constraint: EnumerationConstraint,
+			Statement:  strings.Join(t.GetValues(), "|"),
+			Message:    fmt.Sprintf("invalid enumeration value %q, expecting one of %s", v, strings.Join(t.GetValues(), ", ")),
+		}
+	case "identityref":
+		// the value can be qualified with a module name or a prefix
+		name := v
+		if idx := strings.Index(v, ":"); idx >= 0 {
+			name = v[idx+1:]
+		}
+		for _, id := range t.GetValues() {
+			if id == name {
+				return nil
+			}
+		}
+		return &Violation{
+			Constraint: IdentityConstraint,
+			Statement:  strings.Join(t.GetValues(), "|"),
+			Message:    fmt.Sprintf("identity %q is not derived from the base identity of the type", v),
+		}
+	case "bits":
+		seen := make(map[string]bool)
+		for _, b := range strings.Fields(v) {
+			if seen[b] {
+				return &Violation{Constraint: BitsConstraint, Message: fmt.Sprintf("bit %q is set more than once", b)}
+			}
+			seen[b] = true
+		}
+	case "instance-identifier":
+		p, err := utils.ParsePath(v)
+		if err == nil && len(p.GetElem()) == 0 {
+			err = fmt.Errorf("empty path")
+		}
+		if err == nil && c.InstanceIdentifier != nil {
+			err = c.InstanceIdentifier(p)
+		}
+		if err != nil {
+			return &Violation{Constraint: PathConstraint, Message: fmt.Sprintf("invalid instance-identifier %q: %v", v, err)}
+		}
+	case "binary":
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return &Violation{Constraint: TypeConstraint, Message: fmt.Sprintf("invalid base64 value: %v", err)}
+		}
+		return checkLength(t, len(b), "bytes")
+	case "string":
+		if vl := checkLength(t, utf8.RuneCountInString(v), "characters"); vl != nil {
+			return vl
+		}
+		for _, pat := range t.GetPatterns() {
+			re, err := CompilePattern(pat.GetPattern())
+			if err != nil {
+				return &Violation{
+					Constraint: PatternConstraint,
+					Statement:  pat.GetPattern(),
+					Message:    fmt.Sprintf("the pattern cannot be checked: %v", err),
+				}
+			}
+			if re.MatchString(v) == pat.GetInverted() {
+				msg := "value does not match the pattern"
+				if pat.GetInverted() {
+					msg = "value matches the invert-match pattern"
+				}
+				return &Violation{Constraint: PatternConstraint, Statement: pat.GetPattern(), Message: msg}
+			}
+		}
+	}
+	return nil
+}
+
+func bitSize(typ string) int {
+	switch typ {
+	case "int8", "uint8":
+		return 8
+	case "int16", "uint16":
+		return 16
+	case "int32", "uint32":
+		return 32
+	}
+	return 64
+}
+
+// inRanges reports whether n is in one of the ranges of expression expr,
+// e.g. 1..10|20|30..max. Invalid bounds are ignored.
+func inRanges(expr string, n *big.Rat) bool {
+	for _, r := range strings.Split(expr, "|") {
+		lo, hi, ok := strings.Cut(strings.TrimSpace(r), "..")
+		if !ok {
+			hi = lo
+		}
+		if b, ok := bound(lo); ok && n.Cmp(b) < 0 {
+			continue
+		}
+		if b, ok := bound(hi); ok && n.Cmp(b) > 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// bound returns the number of a range bound, false for min, max or an invalid one.
+func bound(s string) (*big.Rat, bool) {
+	s = strings.TrimSpace(s)
+	if s == "min" || s == "max" {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+func checkRange(t *sdcpb.SchemaLeafType, v string) *Violation {
+	if t.GetRange() == "" {
+		return nil
+	}
+	n, ok := new(big.Rat).SetString(strings.TrimPrefix(v, "+"))
+	if !ok || inRanges(t.GetRange(), n) {
+		return nil
+	}
+	return &Violation{
+		Constraint: RangeConstraint,
+		Statement:  t.GetRange(),
+		Message:    fmt.Sprintf("value %s is out of range %s", v, t.GetRange()),
+	}
+}
+
+func checkLength(t *sdcpb.SchemaLeafType, n int, unit string) *Violation {
+	if t.GetLength() == "" || inRanges(t.GetLength(), new(big.Rat).SetInt64(int64(n))) {
+		return nil
+	}
+	return &Violation{
+		Constraint: LengthConstraint,
+		Statement:  t.GetLength(),
+		Message:    fmt.Sprintf("length %d %s is out of range %s", n, unit, t.GetLength()),
+	}
+}
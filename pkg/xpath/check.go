@@ -0,0 +1,486 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xpath
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store"
+)
+
+// kinds of issues
+const (
+	SyntaxIssue          = "syntax"
+	UnknownNodeIssue     = "unknown-node"
+	UnknownPrefixIssue   = "unknown-prefix"
+	UnknownFunctionIssue = "unknown-function"
+	ArgumentsIssue       = "arguments"
+	UnknownVariableIssue = "unknown-variable"
+	TypeIssue            = "type"
+)
+
+// Issue is a problem found in an expression.
+type Issue struct {
+	Kind string
+	// offset of the problem in the expression
+	Offset  int
+	Message string
+}
+
+// Result is the outcome of checking an expression.
+type Result struct {
+	Issues []*Issue
+	// data paths of the schema nodes the expression refers to,
+	// their first node is qualified with its module.
+	Paths []string
+	// functions called by the expression
+	Functions []string
+}
+
+// function arguments, max is -1 for any number
+type arity struct {
+	min, max int
+}
+
+// XPath 1.0 core functions and the YANG 1.1 ones, RFC 7950 section 10
+var functions = map[string]arity{
+	"last":                 {0, 0},
+	"position":             {0, 0},
+	"count":                {1, 1},
+	"id":                   {1, 1},
+	"local-name":           {0, 1},
+	"namespace-uri":        {0, 1},
+	"name":                 {0, 1},
+	"string":               {0, 1},
+	"concat":               {2, -1},
+	"starts-with":          {2, 2},
+	"contains":             {2, 2},
+	"substring-before":     {2, 2},
+	"substring-after":      {2, 2},
+	"substring":            {2, 3},
+	"string-length":        {0, 1},
+	"normalize-space":      {0, 1},
+	"translate":            {3, 3},
+	"boolean":              {1, 1},
+	"not":                  {1, 1},
+	"true":                 {0, 0},
+	"false":                {0, 0},
+	"lang":                 {1, 1},
+	"number":               {0, 1},
+	"sum":                  {1, 1},
+	"floor":                {1, 1},
+	"ceiling":              {1, 1},
+	"round":                {1, 1},
+	"current":              {0, 0},
+	"re-match":             {2, 2},
+	"deref":                {1, 1},
+	"derived-from":         {2, 2},
+	"derived-from-or-self": {2, 2},
+	"enum-value":           {1, 1},
+	"bit-is-set":           {2, 2},
+}
+
+// functions taking a node set as first argument
+var nodeSetFunctions = map[string]bool{
+	"count":                true,
+	"sum":                  true,
+	"deref":                true,
+	"derived-from":         true,
+	"derived-from-or-self": true,
+	"enum-value":           true,
+	"bit-is-set":           true,
+}
+
+// Check parses the expression s and checks it against the schema of r.
+// node is the data path of the context node, it can start with a module name.
+// Syntax errors are reported as an issue, the returned error is about the schema lookups.
+func Check(ctx context.Context, r *store.Resolver, node []string, s string) (*Result, error) {
+	c := &checker{
+		r:     r,
+		paths: make(map[string]bool),
+		funcs: make(map[string]bool),
+	}
+	err := c.loadModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.node, err = c.qualify(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	e, err := Parse(s)
+	if err != nil {
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			return nil, err
+		}
+		c.result.Issues = append(c.result.Issues, &Issue{Kind: SyntaxIssue, Offset: se.Offset, Message: se.Msg})
+		return &c.result, nil
+	}
+	_, err = c.eval(ctx, e, &nodeSet{nodes: [][]string{c.node}})
+	if err != nil {
+		return nil, err
+	}
+	for p := range c.paths {
+		c.result.Paths = append(c.result.Paths, p)
+	}
+	sort.Strings(c.result.Paths)
+	for f := range c.funcs {
+		c.result.Functions = append(c.result.Functions, f)
+	}
+	sort.Strings(c.result.Functions)
+	sort.SliceStable(c.result.Issues, func(i, j int) bool {
+		return c.result.Issues[i].Offset < c.result.Issues[j].Offset
+	})
+	return &c.result, nil
+}
+
+// nodeSet is the set of schema nodes selected by an expression,
+// any is set if they cannot be determined from the schema.
+type nodeSet struct {
+	nodes [][]string
+	any   bool
+}
+
+type checker struct {
+	r *store.Resolver
+	// context node
+	node []string
+	// module names and module names by prefix
+	modules  map[string]bool
+	prefixes map[string]string
+
+	result Result
+	paths  map[string]bool
+	funcs  map[string]bool
+}
+
+func (c *checker) issuef(kind string, offset int, format string, args ...interface{}) {
+	c.result.Issues = append(c.result.Issues, &Issue{Kind: kind, Offset: offset, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) loadModules(ctx context.Context) error {
+	root, err := c.r.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	c.modules = make(map[string]bool)
+	c.prefixes = make(map[string]string)
+	for _, m := range root.GetContainer().GetChildren() {
+		mse, err := c.r.Get(ctx, []string{m})
+		if err != nil {
+			return err
+		}
+		c.modules[m] = true
+		if p := mse.GetContainer().GetPrefix(); p != "" {
+			c.prefixes[p] = m
+		}
+	}
+	return nil
+}
+
+// qualify returns the data path names with its first node qualified with its module,
+// names can start with the module element.
+func (c *checker) qualify(ctx context.Context, names []string) ([]string, error) {
+	if len(names) == 0 || strings.Contains(names[0], ":") {
+		return names, nil
+	}
+	dp := make([]string, 0, len(names))
+	if len(names) > 1 && c.modules[names[0]] {
+		dp = append(dp, names[0]+":"+names[1])
+		return append(dp, names[2:]...), nil
+	}
+	se, err := c.r.Get(ctx, names[:1])
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.r.Module(ctx, se)
+	if err != nil {
+		return nil, err
+	}
+	dp = append(dp, m+":"+names[0])
+	return append(dp, names[1:]...), nil
+}
+
+// eval checks expression e evaluated with the context nodes cur,
+// it returns the nodes e selects, nil if e is not a node set.
+func (c *checker) eval(ctx context.Context, e Expr, cur *nodeSet) (*nodeSet, error) {
+	switch e := e.(type) {
+	case *BinaryExpr:
+		x, err := c.eval(ctx, e.X, cur)
+		if err != nil {
+			return nil, err
+		}
+		y, err := c.eval(ctx, e.Y, cur)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op != "|" {
+			return nil, nil
+		}
+		if x == nil || y == nil {
+			c.issuef(TypeIssue, e.pos, "the operands of | must be node sets")
+			return &nodeSet{any: true}, nil
+		}
+		return &nodeSet{nodes: append(x.nodes[:len(x.nodes):len(x.nodes)], y.nodes...), any: x.any || y.any}, nil
+	case *NegExpr:
+		_, err := c.eval(ctx, e.X, cur)
+		return nil, err
+	case *Literal, *Number:
+		return nil, nil
+	case *VariableRef:
+		c.issuef(UnknownVariableIssue, e.pos, "unknown variable $%s, YANG expressions have no variables", e.Name)
+		return &nodeSet{any: true}, nil
+	case *FunctionCall:
+		return c.call(ctx, e, cur)
+	case *FilterExpr:
+		x, err := c.eval(ctx, e.X, cur)
+		if err != nil {
+			return nil, err
+		}
+		if x == nil {
+			c.issuef(TypeIssue, e.Pos(), "predicates apply to node sets only")
+			x = &nodeSet{any: true}
+		}
+		return x, c.predicates(ctx, e.Predicates, x)
+	case *PathExpr:
+		set := cur
+		switch {
+		case e.Filter != nil:
+			x, err := c.eval(ctx, e.Filter, cur)
+			if err != nil {
+				return nil, err
+			}
+			if x == nil {
+				c.issuef(TypeIssue, e.pos, "a location path applies to a node set only")
+				x = &nodeSet{any: true}
+			}
+			set = x
+		case e.Absolute:
+			set = &nodeSet{nodes: [][]string{{}}}
+		}
+		for _, s := range e.Steps {
+			var err error
+			set, err = c.step(ctx, s, set)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return set, nil
+	}
+	return nil, nil
+}
+
+func (c *checker) predicates(ctx context.Context, preds []Expr, set *nodeSet) error {
+	for _, pred := range preds {
+		if _, err := c.eval(ctx, pred, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *checker) call(ctx context.Context, fc *FunctionCall, cur *nodeSet) (*nodeSet, error) {
+	// YANG functions can be qualified with the prefix of their module
+	name := fc.Name
+	if _, local, ok := strings.Cut(name, ":"); ok {
+		name = local
+	}
+	c.funcs[name] = true
+	args := make([]*nodeSet, 0, len(fc.Args))
+	for _, a := range fc.Args {
+		x, err := c.eval(ctx, a, cur)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, x)
+	}
+	ar, ok := functions[name]
+	if !ok {
+		c.issuef(UnknownFunctionIssue, fc.pos, "unknown function %s()", fc.Name)
+		return &nodeSet{any: true}, nil
+	}
+	switch {
+	case len(args) < ar.min || ar.max >= 0 && len(args) > ar.max:
+		c.issuef(ArgumentsIssue, fc.pos, "%s() takes %s, got %d", name, argCount(ar), len(args))
+	case nodeSetFunctions[name] && args[0] == nil:
+		c.issuef(TypeIssue, fc.Args[0].Pos(), "the first argument of %s() must be a node set", name)
+	}
+	switch name {
+	case "current":
+		return &nodeSet{nodes: [][]string{c.node}}, nil
+	case "deref", "id":
+		return &nodeSet{any: true}, nil
+	}
+	return nil, nil
+}
+
+func argCount(ar arity) string {
+	switch {
+	case ar.max < 0:
+		return fmt.Sprintf("at least %d arguments", ar.min)
+	case ar.min == ar.max && ar.min == 1:
+		return "1 argument"
+	case ar.min == ar.max:
+		return fmt.Sprintf("%d arguments", ar.min)
+	}
+	return fmt.Sprintf("%d to %d arguments", ar.min, ar.max)
+}
+
+// step returns the nodes selected by the location step s from the nodes set.
+func (c *checker) step(ctx context.Context, s *Step, set *nodeSet) (*nodeSet, error) {
+	var rs *nodeSet
+	switch {
+	case set.any:
+		rs = set
+	case s.Axis == "self":
+		rs = set
+	case s.Axis == "parent":
+		rs = new(nodeSet)
+		for _, n := range set.nodes {
+			if len(n) == 0 {
+				c.issuef(UnknownNodeIssue, s.pos, "the root node has no parent")
+				continue
+			}
+			rs.nodes = append(rs.nodes, n[:len(n)-1])
+		}
+	case s.Axis == "child" && s.NodeType == "":
+		var err error
+		rs, err = c.children(ctx, s, set)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		// data nodes have no attributes nor text nodes,
+		// the other axes are not followed through the schema
+		rs = &nodeSet{any: true}
+	}
+	return rs, c.predicates(ctx, s.Predicates, rs)
+}
+
+// children returns the nodes selected by the child name test of s from the nodes set.
+func (c *checker) children(ctx context.Context, s *Step, set *nodeSet) (*nodeSet, error) {
+	module := ""
+	if s.Prefix != "" {
+		module = c.prefixes[s.Prefix]
+		if module == "" {
+			c.issuef(UnknownPrefixIssue, s.pos, "unknown prefix %q", s.Prefix)
+			return &nodeSet{any: true}, nil
+		}
+	}
+	rs := new(nodeSet)
+	var nerr error
+	for _, n := range set.nodes {
+		if s.Local == "*" {
+			cs, err := c.list(ctx, n, module)
+			if err != nil {
+				return nil, err
+			}
+			rs.nodes = append(rs.nodes, cs...)
+			continue
+		}
+		names := append(n[:len(n):len(n)], s.Local)
+		if len(n) == 0 && module != "" {
+			names = []string{module + ":" + s.Local}
+		}
+		_, err := c.r.Get(ctx, names)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			nerr = err
+			continue
+		}
+		if len(n) == 0 {
+			names, err = c.qualify(ctx, names)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rs.nodes = append(rs.nodes, names)
+		c.paths["/"+strings.Join(names, "/")] = true
+	}
+	if len(rs.nodes) == 0 && len(set.nodes) > 0 && s.Local != "*" {
+		name := s.Local
+		if s.Prefix != "" {
+			name = s.Prefix + ":" + name
+		}
+		var aerr *schema.AmbiguousPathError
+		if errors.As(nerr, &aerr) {
+			c.issuef(UnknownNodeIssue, s.pos, "%v", aerr)
+			return rs, nil
+		}
+		c.issuef(UnknownNodeIssue, s.pos, "node %s not found under %s", name, nodePath(set.nodes[0]))
+	}
+	return rs, nil
+}
+
+// list returns the child data nodes of node n, of the given module if it is set.
+func (c *checker) list(ctx context.Context, n []string, module string) ([][]string, error) {
+	if len(n) > 0 {
+		se, err := c.r.Get(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		cs := se.GetContainer()
+		var ns [][]string
+		add := func(name string) {
+			ns = append(ns, append(n[:len(n):len(n)], name))
+		}
+		for _, k := range cs.GetKeys() {
+			add(k.GetName())
+		}
+		for _, f := range cs.GetFields() {
+			add(f.GetName())
+		}
+		for _, ll := range cs.GetLeaflists() {
+			add(ll.GetName())
+		}
+		for _, ch := range cs.GetChildren() {
+			add(ch)
+		}
+		return ns, nil
+	}
+	var ns [][]string
+	for m := range c.modules {
+		if module != "" && m != module {
+			continue
+		}
+		mse, err := c.r.Get(ctx, []string{m})
+		if err != nil {
+			return nil, err
+		}
+		cs := mse.GetContainer()
+		for _, f := range cs.GetFields() {
+			ns = append(ns, []string{m + ":" + f.GetName()})
+		}
+		for _, ll := range cs.GetLeaflists() {
+			ns = append(ns, []string{m + ":" + ll.GetName()})
+		}
+		for _, ch := range cs.GetChildren() {
+			ns = append(ns, []string{m + ":" + ch})
+		}
+	}
+	return ns, nil
+}
+
+func nodePath(n []string) string {
+	return "/" + strings.Join(n, "/")
+}
@@ -0,0 +1,665 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xpath parses the XPath 1.0 expressions of YANG must and when statements
+// and checks them against a schema: the nodes of the location paths must exist
+// and the functions must be XPath core or YANG functions called with the expected
+// number of arguments.
+package xpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is a node of a parsed expression.
+type Expr interface {
+	// Pos returns the offset of the expression in the source text.
+	Pos() int
+}
+
+// BinaryExpr is an operation on two expressions, Op is one of
+// or, and, =, !=, <, <=, >, >=, +, -, *, div, mod and |.
+type BinaryExpr struct {
+	Op   string
+	X, Y Expr
+	pos  int
+}
+
+// NegExpr is a unary minus.
+type NegExpr struct {
+	X   Expr
+	pos int
+}
+
+type Literal struct {
+	Value string
+	pos   int
+}
+
+type Number struct {
+	Value float64
+	pos   int
+}
+
+type VariableRef struct {
+	Name string
+	pos  int
+}
+
+type FunctionCall struct {
+	// name, with its prefix if any
+	Name string
+	Args []Expr
+	pos  int
+}
+
+// FilterExpr is a primary expression filtered by predicates.
+type FilterExpr struct {
+	X          Expr
+	Predicates []Expr
+}
+
+// PathExpr is a location path, applied to the node set of Filter if it is not nil.
+type PathExpr struct {
+	Filter   Expr
+	Absolute bool
+	Steps    []*Step
+	pos      int
+}
+
+// Step is a location step, it tests either the name or the type of the nodes.
+type Step struct {
+	Axis string
+	// name test, Local is * for any name
+	Prefix, Local string
+	// node type test: node, text, comment or processing-instruction
+	NodeType   string
+	Predicates []Expr
+	pos        int
+}
+
+func (e *BinaryExpr) Pos() int   { return e.pos }
+func (e *NegExpr) Pos() int      { return e.pos }
+func (e *Literal) Pos() int      { return e.pos }
+func (e *Number) Pos() int       { return e.pos }
+func (e *VariableRef) Pos() int  { return e.pos }
+func (e *FunctionCall) Pos() int { return e.pos }
+func (e *FilterExpr) Pos() int   { return e.X.Pos() }
+func (e *PathExpr) Pos() int     { return e.pos }
+func (s *Step) Pos() int         { return s.pos }
+
+// SyntaxError is returned by Parse for an invalid expression.
+type SyntaxError struct {
+	// offset of the error in the expression
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at %d", e.Msg, e.Offset)
+}
+
+var axes = map[string]bool{
+	"ancestor":           true,
+	"ancestor-or-self":   true,
+	"attribute":          true,
+	"child":              true,
+	"descendant":         true,
+	"descendant-or-self": true,
+	"following":          true,
+	"following-sibling":  true,
+	"namespace":          true,
+	"parent":             true,
+	"preceding":          true,
+	"preceding-sibling":  true,
+	"self":               true,
+}
+
+var nodeTypes = map[string]bool{
+	"comment":                true,
+	"text":                   true,
+	"processing-instruction": true,
+	"node":                   true,
+}
+
+// Parse parses the XPath 1.0 expression s.
+func Parse(s string) (Expr, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, end: len(s)}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, p.errorf("unexpected %q", t.text)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	// NCName, QName or prefix:*, * when it is a name test
+	nameToken tokenKind = iota
+	literalToken
+	numberToken
+	variableToken
+	// operators, including the operator names and, or, div and mod
+	opToken
+	// ( ) [ ] . .. @ , ::
+	punctToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c == '-' || c == '.' || c >= '0' && c <= '9'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func lex(s string) ([]*token, error) {
+	toks := make([]*token, 0)
+	// an operator is expected after the previous token, XPath 1.0 section 3.7
+	operator := func() bool {
+		if len(toks) == 0 {
+			return false
+		}
+		prev := toks[len(toks)-1]
+		switch prev.kind {
+		case opToken:
+			return false
+		case punctToken:
+			return prev.text != "@" && prev.text != "::" && prev.text != "(" && prev.text != "[" && prev.text != ","
+		}
+		return true
+	}
+	ncname := func(i int) int {
+		for i < len(s) && isNameChar(s[i]) {
+			i++
+		}
+		return i
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']' || c == '@' || c == ',':
+			toks = append(toks, &token{kind: punctToken, text: string(c), pos: i})
+			i++
+		case c == ':' && i+1 < len(s) && s[i+1] == ':':
+			toks = append(toks, &token{kind: punctToken, text: "::", pos: i})
+			i += 2
+		case c == '.' && i+1 < len(s) && s[i+1] == '.':
+			toks = append(toks, &token{kind: punctToken, text: "..", pos: i})
+			i += 2
+		case c == '.' && (i+1 >= len(s) || !isDigit(s[i+1])):
+			toks = append(toks, &token{kind: punctToken, text: ".", pos: i})
+			i++
+		case isDigit(c) || c == '.':
+			start := i
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+			if i < len(s) && s[i] == '.' {
+				i++
+				for i < len(s) && isDigit(s[i]) {
+					i++
+				}
+			}
+			toks = append(toks, &token{kind: numberToken, text: s[start:i], pos: start})
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, &SyntaxError{Offset: i, Msg: "unterminated literal"}
+			}
+			toks = append(toks, &token{kind: literalToken, text: s[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case c == '/':
+			if i+1 < len(s) && s[i+1] == '/' {
+				toks = append(toks, &token{kind: opToken, text: "//", pos: i})
+				i += 2
+				continue
+			}
+			toks = append(toks, &token{kind: opToken, text: "/", pos: i})
+			i++
+		case c == '|' || c == '+' || c == '-' || c == '=':
+			toks = append(toks, &token{kind: opToken, text: string(c), pos: i})
+			i++
+		case c == '!':
+			if i+1 >= len(s) || s[i+1] != '=' {
+				return nil, &SyntaxError{Offset: i, Msg: "unexpected \"!\""}
+			}
+			toks = append(toks, &token{kind: opToken, text: "!=", pos: i})
+			i += 2
+		case c == '<' || c == '>':
+			if i+1 < len(s) && s[i+1] == '=' {
+				toks = append(toks, &token{kind: opToken, text: s[i : i+2], pos: i})
+				i += 2
+				continue
+			}
+			toks = append(toks, &token{kind: opToken, text: string(c), pos: i})
+			i++
+		case c == '*':
+			if operator() {
+				toks = append(toks, &token{kind: opToken, text: "*", pos: i})
+			} else {
+				toks = append(toks, &token{kind: nameToken, text: "*", pos: i})
+			}
+			i++
+		case c == '$':
+			if i+1 >= len(s) || !isNameStart(s[i+1]) {
+				return nil, &SyntaxError{Offset: i, Msg: "missing variable name"}
+			}
+			end := ncname(i + 1)
+			if end+1 < len(s) && s[end] == ':' && isNameStart(s[end+1]) {
+				end = ncname(end + 1)
+			}
+			toks = append(toks, &token{kind: variableToken, text: s[i+1 : end], pos: i})
+			i = end
+		case isNameStart(c):
+			start := i
+			i = ncname(i)
+			if operator() {
+				switch name := s[start:i]; name {
+				case "and", "or", "div", "mod":
+					toks = append(toks, &token{kind: opToken, text: name, pos: start})
+					continue
+				}
+				return nil, &SyntaxError{Offset: start, Msg: fmt.Sprintf("unexpected %q, expecting an operator", s[start:i])}
+			}
+			// QName or prefix:*
+			if i+1 < len(s) && s[i] == ':' {
+				switch {
+				case s[i+1] == '*':
+					i += 2
+				case isNameStart(s[i+1]):
+					i = ncname(i + 1)
+				}
+			}
+			toks = append(toks, &token{kind: nameToken, text: s[start:i], pos: start})
+		default:
+			return nil, &SyntaxError{Offset: i, Msg: fmt.Sprintf("unexpected %q", c)}
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []*token
+	i    int
+	// length of the expression, the offset of errors at its end
+	end int
+}
+
+func (p *parser) peek() *token {
+	return p.peekN(0)
+}
+
+func (p *parser) peekN(n int) *token {
+	if p.i+n < len(p.toks) {
+		return p.toks[p.i+n]
+	}
+	return nil
+}
+
+func (p *parser) next() *token {
+	t := p.peek()
+	if t != nil {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) is(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t != nil && t.kind == kind && t.text == text
+}
+
+// accept reports whether the next token is kind and text, it is consumed if it is.
+func (p *parser) accept(kind tokenKind, text string) bool {
+	if p.is(kind, text) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if t := p.peek(); t != nil {
+		return &SyntaxError{Offset: t.pos, Msg: msg}
+	}
+	return &SyntaxError{Offset: p.end, Msg: msg + ", got end of expression"}
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if !p.accept(kind, text) {
+		if t := p.peek(); t != nil {
+			return p.errorf("expecting %q, got %q", text, t.text)
+		}
+		return p.errorf("expecting %q", text)
+	}
+	return nil
+}
+
+func (p *parser) expr() (Expr, error) {
+	return p.binary(0)
+}
+
+// operators by precedence, from the lowest
+var precedence = [][]string{
+	{"or"},
+	{"and"},
+	{"=", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "div", "mod"},
+}
+
+func (p *parser) binary(level int) (Expr, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+	x, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t == nil || t.kind != opToken || !contains(precedence[level], t.text) {
+			return x, nil
+		}
+		p.i++
+		y, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: t.text, X: x, Y: y, pos: t.pos}
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) unary() (Expr, error) {
+	if t := p.peek(); t != nil && t.kind == opToken && t.text == "-" {
+		p.i++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &NegExpr{X: x, pos: t.pos}, nil
+	}
+	return p.union()
+}
+
+func (p *parser) union() (Expr, error) {
+	x, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !p.accept(opToken, "|") {
+			return x, nil
+		}
+		y, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "|", X: x, Y: y, pos: t.pos}
+	}
+}
+
+// primaryStart reports whether the next token starts a primary expression
+// rather than a location path.
+func (p *parser) primaryStart() bool {
+	t := p.peek()
+	if t == nil {
+		return false
+	}
+	switch t.kind {
+	case literalToken, numberToken, variableToken:
+		return true
+	case punctToken:
+		return t.text == "("
+	case nameToken:
+		nt := p.peekN(1)
+		return nt != nil && nt.kind == punctToken && nt.text == "(" && !nodeTypes[t.text]
+	}
+	return false
+}
+
+func (p *parser) path() (Expr, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, p.errorf("expecting an expression")
+	}
+	if !p.primaryStart() {
+		return p.locationPath()
+	}
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	preds, err := p.predicates()
+	if err != nil {
+		return nil, err
+	}
+	if len(preds) > 0 {
+		x = &FilterExpr{X: x, Predicates: preds}
+	}
+	if !p.is(opToken, "/") && !p.is(opToken, "//") {
+		return x, nil
+	}
+	pe := &PathExpr{Filter: x, pos: t.pos}
+	err = p.relativePath(pe, true)
+	if err != nil {
+		return nil, err
+	}
+	return pe, nil
+}
+
+func (p *parser) locationPath() (Expr, error) {
+	t := p.peek()
+	pe := &PathExpr{pos: t.pos}
+	if t.kind == opToken && (t.text == "/" || t.text == "//") {
+		pe.Absolute = true
+		if t.text == "/" {
+			p.i++
+			// the root alone
+			if !p.stepStart() {
+				return pe, nil
+			}
+		}
+		err := p.relativePath(pe, t.text == "//")
+		if err != nil {
+			return nil, err
+		}
+		return pe, nil
+	}
+	err := p.relativePath(pe, false)
+	if err != nil {
+		return nil, err
+	}
+	return pe, nil
+}
+
+// stepStart reports whether the next token starts a location step.
+func (p *parser) stepStart() bool {
+	t := p.peek()
+	if t == nil {
+		return false
+	}
+	switch t.kind {
+	case nameToken:
+		return true
+	case punctToken:
+		return t.text == "." || t.text == ".." || t.text == "@"
+	}
+	return false
+}
+
+// relativePath appends the steps of a relative location path to pe,
+// the path starts with a / or // separator if sep is set.
+func (p *parser) relativePath(pe *PathExpr, sep bool) error {
+	for {
+		if sep {
+			t := p.next()
+			if t.text == "//" {
+				pe.Steps = append(pe.Steps, &Step{Axis: "descendant-or-self", NodeType: "node", pos: t.pos})
+			}
+		}
+		s, err := p.step()
+		if err != nil {
+			return err
+		}
+		pe.Steps = append(pe.Steps, s)
+		if !p.is(opToken, "/") && !p.is(opToken, "//") {
+			return nil
+		}
+		sep = true
+	}
+}
+
+func (p *parser) step() (*Step, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, p.errorf("expecting a location step")
+	}
+	if p.accept(punctToken, ".") {
+		return &Step{Axis: "self", NodeType: "node", pos: t.pos}, nil
+	}
+	if p.accept(punctToken, "..") {
+		return &Step{Axis: "parent", NodeType: "node", pos: t.pos}, nil
+	}
+	s := &Step{Axis: "child", pos: t.pos}
+	if p.accept(punctToken, "@") {
+		s.Axis = "attribute"
+	} else if nt := p.peekN(1); t.kind == nameToken && nt != nil && nt.kind == punctToken && nt.text == "::" {
+		if !axes[t.text] {
+			return nil, p.errorf("unknown axis %q", t.text)
+		}
+		s.Axis = t.text
+		p.i += 2
+	}
+	t = p.next()
+	if t == nil || t.kind != nameToken {
+		p.i--
+		return nil, p.errorf("expecting a node test")
+	}
+	if nodeTypes[t.text] && p.accept(punctToken, "(") {
+		s.NodeType = t.text
+		// processing-instruction can take a literal
+		if t.text == "processing-instruction" && p.peek() != nil && p.peek().kind == literalToken {
+			p.i++
+		}
+		if err := p.expect(punctToken, ")"); err != nil {
+			return nil, err
+		}
+	} else if prefix, local, ok := strings.Cut(t.text, ":"); ok {
+		s.Prefix, s.Local = prefix, local
+	} else {
+		s.Local = t.text
+	}
+	preds, err := p.predicates()
+	if err != nil {
+		return nil, err
+	}
+	s.Predicates = preds
+	return s, nil
+}
+
+func (p *parser) predicates() ([]Expr, error) {
+	var preds []Expr
+	for p.accept(punctToken, "[") {
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(punctToken, "]"); err != nil {
+			return nil, err
+		}
+		preds = append(preds, e)
+	}
+	return preds, nil
+}
+
+func (p *parser) primary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case literalToken:
+		return &Literal{Value: t.text, pos: t.pos}, nil
+	case numberToken:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, &SyntaxError{Offset: t.pos, Msg: fmt.Sprintf("invalid number %q", t.text)}
+		}
+		return &Number{Value: v, pos: t.pos}, nil
+	case variableToken:
+		return &VariableRef{Name: t.text, pos: t.pos}, nil
+	case punctToken:
+		// (
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(punctToken, ")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	// function call
+	fc := &FunctionCall{Name: t.text, pos: t.pos}
+	p.i++
+	if p.accept(punctToken, ")") {
+		return fc, nil
+	}
+	for {
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		fc.Args = append(fc.Args, e)
+		if p.accept(punctToken, ")") {
+			return fc, nil
+		}
+		if err := p.expect(punctToken, ","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/sdcio/schema-server/pkg/config"
+	"github.com/sdcio/schema-server/pkg/schema"
+	"github.com/sdcio/schema-server/pkg/store/memstore"
+	"github.com/sdcio/schema-server/pkg/utils"
+)
+
+// runQuery runs the query subcommand with args and returns the exit code:
+// it parses the schema of the config matching --schema, prints its node
+// at --path and exits, 1 if the schema fails to parse or has no such node.
+func runQuery(args []string) int {
+	fs := pflag.NewFlagSet("query", pflag.ContinueOnError)
+	cfgFile := fs.StringP("config", "c", "schema-server.yaml", "config file path, the schema is parsed from its schema-store schemas")
+	ref := fs.String("schema", "", "schema as name@vendor@version, name@vendor or name, optional if the config has a single schema")
+	xpath := fs.StringP("path", "p", "/", "xpath of the node")
+	withDescription := fs.Bool("with-description", false, "include the descriptions")
+	format := fs.String("format", "text", "output format, text (prototext) or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	log.SetLevel(log.WarnLevel)
+	err := func() error {
+		p, err := utils.ParsePath(*xpath)
+		if err != nil {
+			return fmt.Errorf("invalid path %q: %v", *xpath, err)
+		}
+		cfg, err := config.New(*cfgFile)
+		if err != nil {
+			return err
+		}
+		scfg, err := benchSchema(cfg, *ref)
+		if err != nil {
+			return err
+		}
+		sc, err := schema.NewSchema(scfg)
+		if err != nil {
+			return err
+		}
+		s := memstore.New()
+		if err = s.AddSchema(sc); err != nil {
+			return err
+		}
+		rsp, err := s.GetSchema(context.Background(), &sdcpb.GetSchemaRequest{
+			Path:            p,
+			Schema:          &sdcpb.Schema{Name: scfg.Name, Vendor: scfg.Vendor, Version: scfg.Version},
+			WithDescription: *withDescription,
+		})
+		if err != nil {
+			return err
+		}
+		switch *format {
+		case "json":
+			b, err := protojson.MarshalOptions{Multiline: true}.Marshal(rsp)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+		default:
+			fmt.Println(prototext.Format(rsp))
+		}
+		return nil
+	}()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "query: %v\n", err)
+		return 1
+	}
+	return 0
+}
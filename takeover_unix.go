@@ -0,0 +1,53 @@
+// Copyright 2024 Nokia
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/sdcio/schema-server/pkg/server"
+)
+
+// setupTakeoverHandler hands the listener of the current server over to a new process
+// on SIGUSR2, e.g. once the executable is upgraded. The server is drained
+// and the process exits when the new one serves.
+func setupTakeoverHandler(current *atomic.Pointer[server.Server]) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR2)
+	go func() {
+		for range c {
+			s := current.Load()
+			if s == nil {
+				continue
+			}
+			log.Infof("received signal 'user defined signal 2'. handing the listener over to a new process...")
+			if err := s.Takeover(context.Background()); err != nil {
+				log.Errorf("takeover failed, still serving: %v", err)
+				continue
+			}
+			stop = true
+			s.Drain()
+			os.Exit(0)
+		}
+	}()
+}